@@ -0,0 +1,284 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package diagnostics takes snapshots of the host and of the current process
+// for debug endpoints and diagnostics bundles. Use the redaction options to
+// remove identifying information before attaching a snapshot to a support
+// ticket.
+package diagnostics
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/elastic/go-sysinfo"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Redacted replaces the values that were removed by a redaction option.
+const Redacted = "[redacted]"
+
+// Snapshot is the state of the host and of the current process.
+type Snapshot struct {
+	Time    time.Time        `json:"time"`
+	Go      types.GoInfo     `json:"go"`
+	Host    *HostSnapshot    `json:"host,omitempty"`
+	Process *ProcessSnapshot `json:"process,omitempty"`
+
+	// Errors that occurred while taking the snapshot keyed by the part that
+	// could not be read (e.g. host.memory).
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// HostSnapshot contains the information about the host.
+type HostSnapshot struct {
+	Info   types.HostInfo        `json:"info"`
+	Memory *types.HostMemoryInfo `json:"memory,omitempty"`
+	CPU    *types.CPUTimes       `json:"cpu,omitempty"`
+}
+
+// ProcessSnapshot contains the information about the current process.
+type ProcessSnapshot struct {
+	Info            types.ProcessInfo      `json:"info"`
+	User            *types.UserInfo        `json:"user,omitempty"`
+	Memory          *types.MemoryInfo      `json:"memory,omitempty"`
+	CPU             *types.CPUTimes        `json:"cpu,omitempty"`
+	OpenHandleCount *int                   `json:"open_handle_count,omitempty"`
+	OpenHandles     []types.OpenHandleInfo `json:"open_handles,omitempty"`
+	Environment     map[string]string      `json:"environment,omitempty"`
+}
+
+// Option configures what is included in a snapshot.
+type Option func(*options)
+
+type options struct {
+	environment bool
+	openHandles bool
+	redactHost  bool
+	redactArgs  bool
+	redactPaths bool
+}
+
+// WithEnvironment includes the environment variables of the process. The
+// values of variables whose name suggests that they hold a secret (e.g.
+// API_TOKEN or DB_PASSWORD) are always redacted.
+func WithEnvironment() Option {
+	return func(o *options) { o.environment = true }
+}
+
+// WithOpenHandles includes the list of open file descriptors or handles of
+// the process. By default only their number is included.
+func WithOpenHandles() Option {
+	return func(o *options) { o.openHandles = true }
+}
+
+// RedactHost redacts the hostname, host ID, IP and MAC addresses, and the
+// container ID.
+func RedactHost() Option {
+	return func(o *options) { o.redactHost = true }
+}
+
+// RedactArgs redacts the arguments of the process except for the program
+// name (see RedactPaths).
+func RedactArgs() Option {
+	return func(o *options) { o.redactArgs = true }
+}
+
+// RedactPaths redacts the executable, the program name (the first argument),
+// and the working directory of the process and the paths of its open handles
+// since they often contain user names. Info.Name is kept.
+func RedactPaths() Option {
+	return func(o *options) { o.redactPaths = true }
+}
+
+// secretEnvName matches the names of environment variables that likely
+// hold credentials.
+var secretEnvName = regexp.MustCompile(`(?i)pass|secret|token|key|auth|credential|cookie|session`)
+
+// Take returns a snapshot of the host and of the current process. Parts that
+// cannot be read are listed in Snapshot.Errors and parts that are not
+// implemented for the platform are omitted.
+func Take(opts ...Option) *Snapshot {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Snapshot{Time: time.Now().UTC(), Go: sysinfo.Go()}
+	if host, err := sysinfo.Host(); s.addErr("host", err) {
+		s.Host = takeHost(s, host, o)
+	}
+	if self, err := sysinfo.Self(); s.addErr("process", err) {
+		s.Process = takeProcess(s, self, o)
+	}
+	return s
+}
+
+// addErr records err under key. It returns true if err is nil.
+func (s *Snapshot) addErr(key string, err error) bool {
+	if err == nil {
+		return true
+	}
+	if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+		if s.Errors == nil {
+			s.Errors = map[string]string{}
+		}
+		s.Errors[key] = err.Error()
+	}
+	return false
+}
+
+func takeHost(s *Snapshot, host types.Host, o options) *HostSnapshot {
+	hs := &HostSnapshot{Info: host.Info()}
+	if mem, err := host.Memory(); s.addErr("host.memory", err) {
+		hs.Memory = mem
+	}
+	if cpu, err := host.CPUTime(); s.addErr("host.cpu", err) {
+		hs.CPU = &cpu
+	}
+
+	if o.redactHost {
+		info := &hs.Info
+		info.Hostname = Redacted
+		if info.UniqueID != "" {
+			info.UniqueID = Redacted
+		}
+		info.IPs = redactAll(info.IPs)
+		info.MACs = redactAll(info.MACs)
+		if info.Container != nil {
+			container := *info.Container
+			if container.ID != "" {
+				container.ID = Redacted
+			}
+			container.CgroupPath = ""
+			info.Container = &container
+		}
+		if info.Partition != nil {
+			partition := *info.Partition
+			if partition.Name != "" {
+				partition.Name = Redacted
+			}
+			info.Partition = &partition
+		}
+	}
+	return hs
+}
+
+func takeProcess(s *Snapshot, p types.Process, o options) *ProcessSnapshot {
+	ps := &ProcessSnapshot{}
+	info, err := p.Info()
+	if types.IsPartialInfo(err) {
+		s.addErr("process.info", err)
+		err = nil
+	}
+	if s.addErr("process.info", err) {
+		ps.Info = info
+	}
+	if user, err := p.User(); s.addErr("process.user", err) {
+		ps.User = &user
+	}
+	if mem, err := p.Memory(); s.addErr("process.memory", err) {
+		ps.Memory = &mem
+	}
+	if cpu, err := p.CPUTime(); s.addErr("process.cpu", err) {
+		ps.CPU = &cpu
+	}
+	if v, ok := p.(types.OpenHandleCounter); ok {
+		if n, err := v.OpenHandleCount(); s.addErr("process.open_handle_count", err) {
+			ps.OpenHandleCount = &n
+		}
+	}
+	if v, ok := p.(types.OpenHandleEnumerator); ok && o.openHandles {
+		if handles, err := v.OpenHandles(); s.addErr("process.open_handles", err) {
+			ps.OpenHandles = handles
+		}
+	}
+	if v, ok := p.(types.Environment); ok && o.environment {
+		if env, err := v.Environment(); s.addErr("process.environment", err) {
+			ps.Environment = redactEnvironment(env)
+		}
+	}
+
+	if o.redactArgs && len(ps.Info.Args) > 1 {
+		ps.Info.Args = append([]string{ps.Info.Args[0]}, redactAll(ps.Info.Args[1:])...)
+	}
+	if o.redactPaths {
+		if ps.Info.Exe != "" {
+			ps.Info.Exe = Redacted
+		}
+		if len(ps.Info.Args) > 0 {
+			ps.Info.Args = append([]string{Redacted}, ps.Info.Args[1:]...)
+		}
+		if ps.Info.CWD != "" {
+			ps.Info.CWD = Redacted
+		}
+		for i := range ps.OpenHandles {
+			if ps.OpenHandles[i].Type == types.HandleTypeFile || ps.OpenHandles[i].Type == types.HandleTypeDir {
+				ps.OpenHandles[i].Path = Redacted
+			}
+		}
+	}
+	return ps
+}
+
+func redactAll(values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	redacted := make([]string, len(values))
+	for i := range redacted {
+		redacted[i] = Redacted
+	}
+	return redacted
+}
+
+func redactEnvironment(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+	for k, v := range env {
+		if secretEnvName.MatchString(k) {
+			v = Redacted
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// WriteJSON writes the snapshot as indented JSON.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// Handler returns an http.Handler that responds with a new snapshot as JSON
+// on each request.
+func Handler(opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		Take(opts...).WriteJSON(w)
+	})
+}
+
+// Var returns an expvar.Var that takes a new snapshot each time it is read.
+// Publish it with expvar.Publish to include it in /debug/vars.
+func Var(opts ...Option) expvar.Var {
+	return expvar.Func(func() interface{} { return Take(opts...) })
+}
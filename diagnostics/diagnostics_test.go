@@ -0,0 +1,125 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake(t *testing.T) {
+	s := Take()
+	if s.Process == nil {
+		t.Skip("process information is not implemented on", s.Go.OS)
+	}
+	assert.Empty(t, s.Errors)
+	assert.Equal(t, os.Getpid(), s.Process.Info.PID)
+	assert.Equal(t, os.Args, s.Process.Info.Args)
+	assert.Nil(t, s.Process.Environment)
+	assert.Nil(t, s.Process.OpenHandles)
+	if assert.NotNil(t, s.Host) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+		assert.Equal(t, hostname, s.Host.Info.Hostname)
+	}
+}
+
+func TestTakeRedacted(t *testing.T) {
+	s := Take(WithEnvironment(), WithOpenHandles(), RedactHost(), RedactArgs(), RedactPaths())
+	if s.Process == nil || s.Host == nil {
+		t.Skip("host or process information is not implemented on", s.Go.OS)
+	}
+
+	assert.Equal(t, Redacted, s.Host.Info.Hostname)
+	for _, mac := range s.Host.Info.MACs {
+		assert.Equal(t, Redacted, mac)
+	}
+
+	args := s.Process.Info.Args
+	if assert.Len(t, args, len(os.Args)) {
+		for _, arg := range args {
+			assert.Equal(t, Redacted, arg)
+		}
+	}
+	if s.Process.Info.Exe != "" {
+		assert.Equal(t, Redacted, s.Process.Info.Exe)
+	}
+	assert.Equal(t, Redacted, s.Process.Info.CWD)
+	assert.NotEqual(t, Redacted, s.Process.Info.Name)
+
+	// The environment is read from the OS so variables set with os.Setenv
+	// are not necessarily included.
+	for k, v := range s.Process.Environment {
+		if secretEnvName.MatchString(k) {
+			assert.Equal(t, Redacted, v, k)
+		}
+	}
+}
+
+func TestTakeRedactArgs(t *testing.T) {
+	s := Take(RedactArgs())
+	if s.Process == nil {
+		t.Skip("process information is not implemented on", s.Go.OS)
+	}
+
+	// The program name is only redacted by RedactPaths.
+	args := s.Process.Info.Args
+	if assert.Len(t, args, len(os.Args)) {
+		assert.Equal(t, os.Args[0], args[0])
+	}
+	assert.NotEqual(t, Redacted, s.Process.Info.Exe)
+}
+
+func TestRedactEnvironment(t *testing.T) {
+	env := redactEnvironment(map[string]string{
+		"PATH":              "/usr/bin",
+		"AWS_SECRET_ACCESS": "abc",
+		"DB_PASSWORD":       "abc",
+		"GITHUB_TOKEN":      "abc",
+		"API_KEY":           "abc",
+	})
+	assert.Equal(t, map[string]string{
+		"PATH":              "/usr/bin",
+		"AWS_SECRET_ACCESS": Redacted,
+		"DB_PASSWORD":       Redacted,
+		"GITHUB_TOKEN":      Redacted,
+		"API_KEY":           Redacted,
+	}, env)
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler(RedactHost()).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/sysinfo", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var s Snapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &s))
+	assert.False(t, s.Time.IsZero())
+	if s.Host != nil {
+		assert.Equal(t, Redacted, s.Host.Info.Hostname)
+	}
+
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(Var().String()), &v))
+	assert.Contains(t, v, "go")
+}
@@ -0,0 +1,171 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package ecs maps the information reported by go-sysinfo to the host.* and
+// process.* field sets of the Elastic Common Schema (ECS). The fields are
+// returned as flat maps with dotted keys (e.g. host.os.family) that can be
+// merged into an event. Empty values are omitted.
+package ecs
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Host returns the ECS host fields of info. The container fields are
+// included for containerized hosts.
+func Host(info types.HostInfo) map[string]interface{} {
+	m := map[string]interface{}{}
+	put(m, "host.architecture", info.Architecture)
+	put(m, "host.hostname", info.Hostname)
+	put(m, "host.name", info.Hostname)
+	put(m, "host.id", info.UniqueID)
+	put(m, "host.ip", info.IPs)
+	put(m, "host.mac", formatMACs(info.MACs))
+	if !info.BootTime.IsZero() {
+		m["host.uptime"] = int64(time.Since(info.BootTime) / time.Second)
+	}
+
+	if os := info.OS; os != nil {
+		put(m, "host.os.type", osType(os.Family))
+		put(m, "host.os.family", os.Family)
+		put(m, "host.os.platform", os.Platform)
+		put(m, "host.os.name", os.Name)
+		put(m, "host.os.version", os.Version)
+		put(m, "host.os.full", strings.TrimSpace(os.Name+" "+os.Version))
+	}
+	put(m, "host.os.kernel", info.KernelVersion)
+
+	if c := info.Container; c != nil {
+		put(m, "container.id", c.ID)
+		put(m, "container.runtime", c.Runtime)
+	}
+	return m
+}
+
+// osType returns the ECS os.type (linux, macos, unix, or windows) of an OS
+// family. It returns an empty string if the family is unknown.
+func osType(family string) string {
+	switch family {
+	case "":
+		return ""
+	case "windows":
+		return "windows"
+	case "darwin":
+		return "macos"
+	case "aix", "freebsd", "netbsd", "openbsd", "solaris":
+		return "unix"
+	default:
+		// The other families are Linux distributions (e.g. debian, redhat).
+		return "linux"
+	}
+}
+
+// formatMACs formats the MAC addresses as recommended by ECS: uppercase
+// hexadecimal octets separated by hyphens (RFC 7042).
+func formatMACs(macs []string) []string {
+	if len(macs) == 0 {
+		return nil
+	}
+	formatted := make([]string, 0, len(macs))
+	for _, mac := range macs {
+		formatted = append(formatted, strings.ToUpper(strings.Replace(mac, ":", "-", -1)))
+	}
+	return formatted
+}
+
+// Process returns the ECS process fields of info. Set process.entity_id
+// with EntityID to correlate events of the same process.
+func Process(info types.ProcessInfo) map[string]interface{} {
+	m := map[string]interface{}{
+		"process.pid":        info.PID,
+		"process.parent.pid": info.PPID,
+	}
+	put(m, "process.name", info.Name)
+	put(m, "process.executable", info.Exe)
+	put(m, "process.working_directory", info.CWD)
+	if len(info.Args) > 0 {
+		m["process.args"] = info.Args
+		m["process.args_count"] = len(info.Args)
+		m["process.command_line"] = strings.Join(info.Args, " ")
+	}
+	if !info.StartTime.IsZero() {
+		m["process.start"] = info.StartTime
+	}
+	return m
+}
+
+// ProcessUser returns the ECS user and group fields of a process. The
+// effective IDs are reported as process.user.id and process.group.id and
+// the real IDs as process.real_user.id and process.real_group.id. On
+// Windows, where only the SID is known, it is reported as process.user.id.
+func ProcessUser(user types.UserInfo) map[string]interface{} {
+	m := map[string]interface{}{}
+	if user.EUID == "" {
+		put(m, "process.user.id", user.UID)
+	} else {
+		put(m, "process.user.id", user.EUID)
+		put(m, "process.real_user.id", user.UID)
+		put(m, "process.saved_user.id", user.SUID)
+	}
+	if user.EGID == "" {
+		put(m, "process.group.id", user.GID)
+	} else {
+		put(m, "process.group.id", user.EGID)
+		put(m, "process.real_group.id", user.GID)
+		put(m, "process.saved_group.id", user.SGID)
+	}
+	return m
+}
+
+// EntityID returns a process.entity_id for the process. The ID is stable
+// for the lifetime of the process and unique across hosts and PID reuse
+// because it is derived from the host ID (e.g. types.HostInfo.UniqueID) and
+// the PID, start time, and boot ID of the process. It is the URL safe
+// base64 encoding (without padding) of the first 12 bytes of the SHA-256
+// of these values.
+func EntityID(hostID string, id types.ProcessIdentity) string {
+	var buf [12]byte
+	h := sha256.New()
+	h.Write([]byte(hostID))
+	h.Write([]byte{0})
+	h.Write([]byte(id.BootID))
+	h.Write([]byte{0})
+	binary.BigEndian.PutUint32(buf[:4], uint32(id.PID))
+	binary.BigEndian.PutUint64(buf[4:], uint64(id.StartTime.UnixNano()))
+	h.Write(buf[:])
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+func put(m map[string]interface{}, key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case []string:
+		if len(v) == 0 {
+			return
+		}
+	}
+	m[key] = value
+}
@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestHost(t *testing.T) {
+	m := Host(types.HostInfo{
+		Architecture:  "x86_64",
+		BootTime:      time.Now().Add(-time.Hour),
+		Hostname:      "web01",
+		IPs:           []string{"10.0.0.5"},
+		KernelVersion: "4.13.0-32-generic",
+		MACs:          []string{"00:0c:29:aa:bb:cc"},
+		OS:            &types.OSInfo{Family: "debian", Platform: "ubuntu", Name: "Ubuntu", Version: "17.10 (Artful Aardvark)"},
+		UniqueID:      "4f2c8d1e6b1a4e3c9d572a0c3b8e7f16",
+		Container:     &types.ContainerInfo{Runtime: types.ContainerRuntimeDocker, ID: "0123456789ab"},
+	})
+
+	assert.InDelta(t, 3600, m["host.uptime"], 5)
+	delete(m, "host.uptime")
+	assert.Equal(t, map[string]interface{}{
+		"host.architecture": "x86_64",
+		"host.hostname":     "web01",
+		"host.name":         "web01",
+		"host.id":           "4f2c8d1e6b1a4e3c9d572a0c3b8e7f16",
+		"host.ip":           []string{"10.0.0.5"},
+		"host.mac":          []string{"00-0C-29-AA-BB-CC"},
+		"host.os.type":      "linux",
+		"host.os.family":    "debian",
+		"host.os.platform":  "ubuntu",
+		"host.os.name":      "Ubuntu",
+		"host.os.version":   "17.10 (Artful Aardvark)",
+		"host.os.full":      "Ubuntu 17.10 (Artful Aardvark)",
+		"host.os.kernel":    "4.13.0-32-generic",
+		"container.id":      "0123456789ab",
+		"container.runtime": "docker",
+	}, m)
+
+	m = Host(types.HostInfo{Hostname: "mac", OS: &types.OSInfo{Family: "darwin"}})
+	assert.Equal(t, "macos", m["host.os.type"])
+	assert.NotContains(t, m, "host.os.full")
+	assert.NotContains(t, m, "host.uptime")
+
+	m = Host(types.HostInfo{OS: &types.OSInfo{Family: "aix"}})
+	assert.Equal(t, "unix", m["host.os.type"])
+}
+
+func TestProcess(t *testing.T) {
+	start := time.Date(2018, 3, 1, 12, 30, 0, 0, time.UTC)
+	m := Process(types.ProcessInfo{
+		Name:      "nginx",
+		PID:       1234,
+		PPID:      1,
+		CWD:       "/",
+		Exe:       "/usr/sbin/nginx",
+		Args:      []string{"/usr/sbin/nginx", "-g", "daemon off;"},
+		StartTime: start,
+	})
+	assert.Equal(t, map[string]interface{}{
+		"process.pid":               1234,
+		"process.parent.pid":        1,
+		"process.name":              "nginx",
+		"process.executable":        "/usr/sbin/nginx",
+		"process.working_directory": "/",
+		"process.args":              []string{"/usr/sbin/nginx", "-g", "daemon off;"},
+		"process.args_count":        3,
+		"process.command_line":      "/usr/sbin/nginx -g daemon off;",
+		"process.start":             start,
+	}, m)
+}
+
+func TestProcessUser(t *testing.T) {
+	m := ProcessUser(types.UserInfo{UID: "1000", EUID: "0", SUID: "0", GID: "1000", EGID: "1000", SGID: "1000"})
+	assert.Equal(t, map[string]interface{}{
+		"process.user.id":        "0",
+		"process.real_user.id":   "1000",
+		"process.saved_user.id":  "0",
+		"process.group.id":       "1000",
+		"process.real_group.id":  "1000",
+		"process.saved_group.id": "1000",
+	}, m)
+
+	m = ProcessUser(types.UserInfo{UID: "S-1-5-18"})
+	assert.Equal(t, map[string]interface{}{"process.user.id": "S-1-5-18"}, m)
+}
+
+func TestEntityID(t *testing.T) {
+	id := types.ProcessIdentity{PID: 1234, StartTime: time.Unix(1519907400, 0), BootID: "4f2c8d1e-6b1a-4e3c-9d57-2a0c3b8e7f16"}
+
+	entityID := EntityID("host-a", id)
+	assert.Len(t, entityID, 16)
+	assert.Equal(t, entityID, EntityID("host-a", id))
+	assert.NotEqual(t, entityID, EntityID("host-b", id))
+
+	reused := id
+	reused.StartTime = reused.StartTime.Add(time.Second)
+	assert.NotEqual(t, entityID, EntityID("host-a", reused))
+}
@@ -18,14 +18,31 @@
 package registry
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/elastic/go-sysinfo/types"
 )
 
+// Providers is the set of providers that were registered together. Fields
+// are nil when the provider doesn't implement that kind.
+type Providers struct {
+	Host       HostProvider
+	Process    ProcessProvider
+	Network    NetworkProvider
+	FileSystem FileSystemProvider
+	User       UserProvider
+	Signature  SignatureProvider
+}
+
 var (
-	hostProvider    HostProvider
-	processProvider ProcessProvider
+	defaults Providers // Providers of the current platform.
+
+	namedMu sync.RWMutex
+	named   = map[string]Providers{}
 )
 
 type HostProvider interface {
@@ -38,21 +55,265 @@ type ProcessProvider interface {
 	Self() (types.Process, error)
 }
 
+// ProcessWatcher is implemented by process providers that can stream
+// process events. The channel is closed when the context is done.
+type ProcessWatcher interface {
+	WatchProcesses(ctx context.Context) (<-chan types.ProcessEvent, error)
+}
+
+// ProcessExitWatcher is implemented by process providers that can stream
+// the resource usage of processes when they exit. The channel is closed when
+// the context is done.
+type ProcessExitWatcher interface {
+	WatchProcessExits(ctx context.Context) (<-chan types.ProcessExitEvent, error)
+}
+
+// ProcessContextProvider is implemented by process providers whose process
+// enumeration can be interrupted when the context is done.
+type ProcessContextProvider interface {
+	ProcessesContext(ctx context.Context) ([]types.Process, error)
+}
+
+// ProcessFieldsProvider is implemented by process providers that can skip
+// collecting the process information that was not selected.
+type ProcessFieldsProvider interface {
+	ProcessWithFields(pid int, fields types.ProcessField) (types.Process, error)
+}
+
+// ProcessIterator is implemented by process providers that can enumerate
+// processes without building the complete list up front.
+type ProcessIterator interface {
+	ForEachProcess(fn func(types.Process) error) error
+}
+
+// HostFSProvider is implemented by providers that can read the data of the
+// host from an alternate root filesystem (e.g. /hostfs in a container).
+type HostFSProvider interface {
+	WithHostFS(root string) interface{}
+}
+
+// ProcessFieldsSelector is implemented by process providers that can return
+// a provider whose processes only collect the selected information.
+type ProcessFieldsSelector interface {
+	WithFields(fields types.ProcessField) interface{}
+}
+
+// ProcessBackendProvider is implemented by process providers that can
+// enumerate processes with more than one backend (see
+// types.ProcessBackendEBPF).
+type ProcessBackendProvider interface {
+	WithProcessBackend(backend string) (interface{}, error)
+}
+
+// CacheTTLProvider is implemented by providers that can return a provider
+// whose host data is cached for a TTL that is specific to one call instead of
+// the TTL of shared.StaticCache.
+type CacheTTLProvider interface {
+	WithCacheTTL(ttl time.Duration) interface{}
+}
+
+// EnvFilterProvider is implemented by process providers that can return a
+// provider whose processes apply a filter to their environment.
+type EnvFilterProvider interface {
+	WithEnvFilter(filter types.EnvFilter) interface{}
+}
+
+type NetworkProvider interface {
+	Network() (types.Network, error)
+}
+
+type FileSystemProvider interface {
+	FileSystems() ([]types.FileSystemInfo, error)
+}
+
+type UserProvider interface {
+	Users() ([]types.UserAccount, error)
+	Groups() ([]types.GroupAccount, error)
+}
+
+type SignatureProvider interface {
+	FileSignature(path string) (*types.SignatureInfo, error)
+}
+
+// Register registers the providers of the current platform. It panics if a
+// provider of the same kind was already registered.
 func Register(provider interface{}) {
-	if h, ok := provider.(HostProvider); ok {
-		if hostProvider != nil {
-			panic(errors.Errorf("HostProvider already registered: %v", hostProvider))
+	p := providersOf(provider)
+
+	if p.Host != nil {
+		if defaults.Host != nil {
+			panic(errors.Errorf("HostProvider already registered: %v", defaults.Host))
+		}
+		defaults.Host = p.Host
+	}
+
+	if p.Process != nil {
+		if defaults.Process != nil {
+			panic(errors.Errorf("ProcessProvider already registered: %v", defaults.Process))
+		}
+		defaults.Process = p.Process
+	}
+
+	if p.Network != nil {
+		if defaults.Network != nil {
+			panic(errors.Errorf("NetworkProvider already registered: %v", defaults.Network))
+		}
+		defaults.Network = p.Network
+	}
+
+	if p.FileSystem != nil {
+		if defaults.FileSystem != nil {
+			panic(errors.Errorf("FileSystemProvider already registered: %v", defaults.FileSystem))
 		}
-		hostProvider = h
+		defaults.FileSystem = p.FileSystem
 	}
 
-	if p, ok := provider.(ProcessProvider); ok {
-		if processProvider != nil {
-			panic(errors.Errorf("ProcessProvider already registered: %v", processProvider))
+	if p.User != nil {
+		if defaults.User != nil {
+			panic(errors.Errorf("UserProvider already registered: %v", defaults.User))
 		}
-		processProvider = p
+		defaults.User = p.User
 	}
+
+	if p.Signature != nil {
+		if defaults.Signature != nil {
+			panic(errors.Errorf("SignatureProvider already registered: %v", defaults.Signature))
+		}
+		defaults.Signature = p.Signature
+	}
+}
+
+// RegisterNamed registers an alternative provider (e.g. a mock or a provider
+// that reads a remote host) that can be selected by name. The provider must
+// implement at least one of the provider interfaces.
+func RegisterNamed(name string, provider interface{}) error {
+	if name == "" {
+		return errors.New("provider name must not be empty")
+	}
+	p := providersOf(provider)
+	if p == (Providers{}) {
+		return errors.Errorf("%T does not implement any provider interface", provider)
+	}
+
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if _, found := named[name]; found {
+		return errors.Errorf("provider %q already registered", name)
+	}
+	named[name] = p
+	return nil
+}
+
+// Unregister removes a provider that was registered with RegisterNamed.
+func Unregister(name string) {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	delete(named, name)
+}
+
+// Get returns the providers registered under name or the providers of the
+// current platform if name is empty.
+func Get(name string) (Providers, error) {
+	if name == "" {
+		return defaults, nil
+	}
+
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	p, found := named[name]
+	if !found {
+		return Providers{}, errors.Errorf("provider %q is not registered", name)
+	}
+	return p, nil
+}
+
+// WithHostFS returns the providers that read the data of the host whose
+// root filesystem is mounted at root. types.ErrNotImplemented is returned
+// if the providers don't support it.
+func WithHostFS(p Providers, root string) (Providers, error) {
+	for _, provider := range p.all() {
+		if h, ok := provider.(HostFSProvider); ok {
+			return providersOf(h.WithHostFS(root)), nil
+		}
+	}
+	return Providers{}, types.ErrNotImplemented
+}
+
+// WithProcessBackend returns the providers that enumerate processes with the
+// given backend. types.ErrNotImplemented is returned if the providers return
+// processes but don't support selecting a backend.
+func WithProcessBackend(p Providers, backend string) (Providers, error) {
+	for _, provider := range p.all() {
+		if b, ok := provider.(ProcessBackendProvider); ok {
+			v, err := b.WithProcessBackend(backend)
+			if err != nil {
+				return Providers{}, err
+			}
+			return providersOf(v), nil
+		}
+	}
+	if p.Process == nil {
+		return p, nil
+	}
+	return Providers{}, types.ErrNotImplemented
+}
+
+// WithFields returns the providers whose processes only collect the selected
+// information. The providers are returned unchanged if they don't support it.
+func WithFields(p Providers, fields types.ProcessField) Providers {
+	for _, provider := range p.all() {
+		if s, ok := provider.(ProcessFieldsSelector); ok {
+			return providersOf(s.WithFields(fields))
+		}
+	}
+	return p
+}
+
+// WithCacheTTL returns the providers that cache host data for ttl. The
+// providers are returned unchanged if they don't cache any data.
+func WithCacheTTL(p Providers, ttl time.Duration) Providers {
+	for _, provider := range p.all() {
+		if c, ok := provider.(CacheTTLProvider); ok {
+			return providersOf(c.WithCacheTTL(ttl))
+		}
+	}
+	return p
+}
+
+// WithEnvFilter returns the providers whose processes apply filter to their
+// environment. types.ErrNotImplemented is returned if the providers return
+// processes but don't support it so that secrets are not returned
+// unfiltered.
+func WithEnvFilter(p Providers, filter types.EnvFilter) (Providers, error) {
+	for _, provider := range p.all() {
+		if f, ok := provider.(EnvFilterProvider); ok {
+			return providersOf(f.WithEnvFilter(filter)), nil
+		}
+	}
+	if p.Process == nil {
+		return p, nil
+	}
+	return Providers{}, types.ErrNotImplemented
+}
+
+func (p Providers) all() []interface{} {
+	return []interface{}{p.Host, p.Process, p.Network, p.FileSystem, p.User, p.Signature}
+}
+
+func providersOf(provider interface{}) Providers {
+	var p Providers
+	p.Host, _ = provider.(HostProvider)
+	p.Process, _ = provider.(ProcessProvider)
+	p.Network, _ = provider.(NetworkProvider)
+	p.FileSystem, _ = provider.(FileSystemProvider)
+	p.User, _ = provider.(UserProvider)
+	p.Signature, _ = provider.(SignatureProvider)
+	return p
 }
 
-func GetHostProvider() HostProvider       { return hostProvider }
-func GetProcessProvider() ProcessProvider { return processProvider }
+func GetHostProvider() HostProvider             { return defaults.Host }
+func GetProcessProvider() ProcessProvider       { return defaults.Process }
+func GetNetworkProvider() NetworkProvider       { return defaults.Network }
+func GetFileSystemProvider() FileSystemProvider { return defaults.FileSystem }
+func GetUserProvider() UserProvider             { return defaults.User }
+func GetSignatureProvider() SignatureProvider   { return defaults.Signature }
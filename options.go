@@ -0,0 +1,174 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sysinfo
+
+import (
+	"time"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Option configures the functions of this package that read host, process,
+// network, file system, account, and signature information. Options that
+// don't apply to a function are ignored.
+type Option func(*options)
+
+// ProcessOption configures how a process is opened.
+type ProcessOption = Option
+
+// MachineIDOption configures MachineID.
+type MachineIDOption = Option
+
+type options struct {
+	provider    string
+	hostFS      string
+	fields      types.ProcessField
+	envFilter   types.EnvFilter
+	backend     string // Process enumeration backend.
+	cacheTTL    time.Duration
+	useCacheTTL bool // cacheTTL was set with WithCacheTTL.
+	combined    bool // MachineID returns a hash of all IDs.
+}
+
+func newOptions(opts []Option) (options, registry.Providers, error) {
+	o := options{fields: types.FieldAll}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	providers, err := registry.Get(o.provider)
+	if err != nil {
+		return o, providers, err
+	}
+	if o.hostFS != "" {
+		if providers, err = registry.WithHostFS(providers, o.hostFS); err != nil {
+			return o, providers, err
+		}
+	}
+	if o.fields != types.FieldAll {
+		providers = registry.WithFields(providers, o.fields)
+	}
+	if o.envFilter != nil {
+		if providers, err = registry.WithEnvFilter(providers, o.envFilter); err != nil {
+			return o, providers, err
+		}
+	}
+	if o.backend != "" {
+		if providers, err = registry.WithProcessBackend(providers, o.backend); err != nil {
+			return o, providers, err
+		}
+	}
+	if o.useCacheTTL {
+		providers = registry.WithCacheTTL(providers, o.cacheTTL)
+	}
+	return o, providers, nil
+}
+
+// WithProvider selects a provider that was registered with RegisterProvider
+// instead of the provider of the current platform.
+func WithProvider(name string) Option {
+	return func(o *options) {
+		o.provider = name
+	}
+}
+
+// WithHostFS makes the providers read the data of the host from a root
+// filesystem that is mounted at root instead of /. Use it when running in a
+// container with the host's root bind-mounted (e.g. at /hostfs) to report
+// the host instead of the container. Only the Linux provider supports it;
+// other providers return types.ErrNotImplemented.
+func WithHostFS(root string) Option {
+	return func(o *options) {
+		o.hostFS = root
+	}
+}
+
+// WithFields selects the process information that will be read so that
+// providers can skip the lookups for everything else (e.g. resolving the
+// working directory or reading the command line). It applies to every process
+// that the call returns, including those of Processes and ForEachProcess.
+// ProcessInfo fields that were not selected are left empty. Use
+// types.FieldNone to read only the Name, PID, PPID, and StartTime. Providers
+// that don't support it ignore the option.
+func WithFields(fields types.ProcessField) ProcessOption {
+	return func(o *options) {
+		o.fields = fields
+	}
+}
+
+// WithProcessBackend selects how processes are enumerated (see the
+// types.ProcessBackend constants). Only the Linux provider supports it; it
+// uses procfs when the selected backend cannot be used (e.g. the eBPF
+// backend without CAP_BPF). Other providers return types.ErrNotImplemented.
+func WithProcessBackend(backend string) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// WithEnvFilter makes the processes apply filter to their environment before
+// returning it from Environment. Use it to drop or redact secrets (e.g.
+// AWS_SECRET_ACCESS_KEY) so that they never leave the library. The filter is
+// stored on each process that the call returns, including their children and
+// ancestors. The filters can be combined with ChainEnvFilters. A provider
+// that doesn't support it returns types.ErrNotImplemented.
+func WithEnvFilter(filter types.EnvFilter) Option {
+	return func(o *options) {
+		o.envFilter = filter
+	}
+}
+
+// WithCacheTTL caches the host data that rarely changes (see SetCacheTTL) for
+// the given duration for this call only, overriding the TTL that was set with
+// SetCacheTTL. Values that were cached less than ttl ago are reused. A TTL of
+// zero or less makes the call read the current values.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+		o.useCacheTTL = true
+	}
+}
+
+// WithCombinedMachineID makes MachineID return a hash of all available IDs
+// instead of the preferred one.
+func WithCombinedMachineID() MachineIDOption {
+	return func(o *options) {
+		o.combined = true
+	}
+}
+
+// RegisterProvider registers an alternative provider, like a mock for tests
+// or a provider that reads the data of a remote host, that can be selected
+// with WithProvider. The provider must implement at least one of these
+// methods:
+//
+//	Host() (types.Host, error)
+//	Processes() ([]types.Process, error), Process(pid int) (types.Process, error), and Self() (types.Process, error)
+//	Network() (types.Network, error)
+//	FileSystems() ([]types.FileSystemInfo, error)
+//	Users() ([]types.UserAccount, error) and Groups() ([]types.GroupAccount, error)
+//	FileSignature(path string) (*types.SignatureInfo, error)
+func RegisterProvider(name string, provider interface{}) error {
+	return registry.RegisterNamed(name, provider)
+}
+
+// UnregisterProvider removes a provider that was registered with
+// RegisterProvider.
+func UnregisterProvider(name string) {
+	registry.Unregister(name)
+}
@@ -0,0 +1,359 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package prometheus exposes host and process metrics to Prometheus.
+//
+// An Exporter implements prometheus.Collector so that it can be registered
+// with a prometheus.Registry. It can also be mounted directly as an
+// http.Handler that writes the metrics in the text exposition format.
+package prometheus
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joeshaw/multierror"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/elastic/go-sysinfo"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Metric types.
+const (
+	Counter = "counter"
+	Gauge   = "gauge"
+)
+
+// Label is a name/value pair that identifies a metric of a family.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Metric is a sample of a metric.
+type Metric struct {
+	Name   string  // Full name including the namespace.
+	Help   string  // Description of the metric family.
+	Type   string  // Counter or Gauge.
+	Labels []Label // Labels of the sample (may be empty).
+	Value  float64
+}
+
+// LabelNames returns the names of the labels of m.
+func (m Metric) LabelNames() []string {
+	names := make([]string, 0, len(m.Labels))
+	for _, l := range m.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// LabelValues returns the values of the labels of m.
+func (m Metric) LabelValues() []string {
+	values := make([]string, 0, len(m.Labels))
+	for _, l := range m.Labels {
+		values = append(values, l.Value)
+	}
+	return values
+}
+
+// Exporter reads CPU, memory, file system, and network metrics of a host and
+// the CPU, memory, and file descriptor metrics of a process. Metrics that are
+// not available on the platform are skipped.
+type Exporter struct {
+	Namespace string        // Prefix of the metric names (e.g. sysinfo).
+	Host      types.Host    // Host to report (nil to skip the host metrics).
+	Process   types.Process // Process to report (nil to skip the process metrics).
+
+	// FileSystems returns the file systems to report. No file system
+	// metrics are reported if it is nil.
+	FileSystems func() ([]types.FileSystemInfo, error)
+}
+
+// NewExporter returns an Exporter for the host and the current process whose
+// metric names are prefixed by namespace.
+func NewExporter(namespace string) (*Exporter, error) {
+	host, err := sysinfo.Host()
+	if err != nil {
+		return nil, err
+	}
+	self, err := sysinfo.Self()
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		Namespace: namespace,
+		Host:      host,
+		Process:   self,
+		FileSystems: func() ([]types.FileSystemInfo, error) {
+			return sysinfo.FileSystems()
+		},
+	}, nil
+}
+
+// Metrics reads the current values of the metrics. If some of the metrics
+// cannot be read an error is returned together with the metrics that were
+// read.
+func (c *Exporter) Metrics() ([]Metric, error) {
+	r := &collector{namespace: c.Namespace}
+	if c.Host != nil {
+		r.host(c.Host)
+	}
+	if c.FileSystems != nil {
+		fss, err := c.FileSystems()
+		if r.addErr(err) == nil {
+			r.fileSystems(fss)
+		}
+	}
+	if c.Process != nil {
+		r.process(c.Process)
+	}
+	return r.metrics, r.Err()
+}
+
+// Describe implements prometheus.Collector. It sends no descriptors because
+// the metrics depend on what the platform supports and on the file systems
+// and network interfaces at the time of each collection, so the Exporter is
+// registered as an unchecked collector.
+func (c *Exporter) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector. Like ServeHTTP it reports an
+// error only if no metric could be read.
+func (c *Exporter) Collect(ch chan<- prometheus.Metric) {
+	metrics, err := c.Metrics()
+	if err != nil && len(metrics) == 0 {
+		name := "collect_error"
+		if c.Namespace != "" {
+			name = c.Namespace + "_" + name
+		}
+		ch <- prometheus.NewInvalidMetric(prometheus.NewDesc(name, "Error reading the metrics.", nil, nil), err)
+		return
+	}
+
+	for _, m := range metrics {
+		valueType := prometheus.GaugeValue
+		if m.Type == Counter {
+			valueType = prometheus.CounterValue
+		}
+		desc := prometheus.NewDesc(m.Name, m.Help, m.LabelNames(), nil)
+		ch <- prometheus.MustNewConstMetric(desc, valueType, m.Value, m.LabelValues()...)
+	}
+}
+
+// ServeHTTP writes the metrics in the text exposition format. It responds
+// with an error only if no metric could be read.
+func (c *Exporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	metrics, err := c.Metrics()
+	if err != nil && len(metrics) == 0 {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteText(w, metrics)
+}
+
+type collector struct {
+	namespace string
+	metrics   []Metric
+	errs      []error
+}
+
+func (r *collector) add(name, help, typ string, value float64, labels ...Label) {
+	if r.namespace != "" {
+		name = r.namespace + "_" + name
+	}
+	r.metrics = append(r.metrics, Metric{Name: name, Help: help, Type: typ, Labels: labels, Value: value})
+}
+
+// addErr records err unless the feature is not available on the platform
+// and returns it.
+func (r *collector) addErr(err error) error {
+	if err != nil && !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+		r.errs = append(r.errs, err)
+	}
+	return err
+}
+
+func (r *collector) Err() error {
+	if len(r.errs) > 0 {
+		return &multierror.MultiError{Errors: r.errs}
+	}
+	return nil
+}
+
+func (r *collector) cpuTimes(name, help string, cpu types.CPUTimes) {
+	for _, mode := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"user", cpu.User},
+		{"system", cpu.System},
+		{"idle", cpu.Idle},
+		{"iowait", cpu.IOWait},
+		{"irq", cpu.IRQ},
+		{"nice", cpu.Nice},
+		{"softirq", cpu.SoftIRQ},
+		{"steal", cpu.Steal},
+	} {
+		// The User and System times are always reported.
+		if mode.value == 0 && mode.name != "user" && mode.name != "system" {
+			continue
+		}
+		r.add(name, help, Counter, mode.value.Seconds(), Label{"mode", mode.name})
+	}
+}
+
+func (r *collector) host(host types.Host) {
+	info := host.Info()
+	if !info.BootTime.IsZero() {
+		r.add("host_boot_time_seconds", "Boot time of the host in seconds since the epoch.", Gauge, unixSeconds(info.BootTime))
+	}
+
+	if cpu, err := host.CPUTime(); r.addErr(err) == nil {
+		r.cpuTimes("host_cpu_seconds_total", "CPU time spent by the host in each mode.", cpu)
+	}
+
+	if mem, err := host.Memory(); r.addErr(err) == nil {
+		r.add("host_memory_total_bytes", "Total physical memory.", Gauge, float64(mem.Total))
+		r.add("host_memory_used_bytes", "Used physical memory.", Gauge, float64(mem.Used))
+		r.add("host_memory_available_bytes", "Memory available without swapping.", Gauge, float64(mem.Available))
+		r.add("host_memory_free_bytes", "Memory not used by the system.", Gauge, float64(mem.Free))
+		r.add("host_virtual_memory_total_bytes", "Total virtual memory.", Gauge, float64(mem.VirtualTotal))
+		r.add("host_virtual_memory_used_bytes", "Used virtual memory.", Gauge, float64(mem.VirtualUsed))
+	}
+
+	if v, ok := host.(types.NetworkInterfaces); ok {
+		ifaces, err := v.NetworkInterfaces()
+		if r.addErr(err) == nil {
+			r.networkInterfaces(ifaces)
+		}
+	}
+}
+
+func (r *collector) networkInterfaces(ifaces []types.NetworkInterfaceInfo) {
+	for _, iface := range ifaces {
+		c := iface.Counters
+		if c == nil {
+			continue
+		}
+		device := Label{"device", iface.Name}
+		r.add("network_receive_bytes_total", "Bytes received by the interface.", Counter, float64(c.RxBytes), device)
+		r.add("network_receive_packets_total", "Packets received by the interface.", Counter, float64(c.RxPackets), device)
+		r.add("network_receive_errors_total", "Receive errors of the interface.", Counter, float64(c.RxErrors), device)
+		r.add("network_receive_drop_total", "Received packets dropped by the interface.", Counter, float64(c.RxDropped), device)
+		r.add("network_transmit_bytes_total", "Bytes transmitted by the interface.", Counter, float64(c.TxBytes), device)
+		r.add("network_transmit_packets_total", "Packets transmitted by the interface.", Counter, float64(c.TxPackets), device)
+		r.add("network_transmit_errors_total", "Transmit errors of the interface.", Counter, float64(c.TxErrors), device)
+		r.add("network_transmit_drop_total", "Transmitted packets dropped by the interface.", Counter, float64(c.TxDropped), device)
+	}
+}
+
+func (r *collector) fileSystems(fss []types.FileSystemInfo) {
+	for _, fs := range fss {
+		labels := []Label{{"device", fs.Device}, {"fstype", fs.Type}, {"mountpoint", fs.MountPoint}}
+		r.add("filesystem_size_bytes", "Size of the file system.", Gauge, float64(fs.Total), labels...)
+		r.add("filesystem_free_bytes", "Free space including space reserved for privileged users.", Gauge, float64(fs.Free), labels...)
+		r.add("filesystem_avail_bytes", "Free space available to unprivileged users.", Gauge, float64(fs.Available), labels...)
+		if fs.Inodes > 0 {
+			r.add("filesystem_files", "Total number of inodes.", Gauge, float64(fs.Inodes), labels...)
+			r.add("filesystem_files_free", "Number of free inodes.", Gauge, float64(fs.InodesFree), labels...)
+		}
+	}
+}
+
+func (r *collector) process(p types.Process) {
+	pid := Label{"pid", strconv.Itoa(p.PID())}
+
+	info, err := p.Info()
+	if types.IsPartialInfo(err) {
+		// The start time is always read.
+		err = nil
+	}
+	if r.addErr(err) == nil {
+		if !info.StartTime.IsZero() {
+			r.add("process_start_time_seconds", "Start time of the process in seconds since the epoch.", Gauge, unixSeconds(info.StartTime), pid)
+		}
+	}
+
+	if cpu, err := p.CPUTime(); r.addErr(err) == nil {
+		r.add("process_cpu_seconds_total", "CPU time spent by the process in each mode.", Counter, cpu.User.Seconds(), pid, Label{"mode", "user"})
+		r.add("process_cpu_seconds_total", "CPU time spent by the process in each mode.", Counter, cpu.System.Seconds(), pid, Label{"mode", "system"})
+	}
+
+	if mem, err := p.Memory(); r.addErr(err) == nil {
+		r.add("process_resident_memory_bytes", "Resident memory size of the process.", Gauge, float64(mem.Resident), pid)
+		r.add("process_virtual_memory_bytes", "Virtual memory size of the process.", Gauge, float64(mem.Virtual), pid)
+	}
+
+	if v, ok := p.(types.OpenHandleCounter); ok {
+		if n, err := v.OpenHandleCount(); r.addErr(err) == nil {
+			r.add("process_open_fds", "Number of open file descriptors or handles.", Gauge, float64(n), pid)
+		}
+	}
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+// (version 0.0.4). Metrics with the same name are written as one family.
+func WriteText(w io.Writer, metrics []Metric) error {
+	// Group the samples by family while keeping the order of the families.
+	var names []string
+	families := map[string][]Metric{}
+	for _, m := range metrics {
+		if _, found := families[m.Name]; !found {
+			names = append(names, m.Name)
+		}
+		families[m.Name] = append(families[m.Name], m)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		family := families[name]
+		bw.WriteString("# HELP " + name + " " + helpEscaper.Replace(family[0].Help) + "\n")
+		bw.WriteString("# TYPE " + name + " " + family[0].Type + "\n")
+		for _, m := range family {
+			bw.WriteString(name)
+			if len(m.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, l := range m.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					bw.WriteString(l.Name + `="` + labelEscaper.Replace(l.Value) + `"`)
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteString(" " + strconv.FormatFloat(m.Value, 'g', -1, 64) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
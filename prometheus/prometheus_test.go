@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package prometheus
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+type mockHost struct {
+	types.Host
+}
+
+func (mockHost) Info() types.HostInfo {
+	return types.HostInfo{BootTime: time.Unix(1500000000, 0)}
+}
+
+func (mockHost) CPUTime() (types.CPUTimes, error) {
+	return types.CPUTimes{User: 10 * time.Second, System: 5 * time.Second, Idle: 100 * time.Second}, nil
+}
+
+func (mockHost) Memory() (*types.HostMemoryInfo, error) {
+	return nil, types.ErrNotImplemented
+}
+
+type mockProcess struct {
+	types.Process
+}
+
+func (mockProcess) PID() int { return 42 }
+
+func (mockProcess) Info() (types.ProcessInfo, error) {
+	return types.ProcessInfo{PID: 42, StartTime: time.Unix(1500000100, 500000000)}, &types.PartialInfoError{}
+}
+
+func (mockProcess) CPUTime() (types.CPUTimes, error) {
+	return types.CPUTimes{User: 1500 * time.Millisecond, System: 250 * time.Millisecond}, nil
+}
+
+func (mockProcess) Memory() (types.MemoryInfo, error) {
+	return types.MemoryInfo{Resident: 4096, Virtual: 8192}, nil
+}
+
+const expectedText = `# HELP test_filesystem_avail_bytes Free space available to unprivileged users.
+# TYPE test_filesystem_avail_bytes gauge
+test_filesystem_avail_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 700
+# HELP test_filesystem_free_bytes Free space including space reserved for privileged users.
+# TYPE test_filesystem_free_bytes gauge
+test_filesystem_free_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 750
+# HELP test_filesystem_size_bytes Size of the file system.
+# TYPE test_filesystem_size_bytes gauge
+test_filesystem_size_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 1000
+# HELP test_host_boot_time_seconds Boot time of the host in seconds since the epoch.
+# TYPE test_host_boot_time_seconds gauge
+test_host_boot_time_seconds 1.5e+09
+# HELP test_host_cpu_seconds_total CPU time spent by the host in each mode.
+# TYPE test_host_cpu_seconds_total counter
+test_host_cpu_seconds_total{mode="user"} 10
+test_host_cpu_seconds_total{mode="system"} 5
+test_host_cpu_seconds_total{mode="idle"} 100
+# HELP test_process_cpu_seconds_total CPU time spent by the process in each mode.
+# TYPE test_process_cpu_seconds_total counter
+test_process_cpu_seconds_total{pid="42",mode="user"} 1.5
+test_process_cpu_seconds_total{pid="42",mode="system"} 0.25
+# HELP test_process_resident_memory_bytes Resident memory size of the process.
+# TYPE test_process_resident_memory_bytes gauge
+test_process_resident_memory_bytes{pid="42"} 4096
+# HELP test_process_start_time_seconds Start time of the process in seconds since the epoch.
+# TYPE test_process_start_time_seconds gauge
+test_process_start_time_seconds{pid="42"} 1.5000001005e+09
+# HELP test_process_virtual_memory_bytes Virtual memory size of the process.
+# TYPE test_process_virtual_memory_bytes gauge
+test_process_virtual_memory_bytes{pid="42"} 8192
+`
+
+func TestExporter(t *testing.T) {
+	c := &Exporter{
+		Namespace: "test",
+		Host:      mockHost{},
+		Process:   mockProcess{},
+		FileSystems: func() ([]types.FileSystemInfo, error) {
+			return []types.FileSystemInfo{{Device: "/dev/sda1", MountPoint: "/", Type: "ext4", Total: 1000, Free: 750, Available: 700}}, nil
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, expectedText, rec.Body.String())
+}
+
+func TestExporterCollector(t *testing.T) {
+	c := &Exporter{Namespace: "test", Host: mockHost{}, Process: mockProcess{}}
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]float64{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			switch {
+			case m.GetCounter() != nil:
+				values[f.GetName()] += m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				values[f.GetName()] += m.GetGauge().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, 1500000000.0, values["test_host_boot_time_seconds"])
+	assert.Equal(t, 8192.0, values["test_process_virtual_memory_bytes"])
+}
+
+func TestExporterSelf(t *testing.T) {
+	c, err := NewExporter("sysinfo")
+	if types.IsNotImplemented(err) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics, err := c.Metrics()
+	if err != nil {
+		t.Log(err)
+	}
+	names := map[string]bool{}
+	for _, m := range metrics {
+		names[m.Name] = true
+	}
+	assert.True(t, names["sysinfo_process_resident_memory_bytes"])
+	assert.True(t, names["sysinfo_host_cpu_seconds_total"])
+}
+
+func TestWriteTextEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteText(&buf, []Metric{{
+		Name:   "x",
+		Help:   "back\\slash\nnewline",
+		Type:   Gauge,
+		Labels: []Label{{"path", `C:\a "b"`}},
+		Value:  -1,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, `# HELP x back\\slash\nnewline`, lines[0])
+	assert.Equal(t, `x{path="C:\\a \"b\""} -1`, lines[2])
+}
+
+func TestMetricLabels(t *testing.T) {
+	m := Metric{Labels: []Label{{"device", "eth0"}, {"mode", "rx"}}}
+	assert.Equal(t, []string{"device", "mode"}, m.LabelNames())
+	assert.Equal(t, []string{"eth0", "rx"}, m.LabelValues())
+}
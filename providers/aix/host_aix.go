@@ -0,0 +1,296 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build aix,cgo
+
+package aix
+
+/*
+#cgo LDFLAGS: -lperfstat
+#include <string.h>
+#include <unistd.h>
+#include <sys/utsname.h>
+#include <libperfstat.h>
+
+// The partition type is a union of bitfields that cgo cannot access.
+static int
+lpar_enabled(perfstat_partition_total_t *p)
+{
+	return p->type.b.lpar_enabled;
+}
+
+static int
+shared_enabled(perfstat_partition_total_t *p)
+{
+	return p->type.b.shared_enabled;
+}
+
+static void
+first_cpu(perfstat_id_t *id)
+{
+	strcpy(id->name, FIRST_CPU);
+}
+*/
+import "C"
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// pageSize is the unit of the perfstat memory counters.
+const pageSize = 4096
+
+func (s aixSystem) Host() (types.Host, error) {
+	return newHost()
+}
+
+type host struct {
+	info types.HostInfo
+}
+
+func (h *host) Info() types.HostInfo {
+	return h.info
+}
+
+// VirtualizationInfo reports PowerVM as the hypervisor of an LPAR.
+func (h *host) VirtualizationInfo() (*types.VirtualizationInfo, error) {
+	if h.info.Partition == nil {
+		return &types.VirtualizationInfo{}, nil
+	}
+	return &types.VirtualizationInfo{
+		Hypervisor: "PowerVM",
+		Role:       types.VirtualizationRoleGuest,
+	}, nil
+}
+
+func (h *host) CPUTime() (types.CPUTimes, error) {
+	var cpu C.perfstat_cpu_total_t
+	if rtn, err := C.perfstat_cpu_total(nil, &cpu, C.int(unsafe.Sizeof(cpu)), 1); rtn != 1 {
+		return types.CPUTimes{}, errors.Wrap(err, "perfstat_cpu_total failed")
+	}
+	return cpuTimes(uint64(cpu.user), uint64(cpu.sys), uint64(cpu.idle), uint64(cpu.wait)), nil
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	n, err := C.perfstat_cpu(nil, nil, C.int(unsafe.Sizeof(C.perfstat_cpu_t{})), 0)
+	if n <= 0 {
+		return nil, errors.Wrap(err, "perfstat_cpu failed")
+	}
+
+	var id C.perfstat_id_t
+	C.first_cpu(&id)
+	cpus := make([]C.perfstat_cpu_t, n)
+	n, err = C.perfstat_cpu(&id, &cpus[0], C.int(unsafe.Sizeof(cpus[0])), n)
+	if n < 0 {
+		return nil, errors.Wrap(err, "perfstat_cpu failed")
+	}
+
+	times := make([]types.CPUTimes, 0, n)
+	for _, cpu := range cpus[:n] {
+		times = append(times, cpuTimes(uint64(cpu.user), uint64(cpu.sys), uint64(cpu.idle), uint64(cpu.wait)))
+	}
+	return times, nil
+}
+
+// cpuTimes converts the perfstat clock ticks.
+func cpuTimes(user, system, idle, wait uint64) types.CPUTimes {
+	tick := time.Second / time.Duration(C.sysconf(C._SC_CLK_TCK))
+	return types.CPUTimes{
+		User:   time.Duration(user) * tick,
+		System: time.Duration(system) * tick,
+		Idle:   time.Duration(idle) * tick,
+		IOWait: time.Duration(wait) * tick,
+	}
+}
+
+func (h *host) Memory() (*types.HostMemoryInfo, error) {
+	var mem C.perfstat_memory_total_t
+	if rtn, err := C.perfstat_memory_total(nil, &mem, C.int(unsafe.Sizeof(mem)), 1); rtn != 1 {
+		return nil, errors.Wrap(err, "perfstat_memory_total failed")
+	}
+
+	info := &types.HostMemoryInfo{
+		Total:     uint64(mem.real_total) * pageSize,
+		Free:      uint64(mem.real_free) * pageSize,
+		Available: uint64(mem.real_avail) * pageSize,
+	}
+	info.Used = info.Total - info.Free
+	return info, nil
+}
+
+func newHost() (*host, error) {
+	h := &host{}
+	r := &reader{}
+	r.bootTime(h)
+	r.hostname(h)
+	r.network(h)
+	r.uname(h)
+	r.time(h)
+	r.partition(h)
+	r.wpar(h)
+	return h, r.Err()
+}
+
+type reader struct {
+	errs []error
+}
+
+func (r *reader) addErr(err error) bool {
+	if err != nil {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+			r.errs = append(r.errs, err)
+		}
+		return true
+	}
+	return false
+}
+
+func (r *reader) Err() error {
+	if len(r.errs) > 0 {
+		return &multierror.MultiError{Errors: r.errs}
+	}
+	return nil
+}
+
+func (r *reader) bootTime(h *host) {
+	v, err := bootClock.BootTime()
+	if r.addErr(err) {
+		return
+	}
+	h.info.BootTime = v
+}
+
+var bootClock = shared.NewBootTimeClock(initStartTime)
+
+// initStartTime returns the start time of init, which is started by the
+// kernel at boot.
+func initStartTime() (time.Time, error) {
+	data, err := ioutil.ReadFile("/proc/1/psinfo")
+	if err != nil {
+		return time.Time{}, err
+	}
+	ps, err := parsePSInfo(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ps.Start, nil
+}
+
+func (r *reader) hostname(h *host) {
+	v, err := os.Hostname()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Hostname = v
+}
+
+func (r *reader) network(h *host) {
+	ips, macs, err := shared.Network()
+	if r.addErr(err) {
+		return
+	}
+	h.info.IPs = ips
+	h.info.MACs = macs
+}
+
+// uname reads the OS version. AIX reports the major version in version and
+// the minor version in release (e.g. 7 and 2). The machine is a hardware ID,
+// so the architecture is always powerpc.
+func (r *reader) uname(h *host) {
+	var uts C.struct_utsname
+	if rtn, err := C.uname(&uts); rtn == -1 {
+		r.addErr(errors.Wrap(err, "uname failed"))
+		return
+	}
+	version := C.GoString(&uts.version[0]) + "." + C.GoString(&uts.release[0])
+	major, _ := strconv.Atoi(C.GoString(&uts.version[0]))
+	minor, _ := strconv.Atoi(C.GoString(&uts.release[0]))
+
+	h.info.Architecture = "powerpc"
+	h.info.KernelVersion = version
+	h.info.OS = &types.OSInfo{
+		Family:   "aix",
+		Platform: "aix",
+		Name:     "AIX",
+		Version:  version,
+		Major:    major,
+		Minor:    minor,
+	}
+}
+
+func (r *reader) time(h *host) {
+	h.info.Timezone, h.info.TimezoneOffsetSec = time.Now().Zone()
+}
+
+// partition reads the LPAR from perfstat_partition_total. Partition is nil
+// when the system is not partitioned. The entitled capacity is reported in
+// hundredths of a processing unit.
+func (r *reader) partition(h *host) {
+	var p C.perfstat_partition_total_t
+	if rtn, err := C.perfstat_partition_total(nil, &p, C.int(unsafe.Sizeof(p)), 1); rtn != 1 {
+		r.addErr(errors.Wrap(err, "perfstat_partition_total failed"))
+		return
+	}
+	if C.lpar_enabled(&p) == 0 {
+		return
+	}
+
+	h.info.Partition = &types.PartitionInfo{
+		Name:             C.GoString(&p.name[0]),
+		ID:               int(p.lpar_id),
+		Shared:           C.shared_enabled(&p) != 0,
+		EntitledCapacity: float64(p.entitled_proc_capacity) / 100,
+		SMTThreads:       int(p.smt_thrds),
+	}
+}
+
+// wpar reports a workload partition as a container. The process runs in a
+// WPAR when its corral ID is not 0 (the global environment).
+func (r *reader) wpar(h *host) {
+	data, err := ioutil.ReadFile("/proc/" + strconv.Itoa(os.Getpid()) + "/psinfo")
+	if r.addErr(err) {
+		return
+	}
+	ps, err := parsePSInfo(data)
+	if r.addErr(err) {
+		return
+	}
+
+	containerized := ps.CID != 0
+	h.info.Containerized = &containerized
+	if !containerized {
+		return
+	}
+
+	h.info.Container = &types.ContainerInfo{Runtime: types.ContainerRuntimeWPAR}
+	var w C.perfstat_wpar_total_t
+	if rtn, err := C.perfstat_wpar_total(nil, &w, C.int(unsafe.Sizeof(w)), 1); rtn != 1 {
+		r.addErr(errors.Wrap(err, "perfstat_wpar_total failed"))
+		return
+	}
+	h.info.Container.ID = C.GoString(&w.name[0])
+}
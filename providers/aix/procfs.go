@@ -0,0 +1,174 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aix
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Offsets of the fields of struct psinfo from sys/procfs.h. AIX uses the
+// same 64-bit layout for 32-bit and 64-bit processes and is big endian. Only
+// pr_sname is read from the embedded lwpsinfo of the representative thread.
+const (
+	psinfoMinSize = 328
+
+	psinfoUID    = 16
+	psinfoEUID   = 24
+	psinfoGID    = 32
+	psinfoEGID   = 40
+	psinfoPID    = 48
+	psinfoPPID   = 56
+	psinfoSize   = 96
+	psinfoRSSize = 104
+	psinfoStart  = 112
+	psinfoCID    = 144
+	psinfoArgc   = 148
+	psinfoArgv   = 152
+	psinfoEnvp   = 160
+	psinfoFname  = 168
+	psinfoPSArgs = 184
+	psinfoSname  = 328 + 30 // pr_lwp.pr_sname
+
+	prfnsz  = 16 // PRFNSZ
+	prargsz = 80 // PRARGSZ
+)
+
+// Offsets of the CPU times in struct pstatus.
+const (
+	pstatusMinSize = 152
+
+	pstatusUTime = 120
+	pstatusSTime = 136
+)
+
+// Offsets of the saved IDs in struct prcred.
+const (
+	prcredMinSize = 48
+
+	prcredSUID = 16
+	prcredSGID = 40
+)
+
+// psinfo contains the fields of struct psinfo that are used by the provider.
+type psinfo struct {
+	PID    int
+	PPID   int
+	UID    uint64
+	EUID   uint64
+	GID    uint64
+	EGID   uint64
+	Size   uint64 // Virtual size in bytes.
+	RSSize uint64 // Resident set size in bytes.
+	Start  time.Time
+	CID    uint16 // Corral ID. It is the WPAR ID, and 0 in the global environment.
+	Name   string
+	PSArgs string // First 80 characters of the arguments.
+	Argc   int
+	Argv   uint64 // Address of the argument vector.
+	Envp   uint64 // Address of the environment vector.
+	Sname  byte   // State of the representative thread (e.g. R, S, Z).
+}
+
+func parsePSInfo(b []byte) (*psinfo, error) {
+	if len(b) < psinfoMinSize {
+		return nil, errors.Errorf("psinfo is too small (%d bytes)", len(b))
+	}
+
+	be := binary.BigEndian
+	ps := &psinfo{
+		PID:    int(be.Uint64(b[psinfoPID:])),
+		PPID:   int(be.Uint64(b[psinfoPPID:])),
+		UID:    be.Uint64(b[psinfoUID:]),
+		EUID:   be.Uint64(b[psinfoEUID:]),
+		GID:    be.Uint64(b[psinfoGID:]),
+		EGID:   be.Uint64(b[psinfoEGID:]),
+		Size:   be.Uint64(b[psinfoSize:]) * 1024,
+		RSSize: be.Uint64(b[psinfoRSSize:]) * 1024,
+		Start:  time.Unix(timestruc(b[psinfoStart:])),
+		CID:    be.Uint16(b[psinfoCID:]),
+		Name:   nullTerminated(b[psinfoFname : psinfoFname+prfnsz]),
+		PSArgs: nullTerminated(b[psinfoPSArgs : psinfoPSArgs+prargsz]),
+		Argc:   int(be.Uint32(b[psinfoArgc:])),
+		Argv:   be.Uint64(b[psinfoArgv:]),
+		Envp:   be.Uint64(b[psinfoEnvp:]),
+	}
+	if len(b) > psinfoSname {
+		ps.Sname = b[psinfoSname]
+	}
+	return ps, nil
+}
+
+// threadState maps pr_sname to one of the ProcessState constants.
+func threadState(sname byte) string {
+	switch sname {
+	case 'A', 'R':
+		return types.ProcessStateRunning
+	case 'S':
+		return types.ProcessStateSleeping
+	case 'T':
+		return types.ProcessStateStopped
+	case 'Z':
+		return types.ProcessStateZombie
+	case 'W':
+		return types.ProcessStateWaiting
+	case 'I':
+		return types.ProcessStateIdle
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+// parsePStatus returns the user and system CPU time from struct pstatus.
+func parsePStatus(b []byte) (user, system time.Duration, err error) {
+	if len(b) < pstatusMinSize {
+		return 0, 0, errors.Errorf("pstatus is too small (%d bytes)", len(b))
+	}
+	return timestrucDuration(b[pstatusUTime:]), timestrucDuration(b[pstatusSTime:]), nil
+}
+
+// parsePRCred returns the saved user and group IDs from struct prcred.
+func parsePRCred(b []byte) (suid, sgid uint64, err error) {
+	if len(b) < prcredMinSize {
+		return 0, 0, errors.Errorf("prcred is too small (%d bytes)", len(b))
+	}
+	return binary.BigEndian.Uint64(b[prcredSUID:]), binary.BigEndian.Uint64(b[prcredSGID:]), nil
+}
+
+// timestruc returns the seconds and nanoseconds of a pr_timestruc64_t.
+func timestruc(b []byte) (sec, nsec int64) {
+	return int64(binary.BigEndian.Uint64(b)), int64(int32(binary.BigEndian.Uint32(b[8:])))
+}
+
+func timestrucDuration(b []byte) time.Duration {
+	sec, nsec := timestruc(b)
+	return time.Duration(sec)*time.Second + time.Duration(nsec)
+}
+
+func nullTerminated(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
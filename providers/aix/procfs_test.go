@@ -0,0 +1,109 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aix
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParsePSInfo(t *testing.T) {
+	b := make([]byte, 448)
+	be := binary.BigEndian
+	be.PutUint64(b[psinfoUID:], 100)
+	be.PutUint64(b[psinfoEUID:], 0)
+	be.PutUint64(b[psinfoGID:], 10)
+	be.PutUint64(b[psinfoEGID:], 0)
+	be.PutUint64(b[psinfoPID:], 1234)
+	be.PutUint64(b[psinfoPPID:], 1)
+	be.PutUint64(b[psinfoSize:], 4096)
+	be.PutUint64(b[psinfoRSSize:], 1024)
+	be.PutUint64(b[psinfoStart:], 1500000000)
+	be.PutUint32(b[psinfoStart+8:], 500)
+	be.PutUint16(b[psinfoCID:], 3)
+	be.PutUint32(b[psinfoArgc:], 3)
+	be.PutUint64(b[psinfoArgv:], 0x0fffffffffffe000)
+	be.PutUint64(b[psinfoEnvp:], 0x0fffffffffffe020)
+	copy(b[psinfoFname:], "httpd")
+	copy(b[psinfoPSArgs:], "httpd -k start")
+	b[psinfoSname] = 'S'
+
+	info, err := parsePSInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &psinfo{
+		PID:    1234,
+		PPID:   1,
+		UID:    100,
+		EUID:   0,
+		GID:    10,
+		EGID:   0,
+		Size:   4096 * 1024,
+		RSSize: 1024 * 1024,
+		Start:  time.Unix(1500000000, 500),
+		CID:    3,
+		Name:   "httpd",
+		PSArgs: "httpd -k start",
+		Argc:   3,
+		Argv:   0x0fffffffffffe000,
+		Envp:   0x0fffffffffffe020,
+		Sname:  'S',
+	}, info)
+	assert.Equal(t, types.ProcessStateSleeping, threadState(info.Sname))
+
+	_, err = parsePSInfo(b[:100])
+	assert.Error(t, err)
+}
+
+func TestParsePStatus(t *testing.T) {
+	b := make([]byte, pstatusMinSize)
+	be := binary.BigEndian
+	be.PutUint64(b[pstatusUTime:], 2)
+	be.PutUint32(b[pstatusUTime+8:], 500000000)
+	be.PutUint32(b[pstatusSTime+8:], 1000)
+
+	user, system, err := parsePStatus(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2500*time.Millisecond, user)
+	assert.Equal(t, time.Microsecond, system)
+
+	_, _, err = parsePStatus(b[:100])
+	assert.Error(t, err)
+}
+
+func TestParsePRCred(t *testing.T) {
+	b := make([]byte, prcredMinSize)
+	be := binary.BigEndian
+	be.PutUint64(b[prcredSUID:], 202)
+	be.PutUint64(b[prcredSGID:], 1)
+
+	suid, sgid, err := parsePRCred(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 202, suid)
+	assert.EqualValues(t, 1, sgid)
+}
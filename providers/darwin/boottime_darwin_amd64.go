@@ -20,20 +20,41 @@
 package darwin
 
 import (
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
 )
 
 const kernBoottimeMIB = "kern.boottime"
 
-func BootTime() (time.Time, error) {
+var bootClock = shared.NewBootTimeClock(func() (time.Time, error) {
 	var tv syscall.Timeval
 	if err := sysctlByName(kernBoottimeMIB, &tv); err != nil {
 		return time.Time{}, errors.Wrap(err, "failed to get host uptime")
 	}
 
-	bootTime := time.Unix(int64(tv.Sec), int64(tv.Usec)*int64(time.Microsecond))
-	return bootTime, nil
+	return time.Unix(int64(tv.Sec), int64(tv.Usec)*int64(time.Microsecond)), nil
+})
+
+func BootTime() (time.Time, error) {
+	return bootClock.BootTime()
+}
+
+var bootSessionUUID struct {
+	sync.Once
+	id string
+}
+
+// bootID returns kern.bootsessionuuid, a UUID that is generated on each
+// boot. It is only read once because it doesn't change while the current
+// process is running. An empty string is returned if it cannot be read.
+func bootID() string {
+	bootSessionUUID.Do(func() {
+		bootSessionUUID.id, _ = syscall.Sysctl("kern.bootsessionuuid")
+	})
+	return bootSessionUUID.id
 }
@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#include <sys/timex.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ClockInfo returns the kernel clock discipline state from ntp_adjtime which
+// is maintained by timed, the NTP server from /etc/ntp.conf, and the version
+// of the time zone database.
+func (h *host) ClockInfo() (*types.ClockStatus, error) {
+	var tx C.struct_timex
+	state, err := C.ntp_adjtime(&tx)
+	if state < 0 {
+		return nil, errors.Wrap(err, "ntp_adjtime failed")
+	}
+
+	unit := time.Microsecond
+	if tx.status&C.STA_NANO != 0 {
+		unit = time.Nanosecond
+	}
+	status := &types.ClockStatus{
+		Synchronized:   state != C.TIME_ERROR && tx.status&C.STA_UNSYNC == 0,
+		Offset:         time.Duration(tx.offset) * unit,
+		Frequency:      float64(tx.freq) / 65536,
+		MaxError:       time.Duration(tx.maxerror) * time.Microsecond,
+		EstimatedError: time.Duration(tx.esterror) * time.Microsecond,
+	}
+
+	if content, err := ioutil.ReadFile("/etc/ntp.conf"); err == nil {
+		status.Source = ntpServer(content)
+	}
+	if content, err := ioutil.ReadFile("/usr/share/zoneinfo/+VERSION"); err == nil {
+		status.TZDataVersion = shared.TZDataVersion(content)
+	}
+	return status, nil
+}
+
+// ntpServer returns the first server from ntp.conf.
+func ntpServer(content []byte) string {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) >= 2 && fields[0] == "server" {
+			return fields[1]
+		}
+	}
+	return ""
+}
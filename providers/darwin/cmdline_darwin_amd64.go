@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// KernelCmdline returns the boot-args from kern.bootargs.
+func (h *host) KernelCmdline() (*types.KernelCmdlineInfo, error) {
+	bootArgs, err := syscall.Sysctl("kern.bootargs")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kern.bootargs")
+	}
+
+	raw := strings.TrimSpace(bootArgs)
+	return &types.KernelCmdlineInfo{
+		Raw:        raw,
+		Parameters: shared.ParseKernelCmdline(raw),
+	}, nil
+}
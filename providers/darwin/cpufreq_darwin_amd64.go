@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// CPUFrequency returns the nominal frequency of each logical CPU. macOS does
+// not expose the live frequency without private frameworks so Current is the
+// value of hw.cpufrequency.
+func (h *host) CPUFrequency() ([]types.CPUFrequencyInfo, error) {
+	var current, min, max uint64
+	if err := sysctlByName("hw.cpufrequency", &current); err != nil {
+		return nil, errors.Wrap(err, "failed to get hw.cpufrequency")
+	}
+	sysctlByName("hw.cpufrequency_min", &min)
+	sysctlByName("hw.cpufrequency_max", &max)
+
+	var ncpu uint32
+	if err := sysctlByName("hw.logicalcpu", &ncpu); err != nil {
+		return nil, errors.Wrap(err, "failed to get hw.logicalcpu")
+	}
+
+	freqs := make([]types.CPUFrequencyInfo, 0, ncpu)
+	for i := 0; i < int(ncpu); i++ {
+		freqs = append(freqs, types.CPUFrequencyInfo{
+			CPU:     i,
+			Current: current,
+			Min:     min,
+			Max:     max,
+		})
+	}
+	return freqs, nil
+}
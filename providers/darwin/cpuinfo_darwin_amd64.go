@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// CPUInfo returns the processor model and topology. The result is cached in
+// shared.StaticCache.
+func (h *host) CPUInfo() (*types.ProcessorInfo, error) {
+	v, err := h.cache.Get("cpuinfo", func() (interface{}, error) {
+		return readCPUInfo()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.CopyProcessorInfo(v.(*types.ProcessorInfo)), nil
+}
+
+func readCPUInfo() (*types.ProcessorInfo, error) {
+	info := &types.ProcessorInfo{}
+
+	var err error
+	if info.Vendor, err = syscall.Sysctl("machdep.cpu.vendor"); err != nil {
+		return nil, errors.Wrap(err, "failed to get CPU vendor")
+	}
+	if info.ModelName, err = syscall.Sysctl("machdep.cpu.brand_string"); err != nil {
+		return nil, errors.Wrap(err, "failed to get CPU brand string")
+	}
+
+	for _, v := range []struct {
+		name  string
+		value *int
+	}{
+		{"hw.packages", &info.Sockets},
+		{"hw.physicalcpu", &info.PhysicalCores},
+		{"hw.logicalcpu", &info.LogicalCores},
+	} {
+		var n uint32
+		if err := sysctlByName(v.name, &n); err != nil {
+			return nil, errors.Wrapf(err, "failed to get %v", v.name)
+		}
+		*v.value = int(n)
+	}
+
+	// The frequencies are not reported on Apple silicon.
+	sysctlByName("hw.cpufrequency", &info.BaseFrequency)
+	sysctlByName("hw.cpufrequency_max", &info.MaxFrequency)
+
+	for _, c := range []struct {
+		name  string
+		level int
+		typ   string
+	}{
+		{"hw.l1dcachesize", 1, types.CPUCacheData},
+		{"hw.l1icachesize", 1, types.CPUCacheInstruction},
+		{"hw.l2cachesize", 2, types.CPUCacheUnified},
+		{"hw.l3cachesize", 3, types.CPUCacheUnified},
+	} {
+		var size uint64
+		if err := sysctlByName(c.name, &size); err != nil || size == 0 {
+			continue
+		}
+		info.Caches = append(info.Caches, types.CPUCacheInfo{Level: c.level, Type: c.typ, Size: size})
+	}
+
+	// Feature names are upper case (e.g. SSE4.2 and AVX2).
+	for _, name := range []string{"machdep.cpu.features", "machdep.cpu.leaf7_features", "machdep.cpu.extfeatures"} {
+		features, err := syscall.Sysctl(name)
+		if err != nil {
+			continue
+		}
+		for _, f := range strings.Fields(features) {
+			info.Flags = append(info.Flags, strings.Replace(strings.ToLower(f), ".", "_", -1))
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,250 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+enum { busPCI = 1, busUSB = 2 };
+
+typedef struct {
+	int bus;
+	char id[32];
+	char parent[32];
+	uint32_t vendorID;
+	uint32_t productID;
+	uint32_t class;
+	char vendor[128];
+	char product[128];
+	char serial[128];
+	char driver[128];
+} device;
+
+// copyProperty copies a CFString or CFData property into buf. It returns the
+// number of bytes copied or -1 if the property is missing.
+static int
+copyProperty(io_registry_entry_t entry, CFStringRef key, void *buf, size_t size)
+{
+	memset(buf, 0, size);
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, key, kCFAllocatorDefault, 0);
+	if (value == NULL) {
+		return -1;
+	}
+
+	int n = -1;
+	if (CFGetTypeID(value) == CFDataGetTypeID()) {
+		CFIndex len = CFDataGetLength((CFDataRef)value);
+		if (len > (CFIndex)size) {
+			len = size;
+		}
+		CFDataGetBytes((CFDataRef)value, CFRangeMake(0, len), (UInt8 *)buf);
+		n = len;
+	} else if (CFGetTypeID(value) == CFStringGetTypeID()) {
+		if (CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8)) {
+			n = strlen(buf);
+		}
+	}
+	CFRelease(value);
+	return n;
+}
+
+static uint32_t
+numberProperty(io_registry_entry_t entry, CFStringRef key)
+{
+	uint32_t n = 0;
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, key, kCFAllocatorDefault, 0);
+	if (value == NULL) {
+		return 0;
+	}
+	if (CFGetTypeID(value) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)value, kCFNumberSInt32Type, &n);
+	}
+	CFRelease(value);
+	return n;
+}
+
+static int
+deviceBus(io_registry_entry_t entry)
+{
+	if (IOObjectConformsTo(entry, "IOPCIDevice")) {
+		return busPCI;
+	}
+	if (IOObjectConformsTo(entry, "IOUSBHostDevice") || IOObjectConformsTo(entry, "IOUSBDevice")) {
+		return busUSB;
+	}
+	return 0;
+}
+
+// deviceID writes the PCI address (e.g. 0:20:0) or the USB location ID (e.g.
+// 0x14100000) of the device into buf.
+static void
+deviceID(io_registry_entry_t entry, int bus, char *buf, size_t size)
+{
+	if (bus == busPCI) {
+		copyProperty(entry, CFSTR("pcidebug"), buf, size - 1);
+	} else {
+		snprintf(buf, size, "0x%08x", numberProperty(entry, CFSTR("locationID")));
+	}
+}
+
+// deviceParent writes the ID of the closest ancestor that is a PCI or USB
+// device into buf.
+static void
+deviceParent(io_registry_entry_t entry, char *buf, size_t size)
+{
+	buf[0] = 0;
+	io_registry_entry_t current = entry;
+	IOObjectRetain(current);
+	for (;;) {
+		io_registry_entry_t parent;
+		kern_return_t kr = IORegistryEntryGetParentEntry(current, kIOServicePlane, &parent);
+		IOObjectRelease(current);
+		if (kr != KERN_SUCCESS) {
+			return;
+		}
+		int bus = deviceBus(parent);
+		if (bus != 0) {
+			deviceID(parent, bus, buf, size);
+			IOObjectRelease(parent);
+			return;
+		}
+		current = parent;
+	}
+}
+
+// deviceDriver writes the class of the first child of the device, which is
+// the driver that matched it, into buf.
+static void
+deviceDriver(io_registry_entry_t entry, char *buf, size_t size)
+{
+	buf[0] = 0;
+	io_registry_entry_t child;
+	if (IORegistryEntryGetChildEntry(entry, kIOServicePlane, &child) != KERN_SUCCESS) {
+		return;
+	}
+	io_name_t name;
+	if (IOObjectGetClass(child, name) == KERN_SUCCESS) {
+		strlcpy(buf, name, size);
+	}
+	IOObjectRelease(child);
+}
+
+// devices copies up to max PCI and USB devices from the IORegistry into out.
+// It returns the number of devices.
+static int
+devices(const char *className, device *out, int max)
+{
+	io_iterator_t iter;
+	if (IOServiceGetMatchingServices(kIOMasterPortDefault, IOServiceMatching(className), &iter) != KERN_SUCCESS) {
+		return 0;
+	}
+
+	int n = 0;
+	io_registry_entry_t entry;
+	while ((entry = IOIteratorNext(iter)) != MACH_PORT_NULL) {
+		int bus = deviceBus(entry);
+		if (n < max && bus != 0) {
+			device *dev = &out[n++];
+			memset(dev, 0, sizeof(*dev));
+			dev->bus = bus;
+			deviceID(entry, bus, dev->id, sizeof(dev->id));
+			deviceParent(entry, dev->parent, sizeof(dev->parent));
+			deviceDriver(entry, dev->driver, sizeof(dev->driver));
+
+			if (bus == busPCI) {
+				uint8_t class[4];
+				copyProperty(entry, CFSTR("vendor-id"), &dev->vendorID, sizeof(dev->vendorID));
+				copyProperty(entry, CFSTR("device-id"), &dev->productID, sizeof(dev->productID));
+				copyProperty(entry, CFSTR("class-code"), class, sizeof(class));
+				dev->class = class[0] | class[1] << 8 | class[2] << 16;
+				copyProperty(entry, CFSTR("model"), dev->product, sizeof(dev->product) - 1);
+			} else {
+				dev->vendorID = numberProperty(entry, CFSTR("idVendor"));
+				dev->productID = numberProperty(entry, CFSTR("idProduct"));
+				dev->class = numberProperty(entry, CFSTR("bDeviceClass"));
+				copyProperty(entry, CFSTR("USB Vendor Name"), dev->vendor, sizeof(dev->vendor));
+				copyProperty(entry, CFSTR("USB Product Name"), dev->product, sizeof(dev->product));
+				copyProperty(entry, CFSTR("USB Serial Number"), dev->serial, sizeof(dev->serial));
+			}
+		}
+		IOObjectRelease(entry);
+	}
+	IOObjectRelease(iter);
+	return n;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// maxDevices limits the number of devices of each class.
+const maxDevices = 256
+
+// deviceClasses are the IOKit classes of the PCI and USB devices. USB
+// devices are IOUSBHostDevice since macOS 10.11.
+var deviceClasses = []string{"IOPCIDevice", "IOUSBHostDevice"}
+
+// Devices lists the PCI and USB devices from the IORegistry. PCI devices are
+// identified by their address (see "ioreg -r -c IOPCIDevice") and USB
+// devices by their location ID.
+func (h *host) Devices() ([]types.DeviceInfo, error) {
+	buf := make([]C.device, maxDevices)
+
+	var devices []types.DeviceInfo
+	for _, class := range deviceClasses {
+		cClass := C.CString(class)
+		n := C.devices(cClass, &buf[0], C.int(len(buf)))
+		C.free(unsafe.Pointer(cClass))
+
+		for _, dev := range buf[:n] {
+			info := types.DeviceInfo{
+				Bus:       types.DeviceBusPCI,
+				ID:        C.GoString(&dev.id[0]),
+				Parent:    C.GoString(&dev.parent[0]),
+				VendorID:  fmt.Sprintf("%04x", uint32(dev.vendorID)&0xffff),
+				ProductID: fmt.Sprintf("%04x", uint32(dev.productID)&0xffff),
+				Class:     fmt.Sprintf("%06x", uint32(dev.class)),
+				Vendor:    C.GoString(&dev.vendor[0]),
+				Product:   C.GoString(&dev.product[0]),
+				Driver:    C.GoString(&dev.driver[0]),
+				Serial:    C.GoString(&dev.serial[0]),
+			}
+			if dev.bus == C.busUSB {
+				info.Bus = types.DeviceBusUSB
+				info.Class = fmt.Sprintf("%02x", uint32(dev.class))
+			} else {
+				info.Vendor = pciVendors[uint32(dev.vendorID)&0xffff]
+			}
+			devices = append(devices, info)
+		}
+	}
+	return devices, nil
+}
@@ -0,0 +1,182 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdint.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+#include <IOKit/IOCFPlugIn.h>
+#include <IOKit/storage/ata/ATASMARTLib.h>
+
+typedef struct {
+	char name[32];
+	char model[128];
+	char serial[128];
+	int nvme;
+	int exceeded; // -1 unknown, 0 passed, 1 threshold exceeded.
+	int hasData;
+	uint8_t data[512];
+} smartDevice;
+
+static void
+copyString(CFTypeRef value, char *buf, size_t size)
+{
+	memset(buf, 0, size);
+	if (value != NULL && CFGetTypeID(value) == CFStringGetTypeID()) {
+		CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8);
+	}
+}
+
+static Boolean
+boolProperty(io_registry_entry_t entry, CFStringRef key)
+{
+	Boolean rtn = false;
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, key, kCFAllocatorDefault, 0);
+	if (value == NULL) {
+		return false;
+	}
+	if (CFGetTypeID(value) == CFBooleanGetTypeID()) {
+		rtn = CFBooleanGetValue((CFBooleanRef)value);
+	}
+	CFRelease(value);
+	return rtn;
+}
+
+// readATASMART reads the SMART status and data through the ATA SMART user
+// client of the device.
+static void
+readATASMART(io_service_t service, smartDevice *dev)
+{
+	IOCFPlugInInterface **plugin = NULL;
+	SInt32 score = 0;
+	if (IOCreatePlugInInterfaceForService(service, kIOATASMARTUserClientTypeID, kIOCFPlugInInterfaceID, &plugin, &score) != kIOReturnSuccess) {
+		return;
+	}
+
+	IOATASMARTInterface **smart = NULL;
+	if ((*plugin)->QueryInterface(plugin, CFUUIDGetUUIDBytes(kIOATASMARTInterfaceID), (LPVOID *)&smart) == S_OK && smart != NULL) {
+		Boolean exceeded = false;
+		if ((*smart)->SMARTReturnStatus(smart, &exceeded) == kIOReturnSuccess) {
+			dev->exceeded = exceeded ? 1 : 0;
+		}
+		if ((*smart)->SMARTReadData(smart, (ATASMARTData *)dev->data) == kIOReturnSuccess) {
+			dev->hasData = 1;
+		}
+		(*smart)->Release(smart);
+	}
+	IODestroyPlugInInterface(plugin);
+}
+
+// smartDevices copies up to max SMART capable block storage devices into
+// out and returns their number or -1 on error.
+static int
+smartDevices(smartDevice *out, int max)
+{
+	io_iterator_t iter;
+	if (IOServiceGetMatchingServices(kIOMasterPortDefault, IOServiceMatching("IOBlockStorageDevice"), &iter) != KERN_SUCCESS) {
+		return -1;
+	}
+
+	int n = 0;
+	io_service_t service;
+	while ((service = IOIteratorNext(iter)) != MACH_PORT_NULL) {
+		Boolean ata = boolProperty(service, CFSTR("SMART Capable"));
+		Boolean nvme = boolProperty(service, CFSTR("NVMe SMART Capable"));
+		if (n < max && (ata || nvme)) {
+			smartDevice *dev = &out[n++];
+			memset(dev, 0, sizeof(*dev));
+			dev->nvme = nvme;
+			dev->exceeded = -1;
+
+			CFTypeRef name = IORegistryEntrySearchCFProperty(service, kIOServicePlane, CFSTR("BSD Name"), kCFAllocatorDefault, kIORegistryIterateRecursively);
+			copyString(name, dev->name, sizeof(dev->name));
+			if (name != NULL) {
+				CFRelease(name);
+			}
+
+			CFTypeRef chars = IORegistryEntryCreateCFProperty(service, CFSTR("Device Characteristics"), kCFAllocatorDefault, 0);
+			if (chars != NULL) {
+				if (CFGetTypeID(chars) == CFDictionaryGetTypeID()) {
+					copyString(CFDictionaryGetValue((CFDictionaryRef)chars, CFSTR("Product Name")), dev->model, sizeof(dev->model));
+					copyString(CFDictionaryGetValue((CFDictionaryRef)chars, CFSTR("Serial Number")), dev->serial, sizeof(dev->serial));
+				}
+				CFRelease(chars);
+			}
+
+			if (ata) {
+				readATASMART(service, dev);
+			}
+		}
+		IOObjectRelease(service);
+	}
+	IOObjectRelease(iter);
+	return n;
+}
+*/
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// DiskHealth reads the SMART data of ATA disks through the ATA SMART user
+// client of IOKit. NVMe disks are reported without SMART data because their
+// SMART interface is not part of the public SDK.
+func (h *host) DiskHealth() ([]types.DiskHealthInfo, error) {
+	var buf [16]C.smartDevice
+	n := C.smartDevices(&buf[0], C.int(len(buf)))
+	if n < 0 {
+		return nil, errors.New("failed to list IOBlockStorageDevice services")
+	}
+
+	disks := make([]types.DiskHealthInfo, 0, int(n))
+	for _, dev := range buf[:n] {
+		info := types.DiskHealthInfo{
+			Name:     C.GoString(&dev.name[0]),
+			Model:    strings.TrimSpace(C.GoString(&dev.model[0])),
+			Serial:   strings.TrimSpace(C.GoString(&dev.serial[0])),
+			Protocol: types.DiskProtocolATA,
+		}
+		if dev.nvme != 0 {
+			info.Protocol = types.DiskProtocolNVMe
+		}
+
+		switch dev.exceeded {
+		case 0:
+			info.Status = types.DiskHealthPassed
+		case 1:
+			info.Status = types.DiskHealthFailing
+		}
+		if dev.hasData != 0 {
+			shared.ParseATASMARTData(C.GoBytes(unsafe.Pointer(&dev.data[0]), C.int(len(dev.data))), &info)
+		}
+		disks = append(disks, info)
+	}
+	return disks, nil
+}
@@ -0,0 +1,176 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"bufio"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	dynamicStoreGlobalDNSKey  = "State:/Network/Global/DNS"
+	dynamicStoreServicePrefix = "State:/Network/Service/"
+	etcResolverDir            = "/etc/resolver"
+)
+
+// parseDynamicStoreDNS parses the DNS configuration that dynamicStoreDNS
+// copies from the SCDynamicStore. Each key of the store starts a block of
+// lines of the form "<attribute> <value>":
+//
+//	key State:/Network/Service/<id>/DNS
+//	nameserver 192.168.1.1
+//	search example.com
+//
+// The DNS and IP entities of a service are merged into one resolver so that
+// the resolver is reported with the name of the interface of the service.
+func parseDynamicStoreDNS(text string) *types.DNSConfigInfo {
+	info := &types.DNSConfigInfo{}
+	services := map[string]*types.DNSResolverInfo{}
+
+	var global bool
+	var current *types.DNSResolverInfo
+	var domain string
+	finish := func() {
+		if current != nil && len(current.SearchDomains) == 0 && domain != "" {
+			current.SearchDomains = []string{domain}
+		}
+		if global && len(info.SearchDomains) == 0 && domain != "" {
+			info.SearchDomains = []string{domain}
+		}
+		domain = ""
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		attr, value := parts[0], parts[1]
+
+		if attr == "key" {
+			finish()
+			global, current = false, nil
+			switch {
+			case value == dynamicStoreGlobalDNSKey:
+				global = true
+			case strings.HasPrefix(value, dynamicStoreServicePrefix):
+				id := strings.TrimPrefix(value, dynamicStoreServicePrefix)
+				if i := strings.IndexByte(id, '/'); i >= 0 {
+					id = id[:i]
+				}
+				if current = services[id]; current == nil {
+					current = &types.DNSResolverInfo{}
+					services[id] = current
+				}
+			}
+			continue
+		}
+
+		switch {
+		case global:
+			switch attr {
+			case "nameserver":
+				info.Nameservers = append(info.Nameservers, value)
+			case "search":
+				info.SearchDomains = append(info.SearchDomains, value)
+			case "domain":
+				domain = value
+			}
+		case current != nil:
+			switch attr {
+			case "nameserver":
+				current.Nameservers = append(current.Nameservers, value)
+			case "search":
+				current.SearchDomains = append(current.SearchDomains, value)
+			case "domain":
+				domain = value
+			case "match":
+				current.Domains = append(current.Domains, value)
+			case "interface":
+				current.Interface = value
+			}
+		}
+	}
+	finish()
+
+	for _, r := range services {
+		if len(r.Nameservers) > 0 {
+			info.Resolvers = append(info.Resolvers, *r)
+		}
+	}
+	sort.Slice(info.Resolvers, func(i, j int) bool {
+		a, b := info.Resolvers[i], info.Resolvers[j]
+		if a.Interface != b.Interface {
+			return a.Interface < b.Interface
+		}
+		return strings.Join(a.Domains, ",") < strings.Join(b.Domains, ",")
+	})
+	return info
+}
+
+// etcResolvers reads the resolver(5) files in /etc/resolver. Each file
+// configures the nameservers for the domain it is named after.
+func etcResolvers(dir string) []types.DNSResolverInfo {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var resolvers []types.DNSResolverInfo
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			continue
+		}
+		if r := parseResolverFile(f.Name(), string(content)); len(r.Nameservers) > 0 {
+			resolvers = append(resolvers, r)
+		}
+	}
+	return resolvers
+}
+
+func parseResolverFile(name, content string) types.DNSResolverInfo {
+	r := types.DNSResolverInfo{Domains: []string{name}}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			r.Nameservers = append(r.Nameservers, fields[1])
+		case "domain":
+			r.Domains = fields[1:2]
+		case "search":
+			r.SearchDomains = fields[1:]
+		}
+	}
+	return r
+}
@@ -0,0 +1,141 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework SystemConfiguration -framework CoreFoundation
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <SystemConfiguration/SystemConfiguration.h>
+
+static void
+appendLine(char *buf, size_t size, const char *attr, CFTypeRef value)
+{
+	char tmp[1024];
+	if (value == NULL || CFGetTypeID(value) != CFStringGetTypeID()) {
+		return;
+	}
+	if (!CFStringGetCString((CFStringRef)value, tmp, sizeof(tmp), kCFStringEncodingUTF8)) {
+		return;
+	}
+	size_t len = strlen(buf);
+	if (len < size) {
+		snprintf(buf + len, size - len, "%s %s\n", attr, tmp);
+	}
+}
+
+static void
+appendArray(char *buf, size_t size, const char *attr, CFDictionaryRef dict, CFStringRef key)
+{
+	CFTypeRef value = CFDictionaryGetValue(dict, key);
+	if (value == NULL || CFGetTypeID(value) != CFArrayGetTypeID()) {
+		return;
+	}
+	for (CFIndex i = 0; i < CFArrayGetCount((CFArrayRef)value); i++) {
+		appendLine(buf, size, attr, CFArrayGetValueAtIndex((CFArrayRef)value, i));
+	}
+}
+
+// dynamicStoreDNS writes the global DNS configuration and the DNS and IP
+// entities of the network services from the SCDynamicStore into buf in the
+// format read by parseDynamicStoreDNS. It returns -1 on error.
+static int
+dynamicStoreDNS(char *buf, size_t size)
+{
+	buf[0] = 0;
+
+	SCDynamicStoreRef store = SCDynamicStoreCreate(NULL, CFSTR("go-sysinfo"), NULL, NULL);
+	if (store == NULL) {
+		return -1;
+	}
+
+	CFStringRef global = SCDynamicStoreKeyCreateNetworkGlobalEntity(NULL, kSCDynamicStoreDomainState, kSCEntNetDNS);
+	CFStringRef patterns[3] = {
+		SCDynamicStoreKeyCreateNetworkServiceEntity(NULL, kSCDynamicStoreDomainState, kSCCompAnyRegex, kSCEntNetDNS),
+		SCDynamicStoreKeyCreateNetworkServiceEntity(NULL, kSCDynamicStoreDomainState, kSCCompAnyRegex, kSCEntNetIPv4),
+		SCDynamicStoreKeyCreateNetworkServiceEntity(NULL, kSCDynamicStoreDomainState, kSCCompAnyRegex, kSCEntNetIPv6),
+	};
+	CFArrayRef keyList = CFArrayCreate(NULL, (const void **)&global, 1, &kCFTypeArrayCallBacks);
+	CFArrayRef patternList = CFArrayCreate(NULL, (const void **)patterns, 3, &kCFTypeArrayCallBacks);
+	CFDictionaryRef values = SCDynamicStoreCopyMultiple(store, keyList, patternList);
+
+	CFRelease(patternList);
+	CFRelease(keyList);
+	for (int i = 0; i < 3; i++) {
+		CFRelease(patterns[i]);
+	}
+	CFRelease(global);
+	CFRelease(store);
+
+	if (values == NULL) {
+		return -1;
+	}
+
+	CFIndex count = CFDictionaryGetCount(values);
+	const void **keys = malloc(count * sizeof(void *));
+	const void **dicts = malloc(count * sizeof(void *));
+	CFDictionaryGetKeysAndValues(values, keys, dicts);
+	for (CFIndex i = 0; i < count; i++) {
+		CFDictionaryRef dict = (CFDictionaryRef)dicts[i];
+		if (CFGetTypeID(dict) != CFDictionaryGetTypeID()) {
+			continue;
+		}
+		appendLine(buf, size, "key", keys[i]);
+		appendArray(buf, size, "nameserver", dict, kSCPropNetDNSServerAddresses);
+		appendArray(buf, size, "search", dict, kSCPropNetDNSSearchDomains);
+		appendLine(buf, size, "domain", CFDictionaryGetValue(dict, kSCPropNetDNSDomainName));
+		appendArray(buf, size, "match", dict, kSCPropNetDNSSupplementalMatchDomains);
+		appendLine(buf, size, "interface", CFDictionaryGetValue(dict, kSCPropInterfaceName));
+	}
+	free(keys);
+	free(dicts);
+	CFRelease(values);
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const dynamicStoreDNSBufSize = 64 * 1024
+
+// DNSConfig returns the DNS configuration from the SCDynamicStore with the
+// resolvers of the network services and the scoped resolvers configured in
+// /etc/resolver.
+func (h *host) DNSConfig() (*types.DNSConfigInfo, error) {
+	buf := (*C.char)(C.malloc(dynamicStoreDNSBufSize))
+	defer C.free(unsafe.Pointer(buf))
+
+	if C.dynamicStoreDNS(buf, dynamicStoreDNSBufSize) != 0 {
+		return nil, errors.New("failed to copy DNS configuration from SCDynamicStore")
+	}
+
+	info := parseDynamicStoreDNS(C.GoString(buf))
+	info.Resolvers = append(info.Resolvers, etcResolvers(etcResolverDir)...)
+	return info, nil
+}
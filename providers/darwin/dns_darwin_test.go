@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const dynamicStoreDNSFixture = `key State:/Network/Service/8A2C/IPv4
+interface en0
+key State:/Network/Global/DNS
+nameserver 192.168.1.1
+domain lan
+key State:/Network/Service/8A2C/DNS
+nameserver 192.168.1.1
+domain lan
+key State:/Network/Service/F00D/DNS
+nameserver 10.8.0.1
+search corp.example.com
+match corp.example.com
+match example.net
+key State:/Network/Service/F00D/IPv4
+interface utun3
+key State:/Network/Service/0000/IPv6
+interface en5
+`
+
+func TestParseDynamicStoreDNS(t *testing.T) {
+	info := parseDynamicStoreDNS(dynamicStoreDNSFixture)
+	assert.Equal(t, &types.DNSConfigInfo{
+		Nameservers:   []string{"192.168.1.1"},
+		SearchDomains: []string{"lan"},
+		Resolvers: []types.DNSResolverInfo{
+			{
+				Interface:     "en0",
+				Nameservers:   []string{"192.168.1.1"},
+				SearchDomains: []string{"lan"},
+			},
+			{
+				Interface:     "utun3",
+				Nameservers:   []string{"10.8.0.1"},
+				SearchDomains: []string{"corp.example.com"},
+				Domains:       []string{"corp.example.com", "example.net"},
+			},
+		},
+	}, info)
+}
+
+func TestParseResolverFile(t *testing.T) {
+	r := parseResolverFile("consul", "# Forward to the local agent.\nnameserver 127.0.0.1\nport 8600\n")
+	assert.Equal(t, types.DNSResolverInfo{
+		Nameservers: []string{"127.0.0.1"},
+		Domains:     []string{"consul"},
+	}, r)
+}
@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"bytes"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// mntNoWait (MNT_NOWAIT) makes getfsstat return the cached file system
+// statistics instead of blocking on unresponsive file systems.
+const mntNoWait = 2
+
+func fileSystems() ([]types.FileSystemInfo, error) {
+	// The number of mounts can change between calls.
+	var stats []syscall.Statfs_t
+	for {
+		n, err := syscall.Getfsstat(nil, mntNoWait)
+		if err != nil {
+			return nil, errors.Wrap(err, "getfsstat failed")
+		}
+
+		stats = make([]syscall.Statfs_t, n+1)
+		if n, err = syscall.Getfsstat(stats, mntNoWait); err != nil {
+			return nil, errors.Wrap(err, "getfsstat failed")
+		}
+		if n < len(stats) {
+			stats = stats[:n]
+			break
+		}
+	}
+
+	filesystems := make([]types.FileSystemInfo, 0, len(stats))
+	for _, st := range stats {
+		blockSize := uint64(st.Bsize)
+		fs := types.FileSystemInfo{
+			Device:     int8SliceToString(st.Mntfromname[:]),
+			MountPoint: int8SliceToString(st.Mntonname[:]),
+			Type:       int8SliceToString(st.Fstypename[:]),
+			Total:      st.Blocks * blockSize,
+			Free:       st.Bfree * blockSize,
+			Available:  st.Bavail * blockSize,
+			Inodes:     st.Files,
+			InodesFree: st.Ffree,
+		}
+		fs.Used = fs.Total - fs.Free
+		fs.InodesUsed = fs.Inodes - fs.InodesFree
+		filesystems = append(filesystems, fs)
+	}
+	return filesystems, nil
+}
+
+func int8SliceToString(s []int8) string {
+	buf := bytes.NewBuffer(make([]byte, len(s)))
+	buf.Reset()
+
+	for _, b := range s {
+		if b == 0 {
+			break
+		}
+		buf.WriteByte(byte(b))
+	}
+	return buf.String()
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"howett.net/plist"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	alfPreferences = "/Library/Preferences/com.apple.alf.plist"
+	alfProduct     = "Application Firewall"
+)
+
+// alfState contains the keys of com.apple.alf.plist. globalstate is 0 when
+// the firewall is off, 1 when it is on, and 2 when it blocks all incoming
+// connections.
+type alfState struct {
+	GlobalState int `plist:"globalstate"`
+}
+
+// firewallInfo reports the state of the Application Firewall from its
+// preferences. The state of pf is not reported because its status is only
+// available through an ioctl whose structure is not part of the SDK.
+func firewallInfo() (*types.FirewallInfo, error) {
+	data, err := ioutil.ReadFile(alfPreferences)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read application firewall preferences")
+	}
+	return parseALFPreferences(data)
+}
+
+func parseALFPreferences(data []byte) (*types.FirewallInfo, error) {
+	var state alfState
+	if _, err := plist.Unmarshal(data, &state); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal application firewall preferences")
+	}
+
+	profile := types.FirewallProfile{
+		Name:    "alf",
+		Enabled: state.GlobalState != 0,
+	}
+	// In the normal mode the firewall asks whether to accept connections
+	// per application so there is no default inbound policy.
+	if state.GlobalState == 2 {
+		profile.DefaultInbound = types.FirewallPolicyBlock
+	}
+	if profile.Enabled {
+		profile.DefaultOutbound = types.FirewallPolicyAllow
+	}
+
+	return &types.FirewallInfo{
+		Enabled:  profile.Enabled,
+		Product:  alfProduct,
+		Profiles: []types.FirewallProfile{profile},
+	}, nil
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const alfPreferencesFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>allowsignedenabled</key>
+	<integer>1</integer>
+	<key>globalstate</key>
+	<integer>2</integer>
+	<key>stealthenabled</key>
+	<integer>0</integer>
+</dict>
+</plist>
+`
+
+func TestParseALFPreferences(t *testing.T) {
+	info, err := parseALFPreferences([]byte(alfPreferencesFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.FirewallInfo{
+		Enabled: true,
+		Product: "Application Firewall",
+		Profiles: []types.FirewallProfile{
+			{Name: "alf", Enabled: true, DefaultInbound: "block", DefaultOutbound: "allow"},
+		},
+	}, info)
+}
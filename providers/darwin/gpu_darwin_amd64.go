@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdint.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+typedef struct {
+	char model[128];
+	char pcidebug[32];
+	uint32_t vendorID;
+	uint32_t deviceID;
+	uint64_t vramMB;
+} gpuDevice;
+
+// dataProperty copies up to size bytes of a CFData property into buf. It
+// returns the number of bytes copied or -1 if the property is missing.
+static int
+dataProperty(io_registry_entry_t entry, CFStringRef key, void *buf, size_t size)
+{
+	memset(buf, 0, size);
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, key, kCFAllocatorDefault, 0);
+	if (value == NULL) {
+		return -1;
+	}
+
+	int n = -1;
+	if (CFGetTypeID(value) == CFDataGetTypeID()) {
+		CFIndex len = CFDataGetLength((CFDataRef)value);
+		if (len > (CFIndex)size) {
+			len = size;
+		}
+		CFDataGetBytes((CFDataRef)value, CFRangeMake(0, len), (UInt8 *)buf);
+		n = len;
+	} else if (CFGetTypeID(value) == CFStringGetTypeID()) {
+		if (CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8)) {
+			n = strlen(buf);
+		}
+	}
+	CFRelease(value);
+	return n;
+}
+
+static uint64_t
+numberProperty(io_registry_entry_t entry, CFStringRef key)
+{
+	uint64_t n = 0;
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, key, kCFAllocatorDefault, 0);
+	if (value == NULL) {
+		return 0;
+	}
+	if (CFGetTypeID(value) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)value, kCFNumberSInt64Type, &n);
+	}
+	CFRelease(value);
+	return n;
+}
+
+// gpuDevices copies up to max PCI display controllers into out and returns
+// their number or -1 on error.
+static int
+gpuDevices(gpuDevice *out, int max)
+{
+	io_iterator_t iter;
+	if (IOServiceGetMatchingServices(kIOMasterPortDefault, IOServiceMatching("IOPCIDevice"), &iter) != KERN_SUCCESS) {
+		return -1;
+	}
+
+	int n = 0;
+	io_registry_entry_t entry;
+	while ((entry = IOIteratorNext(iter)) != MACH_PORT_NULL) {
+		// class-code is 0x03xxxx for display controllers.
+		uint8_t classCode[4];
+		if (n < max && dataProperty(entry, CFSTR("class-code"), classCode, sizeof(classCode)) == 4 && classCode[2] == 0x03) {
+			gpuDevice *gpu = &out[n++];
+			dataProperty(entry, CFSTR("model"), gpu->model, sizeof(gpu->model) - 1);
+			dataProperty(entry, CFSTR("pcidebug"), gpu->pcidebug, sizeof(gpu->pcidebug) - 1);
+			dataProperty(entry, CFSTR("vendor-id"), &gpu->vendorID, sizeof(gpu->vendorID));
+			dataProperty(entry, CFSTR("device-id"), &gpu->deviceID, sizeof(gpu->deviceID));
+			gpu->vramMB = numberProperty(entry, CFSTR("VRAM,totalMB"));
+		}
+		IOObjectRelease(entry);
+	}
+	IOObjectRelease(iter);
+	return n;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// pciVendors are the vendors of the GPUs and most other PCI devices used in
+// Macs.
+var pciVendors = map[uint32]string{
+	0x1002: "Advanced Micro Devices, Inc. [AMD/ATI]",
+	0x10de: "NVIDIA Corporation",
+	0x8086: "Intel Corporation",
+}
+
+// GPUs lists the PCI display controllers from the IORegistry (see
+// "ioreg -r -c IOPCIDevice"). Integrated Intel GPUs do not report their VRAM
+// because they use system memory.
+func (h *host) GPUs() ([]types.GPUInfo, error) {
+	var buf [8]C.gpuDevice
+	n := C.gpuDevices(&buf[0], C.int(len(buf)))
+	if n < 0 {
+		return nil, errors.New("failed to list IOPCIDevice services")
+	}
+
+	gpus := make([]types.GPUInfo, 0, int(n))
+	for _, dev := range buf[:n] {
+		vendorID := uint32(dev.vendorID) & 0xffff
+		deviceID := uint32(dev.deviceID) & 0xffff
+		gpus = append(gpus, types.GPUInfo{
+			Vendor: pciVendors[vendorID],
+			Model:  C.GoString(&dev.model[0]),
+			PCIID:  fmt.Sprintf("%04x:%04x", vendorID, deviceID),
+			BusID:  C.GoString(&dev.pcidebug[0]),
+			VRAM:   uint64(dev.vramMB) * 1024 * 1024,
+		})
+	}
+	return gpus, nil
+}
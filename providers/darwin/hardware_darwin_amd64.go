@@ -0,0 +1,106 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// platformProperty copies a CFString or CFData property of the
+// IOPlatformExpertDevice into buf. It returns -1 if the property is missing.
+static int
+platformProperty(const char *key, char *buf, size_t size)
+{
+	io_service_t service = IOServiceGetMatchingService(kIOMasterPortDefault,
+		IOServiceMatching("IOPlatformExpertDevice"));
+	if (service == MACH_PORT_NULL) {
+		return -1;
+	}
+
+	CFStringRef cfKey = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef value = IORegistryEntryCreateCFProperty(service, cfKey, kCFAllocatorDefault, 0);
+	CFRelease(cfKey);
+	IOObjectRelease(service);
+	if (value == NULL) {
+		return -1;
+	}
+
+	int rtn = -1;
+	memset(buf, 0, size);
+	if (CFGetTypeID(value) == CFStringGetTypeID()) {
+		if (CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8)) {
+			rtn = 0;
+		}
+	} else if (CFGetTypeID(value) == CFDataGetTypeID()) {
+		CFIndex len = CFDataGetLength((CFDataRef)value);
+		if (len >= (CFIndex)size) {
+			len = size - 1;
+		}
+		CFDataGetBytes((CFDataRef)value, CFRangeMake(0, len), (UInt8 *)buf);
+		rtn = 0;
+	}
+	CFRelease(value);
+	return rtn;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Hardware returns the hardware information from the IOPlatformExpertDevice
+// (see "ioreg -d2 -c IOPlatformExpertDevice"). Macs do not report BIOS or
+// chassis information. The result is cached in shared.StaticCache.
+func (h *host) Hardware() (*types.HardwareInfo, error) {
+	v, err := h.cache.Get("hardware", func() (interface{}, error) {
+		return readHardware()
+	})
+	if err != nil {
+		return nil, err
+	}
+	hw := *v.(*types.HardwareInfo)
+	return &hw, nil
+}
+
+func readHardware() (*types.HardwareInfo, error) {
+	return &types.HardwareInfo{
+		Manufacturer: platformProperty("manufacturer"),
+		ProductName:  platformProperty("model"),
+		SerialNumber: platformProperty("IOPlatformSerialNumber"),
+		UUID:         platformProperty("IOPlatformUUID"),
+	}, nil
+}
+
+func platformProperty(key string) string {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var buf [256]C.char
+	if C.platformProperty(cKey, &buf[0], C.size_t(len(buf))) != 0 {
+		return ""
+	}
+	return C.GoString(&buf[0])
+}
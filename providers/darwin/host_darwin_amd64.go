@@ -35,33 +35,139 @@ func init() {
 	registry.Register(darwinSystem{})
 }
 
-type darwinSystem struct{}
+type darwinSystem struct {
+	fields    types.ProcessField // Fields collected by the processes. Zero selects all fields.
+	envFilter types.EnvFilter    // Applied to the environment of the processes.
+	cache     shared.CachePolicy // TTL of the StaticCache lookups of the host.
+}
+
+// WithFields returns a provider whose processes only read the selected
+// information.
+func (s darwinSystem) WithFields(fields types.ProcessField) interface{} {
+	s.fields = fields
+	return s
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s darwinSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+// WithCacheTTL returns a provider that caches the host data for ttl.
+func (s darwinSystem) WithCacheTTL(ttl time.Duration) interface{} {
+	s.cache = shared.CacheTTL(ttl)
+	return s
+}
 
 func (s darwinSystem) Host() (types.Host, error) {
-	return newHost()
+	return newHost(s.cache)
+}
+
+// FileSystems returns the mounted file systems and whether the volumes are
+// encrypted with FileVault.
+func (s darwinSystem) FileSystems() ([]types.FileSystemInfo, error) {
+	filesystems, err := fileSystems()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range filesystems {
+		fs := &filesystems[i]
+		if fs.Encrypted = volumeEncrypted(fs.MountPoint); fs.Encrypted != nil && *fs.Encrypted {
+			fs.Encryption = "filevault"
+		}
+	}
+	return filesystems, nil
 }
 
 type host struct {
-	info types.HostInfo
+	info  types.HostInfo
+	cache shared.CachePolicy
 }
 
 func (h *host) Info() types.HostInfo {
 	return h.info
 }
 
+// Uptime returns the time since boot.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock.Uptime()
+}
+
+// loadAvg is struct loadavg.
+type loadAvg struct {
+	Ldavg  [3]uint32
+	_      uint32
+	Fscale int64
+}
+
+// LoadAverage returns the load averages from vm.loadavg.
+func (h *host) LoadAverage() (*types.LoadAverageInfo, error) {
+	var la loadAvg
+	if err := sysctlByName("vm.loadavg", &la); err != nil {
+		return nil, errors.Wrap(err, "failed to read vm.loadavg")
+	}
+
+	return shared.FixedPointLoadAverage(la.Ldavg, la.Fscale), nil
+}
+
 func (h *host) CPUTime() (types.CPUTimes, error) {
 	cpu, err := getHostCPULoadInfo()
 	if err != nil {
 		return types.CPUTimes{}, errors.Wrap(err, "failed to get host CPU usage")
 	}
 
+	return cpu.times(time.Duration(getClockTicks())), nil
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	cpus, err := getHostCPULoadInfoPerCPU()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get per CPU usage")
+	}
+
 	ticksPerSecond := time.Duration(getClockTicks())
 
-	return types.CPUTimes{
-		User:   time.Duration(cpu.User) * time.Second / ticksPerSecond,
-		System: time.Duration(cpu.System) * time.Second / ticksPerSecond,
-		Idle:   time.Duration(cpu.Idle) * time.Second / ticksPerSecond,
-		Nice:   time.Duration(cpu.Nice) * time.Second / ticksPerSecond,
+	times := make([]types.CPUTimes, 0, len(cpus))
+	for _, cpu := range cpus {
+		times = append(times, cpu.times(ticksPerSecond))
+	}
+	return times, nil
+}
+
+func (h *host) NetworkInterfaces() ([]types.NetworkInterfaceInfo, error) {
+	return networkInterfaces()
+}
+
+func (h *host) Routes() ([]types.RouteInfo, error) {
+	return routes()
+}
+
+func (h *host) Neighbors() ([]types.NeighborInfo, error) {
+	return neighbors()
+}
+
+// VMStat returns paging counters from host_statistics64. macOS does not
+// report context switch, interrupt, or fork counters.
+func (h *host) VMStat() (*types.VMStatInfo, error) {
+	pageSize, err := getPageSize()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get page size")
+	}
+
+	vmStat, err := getHostVMInfo64()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get virtual memory statistics")
+	}
+
+	return &types.VMStatInfo{
+		PageIn:     vmStat.Pageins * pageSize,
+		PageOut:    vmStat.Pageouts * pageSize,
+		SwapIn:     vmStat.Swapins * pageSize,
+		SwapOut:    vmStat.Swapouts * pageSize,
+		PageFaults: vmStat.Faults,
 	}, nil
 }
 
@@ -126,11 +232,15 @@ func (h *host) Memory() (*types.HostMemoryInfo, error) {
 	mem.VirtualUsed = swap.Used
 	mem.VirtualFree = swap.Available
 
+	mem.Cached = uint64(vmStat.External_page_count) * pageSizeBytes
+	mem.SwapIn = uint64(vmStat.Swapins) * pageSizeBytes
+	mem.SwapOut = uint64(vmStat.Swapouts) * pageSizeBytes
+
 	return &mem, nil
 }
 
-func newHost() (*host, error) {
-	h := &host{}
+func newHost(cache shared.CachePolicy) (*host, error) {
+	h := &host{cache: cache}
 	r := &reader{}
 	r.architecture(h)
 	r.bootTime(h)
@@ -149,7 +259,7 @@ type reader struct {
 
 func (r *reader) addErr(err error) bool {
 	if err != nil {
-		if errors.Cause(err) != types.ErrNotImplemented {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
 			r.errs = append(r.errs, err)
 		}
 		return true
@@ -206,11 +316,18 @@ func (r *reader) kernelVersion(h *host) {
 }
 
 func (r *reader) os(h *host) {
-	v, err := OperatingSystem()
+	v, err := h.cache.Get("os", func() (interface{}, error) {
+		osInfo, err := OperatingSystem()
+		if err != nil {
+			return nil, err
+		}
+		fixCompatVersion(osInfo)
+		return osInfo, nil
+	})
 	if r.addErr(err) {
 		return
 	}
-	h.info.OS = v
+	h.info.OS = shared.CopyOSInfo(v.(*types.OSInfo))
 }
 
 func (r *reader) time(h *host) {
@@ -218,9 +335,10 @@ func (r *reader) time(h *host) {
 }
 
 func (r *reader) uniqueID(h *host) {
-	v, err := MachineID()
+	v, err := machineID(h.cache)
 	if r.addErr(err) {
 		return
 	}
 	h.info.UniqueID = v
+	h.info.UniqueIDSource = types.MachineIDSourcePlatformUUID
 }
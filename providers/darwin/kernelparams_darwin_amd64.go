@@ -0,0 +1,115 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #include <sys/sysctl.h>
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+)
+
+// MIB values of the sysctl meta interface (see bsd/kern/kern_newsysctl.c).
+const (
+	sysctlMetaName   = 1
+	sysctlMetaNext   = 2
+	sysctlMetaOIDFmt = 4
+)
+
+// KernelParam returns the value of the named sysctl.
+func (h *host) KernelParam(name string) (string, error) {
+	mib, err := nametomib(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "unknown sysctl %v", name)
+	}
+
+	value, err := readKernelParam(mib)
+	if err != nil {
+		return "", errors.Wrapf(err, "sysctl %v failed", name)
+	}
+	return value, nil
+}
+
+// KernelParams walks the sysctl tree like "sysctl -a". Opaque values (e.g.
+// structs) and values that cannot be read are omitted.
+func (h *host) KernelParams() (map[string]string, error) {
+	params := map[string]string{}
+
+	var mib []C.int
+	for {
+		var data []byte
+		err := sysctl(append([]C.int{0, sysctlMetaNext}, mib...), &data)
+		if err == syscall.ENOENT || (err == nil && len(data) == 0) {
+			// The end of the tree was reached.
+			return params, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get next sysctl")
+		}
+
+		mib = make([]C.int, len(data)/4)
+		for i := range mib {
+			mib[i] = C.int(int32(binary.LittleEndian.Uint32(data[i*4:])))
+		}
+
+		var name []byte
+		if err = sysctl(append([]C.int{0, sysctlMetaName}, mib...), &name); err != nil {
+			continue
+		}
+		value, err := readKernelParam(mib)
+		if err != nil {
+			continue
+		}
+		params[cString(name)] = value
+	}
+}
+
+func readKernelParam(mib []C.int) (string, error) {
+	// The format is returned as the kind (u_int) followed by the format string.
+	var fmtData []byte
+	if err := sysctl(append([]C.int{0, sysctlMetaOIDFmt}, mib...), &fmtData); err != nil {
+		return "", err
+	}
+	if len(fmtData) < 4 {
+		return "", errors.New("invalid sysctl format")
+	}
+	if binary.LittleEndian.Uint32(fmtData)&C.CTLTYPE == C.CTLTYPE_NODE {
+		return "", errors.New("sysctl is a node")
+	}
+
+	var data []byte
+	if err := sysctl(mib, &data); err != nil {
+		return "", err
+	}
+	return shared.FormatSysctl(cString(fmtData[4:]), data)
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
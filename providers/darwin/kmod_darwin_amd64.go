@@ -0,0 +1,138 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/kext/KextManager.h>
+
+typedef struct {
+	char     name[256];
+	char     version[64];
+	char     path[1024];
+	uint64_t address;
+	uint64_t size;
+	int32_t  refs;
+} kextInfo;
+
+static void
+copyString(CFDictionaryRef dict, const char *key, char *buf, size_t size)
+{
+	CFStringRef cfKey = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef value = CFDictionaryGetValue(dict, cfKey);
+	CFRelease(cfKey);
+	if (value != NULL && CFGetTypeID(value) == CFStringGetTypeID()) {
+		CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8);
+	}
+}
+
+static void
+copyNumber(CFDictionaryRef dict, const char *key, CFNumberType type, void *out)
+{
+	CFStringRef cfKey = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef value = CFDictionaryGetValue(dict, cfKey);
+	CFRelease(cfKey);
+	if (value != NULL && CFGetTypeID(value) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)value, type, out);
+	}
+}
+
+// loadedKexts returns a calloc'ed array describing the loaded kernel
+// extensions. The caller must free it. It returns -1 on failure.
+static int
+loadedKexts(kextInfo **out)
+{
+	CFDictionaryRef kexts = KextManagerCopyLoadedKextInfo(NULL, NULL);
+	if (kexts == NULL) {
+		return -1;
+	}
+
+	CFIndex count = CFDictionaryGetCount(kexts);
+	const void **values = calloc(count, sizeof(void *));
+	kextInfo *infos = calloc(count, sizeof(kextInfo));
+	if (values == NULL || infos == NULL) {
+		free(values);
+		free(infos);
+		CFRelease(kexts);
+		return -1;
+	}
+	CFDictionaryGetKeysAndValues(kexts, NULL, values);
+
+	for (CFIndex i = 0; i < count; i++) {
+		CFDictionaryRef kext = (CFDictionaryRef)values[i];
+		if (CFGetTypeID(kext) != CFDictionaryGetTypeID()) {
+			continue;
+		}
+		copyString(kext, "CFBundleIdentifier", infos[i].name, sizeof(infos[i].name));
+		copyString(kext, "CFBundleVersion", infos[i].version, sizeof(infos[i].version));
+		copyString(kext, "OSBundlePath", infos[i].path, sizeof(infos[i].path));
+		copyNumber(kext, "OSBundleLoadAddress", kCFNumberSInt64Type, &infos[i].address);
+		copyNumber(kext, "OSBundleLoadSize", kCFNumberSInt64Type, &infos[i].size);
+		copyNumber(kext, "OSBundleRetainCount", kCFNumberSInt32Type, &infos[i].refs);
+	}
+
+	free(values);
+	CFRelease(kexts);
+	*out = infos;
+	return (int)count;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// LoadedModules returns the kernel extensions reported by
+// KextManagerCopyLoadedKextInfo.
+func (h *host) LoadedModules() ([]types.KernelModuleInfo, error) {
+	var infos *C.kextInfo
+	n := C.loadedKexts(&infos)
+	if n < 0 {
+		return nil, errors.New("KextManagerCopyLoadedKextInfo failed")
+	}
+	defer C.free(unsafe.Pointer(infos))
+
+	kexts := (*[1 << 20]C.kextInfo)(unsafe.Pointer(infos))[:n:n]
+	modules := make([]types.KernelModuleInfo, 0, len(kexts))
+	for i := range kexts {
+		kext := &kexts[i]
+		name := C.GoString(&kext.name[0])
+		if name == "" {
+			continue
+		}
+		modules = append(modules, types.KernelModuleInfo{
+			Name:     name,
+			Path:     C.GoString(&kext.path[0]),
+			Version:  C.GoString(&kext.version[0]),
+			Address:  uint64(kext.address),
+			Size:     uint64(kext.size),
+			RefCount: int(kext.refs),
+		})
+	}
+	return modules, nil
+}
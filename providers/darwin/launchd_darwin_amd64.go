@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework ServiceManagement -framework CoreFoundation
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <ServiceManagement/ServiceManagement.h>
+
+typedef struct {
+	int  pid;
+	int  user;
+	char label[256];
+} launchdJob;
+
+static CFIndex
+appendJobs(CFStringRef domain, int user, launchdJob **jobs, CFIndex n)
+{
+#pragma clang diagnostic push
+#pragma clang diagnostic ignored "-Wdeprecated-declarations"
+	CFArrayRef dicts = SMCopyAllJobDictionaries(domain);
+#pragma clang diagnostic pop
+	if (dicts == NULL) {
+		return n;
+	}
+
+	CFIndex count = CFArrayGetCount(dicts);
+	launchdJob *grown = realloc(*jobs, (n + count) * sizeof(launchdJob));
+	if (grown == NULL) {
+		CFRelease(dicts);
+		return n;
+	}
+	*jobs = grown;
+
+	for (CFIndex i = 0; i < count; i++) {
+		CFDictionaryRef job = CFArrayGetValueAtIndex(dicts, i);
+		CFNumberRef pid = CFDictionaryGetValue(job, CFSTR("PID"));
+		CFStringRef label = CFDictionaryGetValue(job, CFSTR("Label"));
+		if (pid == NULL || label == NULL) {
+			continue;
+		}
+
+		launchdJob *out = &(*jobs)[n];
+		memset(out, 0, sizeof(*out));
+		out->user = user;
+		if (!CFNumberGetValue(pid, kCFNumberIntType, &out->pid) ||
+			!CFStringGetCString(label, out->label, sizeof(out->label), kCFStringEncodingUTF8)) {
+			continue;
+		}
+		n++;
+	}
+	CFRelease(dicts);
+	return n;
+}
+
+// launchdJobs returns the running jobs of the system domain and of the
+// domain of the current user. The caller must free the array.
+static CFIndex
+launchdJobs(launchdJob **jobs)
+{
+	*jobs = NULL;
+	CFIndex n = appendJobs(kSMDomainSystemLaunchd, 0, jobs, 0);
+	return appendJobs(kSMDomainUserLaunchd, 1, jobs, n);
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const launchdPID = 1
+
+// runningLaunchdJobs returns the running launchd jobs keyed by PID.
+func runningLaunchdJobs() map[int]types.LaunchdJobInfo {
+	var list *C.launchdJob
+	n := int(C.launchdJobs(&list))
+	if list == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(list))
+
+	jobs := make(map[int]types.LaunchdJobInfo, n)
+	for _, job := range (*[1 << 20]C.launchdJob)(unsafe.Pointer(list))[:n:n] {
+		info := types.LaunchdJobInfo{
+			Label:  C.GoString(&job.label[0]),
+			Domain: "system",
+			PID:    int(job.pid),
+		}
+		if job.user != 0 {
+			info.Domain = "user"
+		}
+		jobs[info.PID] = info
+	}
+	return jobs
+}
+
+// launchdJobTable is the table of running launchd jobs. It is read on first
+// use and shared by the processes of one enumeration so that launchd is only
+// queried once.
+type launchdJobTable struct {
+	once sync.Once
+	jobs map[int]types.LaunchdJobInfo
+}
+
+func (t *launchdJobTable) get() map[int]types.LaunchdJobInfo {
+	if t == nil {
+		return runningLaunchdJobs()
+	}
+	t.once.Do(func() { t.jobs = runningLaunchdJobs() })
+	return t.jobs
+}
+
+// launchdJob returns the job that manages the process by walking up its
+// ancestors until a process started by launchd is found. It returns nil when
+// no job is found (e.g. for processes of another user's domain).
+func launchdJob(table *launchdJobTable, pid, ppid int) *types.LaunchdJobInfo {
+	jobs := table.get()
+	for seen := 0; pid > launchdPID && seen < 64; seen++ {
+		if job, found := jobs[pid]; found {
+			return &job
+		}
+		if ppid == launchdPID {
+			return nil
+		}
+
+		pid = ppid
+		var task procTaskAllInfo
+		if err := getProcTaskAllInfo(pid, &task); err != nil {
+			return nil
+		}
+		ppid = int(task.Pbsd.Pbi_ppid)
+	}
+	return nil
+}
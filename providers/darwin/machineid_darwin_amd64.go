@@ -27,13 +27,27 @@ import (
 	"unsafe"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
 )
 
 // MachineID returns the Hardware UUID also accessible via
 // About this Mac -> System Report and as the field
 // IOPlatformUUID in the output of "ioreg -d2 -c IOPlatformExpertDevice".
+// The result is cached in shared.StaticCache.
 func MachineID() (string, error) {
-	return getHostUUID()
+	return machineID(shared.CachePolicy{})
+}
+
+func machineID(cache shared.CachePolicy) (string, error) {
+	v, err := cache.Get("host-uuid", func() (interface{}, error) {
+		return getHostUUID()
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
 }
 
 func getHostUUID() (string, error) {
@@ -59,3 +73,12 @@ func getHostUUID() (string, error) {
 
 	return C.GoString(&uuid[0]), nil
 }
+
+// MachineIDs returns the hardware UUID. It is unique for each virtual machine.
+func (h *host) MachineIDs() ([]types.MachineIDInfo, error) {
+	id, err := machineID(h.cache)
+	if err != nil {
+		return nil, err
+	}
+	return []types.MachineIDInfo{{ID: id, Source: types.MachineIDSourcePlatformUUID}}, nil
+}
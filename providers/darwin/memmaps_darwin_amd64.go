@@ -0,0 +1,95 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #cgo LDFLAGS:-lproc
+// #include <libproc.h>
+// #include <mach/vm_prot.h>
+// #include <mach/vm_region.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// MemoryMaps returns the memory regions of the process. The regions are
+// enumerated with proc_pidinfo PROC_PIDREGIONPATHINFO which does not require
+// task_for_pid.
+func (p *process) MemoryMaps() ([]types.MemoryMapInfo, error) {
+	pageSize, err := getPageSize()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get page size")
+	}
+
+	var maps []types.MemoryMapInfo
+	var info C.struct_proc_regionwithpathinfo
+	size := C.int(unsafe.Sizeof(info))
+	for addr := uint64(0); ; {
+		n, err := C.proc_pidinfo(C.int(p.pid), C.PROC_PIDREGIONPATHINFO, C.uint64_t(addr), unsafe.Pointer(&info), size)
+		if n != size {
+			if len(maps) == 0 && err != nil {
+				return nil, err
+			}
+			// There are no more regions.
+			break
+		}
+
+		region := &info.prp_prinfo
+		start := uint64(region.pri_address)
+		end := start + uint64(region.pri_size)
+		addr = end
+
+		path := C.GoString(&info.prp_vip.vip_path[0])
+		maps = append(maps, types.MemoryMapInfo{
+			Start:       start,
+			End:         end,
+			Permissions: regionPermissions(uint32(region.pri_protection), uint32(region.pri_share_mode)),
+			Path:        path,
+			Offset:      uint64(region.pri_offset),
+			Anonymous:   path == "",
+			Resident:    uint64(region.pri_pages_resident) * pageSize,
+		})
+	}
+	return maps, nil
+}
+
+// regionPermissions formats the protection and share mode of a region in the
+// rwxp form used by Linux.
+func regionPermissions(protection, shareMode uint32) string {
+	perms := []byte("---p")
+	if protection&C.VM_PROT_READ != 0 {
+		perms[0] = 'r'
+	}
+	if protection&C.VM_PROT_WRITE != 0 {
+		perms[1] = 'w'
+	}
+	if protection&C.VM_PROT_EXECUTE != 0 {
+		perms[2] = 'x'
+	}
+	switch shareMode {
+	case C.SM_SHARED, C.SM_TRUESHARED, C.SM_SHARED_ALIASED:
+		perms[3] = 's'
+	}
+	return string(perms)
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Modules returns the file-backed images that have at least one executable
+// region.
+func (p *process) Modules() ([]types.ModuleInfo, error) {
+	maps, err := p.MemoryMaps()
+	if err != nil {
+		return nil, err
+	}
+
+	type module struct {
+		types.ModuleInfo
+		end        uint64
+		executable bool
+	}
+
+	var order []string
+	modules := map[string]*module{}
+	for _, m := range maps {
+		if m.Anonymous {
+			continue
+		}
+
+		mod, found := modules[m.Path]
+		if !found {
+			mod = &module{ModuleInfo: types.ModuleInfo{Path: m.Path, BaseAddress: m.Start}, end: m.End}
+			modules[m.Path] = mod
+			order = append(order, m.Path)
+		}
+		if m.Start < mod.BaseAddress {
+			mod.BaseAddress = m.Start
+		}
+		if m.End > mod.end {
+			mod.end = m.End
+		}
+		if strings.Contains(m.Permissions, "x") {
+			mod.executable = true
+		}
+	}
+
+	infos := make([]types.ModuleInfo, 0, len(order))
+	for _, path := range order {
+		mod := modules[path]
+		if !mod.executable {
+			continue
+		}
+		mod.Size = mod.end - mod.BaseAddress
+		infos = append(infos, mod.ModuleInfo)
+	}
+	return infos, nil
+}
@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// Offsets within the if_msghdr2 struct.
+	ifMsghdr2IndexOffset = 12
+	ifMsghdr2DataOffset  = 32
+
+	// Offsets of the 64-bit counters within the if_data64 struct.
+	ifData64IPacketsOffset = 24
+	ifData64IErrorsOffset  = 32
+	ifData64OPacketsOffset = 40
+	ifData64OErrorsOffset  = 48
+	ifData64IBytesOffset   = 64
+	ifData64OBytesOffset   = 72
+	ifData64IQDropsOffset  = 96
+	ifData64Size           = 128
+)
+
+func networkInterfaces() ([]types.NetworkInterfaceInfo, error) {
+	ifcs, err := shared.NetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	rib, err := syscall.RouteRIB(syscall.NET_RT_IFLIST2, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get interface list")
+	}
+
+	counters := parseIfList2(rib)
+	for i, ifc := range ifcs {
+		ifcs[i].Counters = counters[ifc.Index]
+	}
+	return ifcs, nil
+}
+
+// parseIfList2 parses the if_msghdr2 messages returned by the NET_RT_IFLIST2
+// sysctl and returns the counters by interface index. The 64-bit counters
+// are used because the ones returned by NET_RT_IFLIST wrap at 4 GiB.
+func parseIfList2(b []byte) map[int]*types.NetworkInterfaceCounters {
+	counters := map[int]*types.NetworkInterfaceCounters{}
+	for len(b) >= 4 {
+		msgLen := int(binary.LittleEndian.Uint16(b))
+		if msgLen < 4 || msgLen > len(b) {
+			break
+		}
+		msg := b[:msgLen]
+		b = b[msgLen:]
+
+		if msg[3] != syscall.RTM_IFINFO2 || len(msg) < ifMsghdr2DataOffset+ifData64Size {
+			continue
+		}
+
+		index := int(binary.LittleEndian.Uint16(msg[ifMsghdr2IndexOffset:]))
+		data := msg[ifMsghdr2DataOffset:]
+		counters[index] = &types.NetworkInterfaceCounters{
+			RxBytes:   binary.LittleEndian.Uint64(data[ifData64IBytesOffset:]),
+			RxPackets: binary.LittleEndian.Uint64(data[ifData64IPacketsOffset:]),
+			RxErrors:  binary.LittleEndian.Uint64(data[ifData64IErrorsOffset:]),
+			RxDropped: binary.LittleEndian.Uint64(data[ifData64IQDropsOffset:]),
+			TxBytes:   binary.LittleEndian.Uint64(data[ifData64OBytesOffset:]),
+			TxPackets: binary.LittleEndian.Uint64(data[ifData64OPacketsOffset:]),
+			TxErrors:  binary.LittleEndian.Uint64(data[ifData64OErrorsOffset:]),
+		}
+	}
+	return counters
+}
@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseIfList2(t *testing.T) {
+	msg := make([]byte, ifMsghdr2DataOffset+ifData64Size)
+	binary.LittleEndian.PutUint16(msg, uint16(len(msg)))
+	msg[3] = syscall.RTM_IFINFO2
+	binary.LittleEndian.PutUint16(msg[ifMsghdr2IndexOffset:], 4)
+
+	data := msg[ifMsghdr2DataOffset:]
+	binary.LittleEndian.PutUint64(data[ifData64IBytesOffset:], 1000)
+	binary.LittleEndian.PutUint64(data[ifData64IPacketsOffset:], 10)
+	binary.LittleEndian.PutUint64(data[ifData64OBytesOffset:], 2000)
+	binary.LittleEndian.PutUint64(data[ifData64OPacketsOffset:], 20)
+
+	// An RTM_NEWADDR message that must be skipped.
+	other := make([]byte, 20)
+	binary.LittleEndian.PutUint16(other, uint16(len(other)))
+	other[3] = syscall.RTM_NEWADDR
+
+	counters := parseIfList2(append(other, msg...))
+	assert.Equal(t, map[int]*types.NetworkInterfaceCounters{
+		4: {RxBytes: 1000, RxPackets: 10, TxBytes: 2000, TxPackets: 20},
+	}, counters)
+}
@@ -31,8 +31,13 @@ import (
 const (
 	systemVersionPlist = "/System/Library/CoreServices/SystemVersion.plist"
 
+	// Rapid Security Responses (macOS 13+) are delivered in a cryptex that
+	// contains its own version plist.
+	cryptexSystemVersionPlist = "/System/Cryptexes/OS" + systemVersionPlist
+
 	plistProductName         = "ProductName"
 	plistProductVersion      = "ProductVersion"
+	plistProductVersionExtra = "ProductVersionExtra"
 	plistProductBuildVersion = "ProductBuildVersion"
 )
 
@@ -42,7 +47,38 @@ func OperatingSystem() (*types.OSInfo, error) {
 		return nil, errors.Wrap(err, "failed to read plist file")
 	}
 
-	return getOSInfo(data)
+	osInfo, err := getOSInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = ioutil.ReadFile(cryptexSystemVersionPlist); err == nil {
+		if err = addSupplementalVersion(osInfo, data); err != nil {
+			return nil, err
+		}
+	}
+	return osInfo, nil
+}
+
+// addSupplementalVersion updates the version extra and build of osInfo with
+// the values from a Rapid Security Response version plist.
+func addSupplementalVersion(osInfo *types.OSInfo, data []byte) error {
+	attrs := map[string]string{}
+	if _, err := plist.Unmarshal(data, &attrs); err != nil {
+		return errors.Wrap(err, "failed to unmarshal plist data")
+	}
+
+	// The plist is present without an RSR applied, but then it has no
+	// ProductVersionExtra.
+	extra := attrs[plistProductVersionExtra]
+	if extra == "" {
+		return nil
+	}
+	osInfo.VersionExtra = extra
+	if build := attrs[plistProductBuildVersion]; build != "" {
+		osInfo.Build = build
+	}
+	return nil
 }
 
 func getOSInfo(data []byte) (*types.OSInfo, error) {
@@ -66,6 +102,10 @@ func getOSInfo(data []byte) (*types.OSInfo, error) {
 		return nil, errors.Errorf("plist key %v not found", plistProductBuildVersion)
 	}
 
+	return makeOSInfo(productName, version, build, attrs[plistProductVersionExtra]), nil
+}
+
+func makeOSInfo(productName, version, build, extra string) *types.OSInfo {
 	var major, minor, patch int
 	for i, v := range strings.SplitN(version, ".", 3) {
 		switch i {
@@ -81,13 +121,14 @@ func getOSInfo(data []byte) (*types.OSInfo, error) {
 	}
 
 	return &types.OSInfo{
-		Family:   "darwin",
-		Platform: "darwin",
-		Name:     productName,
-		Version:  version,
-		Major:    major,
-		Minor:    minor,
-		Patch:    patch,
-		Build:    build,
-	}, nil
+		Family:       "darwin",
+		Platform:     "darwin",
+		Name:         productName,
+		Version:      version,
+		Major:        major,
+		Minor:        minor,
+		Patch:        patch,
+		Build:        build,
+		VersionExtra: extra,
+	}
 }
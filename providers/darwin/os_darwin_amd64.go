@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"syscall"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// compatVersion is reported by macOS 11+ to binaries built with an
+	// older SDK (see SYSTEM_VERSION_COMPAT).
+	compatVersion = "10.16"
+
+	productVersionMIB = "kern.osproductversion"
+)
+
+// fixCompatVersion replaces the compatibility version of macOS 11+ with the
+// real product version from the kernel.
+func fixCompatVersion(osInfo *types.OSInfo) {
+	if osInfo.Version != compatVersion {
+		return
+	}
+
+	version, err := syscall.Sysctl(productVersionMIB)
+	if err != nil || version == "" || version == compatVersion {
+		return
+	}
+
+	fixed := makeOSInfo(osInfo.Name, version, osInfo.Build, osInfo.VersionExtra)
+	*osInfo = *fixed
+}
@@ -56,3 +56,37 @@ func TestOperatingSystem(t *testing.T) {
 	assert.Equal(t, 6, osInfo.Patch)
 	assert.Equal(t, "16G1114", osInfo.Build)
 }
+
+const CryptexSystemVersionPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+        <key>ProductBuildVersion</key>
+        <string>22F770820d</string>
+        <key>ProductCopyright</key>
+        <string>1983-2023 Apple Inc.</string>
+        <key>ProductName</key>
+        <string>macOS</string>
+        <key>ProductUserVisibleVersion</key>
+        <string>13.4.1 (c)</string>
+        <key>ProductVersion</key>
+        <string>13.4.1</string>
+        <key>ProductVersionExtra</key>
+        <string>(c)</string>
+</dict>
+</plist>
+`
+
+func TestOperatingSystemRapidSecurityResponse(t *testing.T) {
+	osInfo, err := getOSInfo([]byte(SystemVersionPlist))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = addSupplementalVersion(osInfo, []byte(CryptexSystemVersionPlist)); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "(c)", osInfo.VersionExtra)
+	assert.Equal(t, "22F770820d", osInfo.Build)
+}
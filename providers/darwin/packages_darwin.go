@@ -0,0 +1,145 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"howett.net/plist"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const receiptsDir = "/var/db/receipts"
+
+// appBundleGlobs match the application bundles in /Applications and its
+// subdirectories (such as /Applications/Utilities).
+var appBundleGlobs = []string{"/Applications/*.app", "/Applications/*/*.app"}
+
+type bundleInfo struct {
+	Name        string `plist:"CFBundleName"`
+	DisplayName string `plist:"CFBundleDisplayName"`
+	Version     string `plist:"CFBundleShortVersionString"`
+	Build       string `plist:"CFBundleVersion"`
+	Copyright   string `plist:"NSHumanReadableCopyright"`
+}
+
+type receiptInfo struct {
+	Identifier  string    `plist:"PackageIdentifier"`
+	Version     string    `plist:"PackageVersion"`
+	InstallDate time.Time `plist:"InstallDate"`
+	Prefix      string    `plist:"InstallPrefixPath"`
+}
+
+// installedPackages returns the application bundles and the installer
+// package receipts that are listed by pkgutil --pkgs.
+func installedPackages() ([]types.PackageInfo, error) {
+	var pkgs []types.PackageInfo
+	for _, pattern := range appBundleGlobs {
+		bundles, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range bundles {
+			if pkg, err := readAppBundle(path); err == nil {
+				pkgs = append(pkgs, *pkg)
+			}
+		}
+	}
+
+	receipts, err := filepath.Glob(filepath.Join(receiptsDir, "*.plist"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range receipts {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if pkg, err := parseReceipt(data); err == nil {
+			pkgs = append(pkgs, *pkg)
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+	return pkgs, nil
+}
+
+func readAppBundle(path string) (*types.PackageInfo, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, "Contents", "Info.plist"))
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := parseBundleInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Path = path
+	if pkg.Name == "" {
+		pkg.Name = filepath.Base(path[:len(path)-len(filepath.Ext(path))])
+	}
+	if fi, err := os.Stat(path); err == nil {
+		pkg.InstallTime = fi.ModTime()
+	}
+	return pkg, nil
+}
+
+// parseBundleInfo parses the Info.plist of an application bundle.
+func parseBundleInfo(data []byte) (*types.PackageInfo, error) {
+	var info bundleInfo
+	if _, err := plist.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal Info.plist")
+	}
+
+	name := info.DisplayName
+	if name == "" {
+		name = info.Name
+	}
+	return &types.PackageInfo{
+		Name:    name,
+		Version: info.Version,
+		Release: info.Build,
+		Vendor:  info.Copyright,
+		Type:    types.PackageTypeApp,
+	}, nil
+}
+
+// parseReceipt parses an installer receipt from /var/db/receipts.
+func parseReceipt(data []byte) (*types.PackageInfo, error) {
+	var info receiptInfo
+	if _, err := plist.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal receipt")
+	}
+	if info.Identifier == "" {
+		return nil, errors.New("receipt has no PackageIdentifier")
+	}
+
+	return &types.PackageInfo{
+		Name:        info.Identifier,
+		Version:     info.Version,
+		InstallTime: info.InstallDate,
+		Path:        info.Prefix,
+		Type:        types.PackageTypePkg,
+	}, nil
+}
@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const safariInfoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>com.apple.Safari</string>
+	<key>CFBundleName</key>
+	<string>Safari</string>
+	<key>CFBundleShortVersionString</key>
+	<string>13.1</string>
+	<key>CFBundleVersion</key>
+	<string>15609.1.20.111.8</string>
+	<key>NSHumanReadableCopyright</key>
+	<string>Copyright © 2003-2020 Apple Inc.</string>
+</dict>
+</plist>
+`
+
+const receiptPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>InstallDate</key>
+	<date>2020-04-15T08:30:00Z</date>
+	<key>InstallPrefixPath</key>
+	<string>/</string>
+	<key>PackageIdentifier</key>
+	<string>com.apple.pkg.XProtectPlistConfigData</string>
+	<key>PackageVersion</key>
+	<string>2119</string>
+</dict>
+</plist>
+`
+
+func TestParseBundleInfo(t *testing.T) {
+	pkg, err := parseBundleInfo([]byte(safariInfoPlist))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.PackageInfo{
+		Name:    "Safari",
+		Version: "13.1",
+		Release: "15609.1.20.111.8",
+		Vendor:  "Copyright © 2003-2020 Apple Inc.",
+		Type:    types.PackageTypeApp,
+	}, pkg)
+}
+
+func TestParseReceipt(t *testing.T) {
+	pkg, err := parseReceipt([]byte(receiptPlist))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.PackageInfo{
+		Name:        "com.apple.pkg.XProtectPlistConfigData",
+		Version:     "2119",
+		InstallTime: time.Date(2020, 4, 15, 8, 30, 0, 0, time.UTC),
+		Path:        "/",
+		Type:        types.PackageTypePkg,
+	}, pkg)
+}
+
+const installHistoryFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<array>
+	<dict>
+		<key>date</key>
+		<date>2020-03-25T10:00:00Z</date>
+		<key>displayName</key>
+		<string>macOS 10.15.4 Update</string>
+		<key>displayVersion</key>
+		<string>10.15.4</string>
+		<key>packageIdentifiers</key>
+		<array>
+			<string>com.apple.pkg.macOSBrain</string>
+		</array>
+		<key>processName</key>
+		<string>softwareupdated</string>
+	</dict>
+	<dict>
+		<key>date</key>
+		<date>2020-05-27T09:00:00Z</date>
+		<key>displayName</key>
+		<string>XProtectPlistConfigData</string>
+		<key>displayVersion</key>
+		<string>2122</string>
+		<key>processName</key>
+		<string>softwareupdated</string>
+	</dict>
+</array>
+</plist>
+`
+
+func TestParseInstallHistory(t *testing.T) {
+	patches, err := parseInstallHistory([]byte(installHistoryFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.OSPatchInfo{
+		{ID: "XProtectPlistConfigData", Version: "2122", Description: "softwareupdated", InstallTime: time.Date(2020, 5, 27, 9, 0, 0, 0, time.UTC)},
+		{ID: "macOS 10.15.4 Update", Version: "10.15.4", Description: "softwareupdated", InstallTime: time.Date(2020, 3, 25, 10, 0, 0, 0, time.UTC)},
+	}, patches)
+}
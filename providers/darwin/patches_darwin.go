@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package darwin
+
+import (
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"howett.net/plist"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const installHistoryPlist = "/Library/Receipts/InstallHistory.plist"
+
+type installHistoryEntry struct {
+	Date           time.Time `plist:"date"`
+	DisplayName    string    `plist:"displayName"`
+	DisplayVersion string    `plist:"displayVersion"`
+	ProcessName    string    `plist:"processName"`
+}
+
+// installHistory returns the entries of InstallHistory.plist which records
+// the OS updates and packages installed by softwareupdated and installer,
+// newest first.
+func installHistory() ([]types.OSPatchInfo, error) {
+	data, err := ioutil.ReadFile(installHistoryPlist)
+	if err != nil {
+		return nil, err
+	}
+	return parseInstallHistory(data)
+}
+
+func parseInstallHistory(data []byte) ([]types.OSPatchInfo, error) {
+	var entries []installHistoryEntry
+	if _, err := plist.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal install history")
+	}
+
+	patches := make([]types.OSPatchInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.DisplayName == "" {
+			continue
+		}
+		patches = append(patches, types.OSPatchInfo{
+			ID:          e.DisplayName,
+			Version:     e.DisplayVersion,
+			Description: e.ProcessName,
+			InstallTime: e.Date,
+		})
+	}
+
+	sort.SliceStable(patches, func(i, j int) bool { return patches[i].InstallTime.After(patches[j].InstallTime) })
+	return patches, nil
+}
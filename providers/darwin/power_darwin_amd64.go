@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/ps/IOPowerSources.h>
+#include <IOKit/ps/IOPSKeys.h>
+
+typedef struct {
+	char name[128];
+	char type[64];
+	char state[64];
+	char health[64];
+	int current;  // -1 if unknown
+	int max;      // -1 if unknown
+	int charging; // 1, 0, or -1 if unknown
+	int charged;  // 1, 0, or -1 if unknown
+	int present;  // 1, 0, or -1 if unknown
+} powerSource;
+
+static void
+dictString(CFDictionaryRef dict, CFStringRef key, char *buf, size_t size)
+{
+	buf[0] = 0;
+	CFTypeRef value = CFDictionaryGetValue(dict, key);
+	if (value != NULL && CFGetTypeID(value) == CFStringGetTypeID()) {
+		CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8);
+	}
+}
+
+static int
+dictInt(CFDictionaryRef dict, CFStringRef key)
+{
+	int n = -1;
+	CFTypeRef value = CFDictionaryGetValue(dict, key);
+	if (value != NULL && CFGetTypeID(value) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)value, kCFNumberIntType, &n);
+	}
+	return n;
+}
+
+static int
+dictBool(CFDictionaryRef dict, CFStringRef key)
+{
+	CFTypeRef value = CFDictionaryGetValue(dict, key);
+	if (value != NULL && CFGetTypeID(value) == CFBooleanGetTypeID()) {
+		return CFBooleanGetValue((CFBooleanRef)value);
+	}
+	return -1;
+}
+
+// powerSources copies up to max power sources into out and the type of the
+// source that currently powers the system (e.g. "AC Power") into providing.
+// It returns the number of power sources or -1 on error.
+static int
+powerSources(powerSource *out, int max, char *providing, size_t size)
+{
+	CFTypeRef info = IOPSCopyPowerSourcesInfo();
+	if (info == NULL) {
+		return -1;
+	}
+
+	providing[0] = 0;
+	CFStringRef providingType = IOPSGetProvidingPowerSourceType(info);
+	if (providingType != NULL) {
+		CFStringGetCString(providingType, providing, size, kCFStringEncodingUTF8);
+	}
+
+	CFArrayRef list = IOPSCopyPowerSourcesList(info);
+	if (list == NULL) {
+		CFRelease(info);
+		return -1;
+	}
+
+	int n = 0;
+	for (CFIndex i = 0; i < CFArrayGetCount(list) && n < max; i++) {
+		CFDictionaryRef desc = IOPSGetPowerSourceDescription(info, CFArrayGetValueAtIndex(list, i));
+		if (desc == NULL) {
+			continue;
+		}
+
+		powerSource *ps = &out[n++];
+		dictString(desc, CFSTR(kIOPSNameKey), ps->name, sizeof(ps->name));
+		dictString(desc, CFSTR(kIOPSTypeKey), ps->type, sizeof(ps->type));
+		dictString(desc, CFSTR(kIOPSPowerSourceStateKey), ps->state, sizeof(ps->state));
+		dictString(desc, CFSTR(kIOPSBatteryHealthKey), ps->health, sizeof(ps->health));
+		ps->current = dictInt(desc, CFSTR(kIOPSCurrentCapacityKey));
+		ps->max = dictInt(desc, CFSTR(kIOPSMaxCapacityKey));
+		ps->charging = dictBool(desc, CFSTR(kIOPSIsChargingKey));
+		ps->charged = dictBool(desc, CFSTR(kIOPSIsChargedKey));
+		ps->present = dictBool(desc, CFSTR(kIOPSIsPresentKey));
+	}
+
+	CFRelease(list);
+	CFRelease(info);
+	return n;
+}
+*/
+import "C"
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Values of the IOPSKeys.h power source keys.
+const (
+	iopsACPowerValue = "AC Power"
+	iopsUPSType      = "UPS"
+)
+
+// PowerSources returns the batteries and UPSs from IOPSCopyPowerSourcesInfo.
+// The AC adapter is derived from the type of the providing power source.
+func (h *host) PowerSources() ([]types.PowerSourceInfo, error) {
+	var buf [8]C.powerSource
+	var providing [64]C.char
+	n := C.powerSources(&buf[0], C.int(len(buf)), &providing[0], C.size_t(len(providing)))
+	if n < 0 {
+		return nil, errors.New("IOPSCopyPowerSourcesInfo failed")
+	}
+
+	var sources []types.PowerSourceInfo
+	if p := C.GoString(&providing[0]); p != "" {
+		online := p == iopsACPowerValue
+		sources = append(sources, types.PowerSourceInfo{
+			Name:   "AC",
+			Type:   types.PowerSourceMains,
+			Online: &online,
+		})
+	}
+
+	for _, ps := range buf[:n] {
+		if ps.present == 0 {
+			continue
+		}
+
+		state := C.GoString(&ps.state[0])
+		info := types.PowerSourceInfo{
+			Name:   C.GoString(&ps.name[0]),
+			Type:   types.PowerSourceBattery,
+			Health: C.GoString(&ps.health[0]),
+		}
+		if C.GoString(&ps._type[0]) == iopsUPSType {
+			online := state == iopsACPowerValue
+			info.Type = types.PowerSourceUPS
+			info.Online = &online
+		}
+		if ps.current >= 0 && ps.max > 0 {
+			info.Percent = 100 * float64(ps.current) / float64(ps.max)
+		}
+		switch {
+		case ps.charging == 1:
+			info.State = types.BatteryCharging
+		case ps.charged == 1:
+			info.State = types.BatteryFull
+		case state == iopsACPowerValue:
+			info.State = types.BatteryNotCharging
+		case state != "":
+			info.State = types.BatteryDischarging
+		}
+		sources = append(sources, info)
+	}
+	return sources, nil
+}
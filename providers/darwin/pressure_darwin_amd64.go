@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// PressureStats returns the memory pressure level from
+// kern.memorystatus_vm_pressure_level. This is the same level that is
+// delivered by DISPATCH_SOURCE_TYPE_MEMORYPRESSURE. macOS does not report
+// stall times.
+func (h *host) PressureStats() (*types.PressureInfo, error) {
+	var level int32
+	if err := sysctlByName("kern.memorystatus_vm_pressure_level", &level); err != nil {
+		return nil, errors.Wrap(err, "failed to read kern.memorystatus_vm_pressure_level")
+	}
+
+	return &types.PressureInfo{MemoryLevel: memoryPressureLevel(level)}, nil
+}
+
+// memoryPressureLevel maps a kVMPressure value to a level name.
+func memoryPressureLevel(level int32) string {
+	switch level {
+	case 1:
+		return types.MemoryPressureNormal
+	case 2:
+		return types.MemoryPressureWarning
+	case 4:
+		return types.MemoryPressureCritical
+	default:
+		return ""
+	}
+}
@@ -21,19 +21,24 @@ package darwin
 
 // #cgo LDFLAGS:-lproc
 // #include <sys/sysctl.h>
+// #include <sys/stat.h>
 // #include <libproc.h>
+// #include <sys/resource.h>
 import "C"
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"os"
 	"strconv"
+	"syscall"
 	"time"
 	"unsafe"
 
 	"github.com/pkg/errors"
 
+	"github.com/elastic/go-sysinfo/providers/shared"
 	"github.com/elastic/go-sysinfo/types"
 )
 
@@ -58,6 +63,7 @@ func (s darwinSystem) Processes() ([]types.Process, error) {
 	}
 
 	bbuf := bytes.NewBuffer(buf)
+	launchd := &launchdJobTable{}
 	processes := make([]types.Process, 0, n)
 	for i := 0; i < int(n); i++ {
 		err = binary.Read(bbuf, binary.LittleEndian, &pid)
@@ -69,60 +75,206 @@ func (s darwinSystem) Processes() ([]types.Process, error) {
 			continue
 		}
 
-		processes = append(processes, &process{pid: int(pid)})
+		processes = append(processes, &process{pid: int(pid), fields: s.fields, envFilter: s.envFilter, launchd: launchd})
 	}
 	return processes, nil
 }
 
 func (s darwinSystem) Process(pid int) (types.Process, error) {
-	p := process{pid: pid}
+	p := process{pid: pid, fields: s.fields, envFilter: s.envFilter, launchd: &launchdJobTable{}}
 
 	return &p, nil
 }
 
+// ProcessWithFields returns the process and skips reading the working
+// directory and the process arguments when Info is called unless they were
+// selected.
+func (s darwinSystem) ProcessWithFields(pid int, fields types.ProcessField) (types.Process, error) {
+	return &process{pid: pid, fields: fields, envFilter: s.envFilter, launchd: &launchdJobTable{}}, nil
+}
+
+// WatchProcesses streams process events from kqueue EVFILT_PROC filters.
+// The process list is polled instead if kqueue cannot be created.
+func (s darwinSystem) WatchProcesses(ctx context.Context) (<-chan types.ProcessEvent, error) {
+	// The start events only need the PPID.
+	list := darwinSystem{fields: types.FieldNone}.Processes
+	events, err := watchKqueue(ctx, list)
+	if err == nil {
+		return events, nil
+	}
+	return shared.PollProcesses(ctx, s.Processes, shared.ProcessPollInterval)
+}
+
 func (s darwinSystem) Self() (types.Process, error) {
 	return s.Process(os.Getpid())
 }
 
 type process struct {
-	pid  int
-	cwd  string
-	exe  string
-	args []string
-	env  map[string]string
+	pid       int
+	fields    types.ProcessField // Zero selects all fields.
+	envFilter types.EnvFilter
+	cwd       string
+	exe       string
+	args      []string
+	env       map[string]string
+	launchd   *launchdJobTable // Shared by the processes of an enumeration.
 }
 
 func (p *process) PID() int {
 	return p.pid
 }
 
+// Children returns the direct children of the process.
+func (p *process) Children() ([]types.Process, error) {
+	procs, err := darwinSystem{fields: p.fields, envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Children(procs, p)
+}
+
+// Ancestors returns the parents of the process up to the root of the tree.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := darwinSystem{fields: p.fields, envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Ancestors(procs, p)
+}
+
 func (p *process) Info() (types.ProcessInfo, error) {
 	var task procTaskAllInfo
 	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
 		return types.ProcessInfo{}, err
 	}
 
-	var vnode procVnodePathInfo
-	if err := getProcVnodePathInfo(p.pid, &vnode); err != nil {
-		return types.ProcessInfo{}, err
+	var partial types.PartialInfoError
+
+	var cwd string
+	if p.fields.Includes(types.FieldCWD) {
+		var vnode procVnodePathInfo
+		if err := getProcVnodePathInfo(p.pid, &vnode); err != nil {
+			partial.Add("cwd", err)
+		} else {
+			cwd = int8SliceToString(vnode.Cdir.Path[:])
+		}
 	}
 
-	if err := kern_procargs(p.pid, p); err != nil {
-		return types.ProcessInfo{}, err
+	// The arguments of processes of other users and of processes protected
+	// by SIP can't be read.
+	var exe string
+	var args []string
+	if p.fields.Includes(types.FieldExe) || p.fields.Includes(types.FieldArgs) {
+		if err := kern_procargs(p.pid, p); err != nil {
+			if p.fields.Includes(types.FieldExe) {
+				partial.Add("exe", err)
+			}
+			if p.fields.Includes(types.FieldArgs) {
+				partial.Add("args", err)
+			}
+		}
+		if p.fields.Includes(types.FieldExe) {
+			exe = p.exe
+		}
+		if p.fields.Includes(types.FieldArgs) {
+			args = p.args
+		}
+	}
+
+	var launchd *types.LaunchdJobInfo
+	if p.fields.Includes(types.FieldLaunchd) {
+		launchd = launchdJob(p.launchd, p.pid, int(task.Pbsd.Pbi_ppid))
 	}
 
 	return types.ProcessInfo{
 		Name: int8SliceToString(task.Pbsd.Pbi_name[:]),
 		PID:  p.pid,
 		PPID: int(task.Pbsd.Pbi_ppid),
-		CWD:  int8SliceToString(vnode.Cdir.Path[:]),
-		Exe:  p.exe,
-		Args: p.args,
+		CWD:  cwd,
+		Exe:  exe,
+		Args: args,
+		StartTime: time.Unix(int64(task.Pbsd.Pbi_start_tvsec),
+			int64(task.Pbsd.Pbi_start_tvusec)*int64(time.Microsecond)),
+		State:   taskState(&task),
+		Launchd: launchd,
+	}, partial.ErrOrNil()
+}
+
+// Mach scheduling policies from mach/policy.h.
+var machPolicies = map[int32]string{
+	1: "timeshare", // POLICY_TIMESHARE
+	2: "rr",        // POLICY_RR
+	4: "fifo",      // POLICY_FIFO
+}
+
+// Scheduling returns the default scheduling policy and priority of the
+// threads of the process and its nice value.
+func (p *process) Scheduling() (*types.SchedulingInfo, error) {
+	var task procTaskAllInfo
+	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
+		return nil, err
+	}
+
+	policy, found := machPolicies[task.Ptinfo.Policy]
+	if !found {
+		policy = strconv.Itoa(int(task.Ptinfo.Policy))
+	}
+	nice := int(task.Pbsd.Pbi_nice)
+	return &types.SchedulingInfo{
+		Policy:   policy,
+		Priority: int(task.Ptinfo.Priority),
+		Nice:     &nice,
+	}, nil
+}
+
+// taskState maps the p_stat value of a process to one of the ProcessState
+// constants. Runnable and sleeping processes both report SRUN so they are
+// told apart by the number of running threads.
+func taskState(task *procTaskAllInfo) string {
+	switch processState(task.Pbsd.Pbi_status) {
+	case stateRun:
+		if task.Ptinfo.Numrunning > 0 {
+			return types.ProcessStateRunning
+		}
+		return types.ProcessStateSleeping
+	case stateSleep:
+		return types.ProcessStateSleeping
+	case stateStop:
+		return types.ProcessStateStopped
+	case stateZombie:
+		return types.ProcessStateZombie
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+// Identity returns the PID, start time, and boot session UUID of the
+// process without reading its arguments.
+func (p *process) Identity() (types.ProcessIdentity, error) {
+	var task procTaskAllInfo
+	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
+		return types.ProcessIdentity{}, err
+	}
+	return types.ProcessIdentity{
+		PID: p.pid,
 		StartTime: time.Unix(int64(task.Pbsd.Pbi_start_tvsec),
 			int64(task.Pbsd.Pbi_start_tvusec)*int64(time.Microsecond)),
+		BootID: bootID(),
 	}, nil
 }
 
+// Hashes hashes the executable of the process. The path is resolved with
+// proc_pidpath which follows the vnode of the running image so a binary that
+// was moved is still found.
+func (p *process) Hashes(algorithms ...types.HashType) (map[types.HashType]string, error) {
+	buf := make([]byte, C.PROC_PIDPATHINFO_MAXSIZE)
+	n, err := C.proc_pidpath(C.int(p.pid), unsafe.Pointer(&buf[0]), C.uint32_t(len(buf)))
+	if n <= 0 {
+		return nil, errors.Wrap(err, "proc_pidpath failed")
+	}
+	return shared.HashFile(string(buf[:n]), algorithms...)
+}
+
 func (p *process) User() (types.UserInfo, error) {
 	var task procTaskAllInfo
 	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
@@ -139,8 +291,16 @@ func (p *process) User() (types.UserInfo, error) {
 	}, nil
 }
 
+// Environment returns the environment of the process. It is read together
+// with the arguments, so it is only read here if Info has not already read
+// it (e.g. because FieldExe and FieldArgs were not selected).
 func (p *process) Environment() (map[string]string, error) {
-	return p.env, nil
+	if p.env == nil {
+		if err := kern_procargs(p.pid, p); err != nil {
+			return nil, err
+		}
+	}
+	return shared.FilterEnv(p.envFilter, p.env), nil
 }
 
 func (p *process) CPUTime() (types.CPUTimes, error) {
@@ -159,9 +319,13 @@ func (p *process) Memory() (types.MemoryInfo, error) {
 	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
 		return types.MemoryInfo{}, err
 	}
+	// macOS compresses memory before swapping it out so the compressed
+	// size is reported as swap.
+	swap, _ := getTaskVMCompressed(p.pid)
 	return types.MemoryInfo{
 		Virtual:  task.Ptinfo.Virtual_size,
 		Resident: task.Ptinfo.Resident_size,
+		Swap:     swap,
 		Metrics: map[string]uint64{
 			"page_ins":    uint64(task.Ptinfo.Pageins),
 			"page_faults": uint64(task.Ptinfo.Faults),
@@ -169,12 +333,113 @@ func (p *process) Memory() (types.MemoryInfo, error) {
 	}, nil
 }
 
+// ResourceUsage returns the page fault and context switch counts from
+// proc_pidinfo(PROC_PIDTASKINFO). Darwin counts page-ins separately from
+// faults so page-ins are reported as major faults.
+func (p *process) ResourceUsage() (*types.ResourceUsageInfo, error) {
+	var task procTaskAllInfo
+	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
+		return nil, err
+	}
+
+	usage := &types.ResourceUsageInfo{
+		MajorPageFaults: uint64(task.Ptinfo.Pageins),
+		ContextSwitches: uint64(task.Ptinfo.Csw),
+	}
+	if task.Ptinfo.Faults > task.Ptinfo.Pageins {
+		usage.MinorPageFaults = uint64(task.Ptinfo.Faults - task.Ptinfo.Pageins)
+	}
+	return usage, nil
+}
+
+// IOCounters returns the disk I/O statistics reported by
+// proc_pid_rusage(RUSAGE_INFO_V4).
+func (p *process) IOCounters() (*types.IOCountersInfo, error) {
+	var info C.struct_rusage_info_v4
+	if rtn := C.proc_pid_rusage(C.int(p.pid), C.RUSAGE_INFO_V4, (*C.rusage_info_t)(unsafe.Pointer(&info))); rtn != 0 {
+		return nil, errors.Errorf("proc_pid_rusage returned %v", rtn)
+	}
+
+	return &types.IOCountersInfo{
+		ReadBytes:  uint64(info.ri_diskio_bytesread),
+		WriteBytes: uint64(info.ri_diskio_byteswritten),
+		Metrics: map[string]uint64{
+			"logical_writes_bytes": uint64(info.ri_logical_writes),
+		},
+	}, nil
+}
+
+// OpenHandles returns the list of open file descriptors of the process.
+func (p *process) OpenHandles() ([]types.OpenHandleInfo, error) {
+	// Calling with a nil buffer returns the size needed to hold the list.
+	n := C.proc_pidinfo(C.int(p.pid), C.PROC_PIDLISTFDS, 0, nil, 0)
+	if n <= 0 {
+		return nil, errors.Errorf("proc_pidinfo with PROC_PIDLISTFDS returned %v", n)
+	}
+
+	var fdInfo C.struct_proc_fdinfo
+	fds := make([]C.struct_proc_fdinfo, int(n)/int(unsafe.Sizeof(fdInfo)))
+	n = C.proc_pidinfo(C.int(p.pid), C.PROC_PIDLISTFDS, 0, unsafe.Pointer(&fds[0]), n)
+	if n <= 0 {
+		return nil, errors.Errorf("proc_pidinfo with PROC_PIDLISTFDS returned %v", n)
+	}
+	fds = fds[:int(n)/int(unsafe.Sizeof(fdInfo))]
+
+	handles := make([]types.OpenHandleInfo, 0, len(fds))
+	for _, fd := range fds {
+		handle := types.OpenHandleInfo{
+			FD:   uint64(fd.proc_fd),
+			Type: fdTypeName(fd.proc_fdtype),
+		}
+
+		if fd.proc_fdtype == C.PROX_FDTYPE_VNODE {
+			var vnode C.struct_vnode_fdinfowithpath
+			size := C.int(unsafe.Sizeof(vnode))
+			ptr := unsafe.Pointer(&vnode)
+
+			if C.proc_pidfdinfo(C.int(p.pid), C.int(fd.proc_fd), C.PROC_PIDFDVNODEPATHINFO, ptr, size) == size {
+				handle.Path = C.GoString(&vnode.pvip.vip_path[0])
+				if vnode.pvip.vip_vi.vi_stat.vst_mode&C.S_IFMT == C.S_IFDIR {
+					handle.Type = types.HandleTypeDir
+				}
+			}
+		}
+
+		handles = append(handles, handle)
+	}
+
+	return handles, nil
+}
+
+func fdTypeName(fdType C.uint32_t) string {
+	switch fdType {
+	case C.PROX_FDTYPE_VNODE:
+		return types.HandleTypeFile
+	case C.PROX_FDTYPE_SOCKET:
+		return types.HandleTypeSocket
+	case C.PROX_FDTYPE_PIPE:
+		return types.HandleTypePipe
+	case C.PROX_FDTYPE_KQUEUE:
+		return "kqueue"
+	case C.PROX_FDTYPE_PSEM:
+		return "posix_semaphore"
+	case C.PROX_FDTYPE_PSHM:
+		return "posix_shared_memory"
+	case C.PROX_FDTYPE_FSEVENTS:
+		return "fsevents"
+	default:
+		return types.HandleTypeUnknown
+	}
+}
+
 func getProcTaskAllInfo(pid int, info *procTaskAllInfo) error {
 	size := C.int(unsafe.Sizeof(*info))
 	ptr := unsafe.Pointer(info)
 
 	n, err := C.proc_pidinfo(C.int(pid), C.PROC_PIDTASKALLINFO, 0, ptr, size)
-	if err != nil {
+	if err == syscall.ESRCH {
+		return &types.ProcessNotFoundError{PID: pid}
+	} else if err != nil {
 		return err
 	} else if n != size {
 		return errors.New("failed to read process info with proc_pidinfo")
@@ -204,7 +469,7 @@ func kern_procargs(pid int, p *process) error {
 	mib := []C.int{C.CTL_KERN, C.KERN_PROCARGS2, C.int(pid)}
 	var data []byte
 	if err := sysctl(mib, &data); err != nil {
-		return nil
+		return err
 	}
 	buf := bytes.NewBuffer(data)
 
@@ -229,10 +494,12 @@ func kern_procargs(pid int, p *process) error {
 	}
 
 	// args
-	for i := 0; i < int(argc); i++ {
-		p.args = append(p.args, string(lines[0]))
+	var args []string
+	for i := 0; i < int(argc) && len(lines) > 0; i++ {
+		args = append(args, string(lines[0]))
 		lines = lines[1:]
 	}
+	p.args = args
 
 	// env vars
 	env := make(map[string]string, len(lines))
@@ -252,16 +519,3 @@ func kern_procargs(pid int, p *process) error {
 
 	return nil
 }
-
-func int8SliceToString(s []int8) string {
-	buf := bytes.NewBuffer(make([]byte, len(s)))
-	buf.Reset()
-
-	for _, b := range s {
-		if b == 0 {
-			break
-		}
-		buf.WriteByte(byte(b))
-	}
-	return buf.String()
-}
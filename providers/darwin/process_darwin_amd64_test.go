@@ -30,6 +30,10 @@ import (
 
 var _ registry.HostProvider = darwinSystem{}
 var _ registry.ProcessProvider = darwinSystem{}
+var _ registry.ProcessFieldsSelector = darwinSystem{}
+var _ registry.ProcessWatcher = darwinSystem{}
+var _ registry.CacheTTLProvider = darwinSystem{}
+var _ registry.EnvFilterProvider = darwinSystem{}
 
 func TestKernProcInfo(t *testing.T) {
 	var p process
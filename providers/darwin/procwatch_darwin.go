@@ -0,0 +1,181 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// kqueueRescan is the kevent timeout after which the process list is read
+// again to pick up processes whose parent could not be watched.
+const kqueueRescan = time.Second
+
+// watchKqueue streams process events using kqueue EVFILT_PROC filters.
+// Exits and execs of watched processes are reported by the kernel. macOS
+// doesn't report the PID of a forked child (there is no NOTE_TRACK), so a
+// NOTE_FORK triggers a rescan of the process list that registers the new
+// processes. A child that exits before the rescan is not reported.
+func watchKqueue(ctx context.Context, list func() ([]types.Process, error)) (<-chan types.ProcessEvent, error) {
+	kq, err := syscall.Kqueue()
+	if err != nil {
+		return nil, errors.Wrap(err, "kqueue failed")
+	}
+
+	w := &kqueueWatcher{kq: kq, list: list, pids: map[int]struct{}{}, exited: map[int]struct{}{}}
+	if err = w.rescan(ctx, nil); err != nil {
+		syscall.Close(kq)
+		return nil, err
+	}
+
+	events := make(chan types.ProcessEvent)
+	go func() {
+		defer close(events)
+		defer syscall.Close(kq)
+		w.run(ctx, events)
+	}()
+	return events, nil
+}
+
+type kqueueWatcher struct {
+	kq   int
+	list func() ([]types.Process, error)
+	pids map[int]struct{} // Processes that are known to be running.
+
+	// exited are the processes whose exit was reported but which are still
+	// listed because they have not been reaped yet.
+	exited map[int]struct{}
+}
+
+func (w *kqueueWatcher) run(ctx context.Context, events chan<- types.ProcessEvent) {
+	timeout := syscall.NsecToTimespec(int64(kqueueRescan))
+	buf := make([]syscall.Kevent_t, 64)
+	for ctx.Err() == nil {
+		n, err := syscall.Kevent(w.kq, nil, buf, &timeout)
+		if err == syscall.EINTR {
+			continue
+		} else if err != nil {
+			return
+		}
+
+		rescan := n == 0
+		now := time.Now()
+		for _, ev := range buf[:n] {
+			pid := int(ev.Ident)
+			switch {
+			case ev.Fflags&syscall.NOTE_EXIT != 0:
+				delete(w.pids, pid)
+				w.exited[pid] = struct{}{}
+				if !send(ctx, events, types.ProcessEvent{Type: types.ProcessEventExit, PID: pid, Time: now}) {
+					return
+				}
+			case ev.Fflags&syscall.NOTE_EXEC != 0:
+				if !send(ctx, events, types.ProcessEvent{Type: types.ProcessEventExec, PID: pid, Time: now}) {
+					return
+				}
+			}
+			if ev.Fflags&syscall.NOTE_FORK != 0 {
+				rescan = true
+			}
+		}
+
+		if rescan {
+			// Errors are retried on the next rescan.
+			w.rescan(ctx, events)
+		}
+	}
+}
+
+// rescan reads the process list, watches the new processes, and reports
+// the processes that started or exited without an event. No events are
+// sent if events is nil.
+func (w *kqueueWatcher) rescan(ctx context.Context, events chan<- types.ProcessEvent) error {
+	procs, err := w.list()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	running := make(map[int]struct{}, len(procs))
+	for _, p := range procs {
+		pid := p.PID()
+		running[pid] = struct{}{}
+		if _, found := w.pids[pid]; found {
+			continue
+		}
+		if _, found := w.exited[pid]; found {
+			continue
+		}
+		w.pids[pid] = struct{}{}
+
+		// Processes that cannot be watched (e.g. because they exited) are
+		// still tracked by the rescans.
+		w.watch(pid)
+
+		if events == nil {
+			continue
+		}
+		event := types.ProcessEvent{Type: types.ProcessEventStart, PID: pid, Time: now}
+		if info, err := p.Info(); err == nil || types.IsPartialInfo(err) {
+			event.PPID = info.PPID
+		}
+		if !send(ctx, events, event) {
+			return ctx.Err()
+		}
+	}
+
+	for pid := range w.exited {
+		if _, found := running[pid]; !found {
+			delete(w.exited, pid)
+		}
+	}
+	for pid := range w.pids {
+		if _, found := running[pid]; found {
+			continue
+		}
+		delete(w.pids, pid)
+		if events != nil && !send(ctx, events, types.ProcessEvent{Type: types.ProcessEventExit, PID: pid, Time: now}) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// watch registers the EVFILT_PROC filter of the process.
+func (w *kqueueWatcher) watch(pid int) error {
+	change := syscall.Kevent_t{Fflags: syscall.NOTE_EXIT | syscall.NOTE_FORK | syscall.NOTE_EXEC}
+	syscall.SetKevent(&change, pid, syscall.EVFILT_PROC, syscall.EV_ADD|syscall.EV_CLEAR)
+	_, err := syscall.Kevent(w.kq, []syscall.Kevent_t{change}, nil, nil)
+	return err
+}
+
+func send(ctx context.Context, events chan<- types.ProcessEvent, event types.ProcessEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
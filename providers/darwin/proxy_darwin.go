@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const proxySourceSystemConfiguration = "systemconfiguration"
+
+// parseSystemProxies parses the proxy dictionary that systemProxies copies
+// from SCDynamicStoreCopyProxies. Each line has the form "<key> <value>" and
+// array values are written as one line per element. It returns nil when no
+// proxy is enabled.
+func parseSystemProxies(text string) *types.ProxySettings {
+	values := map[string][]string{}
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), " ", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = append(values[parts[0]], parts[1])
+		}
+	}
+	get := func(key string) string {
+		if v := values[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	proxy := func(prefix string) string {
+		host, port := get(prefix+"Proxy"), get(prefix+"Port")
+		if get(prefix+"Enable") != "1" || host == "" {
+			return ""
+		}
+		if port == "" || port == "0" {
+			return host
+		}
+		return host + ":" + port
+	}
+
+	s := &types.ProxySettings{
+		Source: proxySourceSystemConfiguration,
+		HTTP:   proxy("HTTP"),
+		HTTPS:  proxy("HTTPS"),
+		FTP:    proxy("FTP"),
+		SOCKS:  proxy("SOCKS"),
+		Bypass: values["ExceptionsList"],
+	}
+	if get("ProxyAutoConfigEnable") == "1" {
+		s.AutoConfigURL = get("ProxyAutoConfigURLString")
+	}
+	s.AutoDetect = get("ProxyAutoDiscoveryEnable") == "1"
+
+	if s.HTTP == "" && s.HTTPS == "" && s.FTP == "" && s.SOCKS == "" && s.AutoConfigURL == "" && !s.AutoDetect {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework SystemConfiguration -framework CoreFoundation
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <SystemConfiguration/SystemConfiguration.h>
+
+static void
+appendProxyValue(char *buf, size_t size, const char *key, CFTypeRef value)
+{
+	char tmp[1024];
+	if (CFGetTypeID(value) == CFStringGetTypeID()) {
+		if (!CFStringGetCString((CFStringRef)value, tmp, sizeof(tmp), kCFStringEncodingUTF8)) {
+			return;
+		}
+	} else if (CFGetTypeID(value) == CFNumberGetTypeID()) {
+		long long n;
+		if (!CFNumberGetValue((CFNumberRef)value, kCFNumberLongLongType, &n)) {
+			return;
+		}
+		snprintf(tmp, sizeof(tmp), "%lld", n);
+	} else {
+		return;
+	}
+
+	size_t len = strlen(buf);
+	if (len < size) {
+		snprintf(buf + len, size - len, "%s %s\n", key, tmp);
+	}
+}
+
+// systemProxies writes the proxy settings returned by
+// SCDynamicStoreCopyProxies into buf in the format read by
+// parseSystemProxies. It returns -1 on error.
+static int
+systemProxies(char *buf, size_t size)
+{
+	buf[0] = 0;
+
+	CFDictionaryRef proxies = SCDynamicStoreCopyProxies(NULL);
+	if (proxies == NULL) {
+		return -1;
+	}
+
+	CFIndex count = CFDictionaryGetCount(proxies);
+	const void *keys[count > 0 ? count : 1];
+	const void *values[count > 0 ? count : 1];
+	CFDictionaryGetKeysAndValues(proxies, keys, values);
+	for (CFIndex i = 0; i < count; i++) {
+		char key[128];
+		if (!CFStringGetCString((CFStringRef)keys[i], key, sizeof(key), kCFStringEncodingUTF8)) {
+			continue;
+		}
+		if (CFGetTypeID(values[i]) == CFArrayGetTypeID()) {
+			CFArrayRef array = (CFArrayRef)values[i];
+			for (CFIndex j = 0; j < CFArrayGetCount(array); j++) {
+				appendProxyValue(buf, size, key, CFArrayGetValueAtIndex(array, j));
+			}
+		} else {
+			appendProxyValue(buf, size, key, values[i]);
+		}
+	}
+
+	CFRelease(proxies);
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const systemProxiesBufSize = 16 * 1024
+
+// ProxyConfig returns the proxy settings of the current network location
+// from SCDynamicStoreCopyProxies. These are the settings shown in the
+// network preferences and by scutil --proxy.
+func (h *host) ProxyConfig() ([]types.ProxySettings, error) {
+	buf := (*C.char)(C.malloc(systemProxiesBufSize))
+	defer C.free(unsafe.Pointer(buf))
+
+	if C.systemProxies(buf, systemProxiesBufSize) != 0 {
+		return nil, errors.New("failed to copy proxy settings from SCDynamicStore")
+	}
+
+	if s := parseSystemProxies(C.GoString(buf)); s != nil {
+		return []types.ProxySettings{*s}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseSystemProxies(t *testing.T) {
+	text := `HTTPEnable 1
+HTTPProxy proxy.example.com
+HTTPPort 3128
+HTTPSEnable 0
+HTTPSProxy secure.example.com
+HTTPSPort 443
+ExceptionsList *.local
+ExceptionsList 169.254/16
+FTPPassive 1
+ProxyAutoDiscoveryEnable 0
+`
+	assert.Equal(t, &types.ProxySettings{
+		Source: "systemconfiguration",
+		HTTP:   "proxy.example.com:3128",
+		Bypass: []string{"*.local", "169.254/16"},
+	}, parseSystemProxies(text))
+
+	assert.Equal(t, &types.ProxySettings{
+		Source:        "systemconfiguration",
+		AutoConfigURL: "http://wpad.example.com/wpad.dat",
+	}, parseSystemProxies("ProxyAutoConfigEnable 1\nProxyAutoConfigURLString http://wpad.example.com/wpad.dat\n"))
+
+	assert.Nil(t, parseSystemProxies("ExceptionsList *.local\nFTPPassive 1\n"))
+}
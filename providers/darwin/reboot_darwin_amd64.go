@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// softwareUpdateDir is where softwareupdate stages downloaded updates that
+// are installed on the next restart.
+const softwareUpdateDir = "/Library/Updates"
+
+// RebootRequired reports the updates that softwareupdate has staged for
+// installation during the next restart.
+func (h *host) RebootRequired() (*types.RebootRequiredInfo, error) {
+	info := &types.RebootRequiredInfo{}
+
+	entries, err := ioutil.ReadDir(softwareUpdateDir)
+	if err != nil {
+		return info, nil
+	}
+	for _, e := range entries {
+		// Each staged product has its own directory next to index.plist.
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			info.Packages = append(info.Packages, e.Name())
+		}
+	}
+
+	if len(info.Packages) > 0 {
+		info.Required = true
+		info.Reasons = []string{"updates are staged in " + softwareUpdateDir}
+	}
+	return info, nil
+}
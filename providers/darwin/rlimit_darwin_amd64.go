@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// rlimInfinity is RLIM_INFINITY from sys/resource.h.
+const rlimInfinity = 1<<63 - 1
+
+var rlimitResources = map[string]int{
+	types.RLimitCPU:     syscall.RLIMIT_CPU,
+	types.RLimitFSize:   syscall.RLIMIT_FSIZE,
+	types.RLimitData:    syscall.RLIMIT_DATA,
+	types.RLimitStack:   syscall.RLIMIT_STACK,
+	types.RLimitCore:    syscall.RLIMIT_CORE,
+	types.RLimitAS:      syscall.RLIMIT_AS,
+	types.RLimitMemLock: 6,
+	types.RLimitNProc:   7,
+	types.RLimitNoFile:  syscall.RLIMIT_NOFILE,
+}
+
+// RLimits returns the resource limits of the current process.
+func (p *process) RLimits() (map[string]types.RLimit, error) {
+	if err := p.checkSelf(); err != nil {
+		return nil, err
+	}
+	return shared.RLimits(rlimitResources, rlimInfinity)
+}
+
+// RaiseOpenFileLimit raises the soft limit on open files of the current
+// process to its hard limit. setrlimit(2) rejects values above
+// kern.maxfilesperproc so the limit is capped to that.
+func (p *process) RaiseOpenFileLimit() (types.RLimit, error) {
+	if err := p.checkSelf(); err != nil {
+		return types.RLimit{}, err
+	}
+	max, err := syscall.SysctlUint32("kern.maxfilesperproc")
+	if err != nil {
+		return types.RLimit{}, errors.Wrap(err, "failed to read kern.maxfilesperproc")
+	}
+	return shared.RaiseOpenFileLimit(uint64(max), rlimInfinity)
+}
+
+func (p *process) checkSelf() error {
+	if p.pid != os.Getpid() {
+		return errors.Errorf("resource limits are only available for the current process (pid %d)", p.pid)
+	}
+	return nil
+}
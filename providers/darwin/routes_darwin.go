@@ -0,0 +1,250 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// Offsets within the rt_msghdr struct.
+	rtMsghdrIndexOffset  = 4
+	rtMsghdrFlagsOffset  = 8
+	rtMsghdrAddrsOffset  = 12
+	rtMsghdrExpireOffset = 48 // rtm_rmx.rmx_expire
+	rtMsghdrSize         = syscall.SizeofRtMsghdr
+
+	// Offsets of the address within the sockaddr_in and sockaddr_in6
+	// structs.
+	sockaddrInet4AddrOffset = 4
+	sockaddrInet6AddrOffset = 8
+
+	// Offset of sdl_data within the sockaddr_dl struct.
+	sockaddrDatalinkDataOffset = 8
+)
+
+// routeMessage is a parsed rt_msghdr message with its socket addresses
+// indexed by RTAX_* constant.
+type routeMessage struct {
+	index  int
+	flags  int32
+	expire int32
+	addrs  [syscall.RTAX_MAX][]byte
+}
+
+func routes() ([]types.RouteInfo, error) {
+	rib, err := syscall.RouteRIB(syscall.NET_RT_DUMP, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump routing table")
+	}
+	return parseRoutes(rib, shared.InterfaceNames()), nil
+}
+
+func neighbors() ([]types.NeighborInfo, error) {
+	rib, err := syscall.RouteRIB(syscall.NET_RT_FLAGS, syscall.RTF_LLINFO)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump neighbor table")
+	}
+	return parseNeighbors(rib, shared.InterfaceNames()), nil
+}
+
+// parseRoutes converts the messages returned by the NET_RT_DUMP sysctl into
+// routes. Link layer entries and host routes cloned from another route are
+// skipped because they are reported by netstat as neighbors and cache
+// entries rather than as routes.
+func parseRoutes(b []byte, names map[int]string) []types.RouteInfo {
+	var routes []types.RouteInfo
+	for _, msg := range parseRouteMessages(b) {
+		if msg.flags&syscall.RTF_UP == 0 || msg.flags&(syscall.RTF_LLINFO|syscall.RTF_WASCLONED) != 0 {
+			continue
+		}
+
+		family, dst := sockaddrIP(msg.addrs[syscall.RTAX_DST])
+		if dst == nil {
+			continue
+		}
+
+		route := types.RouteInfo{
+			Family:      family,
+			Destination: dst,
+			Interface:   names[msg.index],
+		}
+		if msg.flags&syscall.RTF_GATEWAY != 0 {
+			_, route.Gateway = sockaddrIP(msg.addrs[syscall.RTAX_GATEWAY])
+		}
+		switch {
+		case msg.flags&syscall.RTF_HOST != 0:
+			route.PrefixLength = len(dst) * 8
+		case msg.addrs[syscall.RTAX_NETMASK] != nil:
+			route.PrefixLength = netmaskLength(msg.addrs[syscall.RTAX_NETMASK], family)
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// parseNeighbors converts the messages returned by the NET_RT_FLAGS sysctl for
+// RTF_LLINFO into neighbors. The gateway of these entries is the link layer
+// address of the neighbor.
+func parseNeighbors(b []byte, names map[int]string) []types.NeighborInfo {
+	var neighbors []types.NeighborInfo
+	for _, msg := range parseRouteMessages(b) {
+		if msg.flags&syscall.RTF_LLINFO == 0 {
+			continue
+		}
+
+		family, ip := sockaddrIP(msg.addrs[syscall.RTAX_DST])
+		if ip == nil || ip.IsMulticast() {
+			continue
+		}
+
+		neighbor := types.NeighborInfo{
+			Family:    family,
+			IP:        ip,
+			MAC:       sockaddrMAC(msg.addrs[syscall.RTAX_GATEWAY]),
+			Interface: names[msg.index],
+		}
+		switch {
+		case neighbor.MAC == "":
+			neighbor.State = types.NeighborStateIncomplete
+		case msg.expire == 0:
+			neighbor.State = types.NeighborStatePermanent
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors
+}
+
+// parseRouteMessages splits b into rt_msghdr messages and their socket
+// addresses. Messages of other types are skipped.
+func parseRouteMessages(b []byte) []routeMessage {
+	var msgs []routeMessage
+	for len(b) >= 4 {
+		msgLen := int(binary.LittleEndian.Uint16(b))
+		if msgLen < 4 || msgLen > len(b) {
+			break
+		}
+		data := b[:msgLen]
+		b = b[msgLen:]
+
+		if data[3] != syscall.RTM_GET || len(data) < rtMsghdrSize {
+			continue
+		}
+
+		msg := routeMessage{
+			index:  int(binary.LittleEndian.Uint16(data[rtMsghdrIndexOffset:])),
+			flags:  int32(binary.LittleEndian.Uint32(data[rtMsghdrFlagsOffset:])),
+			expire: int32(binary.LittleEndian.Uint32(data[rtMsghdrExpireOffset:])),
+		}
+		addrs := binary.LittleEndian.Uint32(data[rtMsghdrAddrsOffset:])
+
+		sa := data[rtMsghdrSize:]
+		for i := 0; i < syscall.RTAX_MAX && len(sa) > 0; i++ {
+			if addrs&(1<<uint(i)) == 0 {
+				continue
+			}
+			l := int(sa[0])
+			if l > len(sa) {
+				break
+			}
+			msg.addrs[i] = sa[:l]
+
+			// Socket addresses are aligned to 32 bits and an empty one still
+			// occupies four bytes.
+			next := (l + 3) &^ 3
+			if l == 0 {
+				next = 4
+			}
+			if next > len(sa) {
+				break
+			}
+			sa = sa[next:]
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// sockaddrIP returns the address family and IP of a sockaddr_in or
+// sockaddr_in6. The KAME scope ID embedded in link-local IPv6 addresses is
+// removed.
+func sockaddrIP(sa []byte) (string, net.IP) {
+	if len(sa) < 2 {
+		return "", nil
+	}
+	switch sa[1] {
+	case syscall.AF_INET:
+		if len(sa) < sockaddrInet4AddrOffset+net.IPv4len {
+			return "", nil
+		}
+		ip := make(net.IP, net.IPv4len)
+		copy(ip, sa[sockaddrInet4AddrOffset:])
+		return types.FamilyIPv4, ip
+	case syscall.AF_INET6:
+		if len(sa) < sockaddrInet6AddrOffset+net.IPv6len {
+			return "", nil
+		}
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, sa[sockaddrInet6AddrOffset:])
+		if ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+			ip[2], ip[3] = 0, 0
+		}
+		return types.FamilyIPv6, ip
+	default:
+		return "", nil
+	}
+}
+
+// sockaddrMAC returns the link layer address of a sockaddr_dl.
+func sockaddrMAC(sa []byte) string {
+	if len(sa) < sockaddrDatalinkDataOffset || sa[1] != syscall.AF_LINK {
+		return ""
+	}
+	nameLen, addrLen := int(sa[5]), int(sa[6])
+	start := sockaddrDatalinkDataOffset + nameLen
+	if addrLen == 0 || start+addrLen > len(sa) {
+		return ""
+	}
+	return net.HardwareAddr(sa[start : start+addrLen]).String()
+}
+
+// netmaskLength returns the prefix length of a netmask. The kernel truncates
+// netmasks after their last non-zero byte and may leave the family unset, so
+// the family of the destination determines where the mask starts.
+func netmaskLength(sa []byte, family string) int {
+	offset, size := sockaddrInet4AddrOffset, net.IPv4len
+	if family == types.FamilyIPv6 {
+		offset, size = sockaddrInet6AddrOffset, net.IPv6len
+	}
+
+	mask := make(net.IPMask, size)
+	if len(sa) > offset {
+		copy(mask, sa[offset:])
+	}
+	ones, _ := mask.Size()
+	return ones
+}
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin
+
+package darwin
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func sockaddrInet4(ip string) []byte {
+	sa := make([]byte, 16)
+	sa[0], sa[1] = byte(len(sa)), syscall.AF_INET
+	copy(sa[sockaddrInet4AddrOffset:], net.ParseIP(ip).To4())
+	return sa
+}
+
+func sockaddrInet6(ip string) []byte {
+	sa := make([]byte, 28)
+	sa[0], sa[1] = byte(len(sa)), syscall.AF_INET6
+	copy(sa[sockaddrInet6AddrOffset:], net.ParseIP(ip))
+	return sa
+}
+
+func sockaddrDatalink(index uint16, mac []byte) []byte {
+	sa := make([]byte, 20)
+	sa[0], sa[1] = byte(len(sa)), syscall.AF_LINK
+	binary.LittleEndian.PutUint16(sa[2:], index)
+	sa[6] = byte(len(mac))
+	copy(sa[sockaddrDatalinkDataOffset:], mac)
+	return sa
+}
+
+func rtMsghdr(index uint16, flags int32, addrs map[int][]byte) []byte {
+	msg := make([]byte, rtMsghdrSize)
+	msg[3] = syscall.RTM_GET
+	binary.LittleEndian.PutUint16(msg[rtMsghdrIndexOffset:], index)
+	binary.LittleEndian.PutUint32(msg[rtMsghdrFlagsOffset:], uint32(flags))
+
+	var present uint32
+	for i := 0; i < syscall.RTAX_MAX; i++ {
+		sa, found := addrs[i]
+		if !found {
+			continue
+		}
+		present |= 1 << uint(i)
+		if len(sa) == 0 {
+			sa = make([]byte, 4)
+		}
+		msg = append(msg, sa...)
+		for len(msg)%4 != 0 {
+			msg = append(msg, 0)
+		}
+	}
+	binary.LittleEndian.PutUint32(msg[rtMsghdrAddrsOffset:], present)
+	binary.LittleEndian.PutUint16(msg, uint16(len(msg)))
+	return msg
+}
+
+func TestParseRoutes(t *testing.T) {
+	var rib []byte
+	// default via 192.168.1.1 on en0.
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_GATEWAY|syscall.RTF_STATIC, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("0.0.0.0"),
+		syscall.RTAX_GATEWAY: sockaddrInet4("192.168.1.1"),
+		syscall.RTAX_NETMASK: {},
+	})...)
+	// 192.168.1.0/24 on en0 with a truncated netmask.
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_CLONING, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("192.168.1.0"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, nil),
+		syscall.RTAX_NETMASK: {7, 0, 0, 0, 255, 255, 255},
+	})...)
+	// fe80::%en0/64 with the scope ID embedded in the address.
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet6("fe80:4::"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, nil),
+		syscall.RTAX_NETMASK: append([]byte{16, 0, 0, 0, 0, 0, 0, 0}, 255, 255, 255, 255, 255, 255, 255, 255),
+	})...)
+	// ARP entries and cloned routes are skipped.
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_HOST|syscall.RTF_LLINFO, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("192.168.1.1"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, []byte{1, 2, 3, 4, 5, 6}),
+	})...)
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_HOST|syscall.RTF_WASCLONED, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("192.168.1.20"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, nil),
+	})...)
+
+	routes := parseRoutes(rib, map[int]string{4: "en0"})
+	assert.Equal(t, []types.RouteInfo{
+		{
+			Family:      types.FamilyIPv4,
+			Destination: net.IP{0, 0, 0, 0},
+			Gateway:     net.IP{192, 168, 1, 1},
+			Interface:   "en0",
+		},
+		{
+			Family:       types.FamilyIPv4,
+			Destination:  net.IP{192, 168, 1, 0},
+			PrefixLength: 24,
+			Interface:    "en0",
+		},
+		{
+			Family:       types.FamilyIPv6,
+			Destination:  net.ParseIP("fe80::"),
+			PrefixLength: 64,
+			Interface:    "en0",
+		},
+	}, routes)
+}
+
+func TestParseNeighbors(t *testing.T) {
+	var rib []byte
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_HOST|syscall.RTF_LLINFO, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("192.168.1.1"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, []byte{0xa0, 0xb1, 0xc2, 0xd3, 0xe4, 0xf5}),
+	})...)
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_HOST|syscall.RTF_LLINFO, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("192.168.1.7"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, nil),
+	})...)
+	rib = append(rib, rtMsghdr(4, syscall.RTF_UP|syscall.RTF_HOST|syscall.RTF_LLINFO, map[int][]byte{
+		syscall.RTAX_DST:     sockaddrInet4("224.0.0.251"),
+		syscall.RTAX_GATEWAY: sockaddrDatalink(4, []byte{1, 0, 0x5e, 0, 0, 0xfb}),
+	})...)
+
+	neighbors := parseNeighbors(rib, map[int]string{4: "en0"})
+	assert.Equal(t, []types.NeighborInfo{
+		{
+			Family:    types.FamilyIPv4,
+			IP:        net.IP{192, 168, 1, 1},
+			MAC:       "a0:b1:c2:d3:e4:f5",
+			Interface: "en0",
+			State:     types.NeighborStatePermanent,
+		},
+		{
+			Family:    types.FamilyIPv4,
+			IP:        net.IP{192, 168, 1, 7},
+			Interface: "en0",
+			State:     types.NeighborStateIncomplete,
+		},
+	}, neighbors)
+}
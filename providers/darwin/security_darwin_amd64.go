@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <sys/mount.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// csr_get_active_config is exported by libsystem_kernel but its header
+// (sys/csr.h) is not part of the SDK.
+extern int csr_get_active_config(uint32_t *config);
+
+// gatekeeperEnabled returns 1 if assessments are enabled, 0 if they are
+// disabled, and -1 if unknown. The setting is stored in
+// /var/db/SystemPolicy-prefs.plist and assessments are enabled when the file
+// does not exist.
+static int
+gatekeeperEnabled()
+{
+	CFStringRef key = CFSTR("enabled");
+	CFStringRef app = CFSTR("/var/db/SystemPolicy-prefs");
+	CFPropertyListRef value = CFPreferencesCopyValue(key, app, kCFPreferencesAnyUser, kCFPreferencesCurrentHost);
+	if (value == NULL) {
+		return 1;
+	}
+
+	int rtn = -1;
+	if (CFGetTypeID(value) == CFStringGetTypeID()) {
+		rtn = CFStringCompare((CFStringRef)value, CFSTR("yes"), kCFCompareCaseInsensitive) == kCFCompareEqualTo;
+	}
+	CFRelease(value);
+	return rtn;
+}
+
+// volumeEncrypted returns 1 if the media backing the volume mounted at path
+// is encrypted, 0 if it is not, and -1 if unknown.
+static int
+volumeEncrypted(const char *path)
+{
+	struct statfs fs;
+	if (statfs(path, &fs) != 0 || strncmp(fs.f_mntfromname, "/dev/", 5) != 0) {
+		return -1;
+	}
+
+	io_service_t media = IOServiceGetMatchingService(kIOMasterPortDefault,
+		IOBSDNameMatching(kIOMasterPortDefault, 0, fs.f_mntfromname + 5));
+	if (media == MACH_PORT_NULL) {
+		return -1;
+	}
+
+	int rtn = -1;
+	CFStringRef keys[] = {CFSTR("Encrypted"), CFSTR("CoreStorage Encrypted")};
+	for (int i = 0; i < 2 && rtn == -1; i++) {
+		CFTypeRef value = IORegistryEntryCreateCFProperty(media, keys[i], kCFAllocatorDefault, 0);
+		if (value == NULL) {
+			continue;
+		}
+		if (CFGetTypeID(value) == CFBooleanGetTypeID()) {
+			rtn = CFBooleanGetValue((CFBooleanRef)value);
+		}
+		CFRelease(value);
+	}
+	IOObjectRelease(media);
+	return rtn;
+}
+
+// secureEnclavePresent returns 1 if the Secure Enclave Processor (T2 chip or
+// Apple silicon) is registered in the I/O Registry.
+static int
+secureEnclavePresent()
+{
+	io_service_t sep = IOServiceGetMatchingService(kIOMasterPortDefault, IOServiceMatching("AppleSEPManager"));
+	if (sep == MACH_PORT_NULL) {
+		return 0;
+	}
+	IOObjectRelease(sep);
+	return 1;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// csrAllowUnrestrictedFS is the SIP flag that disables the filesystem
+// protection. csrutil reports SIP as disabled when it is set.
+const csrAllowUnrestrictedFS = 1 << 1
+
+// SecurityInfo reports the state of System Integrity Protection, Gatekeeper,
+// and FileVault.
+func (h *host) SecurityInfo() (*types.SecurityInfo, error) {
+	info := &types.SecurityInfo{}
+
+	var config C.uint32_t
+	if C.csr_get_active_config(&config) == 0 {
+		v := config&csrAllowUnrestrictedFS == 0
+		info.SIP = &v
+	}
+
+	info.Gatekeeper = intToBool(C.gatekeeperEnabled())
+
+	// Since macOS 10.15 the user data is on a separate volume.
+	for _, path := range []string{"/System/Volumes/Data", "/"} {
+		if info.FileVault = volumeEncrypted(path); info.FileVault != nil {
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// volumeEncrypted returns whether the volume mounted at path is encrypted,
+// or nil if it is not backed by local media.
+func volumeEncrypted(path string) *bool {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return intToBool(C.volumeEncrypted(cPath))
+}
+
+// intToBool converts the 1, 0, or -1 (unknown) return values of the C helpers.
+func intToBool(v C.int) *bool {
+	if v < 0 {
+		return nil
+	}
+	b := v == 1
+	return &b
+}
+
+// FirewallInfo reports the state of the Application Firewall.
+func (h *host) FirewallInfo() (*types.FirewallInfo, error) {
+	return firewallInfo()
+}
+
+// TPMInfo reports whether the Mac has a Secure Enclave. Macs do not have a
+// TPM.
+func (h *host) TPMInfo() (*types.TPMInfo, error) {
+	return &types.TPMInfo{SecureEnclave: intToBool(C.secureEnclavePresent())}, nil
+}
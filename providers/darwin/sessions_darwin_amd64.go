@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #include <utmpx.h>
+import "C"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// utmpxLock serializes access to the non-reentrant getutxent iterator.
+var utmpxLock sync.Mutex
+
+// Sessions returns the USER_PROCESS entries from the utmpx database.
+func (h *host) Sessions() ([]types.SessionInfo, error) {
+	utmpxLock.Lock()
+	defer utmpxLock.Unlock()
+
+	C.setutxent()
+	defer C.endutxent()
+
+	var sessions []types.SessionInfo
+	for ut := C.getutxent(); ut != nil; ut = C.getutxent() {
+		if ut.ut_type != C.USER_PROCESS {
+			continue
+		}
+
+		sessions = append(sessions, types.SessionInfo{
+			User:       C.GoStringN(&ut.ut_user[0], C.int(strnlen(ut.ut_user[:]))),
+			Terminal:   C.GoStringN(&ut.ut_line[0], C.int(strnlen(ut.ut_line[:]))),
+			RemoteHost: C.GoStringN(&ut.ut_host[0], C.int(strnlen(ut.ut_host[:]))),
+			LoginTime:  time.Unix(int64(ut.ut_tv.tv_sec), int64(ut.ut_tv.tv_usec)*int64(time.Microsecond)),
+			PID:        int(ut.ut_pid),
+		})
+	}
+	return sessions, nil
+}
+
+// strnlen returns the length of the string in the fixed size char array b
+// which is not NUL terminated when it is full.
+func strnlen(b []C.char) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+/*
+#cgo LDFLAGS: -framework Security -framework CoreFoundation
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <Security/Security.h>
+
+typedef struct {
+	int  status;       // 0 trusted, 1 untrusted, 2 unsigned.
+	int  notarized;
+	char signer[256];
+	char teamID[64];
+	char signingID[256];
+	char entitlements[4096]; // Newline separated entitlement keys.
+} codeSignature;
+
+static void
+cfString(CFTypeRef value, char *buf, size_t size)
+{
+	if (value != NULL && CFGetTypeID(value) == CFStringGetTypeID()) {
+		CFStringGetCString((CFStringRef)value, buf, size, kCFStringEncodingUTF8);
+	}
+}
+
+static void
+appendEntitlement(const void *key, const void *value, void *ctx)
+{
+	codeSignature *sig = ctx;
+	size_t len = strlen(sig->entitlements);
+	if (len > 0 && len < sizeof(sig->entitlements) - 1) {
+		sig->entitlements[len++] = '\n';
+	}
+	cfString(key, sig->entitlements + len, sizeof(sig->entitlements) - len);
+}
+
+// checkSignature verifies the code signature of the file at path. It returns
+// the OSStatus of the first call that failed.
+static OSStatus
+checkSignature(const char *path, codeSignature *sig)
+{
+	memset(sig, 0, sizeof(*sig));
+
+	CFURLRef url = CFURLCreateFromFileSystemRepresentation(kCFAllocatorDefault,
+		(const UInt8 *)path, strlen(path), false);
+	if (url == NULL) {
+		return errSecParam;
+	}
+	SecStaticCodeRef code = NULL;
+	OSStatus rtn = SecStaticCodeCreateWithPath(url, kSecCSDefaultFlags, &code);
+	CFRelease(url);
+	if (rtn != errSecSuccess) {
+		return rtn;
+	}
+
+	rtn = SecStaticCodeCheckValidity(code, kSecCSDefaultFlags, NULL);
+	if (rtn == errSecCSUnsigned) {
+		sig->status = 2;
+		CFRelease(code);
+		return errSecSuccess;
+	}
+	sig->status = rtn == errSecSuccess ? 0 : 1;
+
+	SecRequirementRef notarized = NULL;
+	if (SecRequirementCreateWithString(CFSTR("notarized"), kSecCSDefaultFlags, &notarized) == errSecSuccess) {
+		sig->notarized = SecStaticCodeCheckValidity(code, kSecCSDefaultFlags, notarized) == errSecSuccess;
+		CFRelease(notarized);
+	}
+
+	CFDictionaryRef info = NULL;
+	rtn = SecCodeCopySigningInformation(code, kSecCSSigningInformation, &info);
+	CFRelease(code);
+	if (rtn != errSecSuccess) {
+		return rtn;
+	}
+
+	cfString(CFDictionaryGetValue(info, kSecCodeInfoTeamIdentifier), sig->teamID, sizeof(sig->teamID));
+	cfString(CFDictionaryGetValue(info, kSecCodeInfoIdentifier), sig->signingID, sizeof(sig->signingID));
+
+	CFArrayRef certs = CFDictionaryGetValue(info, kSecCodeInfoCertificates);
+	if (certs != NULL && CFArrayGetCount(certs) > 0) {
+		SecCertificateRef leaf = (SecCertificateRef)CFArrayGetValueAtIndex(certs, 0);
+		CFStringRef summary = SecCertificateCopySubjectSummary(leaf);
+		if (summary != NULL) {
+			cfString(summary, sig->signer, sizeof(sig->signer));
+			CFRelease(summary);
+		}
+	}
+
+	CFDictionaryRef entitlements = CFDictionaryGetValue(info, kSecCodeInfoEntitlementsDict);
+	if (entitlements != NULL && CFGetTypeID(entitlements) == CFDictionaryGetTypeID()) {
+		CFDictionaryApplyFunction(entitlements, appendEntitlement, sig);
+	}
+
+	CFRelease(info);
+	return errSecSuccess;
+}
+*/
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// FileSignature verifies the code signature of the file using the Security
+// framework.
+func (s darwinSystem) FileSignature(path string) (*types.SignatureInfo, error) {
+	return codeSignature(path)
+}
+
+// SignatureInfo verifies the code signature of the executable of the process.
+func (p *process) SignatureInfo() (*types.SignatureInfo, error) {
+	if p.exe == "" {
+		if _, err := p.Info(); err != nil && (p.exe == "" || !types.IsPartialInfo(err)) {
+			return nil, err
+		}
+	}
+	return codeSignature(p.exe)
+}
+
+func codeSignature(path string) (*types.SignatureInfo, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	var sig C.codeSignature
+	if rtn := C.checkSignature(cpath, &sig); rtn != C.errSecSuccess {
+		return nil, errors.Errorf("failed to check the code signature of %v (OSStatus %d)", path, int(rtn))
+	}
+
+	info := &types.SignatureInfo{
+		Signer:    C.GoString(&sig.signer[0]),
+		TeamID:    C.GoString(&sig.teamID[0]),
+		SigningID: C.GoString(&sig.signingID[0]),
+		Notarized: sig.notarized != 0,
+	}
+	switch sig.status {
+	case 0:
+		info.Status = types.SignatureStatusTrusted
+	case 1:
+		info.Status = types.SignatureStatusUntrusted
+	default:
+		info.Status = types.SignatureStatusUnsigned
+	}
+	if entitlements := C.GoString(&sig.entitlements[0]); entitlements != "" {
+		info.Entitlements = strings.Split(entitlements, "\n")
+	}
+	return info, nil
+}
@@ -0,0 +1,142 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #cgo LDFLAGS:-lproc
+// #include <libproc.h>
+// #include <netinet/in.h>
+import "C"
+
+import (
+	"net"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// tcpStates maps the TSI_S_* states of struct tcp_sockinfo to their names.
+var tcpStates = map[C.int]string{
+	C.TSI_S_CLOSED:       types.TCPStateClose,
+	C.TSI_S_LISTEN:       types.TCPStateListen,
+	C.TSI_S_SYN_SENT:     types.TCPStateSynSent,
+	C.TSI_S_SYN_RECEIVED: types.TCPStateSynRecv,
+	C.TSI_S_ESTABLISHED:  types.TCPStateEstablished,
+	C.TSI_S__CLOSE_WAIT:  types.TCPStateCloseWait,
+	C.TSI_S_FIN_WAIT_1:   types.TCPStateFinWait1,
+	C.TSI_S_CLOSING:      types.TCPStateClosing,
+	C.TSI_S_LAST_ACK:     types.TCPStateLastAck,
+	C.TSI_S_FIN_WAIT_2:   types.TCPStateFinWait2,
+	C.TSI_S_TIME_WAIT:    types.TCPStateTimeWait,
+}
+
+// Connections returns the TCP and UDP sockets owned by the process. They are
+// read with proc_pidfdinfo(PROC_PIDFDSOCKETINFO) for each socket descriptor.
+// Reading the sockets of another user's process requires root.
+func (p *process) Connections() ([]types.NetworkConnection, error) {
+	// Calling with a nil buffer returns the size needed to hold the list.
+	n := C.proc_pidinfo(C.int(p.pid), C.PROC_PIDLISTFDS, 0, nil, 0)
+	if n <= 0 {
+		return nil, errors.Errorf("proc_pidinfo with PROC_PIDLISTFDS returned %v", n)
+	}
+
+	var fdInfo C.struct_proc_fdinfo
+	fds := make([]C.struct_proc_fdinfo, int(n)/int(unsafe.Sizeof(fdInfo)))
+	n = C.proc_pidinfo(C.int(p.pid), C.PROC_PIDLISTFDS, 0, unsafe.Pointer(&fds[0]), n)
+	if n <= 0 {
+		return nil, errors.Errorf("proc_pidinfo with PROC_PIDLISTFDS returned %v", n)
+	}
+	fds = fds[:int(n)/int(unsafe.Sizeof(fdInfo))]
+
+	var conns []types.NetworkConnection
+	for _, fd := range fds {
+		if fd.proc_fdtype != C.PROX_FDTYPE_SOCKET {
+			continue
+		}
+
+		var socket C.struct_socket_fdinfo
+		size := C.int(unsafe.Sizeof(socket))
+		if C.proc_pidfdinfo(C.int(p.pid), C.int(fd.proc_fd), C.PROC_PIDFDSOCKETINFO, unsafe.Pointer(&socket), size) != size {
+			// The descriptor was closed.
+			continue
+		}
+
+		if conn, ok := socketConnection(&socket.psi); ok {
+			conn.PID = p.pid
+			conns = append(conns, conn)
+		}
+	}
+	return conns, nil
+}
+
+// socketConnection converts a TCP or UDP socket_info. ok is false for other
+// kinds of sockets.
+func socketConnection(si *C.struct_socket_info) (conn types.NetworkConnection, ok bool) {
+	var in *C.struct_in_sockinfo
+	switch {
+	case si.soi_kind == C.SOCKINFO_TCP:
+		tcp := (*C.struct_tcp_sockinfo)(unsafe.Pointer(&si.soi_proto))
+		in = &tcp.tcpsi_ini
+		conn.Type = types.ProtocolTCP
+		conn.State = tcpStates[tcp.tcpsi_state]
+	case si.soi_kind == C.SOCKINFO_IN && si.soi_protocol == C.IPPROTO_UDP:
+		in = (*C.struct_in_sockinfo)(unsafe.Pointer(&si.soi_proto))
+		conn.Type = types.ProtocolUDP
+	default:
+		return conn, false
+	}
+
+	// The addresses are a union of in4in6_addr (the IPv4 address is in the
+	// last four bytes) and in6_addr. IPv6 sockets that are bound to an IPv4
+	// address have INI_IPV4 set.
+	local := (*[16]byte)(unsafe.Pointer(&in.insi_laddr))
+	remote := (*[16]byte)(unsafe.Pointer(&in.insi_faddr))
+	if in.insi_vflag&C.INI_IPV4 != 0 {
+		conn.Family = types.FamilyIPv4
+		conn.LocalIP = copyIP(local[12:])
+		conn.RemoteIP = remoteIP(remote[12:])
+	} else {
+		conn.Family = types.FamilyIPv6
+		conn.LocalIP = copyIP(local[:])
+		conn.RemoteIP = remoteIP(remote[:])
+	}
+	conn.LocalPort = port(in.insi_lport)
+	conn.RemotePort = port(in.insi_fport)
+	return conn, true
+}
+
+// port converts a port that is stored in network byte order in an int.
+func port(v C.int) int {
+	p := uint16(v)
+	return int(p>>8 | p<<8)
+}
+
+func copyIP(ip []byte) net.IP {
+	return append(net.IP(nil), ip...)
+}
+
+// remoteIP returns nil for the unspecified address of unconnected sockets.
+func remoteIP(ip []byte) net.IP {
+	if net.IP(ip).IsUnspecified() {
+		return nil
+	}
+	return copyIP(ip)
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.ConnectionEnumerator = (*process)(nil)
+
+func TestConnections(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	addr := l.Addr().(*net.TCPAddr)
+
+	conns, err := (&process{pid: os.Getpid()}).Connections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, conns, types.NetworkConnection{
+		Family:    types.FamilyIPv4,
+		Type:      types.ProtocolTCP,
+		LocalIP:   net.IPv4(127, 0, 0, 1).To4(),
+		LocalPort: addr.Port,
+		State:     types.TCPStateListen,
+		PID:       os.Getpid(),
+	})
+}
@@ -22,8 +22,12 @@ package darwin
 /*
 #cgo LDFLAGS:-lproc
 #include <sys/sysctl.h>
+#include <mach/mach.h>
 #include <mach/mach_time.h>
 #include <mach/mach_host.h>
+#include <mach/mach_init.h>
+#include <mach/processor_info.h>
+#include <mach/vm_map.h>
 #include <unistd.h>
 */
 import "C"
@@ -31,12 +35,15 @@ import "C"
 import (
 	"bytes"
 	"encoding/binary"
-	"fmt"
+	"os"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
 )
 
 // Single-word zero for use when we need a valid pointer to 0 bytes.
@@ -187,6 +194,49 @@ func getHostCPULoadInfo() (*cpuUsage, error) {
 	return &cpu, nil
 }
 
+func (cpu *cpuUsage) times(ticksPerSecond time.Duration) types.CPUTimes {
+	return types.CPUTimes{
+		User:   time.Duration(cpu.User) * time.Second / ticksPerSecond,
+		System: time.Duration(cpu.System) * time.Second / ticksPerSecond,
+		Idle:   time.Duration(cpu.Idle) * time.Second / ticksPerSecond,
+		Nice:   time.Duration(cpu.Nice) * time.Second / ticksPerSecond,
+	}
+}
+
+// getHostCPULoadInfoPerCPU returns the CPU usage of each processor.
+func getHostCPULoadInfoPerCPU() ([]cpuUsage, error) {
+	var count C.natural_t
+	var info C.processor_info_array_t
+	var infoCount C.mach_msg_type_number_t
+	status := C.host_processor_info(C.host_t(C.mach_host_self()),
+		C.PROCESSOR_CPU_LOAD_INFO,
+		&count,
+		&info,
+		&infoCount)
+
+	if status != C.KERN_SUCCESS {
+		return nil, errors.Errorf("host_processor_info returned status %d", status)
+	}
+	defer C.vm_deallocate(C.mach_task_self_,
+		C.vm_address_t(uintptr(unsafe.Pointer(info))),
+		C.vm_size_t(uintptr(infoCount)*unsafe.Sizeof(C.integer_t(0))))
+
+	// The array contains CPU_STATE_MAX tick counters for each processor.
+	ticks := (*[1 << 20]C.integer_t)(unsafe.Pointer(info))[:infoCount:infoCount]
+
+	cpus := make([]cpuUsage, count)
+	for i := range cpus {
+		t := ticks[i*C.CPU_STATE_MAX:]
+		cpus[i] = cpuUsage{
+			User:   uint32(t[cpuStateUser]),
+			System: uint32(t[cpuStateSystem]),
+			Idle:   uint32(t[cpuStateIdle]),
+			Nice:   uint32(t[cpuStateNice]),
+		}
+	}
+	return cpus, nil
+}
+
 // getClockTicks returns the number of click ticks in one jiffie.
 func getClockTicks() int {
 	return int(C.sysconf(C._SC_CLK_TCK))
@@ -203,7 +253,7 @@ func getHostVMInfo64() (*vmStatistics64Data, error) {
 		&count)
 
 	if status != C.KERN_SUCCESS {
-		return nil, fmt.Errorf("host_statistics64 returned status %d", status)
+		return nil, errors.Errorf("host_statistics64 returned status %d", status)
 	}
 
 	return &vmStat, nil
@@ -238,3 +288,24 @@ func getSwapUsage() (*swapUsage, error) {
 	}
 	return &swap, nil
 }
+
+// getTaskVMCompressed returns the number of bytes of the process that are
+// held by the memory compressor or swapped out from task_info(TASK_VM_INFO).
+// task_for_pid only succeeds for the current process unless running as root
+// so false is returned when the task cannot be accessed.
+func getTaskVMCompressed(pid int) (uint64, bool) {
+	task := C.mach_port_name_t(C.mach_task_self_)
+	if pid != os.Getpid() {
+		if C.task_for_pid(C.mach_port_name_t(C.mach_task_self_), C.int(pid), &task) != C.KERN_SUCCESS {
+			return 0, false
+		}
+		defer C.mach_port_deallocate(C.ipc_space_t(C.mach_task_self_), task)
+	}
+
+	var info C.task_vm_info_data_t
+	count := C.mach_msg_type_number_t(unsafe.Sizeof(info) / unsafe.Sizeof(C.natural_t(0)))
+	if C.task_info(C.task_name_t(task), C.TASK_VM_INFO, C.task_info_t(unsafe.Pointer(&info)), &count) != C.KERN_SUCCESS {
+		return 0, false
+	}
+	return uint64(info.compressed), true
+}
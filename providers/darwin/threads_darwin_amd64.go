@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #cgo LDFLAGS:-lproc
+// #include <libproc.h>
+// #include <mach/thread_info.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Threads returns the threads of the process using proc_pidinfo. The TID is
+// the thread handle reported by PROC_PIDLISTTHREADS. Reading the threads of
+// another user's process requires root.
+func (p *process) Threads() ([]types.ThreadInfo, error) {
+	var task procTaskAllInfo
+	if err := getProcTaskAllInfo(p.pid, &task); err != nil {
+		return nil, err
+	}
+
+	// Leave room for threads started after the count was read.
+	handles := make([]uint64, task.Ptinfo.Threadnum+16)
+	n := C.proc_pidinfo(C.int(p.pid), C.PROC_PIDLISTTHREADS, 0,
+		unsafe.Pointer(&handles[0]), C.int(len(handles)*8))
+	if n <= 0 {
+		return nil, errors.Errorf("proc_pidinfo with PROC_PIDLISTTHREADS returned %v", n)
+	}
+	handles = handles[:int(n)/8]
+
+	threads := make([]types.ThreadInfo, 0, len(handles))
+	for _, handle := range handles {
+		var info C.struct_proc_threadinfo
+		size := C.int(unsafe.Sizeof(info))
+		if C.proc_pidinfo(C.int(p.pid), C.PROC_PIDTHREADINFO, C.uint64_t(handle), unsafe.Pointer(&info), size) != size {
+			// The thread exited.
+			continue
+		}
+
+		threads = append(threads, types.ThreadInfo{
+			TID:   int(handle),
+			Name:  C.GoString(&info.pth_name[0]),
+			State: threadRunState(int(info.pth_run_state)),
+			CPUTime: types.CPUTimes{
+				User:   time.Duration(info.pth_user_time),
+				System: time.Duration(info.pth_system_time),
+			},
+		})
+	}
+	return threads, nil
+}
+
+// threadRunState maps a Mach TH_STATE value to one of the ProcessState
+// constants.
+func threadRunState(state int) string {
+	switch state {
+	case C.TH_STATE_RUNNING:
+		return types.ProcessStateRunning
+	case C.TH_STATE_STOPPED, C.TH_STATE_HALTED:
+		return types.ProcessStateStopped
+	case C.TH_STATE_WAITING:
+		return types.ProcessStateSleeping
+	case C.TH_STATE_UNINTERRUPTIBLE:
+		return types.ProcessStateWaiting
+	default:
+		return types.ProcessStateUnknown
+	}
+}
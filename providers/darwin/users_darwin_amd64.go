@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+// #include <grp.h>
+// #include <pwd.h>
+// #include <stdlib.h>
+// #include <utmpx.h>
+import "C"
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// accountsLock serializes access to the non-reentrant getpwent and getgrent
+// iterators. They query Open Directory so network accounts may be included.
+var accountsLock sync.Mutex
+
+// Users returns the user accounts reported by getpwent. The last login time
+// comes from getlastlogx.
+func (s darwinSystem) Users() ([]types.UserAccount, error) {
+	accountsLock.Lock()
+	defer accountsLock.Unlock()
+
+	C.setpwent()
+	defer C.endpwent()
+
+	var users []types.UserAccount
+	for pw := C.getpwent(); pw != nil; pw = C.getpwent() {
+		user := types.UserAccount{
+			Name:     C.GoString(pw.pw_name),
+			UID:      strconv.FormatUint(uint64(pw.pw_uid), 10),
+			GID:      strconv.FormatUint(uint64(pw.pw_gid), 10),
+			FullName: strings.SplitN(C.GoString(pw.pw_gecos), ",", 2)[0],
+			Home:     C.GoString(pw.pw_dir),
+			Shell:    C.GoString(pw.pw_shell),
+		}
+
+		var ll C.struct_lastlogx
+		if C.getlastlogx(pw.pw_uid, &ll) != nil && ll.ll_tv.tv_sec > 0 {
+			t := time.Unix(int64(ll.ll_tv.tv_sec), int64(ll.ll_tv.tv_usec)*int64(time.Microsecond))
+			user.LastLogin = &t
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Groups returns the groups reported by getgrent.
+func (s darwinSystem) Groups() ([]types.GroupAccount, error) {
+	accountsLock.Lock()
+	defer accountsLock.Unlock()
+
+	C.setgrent()
+	defer C.endgrent()
+
+	var groups []types.GroupAccount
+	for gr := C.getgrent(); gr != nil; gr = C.getgrent() {
+		group := types.GroupAccount{
+			Name: C.GoString(gr.gr_name),
+			GID:  strconv.FormatUint(uint64(gr.gr_gid), 10),
+		}
+
+		// gr_mem is a NULL terminated array of strings.
+		for mem := gr.gr_mem; mem != nil && *mem != nil; mem = (**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(mem)) + unsafe.Sizeof(*mem))) {
+			group.Members = append(group.Members, C.GoString(*mem))
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin,amd64,cgo
+
+package darwin
+
+import (
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// VirtualizationInfo detects the hypervisor from CPUID and the platform
+// strings. kern.hv_vmm_present is set when macOS runs in a virtual machine
+// and kern.hv_support when Hypervisor.framework can run virtual machines.
+func (h *host) VirtualizationInfo() (*types.VirtualizationInfo, error) {
+	hw, _ := h.Hardware()
+	info := shared.Virtualization(hw, "")
+	if info.Role != "" {
+		return info, nil
+	}
+
+	var vmm uint32
+	if err := sysctlByName("kern.hv_vmm_present", &vmm); err == nil && vmm == 1 {
+		info.Hypervisor, info.Role = "unknown", types.VirtualizationRoleGuest
+		return info, nil
+	}
+
+	var support uint32
+	if err := sysctlByName("kern.hv_support", &support); err == nil && support == 1 {
+		info.Hypervisor, info.Role = "Hypervisor.framework", types.VirtualizationRoleHost
+	}
+	return info, nil
+}
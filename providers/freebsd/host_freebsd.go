@@ -0,0 +1,339 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build freebsd,amd64 freebsd,arm64
+
+package freebsd
+
+import (
+	"encoding/binary"
+	"os"
+	"time"
+	"unsafe"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func init() {
+	registry.Register(freebsdSystem{})
+}
+
+type freebsdSystem struct {
+	envFilter types.EnvFilter // Applied to the environment of the processes.
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s freebsdSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+func (s freebsdSystem) Host() (types.Host, error) {
+	return newHost()
+}
+
+type host struct {
+	info types.HostInfo
+}
+
+func (h *host) Info() types.HostInfo {
+	return h.info
+}
+
+// Uptime returns the time since boot.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock.Uptime()
+}
+
+// loadAvg is struct loadavg. The fscale field is a C long.
+type loadAvg struct {
+	Ldavg  [3]uint32
+	Fscale int
+}
+
+// LoadAverage returns the load averages from vm.loadavg.
+func (h *host) LoadAverage() (*types.LoadAverageInfo, error) {
+	data, err := sysctlByName("vm.loadavg")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vm.loadavg")
+	}
+	if len(data) < int(unsafe.Sizeof(loadAvg{})) {
+		return nil, errors.New("unexpected vm.loadavg size")
+	}
+
+	la := (*loadAvg)(unsafe.Pointer(&data[0]))
+	return shared.FixedPointLoadAverage(la.Ldavg, int64(la.Fscale)), nil
+}
+
+func (h *host) CPUTime() (types.CPUTimes, error) {
+	times, err := cpuTimes("kern.cp_time")
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+	if len(times) != 1 {
+		return types.CPUTimes{}, errors.Errorf("unexpected kern.cp_time length")
+	}
+	return times[0], nil
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	return cpuTimes("kern.cp_times")
+}
+
+// cpuTimes reads the CPUSTATES tick counters (user, nice, system, interrupt,
+// idle) from kern.cp_time or kern.cp_times.
+func cpuTimes(name string) ([]types.CPUTimes, error) {
+	const cpuStates = 5
+
+	data, err := sysctlByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%(cpuStates*8) != 0 {
+		return nil, errors.Errorf("unexpected %v size %d", name, len(data))
+	}
+
+	hz, err := statHz()
+	if err != nil {
+		return nil, err
+	}
+
+	ticks := func(b []byte, i int) time.Duration {
+		return time.Duration(binary.LittleEndian.Uint64(b[i*8:])) * time.Second / hz
+	}
+
+	var times []types.CPUTimes
+	for ; len(data) > 0; data = data[cpuStates*8:] {
+		times = append(times, types.CPUTimes{
+			User:   ticks(data, 0),
+			Nice:   ticks(data, 1),
+			System: ticks(data, 2),
+			IRQ:    ticks(data, 3),
+			Idle:   ticks(data, 4),
+		})
+	}
+	return times, nil
+}
+
+// statHz returns the frequency of the statistics clock that is used for the
+// CPU tick counters. It is the stathz field of struct clockinfo.
+func statHz() (time.Duration, error) {
+	data, err := sysctlByName("kern.clockrate")
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 16 {
+		return 0, errors.New("unexpected kern.clockrate size")
+	}
+
+	hz := binary.LittleEndian.Uint32(data[12:])
+	if hz == 0 {
+		// stathz is zero when it is the same as hz.
+		hz = binary.LittleEndian.Uint32(data[0:])
+	}
+	return time.Duration(hz), nil
+}
+
+func (h *host) Memory() (*types.HostMemoryInfo, error) {
+	var mem types.HostMemoryInfo
+
+	total, err := sysctlUint64("hw.physmem")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get total physical memory")
+	}
+	mem.Total = total
+
+	pageSize, err := sysctlUint64("hw.pagesize")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get page size")
+	}
+
+	free, err := sysctlUint64("vm.stats.vm.v_free_count")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get free memory")
+	}
+	mem.Free = free * pageSize
+	mem.Used = mem.Total - mem.Free
+
+	// Inactive and cached pages can be reclaimed without swapping.
+	mem.Available = mem.Free
+	mem.Metrics = map[string]uint64{}
+	for _, counter := range []struct {
+		sysctl      string
+		metric      string
+		reclaimable bool
+	}{
+		{"vm.stats.vm.v_active_count", "active_bytes", false},
+		{"vm.stats.vm.v_inactive_count", "inactive_bytes", true},
+		{"vm.stats.vm.v_cache_count", "cache_bytes", true},
+		{"vm.stats.vm.v_laundry_count", "laundry_bytes", false}, // Added in FreeBSD 12.
+		{"vm.stats.vm.v_wire_count", "wired_bytes", false},
+	} {
+		pages, err := sysctlUint64(counter.sysctl)
+		if err != nil {
+			continue
+		}
+		mem.Metrics[counter.metric] = pages * pageSize
+		if counter.reclaimable {
+			mem.Available += pages * pageSize
+		}
+	}
+
+	mem.Cached = mem.Metrics["cache_bytes"]
+	if bufspace, err := sysctlUint64("vfs.bufspace"); err == nil {
+		mem.Buffers = bufspace
+	}
+	if pages, err := sysctlUint64("vm.stats.vm.v_swappgsin"); err == nil {
+		mem.SwapIn = pages * pageSize
+	}
+	if pages, err := sysctlUint64("vm.stats.vm.v_swappgsout"); err == nil {
+		mem.SwapOut = pages * pageSize
+	}
+
+	// Swap usage is only available through libkvm so virtual memory is
+	// not reported.
+	return &mem, nil
+}
+
+func newHost() (*host, error) {
+	h := &host{}
+	r := &reader{}
+	r.architecture(h)
+	r.bootTime(h)
+	r.hostname(h)
+	r.network(h)
+	r.kernelVersion(h)
+	r.os(h)
+	r.time(h)
+	r.uniqueID(h)
+	return h, r.Err()
+}
+
+type reader struct {
+	errs []error
+}
+
+func (r *reader) addErr(err error) bool {
+	if err != nil {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+			r.errs = append(r.errs, err)
+		}
+		return true
+	}
+	return false
+}
+
+func (r *reader) Err() error {
+	if len(r.errs) > 0 {
+		return &multierror.MultiError{Errors: r.errs}
+	}
+	return nil
+}
+
+func (r *reader) architecture(h *host) {
+	v, err := sysctlString("hw.machine_arch")
+	if r.addErr(err) {
+		return
+	}
+	h.info.Architecture = v
+}
+
+func (r *reader) bootTime(h *host) {
+	v, err := bootClock.BootTime()
+	if r.addErr(err) {
+		return
+	}
+	h.info.BootTime = v
+}
+
+var bootClock = shared.NewBootTimeClock(kernBootTime)
+
+func kernBootTime() (time.Time, error) {
+	data, err := sysctlByName("kern.boottime")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(data) < 16 {
+		return time.Time{}, errors.New("unexpected kern.boottime size")
+	}
+
+	// struct timeval
+	sec := int64(binary.LittleEndian.Uint64(data[0:]))
+	usec := int64(binary.LittleEndian.Uint64(data[8:]))
+	return time.Unix(sec, usec*int64(time.Microsecond)), nil
+}
+
+func (r *reader) hostname(h *host) {
+	v, err := os.Hostname()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Hostname = v
+}
+
+func (r *reader) network(h *host) {
+	ips, macs, err := shared.Network()
+	if r.addErr(err) {
+		return
+	}
+	h.info.IPs = ips
+	h.info.MACs = macs
+}
+
+func (r *reader) kernelVersion(h *host) {
+	v, err := sysctlString("kern.osrelease")
+	if r.addErr(err) {
+		return
+	}
+	h.info.KernelVersion = v
+}
+
+func (r *reader) os(h *host) {
+	v, err := OperatingSystem()
+	if r.addErr(err) {
+		return
+	}
+	h.info.OS = v
+}
+
+func (r *reader) time(h *host) {
+	h.info.Timezone, h.info.TimezoneOffsetSec = time.Now().Zone()
+}
+
+func (r *reader) uniqueID(h *host) {
+	v, err := sysctlString("kern.hostuuid")
+	if r.addErr(err) {
+		return
+	}
+	h.info.UniqueID = v
+	h.info.UniqueIDSource = types.MachineIDSourceHostUUID
+}
+
+// OperatingSystem returns information about the FreeBSD release.
+func OperatingSystem() (*types.OSInfo, error) {
+	release, err := sysctlString("kern.osrelease")
+	if err != nil {
+		return nil, err
+	}
+	return getOSInfo(release)
+}
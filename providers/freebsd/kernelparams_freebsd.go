@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build freebsd,amd64 freebsd,arm64
+
+package freebsd
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+)
+
+// MIB values of the sysctl meta interface (see sys/kern/kern_sysctl.c).
+const (
+	sysctlMetaName   = 1
+	sysctlMetaNext   = 2
+	sysctlMetaOIDFmt = 4
+
+	ctlTypeMask = 0xf
+	ctlTypeNode = 1
+)
+
+// KernelParam returns the value of the named sysctl.
+func (h *host) KernelParam(name string) (string, error) {
+	mib, err := nametomib(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "unknown sysctl %v", name)
+	}
+
+	value, err := readKernelParam(mib)
+	if err != nil {
+		return "", errors.Wrapf(err, "sysctl %v failed", name)
+	}
+	return value, nil
+}
+
+// KernelParams walks the sysctl tree like "sysctl -a". Opaque values (e.g.
+// structs) and values that cannot be read are omitted.
+func (h *host) KernelParams() (map[string]string, error) {
+	params := map[string]string{}
+
+	var mib []int32
+	for {
+		data, err := sysctl(append([]int32{0, sysctlMetaNext}, mib...))
+		if err == syscall.ENOENT || (err == nil && len(data) == 0) {
+			// The end of the tree was reached.
+			return params, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get next sysctl")
+		}
+		mib = bytesToMIB(data)
+
+		name, err := sysctl(append([]int32{0, sysctlMetaName}, mib...))
+		if err != nil {
+			continue
+		}
+		value, err := readKernelParam(mib)
+		if err != nil {
+			continue
+		}
+		params[nullTerminated(name)] = value
+	}
+}
+
+func readKernelParam(mib []int32) (string, error) {
+	// The format is returned as the kind (u_int) followed by the format string.
+	fmtData, err := sysctl(append([]int32{0, sysctlMetaOIDFmt}, mib...))
+	if err != nil {
+		return "", err
+	}
+	if len(fmtData) < 4 {
+		return "", errors.New("invalid sysctl format")
+	}
+	if binary.LittleEndian.Uint32(fmtData)&ctlTypeMask == ctlTypeNode {
+		return "", errors.New("sysctl is a node")
+	}
+
+	data, err := sysctl(mib)
+	if err != nil {
+		return "", err
+	}
+	return shared.FormatSysctl(nullTerminated(fmtData[4:]), data)
+}
+
+func bytesToMIB(data []byte) []int32 {
+	mib := make([]int32, len(data)/4)
+	for i := range mib {
+		mib[i] = int32(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return mib
+}
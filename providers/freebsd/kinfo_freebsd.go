@@ -0,0 +1,131 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build freebsd,amd64 freebsd,arm64
+
+package freebsd
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// kinfoProc is Go's counterpart of struct kinfo_proc from sys/user.h for
+// 64-bit platforms.
+type kinfoProc struct {
+	Structsize    int32
+	Layout        int32
+	Args          uintptr
+	Paddr         uintptr
+	Addr          uintptr
+	Tracep        uintptr
+	Textvp        uintptr
+	Fd            uintptr
+	Vmspace       uintptr
+	Wchan         uintptr
+	Pid           int32
+	Ppid          int32
+	Pgid          int32
+	Tpgid         int32
+	Sid           int32
+	Tsid          int32
+	Jobc          int16
+	SpareShort1   int16
+	TdevFreebsd11 uint32
+	Siglist       [4]uint32
+	Sigmask       [4]uint32
+	Sigignore     [4]uint32
+	Sigcatch      [4]uint32
+	UID           uint32
+	RUID          uint32
+	SvUID         uint32
+	RGID          uint32
+	SvGID         uint32
+	Ngroups       int16
+	SpareShort2   int16
+	Groups        [16]uint32
+	Size          uint64
+	Rssize        int64
+	Swrss         int64
+	Tsize         int64
+	Dsize         int64
+	Ssize         int64
+	Xstat         uint16
+	Acflag        uint16
+	Pctcpu        uint32
+	Estcpu        uint32
+	Slptime       uint32
+	Swtime        uint32
+	Cow           uint32
+	Runtime       uint64
+	Start         syscall.Timeval
+	Childtime     syscall.Timeval
+	Flag          int64
+	Kiflag        int64
+	Traceflag     int32
+	Stat          int8
+	Nice          int8
+	Lock          int8
+	Rqindex       int8
+	OncpuOld      uint8
+	LastcpuOld    uint8
+	Tdname        [17]int8
+	Wmesg         [9]int8
+	Login         [18]int8
+	Lockname      [9]int8
+	Comm          [20]int8
+	Emul          [17]int8
+	Loginclass    [18]int8
+	Moretdname    [4]int8
+	Sparestrings  [46]int8
+	Spareints     [2]int32
+	Tdev          uint64
+	Oncpu         int32
+	Lastcpu       int32
+	Tracer        int32
+	Flag2         int32
+	Fibnum        int32
+	CrFlags       uint32
+	Jid           int32
+	Numthreads    int32
+	Tid           int32
+	Pri           [4]uint8
+	Rusage        syscall.Rusage
+	RusageCh      syscall.Rusage
+	Pcb           uintptr
+	Kstack        uintptr
+	Udata         uintptr
+	Tdaddr        uintptr
+	Spareptrs     [6]uintptr
+	Sparelongs    [12]int64
+	Sflag         int64
+	Tdflags       int64
+}
+
+const (
+	// kinfoProcSize is KINFO_PROC_SIZE from sys/user.h.
+	kinfoProcSize = 1088
+
+	// kinfoFileSize is KINFO_FILE_SIZE and kinfoFilePathOffset is the offset
+	// of kf_path within struct kinfo_file.
+	kinfoFileSize       = 1392
+	kinfoFilePathOffset = kinfoFileSize - 1024
+)
+
+// Fail to compile if the size of kinfoProc does not match the kernel's.
+var _ [kinfoProcSize - unsafe.Sizeof(kinfoProc{})]byte
+var _ [unsafe.Sizeof(kinfoProc{}) - kinfoProcSize]byte
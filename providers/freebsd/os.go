@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package freebsd
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// releaseRegexp matches kern.osrelease values like 12.1-RELEASE-p10 or
+// 14.0-CURRENT.
+var releaseRegexp = regexp.MustCompile(`^(\d+)\.(\d+)-([A-Z0-9]+)(?:-p(\d+))?`)
+
+func getOSInfo(release string) (*types.OSInfo, error) {
+	m := releaseRegexp.FindStringSubmatch(release)
+	if m == nil {
+		return nil, errors.Errorf("failed to parse release %q", release)
+	}
+
+	os := &types.OSInfo{
+		Family:   "freebsd",
+		Platform: "freebsd",
+		Name:     "FreeBSD",
+		Version:  release,
+	}
+	os.Major, _ = strconv.Atoi(m[1])
+	os.Minor, _ = strconv.Atoi(m[2])
+	if m[4] != "" {
+		os.Patch, _ = strconv.Atoi(m[4])
+	}
+	return os, nil
+}
@@ -0,0 +1,271 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build freebsd,amd64 freebsd,arm64
+
+package freebsd
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func (s freebsdSystem) Processes() ([]types.Process, error) {
+	data, err := sysctl([]int32{ctlKern, kernProc, kernProcProc, 0})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process table")
+	}
+	if len(data)%kinfoProcSize != 0 {
+		return nil, errors.Errorf("unexpected process table size %d", len(data))
+	}
+
+	processes := make([]types.Process, 0, len(data)/kinfoProcSize)
+	for ; len(data) > 0; data = data[kinfoProcSize:] {
+		kp := (*kinfoProc)(unsafe.Pointer(&data[0]))
+		processes = append(processes, &process{pid: int(kp.Pid), envFilter: s.envFilter})
+	}
+	return processes, nil
+}
+
+func (s freebsdSystem) Process(pid int) (types.Process, error) {
+	if _, err := getKinfoProc(pid); err != nil {
+		return nil, err
+	}
+	return &process{pid: pid, envFilter: s.envFilter}, nil
+}
+
+func (s freebsdSystem) Self() (types.Process, error) {
+	return &process{pid: os.Getpid(), envFilter: s.envFilter}, nil
+}
+
+type process struct {
+	pid       int
+	envFilter types.EnvFilter
+	info      *types.ProcessInfo
+}
+
+func (p *process) PID() int {
+	return p.pid
+}
+
+func (p *process) Info() (types.ProcessInfo, error) {
+	if p.info != nil {
+		return *p.info, nil
+	}
+
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.ProcessInfo{}, err
+	}
+
+	// The executable path, arguments, and CWD are unavailable for kernel
+	// processes and for processes of other users when unprivileged.
+	exe, _ := getProcString(kernProcPathname, p.pid)
+	args, _ := getProcArgs(p.pid)
+	cwd, _ := getProcCWD(p.pid)
+
+	p.info = &types.ProcessInfo{
+		Name:      int8SliceToString(kp.Comm[:]),
+		PID:       p.pid,
+		PPID:      int(kp.Ppid),
+		CWD:       cwd,
+		Exe:       exe,
+		Args:      args,
+		StartTime: time.Unix(kp.Start.Unix()),
+		State:     kinfoState(kp.Stat),
+	}
+
+	return *p.info, nil
+}
+
+// p_stat values from sys/proc.h.
+const (
+	statRun    = 2 // SRUN
+	statSleep  = 3 // SSLEEP
+	statStop   = 4 // SSTOP
+	statZombie = 5 // SZOMB
+	statWait   = 6 // SWAIT
+	statLock   = 7 // SLOCK
+)
+
+// kinfoState maps ki_stat to one of the ProcessState constants.
+func kinfoState(stat int8) string {
+	switch stat {
+	case statRun:
+		return types.ProcessStateRunning
+	case statSleep:
+		return types.ProcessStateSleeping
+	case statStop:
+		return types.ProcessStateStopped
+	case statZombie:
+		return types.ProcessStateZombie
+	case statWait:
+		return types.ProcessStateIdle
+	case statLock:
+		return types.ProcessStateWaiting
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+func (p *process) User() (types.UserInfo, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.UserInfo{}, err
+	}
+
+	return types.UserInfo{
+		UID:  strconv.Itoa(int(kp.RUID)),
+		EUID: strconv.Itoa(int(kp.UID)),
+		SUID: strconv.Itoa(int(kp.SvUID)),
+		GID:  strconv.Itoa(int(kp.RGID)),
+		EGID: strconv.Itoa(int(kp.Groups[0])),
+		SGID: strconv.Itoa(int(kp.SvGID)),
+	}, nil
+}
+
+func (p *process) Memory() (types.MemoryInfo, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.MemoryInfo{}, err
+	}
+
+	return types.MemoryInfo{
+		Resident: uint64(kp.Rssize) * uint64(os.Getpagesize()),
+		Virtual:  kp.Size,
+	}, nil
+}
+
+func (p *process) CPUTime() (types.CPUTimes, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+
+	return types.CPUTimes{
+		User:   time.Duration(kp.Rusage.Utime.Nano()),
+		System: time.Duration(kp.Rusage.Stime.Nano()),
+	}, nil
+}
+
+func (p *process) Environment() (map[string]string, error) {
+	data, err := sysctl(kernProcMIB(kernProcEnv, p.pid))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process environment")
+	}
+
+	env := map[string]string{}
+	for _, kv := range bytes.Split(data, []byte{0}) {
+		parts := bytes.SplitN(kv, []byte{'='}, 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := string(bytes.TrimSpace(parts[0]))
+		if key == "" {
+			continue
+		}
+
+		env[key] = string(parts[1])
+	}
+
+	return shared.FilterEnv(p.envFilter, env), nil
+}
+
+func (p *process) Children() ([]types.Process, error) {
+	procs, err := freebsdSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Children(procs, p)
+}
+
+// Ancestors returns the parents of the process up to the root of the tree.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := freebsdSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Ancestors(procs, p)
+}
+
+func kernProcMIB(op, pid int) []int32 {
+	return []int32{ctlKern, kernProc, int32(op), int32(pid)}
+}
+
+func getKinfoProc(pid int) (*kinfoProc, error) {
+	data, err := sysctl(kernProcMIB(kernProcPID, pid))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read process info for pid=%v", pid)
+	}
+	if len(data) == 0 {
+		return nil, &types.ProcessNotFoundError{PID: pid}
+	}
+	if len(data) < kinfoProcSize {
+		return nil, errors.Errorf("unexpected kinfo_proc size %d", len(data))
+	}
+
+	kp := *(*kinfoProc)(unsafe.Pointer(&data[0]))
+	return &kp, nil
+}
+
+func getProcString(op, pid int) (string, error) {
+	data, err := sysctl(kernProcMIB(op, pid))
+	if err != nil {
+		return "", err
+	}
+	return nullTerminated(data), nil
+}
+
+func getProcArgs(pid int) ([]string, error) {
+	data, err := sysctl(kernProcMIB(kernProcArgs, pid))
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var args []string
+	for _, arg := range bytes.Split(data, []byte{0}) {
+		args = append(args, string(arg))
+	}
+	return args, nil
+}
+
+// getProcCWD returns kf_path from the struct kinfo_file that describes the
+// working directory of the process.
+func getProcCWD(pid int) (string, error) {
+	data, err := sysctl(kernProcMIB(kernProcCWD, pid))
+	if err != nil {
+		return "", err
+	}
+	if len(data) <= kinfoFilePathOffset {
+		return "", errors.New("unexpected kinfo_file size")
+	}
+	return nullTerminated(data[kinfoFilePathOffset:]), nil
+}
@@ -18,30 +18,95 @@
 package linux
 
 import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/procfs"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
 )
 
 var (
-	bootTimeValue time.Time  // Cached boot time.
-	bootTimeLock  sync.Mutex // Lock that guards access to bootTime.
+	bootClocks     = map[procfs.FS]*shared.BootClock{} // Boot clock of each procfs mount.
+	bootClocksLock sync.Mutex                          // Lock that guards access to bootClocks.
+
+	bootIDs     = map[procfs.FS]string{} // Boot ID of each procfs mount.
+	bootIDsLock sync.Mutex               // Lock that guards access to bootIDs.
 )
 
-func bootTime(fs procfs.FS) (time.Time, error) {
-	bootTimeLock.Lock()
-	defer bootTimeLock.Unlock()
+// bootClock returns the boot clock for the given procfs. /proc/uptime is based
+// on CLOCK_BOOTTIME so it includes the time spent in suspend.
+func bootClock(fs procfs.FS) *shared.BootClock {
+	bootClocksLock.Lock()
+	defer bootClocksLock.Unlock()
+
+	clock, found := bootClocks[fs]
+	if !found {
+		clock = shared.NewUptimeClock(func() (time.Duration, error) {
+			return uptime(fs)
+		})
+		bootClocks[fs] = clock
+	}
+	return clock
+}
+
+// readBootID returns the random ID that the kernel generates on each boot or
+// an empty string if it cannot be read. It only changes on reboot so it is
+// read once per procfs like the boot clock.
+func readBootID(fs procfs.FS) string {
+	bootIDsLock.Lock()
+	defer bootIDsLock.Unlock()
 
-	if !bootTimeValue.IsZero() {
-		return bootTimeValue, nil
+	if id, found := bootIDs[fs]; found {
+		return id
 	}
+	content, err := ioutil.ReadFile(fs.Path("sys/kernel/random/boot_id"))
+	if err != nil {
+		return ""
+	}
+	id := strings.TrimSpace(string(content))
+	bootIDs[fs] = id
+	return id
+}
 
-	stat, err := fs.NewStat()
+func bootTime(fs procfs.FS) (time.Time, error) {
+	return bootClock(fs).BootTime()
+}
+
+func uptime(fs procfs.FS) (time.Duration, error) {
+	content, err := ioutil.ReadFile(fs.Path("uptime"))
 	if err != nil {
-		return time.Time{}, err
+		if !os.IsNotExist(err) {
+			return 0, err
+		}
+
+		// Fallback to the boot time in /proc/stat.
+		stat, err := fs.NewStat()
+		if err != nil {
+			return 0, err
+		}
+		return time.Since(time.Unix(int64(stat.BootTime), 0)), nil
+	}
+
+	return parseUptime(content)
+}
+
+// parseUptime parses the first field of /proc/uptime which is the number of
+// seconds since boot.
+func parseUptime(content []byte) (time.Duration, error) {
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, errors.New("empty uptime")
 	}
 
-	bootTimeValue = time.Unix(int64(stat.BootTime), 0)
-	return bootTimeValue, nil
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse uptime '%v'", fields[0])
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
 }
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Uptime = (*host)(nil)
+
+func TestParseUptime(t *testing.T) {
+	uptime, err := parseUptime([]byte("350735.47 234388.90\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 350735470*time.Millisecond, uptime)
+
+	_, err = parseUptime([]byte(""))
+	assert.Error(t, err)
+}
+
+func TestHostUptime(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uptime, err := host.(types.Uptime).Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 350735470*time.Millisecond, uptime)
+	assert.WithinDuration(t, time.Now().Add(-uptime), host.Info().BootTime, 2*time.Second)
+}
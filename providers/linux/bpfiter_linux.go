@@ -0,0 +1,285 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// The eBPF process backend loads a BPF_TRACE_ITER program attached to the
+// bpf_iter_task target (Linux 5.8+). It writes the TGID of every thread group
+// leader to the iterator, which is read like a file. The offsets of the
+// task_struct fields are taken from the kernel's BTF, so no compiler or BPF
+// library is needed.
+
+// Constants from linux/bpf.h.
+const (
+	bpfProgLoad   = 5
+	bpfLinkCreate = 28
+	bpfIterCreate = 33
+
+	bpfProgTypeTracing = 26
+	bpfTraceIter       = 28
+
+	bpfFuncSeqWrite = 127
+)
+
+// vmlinuxBTF is the BTF of the running kernel.
+const vmlinuxBTF = "/sys/kernel/btf/vmlinux"
+
+// pidInitIno is the inode of the initial PID namespace (PROC_PID_INIT_INO).
+const pidInitIno = 0xEFFFFFFC
+
+var taskIter struct {
+	once sync.Once
+	link int // Link of the loaded program.
+	err  error
+}
+
+// bpfTaskPIDs returns the PIDs of all processes using a BPF task iterator.
+// The program is loaded on first use and kept for the lifetime of the
+// process. The task iterator reports the PIDs of the initial PID namespace,
+// so it is only used when the caller runs in that namespace.
+func bpfTaskPIDs() ([]int, error) {
+	taskIter.once.Do(func() {
+		taskIter.link, taskIter.err = loadTaskIter()
+	})
+	if taskIter.err != nil {
+		return nil, taskIter.err
+	}
+
+	fd, err := bpf(bpfIterCreate, unsafe.Pointer(&bpfIterCreateAttr{LinkFD: uint32(taskIter.link)}), unsafe.Sizeof(bpfIterCreateAttr{}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create BPF task iterator")
+	}
+	f := os.NewFile(uintptr(fd), "bpf_iter_task")
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read BPF task iterator")
+	}
+	pids := make([]int, 0, len(data)/4)
+	for ; len(data) >= 4; data = data[4:] {
+		pids = append(pids, int(nativeEndian.Uint32(data)))
+	}
+	return pids, nil
+}
+
+// inInitPIDNamespace reports whether the current process is in the initial
+// PID namespace.
+func inInitPIDNamespace() bool {
+	var st syscall.Stat_t
+	if err := syscall.Stat("/proc/self/ns/pid", &st); err != nil {
+		return false
+	}
+	return st.Ino == pidInitIno
+}
+
+func loadTaskIter() (int, error) {
+	spec, err := ioutil.ReadFile(vmlinuxBTF)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to read kernel BTF")
+	}
+	btf, err := parseBTF(spec)
+	if err != nil {
+		return -1, err
+	}
+	target, err := btf.funcID("bpf_iter_task")
+	if err != nil {
+		return -1, err
+	}
+	pidOff, err := btf.memberOffset("task_struct", "pid")
+	if err != nil {
+		return -1, err
+	}
+	tgidOff, err := btf.memberOffset("task_struct", "tgid")
+	if err != nil {
+		return -1, err
+	}
+
+	insns, err := taskIterProgram(pidOff, tgidOff)
+	if err != nil {
+		return -1, err
+	}
+	prog, err := loadTracingProgram(insns, target)
+	if err != nil {
+		return -1, err
+	}
+	defer syscall.Close(prog)
+
+	attr := bpfLinkCreateAttr{ProgFD: uint32(prog), AttachType: bpfTraceIter}
+	link, err := bpf(bpfLinkCreate, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to attach BPF task iterator")
+	}
+	return link, nil
+}
+
+// bpfInsn is a struct bpf_insn. Regs holds dst_reg and src_reg.
+type bpfInsn struct {
+	Code uint8
+	Regs uint8
+	Off  int16
+	Imm  int32
+}
+
+func insn(code, dst, src uint8, off int16, imm int32) bpfInsn {
+	// dst_reg is the low nibble on little endian hosts.
+	regs := src<<4 | dst
+	if nativeEndian == binary.BigEndian {
+		regs = dst<<4 | src
+	}
+	return bpfInsn{Code: code, Regs: regs, Off: off, Imm: imm}
+}
+
+// Opcodes used by the task iterator program.
+const (
+	ldxMemW  = 0x61 // BPF_LDX | BPF_MEM | BPF_W
+	ldxMemDW = 0x79 // BPF_LDX | BPF_MEM | BPF_DW
+	stxMemW  = 0x63 // BPF_STX | BPF_MEM | BPF_W
+	movReg   = 0xbf // BPF_ALU64 | BPF_MOV | BPF_X
+	movImm   = 0xb7 // BPF_ALU64 | BPF_MOV | BPF_K
+	addImm   = 0x07 // BPF_ALU64 | BPF_ADD | BPF_K
+	jeqImm   = 0x15 // BPF_JMP | BPF_JEQ | BPF_K
+	jneReg   = 0x5d // BPF_JMP | BPF_JNE | BPF_X
+	call     = 0x85 // BPF_JMP | BPF_CALL
+	exit     = 0x95 // BPF_JMP | BPF_EXIT
+)
+
+// taskIterProgram returns the program that writes ctx->task->tgid with
+// bpf_seq_write if the task is a thread group leader. The context is a
+// struct bpf_iter__task { struct bpf_iter_meta *meta; struct task_struct
+// *task; } and meta->seq is the first field of struct bpf_iter_meta.
+func taskIterProgram(pidOff, tgidOff uint32) ([]bpfInsn, error) {
+	if pidOff > 1<<15-1 || tgidOff > 1<<15-1 {
+		return nil, errors.New("task_struct offsets exceed the BPF offset range")
+	}
+	return []bpfInsn{
+		insn(movReg, 6, 1, 0, 0),               // r6 = ctx
+		insn(ldxMemDW, 7, 6, 8, 0),             // r7 = ctx->task
+		insn(jeqImm, 7, 0, 10, 0),              // if r7 == NULL goto out
+		insn(ldxMemW, 8, 7, int16(pidOff), 0),  // r8 = task->pid
+		insn(ldxMemW, 9, 7, int16(tgidOff), 0), // r9 = task->tgid
+		insn(jneReg, 8, 9, 7, 0),               // if r8 != r9 goto out
+		insn(stxMemW, 10, 9, -4, 0),            // *(u32 *)(fp - 4) = r9
+		insn(ldxMemDW, 1, 6, 0, 0),             // r1 = ctx->meta
+		insn(ldxMemDW, 1, 1, 0, 0),             // r1 = meta->seq
+		insn(movReg, 2, 10, 0, 0),              // r2 = fp
+		insn(addImm, 2, 0, 0, -4),              // r2 -= 4
+		insn(movImm, 3, 0, 0, 4),               // r3 = 4
+		insn(call, 0, 0, 0, bpfFuncSeqWrite),   // bpf_seq_write(r1, r2, r3)
+		insn(movImm, 0, 0, 0, 0),               // out: r0 = 0
+		insn(exit, 0, 0, 0, 0),                 // return r0
+	}, nil
+}
+
+// bpfProgLoadAttr is the BPF_PROG_LOAD part of union bpf_attr up to
+// attach_btf_obj_fd.
+type bpfProgLoadAttr struct {
+	ProgType           uint32
+	InsnCnt            uint32
+	Insns              uint64
+	License            uint64
+	LogLevel           uint32
+	LogSize            uint32
+	LogBuf             uint64
+	KernVersion        uint32
+	ProgFlags          uint32
+	ProgName           [16]byte
+	ProgIfindex        uint32
+	ExpectedAttachType uint32
+	ProgBTFFD          uint32
+	FuncInfoRecSize    uint32
+	FuncInfo           uint64
+	FuncInfoCnt        uint32
+	LineInfoRecSize    uint32
+	LineInfo           uint64
+	LineInfoCnt        uint32
+	AttachBTFID        uint32
+	AttachBTFObjFD     uint32
+	_                  uint32
+}
+
+type bpfLinkCreateAttr struct {
+	ProgFD     uint32
+	TargetFD   uint32
+	AttachType uint32
+	Flags      uint32
+	IterInfo   uint64
+	IterInfoLn uint32
+	_          uint32
+}
+
+type bpfIterCreateAttr struct {
+	LinkFD uint32
+	Flags  uint32
+}
+
+// loadTracingProgram loads a BPF_TRACE_ITER program for the BTF function
+// target. If the verifier rejects it then the verifier log is returned.
+func loadTracingProgram(insns []bpfInsn, target uint32) (int, error) {
+	license := []byte("GPL\x00")
+	attr := bpfProgLoadAttr{
+		ProgType:           bpfProgTypeTracing,
+		InsnCnt:            uint32(len(insns)),
+		Insns:              uint64(uintptr(unsafe.Pointer(&insns[0]))),
+		License:            uint64(uintptr(unsafe.Pointer(&license[0]))),
+		ExpectedAttachType: bpfTraceIter,
+		AttachBTFID:        target,
+	}
+	copy(attr.ProgName[:], "sysinfo_tasks")
+
+	fd, err := bpf(bpfProgLoad, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+	if err == syscall.EACCES || err == syscall.EINVAL {
+		// Load it again to get the reason from the verifier.
+		log := make([]byte, 64*1024)
+		attr.LogLevel, attr.LogSize = 1, uint32(len(log))
+		attr.LogBuf = uint64(uintptr(unsafe.Pointer(&log[0])))
+		if fd, err = bpf(bpfProgLoad, unsafe.Pointer(&attr), unsafe.Sizeof(attr)); err != nil {
+			if n := bytes.IndexByte(log, 0); n >= 0 {
+				log = log[:n]
+			}
+			err = errors.Wrapf(err, "BPF verifier rejected the task iterator: %s", bytes.TrimSpace(log))
+		}
+		runtime.KeepAlive(log)
+	}
+	runtime.KeepAlive(insns)
+	runtime.KeepAlive(license)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to load BPF task iterator")
+	}
+	return fd, nil
+}
+
+func bpf(cmd int, attr unsafe.Pointer, size uintptr) (int, error) {
+	r, _, errno := syscall.Syscall(sysBPF, uintptr(cmd), uintptr(attr), size)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(r), nil
+}
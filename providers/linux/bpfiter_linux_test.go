@@ -0,0 +1,80 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseBTF(t *testing.T) {
+	data, err := ioutil.ReadFile(vmlinuxBTF)
+	if err != nil {
+		t.Skip("kernel BTF is not available:", err)
+	}
+
+	btf, err := parseBTF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = btf.funcID("bpf_iter_task")
+	assert.NoError(t, err)
+	pidOff, err := btf.memberOffset("task_struct", "pid")
+	assert.NoError(t, err)
+	tgidOff, err := btf.memberOffset("task_struct", "tgid")
+	assert.NoError(t, err)
+	assert.Equal(t, pidOff+4, tgidOff)
+
+	_, err = btf.memberOffset("task_struct", "no_such_member")
+	assert.Error(t, err)
+}
+
+func TestBPFTaskPIDs(t *testing.T) {
+	pids, err := bpfTaskPIDs()
+	if err != nil {
+		t.Skip("BPF task iterator is not available:", err)
+	}
+	assert.Contains(t, pids, os.Getpid())
+}
+
+func TestWithProcessBackend(t *testing.T) {
+	_, err := linuxSystem{}.WithProcessBackend("bogus")
+	assert.Error(t, err)
+
+	s, err := newLinuxSystem("").WithProcessBackend(types.ProcessBackendEBPF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Falls back to procfs if the task iterator cannot be used.
+	procs, err := s.(linuxSystem).Processes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, p := range procs {
+		if p.PID() == os.Getpid() {
+			found = true
+		}
+	}
+	assert.True(t, found, "self was not enumerated")
+}
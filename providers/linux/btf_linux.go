@@ -0,0 +1,187 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// BTF type kinds from linux/btf.h.
+const (
+	btfKindInt      = 1
+	btfKindArray    = 3
+	btfKindStruct   = 4
+	btfKindUnion    = 5
+	btfKindEnum     = 6
+	btfKindFunc     = 12
+	btfKindFuncProt = 13
+	btfKindVar      = 14
+	btfKindDatasec  = 15
+	btfKindDeclTag  = 17
+	btfKindEnum64   = 19
+)
+
+const btfMagic = 0xeB9F
+
+// btfType is a struct btf_type and the members of struct and union types.
+type btfType struct {
+	name     string
+	kind     uint8
+	kindFlag bool
+	members  []btfMember
+}
+
+// btfMember is a struct btf_member with the offset in bits.
+type btfMember struct {
+	name   string
+	typeID uint32
+	offset uint32
+}
+
+// btfSpec holds the types of a BTF blob that are needed to load programs.
+// Only the names and the members of structs and unions are kept.
+type btfSpec struct {
+	types []btfType // Indexed by type ID. ID 0 is void.
+}
+
+// parseBTF parses the type section of a raw BTF blob such as
+// /sys/kernel/btf/vmlinux.
+func parseBTF(data []byte) (*btfSpec, error) {
+	if len(data) < 24 {
+		return nil, errors.New("BTF header is truncated")
+	}
+	var bo binary.ByteOrder = binary.LittleEndian
+	if bo.Uint16(data) != btfMagic {
+		bo = binary.BigEndian
+		if bo.Uint16(data) != btfMagic {
+			return nil, errors.New("invalid BTF magic")
+		}
+	}
+
+	hdrLen := bo.Uint32(data[4:])
+	typeOff, typeLen := bo.Uint32(data[8:]), bo.Uint32(data[12:])
+	strOff, strLen := bo.Uint32(data[16:]), bo.Uint32(data[20:])
+	if uint64(hdrLen)+uint64(typeOff)+uint64(typeLen) > uint64(len(data)) ||
+		uint64(hdrLen)+uint64(strOff)+uint64(strLen) > uint64(len(data)) {
+		return nil, errors.New("BTF sections are truncated")
+	}
+	typesData := data[hdrLen+typeOff : hdrLen+typeOff+typeLen]
+	strs := data[hdrLen+strOff : hdrLen+strOff+strLen]
+
+	name := func(off uint32) string {
+		if off >= uint32(len(strs)) {
+			return ""
+		}
+		s := strs[off:]
+		if n := bytes.IndexByte(s, 0); n >= 0 {
+			s = s[:n]
+		}
+		return string(s)
+	}
+
+	spec := &btfSpec{types: []btfType{{}}}
+	for len(typesData) > 0 {
+		if len(typesData) < 12 {
+			return nil, errors.New("BTF type is truncated")
+		}
+		info := bo.Uint32(typesData[4:])
+		t := btfType{
+			name:     name(bo.Uint32(typesData)),
+			kind:     uint8(info >> 24 & 0x1f),
+			kindFlag: info>>31 == 1,
+		}
+		vlen := int(info & 0xffff)
+		typesData = typesData[12:]
+
+		var extra int
+		switch t.kind {
+		case btfKindInt, btfKindVar, btfKindDeclTag:
+			extra = 4
+		case btfKindArray:
+			extra = 12
+		case btfKindStruct, btfKindUnion, btfKindDatasec, btfKindEnum64:
+			extra = 12 * vlen
+		case btfKindEnum, btfKindFuncProt:
+			extra = 8 * vlen
+		}
+		if len(typesData) < extra {
+			return nil, errors.New("BTF type is truncated")
+		}
+
+		if t.kind == btfKindStruct || t.kind == btfKindUnion {
+			t.members = make([]btfMember, vlen)
+			for i := range t.members {
+				m := typesData[12*i:]
+				offset := bo.Uint32(m[8:])
+				if t.kindFlag {
+					// The high 8 bits hold the size of a bitfield.
+					offset &= 0xffffff
+				}
+				t.members[i] = btfMember{name: name(bo.Uint32(m)), typeID: bo.Uint32(m[4:]), offset: offset}
+			}
+		}
+		typesData = typesData[extra:]
+		spec.types = append(spec.types, t)
+	}
+	return spec, nil
+}
+
+// funcID returns the type ID of the function named name.
+func (s *btfSpec) funcID(name string) (uint32, error) {
+	for id, t := range s.types {
+		if t.kind == btfKindFunc && t.name == name {
+			return uint32(id), nil
+		}
+	}
+	return 0, errors.Errorf("BTF function %v not found", name)
+}
+
+// memberOffset returns the byte offset of a member of the struct named
+// structName. Members of anonymous structs and unions are found too.
+func (s *btfSpec) memberOffset(structName, member string) (uint32, error) {
+	for _, t := range s.types {
+		if t.kind != btfKindStruct || t.name != structName {
+			continue
+		}
+		if off, found := s.findMember(t, member, 0); found {
+			if off%8 != 0 {
+				return 0, errors.Errorf("%v.%v is a bitfield", structName, member)
+			}
+			return off / 8, nil
+		}
+		break
+	}
+	return 0, errors.Errorf("BTF member %v.%v not found", structName, member)
+}
+
+func (s *btfSpec) findMember(t btfType, member string, base uint32) (uint32, bool) {
+	for _, m := range t.members {
+		if m.name == member {
+			return base + m.offset, true
+		}
+		if m.name == "" && int(m.typeID) < len(s.types) {
+			if off, found := s.findMember(s.types[m.typeID], member, base+m.offset); found {
+				return off, true
+			}
+		}
+	}
+	return 0, false
+}
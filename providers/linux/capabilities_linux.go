@@ -19,6 +19,9 @@ package linux
 
 import (
 	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/elastic/go-sysinfo/types"
 )
@@ -67,8 +70,13 @@ var capabilityNames = map[int]string{
 	35: "wake_alarm",
 	36: "block_suspend",
 	37: "audit_read",
+	38: "perfmon",
+	39: "bpf",
+	40: "checkpoint_restore",
 }
 
+// capabilityName returns the name of the capability. Capabilities that are
+// newer than this table are returned as their number so they are not lost.
 func capabilityName(num int) string {
 	name, found := capabilityNames[num]
 	if found {
@@ -78,6 +86,25 @@ func capabilityName(num int) string {
 	return strconv.Itoa(num)
 }
 
+// CapabilityValue returns the bit number of the named capability. The name
+// is case insensitive and may have a CAP_ prefix (e.g. CAP_NET_ADMIN or
+// net_admin). Numeric names as returned for unknown capabilities are
+// accepted too.
+func CapabilityValue(name string) (int, error) {
+	name = strings.ToLower(name)
+	name = strings.TrimPrefix(name, "cap_")
+	for num, n := range capabilityNames {
+		if n == name {
+			return num, nil
+		}
+	}
+
+	if num, err := strconv.Atoi(name); err == nil && num >= 0 && num < 64 {
+		return num, nil
+	}
+	return 0, errors.Errorf("unknown capability %v", name)
+}
+
 func readCapabilities(content []byte) (*types.CapabilityInfo, error) {
 	var cap types.CapabilityInfo
 
@@ -109,6 +136,18 @@ func readCapabilities(content []byte) (*types.CapabilityInfo, error) {
 			if err != nil {
 				return err
 			}
+		case "NoNewPrivs":
+			noNewPrivs, err := strconv.ParseBool(string(value))
+			if err != nil {
+				return err
+			}
+			cap.NoNewPrivs = &noNewPrivs
+		case "Seccomp":
+			mode, err := strconv.ParseUint(string(value), 10, 8)
+			if err != nil {
+				return err
+			}
+			cap.SeccompMode = SeccompMode(mode).String()
 		}
 		return nil
 	})
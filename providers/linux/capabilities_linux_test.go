@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestReadCapabilities(t *testing.T) {
+	const status = `Name:	containerd-shim
+CapInh:	0000000000000000
+CapPrm:	0000000000003000
+CapEff:	0000000000003000
+CapBnd:	000001c000000001
+CapAmb:	0000000000001000
+NoNewPrivs:	1
+Seccomp:	2
+`
+	caps, err := readCapabilities([]byte(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noNewPrivs := true
+	assert.Equal(t, &types.CapabilityInfo{
+		Permitted:   []string{"net_admin", "net_raw"},
+		Effective:   []string{"net_admin", "net_raw"},
+		Bounding:    []string{"chown", "perfmon", "bpf", "checkpoint_restore"},
+		Ambient:     []string{"net_admin"},
+		NoNewPrivs:  &noNewPrivs,
+		SeccompMode: "filter",
+	}, caps)
+}
+
+func TestReadCapabilitiesUnknown(t *testing.T) {
+	caps, err := readCapabilities([]byte("CapEff:\t0000020000000000\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"41"}, caps.Effective)
+}
+
+func TestCapabilityValue(t *testing.T) {
+	for name, num := range map[string]int{
+		"CAP_NET_ADMIN": 12,
+		"net_admin":     12,
+		"Sys_Admin":     21,
+		"41":            41,
+	} {
+		v, err := CapabilityValue(name)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, num, v, name)
+		}
+	}
+
+	_, err := CapabilityValue("cap_unknown")
+	assert.Error(t, err)
+}
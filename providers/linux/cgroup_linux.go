@@ -0,0 +1,240 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// CgroupStats returns the statistics of the process's cgroup in the cgroup v2
+// unified hierarchy.
+func (p *process) CgroupStats() (*types.CgroupInfo, error) {
+	cgroup, err := p.fs.ReadFile(p.name("cgroup"))
+	if err != nil {
+		return nil, err
+	}
+
+	mountinfo, err := p.fs.ReadFile("proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.CgroupInfo{}
+
+	path, found := cgroupV2Path(cgroup)
+	if !found {
+		return info, nil
+	}
+
+	mountpoint, root := cgroupV2Mount(mountinfo)
+	if mountpoint == "" {
+		return info, nil
+	}
+
+	// When the hierarchy is mounted from a sub-cgroup (e.g. in a container
+	// without a private cgroup namespace) the path is relative to the root
+	// of the mount.
+	rel := path
+	if root != "/" {
+		if !strings.HasPrefix(path, root) {
+			return info, nil
+		}
+		rel = strings.TrimPrefix(path, root)
+	}
+
+	info.V2, err = readCgroupsV2(filepath.Join(mountpoint, rel))
+	if err != nil {
+		return nil, err
+	}
+	info.V2.Path = path
+	return info, nil
+}
+
+// cgroupV2Path returns the path of the unified hierarchy entry in the contents
+// of /proc/[pid]/cgroup.
+func cgroupV2Path(data []byte) (string, bool) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		// The unified hierarchy entry has the format 0::cgroup-path.
+		if path := strings.TrimPrefix(s.Text(), "0::"); path != s.Text() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// cgroupV2Mount returns the mount point and root of the cgroup2 file system
+// from the contents of /proc/[pid]/mountinfo.
+func cgroupV2Mount(data []byte) (mountpoint, root string) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		// Fields are: ID parent major:minor root mount-point options
+		// [optional-fields...] - fstype source super-options.
+		fields := strings.Fields(s.Text())
+		for i := 6; i < len(fields)-1; i++ {
+			if fields[i] == "-" {
+				if fields[i+1] == "cgroup2" {
+					return fields[4], fields[3]
+				}
+				break
+			}
+		}
+	}
+	return "", ""
+}
+
+// readCgroupsV2 reads the statistics from the cgroup directory. Files of
+// controllers that are not enabled for the cgroup are ignored.
+func readCgroupsV2(dir string) (*types.CgroupsV2, error) {
+	stats := &types.CgroupsV2{}
+
+	readers := []struct {
+		file string
+		read func([]byte, *types.CgroupsV2) error
+	}{
+		{"cpu.stat", parseCgroupV2CPUStat},
+		{"memory.current", func(b []byte, s *types.CgroupsV2) (err error) {
+			s.Memory.Current, err = parseCgroupV2Value(b)
+			return err
+		}},
+		{"memory.max", func(b []byte, s *types.CgroupsV2) (err error) {
+			s.Memory.Max, err = parseCgroupV2Limit(b)
+			return err
+		}},
+		{"io.stat", parseCgroupV2IOStat},
+		{"pids.current", func(b []byte, s *types.CgroupsV2) (err error) {
+			s.Pids.Current, err = parseCgroupV2Value(b)
+			return err
+		}},
+		{"pids.max", func(b []byte, s *types.CgroupsV2) (err error) {
+			s.Pids.Max, err = parseCgroupV2Limit(b)
+			return err
+		}},
+	}
+
+	for _, r := range readers {
+		content, err := ioutil.ReadFile(filepath.Join(dir, r.file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if err = r.read(content, stats); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %v", r.file)
+		}
+	}
+
+	return stats, nil
+}
+
+func parseCgroupV2CPUStat(content []byte, stats *types.CgroupsV2) error {
+	return parseKeyValue(content, " ", func(key, value []byte) error {
+		n, err := strconv.ParseUint(string(value), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		switch string(key) {
+		case "usage_usec":
+			stats.CPU.Usage = time.Duration(n) * time.Microsecond
+		case "user_usec":
+			stats.CPU.User = time.Duration(n) * time.Microsecond
+		case "system_usec":
+			stats.CPU.System = time.Duration(n) * time.Microsecond
+		case "nr_periods":
+			stats.CPU.Periods = n
+		case "nr_throttled":
+			stats.CPU.Throttled = n
+		case "throttled_usec":
+			stats.CPU.ThrottledTime = time.Duration(n) * time.Microsecond
+		}
+		return nil
+	})
+}
+
+func parseCgroupV2IOStat(content []byte, stats *types.CgroupsV2) error {
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		// Format is major:minor rbytes=n wbytes=n rios=n wios=n dbytes=n dios=n.
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		dev := types.CgroupV2IODevStats{Device: fields[0]}
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+
+			n, err := strconv.ParseUint(parts[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			switch parts[0] {
+			case "rbytes":
+				dev.ReadBytes = n
+			case "wbytes":
+				dev.WriteBytes = n
+			case "rios":
+				dev.ReadOps = n
+			case "wios":
+				dev.WriteOps = n
+			case "dbytes":
+				dev.DiscardBytes = n
+			case "dios":
+				dev.DiscardOps = n
+			}
+		}
+		stats.IO = append(stats.IO, dev)
+	}
+	return s.Err()
+}
+
+func parseCgroupV2Value(content []byte) (uint64, error) {
+	return strconv.ParseUint(string(bytes.TrimSpace(content)), 10, 64)
+}
+
+// parseCgroupV2Limit parses a limit file. It returns nil for "max".
+func parseCgroupV2Limit(content []byte) (*uint64, error) {
+	content = bytes.TrimSpace(content)
+	if string(content) == "max" {
+		return nil, nil
+	}
+
+	n, err := parseCgroupV2Value(content)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const hybridCgroup = `9:name=systemd:/system.slice/nginx.service
+4:memory:/system.slice/nginx.service
+0::/system.slice/nginx.service
+`
+
+const hybridMountinfo = `32 24 0:28 / /sys/fs/cgroup ro,nosuid,nodev,noexec shared:9 - tmpfs tmpfs ro,mode=755
+33 32 0:29 / /sys/fs/cgroup/unified rw,nosuid,nodev,noexec,relatime shared:10 - cgroup2 cgroup2 rw,nsdelegate
+34 32 0:30 / /sys/fs/cgroup/systemd rw,nosuid,nodev,noexec,relatime shared:11 - cgroup cgroup rw,xattr,name=systemd
+`
+
+func TestCgroupV2Path(t *testing.T) {
+	path, found := cgroupV2Path([]byte(hybridCgroup))
+	assert.True(t, found)
+	assert.Equal(t, "/system.slice/nginx.service", path)
+
+	_, found = cgroupV2Path([]byte(containerCgroup))
+	assert.False(t, found)
+}
+
+func TestCgroupV2Mount(t *testing.T) {
+	mountpoint, root := cgroupV2Mount([]byte(hybridMountinfo))
+	assert.Equal(t, "/sys/fs/cgroup/unified", mountpoint)
+	assert.Equal(t, "/", root)
+
+	mountpoint, _ = cgroupV2Mount([]byte(containerCgroup))
+	assert.Empty(t, mountpoint)
+}
+
+func TestReadCgroupsV2(t *testing.T) {
+	stats, err := readCgroupsV2("testdata/cgroupv2/system.slice/nginx.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memMax := uint64(536870912)
+	assert.Equal(t, &types.CgroupsV2{
+		CPU: types.CgroupV2CPUStats{
+			Usage:         1451760 * time.Microsecond,
+			User:          921339 * time.Microsecond,
+			System:        530421 * time.Microsecond,
+			Periods:       150,
+			Throttled:     12,
+			ThrottledTime: 48210 * time.Microsecond,
+		},
+		Memory: types.CgroupV2MemoryStats{
+			Current: 8503296,
+			Max:     &memMax,
+		},
+		IO: []types.CgroupV2IODevStats{
+			{Device: "8:0", ReadBytes: 2367488, WriteBytes: 4096, ReadOps: 102, WriteOps: 1},
+			{Device: "253:0", ReadBytes: 1024, ReadOps: 2},
+		},
+		Pids: types.CgroupV2PidsStats{Current: 3},
+	}, stats)
+}
+
+func TestReadCgroupsV2MissingControllers(t *testing.T) {
+	stats, err := readCgroupsV2("testdata/cgroupv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.CgroupsV2{}, stats)
+}
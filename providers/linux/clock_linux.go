@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// adjtimex status bits and states from linux/timex.h.
+const (
+	staUnsync = 0x0040 // Clock is not synchronized.
+	staNano   = 0x2000 // Offset is in nanoseconds instead of microseconds.
+	timeError = 5      // Clock is not synchronized (TIME_ERROR).
+)
+
+// ClockInfo returns the kernel clock discipline state from adjtimex, the
+// current clocksource, and the version of the time zone database.
+func (h *host) ClockInfo() (*types.ClockStatus, error) {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := timexClockStatus(state, int64(tx.Status), int64(tx.Offset), int64(tx.Freq), int64(tx.Maxerror), int64(tx.Esterror))
+	status.Source = readAttr(h.fs, "sys/devices/system/clocksource/clocksource0/current_clocksource")
+
+	for _, name := range []string{"usr/share/zoneinfo/+VERSION", "usr/share/zoneinfo/tzdata.zi"} {
+		if content, err := h.fs.ReadFile(name); err == nil {
+			if status.TZDataVersion = shared.TZDataVersion(content); status.TZDataVersion != "" {
+				break
+			}
+		}
+	}
+	return status, nil
+}
+
+// timexClockStatus converts the values of struct timex. The frequency is in
+// units of 2^-16 ppm and errors are in microseconds.
+func timexClockStatus(state int, flags, offset, freq, maxError, estError int64) *types.ClockStatus {
+	unit := time.Microsecond
+	if flags&staNano != 0 {
+		unit = time.Nanosecond
+	}
+
+	return &types.ClockStatus{
+		Synchronized:   state != timeError && flags&staUnsync == 0,
+		Offset:         time.Duration(offset) * unit,
+		Frequency:      float64(freq) / 65536,
+		MaxError:       time.Duration(maxError) * time.Microsecond,
+		EstimatedError: time.Duration(estError) * time.Microsecond,
+	}
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.ClockInfo = (*host)(nil)
+
+func TestTimexClockStatus(t *testing.T) {
+	assert.Equal(t, &types.ClockStatus{
+		Synchronized:   true,
+		Offset:         -250 * time.Microsecond,
+		Frequency:      -1.5,
+		MaxError:       16 * time.Millisecond,
+		EstimatedError: 2 * time.Microsecond,
+	}, timexClockStatus(0, 0x1, -250, -98304, 16000, 2))
+
+	// STA_NANO changes the offset unit.
+	assert.Equal(t, 250*time.Nanosecond, timexClockStatus(0, staNano, 250, 0, 0, 0).Offset)
+
+	assert.False(t, timexClockStatus(0, staUnsync, 0, 0, 0, 0).Synchronized)
+	assert.False(t, timexClockStatus(timeError, 0, 0, 0, 0, 0).Synchronized)
+}
+
+func TestHostClockInfo(t *testing.T) {
+	host, err := newLinuxSystem("").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock, err := host.(types.ClockInfo).ClockInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", clock)
+}
@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.KernelCmdline = (*host)(nil)
+
+func TestHostKernelCmdline(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmdline, err := host.(types.KernelCmdline).KernelCmdline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "BOOT_IMAGE=/boot/vmlinuz-4.13.0-16-generic root=UUID=5b1c9ce4-3d3e-4a2a-b7d7-3e9e0cdb3bf5 ro quiet splash vt.handoff=7", cmdline.Raw)
+	assert.Len(t, cmdline.Parameters, 6)
+
+	root, found := cmdline.Get("root")
+	assert.True(t, found)
+	assert.Equal(t, "UUID=5b1c9ce4-3d3e-4a2a-b7d7-3e9e0cdb3bf5", root)
+}
@@ -20,17 +20,51 @@ package linux
 import (
 	"bufio"
 	"bytes"
-	"io/ioutil"
 	"os"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Names are relative to the root of a fileSystem.
+const (
+	procOneCgroup     = "proc/1/cgroup"
+	procOneMountinfo  = "proc/1/mountinfo"
+	procSelfMountinfo = "proc/self/mountinfo"
+	dockerEnvFile     = ".dockerenv"
+	podmanEnvFile     = "run/.containerenv"
 )
 
-const procOneCgroup = "/proc/1/cgroup"
+// containerIDRegexp matches the 64 character hex IDs used by docker,
+// containerd, cri-o, and podman.
+var containerIDRegexp = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// cgroupRuntimes maps substrings of a container's cgroup path to its runtime.
+// More specific markers must come first.
+var cgroupRuntimes = []struct {
+	marker  string
+	runtime string
+}{
+	{"cri-containerd-", types.ContainerRuntimeContainerd},
+	{"crio-", types.ContainerRuntimeCRIO},
+	{"libpod-", types.ContainerRuntimePodman},
+	{"docker", types.ContainerRuntimeDocker},
+	{"/lxc/", types.ContainerRuntimeLXC},
+	{"lxc.payload", types.ContainerRuntimeLXC},
+	{"containerd", types.ContainerRuntimeContainerd},
+}
 
 // IsContainerized returns true if this process is containerized.
 func IsContainerized() (bool, error) {
-	data, err := ioutil.ReadFile(procOneCgroup)
+	return isContainerized(dirFS("/"))
+}
+
+// isContainerized reports whether the init process of fs runs in a container.
+func isContainerized(fs fileSystem) (bool, error) {
+	data, err := fs.ReadFile(procOneCgroup)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -57,3 +91,122 @@ func isContainerizedCgroup(data []byte) (bool, error) {
 
 	return true, s.Err()
 }
+
+// ContainerInfo returns details about the container this process is running
+// in. It returns nil if no container could be detected.
+func ContainerInfo() (*types.ContainerInfo, error) {
+	return readContainerInfo(dirFS("/"), procSelfMountinfo)
+}
+
+// readContainerInfo returns details about the container of the init process
+// of fs. The mount IDs are taken from the given mountinfo file. Below a host
+// FS mount that is the one of the init process because proc/self refers to
+// the reading process and not to the host.
+func readContainerInfo(fs fileSystem, mountinfoFile string) (*types.ContainerInfo, error) {
+	cgroup, err := fs.ReadFile(procOneCgroup)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read process cgroups")
+	}
+
+	mountinfo, err := fs.ReadFile(mountinfoFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read process mountinfo")
+	}
+
+	exists := func(name string) bool {
+		_, err := fs.Stat(name)
+		return err == nil
+	}
+	return containerInfo(cgroup, mountinfo, exists), nil
+}
+
+func containerInfo(cgroup, mountinfo []byte, exists func(string) bool) *types.ContainerInfo {
+	info := &types.ContainerInfo{CgroupPath: containerCgroupPath(cgroup)}
+
+	for _, r := range cgroupRuntimes {
+		if strings.Contains(info.CgroupPath, r.marker) {
+			info.Runtime = r.runtime
+			break
+		}
+	}
+
+	switch {
+	case info.Runtime != "":
+	case exists(dockerEnvFile):
+		info.Runtime = types.ContainerRuntimeDocker
+	case exists(podmanEnvFile):
+		info.Runtime = types.ContainerRuntimePodman
+	}
+
+	if info.Runtime == types.ContainerRuntimeLXC {
+		info.ID = lxcName(info.CgroupPath)
+	} else if id := containerIDRegexp.FindString(info.CgroupPath); id != "" {
+		info.ID = id
+	} else {
+		// With a private cgroup namespace the cgroup path is "/" so look for
+		// the ID in the sources of the files that runtimes bind mount into
+		// the container (e.g. /etc/hostname).
+		info.ID = mountinfoContainerID(mountinfo)
+	}
+
+	if info.Runtime == "" && info.ID == "" && info.CgroupPath == "" {
+		return nil
+	}
+	return info
+}
+
+// containerCgroupPath returns the first cgroup path that is not the root or
+// the init scope of the host.
+func containerCgroupPath(data []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		// Format is hierarchy-ID:controller-list:cgroup-path.
+		parts := strings.SplitN(s.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		path := parts[2]
+		if path == "/" || path == "/init.scope" {
+			continue
+		}
+		return path
+	}
+	return ""
+}
+
+// lxcName returns the container name from an LXC cgroup path like
+// /lxc/<name> or /lxc.payload.<name>.
+func lxcName(path string) string {
+	for _, prefix := range []string{"/lxc/", "/lxc.payload."} {
+		if idx := strings.Index(path, prefix); idx >= 0 {
+			name := path[idx+len(prefix):]
+			if end := strings.IndexByte(name, '/'); end >= 0 {
+				name = name[:end]
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// mountinfoContainerID looks for a container ID in the mount sources of
+// /etc/hostname, /etc/hosts, or /etc/resolv.conf.
+func mountinfoContainerID(data []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		// Fields are: ID parent major:minor root mount-point ...
+		fields := strings.Fields(s.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		switch fields[4] {
+		case "/etc/hostname", "/etc/hosts", "/etc/resolv.conf":
+			if id := containerIDRegexp.FindString(fields[3]); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
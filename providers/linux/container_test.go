@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
 )
 
 const nonContainerizedCgroup = `11:freezer:/
@@ -87,3 +89,48 @@ func TestIsContainerized(t *testing.T) {
 	}
 	assert.True(t, containerized)
 }
+
+const kubepodsCgroup = `12:pids:/kubepods/burstable/pod5f0b2f5a-4c1e-4f6e-9a5d-1f1d2e3c4b5a/crio-3c1c8536d5d2ab9d5cfcd9c87b6e4eebb5a742e9d3aee66f8f8a0c6bd71798c2.scope
+1:name=systemd:/kubepods/burstable/pod5f0b2f5a-4c1e-4f6e-9a5d-1f1d2e3c4b5a/crio-3c1c8536d5d2ab9d5cfcd9c87b6e4eebb5a742e9d3aee66f8f8a0c6bd71798c2.scope
+`
+
+const lxcCgroup = `0::/lxc.payload.web01/init.scope
+`
+
+const privateCgroupNamespace = `0::/
+`
+
+const dockerMountinfo = `1020 1001 0:50 / / rw,relatime master:300 - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/ABC
+1031 1020 8:1 /var/lib/docker/containers/81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60/resolv.conf /etc/resolv.conf rw,relatime - ext4 /dev/sda1 rw
+1032 1020 8:1 /var/lib/docker/containers/81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60/hostname /etc/hostname rw,relatime - ext4 /dev/sda1 rw
+`
+
+func TestContainerInfo(t *testing.T) {
+	noFiles := func(string) bool { return false }
+
+	assert.Nil(t, containerInfo([]byte(nonContainerizedCgroup), nil, noFiles))
+
+	assert.Equal(t, &types.ContainerInfo{
+		Runtime:    types.ContainerRuntimeDocker,
+		ID:         "81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60",
+		CgroupPath: "/docker/81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60",
+	}, containerInfo([]byte(containerCgroup), nil, noFiles))
+
+	assert.Equal(t, &types.ContainerInfo{
+		Runtime:    types.ContainerRuntimeCRIO,
+		ID:         "3c1c8536d5d2ab9d5cfcd9c87b6e4eebb5a742e9d3aee66f8f8a0c6bd71798c2",
+		CgroupPath: "/kubepods/burstable/pod5f0b2f5a-4c1e-4f6e-9a5d-1f1d2e3c4b5a/crio-3c1c8536d5d2ab9d5cfcd9c87b6e4eebb5a742e9d3aee66f8f8a0c6bd71798c2.scope",
+	}, containerInfo([]byte(kubepodsCgroup), nil, noFiles))
+
+	assert.Equal(t, &types.ContainerInfo{
+		Runtime:    types.ContainerRuntimeLXC,
+		ID:         "web01",
+		CgroupPath: "/lxc.payload.web01/init.scope",
+	}, containerInfo([]byte(lxcCgroup), nil, noFiles))
+
+	dockerEnv := func(path string) bool { return path == dockerEnvFile }
+	assert.Equal(t, &types.ContainerInfo{
+		Runtime: types.ContainerRuntimeDocker,
+		ID:      "81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60",
+	}, containerInfo([]byte(privateCgroupNamespace), []byte(dockerMountinfo), dockerEnv))
+}
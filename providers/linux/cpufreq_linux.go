@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func (h *host) CPUFrequency() ([]types.CPUFrequencyInfo, error) {
+	freqs, err := readCPUFreq(h.fs, "sys/devices/system/cpu")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(freqs) > 0 {
+		return freqs, nil
+	}
+
+	// Without a cpufreq driver (e.g. in most VMs) only the frequency
+	// measured by the kernel at boot is available.
+	content, err := h.fs.ReadFile("proc/cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+	return parseCPUInfoFrequencies(content)
+}
+
+// readCPUFreq reads the cpufreq policy of each CPU. Frequencies are in kHz.
+// CPUs without a cpufreq directory are omitted.
+func readCPUFreq(fs fileSystem, dir string) ([]types.CPUFrequencyInfo, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var freqs []types.CPUFrequencyInfo
+	for _, e := range entries {
+		if !cpuDirRegexp.MatchString(e.Name()) {
+			continue
+		}
+		cpu, _ := strconv.Atoi(strings.TrimPrefix(e.Name(), "cpu"))
+
+		read := func(name string) string {
+			v, err := fs.ReadFile(path.Join(dir, e.Name(), "cpufreq", name))
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSpace(string(v))
+		}
+		kHz := func(name string) uint64 {
+			v, _ := strconv.ParseUint(read(name), 10, 64)
+			return v * 1000
+		}
+
+		f := types.CPUFrequencyInfo{
+			CPU:      cpu,
+			Current:  kHz("scaling_cur_freq"),
+			Min:      kHz("scaling_min_freq"),
+			Max:      kHz("scaling_max_freq"),
+			Governor: read("scaling_governor"),
+			Driver:   read("scaling_driver"),
+		}
+		if f.Current == 0 {
+			// Some drivers only provide the hardware frequency.
+			f.Current = kHz("cpuinfo_cur_freq")
+		}
+		if f.Current == 0 {
+			continue
+		}
+		freqs = append(freqs, f)
+	}
+
+	sort.Slice(freqs, func(i, j int) bool { return freqs[i].CPU < freqs[j].CPU })
+	return freqs, nil
+}
+
+// parseCPUInfoFrequencies returns the "cpu MHz" value of each processor in
+// /proc/cpuinfo.
+func parseCPUInfoFrequencies(content []byte) ([]types.CPUFrequencyInfo, error) {
+	var freqs []types.CPUFrequencyInfo
+	cpu := -1
+	err := parseKeyValue(content, ":", func(key, value []byte) error {
+		switch strings.TrimSpace(string(key)) {
+		case "processor":
+			cpu, _ = strconv.Atoi(string(value))
+		case "cpu MHz":
+			mhz, err := strconv.ParseFloat(string(value), 64)
+			if err != nil {
+				return err
+			}
+			freqs = append(freqs, types.CPUFrequencyInfo{
+				CPU:     cpu,
+				Current: uint64(mhz * 1000000),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return freqs, nil
+}
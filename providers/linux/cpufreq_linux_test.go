@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.CPUFrequency = (*host)(nil)
+
+func TestCPUFrequency(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	freqs, err := host.(types.CPUFrequency).CPUFrequency()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.CPUFrequencyInfo{
+		{CPU: 0, Current: 2400000000, Min: 1200000000, Max: 3000000000, Governor: "powersave", Driver: "intel_pstate"},
+		{CPU: 1, Current: 2500000000, Min: 1200000000, Max: 3000000000, Governor: "powersave", Driver: "intel_pstate"},
+	}, freqs)
+}
+
+func TestParseCPUInfoFrequencies(t *testing.T) {
+	const cpuinfo = "processor\t: 0\ncpu MHz\t\t: 2394.442\n\nprocessor\t: 1\ncpu MHz\t\t: 2400.000\n"
+
+	freqs, err := parseCPUInfoFrequencies([]byte(cpuinfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.CPUFrequencyInfo{
+		{CPU: 0, Current: 2394442000},
+		{CPU: 1, Current: 2400000000},
+	}, freqs)
+}
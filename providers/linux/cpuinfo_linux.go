@@ -0,0 +1,191 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// armImplementers maps the "CPU implementer" values of ARM processors to
+// vendor names.
+var armImplementers = map[string]string{
+	"0x41": "ARM",
+	"0x42": "Broadcom",
+	"0x43": "Cavium",
+	"0x46": "Fujitsu",
+	"0x48": "HiSilicon",
+	"0x4e": "NVIDIA",
+	"0x50": "APM",
+	"0x51": "Qualcomm",
+	"0x61": "Apple",
+	"0xc0": "Ampere",
+}
+
+var cpuDirRegexp = regexp.MustCompile(`^cpu[0-9]+$`)
+
+// CPUInfo returns the processor model and topology. The result is cached in
+// shared.StaticCache.
+func (h *host) CPUInfo() (*types.ProcessorInfo, error) {
+	v, err := h.cache.Get(h.cacheKey("cpuinfo"), func() (interface{}, error) {
+		return h.readCPUInfo()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.CopyProcessorInfo(v.(*types.ProcessorInfo)), nil
+}
+
+func (h *host) readCPUInfo() (*types.ProcessorInfo, error) {
+	content, err := h.fs.ReadFile("proc/cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := parseCPUInfo(content)
+	if err != nil {
+		return nil, err
+	}
+
+	readCPUTopology(h.fs, "sys/devices/system/cpu", info)
+	return info, nil
+}
+
+// parseCPUInfo parses /proc/cpuinfo. The model and flags are taken from the
+// first processor. The socket and core counts are derived from the physical
+// and core IDs which are only reported on x86.
+func parseCPUInfo(content []byte) (*types.ProcessorInfo, error) {
+	info := &types.ProcessorInfo{}
+	sockets := map[string]struct{}{}
+	cores := map[string]struct{}{}
+
+	var physicalID string
+	err := parseKeyValue(content, ":", func(key, value []byte) error {
+		v := string(value)
+		switch strings.TrimSpace(string(key)) {
+		case "processor":
+			info.LogicalCores++
+		case "vendor_id":
+			info.Vendor = v
+		case "CPU implementer":
+			if info.Vendor == "" {
+				info.Vendor = armImplementers[v]
+			}
+		case "model name", "Processor":
+			if info.ModelName == "" {
+				info.ModelName = v
+			}
+		case "flags", "Features":
+			if info.Flags == nil {
+				info.Flags = strings.Fields(v)
+			}
+		case "physical id":
+			physicalID = v
+			sockets[v] = struct{}{}
+		case "core id":
+			cores[physicalID+"/"+v] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info.Sockets = len(sockets)
+	info.PhysicalCores = len(cores)
+	return info, nil
+}
+
+// readCPUTopology adds the socket and core counts (unless already known),
+// the frequencies, and the caches from the sysfs directory dir of fs. Missing
+// files are ignored because their availability depends on the architecture
+// and drivers.
+func readCPUTopology(fs fileSystem, dir string, info *types.ProcessorInfo) {
+	read := func(elem ...string) string {
+		return readAttr(fs, path.Join(append([]string{dir}, elem...)...))
+	}
+
+	if entries, err := fs.ReadDir(dir); err == nil && info.PhysicalCores == 0 {
+		sockets := map[string]struct{}{}
+		cores := map[string]struct{}{}
+		for _, e := range entries {
+			if !cpuDirRegexp.MatchString(e.Name()) {
+				continue
+			}
+			pkg := read(e.Name(), "topology/physical_package_id")
+			core := read(e.Name(), "topology/core_id")
+			if pkg == "" || core == "" {
+				continue
+			}
+			sockets[pkg] = struct{}{}
+			cores[pkg+"/"+core] = struct{}{}
+		}
+		info.Sockets = len(sockets)
+		info.PhysicalCores = len(cores)
+	}
+
+	// Frequencies are in kHz. base_frequency is only provided by
+	// intel_pstate.
+	if v, err := strconv.ParseUint(read("cpu0/cpufreq/base_frequency"), 10, 64); err == nil {
+		info.BaseFrequency = v * 1000
+	}
+	if v, err := strconv.ParseUint(read("cpu0/cpufreq/cpuinfo_max_freq"), 10, 64); err == nil {
+		info.MaxFrequency = v * 1000
+	}
+
+	indexes, _ := fs.Glob(path.Join(dir, "cpu0/cache/index*"))
+	sort.Strings(indexes)
+	for _, index := range indexes {
+		name := path.Base(index)
+		level, err := strconv.Atoi(read("cpu0/cache", name, "level"))
+		if err != nil {
+			continue
+		}
+		size, err := parseCacheSize(read("cpu0/cache", name, "size"))
+		if err != nil {
+			continue
+		}
+		info.Caches = append(info.Caches, types.CPUCacheInfo{
+			Level: level,
+			Type:  strings.ToLower(read("cpu0/cache", name, "type")),
+			Size:  size,
+		})
+	}
+}
+
+// parseCacheSize parses cache sizes like 32K or 8192K.
+func parseCacheSize(s string) (uint64, error) {
+	multiplier := uint64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1 << 20
+	}
+	v, err := strconv.ParseUint(strings.TrimRight(s, "KM"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * multiplier, nil
+}
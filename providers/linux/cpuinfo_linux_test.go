@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.CPUInfo = (*host)(nil)
+
+func TestCPUInfo(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := host.(types.CPUInfo).CPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "GenuineIntel", info.Vendor)
+	assert.Equal(t, "Intel(R) Xeon(R) CPU E5-2676 v3 @ 2.40GHz", info.ModelName)
+	assert.Equal(t, 1, info.Sockets)
+	assert.Equal(t, 2, info.PhysicalCores)
+	assert.Equal(t, 2, info.LogicalCores)
+	assert.EqualValues(t, 0, info.BaseFrequency)
+	assert.EqualValues(t, uint64(3000000000), info.MaxFrequency)
+	assert.Contains(t, info.Flags, "avx2")
+	assert.Equal(t, []types.CPUCacheInfo{
+		{Level: 1, Type: types.CPUCacheData, Size: 32 << 10},
+		{Level: 1, Type: types.CPUCacheInstruction, Size: 32 << 10},
+		{Level: 2, Type: types.CPUCacheUnified, Size: 256 << 10},
+		{Level: 3, Type: types.CPUCacheUnified, Size: 30720 << 10},
+	}, info.Caches)
+}
+
+func TestParseCPUInfoARM(t *testing.T) {
+	const cpuinfo = `processor	: 0
+BogoMIPS	: 243.75
+Features	: fp asimd evtstrm aes pmull sha1 sha2 crc32 atomics fphp asimdhp cpuid asimdrdm lrcpc dcpop asimddp ssbs
+CPU implementer	: 0x41
+CPU architecture: 8
+CPU variant	: 0x3
+CPU part	: 0xd0c
+CPU revision	: 1
+
+processor	: 1
+BogoMIPS	: 243.75
+Features	: fp asimd evtstrm aes pmull sha1 sha2 crc32 atomics fphp asimdhp cpuid asimdrdm lrcpc dcpop asimddp ssbs
+CPU implementer	: 0x41
+CPU architecture: 8
+CPU variant	: 0x3
+CPU part	: 0xd0c
+CPU revision	: 1
+`
+
+	info, err := parseCPUInfo([]byte(cpuinfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "ARM", info.Vendor)
+	assert.Equal(t, 2, info.LogicalCores)
+	assert.Zero(t, info.PhysicalCores)
+	assert.Contains(t, info.Flags, "asimd")
+
+	// The core counts come from sysfs when cpuinfo lacks them.
+	readCPUTopology(dirFS("testdata/ubuntu1710"), "sys/devices/system/cpu", info)
+	assert.Equal(t, 1, info.Sockets)
+	assert.Equal(t, 2, info.PhysicalCores)
+}
+
+func TestParseCacheSize(t *testing.T) {
+	for s, expected := range map[string]uint64{"32K": 32 << 10, "2M": 2 << 20, "512": 512} {
+		size, err := parseCacheSize(s)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, size, s)
+		}
+	}
+}
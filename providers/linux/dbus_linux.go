@@ -0,0 +1,361 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// This file contains a minimal D-Bus client that is only capable of issuing
+// method calls without arguments and decoding the replies of the systemd
+// manager. See https://dbus.freedesktop.org/doc/dbus-specification.html.
+
+const (
+	dbusMethodCall   = 1
+	dbusMethodReturn = 2
+	dbusError        = 3
+
+	// Header field codes.
+	dbusFieldPath        = 1
+	dbusFieldInterface   = 2
+	dbusFieldMember      = 3
+	dbusFieldErrorName   = 4
+	dbusFieldReplySerial = 5
+	dbusFieldDestination = 6
+	dbusFieldSignature   = 8
+
+	dbusTimeout = 5 * time.Second
+)
+
+// dbusConn is a connection to a message bus.
+type dbusConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	serial uint32
+}
+
+// dialDBus connects to the bus listening on the Unix socket at path and
+// authenticates using the EXTERNAL mechanism.
+func dialDBus(path string) (*dbusConn, error) {
+	conn, err := net.DialTimeout("unix", path, dbusTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(dbusTimeout))
+
+	c := &dbusConn{conn: conn, r: bufio.NewReader(conn)}
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err = io.WriteString(conn, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		conn.Close()
+		return nil, errors.Errorf("dbus authentication failed: %v", strings.TrimSpace(line))
+	}
+	if _, err = io.WriteString(conn, "BEGIN\r\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Every connection to a message bus must first register itself.
+	if _, err = c.call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *dbusConn) Close() error {
+	return c.conn.Close()
+}
+
+// call invokes a method that takes no arguments and returns the reply.
+func (c *dbusConn) call(dest, path, iface, member string) (*dbusMessage, error) {
+	c.serial++
+	if _, err := c.conn.Write(encodeDBusCall(c.serial, dest, path, iface, member)); err != nil {
+		return nil, err
+	}
+
+	for {
+		msg, err := readDBusMessage(c.r)
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip signals and other messages that are not the reply.
+		if msg.replySerial != c.serial {
+			continue
+		}
+		if msg.typ == dbusError {
+			text := msg.errorName
+			if msg.signature == "s" {
+				if s, err := newDBusDecoder(msg.body).string(); err == nil {
+					text += ": " + s
+				}
+			}
+			return nil, errors.Errorf("dbus call %v.%v failed: %v", iface, member, text)
+		}
+		return msg, nil
+	}
+}
+
+// dbusMessage is a decoded method return or error message.
+type dbusMessage struct {
+	typ         byte
+	replySerial uint32
+	errorName   string
+	signature   string
+	body        []byte
+}
+
+// encodeDBusCall returns a method call message without a body.
+func encodeDBusCall(serial uint32, dest, path, iface, member string) []byte {
+	e := &dbusEncoder{}
+	e.byte('l')
+	e.byte(dbusMethodCall)
+	e.byte(0) // Flags.
+	e.byte(1) // Protocol version.
+	e.uint32(0)
+	e.uint32(serial)
+
+	fields := &dbusEncoder{offset: 16}
+	fields.field(dbusFieldPath, "o", path)
+	fields.field(dbusFieldInterface, "s", iface)
+	fields.field(dbusFieldMember, "s", member)
+	fields.field(dbusFieldDestination, "s", dest)
+
+	e.uint32(uint32(len(fields.buf)))
+	e.buf = append(e.buf, fields.buf...)
+	e.align(8)
+	return e.buf
+}
+
+// readDBusMessage reads a little or big endian message from r.
+func readDBusMessage(r io.Reader) (*dbusMessage, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if fixed[0] == 'B' {
+		order = binary.BigEndian
+	}
+	bodyLen := order.Uint32(fixed[4:])
+	fieldsLen := order.Uint32(fixed[12:])
+
+	// The header is padded to a multiple of 8 bytes.
+	headerLen := 16 + fieldsLen
+	headerLen += (8 - headerLen%8) % 8
+	if headerLen+bodyLen > 128<<20 {
+		return nil, errors.New("dbus message is too large")
+	}
+
+	data := make([]byte, headerLen+bodyLen)
+	copy(data, fixed[:])
+	if _, err := io.ReadFull(r, data[16:]); err != nil {
+		return nil, err
+	}
+
+	msg := &dbusMessage{typ: fixed[1], body: data[headerLen:]}
+	d := &dbusDecoder{data: data[:16+fieldsLen], order: order, pos: 16}
+	for d.pos < len(d.data) {
+		d.align(8)
+		code, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := d.signature()
+		if err != nil {
+			return nil, err
+		}
+
+		switch sig {
+		case "s", "o":
+			s, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			if code == dbusFieldErrorName {
+				msg.errorName = s
+			}
+		case "g":
+			s, err := d.signature()
+			if err != nil {
+				return nil, err
+			}
+			if code == dbusFieldSignature {
+				msg.signature = s
+			}
+		case "u":
+			v, err := d.uint32()
+			if err != nil {
+				return nil, err
+			}
+			if code == dbusFieldReplySerial {
+				msg.replySerial = v
+			}
+		default:
+			return nil, errors.Errorf("unexpected dbus header field type %v", sig)
+		}
+	}
+	return msg, nil
+}
+
+// dbusEncoder marshals values in little endian byte order. Offset is the
+// position of buf within the message which is needed for alignment.
+type dbusEncoder struct {
+	buf    []byte
+	offset int
+}
+
+func (e *dbusEncoder) align(n int) {
+	for (e.offset+len(e.buf))%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *dbusEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *dbusEncoder) uint32(v uint32) {
+	e.align(4)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *dbusEncoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+func (e *dbusEncoder) signature(s string) {
+	e.byte(byte(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+// field appends a header field whose value is a string-like variant.
+func (e *dbusEncoder) field(code byte, sig, value string) {
+	e.align(8)
+	e.byte(code)
+	e.signature(sig)
+	if sig == "g" {
+		e.signature(value)
+	} else {
+		e.string(value)
+	}
+}
+
+// dbusDecoder unmarshals values from a message body.
+type dbusDecoder struct {
+	data  []byte
+	order binary.ByteOrder
+	pos   int
+}
+
+func newDBusDecoder(body []byte) *dbusDecoder {
+	return &dbusDecoder{data: body, order: binary.LittleEndian}
+}
+
+func (d *dbusDecoder) align(n int) {
+	d.pos += (n - d.pos%n) % n
+}
+
+func (d *dbusDecoder) byte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	d.pos++
+	return d.data[d.pos-1], nil
+}
+
+func (d *dbusDecoder) uint32() (uint32, error) {
+	d.align(4)
+	if d.pos+4 > len(d.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	d.pos += 4
+	return d.order.Uint32(d.data[d.pos-4:]), nil
+}
+
+func (d *dbusDecoder) string() (string, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return "", err
+	}
+	return d.text(int(n))
+}
+
+func (d *dbusDecoder) signature() (string, error) {
+	n, err := d.byte()
+	if err != nil {
+		return "", err
+	}
+	return d.text(int(n))
+}
+
+// text returns the n bytes at the current position and skips the NUL
+// terminator.
+func (d *dbusDecoder) text(n int) (string, error) {
+	if n < 0 || d.pos+n+1 > len(d.data) {
+		return "", io.ErrUnexpectedEOF
+	}
+	s := string(d.data[d.pos : d.pos+n])
+	d.pos += n + 1
+	return s, nil
+}
+
+// array calls fn for each element of an array whose elements are aligned to
+// align bytes.
+func (d *dbusDecoder) array(align int, fn func() error) error {
+	n, err := d.uint32()
+	if err != nil {
+		return err
+	}
+	d.align(align)
+	end := d.pos + int(n)
+	if end > len(d.data) {
+		return io.ErrUnexpectedEOF
+	}
+	for d.pos < end {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
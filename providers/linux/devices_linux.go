@@ -0,0 +1,159 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// pciIDsPaths and usbIDsPaths are the locations of the ID databases used by
+// the common distributions (hwdata on Red Hat, pciutils and usbutils on
+// others).
+var (
+	pciIDsPaths = []string{
+		"usr/share/hwdata/pci.ids",
+		"usr/share/misc/pci.ids",
+		"usr/share/pci.ids",
+	}
+	usbIDsPaths = []string{
+		"usr/share/hwdata/usb.ids",
+		"usr/share/misc/usb.ids",
+		"usr/share/usb.ids",
+	}
+)
+
+// Devices lists the PCI and USB devices in /sys/bus/pci/devices and
+// /sys/bus/usb/devices.
+func (h *host) Devices() ([]types.DeviceInfo, error) {
+	return readDevices(filepath.Dir(string(h.procFS)))
+}
+
+func readDevices(root string) ([]types.DeviceInfo, error) {
+	pci, err := filepath.Glob(filepath.Join(root, "sys/bus/pci/devices/*"))
+	if err != nil {
+		return nil, err
+	}
+	usb, err := filepath.Glob(filepath.Join(root, "sys/bus/usb/devices/*"))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]types.DeviceInfo, 0, len(pci)+len(usb))
+	for _, dir := range pci {
+		devices = append(devices, readPCIDevice(root, dir))
+	}
+	for _, dir := range usb {
+		// Skip the interfaces of a device (e.g. 1-1:1.0).
+		if strings.Contains(filepath.Base(dir), ":") {
+			continue
+		}
+		devices = append(devices, readUSBDevice(root, dir))
+	}
+
+	// The parent is the directory that contains the device in
+	// /sys/devices. Only keep parents that are devices themselves (e.g. not
+	// pci0000:00).
+	ids := make(map[string]struct{}, len(devices))
+	for _, d := range devices {
+		ids[d.ID] = struct{}{}
+	}
+	for i := range devices {
+		if _, found := ids[devices[i].Parent]; !found {
+			devices[i].Parent = ""
+		}
+	}
+	return devices, nil
+}
+
+func readPCIDevice(root, dir string) types.DeviceInfo {
+	dev := types.DeviceInfo{
+		Bus:       types.DeviceBusPCI,
+		ID:        filepath.Base(dir),
+		Parent:    sysfsParent(dir),
+		VendorID:  strings.TrimPrefix(readSysfsString(filepath.Join(dir, "vendor")), "0x"),
+		ProductID: strings.TrimPrefix(readSysfsString(filepath.Join(dir, "device")), "0x"),
+		Class:     strings.TrimPrefix(readSysfsString(filepath.Join(dir, "class")), "0x"),
+		Driver:    sysfsDriver(dir),
+	}
+	if dev.VendorID != "" {
+		dev.Vendor, dev.Product = lookupHardwareIDs(root, pciIDsPaths, dev.VendorID, dev.ProductID)
+	}
+	return dev
+}
+
+func readUSBDevice(root, dir string) types.DeviceInfo {
+	dev := types.DeviceInfo{
+		Bus:       types.DeviceBusUSB,
+		ID:        filepath.Base(dir),
+		Parent:    sysfsParent(dir),
+		VendorID:  readSysfsString(filepath.Join(dir, "idVendor")),
+		ProductID: readSysfsString(filepath.Join(dir, "idProduct")),
+		Vendor:    readSysfsString(filepath.Join(dir, "manufacturer")),
+		Product:   readSysfsString(filepath.Join(dir, "product")),
+		Class:     readSysfsString(filepath.Join(dir, "bDeviceClass")),
+		Driver:    sysfsDriver(dir),
+		Serial:    readSysfsString(filepath.Join(dir, "serial")),
+	}
+	// The strings descriptors are optional.
+	if (dev.Vendor == "" || dev.Product == "") && dev.VendorID != "" {
+		vendor, product := lookupHardwareIDs(root, usbIDsPaths, dev.VendorID, dev.ProductID)
+		if dev.Vendor == "" {
+			dev.Vendor = vendor
+		}
+		if dev.Product == "" {
+			dev.Product = product
+		}
+	}
+	return dev
+}
+
+// sysfsParent returns the name of the directory that contains the device.
+func sysfsParent(dir string) string {
+	path, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(filepath.Dir(path))
+}
+
+// sysfsDriver returns the name of the driver bound to the device.
+func sysfsDriver(dir string) string {
+	driver, err := os.Readlink(filepath.Join(dir, "driver"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(driver)
+}
+
+// lookupHardwareIDs resolves the IDs using the first ID database found.
+func lookupHardwareIDs(root string, paths []string, vendorID, deviceID string) (vendor, device string) {
+	for _, path := range paths {
+		f, err := os.Open(filepath.Join(root, path))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		return shared.LookupHardwareIDs(f, vendorID, deviceID)
+	}
+	return "", ""
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Devices = (*host)(nil)
+
+func TestReadDevices(t *testing.T) {
+	devices, err := readDevices("testdata/ubuntu1710")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.DeviceInfo{
+		{
+			Bus:       types.DeviceBusPCI,
+			ID:        "0000:00:02.0",
+			VendorID:  "8086",
+			ProductID: "5916",
+			Vendor:    "Intel Corporation",
+			Product:   "HD Graphics 620",
+			Class:     "030000",
+			Driver:    "i915",
+		},
+		{
+			Bus:       types.DeviceBusPCI,
+			ID:        "0000:00:14.0",
+			VendorID:  "8086",
+			ProductID: "9d2f",
+			Vendor:    "Intel Corporation",
+			Product:   "Sunrise Point-LP USB 3.0 xHCI Controller",
+			Class:     "0c0330",
+			Driver:    "xhci_hcd",
+		},
+		{
+			Bus:       types.DeviceBusUSB,
+			ID:        "1-1",
+			Parent:    "usb1",
+			VendorID:  "046d",
+			ProductID: "c52b",
+			Vendor:    "Logitech",
+			Product:   "USB Receiver",
+			Class:     "00",
+			Driver:    "usb",
+		},
+		{
+			Bus:       types.DeviceBusUSB,
+			ID:        "1-2",
+			Parent:    "usb1",
+			VendorID:  "046d",
+			ProductID: "c077",
+			Vendor:    "Logitech, Inc.",
+			Product:   "M105 Optical Mouse",
+			Class:     "00",
+			Driver:    "usb",
+		},
+		{
+			Bus:       types.DeviceBusUSB,
+			ID:        "usb1",
+			Parent:    "0000:00:14.0",
+			VendorID:  "1d6b",
+			ProductID: "0002",
+			Vendor:    "Linux 4.13.0-16-generic xhci-hcd",
+			Product:   "xHCI Host Controller",
+			Class:     "09",
+			Driver:    "usb",
+			Serial:    "0000:00:14.0",
+		},
+	}, devices)
+}
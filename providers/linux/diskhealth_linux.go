@@ -0,0 +1,276 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from scsi/sg.h and linux/nvme_ioctl.h.
+const (
+	sgIO           = 0x2285 // SG_IO
+	sgDxferNone    = -1     // SG_DXFER_NONE
+	sgDxferFromDev = -3     // SG_DXFER_FROM_DEV
+	sgTimeoutMs    = 5000
+
+	nvmeIoctlAdminCmd     = 0xc0484e41 // NVME_IOCTL_ADMIN_CMD
+	nvmeAdminGetLogPage   = 0x02
+	nvmeNamespaceAll      = 0xffffffff
+	ataPassThrough16      = 0x85 // ATA PASS-THROUGH(16) SCSI command.
+	senseDescriptorATA    = 0x09 // ATA Status Return sense data descriptor.
+	senseFormatDescriptor = 0x72 // Current errors in descriptor format.
+)
+
+// sgIOHdr is Go's counterpart of the sg_io_hdr struct.
+type sgIOHdr struct {
+	InterfaceID    int32
+	DxferDirection int32
+	CmdLen         uint8
+	MxSbLen        uint8
+	IovecCount     uint16
+	DxferLen       uint32
+	Dxferp         unsafe.Pointer
+	Cmdp           unsafe.Pointer
+	Sbp            unsafe.Pointer
+	Timeout        uint32
+	Flags          uint32
+	PackID         int32
+	UsrPtr         unsafe.Pointer
+	Status         uint8
+	MaskedStatus   uint8
+	MsgStatus      uint8
+	SbLenWr        uint8
+	HostStatus     uint16
+	DriverStatus   uint16
+	Resid          int32
+	Duration       uint32
+	Info           uint32
+}
+
+// nvmeAdminCmd is Go's counterpart of the nvme_admin_cmd struct.
+type nvmeAdminCmd struct {
+	Opcode      uint8
+	Flags       uint8
+	Rsvd1       uint16
+	NSID        uint32
+	CDW2        uint32
+	CDW3        uint32
+	Metadata    uint64
+	Addr        uint64
+	MetadataLen uint32
+	DataLen     uint32
+	CDW10       uint32
+	CDW11       uint32
+	CDW12       uint32
+	CDW13       uint32
+	CDW14       uint32
+	CDW15       uint32
+	TimeoutMs   uint32
+	Result      uint32
+}
+
+// DiskHealth reads the SMART data of NVMe controllers with the Get Log Page
+// admin command and of SATA disks with ATA pass-through over SG_IO. Both
+// require root. Other disks, such as SCSI or virtual disks, are not
+// reported.
+func (h *host) DiskHealth() ([]types.DiskHealthInfo, error) {
+	return readDiskHealth(h.fs)
+}
+
+func readDiskHealth(fs fileSystem) ([]types.DiskHealthInfo, error) {
+	var disks []types.DiskHealthInfo
+
+	controllers, err := fs.Glob("sys/class/nvme/nvme*")
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range controllers {
+		info := types.DiskHealthInfo{
+			Name:     path.Base(dir),
+			Model:    readAttr(fs, path.Join(dir, "model")),
+			Serial:   readAttr(fs, path.Join(dir, "serial")),
+			Protocol: types.DiskProtocolNVMe,
+		}
+		if data, err := nvmeSMARTLog(fs, path.Join("dev", info.Name)); err == nil {
+			shared.ParseNVMeSMARTLog(data, &info)
+		}
+		disks = append(disks, info)
+	}
+
+	blockDevices, err := fs.Glob("sys/block/sd*")
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range blockDevices {
+		// libata reports ATA as the SCSI vendor of the disks it translates.
+		if readAttr(fs, path.Join(dir, "device/vendor")) != "ATA" {
+			continue
+		}
+		info := types.DiskHealthInfo{
+			Name:     path.Base(dir),
+			Model:    readAttr(fs, path.Join(dir, "device/model")),
+			Protocol: types.DiskProtocolATA,
+		}
+		if vpd, err := fs.ReadFile(path.Join(dir, "device/vpd_pg80")); err == nil {
+			info.Serial = parseVPDSerial(vpd)
+		}
+		readATASMART(fs, path.Join("dev", info.Name), &info)
+		disks = append(disks, info)
+	}
+
+	return disks, nil
+}
+
+// parseVPDSerial returns the serial number from the Unit Serial Number VPD
+// page (0x80).
+func parseVPDSerial(vpd []byte) string {
+	if len(vpd) < 4 || vpd[1] != 0x80 {
+		return ""
+	}
+	n := int(vpd[2])<<8 | int(vpd[3])
+	if n > len(vpd)-4 {
+		n = len(vpd) - 4
+	}
+	return strings.TrimSpace(string(bytes.TrimRight(vpd[4:4+n], "\x00")))
+}
+
+// nvmeSMARTLog reads the SMART / Health Information log from the NVMe
+// controller device name of fs.
+func nvmeSMARTLog(fs fileSystem, name string) ([]byte, error) {
+	f, err := openDevice(fs, name, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, shared.SMARTDataSize)
+	cmd := nvmeAdminCmd{
+		Opcode:  nvmeAdminGetLogPage,
+		NSID:    nvmeNamespaceAll,
+		Addr:    uint64(uintptr(unsafe.Pointer(&data[0]))),
+		DataLen: uint32(len(data)),
+		// Number of dwords minus one and the log page identifier.
+		CDW10: uint32(len(data)/4-1)<<16 | shared.NVMeSMARTLogPage,
+	}
+	err = ioctl(f.Fd(), nvmeIoctlAdminCmd, unsafe.Pointer(&cmd))
+	runtime.KeepAlive(data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readATASMART reads the SMART status and attributes of the SATA disk at
+// name of fs.
+func readATASMART(fs fileSystem, name string, info *types.DiskHealthInfo) {
+	f, err := openDevice(fs, name, os.O_RDONLY|syscall.O_NONBLOCK)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	sense := make([]byte, 32)
+	if err := ataPassThrough(f.Fd(), shared.ATASMARTReturnStatus, nil, sense); err == nil {
+		if lbaMid, lbaHigh, ok := parseATAStatusSense(sense); ok {
+			info.Status = shared.ATASMARTStatus(lbaMid, lbaHigh)
+		}
+	}
+
+	data := make([]byte, shared.SMARTDataSize)
+	if err := ataPassThrough(f.Fd(), shared.ATASMARTReadData, data, sense); err == nil {
+		shared.ParseATASMARTData(data, info)
+	}
+}
+
+// ataPassThrough issues a SMART command with the given feature. Commands
+// without data request the ATA registers to be returned in the sense data.
+func ataPassThrough(fd uintptr, feature byte, data, sense []byte) error {
+	cdb := [16]byte{
+		0:  ataPassThrough16,
+		1:  3 << 1, // Non-data protocol.
+		2:  1 << 5, // CK_COND, return the registers.
+		4:  feature,
+		10: shared.ATASMARTLBAMid,
+		12: shared.ATASMARTLBAHigh,
+		14: shared.ATASMARTCommand,
+	}
+	hdr := sgIOHdr{
+		InterfaceID:    'S',
+		DxferDirection: sgDxferNone,
+		CmdLen:         uint8(len(cdb)),
+		MxSbLen:        uint8(len(sense)),
+		Cmdp:           unsafe.Pointer(&cdb[0]),
+		Sbp:            unsafe.Pointer(&sense[0]),
+		Timeout:        sgTimeoutMs,
+	}
+	if len(data) > 0 {
+		cdb[1] = 4 << 1 // PIO data-in protocol.
+		cdb[2] = 0x0e   // T_DIR from device, BYT_BLOK, length in the sector count.
+		cdb[6] = byte(len(data) / shared.SMARTDataSize)
+		hdr.DxferDirection = sgDxferFromDev
+		hdr.DxferLen = uint32(len(data))
+		hdr.Dxferp = unsafe.Pointer(&data[0])
+	}
+
+	if err := ioctl(fd, sgIO, unsafe.Pointer(&hdr)); err != nil {
+		return err
+	}
+	if len(data) > 0 && (hdr.Status != 0 || hdr.HostStatus != 0) {
+		return syscall.EIO
+	}
+	return nil
+}
+
+// parseATAStatusSense returns the LBA mid and high registers from the ATA
+// Status Return descriptor of descriptor format sense data.
+func parseATAStatusSense(sense []byte) (lbaMid, lbaHigh byte, ok bool) {
+	if len(sense) < 8 || sense[0] != senseFormatDescriptor {
+		return 0, 0, false
+	}
+	descs := sense[8:]
+	if n := int(sense[7]); n < len(descs) {
+		descs = descs[:n]
+	}
+	for len(descs) >= 2 {
+		n := 2 + int(descs[1])
+		if n > len(descs) {
+			break
+		}
+		if descs[0] == senseDescriptorATA && n >= 14 {
+			return descs[9], descs[11], true
+		}
+		descs = descs[n:]
+	}
+	return 0, 0, false
+}
+
+func ioctl(fd, request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
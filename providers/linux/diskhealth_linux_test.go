@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.DiskHealth = (*host)(nil)
+
+func TestReadDiskHealth(t *testing.T) {
+	disks, err := readDiskHealth(dirFS("testdata/ubuntu1710"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// There are no device nodes so only the identity is known. The QEMU
+	// disk is not an ATA disk.
+	assert.Equal(t, []types.DiskHealthInfo{
+		{
+			Name:     "nvme0",
+			Model:    "Samsung SSD 970 EVO Plus 500GB",
+			Serial:   "S4EVNF0M123456A",
+			Protocol: types.DiskProtocolNVMe,
+		},
+		{
+			Name:     "sda",
+			Model:    "WDC WD40EFRX-68N",
+			Serial:   "WD-WCC7K1234567",
+			Protocol: types.DiskProtocolATA,
+		},
+	}, disks)
+}
+
+func TestParseATAStatusSense(t *testing.T) {
+	sense := []byte{
+		0x72, 0x01, 0x00, 0x1d, 0x00, 0x00, 0x00, 0x0e,
+		0x09, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x4f, 0x00, 0xc2, 0x00, 0x50,
+	}
+	lbaMid, lbaHigh, ok := parseATAStatusSense(sense)
+	assert.True(t, ok)
+	assert.EqualValues(t, 0x4f, lbaMid)
+	assert.EqualValues(t, 0xc2, lbaHigh)
+
+	// Fixed format sense data.
+	_, _, ok = parseATAStatusSense([]byte{0x70, 0, 5, 0, 0, 0, 0, 10})
+	assert.False(t, ok)
+}
+
+func TestNVMeAdminCmdSize(t *testing.T) {
+	// NVME_IOCTL_ADMIN_CMD encodes the size of the struct.
+	assert.EqualValues(t, 72, unsafe.Sizeof(nvmeAdminCmd{}))
+}
@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// sectorSize is the unit of the sector counts in /proc/diskstats. It is
+// always 512 bytes regardless of the device's sector size.
+const sectorSize = 512
+
+func (h *host) DiskIOCounters() ([]types.DiskIOInfo, error) {
+	content, err := h.fs.ReadFile("proc/diskstats")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDiskStats(content)
+}
+
+// parseDiskStats parses the contents of /proc/diskstats. See the kernel's
+// Documentation/iostats.txt for the format.
+func parseDiskStats(content []byte) ([]types.DiskIOInfo, error) {
+	var disks []types.DiskIOInfo
+
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 14 {
+			return nil, errors.Errorf("unexpected diskstats line: %v", s.Text())
+		}
+
+		// Fields 3 through 13 are counters. Newer kernels append discard and
+		// flush statistics that are not reported.
+		var v [11]uint64
+		for i := range v {
+			n, err := strconv.ParseUint(fields[i+3], 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse diskstats for %v", fields[2])
+			}
+			v[i] = n
+		}
+
+		disks = append(disks, types.DiskIOInfo{
+			Name:          fields[2],
+			ReadCount:     v[0],
+			ReadBytes:     v[2] * sectorSize,
+			ReadTime:      time.Duration(v[3]) * time.Millisecond,
+			WriteCount:    v[4],
+			WriteBytes:    v[6] * sectorSize,
+			WriteTime:     time.Duration(v[7]) * time.Millisecond,
+			IOsInProgress: v[8],
+			IOTime:        time.Duration(v[9]) * time.Millisecond,
+		})
+	}
+
+	return disks, s.Err()
+}
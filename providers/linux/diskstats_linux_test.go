@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.DiskIOCounters = (*host)(nil)
+
+func TestDiskIOCounters(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disks, err := host.(types.DiskIOCounters).DiskIOCounters()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, disks, 3) {
+		assert.Equal(t, types.DiskIOInfo{
+			Name:       "sda",
+			ReadCount:  65047,
+			WriteCount: 43551,
+			ReadBytes:  3425560 * 512,
+			WriteBytes: 3141680 * 512,
+			ReadTime:   21768 * time.Millisecond,
+			WriteTime:  91520 * time.Millisecond,
+			IOTime:     37104 * time.Millisecond,
+		}, disks[0])
+	}
+}
+
+func TestParseDiskStatsExtendedFields(t *testing.T) {
+	// Kernel 4.18 added discard statistics and 5.5 added flush statistics.
+	const line = " 259       0 nvme0n1 1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17\n"
+	disks, err := parseDiskStats([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, disks, 1) {
+		assert.EqualValues(t, 9, disks[0].IOsInProgress)
+		assert.EqualValues(t, 10*time.Millisecond, disks[0].IOTime)
+	}
+
+	_, err = parseDiskStats([]byte("8 0 sda 1 2 3\n"))
+	assert.Error(t, err)
+}
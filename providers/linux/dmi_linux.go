@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Hardware returns the SMBIOS values. The result is cached in
+// shared.StaticCache.
+func (h *host) Hardware() (*types.HardwareInfo, error) {
+	v, err := h.cache.Get(h.cacheKey("dmi"), func() (interface{}, error) {
+		return readDMI(h.fs, dmiDir)
+	})
+	if err != nil {
+		return nil, err
+	}
+	hw := *v.(*types.HardwareInfo)
+	return &hw, nil
+}
+
+// dmiDir is where the kernel exports the SMBIOS values.
+const dmiDir = "sys/class/dmi/id"
+
+// readDMI reads the SMBIOS values that the kernel exports in
+// /sys/class/dmi/id. The serial number and UUID are only readable by root.
+func readDMI(fs fileSystem, dir string) (*types.HardwareInfo, error) {
+	if _, err := fs.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			// The kernel was built without CONFIG_DMI (e.g. most ARM boards).
+			return nil, types.ErrNotImplemented
+		}
+		return nil, err
+	}
+
+	read := func(name string) string {
+		v, err := fs.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(v))
+	}
+
+	hw := &types.HardwareInfo{
+		Manufacturer:   read("sys_vendor"),
+		ProductName:    read("product_name"),
+		ProductVersion: read("product_version"),
+		SerialNumber:   read("product_serial"),
+		UUID:           read("product_uuid"),
+		BIOSVendor:     read("bios_vendor"),
+		BIOSVersion:    read("bios_version"),
+		BIOSDate:       read("bios_date"),
+	}
+	if v, err := strconv.Atoi(read("chassis_type")); err == nil {
+		hw.ChassisType = shared.ChassisType(v)
+	}
+	return hw, nil
+}
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Hardware = (*host)(nil)
+
+func TestHardware(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hw, err := host.(types.Hardware).Hardware()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &types.HardwareInfo{
+		Manufacturer: "Dell Inc.",
+		ProductName:  "PowerEdge R740",
+		SerialNumber: "ABC1234",
+		UUID:         "4c4c4544-004b-4d10-8035-b4c04f4e4232",
+		BIOSVendor:   "Dell Inc.",
+		BIOSVersion:  "2.4.3",
+		BIOSDate:     "03/17/2017",
+		ChassisType:  "Rack Mount Chassis",
+	}, hw)
+}
+
+func TestHardwareNoDMI(t *testing.T) {
+	_, err := readDMI(dirFS("testdata/ubuntu1710"), "sys/class/dmi/missing")
+	assert.Equal(t, types.ErrNotImplemented, err)
+}
@@ -0,0 +1,190 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const dnsManagerResolved = "systemd-resolved"
+
+// Addresses of the stub resolvers of systemd-resolved.
+var resolvedStubAddrs = map[string]bool{
+	"127.0.0.53": true,
+	"127.0.0.54": true,
+}
+
+// Directories containing the per-link DNS state of systemd-resolved and
+// systemd-networkd. The files are named after the interface index and use
+// different keys for the nameservers.
+var linkDNSStateDirs = []struct {
+	dir             string
+	serversKey      string
+	routeDomainsKey string // Key of the route-only domains (if any).
+}{
+	{"run/systemd/resolve/netif", "SERVERS", ""},
+	{"run/systemd/netif/links", "DNS", "ROUTE_DOMAINS"},
+}
+
+// DNSConfig returns the configuration from /etc/resolv.conf. When the host
+// uses the systemd-resolved stub resolver the per-link configuration of
+// systemd-resolved and systemd-networkd is reported in Resolvers.
+func (h *host) DNSConfig() (*types.DNSConfigInfo, error) {
+	return dnsConfig(h.fs, shared.InterfaceNames())
+}
+
+func dnsConfig(fs fileSystem, names map[int]string) (*types.DNSConfigInfo, error) {
+	info := &types.DNSConfigInfo{}
+
+	content, err := fs.ReadFile("etc/resolv.conf")
+	switch {
+	case err == nil:
+		parseResolvConf(content, info)
+	case !os.IsNotExist(err):
+		return nil, errors.Wrap(err, "failed to read /etc/resolv.conf")
+	}
+
+	for _, ns := range info.Nameservers {
+		if resolvedStubAddrs[ns] {
+			info.Manager = dnsManagerResolved
+			info.Resolvers = linkResolvers(fs, names)
+			break
+		}
+	}
+	return info, nil
+}
+
+// parseResolvConf parses the nameserver, search, domain, and options
+// directives of resolv.conf(5). As in glibc, the last search or domain
+// directive wins.
+func parseResolvConf(content []byte, info *types.DNSConfigInfo) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			info.Nameservers = append(info.Nameservers, fields[1])
+		case "search":
+			info.SearchDomains = fields[1:]
+		case "domain":
+			info.SearchDomains = fields[1:2]
+		case "options":
+			info.Options = append(info.Options, fields[1:]...)
+		}
+	}
+}
+
+// linkResolvers reads the per-link state files of systemd-resolved and
+// systemd-networkd. The entries of both are merged by interface.
+func linkResolvers(fs fileSystem, names map[int]string) []types.DNSResolverInfo {
+	byIndex := map[int]*types.DNSResolverInfo{}
+	for _, src := range linkDNSStateDirs {
+		entries, err := fs.ReadDir(src.dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			index, err := strconv.Atoi(e.Name())
+			if err != nil || e.IsDir() {
+				continue
+			}
+			content, err := fs.ReadFile(path.Join(src.dir, e.Name()))
+			if err != nil {
+				continue
+			}
+
+			r, found := byIndex[index]
+			if !found {
+				r = &types.DNSResolverInfo{Interface: names[index]}
+				if r.Interface == "" {
+					r.Interface = e.Name()
+				}
+				byIndex[index] = r
+			}
+
+			parseKeyValue(content, "=", func(key, value []byte) error {
+				if len(key) == 0 {
+					return nil
+				}
+				switch string(key) {
+				case src.serversKey:
+					for _, server := range strings.Fields(string(value)) {
+						// Strip the server name used for DNS-over-TLS.
+						if i := strings.IndexByte(server, '#'); i >= 0 {
+							server = server[:i]
+						}
+						r.Nameservers = appendUnique(r.Nameservers, server)
+					}
+				case "DOMAINS":
+					for _, domain := range strings.Fields(string(value)) {
+						// Domains prefixed with ~ are only used for routing.
+						if strings.HasPrefix(domain, "~") {
+							r.Domains = appendUnique(r.Domains, domain[1:])
+						} else {
+							r.SearchDomains = appendUnique(r.SearchDomains, domain)
+						}
+					}
+				case src.routeDomainsKey:
+					for _, domain := range strings.Fields(string(value)) {
+						r.Domains = appendUnique(r.Domains, domain)
+					}
+				}
+				return nil
+			})
+		}
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for index, r := range byIndex {
+		if len(r.Nameservers) > 0 || len(r.SearchDomains) > 0 || len(r.Domains) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+
+	resolvers := make([]types.DNSResolverInfo, 0, len(indexes))
+	for _, index := range indexes {
+		resolvers = append(resolvers, *byIndex[index])
+	}
+	return resolvers
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
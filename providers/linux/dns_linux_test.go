@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.DNSConfig = (*host)(nil)
+
+func TestDNSConfig(t *testing.T) {
+	fs := mapFS{
+		"etc/resolv.conf": `# Generated by NetworkManager
+search corp.example.com example.com
+nameserver 10.0.0.2
+nameserver 10.0.0.3
+options ndots:2 timeout:1
+options rotate
+`,
+	}
+
+	info, err := dnsConfig(fs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.DNSConfigInfo{
+		Nameservers:   []string{"10.0.0.2", "10.0.0.3"},
+		SearchDomains: []string{"corp.example.com", "example.com"},
+		Options:       []string{"ndots:2", "timeout:1", "rotate"},
+	}, info)
+
+	info, err = dnsConfig(mapFS{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.DNSConfigInfo{}, info)
+}
+
+func TestDNSConfigResolved(t *testing.T) {
+	fs := mapFS{
+		"etc/resolv.conf":              "nameserver 127.0.0.53\noptions edns0 trust-ad\ndomain lan\n",
+		"run/systemd/resolve/netif/3":  "# This is private data. Do not parse.\nLLMNR=yes\nSERVERS=10.8.0.1#vpn.example.com\nDOMAINS=~corp.example.com\n",
+		"run/systemd/netif/links/2":    "ADMIN_STATE=configured\nDNS=192.168.1.1 fe80::1\nDOMAINS=lan\nROUTE_DOMAINS=home.arpa\n",
+		"run/systemd/netif/links/3":    "DNS=10.8.0.1\n",
+		"run/systemd/netif/links/lock": "",
+	}
+
+	info, err := dnsConfig(fs, map[int]string{2: "eth0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.DNSConfigInfo{
+		Nameservers:   []string{"127.0.0.53"},
+		SearchDomains: []string{"lan"},
+		Options:       []string{"edns0", "trust-ad"},
+		Manager:       "systemd-resolved",
+		Resolvers: []types.DNSResolverInfo{
+			{
+				Interface:     "eth0",
+				Nameservers:   []string{"192.168.1.1", "fe80::1"},
+				SearchDomains: []string{"lan"},
+				Domains:       []string{"home.arpa"},
+			},
+			{
+				Interface:   "3",
+				Nameservers: []string{"10.8.0.1"},
+				Domains:     []string{"corp.example.com"},
+			},
+		},
+	}, info)
+}
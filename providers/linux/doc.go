@@ -17,4 +17,14 @@
 
 // Package linux implements the HostProvider and ProcessProvider interfaces
 // for providing information about Linux.
+//
+// Processes are listed by reading /proc. The eBPF backend, selected with the
+// sysinfo.WithProcessBackend(types.ProcessBackendEBPF) option, lists them
+// with a BPF task iterator instead, which avoids reading the /proc directory
+// on hosts with very large process counts. The program is assembled at
+// runtime using the offsets from the kernel's BTF, so it needs no BPF library
+// or compiler, but it requires Linux 5.8 or newer, CAP_BPF (or
+// CAP_SYS_ADMIN), and the initial PID namespace. The backend falls back to
+// procfs when it cannot be used. The process details are read from
+// /proc/[pid] with both backends.
 package linux
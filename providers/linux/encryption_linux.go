@@ -0,0 +1,95 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"syscall"
+)
+
+// maxBlockDepth limits how many device-mapper layers (e.g. LVM on LUKS) are
+// followed when looking for an encrypted device.
+const maxBlockDepth = 8
+
+// blockDeviceNumber returns the major:minor number of the block device that
+// backs the file system mounted at path. File systems such as btrfs report
+// an anonymous device number, so the device node is used for them instead.
+func blockDeviceNumber(path, devicePath string) string {
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return ""
+	}
+
+	dev := uint64(st.Dev)
+	if devMajor(dev) == 0 && devicePath != "" {
+		var node syscall.Stat_t
+		if err := syscall.Stat(devicePath, &node); err != nil || node.Mode&syscall.S_IFMT != syscall.S_IFBLK {
+			return ""
+		}
+		dev = uint64(node.Rdev)
+	}
+	return fmt.Sprintf("%d:%d", devMajor(dev), devMinor(dev))
+}
+
+// devMajor and devMinor decode a dev_t like the major(3) and minor(3) macros
+// of glibc.
+func devMajor(dev uint64) uint64 { return (dev>>8)&0xfff | (dev>>32)&^0xfff }
+func devMinor(dev uint64) uint64 { return dev&0xff | (dev>>12)&^0xff }
+
+// blockEncryption reports whether the block device with the given
+// major:minor number is a dm-crypt device or is stacked on top of one. It
+// returns nil if the device is unknown to sysfs. The encryption is the
+// lowercase dm-crypt type from the device-mapper UUID (luks1, luks2, plain,
+// tcrypt, bitlk).
+func blockEncryption(fs fileSystem, number string) (*bool, string) {
+	dir := path.Join("sys/dev/block", number)
+	if _, err := fs.Stat(dir); err != nil {
+		return nil, ""
+	}
+
+	encryption := dmCryptType(fs, dir, 0)
+	encrypted := encryption != ""
+	return &encrypted, encryption
+}
+
+// dmCryptType returns the dm-crypt type of the block device in the sysfs
+// directory dir or of the devices it is built from (its slaves).
+func dmCryptType(fs fileSystem, dir string, depth int) string {
+	// cryptsetup sets UUIDs of the form CRYPT-<type>-<uuid>-<name>.
+	if uuid, err := fs.ReadFile(path.Join(dir, "dm/uuid")); err == nil {
+		if parts := strings.SplitN(strings.TrimSpace(string(uuid)), "-", 3); len(parts) >= 2 && parts[0] == "CRYPT" {
+			return strings.ToLower(parts[1])
+		}
+	}
+
+	if depth >= maxBlockDepth {
+		return ""
+	}
+	slaves, err := fs.ReadDir(path.Join(dir, "slaves"))
+	if err != nil {
+		return ""
+	}
+	for _, slave := range slaves {
+		if t := dmCryptType(fs, path.Join("sys/class/block", slave.Name()), depth+1); t != "" {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockEncryption(t *testing.T) {
+	// LVM logical volume (dm-1) in a LUKS2 container (dm-0) on sda3.
+	fs := mapFS{
+		"sys/dev/block/8:1/size":           "1048576",
+		"sys/dev/block/253:1/dm/uuid":      "LVM-7lfv6QXZBWtz0USu9yepsvSCGgJd7CK2cSoel1uI1bphKxbmqFbWc2b7GilBfdXh",
+		"sys/dev/block/253:1/slaves/dm-0":  "",
+		"sys/class/block/dm-0/dm/uuid":     "CRYPT-LUKS2-6f62d8e4b2a64bfab3e71a0e6b4e60e5-luks-6f62d8e4-b2a6-4bfa-b3e7-1a0e6b4e60e5",
+		"sys/class/block/dm-0/slaves/sda3": "",
+		"sys/class/block/sda3/size":        "497664000",
+	}
+
+	encrypted, encryption := blockEncryption(fs, "253:1")
+	if assert.NotNil(t, encrypted) {
+		assert.True(t, *encrypted)
+	}
+	assert.Equal(t, "luks2", encryption)
+
+	encrypted, encryption = blockEncryption(fs, "8:1")
+	if assert.NotNil(t, encrypted) {
+		assert.False(t, *encrypted)
+	}
+	assert.Empty(t, encryption)
+
+	encrypted, _ = blockEncryption(fs, "0:20")
+	assert.Nil(t, encrypted)
+}
+
+func TestDevNumber(t *testing.T) {
+	assert.EqualValues(t, 253, devMajor(0xfd01))
+	assert.EqualValues(t, 1, devMinor(0xfd01))
+	assert.EqualValues(t, 259, devMajor(0x10301))
+	assert.EqualValues(t, 1048576, devMinor(0x100000000))
+}
@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// getrandomSyscalls contains the getrandom(2) syscall number of each
+// architecture because the syscall package does not define it everywhere.
+var getrandomSyscalls = map[string]uintptr{
+	"386":      355,
+	"amd64":    318,
+	"arm":      384,
+	"arm64":    278,
+	"mips":     4353,
+	"mipsle":   4353,
+	"mips64":   5313,
+	"mips64le": 5313,
+	"ppc64":    359,
+	"ppc64le":  359,
+	"riscv64":  278,
+	"s390x":    349,
+}
+
+// grndNonblock is GRND_NONBLOCK.
+const grndNonblock = 0x1
+
+// Entropy returns the entropy pool state from /proc/sys/kernel/random and
+// whether the pool is initialized. Readiness is tested with a non-blocking
+// getrandom call that fails with EAGAIN until the pool is initialized.
+func (h *host) Entropy() (*types.EntropyInfo, error) {
+	dir := h.procFS.Path("sys/kernel/random")
+	avail, err := readSysfsInt(filepath.Join(dir, "entropy_avail"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read entropy_avail")
+	}
+	poolSize, _ := readSysfsInt(filepath.Join(dir, "poolsize"))
+
+	return &types.EntropyInfo{
+		Available: int(avail),
+		PoolSize:  int(poolSize),
+		Ready:     getrandomReady(),
+	}, nil
+}
+
+// getrandomReady returns nil if getrandom is not available (kernels before
+// 3.17 or unknown architectures).
+func getrandomReady() *bool {
+	trap, found := getrandomSyscalls[runtime.GOARCH]
+	if !found {
+		return nil
+	}
+
+	var b [1]byte
+	_, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), grndNonblock)
+	var ready bool
+	switch errno {
+	case 0:
+		ready = true
+	case syscall.EAGAIN:
+		ready = false
+	default:
+		return nil
+	}
+	return &ready
+}
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build go1.13
+
+package linux
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// errFS is a fileSystem whose reads fail with err.
+type errFS struct {
+	mapFS
+	err error
+}
+
+func (f errFS) ReadFile(name string) ([]byte, error) {
+	return nil, &os.PathError{Op: "open", Path: name, Err: f.err}
+}
+
+func TestErrorsIsWrappedProviderError(t *testing.T) {
+	// dnsConfig wraps the error with github.com/pkg/errors.
+	_, err := dnsConfig(errFS{err: os.ErrPermission}, nil)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, os.ErrPermission), "errors.Is failed for %v", err)
+		var pathErr *os.PathError
+		assert.True(t, errors.As(err, &pathErr))
+	}
+
+	_, err = newLinuxSystem("testdata/ubuntu1710").Process(99999)
+	assert.True(t, errors.Is(err, types.ErrProcessNotFound), "errors.Is failed for %v", err)
+	var notFound *types.ProcessNotFoundError
+	if assert.True(t, errors.As(err, &notFound)) {
+		assert.Equal(t, 99999, notFound.PID)
+	}
+
+	_, err = readDMI(mapFS{}, dmiDir)
+	assert.True(t, errors.Is(err, types.ErrNotImplemented))
+}
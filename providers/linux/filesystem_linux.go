@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// FileSystems returns the file systems that are mounted in the mount
+// namespace of this process. File systems that cannot be queried with
+// statfs(2) are returned without usage data. When a host FS is used the file
+// systems in the mount namespace of the host's init process are returned
+// instead and they are queried through /proc/1/root, which requires
+// CAP_SYS_PTRACE. File systems on block devices report whether they are
+// encrypted with dm-crypt.
+func (s linuxSystem) FileSystems() ([]types.FileSystemInfo, error) {
+	pid := "self"
+	if s.hostFS != "" {
+		pid = "1"
+	}
+
+	content, err := ioutil.ReadFile(s.procFS.Path(pid, "mountinfo"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read mountinfo")
+	}
+
+	filesystems, err := parseMountInfo(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse mountinfo")
+	}
+
+	for i := range filesystems {
+		fs := &filesystems[i]
+		path, device := fs.MountPoint, fs.Device
+		if !strings.HasPrefix(device, "/dev/") {
+			device = ""
+		}
+		if s.hostFS != "" {
+			path = s.procFS.Path("1", "root", path)
+			if device != "" {
+				device = s.procFS.Path("1", "root", device)
+			}
+		}
+		statFileSystem(fs, path)
+		if number := blockDeviceNumber(path, device); number != "" {
+			fs.Encrypted, fs.Encryption = blockEncryption(s.fs, number)
+		}
+	}
+	return filesystems, nil
+}
+
+// parseMountInfo parses the contents of /proc/[pid]/mountinfo. See proc(5).
+func parseMountInfo(content []byte) ([]types.FileSystemInfo, error) {
+	var filesystems []types.FileSystemInfo
+
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		// Fields are: ID parent major:minor root mount-point options
+		// [optional-fields...] - fstype source super-options.
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || sep+2 >= len(fields) {
+			return nil, errors.Errorf("unexpected mountinfo line: %v", s.Text())
+		}
+
+		filesystems = append(filesystems, types.FileSystemInfo{
+			Device:     unescapeMountField(fields[sep+2]),
+			MountPoint: unescapeMountField(fields[4]),
+			Type:       fields[sep+1],
+		})
+	}
+
+	return filesystems, s.Err()
+}
+
+// unescapeMountField decodes the octal escape sequences (e.g. \040 for a
+// space) that the kernel uses in mount table fields.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				buf.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+func statFileSystem(fs *types.FileSystemInfo, path string) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return
+	}
+
+	blockSize := uint64(st.Frsize)
+	if blockSize == 0 {
+		blockSize = uint64(st.Bsize)
+	}
+
+	fs.Total = st.Blocks * blockSize
+	fs.Free = st.Bfree * blockSize
+	fs.Used = fs.Total - fs.Free
+	fs.Available = st.Bavail * blockSize
+	fs.Inodes = st.Files
+	fs.InodesFree = st.Ffree
+	fs.InodesUsed = fs.Inodes - fs.InodesFree
+}
@@ -0,0 +1,65 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ registry.FileSystemProvider = linuxSystem{}
+
+const mountinfo = `22 28 0:20 / /sys rw,nosuid,nodev,noexec,relatime shared:7 - sysfs sysfs rw
+28 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw,errors=remount-ro
+45 28 0:40 / /mnt/my\040share rw,relatime - cifs //server/my\040share rw,vers=3.0
+`
+
+func TestParseMountInfo(t *testing.T) {
+	filesystems, err := parseMountInfo([]byte(mountinfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.FileSystemInfo{
+		{Device: "sysfs", MountPoint: "/sys", Type: "sysfs"},
+		{Device: "/dev/sda1", MountPoint: "/", Type: "ext4"},
+		{Device: "//server/my share", MountPoint: "/mnt/my share", Type: "cifs"},
+	}, filesystems)
+
+	_, err = parseMountInfo([]byte("22 28 0:20 / /sys rw\n"))
+	assert.Error(t, err)
+}
+
+func TestFileSystems(t *testing.T) {
+	filesystems, err := newLinuxSystem("").FileSystems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fs := range filesystems {
+		if fs.MountPoint == "/" {
+			assert.NotZero(t, fs.Total)
+			return
+		}
+	}
+	t.Fatal("root file system not found")
+}
@@ -0,0 +1,339 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/netfilter.h, linux/netfilter/nfnetlink.h, and
+// linux/netfilter/nf_tables.h.
+const (
+	netlinkNetfilter = 12 // NETLINK_NETFILTER
+	nfgenmsgLen      = 4  // sizeof(struct nfgenmsg)
+
+	nfnlSubsysNftables = 10 // NFNL_SUBSYS_NFTABLES
+	nftMsgGetChain     = 4  // NFT_MSG_GETCHAIN
+	nftMsgGetRule      = 7  // NFT_MSG_GETRULE
+
+	nftaChainTable  = 1 // NFTA_CHAIN_TABLE
+	nftaChainHook   = 4 // NFTA_CHAIN_HOOK
+	nftaChainPolicy = 5 // NFTA_CHAIN_POLICY
+	nftaChainType   = 7 // NFTA_CHAIN_TYPE
+	nftaHookHooknum = 1 // NFTA_HOOK_HOOKNUM
+	nftaRuleTable   = 1 // NFTA_RULE_TABLE
+
+	nfprotoInet = 1  // NFPROTO_INET
+	nfprotoIPv4 = 2  // NFPROTO_IPV4
+	nfprotoIPv6 = 10 // NFPROTO_IPV6
+
+	nfInetLocalIn  = 1 // NF_INET_LOCAL_IN
+	nfInetLocalOut = 3 // NF_INET_LOCAL_OUT
+
+	nfDrop   = 0 // NF_DROP
+	nfAccept = 1 // NF_ACCEPT
+)
+
+// Firewall implementations reported in FirewallInfo.
+const (
+	firewallNftables = "nftables"
+	firewallIptables = "iptables"
+)
+
+var nftFamilies = map[uint8]string{
+	nfprotoInet: "inet",
+	nfprotoIPv4: "ip",
+	nfprotoIPv6: "ip6",
+}
+
+// FirewallInfo reports the nftables tables that have filter chains and the
+// filter tables of the legacy iptables and ip6tables. Tables created by
+// iptables-nft are reported as nftables tables. Reading the rules requires
+// CAP_NET_ADMIN.
+func (h *host) FirewallInfo() (*types.FirewallInfo, error) {
+	info := &types.FirewallInfo{}
+	var errs multierror.Errors
+
+	profiles, err := nftablesProfiles()
+	if err != nil {
+		errs = append(errs, err)
+	} else if len(profiles) > 0 {
+		info.Product = firewallNftables
+		info.Profiles = profiles
+	}
+
+	for _, t := range legacyFilterTables {
+		if !procTableLoaded(h.procFS.Path("net", t.namesFile), "filter") {
+			continue
+		}
+		profile, err := iptablesProfile(t)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if info.Product == "" {
+			info.Product = firewallIptables
+		}
+		info.Profiles = append(info.Profiles, *profile)
+	}
+
+	if len(info.Profiles) == 0 && len(errs) > 0 {
+		return nil, errs.Err()
+	}
+	for _, p := range info.Profiles {
+		info.Enabled = info.Enabled || p.Enabled
+	}
+	return info, nil
+}
+
+func nftablesProfiles() ([]types.FirewallProfile, error) {
+	dump := func(msg uint16) ([][]byte, error) {
+		return netlinkRequest(netlinkNetfilter, nfnlSubsysNftables<<8|msg, syscall.NLM_F_DUMP, make([]byte, nfgenmsgLen))
+	}
+
+	chains, err := dump(nftMsgGetChain)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nftables chains")
+	}
+	rules, err := dump(nftMsgGetRule)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nftables rules")
+	}
+	return parseNftables(chains, rules), nil
+}
+
+// parseNftables builds a profile for each table that has a base chain of
+// type filter. The default policies are taken from the chains on the input
+// and output hooks. A drop policy wins if a table has several such chains.
+func parseNftables(chains, rules [][]byte) []types.FirewallProfile {
+	tables := map[string]*types.FirewallProfile{}
+	var names []string
+
+	for _, msg := range chains {
+		if len(msg) < nfgenmsgLen {
+			continue
+		}
+		family, found := nftFamilies[msg[0]]
+		if !found {
+			continue
+		}
+		attrs := parseNetlinkAttrs(msg[nfgenmsgLen:])
+		if cString(attrs[nftaChainType]) != "filter" || attrs[nftaChainHook] == nil {
+			continue
+		}
+
+		name := family + " " + cString(attrs[nftaChainTable])
+		p, found := tables[name]
+		if !found {
+			p = &types.FirewallProfile{Name: name}
+			tables[name] = p
+			names = append(names, name)
+		}
+
+		hook := parseNetlinkAttrs(attrs[nftaChainHook])[nftaHookHooknum]
+		policy := attrs[nftaChainPolicy]
+		if len(hook) < 4 || len(policy) < 4 {
+			continue
+		}
+		verdict := netfilterPolicy(binary.BigEndian.Uint32(policy))
+		switch binary.BigEndian.Uint32(hook) {
+		case nfInetLocalIn:
+			p.DefaultInbound = stricterPolicy(p.DefaultInbound, verdict)
+		case nfInetLocalOut:
+			p.DefaultOutbound = stricterPolicy(p.DefaultOutbound, verdict)
+		}
+	}
+
+	for _, msg := range rules {
+		if len(msg) < nfgenmsgLen {
+			continue
+		}
+		attrs := parseNetlinkAttrs(msg[nfgenmsgLen:])
+		if p, found := tables[nftFamilies[msg[0]]+" "+cString(attrs[nftaRuleTable])]; found {
+			p.Rules++
+		}
+	}
+
+	sort.Strings(names)
+	profiles := make([]types.FirewallProfile, 0, len(names))
+	for _, name := range names {
+		p := tables[name]
+		p.Enabled = p.Rules > 0 || p.DefaultInbound == types.FirewallPolicyBlock || p.DefaultOutbound == types.FirewallPolicyBlock
+		profiles = append(profiles, *p)
+	}
+	return profiles
+}
+
+func netfilterPolicy(verdict uint32) string {
+	switch verdict {
+	case nfAccept:
+		return types.FirewallPolicyAllow
+	case nfDrop:
+		return types.FirewallPolicyBlock
+	default:
+		return ""
+	}
+}
+
+func stricterPolicy(current, policy string) string {
+	if current == types.FirewallPolicyBlock || policy == "" {
+		return current
+	}
+	return policy
+}
+
+// legacyTable describes the getsockopt interface of the legacy iptables
+// (x_tables) for one address family.
+type legacyTable struct {
+	name         string // Name of the user space tool.
+	namesFile    string // File in /proc/net listing the loaded tables.
+	family       int
+	level        int
+	entrySize    int // sizeof(struct ipt_entry) or sizeof(struct ip6t_entry).
+	targetOffset int // Offset of target_offset within the entry.
+}
+
+var legacyFilterTables = []legacyTable{
+	{"iptables", "ip_tables_names", syscall.AF_INET, syscall.IPPROTO_IP, 112, 88},
+	{"ip6tables", "ip6_tables_names", syscall.AF_INET6, syscall.IPPROTO_IPV6, 168, 140},
+}
+
+// Constants from linux/netfilter_ipv4/ip_tables.h and linux/netfilter/x_tables.h.
+const (
+	iptSoGetInfo    = 64 // IPT_SO_GET_INFO and IP6T_SO_GET_INFO
+	iptSoGetEntries = 65 // IPT_SO_GET_ENTRIES and IP6T_SO_GET_ENTRIES
+
+	iptGetInfoLen        = 84 // sizeof(struct ipt_getinfo)
+	iptGetEntriesHdrLen  = 40 // offsetof(struct ipt_get_entries, entrytable)
+	xtTableMaxNameLen    = 32 // XT_TABLE_MAXNAMELEN
+	xtEntryTargetLen     = 32 // sizeof(struct xt_entry_target)
+	xtFunctionMaxNameLen = 29 // XT_FUNCTION_MAXNAMELEN
+
+	nfRepeat = 4 // NF_REPEAT
+)
+
+// procTableLoaded reports whether the table is listed in the given
+// /proc/net/*_tables_names file. Querying a table that is not loaded would
+// make the kernel load the module and create it.
+func procTableLoaded(path, table string) bool {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Fields(string(content)) {
+		if name == table {
+			return true
+		}
+	}
+	return false
+}
+
+func iptablesProfile(t legacyTable) (*types.FirewallProfile, error) {
+	fd, err := syscall.Socket(t.family, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.IPPROTO_RAW)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open raw socket for %v", t.name)
+	}
+	defer syscall.Close(fd)
+
+	info := make([]byte, iptGetInfoLen)
+	copy(info, "filter")
+	if _, err = getsockopt(fd, t.level, iptSoGetInfo, info); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %v filter table info", t.name)
+	}
+	size := nativeEndian.Uint32(info[80:])
+
+	entries := make([]byte, iptGetEntriesHdrLen+int(size))
+	copy(entries, "filter")
+	nativeEndian.PutUint32(entries[xtTableMaxNameLen:], size)
+	if _, err = getsockopt(fd, t.level, iptSoGetEntries, entries); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %v filter table entries", t.name)
+	}
+
+	return parseIptablesEntries(t, info, entries[iptGetEntriesHdrLen:]), nil
+}
+
+type iptEntry struct {
+	offset  int
+	target  string
+	verdict int32
+}
+
+// parseIptablesEntries walks the entries of a table. The policies of the
+// built-in chains are the entries at the underflow offsets. The remaining
+// entries are rules except for the ERROR entries that mark the start of a
+// user defined chain (and the end of the table) and the RETURN entry at the
+// end of each user defined chain.
+func parseIptablesEntries(t legacyTable, info, table []byte) *types.FirewallProfile {
+	validHooks := nativeEndian.Uint32(info[32:])
+	underflows := map[int]uint32{}
+	for hook := uint32(0); hook < 5; hook++ {
+		if validHooks&(1<<hook) != 0 {
+			underflows[int(nativeEndian.Uint32(info[56+4*hook:]))] = hook
+		}
+	}
+
+	var entries []iptEntry
+	for off := 0; off+t.entrySize <= len(table); {
+		targetOff := int(nativeEndian.Uint16(table[off+t.targetOffset:]))
+		nextOff := int(nativeEndian.Uint16(table[off+t.targetOffset+2:]))
+		if nextOff == 0 || targetOff+xtEntryTargetLen > nextOff || off+nextOff > len(table) {
+			break
+		}
+
+		target := table[off+targetOff : off+nextOff]
+		e := iptEntry{offset: off, target: cString(target[2 : 2+xtFunctionMaxNameLen])}
+		if len(target) >= xtEntryTargetLen+4 {
+			e.verdict = int32(nativeEndian.Uint32(target[xtEntryTargetLen:]))
+		}
+		entries = append(entries, e)
+		off += nextOff
+	}
+
+	p := &types.FirewallProfile{Name: t.name + " filter"}
+	for i, e := range entries {
+		if hook, found := underflows[e.offset]; found {
+			// Standard verdicts are stored as -verdict - 1.
+			policy := netfilterPolicy(uint32(-e.verdict - 1))
+			switch hook {
+			case nfInetLocalIn:
+				p.DefaultInbound = policy
+			case nfInetLocalOut:
+				p.DefaultOutbound = policy
+			}
+			continue
+		}
+		if e.target == "ERROR" {
+			continue
+		}
+		if e.target == "" && e.verdict == -nfRepeat-1 && i+1 < len(entries) && entries[i+1].target == "ERROR" {
+			continue
+		}
+		p.Rules++
+	}
+	p.Enabled = p.Rules > 0 || p.DefaultInbound == types.FirewallPolicyBlock || p.DefaultOutbound == types.FirewallPolicyBlock
+	return p
+}
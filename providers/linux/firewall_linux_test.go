@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Firewall = (*host)(nil)
+
+func nftMsg(family uint8, attrs ...[]byte) []byte {
+	msg := make([]byte, nfgenmsgLen)
+	msg[0] = family
+	for _, attr := range attrs {
+		msg = append(msg, attr...)
+	}
+	return msg
+}
+
+func nftChain(family uint8, table, typ string, hook uint32, policy uint32) []byte {
+	be32 := func(v uint32) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		return b
+	}
+	return nftMsg(family,
+		netlinkAttr(nftaChainTable, append([]byte(table), 0)),
+		netlinkAttr(nftaChainHook|syscall.NLA_F_NESTED, netlinkAttr(nftaHookHooknum, be32(hook))),
+		netlinkAttr(nftaChainPolicy, be32(policy)),
+		netlinkAttr(nftaChainType, append([]byte(typ), 0)),
+	)
+}
+
+func TestParseNftables(t *testing.T) {
+	chains := [][]byte{
+		nftChain(nfprotoInet, "filter", "filter", nfInetLocalIn, nfDrop),
+		nftChain(nfprotoInet, "filter", "filter", nfInetLocalOut, nfAccept),
+		nftChain(nfprotoIPv4, "docker", "filter", 2, nfAccept),
+		nftChain(nfprotoIPv4, "nat", "nat", 0, nfAccept),
+		// Regular chains have no hook.
+		nftMsg(nfprotoInet, netlinkAttr(nftaChainTable, append([]byte("filter"), 0))),
+	}
+	rules := [][]byte{
+		nftMsg(nfprotoInet, netlinkAttr(nftaRuleTable, append([]byte("filter"), 0))),
+		nftMsg(nfprotoInet, netlinkAttr(nftaRuleTable, append([]byte("filter"), 0))),
+		nftMsg(nfprotoIPv4, netlinkAttr(nftaRuleTable, append([]byte("nat"), 0))),
+	}
+
+	assert.Equal(t, []types.FirewallProfile{
+		{Name: "inet filter", Enabled: true, DefaultInbound: "block", DefaultOutbound: "allow", Rules: 2},
+		{Name: "ip docker"},
+	}, parseNftables(chains, rules))
+}
+
+func TestParseIptablesEntries(t *testing.T) {
+	ipv4 := legacyFilterTables[0]
+
+	var table []byte
+	var offsets []int
+	entry := func(target string, verdict int32) {
+		offsets = append(offsets, len(table))
+		targetLen := xtEntryTargetLen + 8
+		if target == "ERROR" {
+			targetLen = xtEntryTargetLen + 32
+		}
+		e := make([]byte, ipv4.entrySize+targetLen)
+		nativeEndian.PutUint16(e[ipv4.targetOffset:], uint16(ipv4.entrySize))
+		nativeEndian.PutUint16(e[ipv4.targetOffset+2:], uint16(len(e)))
+		tgt := e[ipv4.entrySize:]
+		nativeEndian.PutUint16(tgt, uint16(targetLen))
+		copy(tgt[2:], target)
+		if target == "" {
+			nativeEndian.PutUint32(tgt[xtEntryTargetLen:], uint32(verdict))
+		}
+		table = append(table, e...)
+	}
+
+	const accept, drop, ret = -nfAccept - 1, -nfDrop - 1, -nfRepeat - 1
+	entry("", accept) // INPUT rule
+	entry("", drop)   // INPUT policy
+	entry("", accept) // FORWARD policy
+	entry("", accept) // OUTPUT policy
+	entry("ERROR", 0) // Start of a user defined chain.
+	entry("LOG", 0)   // Rule in the user defined chain.
+	entry("", ret)    // End of the user defined chain.
+	entry("ERROR", 0) // End of the table.
+
+	info := make([]byte, iptGetInfoLen)
+	nativeEndian.PutUint32(info[32:], 1<<1|1<<2|1<<3)
+	for i, hook := range []uint32{1, 2, 3} {
+		nativeEndian.PutUint32(info[56+4*hook:], uint32(offsets[1+i]))
+	}
+
+	assert.Equal(t, &types.FirewallProfile{
+		Name:            "iptables filter",
+		Enabled:         true,
+		DefaultInbound:  "block",
+		DefaultOutbound: "allow",
+		Rules:           2,
+	}, parseIptablesEntries(ipv4, info, table))
+}
@@ -0,0 +1,118 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// fileSystem is the view of the host's files that the provider reads. Names
+// are slash-separated paths relative to the root of the host (e.g.
+// "etc/os-release"); a leading slash is ignored. The default implementation
+// reads the local filesystem, optionally below a host FS mount (see
+// WithHostFS), and tests can substitute an in-memory tree.
+//
+// Host and process files are read through it, with these exceptions:
+// files parsed by the vendored procfs library (e.g. /proc/[pid]/stat and
+// /proc/stat), walks of /proc/sys and the cgroup hierarchy, sysfs device
+// trees (devices, GPUs, power supplies, sensors), the LSM files, and files
+// that the OS must open itself (devices for ioctls, executables for
+// hashing). Those use the local paths below the host FS mount.
+type fileSystem interface {
+	Open(name string) (file, error)
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Readlink(name string) (string, error)
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+
+	// Glob returns the names matching pattern (see filepath.Match) relative
+	// to the root.
+	Glob(pattern string) ([]string, error)
+}
+
+// file is an open file of a fileSystem.
+type file interface {
+	io.ReadCloser
+	io.ReaderAt
+}
+
+// dirFS is a fileSystem rooted at a directory of the local filesystem.
+type dirFS string
+
+func (d dirFS) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(strings.TrimPrefix(name, "/")))
+}
+
+func (d dirFS) Open(name string) (file, error)             { return os.Open(d.path(name)) }
+func (d dirFS) ReadFile(name string) ([]byte, error)       { return ioutil.ReadFile(d.path(name)) }
+func (d dirFS) ReadDir(name string) ([]os.FileInfo, error) { return ioutil.ReadDir(d.path(name)) }
+func (d dirFS) Readlink(name string) (string, error)       { return os.Readlink(d.path(name)) }
+func (d dirFS) Stat(name string) (os.FileInfo, error)      { return os.Stat(d.path(name)) }
+func (d dirFS) Lstat(name string) (os.FileInfo, error)     { return os.Lstat(d.path(name)) }
+
+func (d dirFS) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(d.path(pattern))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(d.path(""), m)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	return names, nil
+}
+
+// openDevice opens the device node name of fs with the given flags. Devices
+// are used for ioctls so they can only be opened on the local filesystem.
+func openDevice(fs fileSystem, name string, flag int) (*os.File, error) {
+	d, ok := fs.(dirFS)
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.ENODEV}
+	}
+	return os.OpenFile(d.path(name), flag, 0)
+}
+
+// readAttr returns the trimmed content of a single value sysfs attribute or
+// an empty string if it cannot be read.
+func readAttr(fs fileSystem, name string) string {
+	data, err := fs.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readAttrInt returns the integer value of a sysfs attribute.
+func readAttrInt(fs fileSystem, name string) (int64, error) {
+	data, err := fs.ReadFile(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
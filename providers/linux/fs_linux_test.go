@@ -0,0 +1,221 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// mapFS is an in-memory fileSystem for tests. Keys are file names relative
+// to the root (e.g. "etc/os-release"). Directories are implied by the names.
+type mapFS map[string]string
+
+type mapFile struct {
+	*bytes.Reader
+}
+
+func (mapFile) Close() error { return nil }
+
+type mapFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi mapFileInfo) Name() string       { return fi.name }
+func (fi mapFileInfo) Size() int64        { return fi.size }
+func (fi mapFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi mapFileInfo) IsDir() bool        { return fi.dir }
+func (fi mapFileInfo) Sys() interface{}   { return nil }
+
+func (fi mapFileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (m mapFS) clean(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (m mapFS) Open(name string) (file, error) {
+	content, err := m.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return mapFile{bytes.NewReader(content)}, nil
+}
+
+func (m mapFS) ReadFile(name string) ([]byte, error) {
+	content, found := m[m.clean(name)]
+	if !found {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return []byte(content), nil
+}
+
+func (m mapFS) ReadDir(name string) ([]os.FileInfo, error) {
+	dir := m.clean(name)
+	entries := map[string]os.FileInfo{}
+	for k, v := range m {
+		if dir != "" && !strings.HasPrefix(k, dir+"/") {
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(k, dir), "/")
+		if i := strings.IndexByte(rel, '/'); i >= 0 {
+			entries[rel[:i]] = mapFileInfo{name: rel[:i], dir: true}
+		} else {
+			entries[rel] = mapFileInfo{name: rel, size: int64(len(v))}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, fi := range entries {
+		infos = append(infos, fi)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+func (m mapFS) Readlink(name string) (string, error) {
+	return "", &os.PathError{Op: "readlink", Path: name, Err: os.ErrInvalid}
+}
+
+func (m mapFS) Stat(name string) (os.FileInfo, error) {
+	if content, found := m[m.clean(name)]; found {
+		return mapFileInfo{name: path.Base(name), size: int64(len(content))}, nil
+	}
+	if _, err := m.ReadDir(name); err == nil {
+		return mapFileInfo{name: path.Base(name), dir: true}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+func (m mapFS) Lstat(name string) (os.FileInfo, error) {
+	return m.Stat(name)
+}
+
+func (m mapFS) Glob(pattern string) ([]string, error) {
+	var names []string
+	for k := range m {
+		if ok, err := path.Match(m.clean(pattern), k); err != nil {
+			return nil, err
+		} else if ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func TestDirFS(t *testing.T) {
+	fs := dirFS("testdata/ubuntu1710")
+
+	content, err := fs.ReadFile("/etc/machine-id")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "d0e7d6c8e2b54d9f84c6d2b4d1d7a8f1\n", string(content))
+	}
+
+	matches, err := fs.Glob("etc/*-release")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"etc/lsb-release", "etc/os-release"}, matches)
+	}
+}
+
+func TestMapFS(t *testing.T) {
+	fs := mapFS{
+		"etc/os-release":     "ID=alpine\nNAME=\"Alpine Linux\"\nVERSION=3.7.0\nVERSION_ID=3.7.0\n",
+		"etc/machine-id":     "0123456789abcdef0123456789abcdef\n",
+		"etc/hostname":       "alpine\n",
+		"etc/passwd":         "root:x:0:0:root:/root:/bin/ash\n",
+		"sys/class/dmi/id/x": "",
+	}
+
+	osInfo, err := getOSInfo(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "alpine", osInfo.Platform)
+	assert.Equal(t, "3.7.0", osInfo.Version)
+
+	assert.Equal(t, []types.MachineIDInfo{
+		{ID: "0123456789abcdef0123456789abcdef", Source: types.MachineIDSourceSystemd},
+	}, machineIDs(fs))
+
+	name, err := hostname(fs, true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "alpine", name)
+	}
+
+	users, err := linuxSystem{fs: fs}.Users()
+	if assert.NoError(t, err) && assert.Len(t, users, 1) {
+		assert.Equal(t, "root", users[0].Name)
+	}
+
+	_, err = readDMI(fs, dmiDir)
+	assert.NoError(t, err)
+	_, err = readDMI(fs, "sys/class/dmi/missing")
+	assert.Equal(t, types.ErrNotImplemented, err)
+}
+
+func TestMapFSProc(t *testing.T) {
+	fs := mapFS{
+		"proc/meminfo":    "MemTotal: 2048 kB\nMemFree: 1024 kB\nMemAvailable: 1536 kB\n",
+		"proc/loadavg":    "0.25 0.50 1.00 1/100 4242\n",
+		"proc/42/environ": "HOME=/root\x00PATH=/bin\x00",
+		"proc/42/status":  "Name:\tsh\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\n",
+	}
+	h := &host{fs: fs}
+
+	mem, err := h.Memory()
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 2048*1024, mem.Total)
+		assert.EqualValues(t, 1536*1024, mem.Available)
+	}
+
+	load, err := h.LoadAverage()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0.25, load.One)
+	}
+
+	p := &process{Proc: procfs.Proc{PID: 42}, fs: fs}
+	env, err := p.Environment()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"HOME": "/root", "PATH": "/bin"}, env)
+	}
+
+	user, err := p.User()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0", user.UID)
+	}
+}
@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// drmCardRegexp matches the DRM devices and not their connectors (e.g.
+// card0-DP-1).
+var drmCardRegexp = regexp.MustCompile(`^card[0-9]+$`)
+
+// GPUs lists the DRM devices in /sys/class/drm.
+func (h *host) GPUs() ([]types.GPUInfo, error) {
+	return readGPUs(filepath.Dir(string(h.procFS)))
+}
+
+func readGPUs(root string) ([]types.GPUInfo, error) {
+	cards, err := filepath.Glob(filepath.Join(root, "sys/class/drm/card*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var gpus []types.GPUInfo
+	for _, card := range cards {
+		if !drmCardRegexp.MatchString(filepath.Base(card)) {
+			continue
+		}
+		device := filepath.Join(card, "device")
+
+		gpu := types.GPUInfo{}
+		if path, err := filepath.EvalSymlinks(device); err == nil {
+			gpu.BusID = filepath.Base(path)
+		}
+		if driver, err := os.Readlink(filepath.Join(device, "driver")); err == nil {
+			gpu.Driver = filepath.Base(driver)
+			// Out-of-tree modules (e.g. nvidia) report a version.
+			gpu.DriverVersion = readSysfsString(filepath.Join(root, "sys/module", gpu.Driver, "version"))
+		}
+		// Reported by amdgpu.
+		if vram, err := readSysfsInt(filepath.Join(device, "mem_info_vram_total")); err == nil {
+			gpu.VRAM = uint64(vram)
+		}
+
+		vendorID := strings.TrimPrefix(readSysfsString(filepath.Join(device, "vendor")), "0x")
+		deviceID := strings.TrimPrefix(readSysfsString(filepath.Join(device, "device")), "0x")
+		if vendorID != "" {
+			gpu.PCIID = vendorID + ":" + deviceID
+			gpu.Vendor, gpu.Model = lookupHardwareIDs(root, pciIDsPaths, vendorID, deviceID)
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
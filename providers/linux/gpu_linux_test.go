@@ -0,0 +1,45 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.GPUs = (*host)(nil)
+
+func TestReadGPUs(t *testing.T) {
+	gpus, err := readGPUs("testdata/ubuntu1710")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.GPUInfo{
+		{
+			Vendor: "Intel Corporation",
+			Model:  "HD Graphics 620",
+			PCIID:  "8086:5916",
+			BusID:  "0000:00:02.0",
+			Driver: "i915",
+		},
+	}, gpus)
+}
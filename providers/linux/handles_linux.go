@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// anonymousTypes maps the prefix of /proc/<pid>/fd link targets that do not
+// reference a path to the type of the handle.
+var anonymousTypes = map[string]string{
+	"socket":     types.HandleTypeSocket,
+	"pipe":       types.HandleTypePipe,
+	"anon_inode": "anon_inode",
+}
+
+// handleType returns the type of handle given its /proc/<pid>/fd link target
+// and the mode of the file it references. The mode is only consulted for
+// targets that are paths.
+func handleType(target string, mode os.FileMode) string {
+	if !strings.HasPrefix(target, "/") {
+		if idx := strings.IndexByte(target, ':'); idx > 0 {
+			if t, found := anonymousTypes[target[:idx]]; found {
+				return t
+			}
+		}
+		return types.HandleTypeUnknown
+	}
+
+	switch {
+	case mode.IsDir():
+		return types.HandleTypeDir
+	case mode&os.ModeNamedPipe != 0:
+		return types.HandleTypePipe
+	case mode&os.ModeSocket != 0:
+		return types.HandleTypeSocket
+	case mode&os.ModeDevice != 0:
+		return types.HandleTypeDevice
+	default:
+		return types.HandleTypeFile
+	}
+}
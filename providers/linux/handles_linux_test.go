@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestHandleType(t *testing.T) {
+	for _, tc := range []struct {
+		target   string
+		mode     os.FileMode
+		expected string
+	}{
+		{"/var/log/syslog", 0644, types.HandleTypeFile},
+		{"/tmp/deleted.log (deleted)", 0644, types.HandleTypeFile},
+		{"/home", os.ModeDir | 0755, types.HandleTypeDir},
+		{"/dev/null", os.ModeDevice | os.ModeCharDevice | 0666, types.HandleTypeDevice},
+		{"/run/fifo", os.ModeNamedPipe | 0600, types.HandleTypePipe},
+		{"socket:[27554]", 0, types.HandleTypeSocket},
+		{"pipe:[27555]", 0, types.HandleTypePipe},
+		{"anon_inode:[eventpoll]", 0, "anon_inode"},
+		{"net:[4026531993]", 0, types.HandleTypeUnknown},
+	} {
+		assert.Equal(t, tc.expected, handleType(tc.target, tc.mode), tc.target)
+	}
+}
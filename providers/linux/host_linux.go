@@ -18,7 +18,7 @@
 package linux
 
 import (
-	"io/ioutil"
+	"bytes"
 	"os"
 	"path/filepath"
 	"time"
@@ -37,36 +37,162 @@ func init() {
 }
 
 type linuxSystem struct {
-	procFS procfs.FS
+	procFS         procfs.FS
+	fs             fileSystem         // Root of the host.
+	hostFS         string             // Empty unless the host's root filesystem is mounted elsewhere.
+	fields         types.ProcessField // Fields collected by the processes. Zero selects all fields.
+	envFilter      types.EnvFilter    // Applied to the environment of the processes.
+	processBackend string             // Lists the processes. Empty selects procfs.
+	cache          shared.CachePolicy // TTL of the StaticCache lookups of the host.
 }
 
 func newLinuxSystem(hostFS string) linuxSystem {
+	root := hostFS
+	if root == "" {
+		root = "/"
+	}
 	return linuxSystem{
 		procFS: procfs.FS(filepath.Join(hostFS, procfs.DefaultMountPoint)),
+		fs:     dirFS(root),
+		hostFS: hostFS,
+	}
+}
+
+// WithHostFS returns a provider that reads /proc, /sys, and /etc of the host
+// from a root filesystem that is mounted at root. This is used by agents that
+// run in a container with the host's root bound to a path like /hostfs. The
+// host's PID namespace must be shared with the container (e.g. hostPID in
+// Kubernetes) for /proc to describe the host's processes.
+func (s linuxSystem) WithHostFS(root string) interface{} {
+	h := newLinuxSystem(root)
+	h.fields, h.envFilter, h.processBackend, h.cache = s.fields, s.envFilter, s.processBackend, s.cache
+	return h
+}
+
+// WithFields returns a provider whose processes only read the selected parts
+// of /proc/[pid].
+func (s linuxSystem) WithFields(fields types.ProcessField) interface{} {
+	s.fields = fields
+	return s
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s linuxSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+// WithProcessBackend returns a provider that lists the processes with the
+// given backend. The eBPF backend falls back to procfs if the task iterator
+// cannot be loaded or the caller is not in the initial PID namespace.
+func (s linuxSystem) WithProcessBackend(backend string) (interface{}, error) {
+	switch backend {
+	case "", types.ProcessBackendProcFS, types.ProcessBackendEBPF:
+	default:
+		return nil, errors.Errorf("unknown process backend %v", backend)
 	}
+	s.processBackend = backend
+	return s, nil
+}
+
+// WithCacheTTL returns a provider that caches the host data for ttl.
+func (s linuxSystem) WithCacheTTL(ttl time.Duration) interface{} {
+	s.cache = shared.CacheTTL(ttl)
+	return s
 }
 
 func (s linuxSystem) Host() (types.Host, error) {
-	return newHost(s.procFS)
+	return newHost(s.procFS, s.fs, s.hostFS != "", s.cache)
 }
 
 type host struct {
 	procFS procfs.FS
+	fs     fileSystem
+	hostFS bool // The host's root filesystem is mounted elsewhere.
+	cache  shared.CachePolicy
 	stat   procfs.Stat
 	info   types.HostInfo
 }
 
+// cacheKey returns a shared.StaticCache key that is specific to the host FS.
+func (h *host) cacheKey(name string) string {
+	return name + ":" + string(h.procFS)
+}
+
 func (h *host) Info() types.HostInfo {
 	return h.info
 }
 
 func (h *host) Memory() (*types.HostMemoryInfo, error) {
-	content, err := ioutil.ReadFile(h.procFS.Path("meminfo"))
+	content, err := h.fs.ReadFile("proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+
+	mem, err := parseMemInfo(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Swap activity is only reported in vmstat as a number of pages.
+	if content, err = h.fs.ReadFile("proc/vmstat"); err == nil {
+		if vmstat, err := parseVMStat(content); err == nil {
+			pageSize := uint64(os.Getpagesize())
+			mem.SwapIn = vmstat["pswpin"] * pageSize
+			mem.SwapOut = vmstat["pswpout"] * pageSize
+		}
+	}
+
+	return mem, nil
+}
+
+// VMStat returns paging and swapping counters from /proc/vmstat and
+// scheduler counters from /proc/stat.
+func (h *host) VMStat() (*types.VMStatInfo, error) {
+	content, err := h.fs.ReadFile("proc/vmstat")
+	if err != nil {
+		return nil, err
+	}
+
+	vmstat, err := parseVMStat(content)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := h.procFS.NewStat()
+	if err != nil {
+		return nil, err
+	}
+
+	// pgpgin and pgpgout are in KiB. pswpin and pswpout are in pages.
+	pageSize := uint64(os.Getpagesize())
+	return &types.VMStatInfo{
+		PageIn:          vmstat["pgpgin"] * 1024,
+		PageOut:         vmstat["pgpgout"] * 1024,
+		SwapIn:          vmstat["pswpin"] * pageSize,
+		SwapOut:         vmstat["pswpout"] * pageSize,
+		PageFaults:      vmstat["pgfault"],
+		MajorPageFaults: vmstat["pgmajfault"],
+		ContextSwitches: stat.ContextSwitches,
+		Interrupts:      stat.IRQTotal,
+		Forks:           stat.ProcessCreated,
+	}, nil
+}
+
+// LoadAverage returns the load averages from /proc/loadavg.
+func (h *host) LoadAverage() (*types.LoadAverageInfo, error) {
+	content, err := h.fs.ReadFile("proc/loadavg")
 	if err != nil {
 		return nil, err
 	}
 
-	return parseMemInfo(content)
+	return parseLoadAvg(content)
+}
+
+// Uptime returns the time since boot from /proc/uptime.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock(h.procFS).Uptime()
 }
 
 func (h *host) CPUTime() (types.CPUTimes, error) {
@@ -75,25 +201,42 @@ func (h *host) CPUTime() (types.CPUTimes, error) {
 		return types.CPUTimes{}, err
 	}
 
+	return cpuStatToTimes(stat.CPUTotal), nil
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	stat, err := h.procFS.NewStat()
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]types.CPUTimes, 0, len(stat.CPU))
+	for _, cpu := range stat.CPU {
+		times = append(times, cpuStatToTimes(cpu))
+	}
+	return times, nil
+}
+
+func cpuStatToTimes(cpu procfs.CPUStat) types.CPUTimes {
 	return types.CPUTimes{
-		User:    time.Duration(stat.CPUTotal.User * float64(time.Second)),
-		System:  time.Duration(stat.CPUTotal.System * float64(time.Second)),
-		Idle:    time.Duration(stat.CPUTotal.Idle * float64(time.Second)),
-		IOWait:  time.Duration(stat.CPUTotal.Iowait * float64(time.Second)),
-		IRQ:     time.Duration(stat.CPUTotal.IRQ * float64(time.Second)),
-		Nice:    time.Duration(stat.CPUTotal.Nice * float64(time.Second)),
-		SoftIRQ: time.Duration(stat.CPUTotal.SoftIRQ * float64(time.Second)),
-		Steal:   time.Duration(stat.CPUTotal.Steal * float64(time.Second)),
-	}, nil
+		User:    time.Duration(cpu.User * float64(time.Second)),
+		System:  time.Duration(cpu.System * float64(time.Second)),
+		Idle:    time.Duration(cpu.Idle * float64(time.Second)),
+		IOWait:  time.Duration(cpu.Iowait * float64(time.Second)),
+		IRQ:     time.Duration(cpu.IRQ * float64(time.Second)),
+		Nice:    time.Duration(cpu.Nice * float64(time.Second)),
+		SoftIRQ: time.Duration(cpu.SoftIRQ * float64(time.Second)),
+		Steal:   time.Duration(cpu.Steal * float64(time.Second)),
+	}
 }
 
-func newHost(fs procfs.FS) (*host, error) {
-	stat, err := fs.NewStat()
+func newHost(procFS procfs.FS, fs fileSystem, hostFS bool, cache shared.CachePolicy) (*host, error) {
+	stat, err := procFS.NewStat()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read proc stat")
 	}
 
-	h := &host{stat: stat, procFS: fs}
+	h := &host{stat: stat, procFS: procFS, fs: fs, hostFS: hostFS, cache: cache}
 	r := &reader{}
 	r.architecture(h)
 	r.bootTime(h)
@@ -113,7 +256,7 @@ type reader struct {
 
 func (r *reader) addErr(err error) bool {
 	if err != nil {
-		if errors.Cause(err) != types.ErrNotImplemented {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
 			r.errs = append(r.errs, err)
 		}
 		return true
@@ -145,15 +288,28 @@ func (r *reader) bootTime(h *host) {
 }
 
 func (r *reader) containerized(h *host) {
-	v, err := IsContainerized()
+	v, err := isContainerized(h.fs)
 	if r.addErr(err) {
 		return
 	}
 	h.info.Containerized = &v
+
+	if !v {
+		return
+	}
+	mountinfo := procSelfMountinfo
+	if h.hostFS {
+		mountinfo = procOneMountinfo
+	}
+	info, err := readContainerInfo(h.fs, mountinfo)
+	if r.addErr(err) {
+		return
+	}
+	h.info.Container = info
 }
 
 func (r *reader) hostname(h *host) {
-	v, err := os.Hostname()
+	v, err := hostname(h.fs, h.hostFS)
 	if r.addErr(err) {
 		return
 	}
@@ -178,21 +334,38 @@ func (r *reader) kernelVersion(h *host) {
 }
 
 func (r *reader) os(h *host) {
-	v, err := OperatingSystem()
+	v, err := h.cache.Get(h.cacheKey("os"), func() (interface{}, error) {
+		return getOSInfo(h.fs)
+	})
 	if r.addErr(err) {
 		return
 	}
-	h.info.OS = v
+	h.info.OS = shared.CopyOSInfo(v.(*types.OSInfo))
 }
 
 func (r *reader) time(h *host) {
 	h.info.Timezone, h.info.TimezoneOffsetSec = time.Now().Zone()
 }
 
+// uniqueID uses /etc/machine-id and falls back to the other machine IDs.
 func (r *reader) uniqueID(h *host) {
-	v, err := MachineID()
-	if r.addErr(err) {
+	ids, _ := h.MachineIDs()
+	if len(ids) == 0 {
 		return
 	}
-	h.info.UniqueID = v
+	h.info.UniqueID = ids[0].ID
+	h.info.UniqueIDSource = ids[0].Source
+}
+
+// hostname returns the hostname. os.Hostname returns the name in the UTS
+// namespace of the current process, so when a host FS is used the name is
+// read from its /etc/hostname.
+func hostname(fs fileSystem, hostFS bool) (string, error) {
+	if hostFS {
+		content, err := fs.ReadFile("etc/hostname")
+		if err == nil && len(bytes.TrimSpace(content)) > 0 {
+			return string(bytes.TrimSpace(content)), nil
+		}
+	}
+	return os.Hostname()
 }
@@ -19,14 +19,18 @@ package linux
 
 import (
 	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
 )
 
 var _ registry.HostProvider = linuxSystem{}
+var _ registry.HostFSProvider = linuxSystem{}
+var _ types.VMStat = (*host)(nil)
 
 func TestHost(t *testing.T) {
 	host, err := newLinuxSystem("").Host()
@@ -39,6 +43,52 @@ func TestHost(t *testing.T) {
 	t.Log(string(data))
 }
 
+func TestHostFS(t *testing.T) {
+	provider, ok := linuxSystem{}.WithHostFS("testdata/ubuntu1710").(linuxSystem)
+	if !ok {
+		t.Fatal("WithHostFS must return a linuxSystem")
+	}
+	host, err := provider.Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := host.Info()
+	assert.Equal(t, "ubuntu1710", info.Hostname)
+	if assert.NotNil(t, info.OS) {
+		assert.Equal(t, "ubuntu", info.OS.Platform)
+	}
+	assert.Equal(t, types.MachineIDSourceSystemd, info.UniqueIDSource)
+	if assert.NotNil(t, info.Containerized) {
+		assert.False(t, *info.Containerized)
+	}
+	assert.Nil(t, info.Container)
+}
+
+func TestHostFSContainerInfo(t *testing.T) {
+	fs := mapFS{
+		"proc/1/cgroup":       privateCgroupNamespace,
+		"proc/1/mountinfo":    dockerMountinfo,
+		"proc/self/mountinfo": "",
+		".dockerenv":          "",
+	}
+
+	v, err := isContainerized(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, v)
+
+	info, err := readContainerInfo(fs, procOneMountinfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.ContainerInfo{
+		Runtime: types.ContainerRuntimeDocker,
+		ID:      "81438f4655cd771c425607dcf7654f4dc03c073c0123edc45fcfad28132e8c60",
+	}, info)
+}
+
 func TestHostMemoryInfo(t *testing.T) {
 	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
 	if err != nil {
@@ -49,7 +99,55 @@ func TestHostMemoryInfo(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	assert.EqualValues(t, 4139057152, m.Total)
+	assert.EqualValues(t, uint64(4139057152), m.Total)
 	assert.NotContains(t, m.Metrics, "MemTotal")
 	assert.Contains(t, m.Metrics, "Slab")
+	assert.EqualValues(t, 1248984*1024, m.Cached)
+	assert.EqualValues(t, 33316*1024, m.Buffers)
+	assert.EqualValues(t, 68708*1024, m.Slab)
+	assert.EqualValues(t, 1652*1024, m.PageTables)
+	assert.EqualValues(t, 2048*1024, m.HugePageSize)
+	assert.EqualValues(t, 12*os.Getpagesize(), m.SwapIn)
+	assert.EqualValues(t, 34*os.Getpagesize(), m.SwapOut)
+}
+
+func TestHostVMStat(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm, err := host.(types.VMStat).VMStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	assert.Equal(t, &types.VMStatInfo{
+		PageIn:          1051813 * 1024,
+		PageOut:         322894 * 1024,
+		SwapIn:          12 * pageSize,
+		SwapOut:         34 * pageSize,
+		PageFaults:      5736326,
+		MajorPageFaults: 4683,
+		ContextSwitches: 888692448,
+		Interrupts:      421434840,
+		Forks:           754326,
+	}, vm)
+}
+
+func TestHostCPUTimePerCPU(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpus, err := host.CPUTimePerCPU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, cpus, 4) {
+		assert.InDelta(t, 5935.45, cpus[0].User.Seconds(), 1e-6)
+		assert.InDelta(t, 4313.98, cpus[0].System.Seconds(), 1e-6)
+		assert.InDelta(t, 144.35, cpus[0].SoftIRQ.Seconds(), 1e-6)
+	}
 }
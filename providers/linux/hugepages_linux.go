@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// HugePages returns the huge page pools from /sys/kernel/mm/hugepages and the
+// transparent huge page mode from /sys/kernel/mm/transparent_hugepage.
+func (h *host) HugePages() (*types.HugePagesInfo, error) {
+	return readHugePages(h.fs, "sys/kernel/mm")
+}
+
+func readHugePages(fs fileSystem, dir string) (*types.HugePagesInfo, error) {
+	entries, err := fs.ReadDir(path.Join(dir, "hugepages"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	info := &types.HugePagesInfo{}
+	for _, e := range entries {
+		// Directories are named hugepages-<size>kB.
+		name := e.Name()
+		if !strings.HasPrefix(name, "hugepages-") || !strings.HasSuffix(name, "kB") {
+			continue
+		}
+		kB, err := strconv.ParseUint(name[len("hugepages-"):len(name)-len("kB")], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hugepages directory '%v'", name)
+		}
+
+		read := func(file string) uint64 {
+			v, _ := readAttrInt(fs, path.Join(dir, "hugepages", name, file))
+			return uint64(v)
+		}
+		info.Pools = append(info.Pools, types.HugePagePoolInfo{
+			Size:     kB * 1024,
+			Total:    read("nr_hugepages"),
+			Free:     read("free_hugepages"),
+			Reserved: read("resv_hugepages"),
+			Surplus:  read("surplus_hugepages"),
+		})
+	}
+	sort.Slice(info.Pools, func(i, j int) bool { return info.Pools[i].Size < info.Pools[j].Size })
+
+	info.TransparentEnabled = selectedMode(readAttr(fs, path.Join(dir, "transparent_hugepage/enabled")))
+	info.TransparentDefrag = selectedMode(readAttr(fs, path.Join(dir, "transparent_hugepage/defrag")))
+	return info, nil
+}
+
+// selectedMode returns the bracketed value of a sysfs mode list such as
+// "always [madvise] never".
+func selectedMode(modes string) string {
+	start := strings.IndexByte(modes, '[')
+	end := strings.IndexByte(modes, ']')
+	if start < 0 || end < start {
+		return modes
+	}
+	return modes[start+1 : end]
+}
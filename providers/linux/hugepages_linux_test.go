@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.HugePages = (*host)(nil)
+
+func TestHostHugePages(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := host.(types.HugePages).HugePages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.HugePagesInfo{
+		Pools: []types.HugePagePoolInfo{
+			{Size: 2 << 20, Total: 128, Free: 100, Reserved: 4},
+			{Size: 1 << 30},
+		},
+		TransparentEnabled: "madvise",
+		TransparentDefrag:  "madvise",
+	}, info)
+}
+
+func TestSelectedMode(t *testing.T) {
+	assert.Equal(t, "never", selectedMode("always madvise [never]"))
+	assert.Equal(t, "", selectedMode(""))
+}
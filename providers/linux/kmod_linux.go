@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// LoadedModules returns the kernel modules listed in /proc/modules. The
+// version of each module is read from /sys/module/[name]/version. Nothing is
+// returned by kernels built without module support.
+func (h *host) LoadedModules() ([]types.KernelModuleInfo, error) {
+	content, err := h.fs.ReadFile("proc/modules")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	modules, err := parseModules(content)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range modules {
+		version, err := h.fs.ReadFile(path.Join("sys/module", modules[i].Name, "version"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		modules[i].Version = string(bytes.TrimSpace(version))
+	}
+	return modules, nil
+}
+
+// parseModules parses the contents of /proc/modules. Each line contains the
+// name, size, reference count, dependent modules, state, and load address of
+// a module followed by its taint flags in parentheses.
+func parseModules(content []byte) ([]types.KernelModuleInfo, error) {
+	var modules []types.KernelModuleInfo
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 6 {
+			return nil, errors.Errorf("failed to parse modules line: %v", sc.Text())
+		}
+
+		size, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse size of module %v", fields[0])
+		}
+		refs, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse reference count of module %v", fields[0])
+		}
+		addr, err := strconv.ParseUint(strings.TrimPrefix(fields[5], "0x"), 16, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse address of module %v", fields[0])
+		}
+
+		module := types.KernelModuleInfo{
+			Name:     fields[0],
+			Size:     size,
+			RefCount: refs,
+			State:    fields[4],
+			Address:  addr,
+		}
+		for _, dep := range strings.Split(fields[3], ",") {
+			if dep != "" && dep != "-" {
+				module.UsedBy = append(module.UsedBy, dep)
+			}
+		}
+		if len(fields) > 6 {
+			module.Taint = strings.Trim(fields[6], "()")
+			module.Unsigned = strings.ContainsRune(module.Taint, 'E')
+		}
+		modules = append(modules, module)
+	}
+	return modules, sc.Err()
+}
@@ -0,0 +1,65 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.LoadedModules = (*host)(nil)
+
+func TestHostLoadedModules(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules, err := host.(types.LoadedModules).LoadedModules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, modules, 5) {
+		return
+	}
+
+	assert.Equal(t, types.KernelModuleInfo{
+		Name:     "vboxguest",
+		Version:  "5.1.30 r118389",
+		Size:     286720,
+		RefCount: 2,
+		UsedBy:   []string{"vboxsf"},
+		State:    "Live",
+		Taint:    "OE",
+		Unsigned: true,
+	}, modules[1])
+
+	assert.Equal(t, types.KernelModuleInfo{
+		Name:     "nf_nat",
+		Size:     28672,
+		RefCount: 2,
+		UsedBy:   []string{"nf_nat_ipv4", "xt_nat"},
+		State:    "Live",
+	}, modules[2])
+
+	_, err = parseModules([]byte("ahci 36864 2 -"))
+	assert.Error(t, err)
+}
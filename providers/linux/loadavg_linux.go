@@ -0,0 +1,50 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// parseLoadAvg parses the first three fields of /proc/loadavg.
+func parseLoadAvg(content []byte) (*types.LoadAverageInfo, error) {
+	fields := strings.Fields(string(content))
+	if len(fields) < 3 {
+		return nil, errors.Errorf("failed to parse loadavg '%v'", string(content))
+	}
+
+	var values [3]float64
+	for i := range values {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse loadavg value '%v'", fields[i])
+		}
+		values[i] = v
+	}
+
+	return &types.LoadAverageInfo{
+		One:     values[0],
+		Five:    values[1],
+		Fifteen: values[2],
+	}, nil
+}
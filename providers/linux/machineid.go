@@ -19,7 +19,6 @@ package linux
 
 import (
 	"bytes"
-	"io/ioutil"
 	"os"
 
 	"github.com/pkg/errors"
@@ -28,7 +27,12 @@ import (
 )
 
 func MachineID() (string, error) {
-	id, err := ioutil.ReadFile("/etc/machine-id")
+	return machineID(dirFS("/"))
+}
+
+// machineID returns the systemd machine ID of fs.
+func machineID(fs fileSystem) (string, error) {
+	id, err := fs.ReadFile("etc/machine-id")
 	if os.IsNotExist(err) {
 		return "", types.ErrNotImplemented
 	}
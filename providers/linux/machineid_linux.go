@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// MachineIDs returns the systemd and D-Bus machine IDs and the DMI system
+// UUID. The UUID is only readable by root. The result is cached in
+// shared.StaticCache.
+func (h *host) MachineIDs() ([]types.MachineIDInfo, error) {
+	v, _ := h.cache.Get(h.cacheKey("machine-ids"), func() (interface{}, error) {
+		return machineIDs(h.fs), nil
+	})
+	return append([]types.MachineIDInfo(nil), v.([]types.MachineIDInfo)...), nil
+}
+
+func machineIDs(fs fileSystem) []types.MachineIDInfo {
+	var ids []types.MachineIDInfo
+	for _, src := range []struct {
+		path   string
+		source string
+	}{
+		{"etc/machine-id", types.MachineIDSourceSystemd},
+		{"var/lib/dbus/machine-id", types.MachineIDSourceDBus},
+		{"sys/class/dmi/id/product_uuid", types.MachineIDSourceDMI},
+	} {
+		content, err := fs.ReadFile(src.path)
+		if err != nil {
+			continue
+		}
+
+		id := strings.ToLower(string(bytes.TrimSpace(content)))
+		if src.source == types.MachineIDSourceDMI && !shared.ValidSystemUUID(id) {
+			continue
+		}
+		if id == "" || (len(ids) > 0 && ids[len(ids)-1].ID == id) {
+			// The D-Bus machine ID is usually a link to /etc/machine-id.
+			continue
+		}
+		ids = append(ids, types.MachineIDInfo{ID: id, Source: src.source})
+	}
+	return ids
+}
@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.MachineIdentifier = (*host)(nil)
+
+func TestMachineIDs(t *testing.T) {
+	ids := machineIDs(dirFS("testdata/ubuntu1710"))
+	assert.Equal(t, []types.MachineIDInfo{
+		{ID: "d0e7d6c8e2b54d9f84c6d2b4d1d7a8f1", Source: types.MachineIDSourceSystemd},
+		{ID: "4c4c4544-004b-4d10-8035-b4c04f4e4232", Source: types.MachineIDSourceDMI},
+	}, ids)
+
+	assert.Empty(t, machineIDs(dirFS("testdata/missing")))
+}
+
+func TestMachineID(t *testing.T) {
+	id, err := machineID(dirFS("testdata/ubuntu1710"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "d0e7d6c8e2b54d9f84c6d2b4d1d7a8f1", id)
+
+	_, err = machineID(dirFS("testdata/missing"))
+	assert.Equal(t, types.ErrNotImplemented, err)
+}
@@ -0,0 +1,307 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// memoryMapping is a single line of /proc/[pid]/maps.
+type memoryMapping struct {
+	Start  uint64
+	End    uint64
+	Perms  string // Permissions (e.g. r-xp).
+	Offset uint64
+	Device string
+	Inode  uint64
+	Path   string // Backing file or pseudo-path like [heap]. Empty for anonymous mappings.
+}
+
+// parseMaps parses the content of /proc/[pid]/maps. See proc(5).
+func parseMaps(content []byte) ([]memoryMapping, error) {
+	var mappings []memoryMapping
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+
+		m, err := parseMapsLine(line)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, sc.Err()
+}
+
+// parseMapsLine parses a single mapping line. The same format is used for the
+// header of each entry in /proc/[pid]/smaps.
+func parseMapsLine(line string) (memoryMapping, error) {
+	var m memoryMapping
+
+	// The path is the remainder of the line and may contain spaces.
+	fields := strings.SplitN(line, " ", 6)
+	if len(fields) < 5 {
+		return m, errors.Errorf("failed to parse maps line '%v'", line)
+	}
+
+	addrs := strings.SplitN(fields[0], "-", 2)
+	if len(addrs) != 2 {
+		return m, errors.Errorf("failed to parse address range '%v'", fields[0])
+	}
+
+	var err error
+	if m.Start, err = strconv.ParseUint(addrs[0], 16, 64); err != nil {
+		return m, errors.Wrapf(err, "failed to parse start address '%v'", addrs[0])
+	}
+	if m.End, err = strconv.ParseUint(addrs[1], 16, 64); err != nil {
+		return m, errors.Wrapf(err, "failed to parse end address '%v'", addrs[1])
+	}
+	if m.Offset, err = strconv.ParseUint(fields[2], 16, 64); err != nil {
+		return m, errors.Wrapf(err, "failed to parse offset '%v'", fields[2])
+	}
+	if m.Inode, err = strconv.ParseUint(fields[4], 10, 64); err != nil {
+		return m, errors.Wrapf(err, "failed to parse inode '%v'", fields[4])
+	}
+	m.Perms = fields[1]
+	m.Device = fields[3]
+	if len(fields) == 6 {
+		m.Path = strings.TrimLeft(fields[5], " ")
+	}
+	return m, nil
+}
+
+// parseSmaps parses the content of /proc/[pid]/smaps which is the maps
+// format with a block of "Key: value kB" lines after each mapping.
+func parseSmaps(content []byte) ([]types.MemoryMapInfo, error) {
+	var maps []types.MemoryMapInfo
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+
+		if sep := strings.IndexByte(line, ' '); sep > 0 && line[sep-1] == ':' {
+			if len(maps) == 0 {
+				return nil, errors.Errorf("smaps field before first mapping '%v'", line)
+			}
+			cur := &maps[len(maps)-1]
+
+			var dst *uint64
+			switch line[:sep-1] {
+			case "Rss":
+				dst = &cur.Resident
+			case "Pss":
+				dst = &cur.Proportional
+			case "Swap":
+				dst = &cur.Swap
+			default:
+				continue
+			}
+
+			num, err := parseBytesOrNumber([]byte(line[sep+1:]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse smaps line '%v'", line)
+			}
+			*dst = num
+			continue
+		}
+
+		m, err := parseMapsLine(line)
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, mappingToInfo(m))
+	}
+	return maps, sc.Err()
+}
+
+func mappingToInfo(m memoryMapping) types.MemoryMapInfo {
+	return types.MemoryMapInfo{
+		Start:       m.Start,
+		End:         m.End,
+		Permissions: m.Perms,
+		Path:        m.Path,
+		Offset:      m.Offset,
+		Anonymous:   m.Inode == 0,
+	}
+}
+
+// MemoryMaps returns the memory regions of the process. It reads
+// /proc/[pid]/smaps for the per-region RSS, PSS, and swap and falls back to
+// /proc/[pid]/maps on kernels built without CONFIG_PROC_PAGE_MONITOR.
+func (p *process) MemoryMaps() ([]types.MemoryMapInfo, error) {
+	content, err := p.fs.ReadFile(p.name("smaps"))
+	if err == nil {
+		return parseSmaps(content)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if content, err = p.fs.ReadFile(p.name("maps")); err != nil {
+		return nil, err
+	}
+
+	mappings, err := parseMaps(content)
+	if err != nil {
+		return nil, err
+	}
+
+	maps := make([]types.MemoryMapInfo, 0, len(mappings))
+	for _, m := range mappings {
+		maps = append(maps, mappingToInfo(m))
+	}
+	return maps, nil
+}
+
+// MemoryDetails returns the PSS, USS, and shared memory of the process from
+// /proc/[pid]/smaps_rollup (added in kernel 4.14). It falls back to summing
+// the regions of /proc/[pid]/smaps which is slower for large processes.
+func (p *process) MemoryDetails() (*types.MemoryDetailsInfo, error) {
+	content, err := p.fs.ReadFile(p.name("smaps_rollup"))
+	if os.IsNotExist(err) {
+		content, err = p.fs.ReadFile(p.name("smaps"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseSmapsTotals(content)
+}
+
+// parseSmapsTotals sums the "Key: value kB" lines of smaps or smaps_rollup.
+// smaps_rollup has the same format as smaps with a single [rollup] region.
+func parseSmapsTotals(content []byte) (*types.MemoryDetailsInfo, error) {
+	var info types.MemoryDetailsInfo
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		sep := strings.IndexByte(line, ' ')
+		if sep <= 0 || line[sep-1] != ':' {
+			// Region header.
+			continue
+		}
+
+		var dst *uint64
+		switch line[:sep-1] {
+		case "Rss":
+			dst = &info.Resident
+		case "Pss":
+			dst = &info.Proportional
+		case "Shared_Clean":
+			dst = &info.SharedClean
+		case "Shared_Dirty":
+			dst = &info.SharedDirty
+		case "Private_Clean":
+			dst = &info.PrivateClean
+		case "Private_Dirty":
+			dst = &info.PrivateDirty
+		case "Swap":
+			dst = &info.Swap
+		case "SwapPss":
+			dst = &info.SwapPss
+		default:
+			continue
+		}
+
+		num, err := parseBytesOrNumber([]byte(line[sep+1:]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse smaps line '%v'", line)
+		}
+		*dst += num
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	info.Unique = info.PrivateClean + info.PrivateDirty
+	return &info, nil
+}
+
+// Modules returns the file-backed images that have at least one executable
+// mapping in /proc/[pid]/maps.
+func (p *process) Modules() ([]types.ModuleInfo, error) {
+	content, err := p.fs.ReadFile(p.name("maps"))
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := parseMaps(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappingsToModules(mappings), nil
+}
+
+// mappingsToModules groups the mappings of each file into a single module
+// spanning all of its segments.
+func mappingsToModules(mappings []memoryMapping) []types.ModuleInfo {
+	type module struct {
+		types.ModuleInfo
+		end        uint64
+		executable bool
+	}
+
+	var order []string
+	modules := map[string]*module{}
+	for _, m := range mappings {
+		if m.Inode == 0 || !strings.HasPrefix(m.Path, "/") {
+			// Anonymous memory and pseudo-paths like [stack].
+			continue
+		}
+
+		mod, found := modules[m.Path]
+		if !found {
+			mod = &module{ModuleInfo: types.ModuleInfo{Path: m.Path, BaseAddress: m.Start}, end: m.End}
+			modules[m.Path] = mod
+			order = append(order, m.Path)
+		}
+		if m.Start < mod.BaseAddress {
+			mod.BaseAddress = m.Start
+		}
+		if m.End > mod.end {
+			mod.end = m.End
+		}
+		if strings.Contains(m.Perms, "x") {
+			mod.executable = true
+		}
+	}
+
+	infos := make([]types.ModuleInfo, 0, len(order))
+	for _, path := range order {
+		mod := modules[path]
+		if !mod.executable {
+			continue
+		}
+		mod.Size = mod.end - mod.BaseAddress
+		infos = append(infos, mod.ModuleInfo)
+	}
+	return infos
+}
@@ -0,0 +1,178 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.ModuleEnumerator = (*process)(nil)
+var _ types.MemoryMapEnumerator = (*process)(nil)
+var _ types.MemoryDetails = (*process)(nil)
+
+const testMaps = `55d5c5a00000-55d5c5a08000 r--p 00000000 fd:01 1048602                    /usr/bin/cat
+55d5c5a08000-55d5c5a1d000 r-xp 00008000 fd:01 1048602                    /usr/bin/cat
+55d5c5a1d000-55d5c5a26000 r--p 0001d000 fd:01 1048602                    /usr/bin/cat
+55d5c6b2b000-55d5c6b4c000 rw-p 00000000 00:00 0                          [heap]
+7f0c2a000000-7f0c2a2e2000 r--p 00000000 fd:01 1054343                    /usr/lib/locale/locale-archive
+7f0c2a400000-7f0c2a428000 r--p 00000000 fd:01 1050661                    /usr/lib/x86_64-linux-gnu/libc.so.6
+7f0c2a428000-7f0c2a5bd000 r-xp 00028000 fd:01 1050661                    /usr/lib/x86_64-linux-gnu/libc.so.6
+7f0c2a5bd000-7f0c2a615000 r--p 001bd000 fd:01 1050661                    /usr/lib/x86_64-linux-gnu/libc.so.6
+7f0c2a700000-7f0c2a703000 rw-p 00000000 00:00 0 
+7f0c2a800000-7f0c2a801000 r-xp 00000000 fd:01 1050999                    /tmp/my lib.so (deleted)
+7ffd0c9d5000-7ffd0c9f6000 rw-p 00000000 00:00 0                          [stack]
+ffffffffff600000-ffffffffff601000 --xp 00000000 00:00 0                  [vsyscall]
+`
+
+func TestParseMaps(t *testing.T) {
+	mappings, err := parseMaps([]byte(testMaps))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, mappings, 12) {
+		assert.Equal(t, memoryMapping{
+			Start:  0x55d5c5a08000,
+			End:    0x55d5c5a1d000,
+			Perms:  "r-xp",
+			Offset: 0x8000,
+			Device: "fd:01",
+			Inode:  1048602,
+			Path:   "/usr/bin/cat",
+		}, mappings[1])
+		assert.Equal(t, "[heap]", mappings[3].Path)
+		assert.Equal(t, "", mappings[8].Path)
+		assert.Equal(t, "/tmp/my lib.so (deleted)", mappings[9].Path)
+	}
+
+	_, err = parseMaps([]byte("zzzz-1000 r--p 00000000 fd:01 1 /bin/x\n"))
+	assert.Error(t, err)
+}
+
+func TestMappingsToModules(t *testing.T) {
+	mappings, err := parseMaps([]byte(testMaps))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.ModuleInfo{
+		{Path: "/usr/bin/cat", BaseAddress: 0x55d5c5a00000, Size: 0x26000},
+		{Path: "/usr/lib/x86_64-linux-gnu/libc.so.6", BaseAddress: 0x7f0c2a400000, Size: 0x215000},
+		{Path: "/tmp/my lib.so (deleted)", BaseAddress: 0x7f0c2a800000, Size: 0x1000},
+	}, mappingsToModules(mappings))
+}
+
+func TestParseSmaps(t *testing.T) {
+	content := []byte(`55d5c5a08000-55d5c5a1d000 r-xp 00008000 fd:01 1048602                    /usr/bin/cat
+Size:                 84 kB
+KernelPageSize:        4 kB
+Rss:                  80 kB
+Pss:                  40 kB
+Swap:                  0 kB
+VmFlags: rd ex mr mw me dw
+7f0c2a700000-7f0c2a703000 rwxp 00000000 00:00 0 
+Size:                 12 kB
+Rss:                   8 kB
+Pss:                   8 kB
+Swap:                  4 kB
+VmFlags: rd wr ex mr mw me ac
+`)
+
+	maps, err := parseSmaps(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.MemoryMapInfo{
+		{
+			Start:        0x55d5c5a08000,
+			End:          0x55d5c5a1d000,
+			Permissions:  "r-xp",
+			Path:         "/usr/bin/cat",
+			Offset:       0x8000,
+			Resident:     80 * 1024,
+			Proportional: 40 * 1024,
+		},
+		{
+			Start:        0x7f0c2a700000,
+			End:          0x7f0c2a703000,
+			Permissions:  "rwxp",
+			Anonymous:    true,
+			Resident:     8 * 1024,
+			Proportional: 8 * 1024,
+			Swap:         4 * 1024,
+		},
+	}, maps)
+
+	_, err = parseSmaps([]byte("Rss: 4 kB\n"))
+	assert.Error(t, err)
+}
+
+func TestParseSmapsTotals(t *testing.T) {
+	rollup := []byte(`55d5c5a08000-7ffd3c5fe000 ---p 00000000 00:00 0                          [rollup]
+Rss:                 800 kB
+Pss:                 300 kB
+Shared_Clean:        500 kB
+Shared_Dirty:          0 kB
+Private_Clean:       100 kB
+Private_Dirty:       200 kB
+Swap:                 16 kB
+SwapPss:               8 kB
+`)
+
+	info, err := parseSmapsTotals(rollup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.MemoryDetailsInfo{
+		Resident:     800 * 1024,
+		Proportional: 300 * 1024,
+		Unique:       300 * 1024,
+		SharedClean:  500 * 1024,
+		PrivateClean: 100 * 1024,
+		PrivateDirty: 200 * 1024,
+		Swap:         16 * 1024,
+		SwapPss:      8 * 1024,
+	}, info)
+
+	smaps := []byte(`55d5c5a08000-55d5c5a1d000 r-xp 00008000 fd:01 1048602                    /usr/bin/cat
+Rss:                  80 kB
+Pss:                  40 kB
+Shared_Clean:         80 kB
+Private_Dirty:         0 kB
+VmFlags: rd ex mr mw me dw
+7f0c2a700000-7f0c2a703000 rwxp 00000000 00:00 0 
+Rss:                   8 kB
+Pss:                   8 kB
+Private_Dirty:         8 kB
+VmFlags: rd wr ex mr mw me ac
+`)
+
+	info, err = parseSmapsTotals(smaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 88*1024, info.Resident)
+	assert.EqualValues(t, 48*1024, info.Proportional)
+	assert.EqualValues(t, 8*1024, info.Unique)
+	assert.EqualValues(t, 80*1024, info.SharedClean)
+}
@@ -55,6 +55,26 @@ func parseMemInfo(content []byte) (*types.HostMemoryInfo, error) {
 			memInfo.Metrics[k] = num
 		}
 
+		// These are also kept in Metrics for backwards compatibility.
+		switch k {
+		case "Cached":
+			memInfo.Cached = num
+		case "Buffers":
+			memInfo.Buffers = num
+		case "Slab":
+			memInfo.Slab = num
+		case "PageTables":
+			memInfo.PageTables = num
+		case "Dirty":
+			memInfo.Dirty = num
+		case "HugePages_Total":
+			memInfo.HugePagesTotal = num
+		case "HugePages_Free":
+			memInfo.HugePagesFree = num
+		case "Hugepagesize":
+			memInfo.HugePageSize = num
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -98,3 +118,20 @@ func parseBytesOrNumber(data []byte) (uint64, error) {
 
 	return num * multiplier, nil
 }
+
+// parseVMStat parses the counters in /proc/vmstat.
+func parseVMStat(content []byte) (map[string]uint64, error) {
+	vmstat := map[string]uint64{}
+	err := parseKeyValue(content, " ", func(key, value []byte) error {
+		num, err := strconv.ParseUint(string(bytes.TrimSpace(value)), 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %v value of %v", string(key), string(value))
+		}
+		vmstat[string(key)] = num
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vmstat, nil
+}
@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Namespaces returns the namespaces of the process from /proc/[pid]/ns.
+// Reading the namespaces of processes of other users requires
+// CAP_SYS_PTRACE.
+func (p *process) Namespaces() ([]types.NamespaceInfo, error) {
+	return readNamespaces(p.fs, p.name("ns"))
+}
+
+func readNamespaces(fs fileSystem, dir string) ([]types.NamespaceInfo, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]types.NamespaceInfo, 0, len(entries))
+	for _, e := range entries {
+		link, err := fs.Readlink(path.Join(dir, e.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The process exited.
+				continue
+			}
+			return nil, err
+		}
+
+		inode, err := parseNamespaceLink(link)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, types.NamespaceInfo{Type: e.Name(), Inode: inode})
+	}
+	return namespaces, nil
+}
+
+// parseNamespaceLink returns the inode from a link target like
+// net:[4026531993].
+func parseNamespaceLink(link string) (uint64, error) {
+	start := strings.IndexByte(link, '[')
+	if start < 0 || !strings.HasSuffix(link, "]") {
+		return 0, errors.Errorf("unexpected namespace link %q", link)
+	}
+	return strconv.ParseUint(link[start+1:len(link)-1], 10, 64)
+}
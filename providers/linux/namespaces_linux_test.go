@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Namespaces = (*process)(nil)
+
+func TestReadNamespaces(t *testing.T) {
+	namespaces, err := readNamespaces(dirFS("testdata/ubuntu1710"), "proc/1/ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.NamespaceInfo{
+		{Type: "ipc", Inode: 4026531839},
+		{Type: "net", Inode: 4026532008},
+		{Type: "pid", Inode: 4026531836},
+		{Type: "pid_for_children", Inode: 4026532010},
+	}, namespaces)
+
+	other := []types.NamespaceInfo{
+		{Type: "ipc", Inode: 4026531839},
+		{Type: "net", Inode: 4026531993},
+		{Type: "pid", Inode: 4026531836},
+	}
+	assert.True(t, types.SameNamespace(namespaces, other, "pid"))
+	assert.False(t, types.SameNamespace(namespaces, other, "net"))
+	assert.False(t, types.SameNamespace(namespaces, other, "pid_for_children"))
+	assert.Equal(t, []string{"ipc", "pid"}, types.SharedNamespaces(namespaces, other))
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"github.com/prometheus/procfs"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// NetworkInterfaces returns the network interfaces of the host with the
+// counters from /proc/net/dev.
+func (h *host) NetworkInterfaces() ([]types.NetworkInterfaceInfo, error) {
+	ifcs, err := shared.NetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	dev, err := h.procFS.NewNetDev()
+	if err != nil {
+		return nil, err
+	}
+
+	addNetDevCounters(ifcs, dev)
+	return ifcs, nil
+}
+
+func addNetDevCounters(ifcs []types.NetworkInterfaceInfo, dev procfs.NetDev) {
+	for i, ifc := range ifcs {
+		line, found := dev[ifc.Name]
+		if !found {
+			continue
+		}
+
+		ifcs[i].Counters = &types.NetworkInterfaceCounters{
+			RxBytes:   line.RxBytes,
+			RxPackets: line.RxPackets,
+			RxErrors:  line.RxErrors,
+			RxDropped: line.RxDropped,
+			TxBytes:   line.TxBytes,
+			TxPackets: line.TxPackets,
+			TxErrors:  line.TxErrors,
+			TxDropped: line.TxDropped,
+		}
+	}
+}
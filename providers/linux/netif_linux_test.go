@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.NetworkInterfaces = (*host)(nil)
+
+func TestAddNetDevCounters(t *testing.T) {
+	ifcs := []types.NetworkInterfaceInfo{
+		{Index: 1, Name: "lo"},
+		{Index: 2, Name: "eth0"},
+	}
+	dev := procfs.NetDev{
+		"eth0": procfs.NetDevLine{
+			Name:      "eth0",
+			RxBytes:   1000,
+			RxPackets: 10,
+			RxErrors:  1,
+			RxDropped: 2,
+			RxFIFO:    3,
+			TxBytes:   2000,
+			TxPackets: 20,
+			TxErrors:  4,
+			TxDropped: 5,
+		},
+	}
+
+	addNetDevCounters(ifcs, dev)
+	assert.Nil(t, ifcs[0].Counters)
+	assert.Equal(t, &types.NetworkInterfaceCounters{
+		RxBytes:   1000,
+		RxPackets: 10,
+		RxErrors:  1,
+		RxDropped: 2,
+		TxBytes:   2000,
+		TxPackets: 20,
+		TxErrors:  4,
+		TxDropped: 5,
+	}, ifcs[1].Counters)
+}
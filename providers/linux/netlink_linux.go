@@ -0,0 +1,180 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Constants from linux/netlink.h and linux/genetlink.h.
+const (
+	netlinkSockDiag = 4  // NETLINK_SOCK_DIAG
+	netlinkGeneric  = 16 // NETLINK_GENERIC
+
+	nlaHdrLen   = 4
+	nlaTypeMask = 0x3fff // ~(NLA_F_NESTED | NLA_F_NET_BYTEORDER)
+
+	genlHdrLen         = 4
+	genlIDCtrl         = 0x10 // GENL_ID_CTRL
+	ctrlCmdGetFamily   = 3    // CTRL_CMD_GETFAMILY
+	ctrlAttrFamilyID   = 1    // CTRL_ATTR_FAMILY_ID
+	ctrlAttrFamilyName = 2    // CTRL_ATTR_FAMILY_NAME
+
+	netlinkRecvBufSize = 32 * 1024
+)
+
+// netlinkRequest sends a request to the kernel and returns the data of the
+// response messages. Multipart (dump) responses are read until NLMSG_DONE.
+func netlinkRequest(proto int, msgType, flags uint16, payload []byte) ([][]byte, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create netlink socket")
+	}
+	defer syscall.Close(fd)
+
+	msg := make([]byte, syscall.NLMSG_HDRLEN+len(payload))
+	nativeEndian.PutUint32(msg[0:], uint32(len(msg)))
+	nativeEndian.PutUint16(msg[4:], msgType)
+	nativeEndian.PutUint16(msg[6:], flags|syscall.NLM_F_REQUEST)
+	nativeEndian.PutUint32(msg[8:], 1) // Sequence number.
+	copy(msg[syscall.NLMSG_HDRLEN:], payload)
+
+	if err = syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, errors.Wrap(err, "failed to send netlink request")
+	}
+
+	var responses [][]byte
+	buf := make([]byte, netlinkRecvBufSize)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to receive netlink response")
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse netlink response")
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return responses, nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) < 4 {
+					return nil, errors.New("truncated netlink error")
+				}
+				if code := int32(nativeEndian.Uint32(m.Data)); code != 0 {
+					return nil, syscall.Errno(-code)
+				}
+				return responses, nil
+			}
+
+			data := make([]byte, len(m.Data))
+			copy(data, m.Data)
+			responses = append(responses, data)
+
+			if m.Header.Flags&syscall.NLM_F_MULTI == 0 {
+				return responses, nil
+			}
+		}
+	}
+}
+
+// netlinkAttr encodes a netlink attribute (struct nlattr).
+func netlinkAttr(typ uint16, data []byte) []byte {
+	b := make([]byte, nlaAlign(nlaHdrLen+len(data)))
+	nativeEndian.PutUint16(b[0:], uint16(nlaHdrLen+len(data)))
+	nativeEndian.PutUint16(b[2:], typ)
+	copy(b[nlaHdrLen:], data)
+	return b
+}
+
+// parseNetlinkAttrs returns the payloads of the netlink attributes in b by
+// type.
+func parseNetlinkAttrs(b []byte) map[uint16][]byte {
+	attrs := map[uint16][]byte{}
+	for len(b) >= nlaHdrLen {
+		length := int(nativeEndian.Uint16(b[0:]))
+		if length < nlaHdrLen || length > len(b) {
+			break
+		}
+		attrs[nativeEndian.Uint16(b[2:])&nlaTypeMask] = b[nlaHdrLen:length]
+
+		if next := nlaAlign(length); next < len(b) {
+			b = b[next:]
+		} else {
+			break
+		}
+	}
+	return attrs
+}
+
+func nlaAlign(n int) int {
+	return (n + syscall.NLMSG_ALIGNTO - 1) & ^(syscall.NLMSG_ALIGNTO - 1)
+}
+
+// genlRequest sends a generic netlink command and returns the attributes of
+// each response message.
+func genlRequest(family uint16, cmd, version uint8, flags uint16, attrs ...[]byte) ([]map[uint16][]byte, error) {
+	payload := make([]byte, genlHdrLen)
+	payload[0], payload[1] = cmd, version
+	for _, attr := range attrs {
+		payload = append(payload, attr...)
+	}
+
+	responses, err := netlinkRequest(netlinkGeneric, family, flags, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]map[uint16][]byte, 0, len(responses))
+	for _, data := range responses {
+		if len(data) < genlHdrLen {
+			continue
+		}
+		msgs = append(msgs, parseNetlinkAttrs(data[genlHdrLen:]))
+	}
+	return msgs, nil
+}
+
+// genlFamilyID resolves the ID of a generic netlink family (e.g. TASKSTATS).
+func genlFamilyID(name string) (uint16, error) {
+	msgs, err := genlRequest(genlIDCtrl, ctrlCmdGetFamily, 1, 0,
+		netlinkAttr(ctrlAttrFamilyName, append([]byte(name), 0)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to resolve generic netlink family %v", name)
+	}
+	for _, attrs := range msgs {
+		if id, found := attrs[ctrlAttrFamilyID]; found && len(id) >= 2 {
+			return nativeEndian.Uint16(id), nil
+		}
+	}
+	return 0, errors.Errorf("generic netlink family %v not found", name)
+}
+
+// uint32Bytes returns v in native byte order.
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	nativeEndian.PutUint32(b, v)
+	return b
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path"
+	"strconv"
+
+	"github.com/prometheus/procfs"
+)
+
+// netNamespace is a network namespace and the procfs net directory that
+// lists its sockets.
+type netNamespace struct {
+	inode  uint64 // Zero if unknown.
+	netDir string
+}
+
+// netNamespaces returns the network namespace of the current process
+// followed by the other network namespaces used by procs. The sockets of a
+// namespace are read from the net directory of one of its processes
+// (/proc/[pid]/net). Identifying the namespace of a process owned by another
+// user requires CAP_SYS_PTRACE, so those namespaces are only found when
+// running as root.
+func netNamespaces(fs fileSystem, procs procfs.Procs) []netNamespace {
+	self := netNamespace{netDir: "proc/net"}
+	self.inode, _ = netNamespaceInode(fs, "proc/self/ns/net")
+
+	namespaces := []netNamespace{self}
+	seen := map[uint64]struct{}{self.inode: {}}
+	for _, proc := range procs {
+		pid := strconv.Itoa(proc.PID)
+		inode, err := netNamespaceInode(fs, path.Join("proc", pid, "ns", "net"))
+		if err != nil {
+			continue
+		}
+		if _, found := seen[inode]; found {
+			continue
+		}
+		seen[inode] = struct{}{}
+		namespaces = append(namespaces, netNamespace{inode: inode, netDir: path.Join("proc", pid, "net")})
+	}
+	return namespaces
+}
+
+// netNamespaceInode returns the inode of the network namespace from a
+// ns/net link.
+func netNamespaceInode(fs fileSystem, link string) (uint64, error) {
+	target, err := fs.Readlink(link)
+	if err != nil {
+		return 0, err
+	}
+	return parseNamespaceLink(target)
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestNetNamespaces(t *testing.T) {
+	s := newLinuxSystem("testdata/ubuntu1710")
+	procs, err := s.procFS.AllProcs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespaces := netNamespaces(s.fs, procs)
+	assert.Equal(t, []netNamespace{
+		{inode: 4026531993, netDir: "proc/net"},
+		{inode: 4026532008, netDir: "proc/1/net"},
+	}, namespaces)
+
+	// The sockets of a container are only listed in its namespace.
+	conns, err := readNetNamespaceSockets(s.fs, namespaces[1].netDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.NetworkConnection{
+		{
+			Family:    types.FamilyIPv4,
+			Type:      types.ProtocolTCP,
+			LocalIP:   net.IPv4zero.To4(),
+			LocalPort: 80,
+			State:     types.TCPStateListen,
+			UID:       "0",
+			Inode:     4039108,
+		},
+	}, conns)
+}
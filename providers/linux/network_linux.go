@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"github.com/prometheus/procfs"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func (s linuxSystem) Network() (types.Network, error) {
+	return &network{procFS: s.procFS, fs: s.fs}, nil
+}
+
+type network struct {
+	procFS procfs.FS
+	fs     fileSystem
+}
+
+// Connections returns the sockets of the given kind on the host. The sockets
+// of each network namespace that is used by a readable process are included
+// (e.g. those of containers). The owning PID is resolved by matching socket
+// inodes with the open file descriptors of all processes that are readable
+// by the current user. When reading the live procfs the TCP and UDP sockets
+// of the current network namespace are listed with sock_diag over netlink.
+func (n *network) Connections(kind string) ([]types.NetworkConnection, error) {
+	match, err := shared.ConnectionKindFilter(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	procs, err := n.procFS.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	sockDiag := isProcFS(string(n.procFS))
+
+	var conns []types.NetworkConnection
+	for i, ns := range netNamespaces(n.fs, procs) {
+		nsConns, err := readNetNamespaceSockets(n.fs, ns.netDir, sockDiag && i == 0)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			// The last process in the namespace exited.
+			continue
+		}
+		for _, c := range nsConns {
+			if match(c) {
+				c.NetNS = ns.inode
+				conns = append(conns, c)
+			}
+		}
+	}
+	if len(conns) == 0 {
+		return nil, nil
+	}
+
+	owners := socketOwners(procs)
+	for i, c := range conns {
+		conns[i].PID = owners[c.Inode]
+	}
+	return conns, nil
+}
+
+// readNetNamespaceSockets returns the TCP, UDP, and Unix domain sockets
+// listed in a net directory of procfs. If sockDiag is true then the TCP and
+// UDP sockets are queried using sock_diag, falling back to the socket tables
+// if that fails (e.g. the kernel lacks inet_diag support).
+func readNetNamespaceSockets(fs fileSystem, netDir string, sockDiag bool) ([]types.NetworkConnection, error) {
+	var conns []types.NetworkConnection
+	var err error
+	if sockDiag {
+		conns, err = sockDiagConnections()
+	}
+	if !sockDiag || err != nil {
+		if conns, err = readSocketTables(fs, netDir); err != nil {
+			return nil, err
+		}
+	}
+	unixConns, err := readUnixSocketTable(fs, netDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(conns, unixConns...), nil
+}
+
+// socketOwners returns a mapping of socket inode to the PID of the process
+// that has it open. Processes that cannot be read are ignored.
+func socketOwners(procs procfs.Procs) map[uint64]int {
+	owners := map[uint64]int{}
+	for _, proc := range procs {
+		targets, err := proc.FileDescriptorTargets()
+		if err != nil {
+			continue
+		}
+
+		for inode := range socketInodes(targets) {
+			owners[inode] = proc.PID
+		}
+	}
+	return owners
+}
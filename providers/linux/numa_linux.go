@@ -0,0 +1,133 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var nodeDirRegexp = regexp.MustCompile(`^node[0-9]+$`)
+
+// NUMA returns the NUMA nodes from /sys/devices/system/node.
+func (h *host) NUMA() (*types.NUMAInfo, error) {
+	return readNUMANodes(h.fs, "sys/devices/system/node")
+}
+
+// readNUMANodes reads the CPU list and meminfo of each node directory.
+func readNUMANodes(fs fileSystem, dir string) (*types.NUMAInfo, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.NUMAInfo{}
+	for _, e := range entries {
+		if !nodeDirRegexp.MatchString(e.Name()) {
+			continue
+		}
+		id, _ := strconv.Atoi(strings.TrimPrefix(e.Name(), "node"))
+		node := types.NUMANodeInfo{ID: id}
+
+		if list := readAttr(fs, path.Join(dir, e.Name(), "cpulist")); list != "" {
+			if node.CPUs, err = parseCPUList(list); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse cpulist of %v", e.Name())
+			}
+		}
+
+		if content, err := fs.ReadFile(path.Join(dir, e.Name(), "meminfo")); err == nil {
+			if err = parseNodeMemInfo(content, &node); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse meminfo of %v", e.Name())
+			}
+		}
+
+		info.Nodes = append(info.Nodes, node)
+	}
+
+	sort.Slice(info.Nodes, func(i, j int) bool { return info.Nodes[i].ID < info.Nodes[j].ID })
+	return info, nil
+}
+
+// parseCPUList parses the kernel list format (e.g. 0-3,8,10-11).
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	for _, r := range strings.Split(strings.TrimSpace(list), ",") {
+		if r == "" {
+			continue
+		}
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// parseNodeMemInfo parses a node meminfo file. Lines have the form
+// "Node 0 MemTotal:       16310860 kB".
+func parseNodeMemInfo(content []byte, node *types.NUMANodeInfo) error {
+	err := parseKeyValue(content, ":", func(key, value []byte) error {
+		fields := bytes.Fields(key)
+		if len(fields) == 0 {
+			return nil
+		}
+
+		var dst *uint64
+		switch string(fields[len(fields)-1]) {
+		case "MemTotal":
+			dst = &node.MemoryTotal
+		case "MemFree":
+			dst = &node.MemoryFree
+		default:
+			return nil
+		}
+
+		num, err := parseBytesOrNumber(value)
+		if err != nil {
+			return err
+		}
+		*dst = num
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if node.MemoryTotal >= node.MemoryFree {
+		node.MemoryUsed = node.MemoryTotal - node.MemoryFree
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.NUMA = (*host)(nil)
+
+func TestHostNUMA(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numa, err := host.(types.NUMA).NUMA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.NUMAInfo{
+		Nodes: []types.NUMANodeInfo{
+			{
+				ID:          0,
+				CPUs:        []int{0, 1},
+				MemoryTotal: 2021188 * 1024,
+				MemoryFree:  999996 * 1024,
+				MemoryUsed:  1021192 * 1024,
+			},
+			{
+				ID:          1,
+				CPUs:        []int{2, 3},
+				MemoryTotal: 2021188 * 1024,
+				MemoryFree:  1521188 * 1024,
+				MemoryUsed:  500000 * 1024,
+			},
+		},
+	}, numa)
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3,8,10-11\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 8, 10, 11}, cpus)
+
+	_, err = parseCPUList("0-x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// OOMScore returns the OOM killer score and score adjustment of the process.
+func (p *process) OOMScore() (*types.OOMScoreInfo, error) {
+	score, err := readAttrInt(p.fs, p.name("oom_score"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read oom_score")
+	}
+	adj, err := readAttrInt(p.fs, p.name("oom_score_adj"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read oom_score_adj")
+	}
+	return &types.OOMScoreInfo{Score: int(score), ScoreAdj: int(adj)}, nil
+}
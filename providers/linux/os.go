@@ -20,7 +20,6 @@ package linux
 import (
 	"bufio"
 	"bytes"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -34,6 +33,8 @@ import (
 
 const (
 	osRelease      = "/etc/os-release"
+	osReleaseLib   = "/usr/lib/os-release"
+	ostreeBooted   = "/run/ostree-booted"
 	lsbRelease     = "/etc/lsb-release"
 	distribRelease = "/etc/*-release"
 	versionGrok    = `(?P<version>(?P<major>[0-9]+)\.?(?P<minor>[0-9]+)?\.?(?P<patch>\w+)?)(?: \((?P<codename>\w+)\))?`
@@ -55,6 +56,18 @@ var familyMap = map[string][]string{
 
 var platformToFamilyMap map[string]string
 
+// immutablePlatforms are the IDs and VARIANT_IDs of image based
+// distributions.
+var immutablePlatforms = map[string]struct{}{
+	"bottlerocket": {},
+	"coreos":       {},
+	"flatcar":      {},
+	"kinoite":      {},
+	"rhcos":        {},
+	"silverblue":   {},
+	"talos":        {},
+}
+
 func init() {
 	platformToFamilyMap = map[string]string{}
 	for family, platformList := range familyMap {
@@ -65,14 +78,19 @@ func init() {
 }
 
 func OperatingSystem() (*types.OSInfo, error) {
-	return getOSInfo("")
+	return getOSInfo(dirFS("/"))
 }
 
-func getOSInfo(baseDir string) (*types.OSInfo, error) {
-	osInfo, err := getOSRelease(baseDir)
+func getOSInfo(fs fileSystem) (*types.OSInfo, error) {
+	osInfo, err := getOSRelease(fs)
 	if err != nil {
 		// Fallback
-		return findDistribRelease(baseDir)
+		return findDistribRelease(fs)
+	}
+
+	// rpm-ostree based distributions (e.g. Silverblue) create this file.
+	if _, err := fs.Stat(ostreeBooted); err == nil {
+		osInfo.Immutable = true
 	}
 
 	// For the redhat family, enrich version info with data from
@@ -82,7 +100,7 @@ func getOSInfo(baseDir string) (*types.OSInfo, error) {
 		return osInfo, nil
 	}
 
-	distInfo, err := findDistribRelease(baseDir)
+	distInfo, err := findDistribRelease(fs)
 	if err != nil {
 		return osInfo, err
 	}
@@ -93,10 +111,15 @@ func getOSInfo(baseDir string) (*types.OSInfo, error) {
 	return osInfo, nil
 }
 
-func getOSRelease(baseDir string) (*types.OSInfo, error) {
-	lsbRel, _ := ioutil.ReadFile(filepath.Join(baseDir, lsbRelease))
+func getOSRelease(fs fileSystem) (*types.OSInfo, error) {
+	lsbRel, _ := fs.ReadFile(lsbRelease)
 
-	osRel, err := ioutil.ReadFile(filepath.Join(baseDir, osRelease))
+	// /etc/os-release takes precedence, but some image based distributions
+	// only ship /usr/lib/os-release.
+	osRel, err := fs.ReadFile(osRelease)
+	if os.IsNotExist(err) {
+		osRel, err = fs.ReadFile(osReleaseLib)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -128,9 +151,11 @@ func parseOSRelease(content []byte) (*types.OSInfo, error) {
 		val := string(bytes.TrimSpace(parts[1]))
 		fields[key] = val
 
-		// Trim quotes.
-		val, err := strconv.Unquote(val)
-		if err == nil {
+		// Trim quotes. Single quotes are allowed by the shell syntax of
+		// os-release(5) but not by strconv.Unquote.
+		if len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'' {
+			fields[key] = strings.TrimSpace(val[1 : len(val)-1])
+		} else if val, err := strconv.Unquote(val); err == nil {
 			fields[key] = strings.TrimSpace(val)
 		}
 	}
@@ -149,6 +174,17 @@ func makeOSInfo(osRelease map[string]string) (*types.OSInfo, error) {
 		Version:  osRelease["VERSION"],
 		Build:    osRelease["BUILD_ID"],
 		Codename: osRelease["VERSION_CODENAME"],
+
+		Variant:      osRelease["VARIANT"],
+		ImageID:      osRelease["IMAGE_ID"],
+		ImageVersion: osRelease["IMAGE_VERSION"],
+	}
+	if os.Variant == "" {
+		os.Variant = osRelease["VARIANT_ID"]
+	}
+	if os.ImageVersion == "" {
+		// rpm-ostree reports the version of the deployed commit.
+		os.ImageVersion = osRelease["OSTREE_VERSION"]
 	}
 
 	if os.Codename == "" {
@@ -190,32 +226,45 @@ func makeOSInfo(osRelease map[string]string) (*types.OSInfo, error) {
 	}
 
 	os.Family = platformToFamilyMap[strings.ToLower(os.Platform)]
+	if os.Family == "" {
+		// Derivatives list their parent distributions in ID_LIKE.
+		for _, like := range strings.Fields(osRelease["ID_LIKE"]) {
+			if family, found := platformToFamilyMap[like]; found {
+				os.Family = family
+				break
+			}
+		}
+	}
+
+	_, platformImmutable := immutablePlatforms[os.Platform]
+	_, variantImmutable := immutablePlatforms[osRelease["VARIANT_ID"]]
+	os.Immutable = platformImmutable || variantImmutable || osRelease["OSTREE_VERSION"] != ""
 	return os, nil
 }
 
-func findDistribRelease(baseDir string) (*types.OSInfo, error) {
-	matches, err := filepath.Glob(filepath.Join(baseDir, distribRelease))
+func findDistribRelease(fs fileSystem) (*types.OSInfo, error) {
+	matches, err := fs.Glob(distribRelease)
 	if err != nil {
 		return nil, err
 	}
 	for _, path := range matches {
-		if strings.HasSuffix(path, osRelease) || strings.HasSuffix(path, lsbRelease) {
+		if "/"+path == osRelease || "/"+path == lsbRelease {
 			continue
 		}
 
-		info, err := os.Lstat(path)
+		info, err := fs.Lstat(path)
 		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
 			continue
 		}
 
-		return getDistribRelease(path)
+		return getDistribRelease(fs, path)
 	}
 
 	return nil, errors.New("no /etc/<distrib>-release file found")
 }
 
-func getDistribRelease(file string) (*types.OSInfo, error) {
-	data, err := ioutil.ReadFile(file)
+func getDistribRelease(fs fileSystem, file string) (*types.OSInfo, error) {
+	data, err := fs.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +27,7 @@ import (
 
 func TestOperatingSystem(t *testing.T) {
 	t.Run("centos6", func(t *testing.T) {
-		os, err := getOSInfo("testdata/centos6")
+		os, err := getOSInfo(dirFS("testdata/centos6"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -43,7 +43,7 @@ func TestOperatingSystem(t *testing.T) {
 		t.Logf("%#v", os)
 	})
 	t.Run("centos7", func(t *testing.T) {
-		os, err := getOSInfo("testdata/centos7")
+		os, err := getOSInfo(dirFS("testdata/centos7"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -60,7 +60,7 @@ func TestOperatingSystem(t *testing.T) {
 		t.Logf("%#v", os)
 	})
 	t.Run("debian9", func(t *testing.T) {
-		os, err := getOSInfo("testdata/debian9")
+		os, err := getOSInfo(dirFS("testdata/debian9"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -75,7 +75,7 @@ func TestOperatingSystem(t *testing.T) {
 		t.Logf("%#v", os)
 	})
 	t.Run("ubuntu1404", func(t *testing.T) {
-		os, err := getOSInfo("testdata/ubuntu1404")
+		os, err := getOSInfo(dirFS("testdata/ubuntu1404"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -92,7 +92,7 @@ func TestOperatingSystem(t *testing.T) {
 		t.Logf("%#v", os)
 	})
 	t.Run("ubuntu1710", func(t *testing.T) {
-		os, err := getOSInfo("testdata/ubuntu1710")
+		os, err := getOSInfo(dirFS("testdata/ubuntu1710"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -108,4 +108,58 @@ func TestOperatingSystem(t *testing.T) {
 		}, *os)
 		t.Logf("%#v", os)
 	})
+	t.Run("fedora36-silverblue", func(t *testing.T) {
+		os, err := getOSInfo(dirFS("testdata/fedora36-silverblue"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, types.OSInfo{
+			Family:       "redhat",
+			Platform:     "fedora",
+			Name:         "Fedora Linux",
+			Version:      "36.20220505.0 (Silverblue)",
+			Major:        36,
+			Minor:        0,
+			Patch:        0,
+			Variant:      "Silverblue",
+			ImageVersion: "36.20220505.0",
+			Immutable:    true,
+		}, *os)
+		t.Logf("%#v", os)
+	})
+	t.Run("flatcar", func(t *testing.T) {
+		os, err := getOSInfo(dirFS("testdata/flatcar"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, types.OSInfo{
+			Platform:  "flatcar",
+			Name:      "Flatcar Container Linux by Kinvolk",
+			Version:   "3139.2.0",
+			Major:     3139,
+			Minor:     2,
+			Patch:     0,
+			Build:     "2022-04-05-1803",
+			Immutable: true,
+		}, *os)
+		t.Logf("%#v", os)
+	})
+	t.Run("bottlerocket", func(t *testing.T) {
+		os, err := getOSInfo(dirFS("testdata/bottlerocket"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, types.OSInfo{
+			Platform:  "bottlerocket",
+			Name:      "Bottlerocket",
+			Version:   "1.8.0 (aws-k8s-1.22)",
+			Major:     1,
+			Minor:     8,
+			Patch:     0,
+			Build:     "a6233c22",
+			Variant:   "aws-k8s-1.22",
+			Immutable: true,
+		}, *os)
+		t.Logf("%#v", os)
+	})
 }
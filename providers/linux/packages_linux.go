@@ -0,0 +1,244 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	dpkgStatusFile   = "var/lib/dpkg/status"
+	apkInstalledFile = "lib/apk/db/installed"
+)
+
+// rpmDirs are the locations of the rpm database. Newer distributions moved it
+// to /usr/lib/sysimage/rpm and keep /var/lib/rpm as a symlink.
+var rpmDirs = []string{"var/lib/rpm", "usr/lib/sysimage/rpm"}
+
+// Packages reads the dpkg, rpm, and apk databases. A host may have more than
+// one package manager so all databases that exist are read. Both the sqlite
+// (rpm 4.16+) and the Berkeley DB rpm databases are supported.
+func (h *host) Packages() ([]types.PackageInfo, error) {
+	return installedPackages(h.fs)
+}
+
+func installedPackages(fs fileSystem) ([]types.PackageInfo, error) {
+	var pkgs []types.PackageInfo
+	var errs multierror.Errors
+
+	if content, err := fs.ReadFile(dpkgStatusFile); err == nil {
+		dpkg := parseDpkgStatus(content)
+		for i := range dpkg {
+			dpkg[i].InstallTime = dpkgInstallTime(fs, dpkg[i])
+		}
+		pkgs = append(pkgs, dpkg...)
+	} else if !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+
+	if content, err := fs.ReadFile(apkInstalledFile); err == nil {
+		pkgs = append(pkgs, parseAPKInstalled(content)...)
+	} else if !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+
+	for _, dir := range rpmDirs {
+		rpm, found, err := readRPMDir(fs, dir)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		pkgs = append(pkgs, rpm...)
+		if found {
+			break
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+	return pkgs, errs.Err()
+}
+
+// readRPMDir reads the rpm database in dir. The sqlite database
+// (rpmdb.sqlite) is preferred over the Berkeley DB database (Packages) that
+// may be left behind after rpm converted it. found is false if dir contains
+// no database.
+func readRPMDir(fs fileSystem, dir string) (pkgs []types.PackageInfo, found bool, err error) {
+	name := dir + "/rpmdb.sqlite"
+	if f, err := fs.Open(name); err == nil {
+		defer f.Close()
+
+		var wal io.ReaderAt
+		if w, err := fs.Open(name + "-wal"); err == nil {
+			defer w.Close()
+			wal = w
+		} else if !os.IsNotExist(err) {
+			return nil, true, err
+		}
+
+		pkgs, err := readRPMSQLite(f, wal)
+		return pkgs, true, errors.Wrapf(err, "failed to read rpm database /%v", name)
+	} else if !os.IsNotExist(err) {
+		return nil, true, err
+	}
+
+	name = dir + "/Packages"
+	f, err := fs.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, true, err
+	}
+	defer f.Close()
+
+	pkgs, err = readRPMDatabase(f)
+	return pkgs, true, errors.Wrapf(err, "failed to read rpm database /%v", name)
+}
+
+// parseStanzas calls fn with the fields of each paragraph of an RFC 822 style
+// file. Continuation lines are appended to the previous field.
+func parseStanzas(content []byte, fn func(fields map[string]string)) {
+	fields := map[string]string{}
+	var last string
+	flush := func() {
+		if len(fields) > 0 {
+			fn(fields)
+			fields = map[string]string{}
+		}
+		last = ""
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case line[0] == ' ' || line[0] == '\t':
+			if last != "" {
+				fields[last] += "\n" + strings.TrimSpace(line)
+			}
+		default:
+			sep := strings.IndexByte(line, ':')
+			if sep <= 0 {
+				continue
+			}
+			last = line[:sep]
+			fields[last] = strings.TrimSpace(line[sep+1:])
+		}
+	}
+	flush()
+}
+
+// parseDpkgStatus returns the installed packages from /var/lib/dpkg/status.
+// Packages that were removed but whose configuration files remain are
+// omitted.
+func parseDpkgStatus(content []byte) []types.PackageInfo {
+	var pkgs []types.PackageInfo
+	parseStanzas(content, func(f map[string]string) {
+		status := strings.Fields(f["Status"])
+		if len(status) != 3 || status[2] != "installed" || f["Package"] == "" {
+			return
+		}
+
+		summary := f["Description"]
+		if idx := strings.IndexByte(summary, '\n'); idx >= 0 {
+			summary = summary[:idx]
+		}
+		kB, _ := strconv.ParseUint(f["Installed-Size"], 10, 64)
+
+		pkgs = append(pkgs, types.PackageInfo{
+			Name:    f["Package"],
+			Version: f["Version"],
+			Arch:    f["Architecture"],
+			Summary: summary,
+			Vendor:  f["Maintainer"],
+			Size:    kB * 1024,
+			Type:    types.PackageTypeDpkg,
+		})
+	})
+	return pkgs
+}
+
+// dpkgInstallTime returns the modification time of the package's file list
+// because dpkg does not record when a package was installed. Multi-arch
+// packages are listed as <name>:<arch>.list.
+func dpkgInstallTime(fs fileSystem, pkg types.PackageInfo) time.Time {
+	for _, name := range []string{pkg.Name + ":" + pkg.Arch, pkg.Name} {
+		if fi, err := fs.Stat("var/lib/dpkg/info/" + name + ".list"); err == nil {
+			return fi.ModTime()
+		}
+	}
+	return time.Time{}
+}
+
+// parseAPKInstalled returns the packages from the Alpine apk database. Each
+// line is a single letter field name, a colon, and the value.
+func parseAPKInstalled(content []byte) []types.PackageInfo {
+	var pkgs []types.PackageInfo
+	var pkg types.PackageInfo
+	flush := func() {
+		if pkg.Name != "" {
+			pkg.Type = types.PackageTypeAPK
+			pkgs = append(pkgs, pkg)
+		}
+		pkg = types.PackageInfo{}
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			pkg.Name = value
+		case 'V':
+			pkg.Version = value
+		case 'A':
+			pkg.Arch = value
+		case 'T':
+			pkg.Summary = value
+		case 'm':
+			pkg.Vendor = value
+		case 'I':
+			pkg.Size, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+	flush()
+	return pkgs
+}
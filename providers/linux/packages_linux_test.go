@@ -0,0 +1,295 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.PackageEnumerator = (*host)(nil)
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Priority: optional
+Installed-Size: 10680
+Maintainer: Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>
+Architecture: amd64
+Multi-Arch: same
+Version: 2.26-0ubuntu2
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: vim-tiny
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 2:8.0.0095-1ubuntu3
+
+Package: bash
+Status: install ok installed
+Installed-Size: 1588
+Architecture: amd64
+Version: 4.4-5ubuntu1
+Description: GNU Bourne Again SHell
+`
+
+const apkInstalled = `C:Q1n0/uhvt7yGqPeXz5o2FbTs0/Gzc=
+P:musl
+V:1.1.24-r2
+A:x86_64
+S:377212
+I:614400
+T:the musl c library (libc) implementation
+m:Timo Teräs <timo.teras@iki.fi>
+
+P:busybox
+V:1.31.1-r9
+A:x86_64
+I:946176
+T:Size optimized toolbox of many common UNIX utilities
+`
+
+func TestInstalledPackages(t *testing.T) {
+	pkgs, err := installedPackages(mapFS{
+		dpkgStatusFile:   dpkgStatus,
+		apkInstalledFile: apkInstalled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.PackageInfo{
+		{Name: "bash", Version: "4.4-5ubuntu1", Arch: "amd64", Summary: "GNU Bourne Again SHell", Size: 1588 * 1024, Type: types.PackageTypeDpkg},
+		{Name: "busybox", Version: "1.31.1-r9", Arch: "x86_64", Summary: "Size optimized toolbox of many common UNIX utilities", Size: 946176, Type: types.PackageTypeAPK},
+		{Name: "libc6", Version: "2.26-0ubuntu2", Arch: "amd64", Summary: "GNU C Library: Shared libraries", Vendor: "Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>", Size: 10680 * 1024, Type: types.PackageTypeDpkg},
+		{Name: "musl", Version: "1.1.24-r2", Arch: "x86_64", Summary: "the musl c library (libc) implementation", Vendor: "Timo Teräs <timo.teras@iki.fi>", Size: 614400, Type: types.PackageTypeAPK},
+	}, pkgs)
+
+	pkgs, err = installedPackages(mapFS{})
+	assert.NoError(t, err)
+	assert.Empty(t, pkgs)
+}
+
+type rpmTag struct {
+	tag, typ uint32
+	data     []byte
+}
+
+func rpmString(tag uint32, s string) rpmTag {
+	return rpmTag{tag, rpmTypeString, append([]byte(s), 0)}
+}
+
+func rpmInt32(tag, v uint32) rpmTag {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, v)
+	return rpmTag{tag, rpmTypeInt32, data}
+}
+
+// rpmHeaderBlob builds a header in the format stored in the rpm database.
+func rpmHeaderBlob(tags ...rpmTag) []byte {
+	var index, store bytes.Buffer
+	for _, t := range tags {
+		binary.Write(&index, binary.BigEndian, [4]uint32{t.tag, t.typ, uint32(store.Len()), 1})
+		store.Write(t.data)
+	}
+
+	var blob bytes.Buffer
+	binary.Write(&blob, binary.BigEndian, [2]uint32{uint32(len(tags)), uint32(store.Len())})
+	blob.Write(index.Bytes())
+	blob.Write(store.Bytes())
+	return blob.Bytes()
+}
+
+// bdbHashFile builds a little endian hash database with one hash page that
+// holds the values. Values larger than 100 bytes are stored in a chain of
+// overflow pages.
+func bdbHashFile(values ...[]byte) []byte {
+	const pageSize = 512
+	pages := [][]byte{make([]byte, pageSize), make([]byte, pageSize)}
+	le := binary.LittleEndian
+
+	var items [][]byte
+	for i, v := range values {
+		key := make([]byte, 5)
+		key[0] = bdbHashKeyData
+		le.PutUint32(key[1:], uint32(i+1))
+		items = append(items, key)
+
+		if len(v) <= 100 {
+			items = append(items, append([]byte{bdbHashKeyData}, v...))
+			continue
+		}
+
+		item := make([]byte, 12)
+		item[0] = bdbHashOffPage
+		le.PutUint32(item[4:], uint32(len(pages)))
+		le.PutUint32(item[8:], uint32(len(v)))
+		items = append(items, item)
+
+		for rest := v; len(rest) > 0; {
+			n := len(rest)
+			if n > pageSize-bdbPageHeaderSize {
+				n = pageSize - bdbPageHeaderSize
+			}
+			page := make([]byte, pageSize)
+			page[25] = bdbPageTypeOverflow
+			le.PutUint16(page[22:], uint16(n))
+			copy(page[bdbPageHeaderSize:], rest[:n])
+			rest = rest[n:]
+			if len(rest) > 0 {
+				le.PutUint32(page[16:], uint32(len(pages)+1))
+			}
+			pages = append(pages, page)
+		}
+	}
+
+	hash := pages[1]
+	hash[25] = bdbPageTypeHash
+	le.PutUint16(hash[20:], uint16(len(items)))
+	pos := pageSize
+	for i, item := range items {
+		pos -= len(item)
+		copy(hash[pos:], item)
+		le.PutUint16(hash[bdbPageHeaderSize+2*i:], uint16(pos))
+	}
+
+	meta := pages[0]
+	le.PutUint32(meta[12:], bdbHashMagic)
+	le.PutUint32(meta[20:], pageSize)
+	le.PutUint32(meta[32:], uint32(len(pages)-1))
+	meta[25] = 8
+
+	return bytes.Join(pages, nil)
+}
+
+func TestReadRPMDatabase(t *testing.T) {
+	bash := rpmHeaderBlob(
+		rpmString(rpmTagName, "bash"),
+		rpmString(rpmTagVersion, "4.2.46"),
+		rpmString(rpmTagRelease, "34.el7"),
+		rpmTag{rpmTagSummary, rpmTypeI18NString, []byte("The GNU Bourne Again shell (bash) version 4.2 with a long summary to use overflow pages\x00")},
+		rpmString(rpmTagVendor, "CentOS"),
+		rpmString(rpmTagArch, "x86_64"),
+		rpmInt32(rpmTagInstallTime, 1587000000),
+		rpmInt32(rpmTagSize, 3667773),
+		rpmString(1005, string(bytes.Repeat([]byte("x"), 600))), // Padding that spans two overflow pages.
+	)
+	openssl := rpmHeaderBlob(
+		rpmString(rpmTagName, "openssl"),
+		rpmInt32(rpmTagEpoch, 1),
+		rpmString(rpmTagVersion, "1.0.2k"),
+	)
+	counter := []byte{2, 0, 0, 0}
+
+	pkgs, err := readRPMDatabase(bytes.NewReader(bdbHashFile(counter, bash, openssl)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.PackageInfo{
+		{
+			Name:        "bash",
+			Version:     "4.2.46",
+			Release:     "34.el7",
+			Arch:        "x86_64",
+			Summary:     "The GNU Bourne Again shell (bash) version 4.2 with a long summary to use overflow pages",
+			Vendor:      "CentOS",
+			Size:        3667773,
+			InstallTime: time.Unix(1587000000, 0).UTC(),
+			Type:        types.PackageTypeRPM,
+		},
+		{Name: "openssl", Version: "1:1.0.2k", Type: types.PackageTypeRPM},
+	}, pkgs)
+
+	_, err = readRPMDatabase(bytes.NewReader(make([]byte, 1024)))
+	assert.Error(t, err)
+}
+
+func TestReadRPMSQLite(t *testing.T) {
+	pkg := func(i int) types.PackageInfo {
+		p := types.PackageInfo{
+			Name:        fmt.Sprintf("pkg%02d", i),
+			Version:     fmt.Sprintf("1.%d", i),
+			Release:     "1.el9",
+			Arch:        "x86_64",
+			Summary:     fmt.Sprintf("Package %d", i),
+			Size:        uint64(1000 * i),
+			InstallTime: time.Unix(int64(1650000000+i), 0).UTC(),
+			Type:        types.PackageTypeRPM,
+		}
+		if i == 3 {
+			// The header of pkg03 spills onto overflow pages.
+			p.Summary = strings.Repeat("A summary that is long enough to spill the row onto overflow pages ", 12)
+		}
+		return p
+	}
+
+	// The fixture uses 512 byte pages so that the table has interior pages.
+	// pkg05 was deleted.
+	pkgs, err := installedPackages(dirFS("testdata/rpmsqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []types.PackageInfo
+	for i := 1; i <= 12; i++ {
+		if i != 5 {
+			want = append(want, pkg(i))
+		}
+	}
+	assert.Equal(t, want, pkgs)
+
+	// pkg02 and pkg03 were committed to the write-ahead log only, pkg04 was
+	// written to it by a transaction that did not commit.
+	pkgs, err = installedPackages(dirFS("testdata/rpmsqlite-wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.PackageInfo{pkg(1), pkg(2), pkg(3)}, pkgs)
+
+	db, err := ioutil.ReadFile("testdata/rpmsqlite/var/lib/rpm/rpmdb.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The sqlite database is read even if the host has other package managers.
+	pkgs, err = installedPackages(mapFS{
+		dpkgStatusFile:                      dpkgStatus,
+		"usr/lib/sysimage/rpm/rpmdb.sqlite": string(db),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, pkgs, 2+len(want))
+
+	_, err = installedPackages(mapFS{
+		"var/lib/rpm/rpmdb.sqlite": string(db[:len(db)/2]),
+	})
+	assert.Error(t, err)
+
+	_, err = readRPMSQLite(bytes.NewReader(make([]byte, 1024)), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"sort"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// OSPatches lists the kernels installed in /lib/modules, newest first.
+func (h *host) OSPatches() ([]types.OSPatchInfo, error) {
+	return installedKernels(h.fs, h.info.KernelVersion)
+}
+
+func installedKernels(fs fileSystem, running string) ([]types.OSPatchInfo, error) {
+	entries, err := fs.ReadDir("lib/modules")
+	if err != nil {
+		return nil, err
+	}
+
+	var kernels []types.OSPatchInfo
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		kernels = append(kernels, types.OSPatchInfo{
+			ID:          e.Name(),
+			Description: "kernel",
+			InstallTime: e.ModTime(),
+			Current:     e.Name() == running,
+		})
+	}
+
+	sort.Slice(kernels, func(i, j int) bool { return compareVersions(kernels[i].ID, kernels[j].ID) > 0 })
+	return kernels, nil
+}
@@ -0,0 +1,115 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path/filepath"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// powerSupplyTypes maps the type attribute of /sys/class/power_supply
+// devices to the power source type. USB and wireless chargers are reported
+// as mains.
+var powerSupplyTypes = map[string]string{
+	"Battery":  types.PowerSourceBattery,
+	"Mains":    types.PowerSourceMains,
+	"USB":      types.PowerSourceMains,
+	"Wireless": types.PowerSourceMains,
+	"UPS":      types.PowerSourceUPS,
+}
+
+var batteryStates = map[string]string{
+	"Charging":     types.BatteryCharging,
+	"Discharging":  types.BatteryDischarging,
+	"Full":         types.BatteryFull,
+	"Not charging": types.BatteryNotCharging,
+}
+
+// PowerSources reads the batteries and AC adapters in /sys/class/power_supply.
+func (h *host) PowerSources() ([]types.PowerSourceInfo, error) {
+	return readPowerSources(filepath.Dir(string(h.procFS)))
+}
+
+func readPowerSources(root string) ([]types.PowerSourceInfo, error) {
+	devices, err := filepath.Glob(filepath.Join(root, "sys/class/power_supply/*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []types.PowerSourceInfo
+	for _, dir := range devices {
+		typ, found := powerSupplyTypes[readSysfsString(filepath.Join(dir, "type"))]
+		if !found {
+			continue
+		}
+
+		// Peripherals (e.g. wireless mice) report their batteries with
+		// scope Device.
+		if readSysfsString(filepath.Join(dir, "scope")) == "Device" {
+			continue
+		}
+
+		info := types.PowerSourceInfo{
+			Name: filepath.Base(dir),
+			Type: typ,
+		}
+
+		if typ != types.PowerSourceBattery {
+			if online, err := readSysfsInt(filepath.Join(dir, "online")); err == nil {
+				v := online != 0
+				info.Online = &v
+			}
+			sources = append(sources, info)
+			continue
+		}
+
+		if present, err := readSysfsInt(filepath.Join(dir, "present")); err == nil && present == 0 {
+			continue
+		}
+		info.State = batteryStates[readSysfsString(filepath.Join(dir, "status"))]
+		info.Health = readSysfsString(filepath.Join(dir, "health"))
+		if cycles, err := readSysfsInt(filepath.Join(dir, "cycle_count")); err == nil {
+			info.Cycles = int(cycles)
+		}
+		info.Percent = batteryPercent(dir)
+		sources = append(sources, info)
+	}
+	return sources, nil
+}
+
+// batteryPercent returns the capacity attribute or calculates it from the
+// energy (µWh) or charge (µAh) attributes.
+func batteryPercent(dir string) float64 {
+	if capacity, err := readSysfsInt(filepath.Join(dir, "capacity")); err == nil {
+		return float64(capacity)
+	}
+
+	for _, prefix := range []string{"energy", "charge"} {
+		now, err := readSysfsInt(filepath.Join(dir, prefix+"_now"))
+		if err != nil {
+			continue
+		}
+		full, err := readSysfsInt(filepath.Join(dir, prefix+"_full"))
+		if err != nil || full <= 0 {
+			continue
+		}
+		return 100 * float64(now) / float64(full)
+	}
+	return 0
+}
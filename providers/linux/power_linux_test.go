@@ -0,0 +1,42 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.PowerSources = (*host)(nil)
+
+func TestReadPowerSources(t *testing.T) {
+	sources, err := readPowerSources("testdata/ubuntu1710")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	online := true
+	assert.Equal(t, []types.PowerSourceInfo{
+		{Name: "AC", Type: types.PowerSourceMains, Online: &online},
+		{Name: "BAT0", Type: types.PowerSourceBattery, Percent: 87, State: types.BatteryDischarging, Health: "Good", Cycles: 212},
+		{Name: "BAT1", Type: types.PowerSourceBattery, Percent: 75, State: types.BatteryNotCharging},
+	}, sources)
+}
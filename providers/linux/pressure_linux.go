@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// PressureStats returns the Pressure Stall Information from
+// /proc/pressure/{cpu,memory,io}. An error is returned if the kernel does not
+// support PSI or it is disabled (psi=0).
+func (h *host) PressureStats() (*types.PressureInfo, error) {
+	var info types.PressureInfo
+	for _, res := range []struct {
+		name string
+		dst  **types.ResourcePressure
+	}{
+		{"cpu", &info.CPU},
+		{"memory", &info.Memory},
+		{"io", &info.IO},
+	} {
+		content, err := h.fs.ReadFile("proc/pressure/" + res.name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		p, err := parsePressure(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse pressure/%v", res.name)
+		}
+		*res.dst = p
+	}
+
+	if info.CPU == nil && info.Memory == nil && info.IO == nil {
+		return nil, &types.NotSupportedError{Feature: "pressure stall information", Reason: "/proc/pressure does not exist"}
+	}
+	return &info, nil
+}
+
+// parsePressure parses a /proc/pressure file. Each line has the form
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0" where total is in
+// microseconds.
+func parsePressure(content []byte) (*types.ResourcePressure, error) {
+	var p types.ResourcePressure
+	var found bool
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stall types.PressureStall
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("invalid pressure field '%v'", f)
+			}
+
+			var err error
+			switch kv[0] {
+			case "avg10":
+				stall.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				stall.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				stall.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				var us uint64
+				us, err = strconv.ParseUint(kv[1], 10, 64)
+				stall.Total = time.Duration(us) * time.Microsecond
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid pressure field '%v'", f)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			p.Some = stall
+			found = true
+		case "full":
+			s := stall
+			p.Full = &s
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("missing 'some' line")
+	}
+	return &p, nil
+}
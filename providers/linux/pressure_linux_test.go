@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.PressureStats = (*host)(nil)
+
+func TestHostPressureStats(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := host.(types.PressureStats).PressureStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.PressureInfo{
+		CPU: &types.ResourcePressure{
+			Some: types.PressureStall{Avg10: 1.5, Avg60: 0.75, Avg300: 0.2, Total: 1234567 * time.Microsecond},
+		},
+		Memory: &types.ResourcePressure{
+			Some: types.PressureStall{Avg60: 0.1, Avg300: 0.05, Total: 5 * time.Millisecond},
+			Full: &types.PressureStall{Avg60: 0.02, Avg300: 0.01, Total: 2 * time.Millisecond},
+		},
+	}, info)
+
+	_, err = parsePressure([]byte("some avg10=x\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/connector.h and linux/cn_proc.h.
+const (
+	netlinkConnector = 11 // NETLINK_CONNECTOR
+
+	cnIdxProc = 1
+	cnValProc = 1
+
+	procCnMcastListen = 1
+	procCnMcastIgnore = 2
+
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	cnMsgLen          = 20 // sizeof(struct cn_msg)
+	procEventHdrLen   = 16 // Size of the proc_event fields before event_data.
+	procEventMinLen   = cnMsgLen + procEventHdrLen + 16
+	procConnectorRecv = 1 * time.Second // Receive timeout used to check for cancellation.
+)
+
+// nativeEndian is the byte order of netlink messages.
+var nativeEndian binary.ByteOrder = binary.LittleEndian
+
+func init() {
+	var x uint16 = 1
+	if *(*byte)(unsafe.Pointer(&x)) == 0 {
+		nativeEndian = binary.BigEndian
+	}
+}
+
+// WatchProcesses streams process events from the kernel's proc connector.
+// Subscribing requires CAP_NET_ADMIN and a kernel with CONFIG_PROC_EVENTS. If
+// that fails the process list is polled instead.
+func (s linuxSystem) WatchProcesses(ctx context.Context) (<-chan types.ProcessEvent, error) {
+	events, err := watchProcConnector(ctx)
+	if err == nil {
+		return events, nil
+	}
+	return shared.PollProcesses(ctx, s.Processes, shared.ProcessPollInterval)
+}
+
+func watchProcConnector(ctx context.Context) (<-chan types.ProcessEvent, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, netlinkConnector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create netlink socket")
+	}
+
+	if err = syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to bind netlink socket")
+	}
+
+	tv := syscall.NsecToTimeval(procConnectorRecv.Nanoseconds())
+	if err = syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to set netlink receive timeout")
+	}
+
+	if err = sendProcConnectorOp(fd, procCnMcastListen); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to subscribe to process events")
+	}
+
+	events := make(chan types.ProcessEvent)
+	go func() {
+		defer close(events)
+		defer syscall.Close(fd)
+		defer sendProcConnectorOp(fd, procCnMcastIgnore)
+
+		buf := make([]byte, os.Getpagesize())
+		for ctx.Err() == nil {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				switch err {
+				case syscall.EAGAIN, syscall.EINTR, syscall.ENOBUFS:
+					// ENOBUFS means that events were dropped because the
+					// socket buffer was full. Keep reading.
+					continue
+				}
+				return
+			}
+
+			msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+			if err != nil {
+				continue
+			}
+			for _, m := range msgs {
+				event, ok := parseProcEvent(m.Data)
+				if !ok {
+					continue
+				}
+				event.Time = time.Now()
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// sendProcConnectorOp sends a PROC_CN_MCAST_LISTEN or PROC_CN_MCAST_IGNORE
+// message to the kernel.
+func sendProcConnectorOp(fd int, op uint32) error {
+	msg := make([]byte, syscall.NLMSG_HDRLEN+cnMsgLen+4)
+
+	// struct nlmsghdr
+	nativeEndian.PutUint32(msg[0:], uint32(len(msg)))
+	nativeEndian.PutUint16(msg[4:], syscall.NLMSG_DONE)
+
+	// struct cn_msg
+	cn := msg[syscall.NLMSG_HDRLEN:]
+	nativeEndian.PutUint32(cn[0:], cnIdxProc)
+	nativeEndian.PutUint32(cn[4:], cnValProc)
+	nativeEndian.PutUint16(cn[16:], 4)
+
+	// enum proc_cn_mcast_op
+	nativeEndian.PutUint32(cn[cnMsgLen:], op)
+
+	return syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
+}
+
+// parseProcEvent parses the cn_msg and proc_event structs contained in a
+// netlink message. Events for threads are ignored.
+func parseProcEvent(data []byte) (types.ProcessEvent, bool) {
+	if len(data) < procEventMinLen {
+		return types.ProcessEvent{}, false
+	}
+
+	what := nativeEndian.Uint32(data[cnMsgLen:])
+	ev := data[cnMsgLen+procEventHdrLen:]
+
+	switch what {
+	case procEventFork:
+		// parent_pid, parent_tgid, child_pid, child_tgid
+		childPID, childTGID := nativeEndian.Uint32(ev[8:]), nativeEndian.Uint32(ev[12:])
+		if childPID != childTGID {
+			return types.ProcessEvent{}, false
+		}
+		return types.ProcessEvent{
+			Type: types.ProcessEventStart,
+			PID:  int(childTGID),
+			PPID: int(nativeEndian.Uint32(ev[4:])),
+		}, true
+	case procEventExec, procEventExit:
+		// process_pid, process_tgid
+		pid, tgid := nativeEndian.Uint32(ev[0:]), nativeEndian.Uint32(ev[4:])
+		if what == procEventExit && pid != tgid {
+			return types.ProcessEvent{}, false
+		}
+		eventType := types.ProcessEventExec
+		if what == procEventExit {
+			eventType = types.ProcessEventExit
+		}
+		return types.ProcessEvent{Type: eventType, PID: int(tgid)}, true
+	}
+	return types.ProcessEvent{}, false
+}
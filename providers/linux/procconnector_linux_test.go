@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ registry.ProcessWatcher = linuxSystem{}
+
+func procEventMessage(what uint32, fields ...uint32) []byte {
+	data := make([]byte, procEventMinLen)
+	nativeEndian.PutUint32(data[cnMsgLen:], what)
+	for i, v := range fields {
+		nativeEndian.PutUint32(data[cnMsgLen+procEventHdrLen+4*i:], v)
+	}
+	return data
+}
+
+func TestParseProcEvent(t *testing.T) {
+	tests := []struct {
+		data  []byte
+		event types.ProcessEvent
+		ok    bool
+	}{
+		{procEventMessage(procEventFork, 100, 100, 200, 200), types.ProcessEvent{Type: types.ProcessEventStart, PID: 200, PPID: 100}, true},
+		{procEventMessage(procEventFork, 100, 100, 201, 200), types.ProcessEvent{}, false}, // New thread.
+		{procEventMessage(procEventExec, 200, 200), types.ProcessEvent{Type: types.ProcessEventExec, PID: 200}, true},
+		{procEventMessage(procEventExit, 200, 200, 0, 17), types.ProcessEvent{Type: types.ProcessEventExit, PID: 200}, true},
+		{procEventMessage(procEventExit, 201, 200, 0, 17), types.ProcessEvent{}, false}, // Thread exit.
+		{procEventMessage(0), types.ProcessEvent{}, false},                              // Subscription ack.
+		{make([]byte, 8), types.ProcessEvent{}, false},
+	}
+
+	for i, tc := range tests {
+		event, ok := parseProcEvent(tc.data)
+		assert.Equal(t, tc.ok, ok, "test %d", i)
+		assert.Equal(t, tc.event, event, "test %d", i)
+	}
+}
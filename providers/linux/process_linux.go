@@ -19,20 +19,36 @@ package linux
 
 import (
 	"bytes"
-	"io/ioutil"
+	"context"
+	"io"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/prometheus/procfs"
 
+	"github.com/elastic/go-sysinfo/providers/shared"
 	"github.com/elastic/go-sysinfo/types"
 )
 
 const userHz = 100
 
 func (s linuxSystem) Processes() ([]types.Process, error) {
+	if pids, ok := s.taskPIDs(); ok {
+		processes := make([]types.Process, 0, len(pids))
+		for _, pid := range pids {
+			proc, err := s.procFS.NewProc(pid)
+			if err != nil {
+				// The process exited.
+				continue
+			}
+			processes = append(processes, &process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter})
+		}
+		return processes, nil
+	}
+
 	procs, err := s.procFS.AllProcs()
 	if err != nil {
 		return nil, err
@@ -40,18 +56,121 @@ func (s linuxSystem) Processes() ([]types.Process, error) {
 
 	processes := make([]types.Process, 0, len(procs))
 	for _, proc := range procs {
-		processes = append(processes, &process{Proc: proc, fs: s.procFS})
+		processes = append(processes, &process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter})
 	}
 	return processes, nil
 }
 
+// ProcessesContext is like Processes but it checks the context between the
+// batches of PIDs read from procfs and returns ctx.Err() when it is done.
+func (s linuxSystem) ProcessesContext(ctx context.Context) ([]types.Process, error) {
+	var processes []types.Process
+	err := s.forEachProcess(ctx, func(p types.Process) error {
+		processes = append(processes, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return processes, nil
+}
+
+// ForEachProcess reads the PIDs from procfs in batches (or from the eBPF
+// task iterator if it was selected) and passes the processes to fn one at a
+// time. Iteration stops at the first error returned by fn.
+func (s linuxSystem) ForEachProcess(fn func(types.Process) error) error {
+	return s.forEachProcess(context.Background(), fn)
+}
+
+func (s linuxSystem) forEachProcess(ctx context.Context, fn func(types.Process) error) error {
+	if pids, ok := s.taskPIDs(); ok {
+		for i, pid := range pids {
+			if i%512 == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+
+			proc, err := s.procFS.NewProc(pid)
+			if err != nil {
+				// The process exited.
+				continue
+			}
+			if err = fn(&process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	dir, err := os.Open(string(s.procFS))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		names, err := dir.Readdirnames(512)
+		for _, name := range names {
+			pid, err := strconv.Atoi(name)
+			if err != nil {
+				continue
+			}
+
+			proc, err := s.procFS.NewProc(pid)
+			if err != nil {
+				// The process exited.
+				continue
+			}
+			if err = fn(&process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// taskPIDs returns the PIDs from the eBPF task iterator. It returns false if
+// the eBPF backend was not selected or cannot be used, in which case the
+// PIDs are read from procfs.
+func (s linuxSystem) taskPIDs() ([]int, bool) {
+	if s.processBackend != types.ProcessBackendEBPF || !inInitPIDNamespace() {
+		return nil, false
+	}
+	pids, err := bpfTaskPIDs()
+	if err != nil {
+		return nil, false
+	}
+	return pids, true
+}
+
 func (s linuxSystem) Process(pid int) (types.Process, error) {
 	proc, err := s.procFS.NewProc(pid)
 	if err != nil {
-		return nil, err
+		return nil, processError(pid, err)
+	}
+
+	return &process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter}, nil
+}
+
+// ProcessWithFields returns the process and skips reading the parts of
+// /proc/[pid] that were not selected when Info is called.
+func (s linuxSystem) ProcessWithFields(pid int, fields types.ProcessField) (types.Process, error) {
+	proc, err := s.procFS.NewProc(pid)
+	if err != nil {
+		return nil, processError(pid, err)
 	}
 
-	return &process{Proc: proc, fs: s.procFS}, nil
+	return &process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: fields, envFilter: s.envFilter}, nil
 }
 
 func (s linuxSystem) Self() (types.Process, error) {
@@ -60,26 +179,45 @@ func (s linuxSystem) Self() (types.Process, error) {
 		return nil, err
 	}
 
-	return &process{Proc: proc, fs: s.procFS}, nil
+	return &process{Proc: proc, procFS: s.procFS, fs: s.fs, fields: s.fields, envFilter: s.envFilter}, nil
+}
+
+// processError returns a *types.ProcessNotFoundError if the /proc/[pid]
+// directory does not exist.
+func processError(pid int, err error) error {
+	if os.IsNotExist(err) {
+		return &types.ProcessNotFoundError{PID: pid}
+	}
+	return err
 }
 
 type process struct {
 	procfs.Proc
-	fs   procfs.FS
-	info *types.ProcessInfo
+	procFS    procfs.FS
+	fs        fileSystem         // Host filesystem that procFS belongs to.
+	fields    types.ProcessField // Zero selects all fields.
+	envFilter types.EnvFilter
+	info      *types.ProcessInfo
 }
 
 func (p *process) PID() int {
 	return p.Proc.PID
 }
 
+// path returns the local path of a file in /proc/[pid]. It is used where
+// the file must be opened by the OS (e.g. to hash it).
 func (p *process) path(pa ...string) string {
-	return p.fs.Path(append([]string{strconv.Itoa(p.PID())}, pa...)...)
+	return p.procFS.Path(append([]string{strconv.Itoa(p.PID())}, pa...)...)
+}
+
+// name returns the name of a file in /proc/[pid] relative to p.fs.
+func (p *process) name(pa ...string) string {
+	return path.Join(append([]string{"proc", strconv.Itoa(p.PID())}, pa...)...)
 }
 
 func (p *process) CWD() (string, error) {
 	// TODO: add CWD to procfs
-	cwd, err := os.Readlink(p.path("cwd"))
+	cwd, err := p.fs.Readlink(p.name("cwd"))
 	if os.IsNotExist(err) {
 		return "", nil
 	}
@@ -87,6 +225,10 @@ func (p *process) CWD() (string, error) {
 	return cwd, err
 }
 
+// Info returns the process information. If the executable, arguments, or
+// working directory cannot be read (e.g. because the process belongs to
+// another user) then the remaining information is returned together with a
+// *types.PartialInfoError.
 func (p *process) Info() (types.ProcessInfo, error) {
 	if p.info != nil {
 		return *p.info, nil
@@ -97,27 +239,33 @@ func (p *process) Info() (types.ProcessInfo, error) {
 		return types.ProcessInfo{}, err
 	}
 
-	exe, err := p.Executable()
-	if err != nil {
-		return types.ProcessInfo{}, err
+	var partial types.PartialInfoError
+	var exe, cwd string
+	var args []string
+	if p.fields.Includes(types.FieldExe) {
+		if exe, err = p.Executable(); err != nil {
+			partial.Add("exe", err)
+		}
 	}
 
-	args, err := p.CmdLine()
-	if err != nil {
-		return types.ProcessInfo{}, err
+	if p.fields.Includes(types.FieldArgs) {
+		if args, err = p.CmdLine(); err != nil {
+			partial.Add("args", err)
+		}
 	}
 
-	cwd, err := p.CWD()
-	if err != nil {
-		return types.ProcessInfo{}, err
+	if p.fields.Includes(types.FieldCWD) {
+		if cwd, err = p.CWD(); err != nil {
+			partial.Add("cwd", err)
+		}
 	}
 
-	bootTime, err := bootTime(p.fs)
+	bootTime, err := bootTime(p.procFS)
 	if err != nil {
 		return types.ProcessInfo{}, err
 	}
 
-	p.info = &types.ProcessInfo{
+	info := types.ProcessInfo{
 		Name:      stat.Comm,
 		PID:       p.PID(),
 		PPID:      stat.PPID,
@@ -125,9 +273,37 @@ func (p *process) Info() (types.ProcessInfo, error) {
 		Exe:       exe,
 		Args:      args,
 		StartTime: bootTime.Add(ticksToDuration(stat.Starttime)),
+		State:     procState(stat.State),
+	}
+	if err = partial.ErrOrNil(); err != nil {
+		// Don't cache partial results so that a retry with more privileges
+		// can succeed.
+		return info, err
+	}
+
+	p.info = &info
+	return info, nil
+}
+
+// Identity returns the PID, start time, and boot ID of the process. Unlike
+// Info it only reads /proc/[pid]/stat.
+func (p *process) Identity() (types.ProcessIdentity, error) {
+	id := types.ProcessIdentity{PID: p.PID(), BootID: readBootID(p.procFS)}
+	if p.info != nil {
+		id.StartTime = p.info.StartTime
+		return id, nil
 	}
 
-	return *p.info, nil
+	stat, err := p.NewStat()
+	if err != nil {
+		return types.ProcessIdentity{}, err
+	}
+	bootTime, err := bootTime(p.procFS)
+	if err != nil {
+		return types.ProcessIdentity{}, err
+	}
+	id.StartTime = bootTime.Add(ticksToDuration(stat.Starttime))
+	return id, nil
 }
 
 func (p *process) Memory() (types.MemoryInfo, error) {
@@ -136,10 +312,30 @@ func (p *process) Memory() (types.MemoryInfo, error) {
 		return types.MemoryInfo{}, err
 	}
 
-	return types.MemoryInfo{
+	info := types.MemoryInfo{
 		Resident: uint64(stat.ResidentMemory()),
 		Virtual:  uint64(stat.VirtualMemory()),
-	}, nil
+	}
+
+	// Kernel threads have no VmSwap.
+	if content, err := p.fs.ReadFile(p.name("status")); err == nil {
+		info.Swap, _ = readVmSwap(content)
+	}
+	return info, nil
+}
+
+// readVmSwap returns the VmSwap value from /proc/[pid]/status in bytes.
+func readVmSwap(content []byte) (uint64, error) {
+	var swap uint64
+	err := parseKeyValue(content, ":", func(key, value []byte) error {
+		if string(key) != "VmSwap" {
+			return nil
+		}
+		var err error
+		swap, err = parseBytesOrNumber(value)
+		return err
+	})
+	return swap, err
 }
 
 func (p *process) CPUTime() (types.CPUTimes, error) {
@@ -154,9 +350,195 @@ func (p *process) CPUTime() (types.CPUTimes, error) {
 	}, nil
 }
 
+// IOCounters returns the I/O statistics from /proc/[pid]/io.
+func (p *process) IOCounters() (*types.IOCountersInfo, error) {
+	io, err := p.NewIO()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.IOCountersInfo{
+		ReadBytes:  io.ReadBytes,
+		WriteBytes: io.WriteBytes,
+		ReadCount:  io.SyscR,
+		WriteCount: io.SyscW,
+		Metrics: map[string]uint64{
+			"rchar":                 io.RChar,
+			"wchar":                 io.WChar,
+			"cancelled_write_bytes": uint64(io.CancelledWriteBytes),
+		},
+	}, nil
+}
+
+// ResourceUsage returns the page fault counts from /proc/[pid]/stat and the
+// context switch counts from /proc/[pid]/status.
+func (p *process) ResourceUsage() (*types.ResourceUsageInfo, error) {
+	stat, err := p.NewStat()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := p.fs.ReadFile(p.name("status"))
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := readContextSwitches(content)
+	if err != nil {
+		return nil, err
+	}
+	usage.MinorPageFaults = uint64(stat.MinFlt)
+	usage.MajorPageFaults = uint64(stat.MajFlt)
+	return usage, nil
+}
+
 // OpenHandles returns the list of open file descriptors of the process.
-func (p *process) OpenHandles() ([]string, error) {
-	return p.Proc.FileDescriptorTargets()
+func (p *process) OpenHandles() ([]types.OpenHandleInfo, error) {
+	fds, err := p.Proc.FileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	handles := make([]types.OpenHandleInfo, 0, len(fds))
+	for _, fd := range fds {
+		link := p.name("fd", strconv.FormatUint(uint64(fd), 10))
+
+		target, err := p.fs.Readlink(link)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The descriptor was closed after it was listed.
+				continue
+			}
+			return nil, err
+		}
+
+		// Stat follows the link so the mode of the file itself is used. A
+		// failure only means the type cannot be refined.
+		var mode os.FileMode
+		if info, err := p.fs.Stat(link); err == nil {
+			mode = info.Mode()
+		}
+
+		handles = append(handles, types.OpenHandleInfo{
+			FD:   uint64(fd),
+			Type: handleType(target, mode),
+			Path: target,
+		})
+	}
+
+	return handles, nil
+}
+
+// Connections returns the TCP and UDP sockets owned by the process. They
+// are read from /proc/[pid]/net so that the sockets of processes in another
+// network namespace (e.g. a container) are found.
+func (p *process) Connections() ([]types.NetworkConnection, error) {
+	targets, err := p.Proc.FileDescriptorTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	inodes := socketInodes(targets)
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	all, err := readSocketTables(p.fs, p.name("net"))
+	if err != nil {
+		return nil, err
+	}
+	netNS, _ := netNamespaceInode(p.fs, p.name("ns", "net"))
+
+	var conns []types.NetworkConnection
+	for _, conn := range all {
+		if _, found := inodes[conn.Inode]; found {
+			conn.PID = p.PID()
+			conn.NetNS = netNS
+			conns = append(conns, conn)
+		}
+	}
+	return conns, nil
+}
+
+// Children returns the direct children of the process. It reads
+// /proc/[pid]/task/[tid]/children when the kernel provides it
+// (CONFIG_PROC_CHILDREN) and otherwise scans all processes.
+func (p *process) Children() ([]types.Process, error) {
+	pids, err := p.childPIDs()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		procs, err := p.procFS.AllProcs()
+		if err != nil {
+			return nil, err
+		}
+		all := make([]types.Process, 0, len(procs))
+		for _, proc := range procs {
+			all = append(all, &process{Proc: proc, procFS: p.procFS, fs: p.fs, fields: p.fields, envFilter: p.envFilter})
+		}
+		return shared.Children(all, p)
+	}
+
+	children := make([]types.Process, 0, len(pids))
+	for _, pid := range pids {
+		proc, err := p.procFS.NewProc(pid)
+		if err != nil {
+			// The child exited.
+			continue
+		}
+		children = append(children, &process{Proc: proc, procFS: p.procFS, fs: p.fs, fields: p.fields, envFilter: p.envFilter})
+	}
+	return children, nil
+}
+
+// Ancestors returns the parents of the process up to init. The process table
+// is read once and the chain is resolved from that snapshot.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := p.procFS.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+	all := make([]types.Process, 0, len(procs))
+	for _, proc := range procs {
+		all = append(all, &process{Proc: proc, procFS: p.procFS, fs: p.fs, fields: p.fields, envFilter: p.envFilter})
+	}
+	return shared.Ancestors(all, p)
+}
+
+func (p *process) childPIDs() ([]int, error) {
+	tasks, err := p.fs.ReadDir(p.name("task"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, task := range tasks {
+		content, err := p.fs.ReadFile(p.name("task", task.Name(), "children"))
+		if err != nil {
+			if os.IsNotExist(err) && len(pids) > 0 {
+				// The thread exited.
+				continue
+			}
+			return nil, err
+		}
+
+		for _, field := range strings.Fields(string(content)) {
+			pid, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, err
+			}
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// Hashes hashes the executable through /proc/[pid]/exe so that the binary
+// that is running is hashed even if it was deleted or replaced on disk.
+func (p *process) Hashes(algorithms ...types.HashType) (map[types.HashType]string, error) {
+	return shared.HashFile(p.path("exe"), algorithms...)
 }
 
 // OpenHandles returns the number of open file descriptors of the process.
@@ -166,7 +548,7 @@ func (p *process) OpenHandleCount() (int, error) {
 
 func (p *process) Environment() (map[string]string, error) {
 	// TODO: add Environment to procfs
-	content, err := ioutil.ReadFile(p.path("environ"))
+	content, err := p.fs.ReadFile(p.name("environ"))
 	if err != nil {
 		return nil, err
 	}
@@ -187,11 +569,11 @@ func (p *process) Environment() (map[string]string, error) {
 		env[key] = string(parts[1])
 	}
 
-	return env, nil
+	return shared.FilterEnv(p.envFilter, env), nil
 }
 
 func (p *process) Seccomp() (*types.SeccompInfo, error) {
-	content, err := ioutil.ReadFile(p.path("status"))
+	content, err := p.fs.ReadFile(p.name("status"))
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +582,7 @@ func (p *process) Seccomp() (*types.SeccompInfo, error) {
 }
 
 func (p *process) Capabilities() (*types.CapabilityInfo, error) {
-	content, err := ioutil.ReadFile(p.path("status"))
+	content, err := p.fs.ReadFile(p.name("status"))
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +591,7 @@ func (p *process) Capabilities() (*types.CapabilityInfo, error) {
 }
 
 func (p *process) User() (types.UserInfo, error) {
-	content, err := ioutil.ReadFile(p.path("status"))
+	content, err := p.fs.ReadFile(p.name("status"))
 	if err != nil {
 		return types.UserInfo{}, err
 	}
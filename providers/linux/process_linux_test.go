@@ -18,8 +18,87 @@
 package linux
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+
 	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
 )
 
 var _ registry.HostProvider = linuxSystem{}
 var _ registry.ProcessProvider = linuxSystem{}
+var _ registry.NetworkProvider = linuxSystem{}
+var _ registry.ProcessIterator = linuxSystem{}
+var _ registry.ProcessContextProvider = linuxSystem{}
+var _ registry.ProcessFieldsProvider = linuxSystem{}
+var _ registry.ProcessFieldsSelector = linuxSystem{}
+var _ registry.CacheTTLProvider = linuxSystem{}
+var _ registry.EnvFilterProvider = linuxSystem{}
+var _ registry.ProcessBackendProvider = linuxSystem{}
+
+var _ types.ProcessIdentifier = (*process)(nil)
+
+func TestReadBootID(t *testing.T) {
+	fs := newLinuxSystem("testdata/ubuntu1710").procFS
+	assert.Equal(t, "4f2c8d1e-6b1a-4e3c-9d57-2a0c3b8e7f16", readBootID(fs))
+
+	// The boot ID is only read once per procfs.
+	dir, err := ioutil.TempDir("", "bootid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "sys/kernel/random/boot_id")
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, readBootID(procfs.FS(dir)))
+	if err = ioutil.WriteFile(file, []byte("first\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "first", readBootID(procfs.FS(dir)))
+	if err = ioutil.WriteFile(file, []byte("second\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "first", readBootID(procfs.FS(dir)))
+}
+
+func TestReadVmSwap(t *testing.T) {
+	status := "Name:\tjava\nVmRSS:\t  102400 kB\nVmSwap:\t    2048 kB\nThreads:\t42\n"
+	swap, err := readVmSwap([]byte(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 2048*1024, swap)
+
+	swap, err = readVmSwap([]byte("Name:\tkthreadd\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Zero(t, swap)
+}
+
+func TestProcessNotFound(t *testing.T) {
+	_, err := newLinuxSystem("testdata/ubuntu1710").Process(99999)
+	assert.True(t, types.IsProcessNotFound(err), "expected process not found error, got %v", err)
+	assert.EqualError(t, err, "process 99999 not found")
+}
+
+func TestProcessesContext(t *testing.T) {
+	s := newLinuxSystem("testdata/ubuntu1710")
+	procs, err := s.ProcessesContext(context.Background())
+	if assert.NoError(t, err) {
+		assert.NotEmpty(t, procs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.ProcessesContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,286 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/taskstats.h and linux/acct.h.
+const (
+	taskstatsCmdAttrRegisterCPUMask   = 3 // TASKSTATS_CMD_ATTR_REGISTER_CPUMASK
+	taskstatsCmdAttrDeregisterCPUMask = 4 // TASKSTATS_CMD_ATTR_DEREGISTER_CPUMASK
+
+	acctGroup = 0x20 // AGROUP, last thread of the thread group.
+
+	// Version 12 of struct taskstats added the thread group ID and wall
+	// clock time.
+	taskstatsVersionTGID = 12
+	taskstatsTGIDLen     = 384
+
+	taskstatsRecvBufSize = 1 << 20
+	maxPendingExits      = 65536 // Limit on exited threads whose process is still alive.
+)
+
+// WatchProcessExits registers a taskstats listener for all CPUs and streams
+// the accounting data of each process when its last thread exits. This
+// requires CAP_NET_ADMIN and a kernel with CONFIG_TASKSTATS. Kernels before
+// version 12 of struct taskstats do not report the thread group of a thread
+// so each thread is reported separately. Processes are identified in the
+// PID namespace of the current process.
+func (s linuxSystem) WatchProcessExits(ctx context.Context) (<-chan types.ProcessExitEvent, error) {
+	family, err := taskstatsFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	cpus, err := s.fs.ReadFile("sys/devices/system/cpu/possible")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read possible CPUs")
+	}
+	cpuMask := append(bytes.TrimSpace(cpus), 0)
+
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, netlinkGeneric)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create netlink socket")
+	}
+	if err = syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to bind netlink socket")
+	}
+
+	// A large buffer reduces the events dropped during bursts of exits.
+	syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF, taskstatsRecvBufSize)
+	tv := syscall.NsecToTimeval(procConnectorRecv.Nanoseconds())
+	if err = syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to set netlink receive timeout")
+	}
+
+	if err = sendTaskstatsCmd(fd, family, netlinkAttr(taskstatsCmdAttrRegisterCPUMask, cpuMask)); err != nil {
+		syscall.Close(fd)
+		return nil, errors.Wrap(err, "failed to register taskstats listener")
+	}
+
+	events := make(chan types.ProcessExitEvent)
+	go func() {
+		defer close(events)
+		defer syscall.Close(fd)
+		defer sendTaskstatsCmd(fd, family, netlinkAttr(taskstatsCmdAttrDeregisterCPUMask, cpuMask))
+
+		acc := newExitAccumulator()
+		buf := make([]byte, os.Getpagesize()*4)
+		for ctx.Err() == nil {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				switch err {
+				case syscall.EAGAIN, syscall.EINTR, syscall.ENOBUFS:
+					continue
+				}
+				return
+			}
+
+			msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+			if err != nil {
+				continue
+			}
+			for _, m := range msgs {
+				if m.Header.Type != family || len(m.Data) < genlHdrLen {
+					continue
+				}
+				stats, found := parseNetlinkAttrs(parseNetlinkAttrs(m.Data[genlHdrLen:])[taskstatsTypeAggrPID])[taskstatsTypeStats]
+				if !found {
+					continue
+				}
+				event, ok := acc.add(stats)
+				if !ok {
+					continue
+				}
+				event.Time = time.Now()
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// sendTaskstatsCmd sends a TASKSTATS_CMD_GET request with the given
+// attribute and waits for the acknowledgement.
+func sendTaskstatsCmd(fd int, family uint16, attr []byte) error {
+	msg := make([]byte, syscall.NLMSG_HDRLEN+genlHdrLen, syscall.NLMSG_HDRLEN+genlHdrLen+len(attr))
+	msg = append(msg, attr...)
+	nativeEndian.PutUint32(msg[0:], uint32(len(msg)))
+	nativeEndian.PutUint16(msg[4:], family)
+	nativeEndian.PutUint16(msg[6:], syscall.NLM_F_REQUEST|syscall.NLM_F_ACK)
+	msg[syscall.NLMSG_HDRLEN] = taskstatsCmdGet
+	msg[syscall.NLMSG_HDRLEN+1] = taskstatsGenlVersion
+
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, os.Getpagesize())
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Type == syscall.NLMSG_ERROR && len(m.Data) >= 4 {
+				if code := int32(nativeEndian.Uint32(m.Data)); code != 0 {
+					return syscall.Errno(-code)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// taskExit is the accounting record of an exited thread.
+type taskExit struct {
+	pid, tgid, ppid uint32
+	uid, gid        uint32
+	name            string
+	exitStatus      uint32
+	flags           uint8
+	start           time.Time
+	elapsed         time.Duration // Wall clock time of the thread.
+	groupElapsed    time.Duration // Wall clock time of the thread group (version 12).
+	stats           *types.TaskstatsInfo
+}
+
+// parseTaskExit decodes the identity and accounting fields of a struct
+// taskstats that is sent when a thread exits.
+func parseTaskExit(b []byte) (*taskExit, error) {
+	stats, err := parseTaskstats(b)
+	if err != nil {
+		return nil, err
+	}
+
+	u32 := func(offset int) uint32 { return nativeEndian.Uint32(b[offset:]) }
+	u64 := func(offset int) uint64 { return nativeEndian.Uint64(b[offset:]) }
+	t := &taskExit{
+		pid:        u32(128),
+		ppid:       u32(132),
+		uid:        u32(120),
+		gid:        u32(124),
+		name:       cString(b[80:112]),
+		exitStatus: u32(4),
+		flags:      b[8],
+		start:      time.Unix(int64(u32(136)), 0),
+		elapsed:    time.Duration(u64(144)) * time.Microsecond,
+		stats:      stats,
+	}
+	if version := nativeEndian.Uint16(b); version >= taskstatsVersionTGID && len(b) >= taskstatsTGIDLen {
+		t.start = time.Unix(int64(u64(344)), 0) // ac_btime64
+		t.tgid = u32(368)
+		t.groupElapsed = time.Duration(u64(376)) * time.Microsecond
+	}
+	return t, nil
+}
+
+// exitAccumulator sums the accounting data of the threads of a process
+// until its last thread exits.
+type exitAccumulator struct {
+	pending map[uint32]*taskExit // Keyed by thread group ID.
+}
+
+func newExitAccumulator() *exitAccumulator {
+	return &exitAccumulator{pending: map[uint32]*taskExit{}}
+}
+
+// add records the exit of a thread. It returns an event when the thread was
+// the last thread of its process.
+func (a *exitAccumulator) add(b []byte) (types.ProcessExitEvent, bool) {
+	t, err := parseTaskExit(b)
+	if err != nil {
+		return types.ProcessExitEvent{}, false
+	}
+
+	if t.tgid == 0 {
+		// The thread group is unknown so each thread is its own process.
+		return t.event(t.stats, t.start, t.elapsed), true
+	}
+
+	total := a.pending[t.tgid]
+	if t.flags&acctGroup == 0 {
+		if total == nil {
+			if len(a.pending) >= maxPendingExits {
+				// The last thread of these processes was probably not
+				// seen because events were dropped.
+				a.pending = map[uint32]*taskExit{}
+			}
+			a.pending[t.tgid] = t
+			return types.ProcessExitEvent{}, false
+		}
+		addTaskstats(total.stats, t.stats)
+		if t.pid == t.tgid {
+			total.start = t.start
+		}
+		return types.ProcessExitEvent{}, false
+	}
+
+	delete(a.pending, t.tgid)
+	start := t.start
+	if total != nil {
+		addTaskstats(t.stats, total.stats)
+		if t.pid != t.tgid {
+			start = total.start
+		}
+	}
+	return t.event(t.stats, start, t.groupElapsed), true
+}
+
+func (t *taskExit) event(totals *types.TaskstatsInfo, start time.Time, elapsed time.Duration) types.ProcessExitEvent {
+	pid := t.tgid
+	if pid == 0 {
+		pid = t.pid
+	}
+	return types.ProcessExitEvent{
+		PID:       int(pid),
+		PPID:      int(t.ppid),
+		Name:      t.name,
+		UID:       int(t.uid),
+		GID:       int(t.gid),
+		ExitCode:  int(t.exitStatus>>8) & 0xff,
+		Signal:    int(t.exitStatus & 0x7f),
+		StartTime: start,
+		Elapsed:   elapsed,
+		Totals:    *totals,
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+)
+
+var _ registry.ProcessExitWatcher = linuxSystem{}
+
+// taskExitStats returns a version 12 struct taskstats for an exited thread.
+func taskExitStats(pid, tgid uint32, flags uint8, utime uint64) []byte {
+	b := make([]byte, taskstatsTGIDLen)
+	nativeEndian.PutUint16(b[0:], taskstatsVersionTGID)
+	nativeEndian.PutUint32(b[4:], 2<<8) // ac_exitcode
+	b[8] = flags
+	copy(b[80:], "worker\x00")
+	nativeEndian.PutUint32(b[120:], 1000) // ac_uid
+	nativeEndian.PutUint32(b[132:], 1)    // ac_ppid
+	nativeEndian.PutUint32(b[128:], pid)
+	nativeEndian.PutUint64(b[144:], 500000) // ac_etime
+	nativeEndian.PutUint64(b[152:], utime)
+	nativeEndian.PutUint64(b[344:], uint64(1500000000+pid)) // ac_btime64
+	nativeEndian.PutUint32(b[368:], tgid)
+	nativeEndian.PutUint64(b[376:], 2000000) // ac_tgetime
+	return b
+}
+
+func TestExitAccumulator(t *testing.T) {
+	acc := newExitAccumulator()
+
+	// Threads of 100 exit before the last thread.
+	_, ok := acc.add(taskExitStats(101, 100, 0, 1000))
+	assert.False(t, ok)
+	_, ok = acc.add(taskExitStats(100, 100, 0, 2000))
+	assert.False(t, ok)
+
+	event, ok := acc.add(taskExitStats(102, 100, acctGroup, 3000))
+	if assert.True(t, ok) {
+		assert.Equal(t, 100, event.PID)
+		assert.Equal(t, 1, event.PPID)
+		assert.Equal(t, "worker", event.Name)
+		assert.Equal(t, 1000, event.UID)
+		assert.Equal(t, 2, event.ExitCode)
+		assert.Zero(t, event.Signal)
+		assert.Equal(t, time.Unix(1500000100, 0), event.StartTime)
+		assert.Equal(t, 2*time.Second, event.Elapsed)
+		assert.Equal(t, 6*time.Millisecond, event.Totals.UserTime)
+	}
+	assert.Empty(t, acc.pending)
+
+	// Single threaded process.
+	event, ok = acc.add(taskExitStats(200, 200, acctGroup, 1000))
+	if assert.True(t, ok) {
+		assert.Equal(t, 200, event.PID)
+		assert.Equal(t, time.Millisecond, event.Totals.UserTime)
+	}
+}
+
+func TestExitAccumulatorWithoutTGID(t *testing.T) {
+	b := taskExitStats(300, 0, 0, 1000)
+	nativeEndian.PutUint16(b[0:], 9)
+	nativeEndian.PutUint32(b[4:], 9) // Killed by SIGKILL.
+	nativeEndian.PutUint32(b[136:], 1500000000)
+
+	event, ok := newExitAccumulator().add(b)
+	if assert.True(t, ok) {
+		assert.Equal(t, 300, event.PID)
+		assert.Equal(t, 9, event.Signal)
+		assert.Equal(t, time.Unix(1500000000, 0), event.StartTime)
+		assert.Equal(t, 500*time.Millisecond, event.Elapsed)
+	}
+}
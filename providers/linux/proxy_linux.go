@@ -0,0 +1,211 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Sources of proxy settings on Linux.
+const (
+	proxySourceEnvironment    = "environment"
+	proxySourceEtcEnvironment = "/etc/environment"
+	proxySourceGNOME          = "gnome"
+)
+
+// ProxyConfig returns the proxy settings from the environment of the current
+// process, from /etc/environment, and from the system-wide GNOME settings in
+// the dconf keyfiles below /etc/dconf/db. The per-user GNOME settings are
+// stored in a binary database and are not read.
+func (h *host) ProxyConfig() ([]types.ProxySettings, error) {
+	return proxyConfig(h.fs, os.Getenv), nil
+}
+
+func proxyConfig(fs fileSystem, getenv func(string) string) []types.ProxySettings {
+	var settings []types.ProxySettings
+
+	if s := proxySettingsFromEnv(proxySourceEnvironment, getenv); s != nil {
+		settings = append(settings, *s)
+	}
+
+	if content, err := fs.ReadFile("etc/environment"); err == nil {
+		env := parseEnvironmentFile(content)
+		lookup := func(key string) string { return env[key] }
+		if s := proxySettingsFromEnv(proxySourceEtcEnvironment, lookup); s != nil {
+			settings = append(settings, *s)
+		}
+	}
+
+	if s := gnomeProxySettings(fs); s != nil {
+		settings = append(settings, *s)
+	}
+	return settings
+}
+
+// proxySettingsFromEnv reads the conventional proxy variables. The lower case
+// names take precedence like they do in curl and wget.
+func proxySettingsFromEnv(source string, getenv func(string) string) *types.ProxySettings {
+	get := func(name string) string {
+		if v := getenv(name); v != "" {
+			return v
+		}
+		return getenv(strings.ToUpper(name))
+	}
+
+	s := &types.ProxySettings{
+		Source: source,
+		HTTP:   get("http_proxy"),
+		HTTPS:  get("https_proxy"),
+		FTP:    get("ftp_proxy"),
+		SOCKS:  get("all_proxy"),
+	}
+	for _, host := range strings.Split(get("no_proxy"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			s.Bypass = append(s.Bypass, host)
+		}
+	}
+
+	if s.HTTP == "" && s.HTTPS == "" && s.FTP == "" && s.SOCKS == "" {
+		return nil
+	}
+	return s
+}
+
+// parseEnvironmentFile parses the KEY=value lines of /etc/environment.
+func parseEnvironmentFile(content []byte) map[string]string {
+	env := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		env[strings.TrimSpace(parts[0])] = unquote(strings.TrimSpace(parts[1]))
+	}
+	return env
+}
+
+// gnomeProxySettings reads the org.gnome.system.proxy keys from the dconf
+// keyfiles in /etc/dconf/db/<db>.d. Files are applied in lexical order so
+// later files override earlier ones as they do when dconf compiles them.
+func gnomeProxySettings(fs fileSystem) *types.ProxySettings {
+	files, err := fs.Glob("etc/dconf/db/*.d/*")
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	sort.Strings(files)
+
+	keys := map[string]string{}
+	for _, name := range files {
+		content, err := fs.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		parseDconfKeyfile(content, "system/proxy", keys)
+	}
+
+	s := &types.ProxySettings{Source: proxySourceGNOME}
+	switch keys["system/proxy/mode"] {
+	case "manual":
+		hostPort := func(group string) string {
+			host, port := keys[group+"/host"], keys[group+"/port"]
+			if host == "" {
+				return ""
+			}
+			if port == "" || port == "0" {
+				return host
+			}
+			return host + ":" + port
+		}
+		s.HTTP = hostPort("system/proxy/http")
+		s.HTTPS = hostPort("system/proxy/https")
+		s.FTP = hostPort("system/proxy/ftp")
+		s.SOCKS = hostPort("system/proxy/socks")
+		s.Bypass = parseGVariantStrings(keys["system/proxy/ignore-hosts"])
+	case "auto":
+		s.AutoConfigURL = keys["system/proxy/autoconfig-url"]
+		s.AutoDetect = s.AutoConfigURL == ""
+	default:
+		return nil
+	}
+	return s
+}
+
+// parseDconfKeyfile adds the keys of the groups below prefix to keys. The
+// keys are stored as <group>/<key> and string values are unquoted.
+func parseDconfKeyfile(content []byte, prefix string, keys map[string]string) {
+	var group string
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = strings.Trim(line[1:len(line)-1], "/")
+			continue
+		}
+
+		if group != prefix && !strings.HasPrefix(group, prefix+"/") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		keys[path.Join(group, strings.TrimSpace(parts[0]))] = unquote(strings.TrimSpace(parts[1]))
+	}
+}
+
+// parseGVariantStrings parses a GVariant string array such as
+// ['localhost', '127.0.0.0/8'].
+func parseGVariantStrings(s string) []string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "@as ")
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(s[1:len(s)-1], ",") {
+		if v = unquote(strings.TrimSpace(v)); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// unquote removes matching single or double quotes around s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.ProxyConfig = (*host)(nil)
+
+func TestProxyConfig(t *testing.T) {
+	fs := mapFS{
+		"etc/environment": `PATH="/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin"
+http_proxy="http://proxy.example.com:3128/"
+HTTPS_PROXY=http://proxy.example.com:3128/
+no_proxy=localhost,127.0.0.1, .example.com
+`,
+		"etc/dconf/db/local.d/00-proxy": `[system/proxy]
+mode='manual'
+ignore-hosts=['localhost', '127.0.0.0/8', '::1']
+
+[system/proxy/http]
+host='proxy.example.com'
+port=3128
+
+[system/proxy/socks]
+host='socks.example.com'
+port=1080
+
+[org/gnome/desktop/interface]
+clock-format='24h'
+`,
+		"etc/dconf/db/local.d/10-override": "[system/proxy/http]\nport=8080\n",
+	}
+	env := map[string]string{"ALL_PROXY": "socks5://127.0.0.1:9050"}
+
+	settings := proxyConfig(fs, func(key string) string { return env[key] })
+	assert.Equal(t, []types.ProxySettings{
+		{
+			Source: "environment",
+			SOCKS:  "socks5://127.0.0.1:9050",
+		},
+		{
+			Source: "/etc/environment",
+			HTTP:   "http://proxy.example.com:3128/",
+			HTTPS:  "http://proxy.example.com:3128/",
+			Bypass: []string{"localhost", "127.0.0.1", ".example.com"},
+		},
+		{
+			Source: "gnome",
+			HTTP:   "proxy.example.com:8080",
+			SOCKS:  "socks.example.com:1080",
+			Bypass: []string{"localhost", "127.0.0.0/8", "::1"},
+		},
+	}, settings)
+
+	fs = mapFS{"etc/dconf/db/site.d/proxy": "[system/proxy]\nmode='auto'\nautoconfig-url='http://wpad.example.com/proxy.pac'\n"}
+	settings = proxyConfig(fs, func(string) string { return "" })
+	assert.Equal(t, []types.ProxySettings{
+		{Source: "gnome", AutoConfigURL: "http://wpad.example.com/proxy.pac"},
+	}, settings)
+
+	assert.Empty(t, proxyConfig(mapFS{}, func(string) string { return "" }))
+}
@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// RebootRequired checks for the reboot-required flag file that is created by
+// Debian and Ubuntu package scripts and compares the running kernel to the
+// kernels that are installed in /lib/modules.
+func (h *host) RebootRequired() (*types.RebootRequiredInfo, error) {
+	return rebootRequired(h.fs, h.info.KernelVersion)
+}
+
+func rebootRequired(fs fileSystem, running string) (*types.RebootRequiredInfo, error) {
+	info := &types.RebootRequiredInfo{}
+
+	for _, name := range []string{"run/reboot-required", "var/run/reboot-required"} {
+		if _, err := fs.Stat(name); err != nil {
+			continue
+		}
+		info.Reasons = append(info.Reasons, "/"+name+" exists")
+
+		if content, err := fs.ReadFile(name + ".pkgs"); err == nil {
+			info.Packages = uniqueLines(content)
+		}
+		break
+	}
+
+	// Containers and minimal images often have no /lib/modules so the kernel
+	// checks only run when it exists.
+	if entries, err := fs.ReadDir("lib/modules"); err == nil && running != "" {
+		var newest string
+		var installed bool
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if e.Name() == running {
+				installed = true
+			}
+			if newest == "" || compareVersions(e.Name(), newest) > 0 {
+				newest = e.Name()
+			}
+		}
+
+		switch {
+		case !installed:
+			info.Reasons = append(info.Reasons, fmt.Sprintf("modules of the running kernel %v are not installed", running))
+		case compareVersions(newest, running) > 0:
+			info.Reasons = append(info.Reasons, fmt.Sprintf("kernel %v is installed but %v is running", newest, running))
+		}
+	}
+
+	info.Required = len(info.Reasons) > 0
+	return info, nil
+}
+
+func uniqueLines(content []byte) []string {
+	seen := map[string]struct{}{}
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if _, found := seen[line]; found || line == "" {
+			continue
+		}
+		seen[line] = struct{}{}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// compareVersions compares kernel releases (e.g. 4.13.0-16-generic) by their
+// numeric and non-numeric parts. It returns -1, 0, or 1.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.ParseUint(pa[i], 10, 64)
+		nb, errB := strconv.ParseUint(pb[i], 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case pa[i] != pb[i]:
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(pa) < len(pb):
+		return -1
+	case len(pa) > len(pb):
+		return 1
+	}
+	return 0
+}
+
+// versionParts splits a version on characters that are not letters or digits.
+func versionParts(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return !unicode.IsDigit(r) && !unicode.IsLetter(r)
+	})
+}
@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.RebootRequired = (*host)(nil)
+var _ types.OSPatches = (*host)(nil)
+
+func TestRebootRequired(t *testing.T) {
+	fs := mapFS{
+		"lib/modules/4.13.0-16-generic/modules.dep": "",
+		"lib/modules/4.13.0-17-generic/modules.dep": "",
+	}
+
+	info, err := rebootRequired(fs, "4.13.0-17-generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.RebootRequiredInfo{}, info)
+
+	info, err = rebootRequired(fs, "4.13.0-16-generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.RebootRequiredInfo{
+		Required: true,
+		Reasons:  []string{"kernel 4.13.0-17-generic is installed but 4.13.0-16-generic is running"},
+	}, info)
+
+	fs["var/run/reboot-required"] = "*** System restart required ***\n"
+	fs["var/run/reboot-required.pkgs"] = "linux-base\nlibc6\nlinux-base\n"
+	info, err = rebootRequired(fs, "4.13.0-9-generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.RebootRequiredInfo{
+		Required: true,
+		Reasons: []string{
+			"/var/run/reboot-required exists",
+			"modules of the running kernel 4.13.0-9-generic are not installed",
+		},
+		Packages: []string{"libc6", "linux-base"},
+	}, info)
+
+	// Without /lib/modules only the flag file is checked.
+	info, err = rebootRequired(mapFS{}, "4.13.0-16-generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, info.Required)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 1, compareVersions("4.13.0-17-generic", "4.13.0-16-generic"))
+	assert.Equal(t, 1, compareVersions("5.10.0-1", "5.9.0-30"))
+	assert.Equal(t, -1, compareVersions("4.18.0-80.el8.x86_64", "4.18.0-147.el8.x86_64"))
+	assert.Equal(t, 0, compareVersions("6.1.0", "6.1.0"))
+}
+
+func TestInstalledKernels(t *testing.T) {
+	fs := mapFS{
+		"lib/modules/4.13.0-9-generic/modules.dep":  "",
+		"lib/modules/4.13.0-16-generic/modules.dep": "",
+		"lib/modules/4.13.0-17-generic/modules.dep": "",
+	}
+
+	kernels, err := installedKernels(fs, "4.13.0-16-generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.OSPatchInfo{
+		{ID: "4.13.0-17-generic", Description: "kernel"},
+		{ID: "4.13.0-16-generic", Description: "kernel", Current: true},
+		{ID: "4.13.0-9-generic", Description: "kernel"},
+	}, kernels)
+}
@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// limitNames maps the row names of /proc/<pid>/limits to resource names.
+var limitNames = map[string]string{
+	"Max cpu time":          types.RLimitCPU,
+	"Max file size":         types.RLimitFSize,
+	"Max data size":         types.RLimitData,
+	"Max stack size":        types.RLimitStack,
+	"Max core file size":    types.RLimitCore,
+	"Max resident set":      types.RLimitRSS,
+	"Max processes":         types.RLimitNProc,
+	"Max open files":        types.RLimitNoFile,
+	"Max locked memory":     types.RLimitMemLock,
+	"Max address space":     types.RLimitAS,
+	"Max file locks":        types.RLimitLocks,
+	"Max pending signals":   types.RLimitSigPending,
+	"Max msgqueue size":     types.RLimitMsgQueue,
+	"Max nice priority":     types.RLimitNice,
+	"Max realtime priority": types.RLimitRTPrio,
+	"Max realtime timeout":  types.RLimitRTTime,
+}
+
+// RLimits returns the resource limits of the process as reported by
+// /proc/<pid>/limits. Reading the limits of a process owned by another user
+// requires root or CAP_SYS_PTRACE.
+func (p *process) RLimits() (map[string]types.RLimit, error) {
+	data, err := p.fs.ReadFile(p.name("limits"))
+	if err != nil {
+		return nil, err
+	}
+	return parseLimits(data)
+}
+
+// parseLimits parses the contents of /proc/<pid>/limits. The kernel writes
+// the limit name in a 25 character wide column followed by the soft limit,
+// hard limit, and units separated by whitespace.
+func parseLimits(data []byte) (map[string]types.RLimit, error) {
+	limits := map[string]types.RLimit{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if len(line) <= 25 || strings.HasPrefix(line, "Limit ") {
+			continue
+		}
+
+		name, ok := limitNames[strings.TrimSpace(line[:25])]
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(line[25:])
+		if len(fields) < 2 {
+			return nil, errors.Errorf("failed to parse limits line: %q", line)
+		}
+		soft, err := parseLimitValue(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse soft %v limit", name)
+		}
+		hard, err := parseLimitValue(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse hard %v limit", name)
+		}
+		limits[name] = types.RLimit{Soft: soft, Hard: hard}
+	}
+	return limits, s.Err()
+}
+
+func parseLimitValue(v string) (uint64, error) {
+	if v == "unlimited" {
+		return types.RLimitInfinity, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+// RaiseOpenFileLimit raises the soft limit on open files of the current
+// process to its hard limit.
+func (p *process) RaiseOpenFileLimit() (types.RLimit, error) {
+	if p.PID() != os.Getpid() || !isProcFS(string(p.procFS)) {
+		return types.RLimit{}, errors.Errorf("open file limit can only be raised for the current process (pid %d)", p.PID())
+	}
+	return shared.RaiseOpenFileLimit(types.RLimitInfinity, types.RLimitInfinity)
+}
@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var (
+	_ types.RLimits             = (*process)(nil)
+	_ types.OpenFileLimitRaiser = (*process)(nil)
+)
+
+const procLimits = `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max file size             unlimited            unlimited            bytes     
+Max data size             unlimited            unlimited            bytes     
+Max stack size            8388608              unlimited            bytes     
+Max core file size        0                    unlimited            bytes     
+Max resident set          unlimited            unlimited            bytes     
+Max processes             23961                23961                processes 
+Max open files            1024                 524288               files     
+Max locked memory         8388608              8388608              bytes     
+Max address space         unlimited            unlimited            bytes     
+Max file locks            unlimited            unlimited            locks     
+Max pending signals       23961                23961                signals   
+Max msgqueue size         819200               819200               bytes     
+Max nice priority         0                    0                    
+Max realtime priority     0                    0                    
+Max realtime timeout      unlimited            unlimited            us        
+`
+
+func TestParseLimits(t *testing.T) {
+	limits, err := parseLimits([]byte(procLimits))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, limits, len(limitNames))
+	assert.Equal(t, types.RLimit{Soft: 1024, Hard: 524288}, limits[types.RLimitNoFile])
+	assert.Equal(t, types.RLimit{Soft: 0, Hard: types.RLimitInfinity}, limits[types.RLimitCore])
+	assert.Equal(t, types.RLimit{Soft: 8388608, Hard: types.RLimitInfinity}, limits[types.RLimitStack])
+	assert.Equal(t, types.RLimit{Soft: types.RLimitInfinity, Hard: types.RLimitInfinity}, limits[types.RLimitRTTime])
+	assert.Equal(t, types.RLimit{}, limits[types.RLimitNice])
+
+	_, err = parseLimits([]byte("Max open files            many                 524288               files\n"))
+	assert.Error(t, err)
+}
+
+func TestSelfRLimits(t *testing.T) {
+	system := newLinuxSystem("")
+	self, err := system.Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limit, err := self.(types.OpenFileLimitRaiser).RaiseOpenFileLimit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, limit.Hard, limit.Soft)
+
+	limits, err := self.(types.RLimits).RLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, limits, len(limitNames))
+	assert.Equal(t, limit, limits[types.RLimitNoFile])
+
+	parent, err := system.Process(os.Getppid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits, err = parent.(types.RLimits).RLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotZero(t, limits[types.RLimitNoFile].Hard)
+
+	_, err = parent.(types.OpenFileLimitRaiser).RaiseOpenFileLimit()
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/rtnetlink.h and linux/neighbour.h.
+const (
+	rtmsgLen = 12 // sizeof(struct rtmsg)
+	ndmsgLen = 12 // sizeof(struct ndmsg)
+
+	rtaDst      = 1  // RTA_DST
+	rtaOIF      = 4  // RTA_OIF
+	rtaGateway  = 5  // RTA_GATEWAY
+	rtaPriority = 6  // RTA_PRIORITY
+	rtaPrefSrc  = 7  // RTA_PREFSRC
+	rtaTable    = 15 // RTA_TABLE
+
+	rtnUnicast    = 1     // RTN_UNICAST
+	rtTableLocal  = 255   // RT_TABLE_LOCAL
+	rtmFlagCloned = 0x200 // RTM_F_CLONED
+
+	ndaDst    = 1 // NDA_DST
+	ndaLLAddr = 2 // NDA_LLADDR
+
+	nudIncomplete = 0x01 // NUD_INCOMPLETE
+	nudReachable  = 0x02 // NUD_REACHABLE
+	nudStale      = 0x04 // NUD_STALE
+	nudDelay      = 0x08 // NUD_DELAY
+	nudProbe      = 0x10 // NUD_PROBE
+	nudFailed     = 0x20 // NUD_FAILED
+	nudNoARP      = 0x40 // NUD_NOARP
+	nudPermanent  = 0x80 // NUD_PERMANENT
+)
+
+var neighborStates = []struct {
+	flag  uint16
+	state string
+}{
+	{nudPermanent, types.NeighborStatePermanent},
+	{nudReachable, types.NeighborStateReachable},
+	{nudStale, types.NeighborStateStale},
+	{nudDelay, types.NeighborStateDelay},
+	{nudProbe, types.NeighborStateProbe},
+	{nudFailed, types.NeighborStateFailed},
+	{nudIncomplete, types.NeighborStateIncomplete},
+}
+
+// Routes returns the unicast routes of all routing tables except the local
+// table, which only holds the addresses of the host itself.
+func (h *host) Routes() ([]types.RouteInfo, error) {
+	msgs, err := netlinkRequest(syscall.NETLINK_ROUTE, syscall.RTM_GETROUTE, syscall.NLM_F_DUMP, make([]byte, rtmsgLen))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump routing table")
+	}
+
+	names := shared.InterfaceNames()
+	routes := make([]types.RouteInfo, 0, len(msgs))
+	for _, msg := range msgs {
+		if route, ok := parseRouteMessage(msg, names); ok {
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// Neighbors returns the ARP and NDP entries of the host. Entries in the
+// NOARP state (e.g. multicast addresses) are omitted like `ip neigh` does.
+func (h *host) Neighbors() ([]types.NeighborInfo, error) {
+	msgs, err := netlinkRequest(syscall.NETLINK_ROUTE, syscall.RTM_GETNEIGH, syscall.NLM_F_DUMP, make([]byte, ndmsgLen))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dump neighbor table")
+	}
+
+	names := shared.InterfaceNames()
+	neighbors := make([]types.NeighborInfo, 0, len(msgs))
+	for _, msg := range msgs {
+		if neighbor, ok := parseNeighborMessage(msg, names); ok {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	return neighbors, nil
+}
+
+// parseRouteMessage parses a RTM_NEWROUTE message (struct rtmsg followed by
+// attributes). It returns false for messages that are not reported.
+func parseRouteMessage(b []byte, names map[int]string) (types.RouteInfo, bool) {
+	if len(b) < rtmsgLen {
+		return types.RouteInfo{}, false
+	}
+	family, ok := inetFamily(b[0])
+	if !ok || b[7] != rtnUnicast || nativeEndian.Uint32(b[8:])&rtmFlagCloned != 0 {
+		return types.RouteInfo{}, false
+	}
+
+	attrs := parseNetlinkAttrs(b[rtmsgLen:])
+	route := types.RouteInfo{
+		Family:       family,
+		Destination:  attrs[rtaDst],
+		PrefixLength: int(b[1]),
+		Gateway:      attrs[rtaGateway],
+		Source:       attrs[rtaPrefSrc],
+		Table:        int(b[4]),
+	}
+	if route.Destination == nil {
+		route.Destination = unspecifiedIP(family)
+	}
+	if v := attrs[rtaTable]; len(v) >= 4 {
+		route.Table = int(nativeEndian.Uint32(v))
+	}
+	if route.Table == rtTableLocal {
+		return types.RouteInfo{}, false
+	}
+	if v := attrs[rtaPriority]; len(v) >= 4 {
+		route.Metric = int(nativeEndian.Uint32(v))
+	}
+	if v := attrs[rtaOIF]; len(v) >= 4 {
+		route.Interface = names[int(nativeEndian.Uint32(v))]
+	}
+	return route, true
+}
+
+// parseNeighborMessage parses a RTM_NEWNEIGH message (struct ndmsg followed
+// by attributes). It returns false for messages that are not reported.
+func parseNeighborMessage(b []byte, names map[int]string) (types.NeighborInfo, bool) {
+	if len(b) < ndmsgLen {
+		return types.NeighborInfo{}, false
+	}
+	family, ok := inetFamily(b[0])
+	state := nativeEndian.Uint16(b[8:])
+	if !ok || state == 0 || state&nudNoARP != 0 {
+		return types.NeighborInfo{}, false
+	}
+
+	attrs := parseNetlinkAttrs(b[ndmsgLen:])
+	if attrs[ndaDst] == nil {
+		return types.NeighborInfo{}, false
+	}
+
+	neighbor := types.NeighborInfo{
+		Family:    family,
+		IP:        attrs[ndaDst],
+		Interface: names[int(int32(nativeEndian.Uint32(b[4:])))],
+	}
+	if mac := attrs[ndaLLAddr]; len(mac) > 0 {
+		neighbor.MAC = net.HardwareAddr(mac).String()
+	}
+	for _, s := range neighborStates {
+		if state&s.flag != 0 {
+			neighbor.State = s.state
+			break
+		}
+	}
+	return neighbor, true
+}
+
+func inetFamily(family uint8) (string, bool) {
+	switch family {
+	case syscall.AF_INET:
+		return types.FamilyIPv4, true
+	case syscall.AF_INET6:
+		return types.FamilyIPv6, true
+	default:
+		return "", false
+	}
+}
+
+func unspecifiedIP(family string) net.IP {
+	if family == types.FamilyIPv4 {
+		return net.IPv4zero.To4()
+	}
+	return net.IPv6zero
+}
@@ -0,0 +1,147 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var (
+	_ types.Routes    = (*host)(nil)
+	_ types.Neighbors = (*host)(nil)
+)
+
+func rtmsg(family, dstLen, table, typ uint8, flags uint32, attrs ...[]byte) []byte {
+	b := make([]byte, rtmsgLen)
+	b[0], b[1], b[4], b[7] = family, dstLen, table, typ
+	nativeEndian.PutUint32(b[8:], flags)
+	for _, attr := range attrs {
+		b = append(b, attr...)
+	}
+	return b
+}
+
+func ndmsg(family uint8, ifindex int32, state uint16, attrs ...[]byte) []byte {
+	b := make([]byte, ndmsgLen)
+	b[0] = family
+	nativeEndian.PutUint32(b[4:], uint32(ifindex))
+	nativeEndian.PutUint16(b[8:], state)
+	for _, attr := range attrs {
+		b = append(b, attr...)
+	}
+	return b
+}
+
+func TestParseRouteMessage(t *testing.T) {
+	names := map[int]string{2: "eth0"}
+
+	// Default route via a gateway.
+	route, ok := parseRouteMessage(rtmsg(syscall.AF_INET, 0, 254, rtnUnicast, 0,
+		netlinkAttr(rtaTable, uint32Bytes(254)),
+		netlinkAttr(rtaGateway, net.ParseIP("10.0.2.2").To4()),
+		netlinkAttr(rtaOIF, uint32Bytes(2)),
+		netlinkAttr(rtaPriority, uint32Bytes(100)),
+	), names)
+	if assert.True(t, ok) {
+		assert.Equal(t, types.RouteInfo{
+			Family:       types.FamilyIPv4,
+			Destination:  net.IP{0, 0, 0, 0},
+			PrefixLength: 0,
+			Gateway:      net.IP{10, 0, 2, 2},
+			Interface:    "eth0",
+			Metric:       100,
+			Table:        254,
+		}, route)
+	}
+
+	// Directly connected network.
+	route, ok = parseRouteMessage(rtmsg(syscall.AF_INET, 24, 254, rtnUnicast, 0,
+		netlinkAttr(rtaDst, net.ParseIP("10.0.2.0").To4()),
+		netlinkAttr(rtaPrefSrc, net.ParseIP("10.0.2.15").To4()),
+		netlinkAttr(rtaOIF, uint32Bytes(2)),
+	), names)
+	if assert.True(t, ok) {
+		assert.Equal(t, "10.0.2.0", route.Destination.String())
+		assert.Equal(t, 24, route.PrefixLength)
+		assert.Nil(t, route.Gateway)
+		assert.Equal(t, "10.0.2.15", route.Source.String())
+	}
+
+	// IPv6 default route.
+	route, ok = parseRouteMessage(rtmsg(syscall.AF_INET6, 0, 254, rtnUnicast, 0,
+		netlinkAttr(rtaGateway, net.ParseIP("fe80::1")),
+	), names)
+	if assert.True(t, ok) {
+		assert.Equal(t, types.FamilyIPv6, route.Family)
+		assert.Equal(t, "::", route.Destination.String())
+		assert.Equal(t, "fe80::1", route.Gateway.String())
+		assert.Empty(t, route.Interface)
+	}
+
+	// Local table, broadcast routes, and cloned routes are not reported.
+	_, ok = parseRouteMessage(rtmsg(syscall.AF_INET, 32, rtTableLocal, rtnUnicast, 0), names)
+	assert.False(t, ok)
+	_, ok = parseRouteMessage(rtmsg(syscall.AF_INET, 32, 254, 3, 0), names)
+	assert.False(t, ok)
+	_, ok = parseRouteMessage(rtmsg(syscall.AF_INET6, 128, 254, rtnUnicast, rtmFlagCloned), names)
+	assert.False(t, ok)
+	_, ok = parseRouteMessage(rtmsg(syscall.AF_BRIDGE, 0, 254, rtnUnicast, 0), names)
+	assert.False(t, ok)
+	_, ok = parseRouteMessage([]byte{syscall.AF_INET}, names)
+	assert.False(t, ok)
+}
+
+func TestParseNeighborMessage(t *testing.T) {
+	names := map[int]string{2: "eth0"}
+
+	neighbor, ok := parseNeighborMessage(ndmsg(syscall.AF_INET, 2, nudReachable,
+		netlinkAttr(ndaDst, net.ParseIP("10.0.2.2").To4()),
+		netlinkAttr(ndaLLAddr, []byte{0x52, 0x54, 0x00, 0x12, 0x35, 0x02}),
+	), names)
+	if assert.True(t, ok) {
+		assert.Equal(t, types.NeighborInfo{
+			Family:    types.FamilyIPv4,
+			IP:        net.IP{10, 0, 2, 2},
+			MAC:       "52:54:00:12:35:02",
+			Interface: "eth0",
+			State:     types.NeighborStateReachable,
+		}, neighbor)
+	}
+
+	neighbor, ok = parseNeighborMessage(ndmsg(syscall.AF_INET6, 2, nudFailed,
+		netlinkAttr(ndaDst, net.ParseIP("fe80::2")),
+	), names)
+	if assert.True(t, ok) {
+		assert.Equal(t, "fe80::2", neighbor.IP.String())
+		assert.Empty(t, neighbor.MAC)
+		assert.Equal(t, types.NeighborStateFailed, neighbor.State)
+	}
+
+	_, ok = parseNeighborMessage(ndmsg(syscall.AF_INET, 1, nudNoARP,
+		netlinkAttr(ndaDst, net.ParseIP("224.0.0.1").To4()),
+	), names)
+	assert.False(t, ok)
+	_, ok = parseNeighborMessage(ndmsg(syscall.AF_INET, 2, nudStale), names)
+	assert.False(t, ok)
+}
@@ -0,0 +1,273 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Berkeley DB hash database constants (see db_page.h).
+const (
+	bdbHashMagic = 0x061561
+
+	bdbPageHeaderSize = 26
+
+	bdbPageTypeHashUnsorted = 2
+	bdbPageTypeOverflow     = 7
+	bdbPageTypeHash         = 13
+
+	bdbHashKeyData = 1 // H_KEYDATA item.
+	bdbHashOffPage = 3 // H_OFFPAGE item.
+)
+
+// readRPMDatabase reads all package headers of an rpm Packages database in
+// Berkeley DB hash format. The values of the database are rpm header blobs
+// that are usually larger than a page and so stored on overflow pages.
+func readRPMDatabase(r io.ReaderAt) ([]types.PackageInfo, error) {
+	meta := make([]byte, 512)
+	if _, err := r.ReadAt(meta, 0); err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata page")
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(meta[12:]) != bdbHashMagic {
+		order = binary.BigEndian
+		if order.Uint32(meta[12:]) != bdbHashMagic {
+			return nil, errors.New("not a Berkeley DB hash database")
+		}
+	}
+
+	pageSize := order.Uint32(meta[20:])
+	lastPage := order.Uint32(meta[32:])
+	if pageSize < 512 || pageSize > 64*1024 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+
+	db := &bdbHash{r: r, order: order, pageSize: pageSize}
+	var pkgs []types.PackageInfo
+	page := make([]byte, pageSize)
+	for pgno := uint32(1); pgno <= lastPage; pgno++ {
+		if err := db.readPage(pgno, page); err != nil {
+			return nil, err
+		}
+		if t := page[25]; t != bdbPageTypeHash && t != bdbPageTypeHashUnsorted {
+			continue
+		}
+
+		values, err := db.pageValues(page)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read page %d", pgno)
+		}
+		for _, v := range values {
+			// Small values (e.g. the counter stored under key 0) are not
+			// headers.
+			if len(v) < 16 {
+				continue
+			}
+			pkg, err := parseRPMHeader(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse rpm header on page %d", pgno)
+			}
+			pkgs = append(pkgs, *pkg)
+		}
+	}
+	return pkgs, nil
+}
+
+type bdbHash struct {
+	r        io.ReaderAt
+	order    binary.ByteOrder
+	pageSize uint32
+}
+
+func (db *bdbHash) readPage(pgno uint32, page []byte) error {
+	if _, err := db.r.ReadAt(page, int64(pgno)*int64(db.pageSize)); err != nil {
+		return errors.Wrapf(err, "failed to read page %d", pgno)
+	}
+	return nil
+}
+
+// pageValues returns the data items of a hash page. Items are stored as
+// key/data pairs and their offsets are listed after the page header. Items
+// are allocated from the end of the page so each item ends where the
+// previous one starts.
+func (db *bdbHash) pageValues(page []byte) ([][]byte, error) {
+	entries := int(db.order.Uint16(page[20:]))
+	if bdbPageHeaderSize+2*entries > len(page) {
+		return nil, errors.Errorf("invalid entry count %d", entries)
+	}
+
+	offset := func(i int) int {
+		return int(db.order.Uint16(page[bdbPageHeaderSize+2*i:]))
+	}
+
+	var values [][]byte
+	for i := 1; i < entries; i += 2 {
+		start, end := offset(i), offset(i-1)
+		if start >= end || end > len(page) {
+			return nil, errors.Errorf("invalid item offset %d", start)
+		}
+
+		item := page[start:end]
+		switch item[0] {
+		case bdbHashKeyData:
+			values = append(values, item[1:])
+		case bdbHashOffPage:
+			if len(item) < 12 {
+				return nil, errors.New("short off-page item")
+			}
+			v, err := db.overflow(db.order.Uint32(item[4:]), db.order.Uint32(item[8:]))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// overflow reads a value that is stored in a chain of overflow pages.
+func (db *bdbHash) overflow(pgno, length uint32) ([]byte, error) {
+	value := make([]byte, 0, length)
+	page := make([]byte, db.pageSize)
+	for pgno != 0 && uint32(len(value)) < length {
+		if err := db.readPage(pgno, page); err != nil {
+			return nil, err
+		}
+		if page[25] != bdbPageTypeOverflow {
+			return nil, errors.Errorf("page %d is not an overflow page", pgno)
+		}
+
+		used := int(db.order.Uint16(page[22:]))
+		if bdbPageHeaderSize+used > len(page) {
+			return nil, errors.Errorf("invalid overflow length on page %d", pgno)
+		}
+		value = append(value, page[bdbPageHeaderSize:bdbPageHeaderSize+used]...)
+		pgno = db.order.Uint32(page[16:])
+	}
+	if uint32(len(value)) < length {
+		return nil, errors.New("overflow chain is shorter than the value")
+	}
+	return value[:length], nil
+}
+
+// rpm header tags and types (see rpmtag.h).
+const (
+	rpmTagName        = 1000
+	rpmTagVersion     = 1001
+	rpmTagRelease     = 1002
+	rpmTagEpoch       = 1003
+	rpmTagSummary     = 1004
+	rpmTagInstallTime = 1008
+	rpmTagSize        = 1009
+	rpmTagVendor      = 1011
+	rpmTagArch        = 1022
+
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// parseRPMHeader parses a header blob as it is stored in the database. It is
+// an index of tag entries followed by the data store. All values are big
+// endian.
+func parseRPMHeader(data []byte) (*types.PackageInfo, error) {
+	if len(data) < 8 {
+		return nil, errors.New("header is too short")
+	}
+	il := binary.BigEndian.Uint32(data[0:])
+	dl := binary.BigEndian.Uint32(data[4:])
+	if il > 0xffff || uint64(8)+uint64(il)*16+uint64(dl) > uint64(len(data)) {
+		return nil, errors.Errorf("invalid header size (%d entries, %d bytes)", il, dl)
+	}
+	index := data[8 : 8+il*16]
+	store := data[8+il*16 : 8+il*16+dl]
+
+	pkg := &types.PackageInfo{Type: types.PackageTypeRPM}
+	var epoch *uint32
+	for i := uint32(0); i < il; i++ {
+		e := index[i*16:]
+		tag := binary.BigEndian.Uint32(e[0:])
+		typ := binary.BigEndian.Uint32(e[4:])
+		off := binary.BigEndian.Uint32(e[8:])
+		if off >= dl {
+			continue
+		}
+		value := store[off:]
+
+		str := func() string {
+			if typ != rpmTypeString && typ != rpmTypeStringArray && typ != rpmTypeI18NString {
+				return ""
+			}
+			if end := bytes.IndexByte(value, 0); end >= 0 {
+				return string(value[:end])
+			}
+			return string(value)
+		}
+		int32Value := func() (uint32, bool) {
+			if typ != rpmTypeInt32 || len(value) < 4 {
+				return 0, false
+			}
+			return binary.BigEndian.Uint32(value), true
+		}
+
+		switch tag {
+		case rpmTagName:
+			pkg.Name = str()
+		case rpmTagVersion:
+			pkg.Version = str()
+		case rpmTagRelease:
+			pkg.Release = str()
+		case rpmTagSummary:
+			pkg.Summary = str()
+		case rpmTagVendor:
+			pkg.Vendor = str()
+		case rpmTagArch:
+			pkg.Arch = str()
+		case rpmTagEpoch:
+			if v, ok := int32Value(); ok {
+				epoch = &v
+			}
+		case rpmTagInstallTime:
+			if v, ok := int32Value(); ok {
+				pkg.InstallTime = time.Unix(int64(v), 0).UTC()
+			}
+		case rpmTagSize:
+			if v, ok := int32Value(); ok {
+				pkg.Size = uint64(v)
+			}
+		}
+	}
+
+	if pkg.Name == "" {
+		return nil, errors.New("header has no name")
+	}
+	if epoch != nil && *epoch > 0 {
+		pkg.Version = fmt.Sprintf("%d:%v", *epoch, pkg.Version)
+	}
+	return pkg, nil
+}
@@ -0,0 +1,428 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// SQLite file format constants (see https://www.sqlite.org/fileformat2.html).
+const (
+	sqliteHeader     = "SQLite format 3\x00"
+	sqliteHeaderSize = 100
+
+	sqlitePageInteriorTable = 0x05
+	sqlitePageLeafTable     = 0x0d
+
+	sqliteWALMagicLE     = 0x377f0682
+	sqliteWALMagicBE     = 0x377f0683
+	sqliteWALHeaderSize  = 32
+	sqliteWALFrameHeader = 24
+
+	// sqliteMaxPayload bounds the size of a row so that a corrupt database
+	// can't make the reader allocate unbounded memory.
+	sqliteMaxPayload = 1 << 28
+)
+
+// readRPMSQLite reads all package headers of the sqlite rpm database
+// (rpmdb.sqlite) that is used by rpm 4.16 and newer. The Packages table holds
+// the same header blobs as the Berkeley DB database. wal is the write-ahead
+// log (rpmdb.sqlite-wal) or nil if there is none.
+func readRPMSQLite(r, wal io.ReaderAt) ([]types.PackageInfo, error) {
+	db, err := openSQLite(r, wal)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []types.PackageInfo
+	err = db.table("Packages", func(cols []interface{}) error {
+		if len(cols) < 2 {
+			return nil
+		}
+		blob, ok := cols[1].([]byte)
+		if !ok {
+			return nil
+		}
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse rpm header")
+		}
+		pkgs = append(pkgs, *pkg)
+		return nil
+	})
+	return pkgs, err
+}
+
+// sqliteDB reads the tables of an SQLite 3 database. Pages that were
+// committed to the write-ahead log but not yet copied back to the database
+// are read from the log.
+type sqliteDB struct {
+	r        io.ReaderAt
+	pageSize int
+	usable   int // Page size without the reserved space at the end of each page.
+
+	wal      io.ReaderAt
+	walPages map[uint32]int64 // Offsets of the latest committed page images in the WAL.
+}
+
+func openSQLite(r, wal io.ReaderAt) (*sqliteDB, error) {
+	hdr := make([]byte, sqliteHeaderSize)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return nil, errors.Wrap(err, "failed to read database header")
+	}
+	if string(hdr[:len(sqliteHeader)]) != sqliteHeader {
+		return nil, errors.New("not an SQLite database")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(hdr[16:]))
+	if pageSize == 1 {
+		pageSize = 64 * 1024
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+
+	db := &sqliteDB{r: r, pageSize: pageSize, usable: pageSize - int(hdr[20])}
+	if db.usable < 480 {
+		return nil, errors.Errorf("invalid reserved space %d", hdr[20])
+	}
+	if wal != nil {
+		if err := db.readWAL(wal); err != nil {
+			return nil, errors.Wrap(err, "failed to read write-ahead log")
+		}
+	}
+	return db, nil
+}
+
+// readWAL indexes the frames of the write-ahead log up to the last commit.
+// Frames whose salt or checksum doesn't match are left over from an earlier
+// log or were not completely written, and they end the log.
+func (db *sqliteDB) readWAL(wal io.ReaderAt) error {
+	hdr := make([]byte, sqliteWALHeaderSize)
+	if n, err := wal.ReadAt(hdr, 0); err != nil {
+		if n == 0 && err == io.EOF {
+			// The log is empty.
+			return nil
+		}
+		return err
+	}
+
+	var order binary.ByteOrder
+	switch binary.BigEndian.Uint32(hdr) {
+	case sqliteWALMagicLE:
+		order = binary.LittleEndian
+	case sqliteWALMagicBE:
+		order = binary.BigEndian
+	default:
+		return errors.New("invalid magic number")
+	}
+	if int(binary.BigEndian.Uint32(hdr[8:])) != db.pageSize {
+		return errors.New("page size does not match the database")
+	}
+	s0, s1 := walChecksum(order, 0, 0, hdr[:24])
+	if s0 != binary.BigEndian.Uint32(hdr[24:]) || s1 != binary.BigEndian.Uint32(hdr[28:]) {
+		// A log with an invalid header is ignored.
+		return nil
+	}
+
+	committed := map[uint32]int64{}
+	pending := map[uint32]int64{}
+	frame := make([]byte, sqliteWALFrameHeader+db.pageSize)
+	for off := int64(sqliteWALHeaderSize); ; off += int64(len(frame)) {
+		if _, err := wal.ReadAt(frame, off); err != nil {
+			break
+		}
+		if !bytes.Equal(frame[8:16], hdr[16:24]) {
+			break
+		}
+		s0, s1 = walChecksum(order, s0, s1, frame[:8])
+		s0, s1 = walChecksum(order, s0, s1, frame[sqliteWALFrameHeader:])
+		if s0 != binary.BigEndian.Uint32(frame[16:]) || s1 != binary.BigEndian.Uint32(frame[20:]) {
+			break
+		}
+
+		pending[binary.BigEndian.Uint32(frame)] = off + sqliteWALFrameHeader
+		if binary.BigEndian.Uint32(frame[4:]) != 0 {
+			// Commit frame.
+			for pgno, off := range pending {
+				committed[pgno] = off
+			}
+			pending = map[uint32]int64{}
+		}
+	}
+
+	db.wal, db.walPages = wal, committed
+	return nil
+}
+
+// walChecksum continues the cumulative checksum of the write-ahead log over
+// data.
+func walChecksum(order binary.ByteOrder, s0, s1 uint32, data []byte) (uint32, uint32) {
+	for i := 0; i+8 <= len(data); i += 8 {
+		s0 += order.Uint32(data[i:]) + s1
+		s1 += order.Uint32(data[i+4:]) + s0
+	}
+	return s0, s1
+}
+
+func (db *sqliteDB) page(pgno uint32) ([]byte, error) {
+	if pgno == 0 {
+		return nil, errors.New("invalid page number 0")
+	}
+
+	r, off := db.r, int64(pgno-1)*int64(db.pageSize)
+	if walOff, found := db.walPages[pgno]; found {
+		r, off = db.wal, walOff
+	}
+	page := make([]byte, db.pageSize)
+	if _, err := r.ReadAt(page, off); err != nil {
+		return nil, errors.Wrapf(err, "failed to read page %d", pgno)
+	}
+	return page, nil
+}
+
+// table calls fn with the columns of each row of the named table. Columns
+// are nil, int64, float64, string, or []byte values.
+func (db *sqliteDB) table(name string, fn func(cols []interface{}) error) error {
+	// The schema table is stored on page 1. Its columns are type, name,
+	// tbl_name, rootpage, and sql.
+	var root int64
+	err := db.walk(1, 0, func(cols []interface{}) error {
+		if len(cols) >= 4 && cols[0] == "table" {
+			if v, ok := cols[1].(string); ok && strings.EqualFold(v, name) {
+				root, _ = cols[3].(int64)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read schema")
+	}
+	if root <= 0 || root > math.MaxUint32 {
+		return errors.Errorf("table %v not found", name)
+	}
+	return db.walk(uint32(root), 0, fn)
+}
+
+// walk calls fn with the columns of the rows of the table b-tree rooted at
+// pgno in rowid order.
+func (db *sqliteDB) walk(pgno uint32, depth int, fn func(cols []interface{}) error) error {
+	if depth > 20 {
+		return errors.New("b-tree is too deep")
+	}
+
+	page, err := db.page(pgno)
+	if err != nil {
+		return err
+	}
+	hdr := 0
+	if pgno == 1 {
+		hdr = sqliteHeaderSize
+	}
+
+	typ := page[hdr]
+	cells := int(binary.BigEndian.Uint16(page[hdr+3:]))
+	ptrs := hdr + 8
+	if typ == sqlitePageInteriorTable {
+		ptrs = hdr + 12
+	}
+	if ptrs+2*cells > db.usable {
+		return errors.Errorf("invalid cell count %d on page %d", cells, pgno)
+	}
+
+	for i := 0; i < cells; i++ {
+		off := int(binary.BigEndian.Uint16(page[ptrs+2*i:]))
+		if off < ptrs+2*cells || off+4 > db.usable {
+			return errors.Errorf("invalid cell offset %d on page %d", off, pgno)
+		}
+		cell := page[off:db.usable]
+
+		switch typ {
+		case sqlitePageInteriorTable:
+			if err := db.walk(binary.BigEndian.Uint32(cell), depth+1, fn); err != nil {
+				return err
+			}
+		case sqlitePageLeafTable:
+			size, n := sqliteVarint(cell)
+			if n == 0 {
+				return errors.Errorf("invalid cell on page %d", pgno)
+			}
+			_, m := sqliteVarint(cell[n:]) // rowid
+			if m == 0 {
+				return errors.Errorf("invalid cell on page %d", pgno)
+			}
+			payload, err := db.payload(cell[n+m:], size)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read cell on page %d", pgno)
+			}
+			cols, err := parseSQLiteRecord(payload)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse record on page %d", pgno)
+			}
+			if err := fn(cols); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("page %d is not a table b-tree page (type %d)", pgno, typ)
+		}
+	}
+
+	if typ == sqlitePageInteriorTable {
+		return db.walk(binary.BigEndian.Uint32(page[hdr+8:]), depth+1, fn)
+	}
+	return nil
+}
+
+// payload returns the payload of a table leaf cell whose local part starts at
+// the beginning of cell. Payloads that don't fit on the page continue on a
+// chain of overflow pages.
+func (db *sqliteDB) payload(cell []byte, size uint64) ([]byte, error) {
+	if size > sqliteMaxPayload {
+		return nil, errors.Errorf("payload of %d bytes is too large", size)
+	}
+
+	u, p := db.usable, int(size)
+	local := p
+	if maxLocal := u - 35; p > maxLocal {
+		minLocal := (u-12)*32/255 - 23
+		local = minLocal + (p-minLocal)%(u-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if local == p {
+		if local > len(cell) {
+			return nil, errors.New("payload exceeds the page")
+		}
+		return cell[:local], nil
+	}
+	if local+4 > len(cell) {
+		return nil, errors.New("payload exceeds the page")
+	}
+
+	data := make([]byte, 0, p)
+	data = append(data, cell[:local]...)
+	next := binary.BigEndian.Uint32(cell[local:])
+	for len(data) < p {
+		if next == 0 {
+			return nil, errors.New("overflow chain is shorter than the payload")
+		}
+		page, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = binary.BigEndian.Uint32(page)
+		n := p - len(data)
+		if n > u-4 {
+			n = u - 4
+		}
+		data = append(data, page[4:4+n]...)
+	}
+	return data, nil
+}
+
+// sqliteVarint decodes a big-endian variable-length integer of 1 to 9 bytes.
+// It returns 0 bytes read if b is too short.
+func sqliteVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 9; i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// parseSQLiteRecord decodes a record, which is a header of serial types
+// followed by the values of the columns.
+func parseSQLiteRecord(p []byte) ([]interface{}, error) {
+	hdrSize, n := sqliteVarint(p)
+	if n == 0 || hdrSize < uint64(n) || hdrSize > uint64(len(p)) {
+		return nil, errors.New("invalid record header")
+	}
+
+	var serialTypes []uint64
+	for hdr := p[n:hdrSize]; len(hdr) > 0; {
+		t, n := sqliteVarint(hdr)
+		if n == 0 {
+			return nil, errors.New("invalid record header")
+		}
+		serialTypes = append(serialTypes, t)
+		hdr = hdr[n:]
+	}
+
+	body := p[hdrSize:]
+	cols := make([]interface{}, 0, len(serialTypes))
+	for _, t := range serialTypes {
+		var size int
+		switch {
+		case t <= 4:
+			size = int(t)
+		case t == 5:
+			size = 6
+		case t == 6 || t == 7:
+			size = 8
+		case t == 8 || t == 9:
+			size = 0
+		case t >= 12:
+			if t > 2*sqliteMaxPayload {
+				return nil, errors.New("invalid serial type")
+			}
+			size = int(t-12) / 2
+		default:
+			return nil, errors.Errorf("invalid serial type %d", t)
+		}
+		if size > len(body) {
+			return nil, errors.New("record is too short")
+		}
+		v := body[:size]
+		body = body[size:]
+
+		switch {
+		case t == 0:
+			cols = append(cols, nil)
+		case t <= 6:
+			i := int64(int8(v[0]))
+			for _, b := range v[1:] {
+				i = i<<8 | int64(b)
+			}
+			cols = append(cols, i)
+		case t == 7:
+			cols = append(cols, math.Float64frombits(binary.BigEndian.Uint64(v)))
+		case t == 8 || t == 9:
+			cols = append(cols, int64(t-8))
+		case t%2 == 0:
+			cols = append(cols, v)
+		default:
+			cols = append(cols, string(v))
+		}
+	}
+	return cols, nil
+}
@@ -0,0 +1,57 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// readContextSwitches reads the voluntary_ctxt_switches and
+// nonvoluntary_ctxt_switches fields from /proc/[pid]/status.
+func readContextSwitches(content []byte) (*types.ResourceUsageInfo, error) {
+	var usage types.ResourceUsageInfo
+
+	err := parseKeyValue(content, ":", func(key, value []byte) error {
+		var dst *uint64
+		switch string(key) {
+		case "voluntary_ctxt_switches":
+			dst = &usage.VoluntaryContextSwitches
+		case "nonvoluntary_ctxt_switches":
+			dst = &usage.InvoluntaryContextSwitches
+		default:
+			return nil
+		}
+
+		n, err := strconv.ParseUint(string(value), 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %v value of %v", string(key), string(value))
+		}
+		*dst = n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage.ContextSwitches = usage.VoluntaryContextSwitches + usage.InvoluntaryContextSwitches
+	return &usage, nil
+}
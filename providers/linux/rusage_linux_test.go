@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadContextSwitches(t *testing.T) {
+	content := []byte(`Name:	bash
+State:	S (sleeping)
+Threads:	1
+voluntary_ctxt_switches:	150
+nonvoluntary_ctxt_switches:	7
+`)
+
+	usage, err := readContextSwitches(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 150, usage.VoluntaryContextSwitches)
+	assert.EqualValues(t, 7, usage.InvoluntaryContextSwitches)
+	assert.EqualValues(t, 157, usage.ContextSwitches)
+
+	_, err = readContextSwitches([]byte("voluntary_ctxt_switches:\tx\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Scheduling policies from linux/sched.h.
+var schedPolicies = map[int]string{
+	0: "other",
+	1: "fifo",
+	2: "rr",
+	3: "batch",
+	5: "idle",
+	6: "deadline",
+}
+
+// I/O scheduling classes from linux/ioprio.h.
+var ioprioClasses = map[int]string{
+	0: "none",
+	1: "realtime",
+	2: "best-effort",
+	3: "idle",
+}
+
+const (
+	ioprioWhoProcess = 1  // IOPRIO_WHO_PROCESS
+	ioprioClassShift = 13 // IOPRIO_CLASS_SHIFT
+	ioprioPrioMask   = 0xff
+)
+
+// Scheduling returns the priority, nice value, and scheduling policy from
+// /proc/[pid]/stat and the I/O priority from ioprio_get(2).
+func (p *process) Scheduling() (*types.SchedulingInfo, error) {
+	data, err := p.fs.ReadFile(p.name("stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := parseSchedulingStat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// ioprio_get addresses PIDs of the caller's namespace so it is only used
+	// when reading from a mounted procfs.
+	if isProcFS(p.procFS.Path()) {
+		if v, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(p.PID()), 0); errno == 0 {
+			class, prio := ioprio(int(v))
+			info.IOClass = class
+			info.IOPriority = &prio
+		}
+	}
+	return info, nil
+}
+
+// parseSchedulingStat reads the priority (field 18), nice (19), rt_priority
+// (40), and policy (41) fields of /proc/[pid]/stat. The fields are counted
+// from the end of the command name because it can contain spaces.
+func parseSchedulingStat(data []byte) (*types.SchedulingInfo, error) {
+	i := bytes.LastIndexByte(data, ')')
+	if i < 0 {
+		return nil, errors.New("failed to parse stat: missing command name")
+	}
+	fields := bytes.Fields(data[i+1:])
+	if len(fields) < 39 {
+		return nil, errors.Errorf("failed to parse stat: expected at least 41 fields but got %d", len(fields)+2)
+	}
+
+	var values [4]int
+	for j, n := range []int{18, 19, 40, 41} {
+		v, err := strconv.Atoi(string(fields[n-3]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse stat field %d", n)
+		}
+		values[j] = v
+	}
+
+	nice := values[1]
+	policy, found := schedPolicies[values[3]]
+	if !found {
+		policy = strconv.Itoa(values[3])
+	}
+	return &types.SchedulingInfo{
+		Policy:           policy,
+		Priority:         values[0],
+		Nice:             &nice,
+		RealtimePriority: values[2],
+	}, nil
+}
+
+// ioprio splits an I/O priority into its class and level.
+func ioprio(v int) (class string, prio int) {
+	class, found := ioprioClasses[v>>ioprioClassShift]
+	if !found {
+		class = strconv.Itoa(v >> ioprioClassShift)
+	}
+	return class, v & ioprioPrioMask
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Scheduling = (*process)(nil)
+
+func TestParseSchedulingStat(t *testing.T) {
+	stat := "1207 (kworker/u:1 x) S 2 0 0 0 -1 69238880 0 0 0 0 0 0 0 0 -51 0 1 0 148 0 0 " +
+		"18446744073709551615 0 0 0 0 0 0 0 2147483647 0 0 0 0 17 3 50 1 0 0 0 0 0 0 0 0 0 0 0\n"
+
+	info, err := parseSchedulingStat([]byte(stat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "fifo", info.Policy)
+	assert.Equal(t, -51, info.Priority)
+	assert.Equal(t, 50, info.RealtimePriority)
+	if assert.NotNil(t, info.Nice) {
+		assert.Equal(t, 0, *info.Nice)
+	}
+
+	_, err = parseSchedulingStat([]byte("1 (init) S 0 1"))
+	assert.Error(t, err)
+}
+
+func TestIOPrio(t *testing.T) {
+	class, prio := ioprio(2<<13 | 4)
+	assert.Equal(t, "best-effort", class)
+	assert.Equal(t, 4, prio)
+
+	class, _ = ioprio(3 << 13)
+	assert.Equal(t, "idle", class)
+}
+
+func TestScheduling(t *testing.T) {
+	self, err := newLinuxSystem("").Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := self.(types.Scheduling).Scheduling()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, info.Policy)
+	assert.NotEmpty(t, info.IOClass)
+	// The raw getpriority(2) syscall returns 20 - nice.
+	prio, err := syscall.Getpriority(syscall.PRIO_PROCESS, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, info.Nice) {
+		assert.Equal(t, 20-prio, *info.Nice)
+	}
+}
@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// efiSecureBootVar is the EFI variable that holds the Secure Boot state.
+const efiSecureBootVar = "SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+// SecurityInfo reports the state of SELinux, AppArmor, seccomp, and Secure
+// Boot from sysfs, securityfs, and procfs.
+func (h *host) SecurityInfo() (*types.SecurityInfo, error) {
+	return readSecurityInfo(filepath.Dir(string(h.procFS)))
+}
+
+func readSecurityInfo(root string) (*types.SecurityInfo, error) {
+	info := &types.SecurityInfo{
+		SELinux:  readSELinux(root),
+		AppArmor: readAppArmor(root),
+	}
+
+	// The Seccomp field is only present when the kernel supports seccomp.
+	if status, err := ioutil.ReadFile(filepath.Join(root, "proc/self/status")); err == nil {
+		v := bytes.Contains(status, []byte("\nSeccomp:"))
+		info.SeccompAvailable = &v
+	}
+
+	// The variable consists of 4 bytes of attributes followed by the value.
+	if data, err := ioutil.ReadFile(filepath.Join(root, "sys/firmware/efi/efivars", efiSecureBootVar)); err == nil && len(data) == 5 {
+		v := data[4] == 1
+		info.SecureBoot = &v
+	} else if _, err := os.Stat(filepath.Join(root, "sys/firmware/efi")); err == nil {
+		// UEFI system without the variable, so Secure Boot is unsupported.
+		v := false
+		info.SecureBoot = &v
+	}
+
+	return info, nil
+}
+
+// readSELinux returns nil when SELinux is not installed. selinuxfs is only
+// mounted when SELinux is enabled, otherwise the presence of its config file
+// means that it was disabled.
+func readSELinux(root string) *types.SELinuxInfo {
+	info := &types.SELinuxInfo{Mode: types.SELinuxDisabled}
+
+	fs := filepath.Join(root, "sys/fs/selinux")
+	enforce, err := ioutil.ReadFile(filepath.Join(fs, "enforce"))
+	if err == nil {
+		info.Mode = types.SELinuxPermissive
+		if strings.TrimSpace(string(enforce)) == "1" {
+			info.Mode = types.SELinuxEnforcing
+		}
+		if v, err := ioutil.ReadFile(filepath.Join(fs, "policyvers")); err == nil {
+			info.PolicyVersion, _ = strconv.Atoi(strings.TrimSpace(string(v)))
+		}
+	}
+
+	config, configErr := ioutil.ReadFile(filepath.Join(root, "etc/selinux/config"))
+	if err != nil && configErr != nil {
+		return nil
+	}
+	parseKeyValue(config, "=", func(key, value []byte) error {
+		if string(key) == "SELINUXTYPE" {
+			info.Policy = string(value)
+		}
+		return nil
+	})
+	return info
+}
+
+// readAppArmor returns nil when the kernel has no AppArmor support.
+func readAppArmor(root string) *types.AppArmorInfo {
+	enabled, err := ioutil.ReadFile(filepath.Join(root, "sys/module/apparmor/parameters/enabled"))
+	if err != nil {
+		return nil
+	}
+
+	info := &types.AppArmorInfo{Enabled: strings.TrimSpace(string(enabled)) == "Y"}
+	f, err := os.Open(filepath.Join(root, "sys/kernel/security/apparmor/profiles"))
+	if err != nil {
+		return info
+	}
+	defer f.Close()
+
+	// Each line is "<name> (<mode>)".
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasSuffix(line, "(enforce)"):
+			info.EnforceProfiles++
+		case strings.HasSuffix(line, "(complain)"):
+			info.ComplainProfiles++
+		}
+	}
+	return info
+}
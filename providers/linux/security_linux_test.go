@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Security = (*host)(nil)
+
+func TestSecurityInfo(t *testing.T) {
+	info, err := readSecurityInfo("testdata/ubuntu1710")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secureBoot := true
+	assert.Equal(t, &types.SecurityInfo{
+		AppArmor: &types.AppArmorInfo{
+			Enabled:          true,
+			EnforceProfiles:  3,
+			ComplainProfiles: 1,
+		},
+		SecureBoot: &secureBoot,
+	}, info)
+}
+
+func TestSecurityInfoSELinux(t *testing.T) {
+	info, err := readSecurityInfo("testdata/centos7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &types.SELinuxInfo{
+		Mode:          types.SELinuxEnforcing,
+		Policy:        "targeted",
+		PolicyVersion: 31,
+	}, info.SELinux)
+	assert.Nil(t, info.AppArmor)
+	assert.Nil(t, info.SecureBoot)
+}
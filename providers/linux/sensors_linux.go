@@ -0,0 +1,169 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// hwmonSensors maps the prefix of hwmon attribute names to the sensor type
+// and the divisor that converts the raw value into the unit of the type.
+// See https://www.kernel.org/doc/Documentation/hwmon/sysfs-interface.
+var hwmonSensors = map[string]struct {
+	typ     types.SensorType
+	divisor float64
+}{
+	"temp":  {types.SensorTemperature, 1000}, // millidegree Celsius
+	"fan":   {types.SensorFan, 1},            // RPM
+	"power": {types.SensorPower, 1000000},    // microwatt
+	"in":    {types.SensorVoltage, 1000},     // millivolt
+	"curr":  {types.SensorCurrent, 1000},     // milliampere
+}
+
+// Sensors reads the hwmon devices in /sys/class/hwmon and the thermal
+// zones in /sys/class/thermal.
+func (h *host) Sensors() ([]types.SensorInfo, error) {
+	return readSensors(filepath.Dir(string(h.procFS)))
+}
+
+func readSensors(root string) ([]types.SensorInfo, error) {
+	devices, err := filepath.Glob(filepath.Join(root, "sys/class/hwmon/hwmon*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sensors []types.SensorInfo
+	hwmonNames := map[string]struct{}{}
+	for _, dir := range devices {
+		name := readHwmonName(dir)
+		hwmonNames[name] = struct{}{}
+		sensors = append(sensors, readHwmon(dir, name)...)
+	}
+
+	// Thermal zones are usually also registered as hwmon devices. Only add
+	// the ones that are not.
+	zones, err := filepath.Glob(filepath.Join(root, "sys/class/thermal/thermal_zone*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range zones {
+		zoneType := readSysfsString(filepath.Join(dir, "type"))
+		if _, found := hwmonNames[zoneType]; found {
+			continue
+		}
+		temp, err := readSysfsInt(filepath.Join(dir, "temp"))
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, types.SensorInfo{
+			Type:   types.SensorTemperature,
+			Device: zoneType,
+			Label:  filepath.Base(dir),
+			Value:  float64(temp) / 1000,
+		})
+	}
+
+	return sensors, nil
+}
+
+// readHwmonName returns the name of a hwmon device. Older kernels keep the
+// attributes in the device subdirectory.
+func readHwmonName(dir string) string {
+	if name := readSysfsString(filepath.Join(dir, "name")); name != "" {
+		return name
+	}
+	if name := readSysfsString(filepath.Join(dir, "device/name")); name != "" {
+		return name
+	}
+	return filepath.Base(dir)
+}
+
+func readHwmon(dir, name string) []types.SensorInfo {
+	attrDir := dir
+	inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+	if len(inputs) == 0 {
+		attrDir = filepath.Join(dir, "device")
+		inputs, _ = filepath.Glob(filepath.Join(attrDir, "*_input"))
+	}
+	// Power meters often only report an average.
+	averages, _ := filepath.Glob(filepath.Join(attrDir, "power*_average"))
+	inputs = append(inputs, averages...)
+	sort.Strings(inputs)
+
+	var sensors []types.SensorInfo
+	seen := map[string]struct{}{}
+	for _, input := range inputs {
+		// e.g. temp1_input -> temp1
+		sensor := strings.SplitN(filepath.Base(input), "_", 2)[0]
+		if _, found := seen[sensor]; found {
+			continue
+		}
+
+		prefix := strings.TrimRight(sensor, "0123456789")
+		conv, found := hwmonSensors[prefix]
+		if !found {
+			continue
+		}
+
+		value, err := readSysfsInt(input)
+		if err != nil {
+			continue
+		}
+		seen[sensor] = struct{}{}
+
+		info := types.SensorInfo{
+			Type:   conv.typ,
+			Device: name,
+			Label:  readSysfsString(filepath.Join(attrDir, sensor+"_label")),
+			Value:  float64(value) / conv.divisor,
+		}
+		if info.Label == "" {
+			info.Label = sensor
+		}
+		if v, err := readSysfsInt(filepath.Join(attrDir, sensor+"_max")); err == nil {
+			info.High = float64(v) / conv.divisor
+		}
+		if v, err := readSysfsInt(filepath.Join(attrDir, sensor+"_crit")); err == nil {
+			info.Critical = float64(v) / conv.divisor
+		}
+		sensors = append(sensors, info)
+	}
+	return sensors
+}
+
+func readSysfsString(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysfsInt(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Sensors = (*host)(nil)
+
+func TestReadSensors(t *testing.T) {
+	sensors, err := readSensors("testdata/ubuntu1710")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.SensorInfo{
+		{Type: types.SensorTemperature, Device: "acpitz", Label: "temp1", Value: 27.8, Critical: 119},
+		{Type: types.SensorTemperature, Device: "coretemp", Label: "Package id 0", Value: 45, High: 84, Critical: 100},
+		{Type: types.SensorTemperature, Device: "coretemp", Label: "Core 0", Value: 43, High: 84, Critical: 100},
+		{Type: types.SensorFan, Device: "thinkpad", Label: "fan1", Value: 2412},
+		{Type: types.SensorVoltage, Device: "thinkpad", Label: "in0", Value: 12},
+		{Type: types.SensorPower, Device: "thinkpad", Label: "power1", Value: 15.5},
+		// acpitz is skipped because it is also a hwmon device.
+		{Type: types.SensorTemperature, Device: "x86_pkg_temp", Label: "thermal_zone1", Value: 46},
+	}, sensors)
+}
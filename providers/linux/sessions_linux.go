@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// sizeofUtmp is the size of struct utmp. glibc uses the same layout with
+	// 32-bit times on all architectures.
+	sizeofUtmp = 384
+
+	// userProcess (USER_PROCESS) is the ut_type of a normal login session.
+	userProcess = 7
+)
+
+// utmpPaths are the locations of the utmp file relative to the root
+// filesystem in the order that they are tried.
+var utmpPaths = []string{"run/utmp", "var/run/utmp"}
+
+// Sessions returns the login sessions recorded in the utmp file.
+func (h *host) Sessions() ([]types.SessionInfo, error) {
+	for _, path := range utmpPaths {
+		content, err := h.fs.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		return parseUtmp(content)
+	}
+	return nil, nil
+}
+
+// parseUtmp returns the USER_PROCESS entries of a utmp file. See utmp(5).
+func parseUtmp(content []byte) ([]types.SessionInfo, error) {
+	if len(content)%sizeofUtmp != 0 {
+		return nil, errors.Errorf("utmp size %d is not a multiple of the record size %d", len(content), sizeofUtmp)
+	}
+
+	var sessions []types.SessionInfo
+	for ; len(content) > 0; content = content[sizeofUtmp:] {
+		r := content[:sizeofUtmp]
+		if int16(binary.LittleEndian.Uint16(r[0:])) != userProcess {
+			continue
+		}
+
+		session := types.SessionInfo{
+			PID:        int(int32(binary.LittleEndian.Uint32(r[4:]))),
+			Terminal:   cString(r[8:40]),
+			User:       cString(r[44:76]),
+			RemoteHost: cString(r[76:332]),
+			LoginTime: time.Unix(
+				int64(int32(binary.LittleEndian.Uint32(r[340:]))),
+				int64(int32(binary.LittleEndian.Uint32(r[344:])))*int64(time.Microsecond)),
+		}
+
+		// Fallback to ut_addr_v6 when the host name was not recorded.
+		if session.RemoteHost == "" {
+			addr := net.IP(r[348:364])
+			if bytes.Equal(addr[4:], make([]byte, 12)) {
+				addr = addr[:4]
+			}
+			if !addr.IsUnspecified() {
+				session.RemoteHost = addr.String()
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// cString returns the NUL terminated string contained in b.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.SessionEnumerator = (*host)(nil)
+
+func TestHostSessions(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := host.(types.SessionEnumerator).Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.SessionInfo{
+		{
+			User:       "ubuntu",
+			Terminal:   "pts/0",
+			RemoteHost: "10.0.2.2",
+			LoginTime:  time.Unix(1509470000, 0),
+			PID:        1523,
+		},
+	}, sessions)
+
+	_, err = parseUtmp(make([]byte, sizeofUtmp+1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"syscall"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// imaXattr is the extended attribute where IMA stores the file hash or
+// signature.
+const imaXattr = "security.ima"
+
+// imaXattrTypes maps the first byte of the security.ima attribute (enum
+// evm_ima_xattr_type) to a description.
+var imaXattrTypes = map[byte]string{
+	0x01: "digest",    // IMA_XATTR_DIGEST
+	0x02: "hmac",      // EVM_XATTR_HMAC
+	0x03: "signature", // EVM_IMA_XATTR_DIGSIG
+	0x04: "digest",    // IMA_XATTR_DIGEST_NG
+	0x05: "signature", // EVM_XATTR_PORTABLE_DIGSIG
+	0x06: "signature", // IMA_VERITY_DIGSIG
+}
+
+// FileSignature reports the IMA attribute of the file. Linux executables do
+// not carry signatures otherwise and the kernel performs the IMA appraisal so
+// the status is never trusted.
+func (s linuxSystem) FileSignature(path string) (*types.SignatureInfo, error) {
+	return imaSignature(path)
+}
+
+// SignatureInfo reports the IMA attribute of the executable of the process.
+func (p *process) SignatureInfo() (*types.SignatureInfo, error) {
+	// The exe link refers to the file even if it is in another mount
+	// namespace.
+	return imaSignature(p.path("exe"))
+}
+
+func imaSignature(path string) (*types.SignatureInfo, error) {
+	buf := make([]byte, 1024)
+	n, err := syscall.Getxattr(path, imaXattr, buf)
+	if err != nil {
+		if err == syscall.ENODATA || err == syscall.ENOTSUP {
+			return &types.SignatureInfo{Status: types.SignatureStatusUnsigned}, nil
+		}
+		return nil, err
+	}
+	return parseIMAXattr(buf[:n]), nil
+}
+
+func parseIMAXattr(value []byte) *types.SignatureInfo {
+	info := &types.SignatureInfo{Status: types.SignatureStatusUnsigned}
+	if len(value) == 0 {
+		return info
+	}
+	info.IMA = imaXattrTypes[value[0]]
+	if info.IMA == "signature" {
+		info.Status = types.SignatureStatusUnverified
+	}
+	return info
+}
@@ -0,0 +1,38 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var (
+	_ registry.SignatureProvider = linuxSystem{}
+	_ types.CodeSignature        = (*process)(nil)
+)
+
+func TestParseIMAXattr(t *testing.T) {
+	assert.Equal(t, &types.SignatureInfo{Status: types.SignatureStatusUnsigned}, parseIMAXattr(nil))
+	assert.Equal(t, &types.SignatureInfo{Status: types.SignatureStatusUnsigned, IMA: "digest"}, parseIMAXattr([]byte{0x04, 0x04, 0xab}))
+	assert.Equal(t, &types.SignatureInfo{Status: types.SignatureStatusUnverified, IMA: "signature"}, parseIMAXattr([]byte{0x03, 0x02}))
+}
@@ -0,0 +1,131 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/sock_diag.h and linux/inet_diag.h.
+const (
+	sockDiagByFamily = 20 // SOCK_DIAG_BY_FAMILY
+
+	inetDiagReqV2Len = 56 // sizeof(struct inet_diag_req_v2)
+	inetDiagMsgLen   = 72 // sizeof(struct inet_diag_msg)
+
+	// procSuperMagic is the f_type reported by statfs for procfs.
+	procSuperMagic = 0x9fa0
+)
+
+var sockDiagQueries = []struct {
+	family   uint8
+	protocol uint8
+}{
+	{syscall.AF_INET, syscall.IPPROTO_TCP},
+	{syscall.AF_INET6, syscall.IPPROTO_TCP},
+	{syscall.AF_INET, syscall.IPPROTO_UDP},
+	{syscall.AF_INET6, syscall.IPPROTO_UDP},
+}
+
+// sockDiagConnections returns all TCP and UDP sockets in the network
+// namespace of the current process using NETLINK_SOCK_DIAG. This avoids
+// formatting and parsing the /proc/net tables in the kernel and is much
+// cheaper on hosts with many sockets.
+func sockDiagConnections() ([]types.NetworkConnection, error) {
+	var conns []types.NetworkConnection
+	for _, q := range sockDiagQueries {
+		responses, err := netlinkRequest(netlinkSockDiag, sockDiagByFamily,
+			syscall.NLM_F_DUMP, inetDiagRequest(q.family, q.protocol))
+		if err != nil {
+			if q.family == syscall.AF_INET6 && err == syscall.ENOENT {
+				// IPv6 can be disabled.
+				continue
+			}
+			return nil, errors.Wrap(err, "sock_diag request failed")
+		}
+
+		for _, data := range responses {
+			conn, err := parseInetDiagMsg(data, q.protocol)
+			if err != nil {
+				return nil, err
+			}
+			conns = append(conns, conn)
+		}
+	}
+	return conns, nil
+}
+
+// inetDiagRequest encodes a struct inet_diag_req_v2 that matches sockets of
+// the given family and protocol in any state.
+func inetDiagRequest(family, protocol uint8) []byte {
+	req := make([]byte, inetDiagReqV2Len)
+	req[0] = family
+	req[1] = protocol
+	nativeEndian.PutUint32(req[4:], 0xffffffff) // idiag_states
+	return req
+}
+
+// parseInetDiagMsg decodes a struct inet_diag_msg.
+func parseInetDiagMsg(b []byte, protocol uint8) (types.NetworkConnection, error) {
+	if len(b) < inetDiagMsgLen {
+		return types.NetworkConnection{}, errors.Errorf("inet_diag_msg is too short (%d bytes)", len(b))
+	}
+
+	conn := types.NetworkConnection{
+		Type:       types.ProtocolUDP,
+		LocalPort:  int(binary.BigEndian.Uint16(b[4:])),
+		RemotePort: int(binary.BigEndian.Uint16(b[6:])),
+		UID:        strconv.FormatUint(uint64(nativeEndian.Uint32(b[64:])), 10),
+		Inode:      uint64(nativeEndian.Uint32(b[68:])),
+	}
+	if protocol == syscall.IPPROTO_TCP {
+		conn.Type = types.ProtocolTCP
+		conn.State = tcpStates[uint64(b[1])]
+	}
+
+	ipLen := net.IPv6len
+	conn.Family = types.FamilyIPv6
+	if b[0] == syscall.AF_INET {
+		ipLen = net.IPv4len
+		conn.Family = types.FamilyIPv4
+	}
+	conn.LocalIP = append(net.IP(nil), b[8:8+ipLen]...)
+	if remoteIP := net.IP(b[24 : 24+ipLen]); !remoteIP.IsUnspecified() {
+		conn.RemoteIP = append(net.IP(nil), remoteIP...)
+	}
+
+	return conn, nil
+}
+
+// isProcFS returns true if path is the mount point of a procfs. sock_diag
+// can only be used in place of the socket tables when they come from a real
+// procfs (as opposed to a copy used in tests).
+func isProcFS(path string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false
+	}
+	return st.Type == procSuperMagic
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseInetDiagMsg(t *testing.T) {
+	msg := make([]byte, inetDiagMsgLen)
+	msg[0] = syscall.AF_INET
+	msg[1] = 1 // TCP_ESTABLISHED
+	binary.BigEndian.PutUint16(msg[4:], 22)
+	binary.BigEndian.PutUint16(msg[6:], 51234)
+	copy(msg[8:], net.ParseIP("10.0.0.5").To4())
+	copy(msg[24:], net.ParseIP("10.0.0.9").To4())
+	nativeEndian.PutUint32(msg[64:], 1000)
+	nativeEndian.PutUint32(msg[68:], 98765)
+
+	conn, err := parseInetDiagMsg(msg, syscall.IPPROTO_TCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.NetworkConnection{
+		Family:     types.FamilyIPv4,
+		Type:       types.ProtocolTCP,
+		LocalIP:    net.ParseIP("10.0.0.5").To4(),
+		LocalPort:  22,
+		RemoteIP:   net.ParseIP("10.0.0.9").To4(),
+		RemotePort: 51234,
+		State:      types.TCPStateEstablished,
+		UID:        "1000",
+		Inode:      98765,
+	}, conn)
+
+	// An unconnected IPv6 UDP socket.
+	msg = make([]byte, inetDiagMsgLen)
+	msg[0] = syscall.AF_INET6
+	msg[1] = 7 // TCP_CLOSE
+	binary.BigEndian.PutUint16(msg[4:], 53)
+	copy(msg[8:], net.IPv6loopback)
+
+	conn, err = parseInetDiagMsg(msg, syscall.IPPROTO_UDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.NetworkConnection{
+		Family:    types.FamilyIPv6,
+		Type:      types.ProtocolUDP,
+		LocalIP:   net.IPv6loopback,
+		LocalPort: 53,
+		UID:       "0",
+	}, conn)
+
+	_, err = parseInetDiagMsg(msg[:40], syscall.IPPROTO_UDP)
+	assert.Error(t, err)
+}
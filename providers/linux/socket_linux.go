@@ -0,0 +1,259 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// tcpStates maps the state values used in /proc/net/tcp to their names. See
+// include/net/tcp_states.h.
+var tcpStates = map[uint64]string{
+	1:  types.TCPStateEstablished,
+	2:  types.TCPStateSynSent,
+	3:  types.TCPStateSynRecv,
+	4:  types.TCPStateFinWait1,
+	5:  types.TCPStateFinWait2,
+	6:  types.TCPStateTimeWait,
+	7:  types.TCPStateClose,
+	8:  types.TCPStateCloseWait,
+	9:  types.TCPStateLastAck,
+	10: types.TCPStateListen,
+	11: types.TCPStateClosing,
+}
+
+var socketTables = []struct {
+	file     string
+	family   string
+	protocol string
+}{
+	{"tcp", types.FamilyIPv4, types.ProtocolTCP},
+	{"tcp6", types.FamilyIPv6, types.ProtocolTCP},
+	{"udp", types.FamilyIPv4, types.ProtocolUDP},
+	{"udp6", types.FamilyIPv6, types.ProtocolUDP},
+}
+
+// readSocketTables returns all TCP and UDP sockets listed in a net directory
+// of procfs. /proc/net lists the sockets of the network namespace of the
+// reader and /proc/[pid]/net those of the namespace of the process.
+func readSocketTables(fs fileSystem, netDir string) ([]types.NetworkConnection, error) {
+	var conns []types.NetworkConnection
+	for _, table := range socketTables {
+		content, err := fs.ReadFile(path.Join(netDir, table.file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// IPv6 can be disabled.
+				continue
+			}
+			return nil, err
+		}
+
+		tableConns, err := parseSocketTable(content, table.family, table.protocol)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %v", table.file)
+		}
+		conns = append(conns, tableConns...)
+	}
+
+	return conns, nil
+}
+
+// parseSocketTable parses the contents of /proc/net/{tcp,tcp6,udp,udp6}.
+func parseSocketTable(content []byte, family, protocol string) ([]types.NetworkConnection, error) {
+	var conns []types.NetworkConnection
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for n := 0; sc.Scan(); n++ {
+		// Skip the header.
+		if n == 0 {
+			continue
+		}
+
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		localIP, localPort, err := parseHexAddress(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		remoteIP, remotePort, err := parseHexAddress(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse inode '%v'", fields[9])
+		}
+
+		conn := types.NetworkConnection{
+			Family:     family,
+			Type:       protocol,
+			LocalIP:    localIP,
+			LocalPort:  localPort,
+			RemotePort: remotePort,
+			UID:        fields[7],
+			Inode:      inode,
+		}
+		if !remoteIP.IsUnspecified() {
+			conn.RemoteIP = remoteIP
+		}
+		if protocol == types.ProtocolTCP {
+			state, err := strconv.ParseUint(fields[3], 16, 8)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse state '%v'", fields[3])
+			}
+			conn.State = tcpStates[state]
+		}
+
+		conns = append(conns, conn)
+	}
+
+	return conns, sc.Err()
+}
+
+// unixAcceptCon (__SO_ACCEPTCON) is set in the flags of listening Unix
+// domain sockets.
+const unixAcceptCon = 1 << 16
+
+// unixConnected (SS_CONNECTED) is the state of a connected Unix domain socket.
+const unixConnected = 3
+
+// readUnixSocketTable returns all Unix domain sockets listed in a net
+// directory of procfs.
+func readUnixSocketTable(fs fileSystem, netDir string) ([]types.NetworkConnection, error) {
+	content, err := fs.ReadFile(path.Join(netDir, "unix"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	conns, err := parseUnixSocketTable(content)
+	return conns, errors.Wrap(err, "failed to parse unix")
+}
+
+// parseUnixSocketTable parses the contents of /proc/net/unix.
+func parseUnixSocketTable(content []byte) ([]types.NetworkConnection, error) {
+	var conns []types.NetworkConnection
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for n := 0; sc.Scan(); n++ {
+		// Skip the header.
+		if n == 0 {
+			continue
+		}
+
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 7 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse flags '%v'", fields[3])
+		}
+		state, err := strconv.ParseUint(fields[5], 16, 8)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse state '%v'", fields[5])
+		}
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse inode '%v'", fields[6])
+		}
+
+		conn := types.NetworkConnection{
+			Family: types.FamilyUnix,
+			Type:   types.ProtocolUnix,
+			Path:   strings.Join(fields[7:], " "),
+			Inode:  inode,
+		}
+		switch {
+		case flags&unixAcceptCon != 0:
+			conn.State = types.UnixStateListen
+		case state == unixConnected:
+			conn.State = types.UnixStateConnected
+		}
+
+		conns = append(conns, conn)
+	}
+
+	return conns, sc.Err()
+}
+
+// parseHexAddress parses an address like 0100007F:0035. The IP is stored as
+// a sequence of 32-bit words in host byte order and the port is big endian.
+func parseHexAddress(s string) (net.IP, int, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, 0, errors.Errorf("invalid address '%v'", s)
+	}
+
+	ip, err := hex.DecodeString(parts[0])
+	if err != nil || (len(ip) != net.IPv4len && len(ip) != net.IPv6len) {
+		return nil, 0, errors.Errorf("invalid IP in address '%v'", s)
+	}
+	swapIPWords(ip, nativeEndian)
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, errors.Errorf("invalid port in address '%v'", s)
+	}
+
+	return net.IP(ip), int(port), nil
+}
+
+// swapIPWords converts an IP whose 32-bit words were printed in the byte
+// order of the host to network byte order.
+func swapIPWords(ip []byte, order binary.ByteOrder) {
+	for i := 0; i+4 <= len(ip); i += 4 {
+		order.PutUint32(ip[i:], binary.BigEndian.Uint32(ip[i:]))
+	}
+}
+
+// socketInodes returns the set of socket inodes referenced by the given
+// /proc/<pid>/fd link targets.
+func socketInodes(targets []string) map[uint64]struct{} {
+	inodes := map[uint64]struct{}{}
+	for _, target := range targets {
+		if !strings.HasPrefix(target, "socket:[") || !strings.HasSuffix(target, "]") {
+			continue
+		}
+
+		inode, err := strconv.ParseUint(target[len("socket:["):len(target)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		inodes[inode] = struct{}{}
+	}
+	return inodes
+}
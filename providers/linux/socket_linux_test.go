@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000   111        0 21300 1 0000000000000000 100 0 0 10 0
+   1: 0F02000A:0016 0202000A:C5E2 01 00000000:00000000 02:00097B8F 00000000     0        0 29851 4 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 18683 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:D3A2 01 00000000:00000000 00:00000000 00000000  1000        0 55420 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetUDP = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  340: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 16963 2 0000000000000000 0
+`
+
+func TestParseSocketTable(t *testing.T) {
+	// The tables were captured on a little-endian host.
+	defer func(order binary.ByteOrder) { nativeEndian = order }(nativeEndian)
+	nativeEndian = binary.LittleEndian
+
+	conns, err := parseSocketTable([]byte(procNetTCP), types.FamilyIPv4, types.ProtocolTCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, conns, 2) {
+		assert.Equal(t, types.NetworkConnection{
+			Family:    types.FamilyIPv4,
+			Type:      types.ProtocolTCP,
+			LocalIP:   net.IPv4(127, 0, 0, 1).To4(),
+			LocalPort: 3306,
+			State:     types.TCPStateListen,
+			UID:       "111",
+			Inode:     21300,
+		}, conns[0])
+		assert.Equal(t, types.NetworkConnection{
+			Family:     types.FamilyIPv4,
+			Type:       types.ProtocolTCP,
+			LocalIP:    net.IPv4(10, 0, 2, 15).To4(),
+			LocalPort:  22,
+			RemoteIP:   net.IPv4(10, 0, 2, 2).To4(),
+			RemotePort: 50658,
+			State:      types.TCPStateEstablished,
+			UID:        "0",
+			Inode:      29851,
+		}, conns[1])
+	}
+
+	conns, err = parseSocketTable([]byte(procNetTCP6), types.FamilyIPv6, types.ProtocolTCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, conns, 2) {
+		assert.Equal(t, "::", conns[0].LocalIP.String())
+		assert.Nil(t, conns[0].RemoteIP)
+		assert.Equal(t, "::1", conns[1].LocalIP.String())
+		assert.Equal(t, 8080, conns[1].LocalPort)
+		assert.Equal(t, "::1", conns[1].RemoteIP.String())
+		assert.Equal(t, 54178, conns[1].RemotePort)
+	}
+
+	conns, err = parseSocketTable([]byte(procNetUDP), types.FamilyIPv4, types.ProtocolUDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, conns, 1) {
+		assert.Equal(t, "127.0.0.53", conns[0].LocalIP.String())
+		assert.Equal(t, 53, conns[0].LocalPort)
+		assert.Empty(t, conns[0].State)
+		assert.EqualValues(t, 16963, conns[0].Inode)
+	}
+}
+
+func TestSwapIPWords(t *testing.T) {
+	// 127.0.0.1 is printed as 0100007F on little-endian and as 7F000001 on
+	// big-endian hosts.
+	ip := []byte{0x01, 0x00, 0x00, 0x7f}
+	swapIPWords(ip, binary.LittleEndian)
+	assert.Equal(t, net.IPv4(127, 0, 0, 1).To4(), net.IP(ip))
+
+	ip = []byte{0x7f, 0x00, 0x00, 0x01}
+	swapIPWords(ip, binary.BigEndian)
+	assert.Equal(t, net.IPv4(127, 0, 0, 1).To4(), net.IP(ip))
+
+	// ::1 consists of four words that are swapped individually.
+	ip = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0, 0, 0}
+	swapIPWords(ip, binary.LittleEndian)
+	assert.Equal(t, net.IPv6loopback, net.IP(ip))
+
+	ip = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}
+	swapIPWords(ip, binary.BigEndian)
+	assert.Equal(t, net.IPv6loopback, net.IP(ip))
+}
+
+func TestSocketInodes(t *testing.T) {
+	inodes := socketInodes([]string{
+		"/dev/null",
+		"socket:[21300]",
+		"pipe:[1234]",
+		"socket:[29851]",
+		"socket:[bad]",
+	})
+	assert.Equal(t, map[uint64]struct{}{21300: {}, 29851: {}}, inodes)
+}
+
+const procNetUnix = `Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 20399 /run/systemd/private
+0000000000000000: 00000003 00000000 00000000 0001 03 31837 @/tmp/.X11-unix/X0
+0000000000000000: 00000002 00000000 00000000 0002 01 17445
+`
+
+func TestParseUnixSocketTable(t *testing.T) {
+	conns, err := parseUnixSocketTable([]byte(procNetUnix))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []types.NetworkConnection{
+		{
+			Family: types.FamilyUnix,
+			Type:   types.ProtocolUnix,
+			Path:   "/run/systemd/private",
+			State:  types.UnixStateListen,
+			Inode:  20399,
+		},
+		{
+			Family: types.FamilyUnix,
+			Type:   types.ProtocolUnix,
+			Path:   "@/tmp/.X11-unix/X0",
+			State:  types.UnixStateConnected,
+			Inode:  31837,
+		},
+		{
+			Family: types.FamilyUnix,
+			Type:   types.ProtocolUnix,
+			Inode:  17445,
+		},
+	}, conns)
+}
@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// KernelParam reads the value of a parameter from /proc/sys. Like sysctl(8)
+// it accepts names that are separated by dots or slashes. When dots are used
+// a slash represents a dot in a component (e.g. net.ipv4.conf.eth0/100.rp_filter).
+func (h *host) KernelParam(name string) (string, error) {
+	value, err := h.fs.ReadFile(path.Join("proc/sys", sysctlPath(name)))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read kernel parameter %v", name)
+	}
+	return strings.TrimSuffix(string(value), "\n"), nil
+}
+
+// KernelParams reads all parameters from /proc/sys. Parameters that are
+// write-only or that cannot be read by the current user are omitted.
+func (h *host) KernelParams() (map[string]string, error) {
+	return readKernelParams(h.procFS.Path("sys"))
+}
+
+func readKernelParams(dir string) (map[string]string, error) {
+	params := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			// The directory is not accessible.
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0444 == 0 {
+			return nil
+		}
+
+		value, err := ioutil.ReadFile(path)
+		if err != nil {
+			// Some parameters can't be read even though the mode suggests
+			// it (e.g. net.ipv6.conf.all.stable_secret or when denied).
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		params[sysctlName(rel)] = strings.TrimSuffix(string(value), "\n")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// sysctlPath converts a parameter name to a path relative to /proc/sys. Like
+// procps the name is treated as a path if a slash comes before the first dot.
+func sysctlPath(name string) string {
+	if slash := strings.Index(name, "/"); slash >= 0 {
+		if dot := strings.Index(name, "."); dot < 0 || slash < dot {
+			return name
+		}
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = strings.Replace(p, "/", ".", -1)
+	}
+	return filepath.Join(parts...)
+}
+
+// sysctlName converts a path relative to /proc/sys to the dotted name.
+func sysctlName(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i, p := range parts {
+		parts[i] = strings.Replace(p, ".", "/", -1)
+	}
+	return strings.Join(parts, ".")
+}
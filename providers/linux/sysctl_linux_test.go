@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.KernelParameters = (*host)(nil)
+
+func TestKernelParams(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := host.(types.KernelParameters)
+
+	value, err := params.KernelParam("kernel.randomize_va_space")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "2", value)
+
+	for _, name := range []string{"net.ipv4.conf.eth0/100.rp_filter", "net/ipv4/conf/eth0.100/rp_filter"} {
+		value, err = params.KernelParam(name)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, "1", value, name)
+		}
+	}
+
+	_, err = params.KernelParam("kernel.missing")
+	assert.Error(t, err)
+
+	all, err := params.KernelParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"kernel.random.boot_id":            "4f2c8d1e-6b1a-4e3c-9d57-2a0c3b8e7f16",
+		"kernel.random.entropy_avail":      "3754",
+		"kernel.random.poolsize":           "4096",
+		"kernel.randomize_va_space":        "2",
+		"net.ipv4.ip_forward":              "0",
+		"net.ipv4.tcp_rmem":                "4096\t87380\t6291456",
+		"net.ipv4.conf.eth0/100.rp_filter": "1",
+	}, all)
+}
@@ -0,0 +1,153 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// systemdUnitSuffixes are the types of units that can own processes.
+var systemdUnitSuffixes = []string{".service", ".scope", ".socket", ".mount", ".swap"}
+
+// UnitName returns the systemd unit that owns the process.
+func (p *process) UnitName() (string, error) {
+	unit, _, err := p.systemdUnit()
+	return unit, err
+}
+
+// SliceName returns the systemd slice that contains the unit of the process.
+func (p *process) SliceName() (string, error) {
+	_, slice, err := p.systemdUnit()
+	return slice, err
+}
+
+func (p *process) systemdUnit() (unit, slice string, err error) {
+	cgroup, err := p.fs.ReadFile(p.name("cgroup"))
+	if err != nil {
+		return "", "", err
+	}
+	unit, slice = systemdUnit(systemdCgroupPath(cgroup))
+	return unit, slice, nil
+}
+
+// systemdCgroupPath returns the path of the cgroup that is managed by
+// systemd from the contents of /proc/[pid]/cgroup. It prefers the name=systemd
+// hierarchy of cgroups v1 and otherwise uses the unified hierarchy.
+func systemdCgroupPath(data []byte) string {
+	var unified string
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		parts := strings.SplitN(s.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[1] == "name=systemd":
+			return parts[2]
+		case parts[0] == "0" && parts[1] == "":
+			unified = parts[2]
+		}
+	}
+	return unified
+}
+
+// systemdUnit returns the unit and slice from a cgroup path. Like systemd it
+// skips the leading slices and uses the first unit, so processes of user
+// services are attributed to their user@.service instance.
+func systemdUnit(path string) (unit, slice string) {
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		if strings.HasSuffix(name, ".slice") {
+			slice = name
+			continue
+		}
+		for _, suffix := range systemdUnitSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				if slice == "" {
+					slice = "-.slice"
+				}
+				return name, slice
+			}
+		}
+		break
+	}
+	return "", ""
+}
+
+// ServiceUnits returns the units that are loaded by systemd by calling the
+// ListUnits method of the manager over the D-Bus system bus.
+func (h *host) ServiceUnits() ([]types.ServiceUnitInfo, error) {
+	root := filepath.Dir(string(h.procFS))
+	conn, err := dialDBus(filepath.Join(root, "run/dbus/system_bus_socket"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the dbus system bus")
+	}
+	defer conn.Close()
+
+	msg, err := conn.call("org.freedesktop.systemd1", "/org/freedesktop/systemd1",
+		"org.freedesktop.systemd1.Manager", "ListUnits")
+	if err != nil {
+		return nil, err
+	}
+	return parseListUnits(msg)
+}
+
+// parseListUnits decodes the reply of ListUnits. Each unit is a struct of
+// name, description, load state, active state, sub state, followed unit,
+// object path, job ID, job type, and job object path.
+func parseListUnits(msg *dbusMessage) ([]types.ServiceUnitInfo, error) {
+	if msg.signature != "a(ssssssouso)" {
+		return nil, errors.Errorf("unexpected ListUnits reply signature %v", msg.signature)
+	}
+
+	var units []types.ServiceUnitInfo
+	d := newDBusDecoder(msg.body)
+	err := d.array(8, func() error {
+		d.align(8)
+		var fields [10]string
+		for i := range fields {
+			var err error
+			if i == 7 {
+				_, err = d.uint32()
+			} else {
+				fields[i], err = d.string()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		units = append(units, types.ServiceUnitInfo{
+			Name:        fields[0],
+			Description: fields[1],
+			LoadState:   fields[2],
+			ActiveState: fields[3],
+			SubState:    fields[4],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode ListUnits reply")
+	}
+	return units, nil
+}
@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var (
+	_ types.SystemdUnit  = (*process)(nil)
+	_ types.ServiceUnits = (*host)(nil)
+)
+
+func TestSystemdUnit(t *testing.T) {
+	tests := []struct {
+		cgroup string
+		unit   string
+		slice  string
+	}{
+		{"12:pids:/system.slice/ssh.service\n1:name=systemd:/system.slice/ssh.service\n", "ssh.service", "system.slice"},
+		{"0::/user.slice/user-1000.slice/session-2.scope\n", "session-2.scope", "user-1000.slice"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service\n", "user@1000.service", "user-1000.slice"},
+		{"0::/init.scope\n", "init.scope", "-.slice"},
+		{"0::/docker/8d23f1c4\n", "", ""},
+		{"0::/\n", "", ""},
+	}
+
+	for _, tc := range tests {
+		unit, slice := systemdUnit(systemdCgroupPath([]byte(tc.cgroup)))
+		assert.Equal(t, tc.unit, unit, tc.cgroup)
+		assert.Equal(t, tc.slice, slice, tc.cgroup)
+	}
+}
+
+func TestEncodeDBusCall(t *testing.T) {
+	data := encodeDBusCall(7, "org.freedesktop.systemd1", "/org/freedesktop/systemd1",
+		"org.freedesktop.systemd1.Manager", "ListUnits")
+	assert.Zero(t, len(data)%8)
+
+	msg, err := readDBusMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, dbusMethodCall, msg.typ)
+	assert.Empty(t, msg.body)
+}
+
+func TestParseListUnits(t *testing.T) {
+	body := &dbusEncoder{}
+	body.uint32(0) // Array length.
+	body.align(8)
+	start := len(body.buf)
+	for _, unit := range [][]string{
+		{"ssh.service", "OpenBSD Secure Shell server", "loaded", "active", "running"},
+		{"apt-daily.service", "Daily apt download activities", "loaded", "inactive", "dead"},
+	} {
+		body.align(8)
+		for _, s := range unit {
+			body.string(s)
+		}
+		body.string("")
+		body.string("/org/freedesktop/systemd1/unit/x")
+		body.uint32(0)
+		body.string("")
+		body.string("/")
+	}
+	binary.LittleEndian.PutUint32(body.buf, uint32(len(body.buf)-start))
+
+	// Method return header with the reply serial and body signature.
+	header := &dbusEncoder{}
+	header.byte('l')
+	header.byte(dbusMethodReturn)
+	header.byte(0)
+	header.byte(1)
+	header.uint32(uint32(len(body.buf)))
+	header.uint32(2)
+	fields := &dbusEncoder{offset: 16}
+	fields.align(8)
+	fields.byte(dbusFieldReplySerial)
+	fields.signature("u")
+	fields.uint32(7)
+	fields.field(dbusFieldSignature, "g", "a(ssssssouso)")
+	header.uint32(uint32(len(fields.buf)))
+	header.buf = append(header.buf, fields.buf...)
+	header.align(8)
+
+	msg, err := readDBusMessage(bytes.NewReader(append(header.buf, body.buf...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 7, msg.replySerial)
+
+	units, err := parseListUnits(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.ServiceUnitInfo{
+		{Name: "ssh.service", Description: "OpenBSD Secure Shell server", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+		{Name: "apt-daily.service", Description: "Daily apt download activities", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+	}, units)
+}
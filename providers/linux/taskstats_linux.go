@@ -0,0 +1,182 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from linux/taskstats.h.
+const (
+	taskstatsGenlName    = "TASKSTATS"
+	taskstatsGenlVersion = 1
+
+	taskstatsCmdGet     = 1 // TASKSTATS_CMD_GET
+	taskstatsCmdAttrPID = 1 // TASKSTATS_CMD_ATTR_PID
+
+	taskstatsTypeStats   = 3 // TASKSTATS_TYPE_STATS
+	taskstatsTypeAggrPID = 4 // TASKSTATS_TYPE_AGGR_PID
+
+	// taskstatsMinLen is the size of struct taskstats up to and including
+	// the context switch counters (version 1 of the struct is larger).
+	taskstatsMinLen = 288
+)
+
+var taskstatsFamily struct {
+	once sync.Once
+	id   uint16
+	err  error
+}
+
+// taskstatsFamilyID returns the ID of the TASKSTATS generic netlink family.
+// The ID is assigned when the family is registered so it is only resolved
+// once.
+func taskstatsFamilyID() (uint16, error) {
+	taskstatsFamily.once.Do(func() {
+		taskstatsFamily.id, taskstatsFamily.err = genlFamilyID(taskstatsGenlName)
+	})
+	return taskstatsFamily.id, taskstatsFamily.err
+}
+
+// Taskstats returns the accounting data of the process by querying the
+// taskstats interface over netlink for each thread listed in
+// /proc/[pid]/task. This requires CAP_NET_ADMIN and a kernel with
+// CONFIG_TASKSTATS. The delay fields are only populated when delay
+// accounting is enabled (delayacct boot option or kernel.task_delayacct).
+// PIDs are interpreted in the PID namespace of the current process so this
+// is only supported when reading the live procfs of that namespace.
+func (p *process) Taskstats() (*types.TaskstatsInfo, error) {
+	if !isProcFS(string(p.procFS)) {
+		return nil, errors.Errorf("taskstats requires a live procfs (%v)", p.procFS)
+	}
+
+	family, err := taskstatsFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := p.fs.ReadDir(p.name("task"))
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.TaskstatsInfo{}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+
+		msgs, err := genlRequest(family, taskstatsCmdGet, taskstatsGenlVersion, 0,
+			netlinkAttr(taskstatsCmdAttrPID, uint32Bytes(uint32(tid))))
+		if err != nil {
+			if err == syscall.ESRCH {
+				// The thread exited.
+				continue
+			}
+			return nil, errors.Wrap(err, "taskstats request failed")
+		}
+
+		for _, attrs := range msgs {
+			stats, err := parseTaskstatsAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			addTaskstats(info, stats)
+		}
+	}
+	return info, nil
+}
+
+// parseTaskstatsAttrs decodes the struct taskstats nested in the
+// TASKSTATS_TYPE_AGGR_PID attribute of a TASKSTATS_CMD_NEW reply.
+func parseTaskstatsAttrs(attrs map[uint16][]byte) (*types.TaskstatsInfo, error) {
+	aggr, found := attrs[taskstatsTypeAggrPID]
+	if !found {
+		return nil, errors.New("taskstats reply is missing TASKSTATS_TYPE_AGGR_PID")
+	}
+	stats, found := parseNetlinkAttrs(aggr)[taskstatsTypeStats]
+	if !found {
+		return nil, errors.New("taskstats reply is missing TASKSTATS_TYPE_STATS")
+	}
+	return parseTaskstats(stats)
+}
+
+// parseTaskstats decodes a struct taskstats. Delays are in nanoseconds, CPU
+// times in microseconds, and the memory high-water marks in KiB.
+func parseTaskstats(b []byte) (*types.TaskstatsInfo, error) {
+	if len(b) < taskstatsMinLen {
+		return nil, errors.Errorf("taskstats struct is too short (%d bytes)", len(b))
+	}
+
+	u64 := func(offset int) uint64 { return nativeEndian.Uint64(b[offset:]) }
+	return &types.TaskstatsInfo{
+		CPUDelay:                   time.Duration(u64(24)),
+		BlockIODelay:               time.Duration(u64(40)),
+		SwapinDelay:                time.Duration(u64(56)),
+		UserTime:                   time.Duration(u64(152)) * time.Microsecond,
+		SystemTime:                 time.Duration(u64(160)) * time.Microsecond,
+		MinorFaults:                u64(168),
+		MajorFaults:                u64(176),
+		MaxRSS:                     u64(200) * 1024,
+		MaxVM:                      u64(208) * 1024,
+		ReadChars:                  u64(216),
+		WriteChars:                 u64(224),
+		ReadSyscalls:               u64(232),
+		WriteSyscalls:              u64(240),
+		ReadBytes:                  u64(248),
+		WriteBytes:                 u64(256),
+		CancelledWriteBytes:        u64(264),
+		VoluntaryContextSwitches:   u64(272),
+		InvoluntaryContextSwitches: u64(280),
+	}, nil
+}
+
+// addTaskstats adds the counters of a thread to the process totals. The
+// memory high-water marks are shared by all threads.
+func addTaskstats(total, t *types.TaskstatsInfo) {
+	total.UserTime += t.UserTime
+	total.SystemTime += t.SystemTime
+	total.CPUDelay += t.CPUDelay
+	total.BlockIODelay += t.BlockIODelay
+	total.SwapinDelay += t.SwapinDelay
+	total.MinorFaults += t.MinorFaults
+	total.MajorFaults += t.MajorFaults
+	if t.MaxRSS > total.MaxRSS {
+		total.MaxRSS = t.MaxRSS
+	}
+	if t.MaxVM > total.MaxVM {
+		total.MaxVM = t.MaxVM
+	}
+	total.ReadChars += t.ReadChars
+	total.WriteChars += t.WriteChars
+	total.ReadSyscalls += t.ReadSyscalls
+	total.WriteSyscalls += t.WriteSyscalls
+	total.ReadBytes += t.ReadBytes
+	total.WriteBytes += t.WriteBytes
+	total.CancelledWriteBytes += t.CancelledWriteBytes
+	total.VoluntaryContextSwitches += t.VoluntaryContextSwitches
+	total.InvoluntaryContextSwitches += t.InvoluntaryContextSwitches
+}
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Taskstats = (*process)(nil)
+
+func TestParseTaskstatsAttrs(t *testing.T) {
+	stats := make([]byte, 328)
+	put := func(offset int, v uint64) { nativeEndian.PutUint64(stats[offset:], v) }
+	put(24, 1500)     // cpu_delay_total
+	put(40, 2500)     // blkio_delay_total
+	put(152, 3000000) // ac_utime
+	put(160, 1000000) // ac_stime
+	put(168, 42)      // ac_minflt
+	put(200, 2048)    // hiwater_rss
+	put(216, 16384)   // read_char
+	put(256, 4096)    // write_bytes
+	put(272, 7)       // nvcsw
+	put(280, 3)       // nivcsw
+
+	aggr := append(netlinkAttr(1, uint32Bytes(1234)), netlinkAttr(taskstatsTypeStats, stats)...)
+	attrs := parseNetlinkAttrs(netlinkAttr(taskstatsTypeAggrPID, aggr))
+
+	info, err := parseTaskstatsAttrs(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.TaskstatsInfo{
+		CPUDelay:                   1500 * time.Nanosecond,
+		BlockIODelay:               2500 * time.Nanosecond,
+		UserTime:                   3 * time.Second,
+		SystemTime:                 time.Second,
+		MinorFaults:                42,
+		MaxRSS:                     2 << 20,
+		ReadChars:                  16384,
+		WriteBytes:                 4096,
+		VoluntaryContextSwitches:   7,
+		InvoluntaryContextSwitches: 3,
+	}, info)
+
+	total := &types.TaskstatsInfo{}
+	addTaskstats(total, info)
+	addTaskstats(total, info)
+	assert.Equal(t, 6*time.Second, total.UserTime)
+	assert.EqualValues(t, 2<<20, total.MaxRSS)
+
+	_, err = parseTaskstats(stats[:100])
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/prometheus/procfs"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Threads returns the threads of the process listed in /proc/[pid]/task.
+func (p *process) Threads() ([]types.ThreadInfo, error) {
+	tasks, err := p.fs.ReadDir(p.name("task"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Each task directory has the same layout as a process directory.
+	taskFS := procfs.FS(p.path("task"))
+	threads := make([]types.ThreadInfo, 0, len(tasks))
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+
+		thread, err := taskFS.NewProc(tid)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The thread exited.
+				continue
+			}
+			return nil, err
+		}
+
+		stat, err := thread.NewStat()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		threads = append(threads, types.ThreadInfo{
+			TID:   tid,
+			Name:  stat.Comm,
+			State: procState(stat.State),
+			CPUTime: types.CPUTimes{
+				User:   ticksToDuration(uint64(stat.UTime)),
+				System: ticksToDuration(uint64(stat.STime)),
+			},
+		})
+	}
+	return threads, nil
+}
+
+// procState maps the state character from /proc/[pid]/stat to one of the
+// ProcessState constants.
+func procState(state string) string {
+	switch state {
+	case "R":
+		return types.ProcessStateRunning
+	case "S":
+		return types.ProcessStateSleeping
+	case "D":
+		return types.ProcessStateWaiting
+	case "T", "t":
+		return types.ProcessStateStopped
+	case "Z":
+		return types.ProcessStateZombie
+	case "I":
+		return types.ProcessStateIdle
+	default:
+		return types.ProcessStateUnknown
+	}
+}
@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.ThreadEnumerator = (*process)(nil)
+var _ types.AncestorEnumerator = (*process)(nil)
+
+func TestThreads(t *testing.T) {
+	self, err := newLinuxSystem("").Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threads, err := self.(types.ThreadEnumerator).Threads()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, thread := range threads {
+		if thread.TID == os.Getpid() {
+			found = true
+			assert.NotEmpty(t, thread.Name)
+			assert.NotEqual(t, types.ProcessStateUnknown, thread.State)
+		}
+	}
+	assert.True(t, found, "main thread not found")
+}
+
+func TestProcState(t *testing.T) {
+	assert.Equal(t, types.ProcessStateRunning, procState("R"))
+	assert.Equal(t, types.ProcessStateWaiting, procState("D"))
+	assert.Equal(t, types.ProcessStateZombie, procState("Z"))
+	assert.Equal(t, types.ProcessStateUnknown, procState("W"))
+}
@@ -0,0 +1,134 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// TPMInfo reports the first TPM in /sys/class/tpm. The state of a TPM 2.0
+// is read through the resource manager (/dev/tpmrm0) which usually requires
+// root or membership in the tss group.
+func (h *host) TPMInfo() (*types.TPMInfo, error) {
+	return readTPMInfo(h.fs)
+}
+
+func readTPMInfo(fs fileSystem) (*types.TPMInfo, error) {
+	dir := "sys/class/tpm/tpm0"
+	info := &types.TPMInfo{}
+	if _, err := fs.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return info, nil
+		}
+		return nil, err
+	}
+	info.Present = true
+
+	// Older kernels expose the TPM 1.2 attributes under device/.
+	caps := path.Join(dir, "caps")
+	if _, err := fs.Stat(caps); err != nil {
+		dir = path.Join(dir, "device")
+		caps = path.Join(dir, "caps")
+	}
+
+	switch readAttr(fs, "sys/class/tpm/tpm0/tpm_version_major") {
+	case "1":
+		info.Version = types.TPMVersion12
+	case "2":
+		info.Version = types.TPMVersion20
+	default:
+		// tpm_version_major was added in Linux 5.6. Only TPM 1.2 has caps.
+		info.Version = types.TPMVersion20
+		if _, err := fs.Stat(caps); err == nil {
+			info.Version = types.TPMVersion12
+		}
+	}
+
+	if info.Version == types.TPMVersion12 {
+		readTPM12(fs, dir, caps, info)
+		return info, nil
+	}
+
+	for _, dev := range []string{"dev/tpmrm0", "dev/tpm0"} {
+		f, err := openDevice(fs, dev, os.O_RDWR)
+		if err != nil {
+			continue
+		}
+		err = shared.TPM2Query(tpmSubmit(f), info)
+		f.Close()
+		if err == nil {
+			break
+		}
+	}
+	return info, nil
+}
+
+// readTPM12 reads the caps, enabled, and owned attributes of a TPM 1.2.
+func readTPM12(fs fileSystem, dir, caps string, info *types.TPMInfo) {
+	if f, err := fs.Open(caps); err == nil {
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			parts := strings.SplitN(s.Text(), ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			value := strings.TrimSpace(parts[1])
+			switch parts[0] {
+			case "Manufacturer":
+				if id, err := strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 32); err == nil {
+					info.Manufacturer = shared.TPMManufacturer(uint32(id))
+				}
+			case "Firmware version":
+				info.FirmwareVersion = value
+			}
+		}
+		f.Close()
+	}
+
+	if v, err := readAttrInt(fs, path.Join(dir, "enabled")); err == nil {
+		enabled := v == 1
+		info.Enabled = &enabled
+	}
+	if v, err := readAttrInt(fs, path.Join(dir, "owned")); err == nil {
+		owned := v == 1
+		info.Owned = &owned
+	}
+}
+
+// tpmSubmit returns a function that writes a command to the TPM character
+// device and reads its response.
+func tpmSubmit(f *os.File) shared.TPMSubmitFunc {
+	return func(cmd []byte) ([]byte, error) {
+		if _, err := f.Write(cmd); err != nil {
+			return nil, err
+		}
+		resp := make([]byte, 4096)
+		n, err := f.Read(resp)
+		if err != nil {
+			return nil, err
+		}
+		return resp[:n], nil
+	}
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.TPM = (*host)(nil)
+
+func TestTPMInfo12(t *testing.T) {
+	info, err := readTPMInfo(dirFS("testdata/centos7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled, owned := true, false
+	assert.Equal(t, &types.TPMInfo{
+		Present:         true,
+		Version:         types.TPMVersion12,
+		Manufacturer:    "IFX",
+		FirmwareVersion: "3.17",
+		Enabled:         &enabled,
+		Owned:           &owned,
+	}, info)
+}
+
+func TestTPMInfo20(t *testing.T) {
+	info, err := readTPMInfo(dirFS("testdata/ubuntu1710"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a TPM device only the version is known.
+	assert.Equal(t, &types.TPMInfo{
+		Present: true,
+		Version: types.TPMVersion20,
+	}, info)
+}
+
+func TestTPMInfoNotPresent(t *testing.T) {
+	info, err := readTPMInfo(dirFS("testdata/debian9"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, info.Present)
+}
+
+func TestTPMInfoNoDevices(t *testing.T) {
+	// Filesystems other than dirFS cannot open the TPM device so only the
+	// sysfs attributes are reported.
+	info, err := readTPMInfo(mapFS{
+		"sys/class/tpm/tpm0/tpm_version_major": "2\n",
+		"dev/tpmrm0":                           "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.TPMInfo{Present: true, Version: types.TPMVersion20}, info)
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// sizeofLastlog is the size of struct lastlog which holds a 32-bit time, a
+// 32 byte terminal name, and a 256 byte host name.
+const sizeofLastlog = 292
+
+// Users returns the accounts in /etc/passwd. The last login time is read
+// from /var/log/lastlog when it exists.
+func (s linuxSystem) Users() ([]types.UserAccount, error) {
+	content, err := s.fs.ReadFile("etc/passwd")
+	if err != nil {
+		return nil, err
+	}
+	users := shared.ParsePasswd(content)
+
+	lastlog, err := s.fs.Open("var/log/lastlog")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return users, nil
+		}
+		return nil, err
+	}
+	defer lastlog.Close()
+
+	for i := range users {
+		uid, err := strconv.ParseUint(users[i].UID, 10, 32)
+		if err != nil {
+			continue
+		}
+		users[i].LastLogin, err = readLastlog(lastlog, uid)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// readLastlog returns the last login time of uid. The lastlog file is a
+// sparse array of records indexed by UID.
+func readLastlog(r io.ReaderAt, uid uint64) (*time.Time, error) {
+	var buf [4]byte
+	if _, err := r.ReadAt(buf[:], int64(uid)*sizeofLastlog); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sec := int32(binary.LittleEndian.Uint32(buf[:]))
+	if sec <= 0 {
+		return nil, nil
+	}
+	t := time.Unix(int64(sec), 0)
+	return &t, nil
+}
+
+// Groups returns the groups in /etc/group.
+func (s linuxSystem) Groups() ([]types.GroupAccount, error) {
+	content, err := s.fs.ReadFile("etc/group")
+	if err != nil {
+		return nil, err
+	}
+	return shared.ParseGroup(content), nil
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+)
+
+var _ registry.UserProvider = linuxSystem{}
+
+func TestUsers(t *testing.T) {
+	users, err := newLinuxSystem("testdata/ubuntu1710").Users()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, users, 3) {
+		assert.Equal(t, "root", users[0].Name)
+		assert.Nil(t, users[0].LastLogin)
+
+		ubuntu := users[2]
+		assert.Equal(t, "1000", ubuntu.UID)
+		assert.Equal(t, "Ubuntu", ubuntu.FullName)
+		assert.Equal(t, "/home/ubuntu", ubuntu.Home)
+		if assert.NotNil(t, ubuntu.LastLogin) {
+			assert.Equal(t, time.Unix(1509470000, 0), *ubuntu.LastLogin)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	groups, err := newLinuxSystem("testdata/ubuntu1710").Groups()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, groups, 4) {
+		assert.Equal(t, "adm", groups[1].Name)
+		assert.Equal(t, []string{"syslog", "ubuntu"}, groups[1].Members)
+	}
+}
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// VirtualizationInfo detects the hypervisor from CPUID, the DMI strings in
+// /sys/class/dmi/id, and the Xen and KVM interfaces of the kernel.
+func (h *host) VirtualizationInfo() (*types.VirtualizationInfo, error) {
+	hw, _ := readDMI(h.fs, dmiDir)
+	assetTag, _ := h.fs.ReadFile(path.Join(dmiDir, "chassis_asset_tag"))
+
+	info := shared.Virtualization(hw, strings.TrimSpace(string(assetTag)))
+	readVirtualization(h.fs, info)
+	return info, nil
+}
+
+// readVirtualization refines the CPUID and DMI based result. Xen PV guests
+// do not expose a CPUID signature but report /sys/hypervisor/type, and the
+// control domain (dom0) is identified by /proc/xen/capabilities.
+func readVirtualization(fs fileSystem, info *types.VirtualizationInfo) {
+	if typ, err := fs.ReadFile("sys/hypervisor/type"); err == nil {
+		if strings.TrimSpace(string(typ)) == "xen" && info.Hypervisor == "" {
+			info.Hypervisor, info.Role = "Xen", types.VirtualizationRoleGuest
+		}
+	}
+
+	if caps, err := fs.ReadFile("proc/xen/capabilities"); err == nil {
+		if strings.Contains(string(caps), "control_d") {
+			info.Hypervisor, info.Role = "Xen", types.VirtualizationRoleHost
+		}
+	}
+
+	if info.Role == "" {
+		if _, err := fs.Stat("dev/kvm"); err == nil {
+			info.Hypervisor, info.Role = "KVM", types.VirtualizationRoleHost
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.Virtualization = (*host)(nil)
+
+func TestReadVirtualization(t *testing.T) {
+	info := &types.VirtualizationInfo{}
+	readVirtualization(dirFS("testdata/ubuntu1710"), info)
+	assert.Equal(t, &types.VirtualizationInfo{
+		Hypervisor: "Xen",
+		Role:       types.VirtualizationRoleHost,
+	}, info)
+
+	// The CPUID result is kept for HVM and KVM guests.
+	info = &types.VirtualizationInfo{Hypervisor: "KVM", Role: types.VirtualizationRoleGuest}
+	readVirtualization(dirFS("testdata/missing"), info)
+	assert.Equal(t, "KVM", info.Hypervisor)
+	assert.Equal(t, types.VirtualizationRoleGuest, info.Role)
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"path"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// CPUVulnerabilities returns the contents of
+// /sys/devices/system/cpu/vulnerabilities. The directory exists since kernel
+// 4.15.
+func (h *host) CPUVulnerabilities() (map[string]types.CPUVulnerabilityInfo, error) {
+	const dir = "sys/devices/system/cpu/vulnerabilities"
+	entries, err := h.fs.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns := make(map[string]types.CPUVulnerabilityInfo, len(entries))
+	for _, e := range entries {
+		details := readAttr(h.fs, path.Join(dir, e.Name()))
+		vulns[e.Name()] = types.CPUVulnerabilityInfo{
+			Status:  vulnerabilityStatus(details),
+			Details: details,
+		}
+	}
+	return vulns, nil
+}
+
+// vulnerabilityStatus classifies the text of a vulnerabilities file. The
+// first word decides the state (e.g. "Mitigation: PTI; SMT vulnerable" is
+// mitigated). Entries that are specific to KVM are prefixed with "KVM: ".
+func vulnerabilityStatus(details string) string {
+	details = strings.TrimPrefix(details, "KVM: ")
+	switch {
+	case strings.HasPrefix(details, "Not affected"):
+		return types.CPUVulnerabilityNotAffected
+	case strings.HasPrefix(details, "Mitigation"):
+		return types.CPUVulnerabilityMitigated
+	case strings.HasPrefix(details, "Vulnerable"):
+		return types.CPUVulnerabilityVulnerable
+	default:
+		return types.CPUVulnerabilityUnknown
+	}
+}
@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package linux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+var _ types.CPUVulnerabilities = (*host)(nil)
+
+func TestHostCPUVulnerabilities(t *testing.T) {
+	host, err := newLinuxSystem("testdata/ubuntu1710").Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vulns, err := host.(types.CPUVulnerabilities).CPUVulnerabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]types.CPUVulnerabilityInfo{
+		"itlb_multihit": {Status: types.CPUVulnerabilityMitigated, Details: "KVM: Mitigation: Split huge pages"},
+		"l1tf":          {Status: types.CPUVulnerabilityNotAffected, Details: "Not affected"},
+		"meltdown":      {Status: types.CPUVulnerabilityMitigated, Details: "Mitigation: PTI"},
+		"spectre_v2":    {Status: types.CPUVulnerabilityVulnerable, Details: "Vulnerable: Minimal generic ASM retpoline"},
+	}, vulns)
+
+	assert.Equal(t, types.CPUVulnerabilityUnknown, vulnerabilityStatus("Unknown: Dependent on hypervisor status"))
+}
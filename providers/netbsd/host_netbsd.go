@@ -0,0 +1,320 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build netbsd,amd64 netbsd,arm64
+
+package netbsd
+
+import (
+	"encoding/binary"
+	"os"
+	"time"
+	"unsafe"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func init() {
+	registry.Register(netbsdSystem{})
+}
+
+type netbsdSystem struct {
+	envFilter types.EnvFilter // Applied to the environment of the processes.
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s netbsdSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+func (s netbsdSystem) Host() (types.Host, error) {
+	return newHost()
+}
+
+type host struct {
+	info types.HostInfo
+}
+
+func (h *host) Info() types.HostInfo {
+	return h.info
+}
+
+// Uptime returns the time since boot.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock.Uptime()
+}
+
+// loadAvg is struct loadavg. The fscale field is a C long.
+type loadAvg struct {
+	Ldavg  [3]uint32
+	Fscale int
+}
+
+// LoadAverage returns the load averages from vm.loadavg.
+func (h *host) LoadAverage() (*types.LoadAverageInfo, error) {
+	data, err := sysctl([]int32{ctlVM, vmLoadAvg})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vm.loadavg")
+	}
+	if len(data) < int(unsafe.Sizeof(loadAvg{})) {
+		return nil, errors.New("unexpected vm.loadavg size")
+	}
+
+	la := (*loadAvg)(unsafe.Pointer(&data[0]))
+	return shared.FixedPointLoadAverage(la.Ldavg, int64(la.Fscale)), nil
+}
+
+func (h *host) CPUTime() (types.CPUTimes, error) {
+	data, err := sysctl([]int32{ctlKern, kernCPTime})
+	if err != nil {
+		return types.CPUTimes{}, errors.Wrap(err, "failed to read kern.cp_time")
+	}
+	return cpuTimes(data)
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	ncpu, err := sysctlUint64(ctlHW, hwNCPU)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get number of CPUs")
+	}
+
+	times := make([]types.CPUTimes, 0, ncpu)
+	for i := 0; i < int(ncpu); i++ {
+		data, err := sysctl([]int32{ctlKern, kernCPTime, int32(i)})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read kern.cp_time for cpu %d", i)
+		}
+		t, err := cpuTimes(data)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// cpuTimes converts the CPUSTATES tick counters (user, nice, system,
+// interrupt, idle).
+func cpuTimes(data []byte) (types.CPUTimes, error) {
+	const cpuStates = 5
+	if len(data) != cpuStates*8 {
+		return types.CPUTimes{}, errors.Errorf("unexpected kern.cp_time size %d", len(data))
+	}
+
+	hz, err := statHz()
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+	d := func(i int) time.Duration {
+		return time.Duration(binary.LittleEndian.Uint64(data[i*8:])) * time.Second / hz
+	}
+
+	return types.CPUTimes{
+		User:   d(0),
+		Nice:   d(1),
+		System: d(2),
+		IRQ:    d(3),
+		Idle:   d(4),
+	}, nil
+}
+
+// statHz returns the frequency of the statistics clock. It is the stathz
+// field of struct clockinfo.
+func statHz() (time.Duration, error) {
+	data, err := sysctl([]int32{ctlKern, kernClockRate})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read kern.clockrate")
+	}
+	if len(data) < 16 {
+		return 0, errors.New("unexpected kern.clockrate size")
+	}
+
+	hz := binary.LittleEndian.Uint32(data[12:])
+	if hz == 0 {
+		// stathz is zero when it is the same as hz.
+		hz = binary.LittleEndian.Uint32(data[0:])
+	}
+	return time.Duration(hz), nil
+}
+
+func (h *host) Memory() (*types.HostMemoryInfo, error) {
+	var mem types.HostMemoryInfo
+
+	total, err := sysctlUint64(ctlHW, hwPhysMem64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get total physical memory")
+	}
+	mem.Total = total
+
+	// struct uvmexp_sysctl begins with the int64_t fields pagesize,
+	// pagemask, pageshift, npages, free, active, inactive, paging, and wired.
+	data, err := sysctl([]int32{ctlVM, vmUVMExp2})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vm.uvmexp2")
+	}
+	if len(data) < 72 {
+		return nil, errors.New("unexpected vm.uvmexp2 size")
+	}
+	field := func(i int) uint64 {
+		return binary.LittleEndian.Uint64(data[i*8:])
+	}
+	pageSize := field(0)
+
+	mem.Free = field(4) * pageSize
+	mem.Used = mem.Total - mem.Free
+	mem.Available = mem.Free + field(6)*pageSize
+	mem.Metrics = map[string]uint64{
+		"active_bytes":   field(5) * pageSize,
+		"inactive_bytes": field(6) * pageSize,
+		"wired_bytes":    field(8) * pageSize,
+	}
+
+	// Swap usage is only available through swapctl(2) so virtual memory is
+	// not reported.
+	return &mem, nil
+}
+
+func newHost() (*host, error) {
+	h := &host{}
+	r := &reader{}
+	r.architecture(h)
+	r.bootTime(h)
+	r.hostname(h)
+	r.network(h)
+	r.kernelVersion(h)
+	r.os(h)
+	r.time(h)
+	r.uniqueID(h)
+	return h, r.Err()
+}
+
+type reader struct {
+	errs []error
+}
+
+func (r *reader) addErr(err error) bool {
+	if err != nil {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+			r.errs = append(r.errs, err)
+		}
+		return true
+	}
+	return false
+}
+
+func (r *reader) Err() error {
+	if len(r.errs) > 0 {
+		return &multierror.MultiError{Errors: r.errs}
+	}
+	return nil
+}
+
+func (r *reader) architecture(h *host) {
+	v, err := sysctlString("hw.machine_arch")
+	if r.addErr(err) {
+		return
+	}
+	h.info.Architecture = v
+}
+
+func (r *reader) bootTime(h *host) {
+	v, err := bootClock.BootTime()
+	if r.addErr(err) {
+		return
+	}
+	h.info.BootTime = v
+}
+
+var bootClock = shared.NewBootTimeClock(kernBootTimeValue)
+
+func kernBootTimeValue() (time.Time, error) {
+	data, err := sysctl([]int32{ctlKern, kernBootTime})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to read kern.boottime")
+	}
+	if len(data) < 16 {
+		return time.Time{}, errors.New("unexpected kern.boottime size")
+	}
+
+	// struct timeval with a 32-bit suseconds_t.
+	sec := int64(binary.LittleEndian.Uint64(data[0:]))
+	usec := int64(binary.LittleEndian.Uint32(data[8:]))
+	return time.Unix(sec, usec*int64(time.Microsecond)), nil
+}
+
+func (r *reader) hostname(h *host) {
+	v, err := os.Hostname()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Hostname = v
+}
+
+func (r *reader) network(h *host) {
+	ips, macs, err := shared.Network()
+	if r.addErr(err) {
+		return
+	}
+	h.info.IPs = ips
+	h.info.MACs = macs
+}
+
+func (r *reader) kernelVersion(h *host) {
+	v, err := sysctlString("kern.osrelease")
+	if r.addErr(err) {
+		return
+	}
+	h.info.KernelVersion = v
+}
+
+func (r *reader) os(h *host) {
+	v, err := OperatingSystem()
+	if r.addErr(err) {
+		return
+	}
+	h.info.OS = v
+}
+
+func (r *reader) time(h *host) {
+	h.info.Timezone, h.info.TimezoneOffsetSec = time.Now().Zone()
+}
+
+func (r *reader) uniqueID(h *host) {
+	// The system UUID is only available on machines with SMBIOS.
+	v, err := sysctlString("machdep.dmi.system-uuid")
+	if err != nil {
+		return
+	}
+	h.info.UniqueID = v
+	h.info.UniqueIDSource = types.MachineIDSourceDMI
+}
+
+// OperatingSystem returns information about the NetBSD release.
+func OperatingSystem() (*types.OSInfo, error) {
+	release, err := sysctlString("kern.osrelease")
+	if err != nil {
+		return nil, err
+	}
+	return getOSInfo(release)
+}
@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package netbsd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestGetOSInfo(t *testing.T) {
+	os, err := getOSInfo("9.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &types.OSInfo{
+		Family:   "netbsd",
+		Platform: "netbsd",
+		Name:     "NetBSD",
+		Version:  "9.3",
+		Major:    9,
+		Minor:    3,
+	}, os)
+
+	_, err = getOSInfo("bogus")
+	assert.Error(t, err)
+}
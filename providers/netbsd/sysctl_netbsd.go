@@ -0,0 +1,139 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build netbsd,amd64 netbsd,arm64
+
+package netbsd
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// MIB values from sys/sysctl.h.
+const (
+	ctlKern = 1
+	ctlVM   = 2
+	ctlHW   = 6
+
+	kernClockRate = 12
+	kernBootTime  = 21
+	kernProc2     = 47
+	kernProcArgs  = 48
+	kernCPTime    = 51
+
+	kernProcAll = 0
+	kernProcPID = 1
+
+	kernProcArgv     = 1
+	kernProcEnv      = 3
+	kernProcPathname = 5
+	kernProcCWD      = 6
+
+	vmLoadAvg = 2
+	vmUVMExp2 = 5
+
+	hwNCPU      = 3
+	hwPhysMem64 = 13
+)
+
+func _sysctl(mib []int32, old *byte, oldlen *uintptr, new *byte, newlen uintptr) error {
+	_, _, e1 := syscall.Syscall6(syscall.SYS___SYSCTL,
+		uintptr(unsafe.Pointer(&mib[0])), uintptr(len(mib)),
+		uintptr(unsafe.Pointer(old)), uintptr(unsafe.Pointer(oldlen)),
+		uintptr(unsafe.Pointer(new)), newlen)
+	if e1 != 0 {
+		return e1
+	}
+	return nil
+}
+
+// sysctl returns the raw value for the given MIB. The size of the value may
+// change between calls (e.g. the process table) so it retries with a larger
+// buffer when it gets ENOMEM.
+func sysctl(mib []int32) ([]byte, error) {
+	for {
+		var size uintptr
+		if err := _sysctl(mib, nil, &size, nil, 0); err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+
+		// Add some slack for values that grow between the calls.
+		size += size / 8
+		buf := make([]byte, size)
+		err := _sysctl(mib, &buf[0], &size, nil, 0)
+		if err == syscall.ENOMEM {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	}
+}
+
+// sysctlString returns a string value by name. The syscall package resolves
+// the MIB of the name.
+func sysctlString(name string) (string, error) {
+	v, err := syscall.Sysctl(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "sysctl %v failed", name)
+	}
+	return v, nil
+}
+
+func sysctlUint64(mib ...int32) (uint64, error) {
+	data, err := sysctl(mib)
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(data) {
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(data)), nil
+	case 8:
+		return binary.LittleEndian.Uint64(data), nil
+	default:
+		return 0, errors.Errorf("unexpected size %d for sysctl %v", len(data), mib)
+	}
+}
+
+func nullTerminated(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+func int8SliceToString(s []int8) string {
+	b := make([]byte, 0, len(s))
+	for _, c := range s {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
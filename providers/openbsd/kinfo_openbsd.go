@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build openbsd,amd64 openbsd,arm64
+
+package openbsd
+
+// kinfoProc is the leading part of struct kinfo_proc from sys/sysctl.h. The
+// structure only contains fixed width fields and the kernel copies as many
+// bytes as the caller asks for, so the fields after the CPU times are omitted.
+type kinfoProc struct {
+	Forw       uint64
+	Back       uint64
+	Paddr      uint64
+	Addr       uint64
+	Fd         uint64
+	Stats      uint64
+	Limit      uint64
+	Vmspace    uint64
+	Sigacts    uint64
+	Sess       uint64
+	Tsess      uint64
+	Ru         uint64
+	Eflag      int32
+	Exitsig    int32
+	Flag       int32
+	Pid        int32
+	Ppid       int32
+	Sid        int32
+	Pgid       int32
+	Tpgid      int32
+	UID        uint32
+	RUID       uint32
+	GID        uint32
+	RGID       uint32
+	Groups     [16]uint32
+	Ngroups    int16
+	Jobc       int16
+	Tdev       uint32
+	Estcpu     uint32
+	RtimeSec   uint32
+	RtimeUsec  uint32
+	Cpticks    int32
+	Pctcpu     uint32
+	Swtime     uint32
+	Slptime    uint32
+	Schedflags int32
+	Uticks     uint64
+	Sticks     uint64
+	Iticks     uint64
+	Tracep     uint64
+	Traceflag  int32
+	Holdcnt    int32
+	Siglist    int32
+	Sigmask    uint32
+	Sigignore  uint32
+	Sigcatch   uint32
+	Stat       int8
+	Priority   uint8
+	Usrpri     uint8
+	Nice       uint8
+	Xstat      uint16
+	Acflag     uint16
+	Comm       [24]int8
+	Wmesg      [8]int8
+	Wchan      uint64
+	Login      [32]int8
+	VMRssize   int32
+	VMTsize    int32
+	VMDsize    int32
+	VMSsize    int32
+	Uvalid     int64
+	UstartSec  uint64
+	UstartUsec uint32
+	UutimeSec  uint32
+	UutimeUsec uint32
+	UstimeSec  uint32
+	UstimeUsec uint32
+}
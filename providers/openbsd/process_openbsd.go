@@ -0,0 +1,281 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build openbsd,amd64 openbsd,arm64
+
+package openbsd
+
+import (
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const kinfoProcSize = int(unsafe.Sizeof(kinfoProc{}))
+
+func (s openbsdSystem) Processes() ([]types.Process, error) {
+	kps, err := getKinfoProcs(kernProcAll, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process table")
+	}
+
+	processes := make([]types.Process, 0, len(kps))
+	for _, kp := range kps {
+		processes = append(processes, &process{pid: int(kp.Pid), envFilter: s.envFilter})
+	}
+	return processes, nil
+}
+
+func (s openbsdSystem) Process(pid int) (types.Process, error) {
+	if _, err := getKinfoProc(pid); err != nil {
+		return nil, err
+	}
+	return &process{pid: pid, envFilter: s.envFilter}, nil
+}
+
+func (s openbsdSystem) Self() (types.Process, error) {
+	return &process{pid: os.Getpid(), envFilter: s.envFilter}, nil
+}
+
+type process struct {
+	pid       int
+	envFilter types.EnvFilter
+	info      *types.ProcessInfo
+}
+
+func (p *process) PID() int {
+	return p.pid
+}
+
+// Info returns information about the process. OpenBSD does not expose the
+// path of the executable so Exe is always empty.
+func (p *process) Info() (types.ProcessInfo, error) {
+	if p.info != nil {
+		return *p.info, nil
+	}
+
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.ProcessInfo{}, err
+	}
+
+	// The arguments and CWD are unavailable for kernel processes and for
+	// processes of other users when unprivileged.
+	args, _ := getProcStrings(p.pid, kernProcArgv)
+	var cwd string
+	if data, err := sysctl([]int32{ctlKern, kernProcCWD, int32(p.pid)}); err == nil {
+		cwd = nullTerminated(data)
+	}
+
+	p.info = &types.ProcessInfo{
+		Name:      int8SliceToString(kp.Comm[:]),
+		PID:       p.pid,
+		PPID:      int(kp.Ppid),
+		CWD:       cwd,
+		Args:      args,
+		StartTime: time.Unix(int64(kp.UstartSec), int64(kp.UstartUsec)*int64(time.Microsecond)),
+		State:     kinfoState(kp.Stat),
+	}
+
+	return *p.info, nil
+}
+
+// p_stat values from sys/proc.h.
+const (
+	statRun    = 2 // SRUN
+	statSleep  = 3 // SSLEEP
+	statStop   = 4 // SSTOP
+	statZombie = 5 // SZOMB
+	statDead   = 6 // SDEAD
+	statOnProc = 7 // SONPROC
+)
+
+// kinfoState maps p_stat to one of the ProcessState constants.
+func kinfoState(stat int8) string {
+	switch stat {
+	case statRun, statOnProc:
+		return types.ProcessStateRunning
+	case statSleep:
+		return types.ProcessStateSleeping
+	case statStop:
+		return types.ProcessStateStopped
+	case statZombie, statDead:
+		return types.ProcessStateZombie
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+// User returns the user and group IDs. The saved IDs are not reported.
+func (p *process) User() (types.UserInfo, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.UserInfo{}, err
+	}
+
+	return types.UserInfo{
+		UID:  strconv.Itoa(int(kp.RUID)),
+		EUID: strconv.Itoa(int(kp.UID)),
+		GID:  strconv.Itoa(int(kp.RGID)),
+		EGID: strconv.Itoa(int(kp.GID)),
+	}, nil
+}
+
+func (p *process) Memory() (types.MemoryInfo, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.MemoryInfo{}, err
+	}
+
+	// Virtual size is computed like ps(1) does it.
+	pageSize := uint64(os.Getpagesize())
+	return types.MemoryInfo{
+		Resident: uint64(kp.VMRssize) * pageSize,
+		Virtual:  uint64(kp.VMTsize+kp.VMDsize+kp.VMSsize) * pageSize,
+	}, nil
+}
+
+func (p *process) CPUTime() (types.CPUTimes, error) {
+	kp, err := getKinfoProc(p.pid)
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+
+	return types.CPUTimes{
+		User:   time.Duration(kp.UutimeSec)*time.Second + time.Duration(kp.UutimeUsec)*time.Microsecond,
+		System: time.Duration(kp.UstimeSec)*time.Second + time.Duration(kp.UstimeUsec)*time.Microsecond,
+	}, nil
+}
+
+func (p *process) Environment() (map[string]string, error) {
+	vars, err := getProcStrings(p.pid, kernProcEnv)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process environment")
+	}
+
+	env := map[string]string{}
+	for _, kv := range vars {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			continue
+		}
+
+		env[key] = parts[1]
+	}
+
+	return shared.FilterEnv(p.envFilter, env), nil
+}
+
+func (p *process) Children() ([]types.Process, error) {
+	procs, err := openbsdSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Children(procs, p)
+}
+
+// Ancestors returns the parents of the process up to the root of the tree.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := openbsdSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Ancestors(procs, p)
+}
+
+// getKinfoProcs returns the kinfo_proc structures that match the given
+// KERN_PROC operation. The last two MIB elements are the size of each
+// element and the maximum number of elements to return.
+func getKinfoProcs(op, arg int) ([]kinfoProc, error) {
+	mib := []int32{ctlKern, kernProc, int32(op), int32(arg), int32(kinfoProcSize), 0}
+
+	for {
+		var size uintptr
+		if err := _sysctl(mib, nil, &size, nil, 0); err != nil {
+			return nil, err
+		}
+
+		// Add some slack for processes that are created between the calls.
+		count := int(size)/kinfoProcSize + 8
+		kps := make([]kinfoProc, count)
+		size = uintptr(count * kinfoProcSize)
+		mib[5] = int32(count)
+
+		err := _sysctl(mib, (*byte)(unsafe.Pointer(&kps[0])), &size, nil, 0)
+		if err == syscall.ENOMEM {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return kps[:int(size)/kinfoProcSize], nil
+	}
+}
+
+func getKinfoProc(pid int) (*kinfoProc, error) {
+	kps, err := getKinfoProcs(kernProcPID, pid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read process info for pid=%v", pid)
+	}
+	if len(kps) == 0 {
+		return nil, &types.ProcessNotFoundError{PID: pid}
+	}
+	return &kps[0], nil
+}
+
+// getProcStrings returns the arguments or environment variables of a
+// process. The kernel returns a NULL terminated array of pointers followed by
+// the strings. The pointers are relative to the start of the caller's buffer.
+func getProcStrings(pid int, op int32) ([]string, error) {
+	data, err := sysctl([]int32{ctlKern, kernProcArgs, int32(pid), op})
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return parseProcStrings(data, uint64(uintptr(unsafe.Pointer(&data[0])))), nil
+}
+
+func parseProcStrings(data []byte, base uint64) []string {
+	var strs []string
+	for i := 0; i+8 <= len(data); i += 8 {
+		ptr := binary.LittleEndian.Uint64(data[i:])
+		if ptr == 0 {
+			break
+		}
+		if ptr < base || ptr-base >= uint64(len(data)) {
+			break
+		}
+		strs = append(strs, nullTerminated(data[ptr-base:]))
+	}
+	return strs
+}
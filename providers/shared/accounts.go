@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ParsePasswd parses a passwd(5) file. Comments, blank lines, and NIS
+// compat entries (lines starting with + or -) are skipped.
+func ParsePasswd(content []byte) []types.UserAccount {
+	var users []types.UserAccount
+	forEachEntry(content, 7, func(fields []string) {
+		// The GECOS field is a comma separated list that starts with the
+		// full name.
+		fullName := strings.SplitN(fields[4], ",", 2)[0]
+		users = append(users, types.UserAccount{
+			Name:     fields[0],
+			UID:      fields[2],
+			GID:      fields[3],
+			FullName: fullName,
+			Home:     fields[5],
+			Shell:    fields[6],
+		})
+	})
+	return users
+}
+
+// ParseGroup parses a group(5) file.
+func ParseGroup(content []byte) []types.GroupAccount {
+	var groups []types.GroupAccount
+	forEachEntry(content, 4, func(fields []string) {
+		var members []string
+		if fields[3] != "" {
+			members = strings.Split(fields[3], ",")
+		}
+		groups = append(groups, types.GroupAccount{
+			Name:    fields[0],
+			GID:     fields[2],
+			Members: members,
+		})
+	})
+	return groups
+}
+
+func forEachEntry(content []byte, numFields int, fn func(fields []string)) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == '+' || line[0] == '-' {
+			continue
+		}
+
+		fields := strings.Split(line, ":")
+		if len(fields) < numFields {
+			continue
+		}
+		fn(fields)
+	}
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParsePasswd(t *testing.T) {
+	content := []byte(`# comment
+root:x:0:0:root:/root:/bin/bash
+alice:x:1000:1000:Alice Smith,,,:/home/alice:/bin/zsh
+
++@netgroup::::::
+invalid:x:1
+`)
+
+	assert.Equal(t, []types.UserAccount{
+		{Name: "root", UID: "0", GID: "0", FullName: "root", Home: "/root", Shell: "/bin/bash"},
+		{Name: "alice", UID: "1000", GID: "1000", FullName: "Alice Smith", Home: "/home/alice", Shell: "/bin/zsh"},
+	}, ParsePasswd(content))
+}
+
+func TestParseGroup(t *testing.T) {
+	content := []byte(`root:x:0:
+sudo:x:27:alice,bob
+`)
+
+	assert.Equal(t, []types.GroupAccount{
+		{Name: "root", GID: "0"},
+		{Name: "sudo", GID: "27", Members: []string{"alice", "bob"}},
+	}, ParseGroup(content))
+}
@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"sync"
+	"time"
+)
+
+// BootClock caches the boot time of a host and reports its uptime. Providers
+// create one BootClock per host so that the boot time is derived once and
+// is identical across samples.
+type BootClock struct {
+	uptime   func() (time.Duration, error) // Monotonic time since boot.
+	bootTime func() (time.Time, error)     // Boot time reported by the kernel.
+
+	mu     sync.Mutex
+	boot   time.Time
+	anchor time.Time     // Time (with monotonic reading) when boot was read.
+	offset time.Duration // Uptime at anchor.
+}
+
+// NewUptimeClock returns a BootClock backed by a monotonic clock that measures
+// the time since boot (e.g. CLOCK_BOOTTIME). The boot time is derived from
+// the first reading and truncated to the second, matching the precision of the
+// boot time reported by the Linux kernel.
+func NewUptimeClock(uptime func() (time.Duration, error)) *BootClock {
+	return &BootClock{uptime: uptime}
+}
+
+// NewBootTimeClock returns a BootClock for platforms where the kernel reports
+// the boot time itself (e.g. kern.boottime). The uptime is measured from the
+// first reading using Go's monotonic clock so it is not affected by later
+// wall clock adjustments.
+func NewBootTimeClock(bootTime func() (time.Time, error)) *BootClock {
+	return &BootClock{bootTime: bootTime}
+}
+
+// BootTime returns the cached boot time.
+func (c *BootClock) BootTime() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.init(); err != nil {
+		return time.Time{}, err
+	}
+	return c.boot, nil
+}
+
+// Uptime returns the time elapsed since boot.
+func (c *BootClock) Uptime() (time.Duration, error) {
+	if c.uptime != nil {
+		return c.uptime()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.init(); err != nil {
+		return 0, err
+	}
+	return c.offset + time.Since(c.anchor), nil
+}
+
+func (c *BootClock) init() error {
+	if !c.boot.IsZero() {
+		return nil
+	}
+
+	now := time.Now()
+	if c.uptime != nil {
+		uptime, err := c.uptime()
+		if err != nil {
+			return err
+		}
+		// Truncate also strips the monotonic clock reading.
+		c.boot = now.Add(-uptime).Truncate(time.Second)
+		return nil
+	}
+
+	boot, err := c.bootTime()
+	if err != nil {
+		return err
+	}
+	c.boot = boot
+	c.anchor = now
+	c.offset = now.Sub(boot)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUptimeClock(t *testing.T) {
+	var calls int
+	clock := NewUptimeClock(func() (time.Duration, error) {
+		calls++
+		return time.Duration(calls) * time.Hour, nil
+	})
+
+	boot, err := clock.BootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), boot, 2*time.Second)
+	assert.Zero(t, boot.Nanosecond())
+
+	// The boot time is cached while the uptime comes from the source.
+	again, _ := clock.BootTime()
+	assert.Equal(t, boot, again)
+	uptime, err := clock.Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2*time.Hour, uptime)
+}
+
+func TestBootTimeClock(t *testing.T) {
+	expected := time.Now().Add(-time.Minute)
+	clock := NewBootTimeClock(func() (time.Time, error) { return expected, nil })
+
+	boot, err := clock.BootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, boot)
+
+	first, err := clock.Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.InDelta(t, time.Minute, first, float64(time.Second))
+
+	second, _ := clock.Uptime()
+	assert.True(t, second >= first)
+}
+
+func TestBootClockError(t *testing.T) {
+	clock := NewBootTimeClock(func() (time.Time, error) { return time.Time{}, errors.New("oops") })
+
+	_, err := clock.BootTime()
+	assert.Error(t, err)
+	_, err = clock.Uptime()
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"sync"
+	"time"
+)
+
+// StaticCache holds host data that rarely changes, like the OS version, the
+// machine IDs, the CPU topology, and the SMBIOS values. Providers read
+// through it so that creating a new Host on every collection cycle doesn't
+// re-read and re-parse the same files and registry keys. It is disabled until
+// a TTL is set.
+var StaticCache = &Cache{}
+
+// Cache memoizes the results of slow lookups for a configurable TTL.
+// Errors are not cached.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	gen     uint64 // Incremented when the entries are dropped.
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value  interface{}
+	stored time.Time
+}
+
+// SetTTL sets how long values are cached and drops the cached values. A TTL
+// of zero or less disables the cache.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	c.gen++
+	c.entries = nil
+}
+
+// Invalidate drops all cached values so that the next lookups read the
+// current values.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.entries = nil
+}
+
+// Get returns the cached value for key or calls fn to read it. Values are
+// shared between callers so they must not be modified.
+func (c *Cache) Get(key string, fn func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	ttl := c.ttl
+	c.mu.Unlock()
+	return c.GetTTL(key, ttl, fn)
+}
+
+// GetTTL is like Get but it uses ttl instead of the TTL of the cache. Only
+// values that were stored less than ttl ago are returned. A TTL of zero or
+// less bypasses the cache.
+func (c *Cache) GetTTL(key string, ttl time.Duration, fn func() (interface{}, error)) (interface{}, error) {
+	if ttl <= 0 {
+		return fn()
+	}
+
+	c.mu.Lock()
+	gen := c.gen
+	if e, found := c.entries[key]; found && time.Since(e.stored) < ttl {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	v, err := fn()
+	if err != nil {
+		return v, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Don't store values that were read before an invalidation.
+	if c.gen == gen {
+		if c.entries == nil {
+			c.entries = map[string]cacheEntry{}
+		}
+		c.entries[key] = cacheEntry{value: v, stored: time.Now()}
+	}
+	return v, nil
+}
+
+// CachePolicy selects the TTL that a provider uses for its StaticCache
+// lookups. The zero value uses the TTL that was set with SetTTL.
+type CachePolicy struct {
+	ttl      time.Duration
+	override bool
+}
+
+// CacheTTL returns a CachePolicy that caches values for ttl instead of the
+// TTL of StaticCache. A TTL of zero or less bypasses the cache.
+func CacheTTL(ttl time.Duration) CachePolicy {
+	return CachePolicy{ttl: ttl, override: true}
+}
+
+// Get reads key through StaticCache using the TTL of the policy.
+func (p CachePolicy) Get(key string, fn func() (interface{}, error)) (interface{}, error) {
+	if p.override {
+		return StaticCache.GetTTL(key, p.ttl, fn)
+	}
+	return StaticCache.Get(key, fn)
+}
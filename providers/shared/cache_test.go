@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var reads int
+	read := func() (interface{}, error) {
+		reads++
+		return reads, nil
+	}
+
+	c := &Cache{}
+	c.Get("k", read)
+	v, _ := c.Get("k", read)
+	assert.Equal(t, 2, v, "disabled cache must not store values")
+
+	c.SetTTL(time.Hour)
+	c.Get("k", read)
+	v, _ = c.Get("k", read)
+	assert.Equal(t, 3, v)
+
+	c.Invalidate()
+	v, _ = c.Get("k", read)
+	assert.Equal(t, 4, v)
+
+	errRead := errors.New("failed")
+	_, err := c.Get("e", func() (interface{}, error) { return nil, errRead })
+	assert.Equal(t, errRead, err)
+	v, err = c.Get("e", read)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, v, "errors must not be cached")
+
+	c.SetTTL(time.Nanosecond)
+	c.Get("k", read)
+	time.Sleep(time.Millisecond)
+	v, _ = c.Get("k", read)
+	assert.Equal(t, 7, v, "expired values must be read again")
+}
+
+func TestCacheGetTTL(t *testing.T) {
+	var reads int
+	read := func() (interface{}, error) {
+		reads++
+		return reads, nil
+	}
+
+	c := &Cache{}
+	c.GetTTL("k", time.Hour, read)
+	v, _ := c.GetTTL("k", time.Hour, read)
+	assert.Equal(t, 1, v, "per-call TTL must cache even if the cache is disabled")
+
+	v, _ = c.Get("k", read)
+	assert.Equal(t, 2, v, "disabled cache must not return values stored with a per-call TTL")
+
+	time.Sleep(time.Millisecond)
+	v, _ = c.GetTTL("k", time.Nanosecond, read)
+	assert.Equal(t, 3, v, "values older than the per-call TTL must be read again")
+
+	v, _ = c.GetTTL("k", 0, read)
+	assert.Equal(t, 4, v, "zero TTL must bypass the cache")
+	v, _ = c.GetTTL("k", time.Hour, read)
+	assert.Equal(t, 3, v, "bypassing the cache must not store values")
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ParseKernelCmdline splits a kernel command line into its parameters.
+// Parameters are separated by whitespace and a value can be double quoted to
+// include spaces (e.g. foo="a b"). The quotes are removed.
+func ParseKernelCmdline(cmdline string) []types.KernelCmdlineParam {
+	var params []types.KernelCmdlineParam
+	var word bytes.Buffer
+	var inQuote, inWord bool
+
+	flush := func() {
+		if !inWord {
+			return
+		}
+		w := word.String()
+		p := types.KernelCmdlineParam{Name: w}
+		if idx := strings.IndexByte(w, '='); idx >= 0 {
+			p.Name, p.Value = w[:idx], w[idx+1:]
+		}
+		params = append(params, p)
+		word.Reset()
+		inWord = false
+	}
+
+	for _, r := range cmdline {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			inWord = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	flush()
+	return params
+}
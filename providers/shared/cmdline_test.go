@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseKernelCmdline(t *testing.T) {
+	params := ParseKernelCmdline(`BOOT_IMAGE=/vmlinuz-5.4.0 root=UUID=1234 ro quiet dyndbg="file foo.c +p" nokaslr mitigations=off` + "\n")
+	assert.Equal(t, []types.KernelCmdlineParam{
+		{Name: "BOOT_IMAGE", Value: "/vmlinuz-5.4.0"},
+		{Name: "root", Value: "UUID=1234"},
+		{Name: "ro"},
+		{Name: "quiet"},
+		{Name: "dyndbg", Value: "file foo.c +p"},
+		{Name: "nokaslr"},
+		{Name: "mitigations", Value: "off"},
+	}, params)
+
+	info := types.KernelCmdlineInfo{Parameters: append(params, types.KernelCmdlineParam{Name: "mitigations", Value: "auto"})}
+	v, found := info.Get("mitigations")
+	assert.True(t, found)
+	assert.Equal(t, "auto", v)
+	_, found = info.Get("nosmt")
+	assert.False(t, found)
+
+	assert.Empty(t, ParseKernelCmdline(" \n"))
+}
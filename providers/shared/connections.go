@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ConnectionKindFilter returns a function that reports whether a connection
+// matches the given kind (see the types.ConnectionKind constants). The empty
+// string is treated as types.ConnectionKindAll.
+func ConnectionKindFilter(kind string) (func(types.NetworkConnection) bool, error) {
+	matchTypeFamily := func(protocol, family string) func(types.NetworkConnection) bool {
+		return func(c types.NetworkConnection) bool {
+			return c.Type == protocol && (family == "" || c.Family == family)
+		}
+	}
+
+	switch kind {
+	case "", types.ConnectionKindAll:
+		return func(types.NetworkConnection) bool { return true }, nil
+	case types.ConnectionKindInet:
+		return func(c types.NetworkConnection) bool {
+			return c.Type == types.ProtocolTCP || c.Type == types.ProtocolUDP
+		}, nil
+	case types.ConnectionKindTCP:
+		return matchTypeFamily(types.ProtocolTCP, ""), nil
+	case types.ConnectionKindTCP4:
+		return matchTypeFamily(types.ProtocolTCP, types.FamilyIPv4), nil
+	case types.ConnectionKindTCP6:
+		return matchTypeFamily(types.ProtocolTCP, types.FamilyIPv6), nil
+	case types.ConnectionKindUDP:
+		return matchTypeFamily(types.ProtocolUDP, ""), nil
+	case types.ConnectionKindUDP4:
+		return matchTypeFamily(types.ProtocolUDP, types.FamilyIPv4), nil
+	case types.ConnectionKindUDP6:
+		return matchTypeFamily(types.ProtocolUDP, types.FamilyIPv6), nil
+	case types.ConnectionKindUnix:
+		return matchTypeFamily(types.ProtocolUnix, ""), nil
+	default:
+		return nil, errors.Errorf("invalid connection kind '%v'", kind)
+	}
+}
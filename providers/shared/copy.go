@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// CopyProcessorInfo returns a deep copy of info so that callers can't modify
+// a value that is held in StaticCache.
+func CopyProcessorInfo(info *types.ProcessorInfo) *types.ProcessorInfo {
+	c := *info
+	if info.Caches != nil {
+		c.Caches = append([]types.CPUCacheInfo(nil), info.Caches...)
+	}
+	if info.Flags != nil {
+		c.Flags = append([]string(nil), info.Flags...)
+	}
+	return &c
+}
+
+// CopyOSInfo returns a deep copy of info so that callers can't modify a value
+// that is held in StaticCache.
+func CopyOSInfo(info *types.OSInfo) *types.OSInfo {
+	c := *info
+	if info.InstallDate != nil {
+		t := *info.InstallDate
+		c.InstallDate = &t
+	}
+	return &c
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestCopyProcessorInfo(t *testing.T) {
+	info := &types.ProcessorInfo{
+		Vendor: "GenuineIntel",
+		Caches: []types.CPUCacheInfo{{Level: 1, Type: "data", Size: 32 << 10}},
+		Flags:  []string{"sse4_2", "avx2"},
+	}
+	c := CopyProcessorInfo(info)
+	assert.Equal(t, info, c)
+
+	c.Caches[0].Size = 0
+	c.Flags[0] = "modified"
+	assert.EqualValues(t, 32<<10, info.Caches[0].Size)
+	assert.Equal(t, "sse4_2", info.Flags[0])
+
+	assert.Equal(t, &types.ProcessorInfo{}, CopyProcessorInfo(&types.ProcessorInfo{}))
+}
+
+func TestCopyOSInfo(t *testing.T) {
+	installed := time.Unix(1500000000, 0)
+	info := &types.OSInfo{Family: "windows", InstallDate: &installed}
+	c := CopyOSInfo(info)
+	assert.Equal(t, info, c)
+
+	*c.InstallDate = time.Time{}
+	assert.Equal(t, time.Unix(1500000000, 0), *info.InstallDate)
+}
@@ -0,0 +1,40 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// FilterEnv applies filter to an environment. Providers call it with the
+// filter that was stored on the process before returning the environment so
+// that filtered values are never returned by the API. A nil filter returns
+// the environment unchanged.
+func FilterEnv(filter types.EnvFilter, env map[string]string) map[string]string {
+	if filter == nil || env == nil {
+		return env
+	}
+
+	filtered := make(map[string]string, len(env))
+	for k, v := range env {
+		if v, keep := filter(k, v); keep {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterEnv(t *testing.T) {
+	env := map[string]string{
+		"HOME":                  "/root",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+		"DB_PASSWORD":           "hunter2",
+	}
+
+	assert.Equal(t, env, FilterEnv(nil, env))
+
+	filter := func(name, value string) (string, bool) {
+		if strings.HasPrefix(name, "AWS_") {
+			return "", false
+		}
+		if strings.HasSuffix(name, "_PASSWORD") {
+			return "[redacted]", true
+		}
+		return value, true
+	}
+
+	assert.Equal(t, map[string]string{
+		"HOME":        "/root",
+		"DB_PASSWORD": "[redacted]",
+	}, FilterEnv(filter, env))
+	assert.Equal(t, "secret", env["AWS_SECRET_ACCESS_KEY"], "input must not be modified")
+	assert.Nil(t, FilterEnv(filter, nil))
+}
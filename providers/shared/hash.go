@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// HashTypes are the algorithms computed when none are requested.
+var HashTypes = []types.HashType{types.MD5, types.SHA1, types.SHA256}
+
+// HashFile reads the file once and returns its hashes for each of the
+// algorithms.
+func HashFile(path string, algorithms ...types.HashType) (map[types.HashType]string, error) {
+	if len(algorithms) == 0 {
+		algorithms = HashTypes
+	}
+
+	hashes := make(map[types.HashType]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, 0, len(algorithms))
+	for _, algo := range algorithms {
+		if _, found := hashes[algo]; found {
+			continue
+		}
+
+		var h hash.Hash
+		switch algo {
+		case types.MD5:
+			h = md5.New()
+		case types.SHA1:
+			h = sha1.New()
+		case types.SHA256:
+			h = sha256.New()
+		default:
+			return nil, errors.Errorf("unsupported hash type %v", algo)
+		}
+		hashes[algo] = h
+		writers = append(writers, h)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, errors.Wrapf(err, "failed to hash %v", path)
+	}
+
+	sums := make(map[types.HashType]string, len(hashes))
+	for algo, h := range hashes {
+		sums[algo] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
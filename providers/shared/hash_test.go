@@ -0,0 +1,57 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestHashFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("hello world\n")
+	f.Close()
+
+	hashes, err := HashFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[types.HashType]string{
+		types.MD5:    "6f5902ac237024bdd0c176cb93063dc4",
+		types.SHA1:   "22596363b3de40b06f981fb85d82312e8c0ed511",
+		types.SHA256: "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447",
+	}, hashes)
+
+	hashes, err = HashFile(f.Name(), types.SHA1, types.SHA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, hashes, 1)
+
+	_, err = HashFile(f.Name(), "crc32")
+	assert.Error(t, err)
+}
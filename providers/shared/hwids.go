@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LookupHardwareIDs returns the vendor and device names of a PCI or USB
+// device from a pci.ids or usb.ids database (see https://pci-ids.ucw.cz and
+// http://www.linux-usb.org/usb-ids.html). The IDs are four digit lowercase
+// hex strings (e.g. 8086). The names are empty when not found.
+func LookupHardwareIDs(r io.Reader, vendorID, deviceID string) (vendor, device string) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		if line[0] != '\t' {
+			if vendor != "" {
+				// The devices of the vendor have been read.
+				return vendor, ""
+			}
+			if id, name := splitHardwareID(line); id == vendorID {
+				vendor = name
+				if deviceID == "" {
+					return vendor, ""
+				}
+			}
+			continue
+		}
+
+		// Device lines are indented by one tab and subsystems by two.
+		if vendor == "" || strings.HasPrefix(line, "\t\t") {
+			continue
+		}
+		if id, name := splitHardwareID(line[1:]); id == deviceID {
+			return vendor, name
+		}
+	}
+	return vendor, ""
+}
+
+// splitHardwareID splits "8086  Intel Corporation" into its ID and name.
+func splitHardwareID(line string) (id, name string) {
+	parts := strings.SplitN(line, "  ", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return strings.ToLower(parts[0]), strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pciIDs = `#
+#	List of PCI ID's
+#
+1002  Advanced Micro Devices, Inc. [AMD/ATI]
+	731f  Navi 10 [Radeon RX 5600 OEM/5600 XT / 5700/5700 XT]
+		1002 0b36  Radeon RX 5700 XT 50th Anniversary
+8086  Intel Corporation
+	1237  440FX - 82441FX PMC [Natoma]
+	5916  HD Graphics 620
+		17aa 2245  ThinkPad T470
+10de  NVIDIA Corporation
+
+# List of known device classes, subclasses and programming interfaces
+C 03  Display controller
+	00  VGA compatible controller
+`
+
+func TestLookupHardwareIDs(t *testing.T) {
+	vendor, device := LookupHardwareIDs(strings.NewReader(pciIDs), "8086", "5916")
+	assert.Equal(t, "Intel Corporation", vendor)
+	assert.Equal(t, "HD Graphics 620", device)
+
+	// Subsystem IDs are not device IDs.
+	vendor, device = LookupHardwareIDs(strings.NewReader(pciIDs), "1002", "0b36")
+	assert.Equal(t, "Advanced Micro Devices, Inc. [AMD/ATI]", vendor)
+	assert.Empty(t, device)
+
+	vendor, device = LookupHardwareIDs(strings.NewReader(pciIDs), "10de", "1c82")
+	assert.Equal(t, "NVIDIA Corporation", vendor)
+	assert.Empty(t, device)
+
+	vendor, device = LookupHardwareIDs(strings.NewReader(pciIDs), "ffff", "0000")
+	assert.Empty(t, vendor)
+	assert.Empty(t, device)
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ListeningPorts returns the listening TCP sockets and unconnected UDP
+// sockets from conns. The owning process of each socket is looked up once per
+// PID using the process func. Sockets are still returned when the process
+// cannot be read.
+func ListeningPorts(conns []types.NetworkConnection, process func(pid int) (types.Process, error)) []types.ListeningPort {
+	infos := map[int]*types.ProcessInfo{}
+	var ports []types.ListeningPort
+	for _, conn := range conns {
+		if !isListening(conn) {
+			continue
+		}
+
+		port := types.ListeningPort{NetworkConnection: conn}
+		if conn.PID > 0 {
+			info, found := infos[conn.PID]
+			if !found {
+				if proc, err := process(conn.PID); err == nil {
+					if pi, err := proc.Info(); err == nil || types.IsPartialInfo(err) {
+						info = &pi
+					}
+				}
+				infos[conn.PID] = info
+			}
+			port.Process = info
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+func isListening(conn types.NetworkConnection) bool {
+	switch conn.Type {
+	case types.ProtocolTCP:
+		return conn.State == types.TCPStateListen
+	case types.ProtocolUDP:
+		return conn.RemotePort == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestListeningPorts(t *testing.T) {
+	conns := []types.NetworkConnection{
+		{Type: types.ProtocolTCP, LocalIP: net.IPv4zero, LocalPort: 22, State: types.TCPStateListen, PID: 10},
+		{Type: types.ProtocolTCP, LocalIP: net.IPv4(10, 0, 0, 1), LocalPort: 22, RemoteIP: net.IPv4(10, 0, 0, 2), RemotePort: 5000, State: types.TCPStateEstablished, PID: 11},
+		{Type: types.ProtocolUDP, LocalIP: net.IPv4zero, LocalPort: 53, PID: 20},
+		{Type: types.ProtocolUDP, LocalIP: net.IPv4zero, LocalPort: 68, PID: 10},
+		{Type: types.ProtocolUDP, LocalIP: net.IPv4zero, LocalPort: 123},
+	}
+
+	var lookups int
+	process := func(pid int) (types.Process, error) {
+		lookups++
+		if pid == 20 {
+			return nil, errors.New("process exited")
+		}
+		return newFakeProcess(pid, 1, time.Time{}), nil
+	}
+
+	ports := ListeningPorts(conns, process)
+	if !assert.Len(t, ports, 4) {
+		return
+	}
+	assert.Equal(t, 2, lookups)
+
+	assert.Equal(t, 22, ports[0].LocalPort)
+	if assert.NotNil(t, ports[0].Process) {
+		assert.Equal(t, 10, ports[0].Process.PID)
+	}
+	assert.Equal(t, 53, ports[1].LocalPort)
+	assert.Nil(t, ports[1].Process)
+	assert.Equal(t, ports[0].Process, ports[2].Process)
+	assert.Nil(t, ports[3].Process)
+}
@@ -0,0 +1,35 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import "github.com/elastic/go-sysinfo/types"
+
+// FixedPointLoadAverage converts the fixed-point values of a BSD struct
+// loadavg to a LoadAverageInfo.
+func FixedPointLoadAverage(ldavg [3]uint32, fscale int64) *types.LoadAverageInfo {
+	if fscale == 0 {
+		return &types.LoadAverageInfo{}
+	}
+
+	scale := float64(fscale)
+	return &types.LoadAverageInfo{
+		One:     float64(ldavg[0]) / scale,
+		Five:    float64(ldavg[1]) / scale,
+		Fifteen: float64(ldavg[2]) / scale,
+	}
+}
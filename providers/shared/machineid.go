@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ValidSystemUUID returns false for the placeholder UUIDs that some firmware
+// reports instead of a unique value.
+func ValidSystemUUID(uuid string) bool {
+	switch strings.ToLower(uuid) {
+	case "",
+		"00000000-0000-0000-0000-000000000000",
+		"ffffffff-ffff-ffff-ffff-ffffffffffff",
+		"03000200-0400-0500-0006-000700080009":
+		return false
+	}
+	return true
+}
+
+// CombineMachineIDs returns a SHA-256 hash of all IDs. The result only
+// changes if one of its sources changes, but it also depends on which
+// sources can be read (e.g. the DMI UUID requires root on Linux).
+func CombineMachineIDs(ids []types.MachineIDInfo) types.MachineIDInfo {
+	h := sha256.New()
+	for _, id := range ids {
+		h.Write([]byte(id.Source + "=" + id.ID + "\n"))
+	}
+	return types.MachineIDInfo{
+		ID:     hex.EncodeToString(h.Sum(nil)),
+		Source: types.MachineIDSourceCombined,
+	}
+}
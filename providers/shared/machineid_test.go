@@ -0,0 +1,45 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestCombineMachineIDs(t *testing.T) {
+	ids := []types.MachineIDInfo{
+		{ID: "d0e7d6c8e2b54d9f84c6d2b4d1d7a8f1", Source: types.MachineIDSourceSystemd},
+		{ID: "4c4c4544-004b-4d10-8035-b4c04f4e4232", Source: types.MachineIDSourceDMI},
+	}
+
+	combined := CombineMachineIDs(ids)
+	assert.Equal(t, types.MachineIDSourceCombined, combined.Source)
+	assert.Len(t, combined.ID, 64)
+	assert.Equal(t, combined, CombineMachineIDs(ids))
+	assert.NotEqual(t, combined, CombineMachineIDs(ids[:1]))
+}
+
+func TestValidSystemUUID(t *testing.T) {
+	assert.True(t, ValidSystemUUID("4c4c4544-004b-4d10-8035-b4c04f4e4232"))
+	assert.False(t, ValidSystemUUID("FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF"))
+	assert.False(t, ValidSystemUUID("03000200-0400-0500-0006-000700080009"))
+}
@@ -19,6 +19,9 @@ package shared
 
 import (
 	"net"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
 )
 
 func Network() (ips, macs []string, err error) {
@@ -46,3 +49,52 @@ func Network() (ips, macs []string, err error) {
 
 	return ips, macs, nil
 }
+
+// NetworkInterfaces returns the configuration of the network interfaces. The
+// Counters are left for the platform provider to populate.
+func NetworkInterfaces() ([]types.NetworkInterfaceInfo, error) {
+	ifcs, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]types.NetworkInterfaceInfo, 0, len(ifcs))
+	for _, ifc := range ifcs {
+		info := types.NetworkInterfaceInfo{
+			Index: ifc.Index,
+			Name:  ifc.Name,
+			MTU:   ifc.MTU,
+			MAC:   ifc.HardwareAddr.String(),
+		}
+		if ifc.Flags != 0 {
+			info.Flags = strings.Split(ifc.Flags.String(), "|")
+		}
+
+		addrs, err := ifc.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			info.Addresses = append(info.Addresses, addr.String())
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// InterfaceNames returns the names of the network interfaces by index. It
+// returns an empty map when the interfaces cannot be listed so that callers
+// can still report entries without names.
+func InterfaceNames() map[int]string {
+	names := map[int]string{}
+	ifcs, err := net.Interfaces()
+	if err != nil {
+		return names
+	}
+	for _, ifc := range ifcs {
+		names[ifc.Index] = ifc.Name
+	}
+	return names
+}
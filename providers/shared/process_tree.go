@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Children returns the processes from procs whose parent is the given
+// process. Processes whose info cannot be read (e.g. because they exited or
+// due to missing permissions) are ignored.
+func Children(procs []types.Process, parent types.Process) ([]types.Process, error) {
+	parentInfo, err := parent.Info()
+	if err != nil && !types.IsPartialInfo(err) {
+		return nil, err
+	}
+
+	var children []types.Process
+	for _, p := range procs {
+		info, err := p.Info()
+		if err != nil && !types.IsPartialInfo(err) {
+			continue
+		}
+		if isChild(parentInfo, info) {
+			children = append(children, p)
+		}
+	}
+	return children, nil
+}
+
+// Ancestors returns the chain of parents of the given process from a snapshot
+// of all processes, ordered from the direct parent to the root of the tree
+// (e.g. init or System). The walk stops at the first parent that is missing
+// from the snapshot or whose PID was reused by a process that started after
+// its child.
+func Ancestors(procs []types.Process, p types.Process) ([]types.Process, error) {
+	info, err := p.Info()
+	if err != nil && !types.IsPartialInfo(err) {
+		return nil, err
+	}
+
+	byPID := make(map[int]types.Process, len(procs))
+	for _, proc := range procs {
+		byPID[proc.PID()] = proc
+	}
+
+	var ancestors []types.Process
+	visited := map[int]struct{}{info.PID: {}}
+	for info.PPID > 0 {
+		parent, found := byPID[info.PPID]
+		if !found {
+			break
+		}
+		if _, found = visited[info.PPID]; found {
+			break
+		}
+		visited[info.PPID] = struct{}{}
+
+		parentInfo, err := parent.Info()
+		if err != nil && !types.IsPartialInfo(err) {
+			break
+		}
+		if !isChild(parentInfo, info) {
+			break
+		}
+		ancestors = append(ancestors, parent)
+		info = parentInfo
+	}
+	return ancestors, nil
+}
+
+// ProcessTree builds the tree of descendants of the process with the given
+// PID from a snapshot of all processes. Processes whose info cannot be read
+// are ignored.
+func ProcessTree(procs []types.Process, pid int) (*types.ProcessTreeNode, error) {
+	var root *types.ProcessTreeNode
+	nodes := make([]*types.ProcessTreeNode, 0, len(procs))
+	for _, p := range procs {
+		info, err := p.Info()
+		if err != nil && !types.IsPartialInfo(err) {
+			continue
+		}
+
+		node := &types.ProcessTreeNode{Process: p, Info: info}
+		if info.PID == pid {
+			root = node
+		}
+		nodes = append(nodes, node)
+	}
+	if root == nil {
+		return nil, &types.ProcessNotFoundError{PID: pid}
+	}
+
+	byPPID := map[int][]*types.ProcessTreeNode{}
+	for _, n := range nodes {
+		byPPID[n.Info.PPID] = append(byPPID[n.Info.PPID], n)
+	}
+
+	// Walk the tree iteratively. The visited set guards against cycles such
+	// as a process that reports itself as its parent.
+	visited := map[int]struct{}{root.Info.PID: {}}
+	queue := []*types.ProcessTreeNode{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		for _, child := range byPPID[parent.Info.PID] {
+			if _, found := visited[child.Info.PID]; found || !isChild(parent.Info, child.Info) {
+				continue
+			}
+			visited[child.Info.PID] = struct{}{}
+			parent.Children = append(parent.Children, child)
+			queue = append(queue, child)
+		}
+	}
+	return root, nil
+}
+
+// isChild returns true if child is a child of parent. A child that started
+// before its parent has a reused parent PID and is not a child.
+func isChild(parent, child types.ProcessInfo) bool {
+	if child.PPID != parent.PID || child.PID == parent.PID {
+		return false
+	}
+	if !parent.StartTime.IsZero() && !child.StartTime.IsZero() && child.StartTime.Before(parent.StartTime) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+type fakeProcess struct {
+	types.Process
+	info types.ProcessInfo
+	err  error
+}
+
+func (p *fakeProcess) PID() int                         { return p.info.PID }
+func (p *fakeProcess) Info() (types.ProcessInfo, error) { return p.info, p.err }
+
+func newFakeProcess(pid, ppid int, start time.Time) *fakeProcess {
+	return &fakeProcess{info: types.ProcessInfo{PID: pid, PPID: ppid, StartTime: start}}
+}
+
+func pids(nodes []*types.ProcessTreeNode) []int {
+	var out []int
+	for _, n := range nodes {
+		out = append(out, n.Info.PID)
+	}
+	return out
+}
+
+func TestProcessTree(t *testing.T) {
+	boot := time.Now().Add(-time.Hour)
+	procs := []types.Process{
+		newFakeProcess(1, 0, boot),
+		newFakeProcess(10, 1, boot.Add(time.Second)),
+		newFakeProcess(11, 10, boot.Add(2*time.Second)),
+		newFakeProcess(12, 10, boot.Add(3*time.Second)),
+		newFakeProcess(20, 1, boot.Add(4*time.Second)),
+		// Orphan whose parent PID was reused by process 20.
+		newFakeProcess(21, 20, boot),
+	}
+
+	tree, err := ProcessTree(procs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{10, 20}, pids(tree.Children))
+	assert.Equal(t, []int{11, 12}, pids(tree.Children[0].Children))
+	assert.Empty(t, tree.Children[1].Children)
+
+	_, err = ProcessTree(procs, 99)
+	assert.Error(t, err)
+
+	children, err := Children(procs, procs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.Process{procs[2], procs[3]}, children)
+}
+
+func TestProcessTreeCycle(t *testing.T) {
+	procs := []types.Process{
+		newFakeProcess(0, 0, time.Time{}),
+		newFakeProcess(1, 0, time.Time{}),
+	}
+
+	tree, err := ProcessTree(procs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{1}, pids(tree.Children))
+}
+
+func TestProcessTreePartialInfo(t *testing.T) {
+	partial := &types.PartialInfoError{}
+	partial.Add("exe", errors.New("permission denied"))
+
+	child := newFakeProcess(2, 1, time.Time{})
+	child.err = partial
+	failed := newFakeProcess(3, 1, time.Time{})
+	failed.err = errors.New("process exited")
+	procs := []types.Process{newFakeProcess(1, 0, time.Time{}), child, failed}
+
+	tree, err := ProcessTree(procs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{2}, pids(tree.Children))
+	assert.Equal(t, []string{"exe"}, partial.Fields())
+	assert.True(t, types.IsPartialInfo(partial))
+	assert.False(t, types.IsPartialInfo(failed.err))
+}
+
+func TestAncestors(t *testing.T) {
+	boot := time.Now().Add(-time.Hour)
+	procs := []types.Process{
+		newFakeProcess(1, 0, boot),
+		newFakeProcess(10, 1, boot.Add(time.Second)),
+		newFakeProcess(11, 10, boot.Add(2*time.Second)),
+		newFakeProcess(12, 11, boot.Add(3*time.Second)),
+		// Orphan whose parent PID was reused by process 11.
+		newFakeProcess(13, 11, boot),
+		// Processes that report each other as their parent.
+		newFakeProcess(30, 31, boot),
+		newFakeProcess(31, 30, boot),
+	}
+
+	ancestors, err := Ancestors(procs, procs[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.Process{procs[2], procs[1], procs[0]}, ancestors)
+
+	ancestors, err = Ancestors(procs, procs[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, ancestors)
+
+	ancestors, err = Ancestors(procs, procs[5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.Process{procs[6]}, ancestors)
+
+	ancestors, err = Ancestors(procs, procs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, ancestors)
+}
@@ -0,0 +1,106 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ProcessPollInterval is the interval used by providers that fall back to
+// polling for process events.
+const ProcessPollInterval = time.Second
+
+// PollProcesses detects process start and exit events by comparing the PIDs
+// returned by list at the given interval. Processes that start and exit
+// within one interval are not reported. The channel is closed when the
+// context is done.
+func PollProcesses(ctx context.Context, list func() ([]types.Process, error), interval time.Duration) (<-chan types.ProcessEvent, error) {
+	current, err := pidSet(list)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan types.ProcessEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			next, err := pidSet(list)
+			if err != nil {
+				// Try again on the next tick.
+				continue
+			}
+
+			now := time.Now()
+			for pid := range current {
+				if _, found := next[pid]; !found {
+					if !send(ctx, events, types.ProcessEvent{Type: types.ProcessEventExit, PID: pid, Time: now}) {
+						return
+					}
+				}
+			}
+			for pid, p := range next {
+				if _, found := current[pid]; !found {
+					event := types.ProcessEvent{Type: types.ProcessEventStart, PID: pid, Time: now}
+					if info, err := p.Info(); err == nil || types.IsPartialInfo(err) {
+						event.PPID = info.PPID
+					}
+					if !send(ctx, events, event) {
+						return
+					}
+				}
+			}
+			current = next
+		}
+	}()
+	return events, nil
+}
+
+func pidSet(list func() ([]types.Process, error)) (map[int]types.Process, error) {
+	procs, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make(map[int]types.Process, len(procs))
+	for _, p := range procs {
+		pids[p.PID()] = p
+	}
+	return pids, nil
+}
+
+func send(ctx context.Context, events chan<- types.ProcessEvent, event types.ProcessEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestPollProcesses(t *testing.T) {
+	var mu sync.Mutex
+	procs := []types.Process{
+		newFakeProcess(1, 0, time.Time{}),
+		newFakeProcess(2, 1, time.Time{}),
+	}
+	list := func() ([]types.Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return procs, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := PollProcesses(ctx, list, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	procs = []types.Process{
+		newFakeProcess(1, 0, time.Time{}),
+		newFakeProcess(3, 1, time.Time{}),
+	}
+	mu.Unlock()
+
+	var got []types.ProcessEvent
+	for len(got) < 2 {
+		e := <-events
+		e.Time = time.Time{}
+		got = append(got, e)
+	}
+	assert.Equal(t, []types.ProcessEvent{
+		{Type: types.ProcessEventExit, PID: 2},
+		{Type: types.ProcessEventStart, PID: 3, PPID: 1},
+	}, got)
+
+	cancel()
+	for range events {
+	}
+}
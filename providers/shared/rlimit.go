@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin linux
+
+package shared
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// RLimits reads the resource limits of the current process with
+// getrlimit(2). resources maps resource names to the RLIMIT_* values of the
+// OS and infinity is the OS's RLIM_INFINITY.
+func RLimits(resources map[string]int, infinity uint64) (map[string]types.RLimit, error) {
+	limits := make(map[string]types.RLimit, len(resources))
+	for name, resource := range resources {
+		var rlim syscall.Rlimit
+		if err := syscall.Getrlimit(resource, &rlim); err != nil {
+			return nil, errors.Wrapf(err, "getrlimit failed for %v", name)
+		}
+		limits[name] = toRLimit(rlim, infinity)
+	}
+	return limits, nil
+}
+
+// RaiseOpenFileLimit raises the soft limit on open files of the current
+// process to the hard limit or to max if that is lower.
+func RaiseOpenFileLimit(max, infinity uint64) (types.RLimit, error) {
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		return types.RLimit{}, errors.Wrap(err, "getrlimit failed")
+	}
+
+	target := rlim.Max
+	if target > max {
+		target = max
+	}
+	if rlim.Cur < target {
+		rlim.Cur = target
+		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+			return types.RLimit{}, errors.Wrap(err, "setrlimit failed")
+		}
+	}
+	return toRLimit(rlim, infinity), nil
+}
+
+func toRLimit(rlim syscall.Rlimit, infinity uint64) types.RLimit {
+	limit := types.RLimit{Soft: rlim.Cur, Hard: rlim.Max}
+	if limit.Soft == infinity {
+		limit.Soft = types.RLimitInfinity
+	}
+	if limit.Hard == infinity {
+		limit.Hard = types.RLimitInfinity
+	}
+	return limit
+}
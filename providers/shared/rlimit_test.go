@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build darwin linux
+
+package shared
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestRLimits(t *testing.T) {
+	limits, err := RLimits(map[string]int{types.RLimitNoFile: syscall.RLIMIT_NOFILE}, types.RLimitInfinity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.RLimit{Soft: rlim.Cur, Hard: rlim.Max}, limits[types.RLimitNoFile])
+}
+
+func TestRaiseOpenFileLimit(t *testing.T) {
+	var before syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &before); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Setrlimit(syscall.RLIMIT_NOFILE, &before)
+
+	limit, err := RaiseOpenFileLimit(before.Cur+1, types.RLimitInfinity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Cur < before.Max {
+		assert.Equal(t, before.Cur+1, limit.Soft)
+	} else {
+		assert.Equal(t, before.Cur, limit.Soft)
+	}
+}
+
+func TestToRLimit(t *testing.T) {
+	const darwinInfinity = 1<<63 - 1
+	limit := toRLimit(syscall.Rlimit{Cur: 256, Max: darwinInfinity}, darwinInfinity)
+	assert.Equal(t, types.RLimit{Soft: 256, Hard: types.RLimitInfinity}, limit)
+}
@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// SMARTDataSize is the size of the ATA SMART data structure and of the NVMe
+// SMART / Health Information log page.
+const SMARTDataSize = 512
+
+// ATA SMART attribute IDs.
+const (
+	ataAttrReallocatedSectors = 5
+	ataAttrPowerOnHours       = 9
+	ataAttrAirflowTemperature = 190
+	ataAttrTemperature        = 194
+)
+
+// ATA SMART command values (ATA8-ACS).
+const (
+	ATASMARTCommand      = 0xb0 // SMART
+	ATASMARTReadData     = 0xd0 // SMART READ DATA feature.
+	ATASMARTReturnStatus = 0xda // SMART RETURN STATUS feature.
+	ATASMARTLBAMid       = 0x4f // Signature in the LBA mid register.
+	ATASMARTLBAHigh      = 0xc2 // Signature in the LBA high register.
+
+	ataSMARTFailLBAMid  = 0xf4
+	ataSMARTFailLBAHigh = 0x2c
+)
+
+// ParseATASMARTData reads the temperature, power-on hours, and reallocated
+// sector count from the vendor specific attribute table of the SMART READ
+// DATA response.
+func ParseATASMARTData(data []byte, info *types.DiskHealthInfo) error {
+	if len(data) < SMARTDataSize {
+		return errors.New("ATA SMART data is truncated")
+	}
+
+	// 30 attributes of 12 bytes start at offset 2. Each is made of an ID,
+	// flags (2 bytes), current and worst normalized values, a 6 byte raw
+	// value, and a reserved byte.
+	for i := 0; i < 30; i++ {
+		attr := data[2+i*12 : 2+(i+1)*12]
+		raw := attr[5:11]
+		switch attr[0] {
+		case ataAttrReallocatedSectors:
+			v := uint64(binary.LittleEndian.Uint32(raw)) | uint64(binary.LittleEndian.Uint16(raw[4:]))<<32
+			info.ReallocatedSectors = &v
+		case ataAttrPowerOnHours:
+			// Several vendors use the upper bytes for minutes or milliseconds.
+			v := uint64(binary.LittleEndian.Uint32(raw))
+			info.PowerOnHours = &v
+		case ataAttrTemperature, ataAttrAirflowTemperature:
+			// The lowest byte is the current temperature. The others may
+			// hold the minimum and maximum.
+			if attr[0] == ataAttrAirflowTemperature && info.Temperature != nil {
+				continue
+			}
+			v := float64(raw[0])
+			info.Temperature = &v
+		}
+	}
+	return nil
+}
+
+// ATASMARTStatus converts the LBA mid and high registers returned by SMART
+// RETURN STATUS to a health status. It returns an empty string if the
+// registers do not contain a valid signature.
+func ATASMARTStatus(lbaMid, lbaHigh byte) string {
+	switch {
+	case lbaMid == ATASMARTLBAMid && lbaHigh == ATASMARTLBAHigh:
+		return types.DiskHealthPassed
+	case lbaMid == ataSMARTFailLBAMid && lbaHigh == ataSMARTFailLBAHigh:
+		return types.DiskHealthFailing
+	}
+	return ""
+}
+
+// NVMeSMARTLogPage is the identifier of the SMART / Health Information log.
+const NVMeSMARTLogPage = 0x02
+
+// ParseNVMeSMARTLog parses the NVMe SMART / Health Information log page.
+// The drive is reported as failing when any critical warning flag is set.
+func ParseNVMeSMARTLog(data []byte, info *types.DiskHealthInfo) error {
+	if len(data) < SMARTDataSize {
+		return errors.New("NVMe SMART log is truncated")
+	}
+
+	info.CriticalWarning = data[0]
+	info.Status = types.DiskHealthPassed
+	if info.CriticalWarning != 0 {
+		info.Status = types.DiskHealthFailing
+	}
+
+	// The composite temperature is in Kelvin.
+	if kelvin := binary.LittleEndian.Uint16(data[1:]); kelvin != 0 {
+		v := float64(int(kelvin) - 273)
+		info.Temperature = &v
+	}
+
+	spare, used := uint64(data[3]), uint64(data[5])
+	info.AvailableSpare, info.PercentageUsed = &spare, &used
+
+	// Power on hours is a 128-bit counter. Only the lower 64 bits are read.
+	hours := binary.LittleEndian.Uint64(data[128:])
+	info.PowerOnHours = &hours
+	return nil
+}
@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseATASMARTData(t *testing.T) {
+	data := make([]byte, SMARTDataSize)
+	attr := func(i int, id byte, raw ...byte) {
+		data[2+i*12] = id
+		copy(data[2+i*12+5:], raw)
+	}
+	attr(0, 1, 0, 0)
+	attr(1, ataAttrReallocatedSectors, 8, 1)
+	attr(2, ataAttrPowerOnHours, 0x10, 0x27)
+	attr(3, ataAttrTemperature, 36, 0, 18, 0, 52)
+	attr(4, ataAttrAirflowTemperature, 40)
+
+	var info types.DiskHealthInfo
+	if err := ParseATASMARTData(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, info.ReallocatedSectors) {
+		assert.EqualValues(t, 264, *info.ReallocatedSectors)
+	}
+	if assert.NotNil(t, info.PowerOnHours) {
+		assert.EqualValues(t, 10000, *info.PowerOnHours)
+	}
+	if assert.NotNil(t, info.Temperature) {
+		assert.EqualValues(t, 36, *info.Temperature)
+	}
+
+	assert.Error(t, ParseATASMARTData(data[:100], &info))
+}
+
+func TestATASMARTStatus(t *testing.T) {
+	assert.Equal(t, types.DiskHealthPassed, ATASMARTStatus(0x4f, 0xc2))
+	assert.Equal(t, types.DiskHealthFailing, ATASMARTStatus(0xf4, 0x2c))
+	assert.Empty(t, ATASMARTStatus(0, 0))
+}
+
+func TestParseNVMeSMARTLog(t *testing.T) {
+	data := make([]byte, SMARTDataSize)
+	data[0] = 0x04 // NVM subsystem reliability degraded.
+	binary.LittleEndian.PutUint16(data[1:], 310)
+	data[3] = 100
+	data[5] = 7
+	binary.LittleEndian.PutUint64(data[128:], 1234)
+
+	var info types.DiskHealthInfo
+	if err := ParseNVMeSMARTLog(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.DiskHealthFailing, info.Status)
+	assert.EqualValues(t, 4, info.CriticalWarning)
+	assert.EqualValues(t, 37, *info.Temperature)
+	assert.EqualValues(t, 100, *info.AvailableSpare)
+	assert.EqualValues(t, 7, *info.PercentageUsed)
+	assert.EqualValues(t, 1234, *info.PowerOnHours)
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// SMBIOS structure types.
+const (
+	smbiosTypeBIOS       = 0
+	smbiosTypeSystem     = 1
+	smbiosTypeChassis    = 3
+	smbiosTypeEndOfTable = 127
+)
+
+// chassisTypes are the names of the chassis types from the SMBIOS
+// specification (DSP0134) indexed by their value.
+var chassisTypes = []string{
+	1:  "Other",
+	2:  "Unknown",
+	3:  "Desktop",
+	4:  "Low Profile Desktop",
+	5:  "Pizza Box",
+	6:  "Mini Tower",
+	7:  "Tower",
+	8:  "Portable",
+	9:  "Laptop",
+	10: "Notebook",
+	11: "Hand Held",
+	12: "Docking Station",
+	13: "All in One",
+	14: "Sub Notebook",
+	15: "Space-saving",
+	16: "Lunch Box",
+	17: "Main Server Chassis",
+	18: "Expansion Chassis",
+	19: "Sub Chassis",
+	20: "Bus Expansion Chassis",
+	21: "Peripheral Chassis",
+	22: "RAID Chassis",
+	23: "Rack Mount Chassis",
+	24: "Sealed-case PC",
+	25: "Multi-system Chassis",
+	26: "Compact PCI",
+	27: "Advanced TCA",
+	28: "Blade",
+	29: "Blade Enclosure",
+	30: "Tablet",
+	31: "Convertible",
+	32: "Detachable",
+	33: "IoT Gateway",
+	34: "Embedded PC",
+	35: "Mini PC",
+	36: "Stick PC",
+}
+
+// ChassisType returns the name of an SMBIOS chassis type value. The most
+// significant bit (chassis lock present) is ignored.
+func ChassisType(value int) string {
+	value &= 0x7f
+	if value > 0 && value < len(chassisTypes) {
+		return chassisTypes[value]
+	}
+	return chassisTypes[2]
+}
+
+// ParseSMBIOS extracts the BIOS, system, and chassis information from a raw
+// SMBIOS structure table. The version is the SMBIOS major and minor version;
+// it determines the byte order of the UUID.
+func ParseSMBIOS(table []byte, major, minor int) (*types.HardwareInfo, error) {
+	hw := &types.HardwareInfo{}
+	for len(table) >= 4 {
+		typ, length := table[0], int(table[1])
+		if length < 4 || length > len(table) {
+			return nil, errors.Errorf("invalid SMBIOS structure length %d", length)
+		}
+		formatted := table[:length]
+
+		// The formatted area is followed by a set of strings that is
+		// terminated by two NUL bytes.
+		end := bytes.Index(table[length:], []byte{0, 0})
+		if end < 0 {
+			return nil, errors.New("unterminated SMBIOS string set")
+		}
+		strs := bytes.Split(table[length:length+end], []byte{0})
+		str := func(offset int) string {
+			if offset >= len(formatted) {
+				return ""
+			}
+			i := int(formatted[offset])
+			if i == 0 || i > len(strs) {
+				return ""
+			}
+			return string(bytes.TrimSpace(strs[i-1]))
+		}
+
+		switch typ {
+		case smbiosTypeBIOS:
+			hw.BIOSVendor = str(0x04)
+			hw.BIOSVersion = str(0x05)
+			hw.BIOSDate = str(0x08)
+		case smbiosTypeSystem:
+			hw.Manufacturer = str(0x04)
+			hw.ProductName = str(0x05)
+			hw.ProductVersion = str(0x06)
+			hw.SerialNumber = str(0x07)
+			if len(formatted) >= 0x18 {
+				hw.UUID = smbiosUUID(formatted[0x08:0x18], major, minor)
+			}
+		case smbiosTypeChassis:
+			if len(formatted) > 0x05 {
+				hw.ChassisType = ChassisType(int(formatted[0x05]))
+			}
+		case smbiosTypeEndOfTable:
+			return hw, nil
+		}
+
+		table = table[length+end+2:]
+	}
+	return hw, nil
+}
+
+// smbiosUUID formats a system UUID. Since SMBIOS 2.6 the first three fields
+// are encoded in little-endian. It returns an empty string when the UUID is
+// not set (all zeros) or not present (all ones).
+func smbiosUUID(b []byte, major, minor int) string {
+	if bytes.Equal(b, make([]byte, 16)) || bytes.Equal(b, bytes.Repeat([]byte{0xff}, 16)) {
+		return ""
+	}
+
+	if major > 2 || (major == 2 && minor >= 6) {
+		return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%x-%x",
+			b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8:10], b[10:])
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
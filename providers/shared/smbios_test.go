@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func smbiosStructure(formatted []byte, strs ...string) []byte {
+	b := append([]byte{}, formatted...)
+	b[1] = byte(len(formatted))
+	for _, s := range strs {
+		b = append(b, s...)
+		b = append(b, 0)
+	}
+	if len(strs) == 0 {
+		b = append(b, 0)
+	}
+	return append(b, 0)
+}
+
+func TestParseSMBIOS(t *testing.T) {
+	var table []byte
+
+	// BIOS information (type 0).
+	bios := make([]byte, 0x12)
+	bios[0] = 0
+	bios[0x04], bios[0x05], bios[0x08] = 1, 2, 3
+	table = append(table, smbiosStructure(bios, "Dell Inc.", "2.4.3", "03/17/2017")...)
+
+	// System information (type 1).
+	system := make([]byte, 0x1b)
+	system[0] = 1
+	system[0x04], system[0x05], system[0x06], system[0x07] = 1, 2, 0, 3
+	copy(system[0x08:], []byte{
+		0x44, 0x45, 0x4c, 0x4c, 0x4b, 0x00, 0x10, 0x4d,
+		0x80, 0x35, 0xb4, 0xc0, 0x4f, 0x4e, 0x42, 0x32,
+	})
+	table = append(table, smbiosStructure(system, "Dell Inc.", "PowerEdge R740 ", "ABC1234")...)
+
+	// Chassis information (type 3) with the lock bit set.
+	chassis := make([]byte, 0x0d)
+	chassis[0] = 3
+	chassis[0x05] = 0x80 | 23
+	table = append(table, smbiosStructure(chassis)...)
+
+	// End of table (type 127).
+	table = append(table, smbiosStructure([]byte{127, 0, 0, 0})...)
+
+	hw, err := ParseSMBIOS(table, 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &types.HardwareInfo{
+		Manufacturer: "Dell Inc.",
+		ProductName:  "PowerEdge R740",
+		SerialNumber: "ABC1234",
+		UUID:         "4c4c4544-004b-4d10-8035-b4c04f4e4232",
+		BIOSVendor:   "Dell Inc.",
+		BIOSVersion:  "2.4.3",
+		BIOSDate:     "03/17/2017",
+		ChassisType:  "Rack Mount Chassis",
+	}, hw)
+
+	_, err = ParseSMBIOS([]byte{1, 0x1b, 0, 0, 1}, 3, 0)
+	assert.Error(t, err)
+}
+
+func TestChassisType(t *testing.T) {
+	assert.Equal(t, "Notebook", ChassisType(10))
+	assert.Equal(t, "Unknown", ChassisType(0))
+	assert.Equal(t, "Unknown", ChassisType(99))
+}
@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FormatSysctl formats a raw sysctl value according to its format string as
+// returned by the oidfmt (0.4) meta sysctl of macOS and FreeBSD. Numbers are
+// formatted in decimal and arrays are separated by spaces. Opaque values
+// (e.g. structs) are not supported.
+func FormatSysctl(format string, data []byte) (string, error) {
+	if format == "A" {
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			data = data[:i]
+		}
+		return string(data), nil
+	}
+
+	var size int
+	var signed bool
+	switch {
+	case format == "C":
+		size, signed = 1, true
+	case format == "CU":
+		size = 1
+	case format == "S":
+		size, signed = 2, true
+	case format == "SU":
+		size = 2
+	case format == "I" || strings.HasPrefix(format, "IK"):
+		size, signed = 4, true
+	case format == "IU":
+		size = 4
+	case format == "L" || format == "Q":
+		size, signed = 8, true
+	case format == "LU" || format == "QU":
+		size = 8
+	default:
+		return "", errors.Errorf("unsupported sysctl format %q", format)
+	}
+	if len(data) == 0 || len(data)%size != 0 {
+		return "", errors.Errorf("unexpected size %d for sysctl format %q", len(data), format)
+	}
+
+	values := make([]string, 0, len(data)/size)
+	for ; len(data) > 0; data = data[size:] {
+		var v uint64
+		switch size {
+		case 1:
+			v = uint64(data[0])
+			if signed {
+				v = uint64(int8(data[0]))
+			}
+		case 2:
+			v = uint64(binary.LittleEndian.Uint16(data))
+			if signed {
+				v = uint64(int16(v))
+			}
+		case 4:
+			v = uint64(binary.LittleEndian.Uint32(data))
+			if signed {
+				v = uint64(int32(v))
+			}
+		case 8:
+			v = binary.LittleEndian.Uint64(data)
+		}
+
+		if signed {
+			values = append(values, strconv.FormatInt(int64(v), 10))
+		} else {
+			values = append(values, strconv.FormatUint(v, 10))
+		}
+	}
+	return strings.Join(values, " "), nil
+}
@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSysctl(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		data   []byte
+		value  string
+	}{
+		{"A", []byte("FreeBSD\x00"), "FreeBSD"},
+		{"I", []byte{0xff, 0xff, 0xff, 0xff}, "-1"},
+		{"IU", []byte{0xff, 0xff, 0xff, 0xff}, "4294967295"},
+		{"IK", []byte{0x6c, 0x0b, 0, 0}, "2924"},
+		{"I", []byte{1, 0, 0, 0, 2, 0, 0, 0}, "1 2"},
+		{"LU", []byte{0, 0, 0, 0, 1, 0, 0, 0}, "4294967296"},
+		{"CU", []byte{200}, "200"},
+		{"S", []byte{0xfe, 0xff}, "-2"},
+	} {
+		value, err := FormatSysctl(tc.format, tc.data)
+		if assert.NoError(t, err, tc.format) {
+			assert.Equal(t, tc.value, value, tc.format)
+		}
+	}
+
+	_, err := FormatSysctl("S,clockinfo", make([]byte, 20))
+	assert.Error(t, err)
+	_, err = FormatSysctl("I", []byte{1, 2})
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Constants from the TPM 2.0 Library specification, Part 2.
+const (
+	tpm2STNoSessions    = 0x8001     // TPM_ST_NO_SESSIONS
+	tpm2CCGetCapability = 0x0000017a // TPM_CC_GetCapability
+	tpm2CapProperties   = 0x00000006 // TPM_CAP_TPM_PROPERTIES
+
+	tpm2PTManufacturer   = 0x105 // TPM_PT_MANUFACTURER
+	tpm2PTFirmware1      = 0x10b // TPM_PT_FIRMWARE_VERSION_1
+	tpm2PTFirmware2      = 0x10c // TPM_PT_FIRMWARE_VERSION_2
+	tpm2PTPermanent      = 0x200 // TPM_PT_PERMANENT
+	tpm2PTStartupClear   = 0x201 // TPM_PT_STARTUP_CLEAR
+	tpm2OwnerAuthSet     = 1 << 0
+	tpm2StorageHierarchy = 1 << 1 // shEnable
+
+	tpm2ResponseHdrLen = 10
+)
+
+// TPMSubmitFunc sends a command to the TPM and returns the response.
+type TPMSubmitFunc func(command []byte) ([]byte, error)
+
+// TPM2Query reads the manufacturer, firmware version, and the owner and
+// storage hierarchy state of a TPM 2.0 into info using TPM2_GetCapability.
+func TPM2Query(submit TPMSubmitFunc, info *types.TPMInfo) error {
+	props, err := tpm2Properties(submit, tpm2PTManufacturer, tpm2PTFirmware2-tpm2PTManufacturer+1)
+	if err != nil {
+		return err
+	}
+	info.Manufacturer = TPMManufacturer(props[tpm2PTManufacturer])
+	fw1, fw2 := props[tpm2PTFirmware1], props[tpm2PTFirmware2]
+	info.FirmwareVersion = fmt.Sprintf("%d.%d.%d.%d", fw1>>16, fw1&0xffff, fw2>>16, fw2&0xffff)
+
+	props, err = tpm2Properties(submit, tpm2PTPermanent, 2)
+	if err != nil {
+		return err
+	}
+	if v, found := props[tpm2PTPermanent]; found {
+		owned := v&tpm2OwnerAuthSet != 0
+		info.Owned = &owned
+	}
+	if v, found := props[tpm2PTStartupClear]; found {
+		enabled := v&tpm2StorageHierarchy != 0
+		info.Enabled = &enabled
+	}
+	return nil
+}
+
+func tpm2Properties(submit TPMSubmitFunc, property, count uint32) (map[uint32]uint32, error) {
+	resp, err := submit(tpm2GetCapabilityCommand(property, count))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to submit TPM2_GetCapability")
+	}
+	return parseTPM2Properties(resp)
+}
+
+// tpm2GetCapabilityCommand returns a TPM2_GetCapability command that reads
+// count TPM properties starting at property.
+func tpm2GetCapabilityCommand(property, count uint32) []byte {
+	cmd := make([]byte, 22)
+	binary.BigEndian.PutUint16(cmd[0:], tpm2STNoSessions)
+	binary.BigEndian.PutUint32(cmd[2:], uint32(len(cmd)))
+	binary.BigEndian.PutUint32(cmd[6:], tpm2CCGetCapability)
+	binary.BigEndian.PutUint32(cmd[10:], tpm2CapProperties)
+	binary.BigEndian.PutUint32(cmd[14:], property)
+	binary.BigEndian.PutUint32(cmd[18:], count)
+	return cmd
+}
+
+// parseTPM2Properties parses the TPML_TAGGED_TPM_PROPERTY of a
+// TPM2_GetCapability response.
+func parseTPM2Properties(resp []byte) (map[uint32]uint32, error) {
+	if len(resp) < tpm2ResponseHdrLen {
+		return nil, errors.New("TPM response is truncated")
+	}
+	if rc := binary.BigEndian.Uint32(resp[6:]); rc != 0 {
+		return nil, errors.Errorf("TPM2_GetCapability failed with response code 0x%x", rc)
+	}
+
+	// moreData (1 byte), capability (4 bytes), and count (4 bytes).
+	body := resp[tpm2ResponseHdrLen:]
+	if len(body) < 9 {
+		return nil, errors.New("TPM2_GetCapability response is truncated")
+	}
+	count := int(binary.BigEndian.Uint32(body[5:]))
+	body = body[9:]
+
+	props := make(map[uint32]uint32, count)
+	for i := 0; i < count && len(body) >= 8; i++ {
+		props[binary.BigEndian.Uint32(body)] = binary.BigEndian.Uint32(body[4:])
+		body = body[8:]
+	}
+	return props, nil
+}
+
+// TPMManufacturer converts a TCG vendor ID such as 0x49465800 to its ASCII
+// representation ("IFX").
+func TPMManufacturer(id uint32) string {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, id)
+	return strings.TrimRight(string(b), "\x00 ")
+}
@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// tpm2PropertiesResponse returns a successful TPM2_GetCapability response.
+func tpm2PropertiesResponse(props ...uint32) []byte {
+	resp := make([]byte, tpm2ResponseHdrLen+9)
+	binary.BigEndian.PutUint16(resp, tpm2STNoSessions)
+	binary.BigEndian.PutUint32(resp[tpm2ResponseHdrLen+1:], tpm2CapProperties)
+	binary.BigEndian.PutUint32(resp[tpm2ResponseHdrLen+5:], uint32(len(props)/2))
+	for _, v := range props {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		resp = append(resp, b...)
+	}
+	binary.BigEndian.PutUint32(resp[2:], uint32(len(resp)))
+	return resp
+}
+
+func TestTPM2Query(t *testing.T) {
+	submit := func(cmd []byte) ([]byte, error) {
+		assert.Len(t, cmd, 22)
+		assert.EqualValues(t, tpm2CCGetCapability, binary.BigEndian.Uint32(cmd[6:]))
+
+		switch binary.BigEndian.Uint32(cmd[14:]) {
+		case tpm2PTManufacturer:
+			return tpm2PropertiesResponse(
+				tpm2PTManufacturer, 0x49465800,
+				0x106, 0x534c4239,
+				tpm2PTFirmware1, 0x00070055,
+				tpm2PTFirmware2, 0x0011cf00,
+			), nil
+		default:
+			return tpm2PropertiesResponse(
+				tpm2PTPermanent, 0x00000007,
+				tpm2PTStartupClear, 0x80000007,
+			), nil
+		}
+	}
+
+	var info types.TPMInfo
+	if err := TPM2Query(submit, &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "IFX", info.Manufacturer)
+	assert.Equal(t, "7.85.17.52992", info.FirmwareVersion)
+	if assert.NotNil(t, info.Owned) {
+		assert.True(t, *info.Owned)
+	}
+	if assert.NotNil(t, info.Enabled) {
+		assert.True(t, *info.Enabled)
+	}
+}
+
+func TestParseTPM2PropertiesError(t *testing.T) {
+	resp := make([]byte, tpm2ResponseHdrLen)
+	binary.BigEndian.PutUint32(resp[6:], 0x101) // TPM_RC_FAILURE
+	_, err := parseTPM2Properties(resp)
+	assert.Error(t, err)
+}
+
+func TestTPMManufacturer(t *testing.T) {
+	assert.Equal(t, "IFX", TPMManufacturer(0x49465800))
+	assert.Equal(t, "INTC", TPMManufacturer(0x494e5443))
+	assert.Equal(t, "MSFT", TPMManufacturer(0x4d534654))
+}
@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// TZDataVersion returns the time zone database version from the contents of
+// zoneinfo/+VERSION (e.g. "2024a") or zoneinfo/tzdata.zi whose first line is
+// "# version 2024a". An empty string is returned if no version is found.
+func TZDataVersion(content []byte) string {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	if !sc.Scan() {
+		return ""
+	}
+	line := strings.TrimSpace(sc.Text())
+	if strings.HasPrefix(line, "#") {
+		fields := strings.Fields(strings.TrimPrefix(line, "#"))
+		if len(fields) == 2 && fields[0] == "version" {
+			return fields[1]
+		}
+		return ""
+	}
+	if strings.ContainsAny(line, " \t") {
+		return ""
+	}
+	return line
+}
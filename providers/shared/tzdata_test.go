@@ -0,0 +1,31 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTZDataVersion(t *testing.T) {
+	assert.Equal(t, "2024a", TZDataVersion([]byte("2024a\n")))
+	assert.Equal(t, "2023c", TZDataVersion([]byte("# version 2023c\n# This zic input file is in the public domain.\n")))
+	assert.Equal(t, "", TZDataVersion([]byte("# This zic input file is in the public domain.\n")))
+	assert.Equal(t, "", TZDataVersion(nil))
+}
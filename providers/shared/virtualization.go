@@ -0,0 +1,160 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// hypervisorVendors maps the vendor signatures of CPUID leaf 0x40000000 to
+// hypervisor names.
+var hypervisorVendors = map[string]string{
+	"KVMKVMKVM\x00\x00\x00": "KVM",
+	"Linux KVM Hv":          "KVM",
+	"VMwareVMware":          "VMware",
+	"Microsoft Hv":          "Hyper-V",
+	"XenVMMXenVMM":          "Xen",
+	"TCGTCGTCGTCG":          "QEMU",
+	" prl hyperv ":          "Parallels",
+	" lrpepyh  vr":          "Parallels",
+	"VBoxVBoxVBox":          "VirtualBox",
+	"bhyve bhyve ":          "bhyve",
+	"ACRNACRNACRN":          "ACRN",
+	"QNXQVMBSQG":            "QNX",
+	"Apple VZ":              "Apple",
+}
+
+// azureAssetTag is the chassis asset tag of Azure virtual machines.
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+// CPUIDHypervisor returns the name of the hypervisor reported by CPUID.
+// The hypervisor bit is set in leaf 1 when running in a virtual machine and
+// the vendor signature is then available in leaf 0x40000000. root is true
+// when the OS runs in the Hyper-V root partition, i.e. it is the host. An
+// empty name is returned on bare metal and on non-x86 architectures.
+func CPUIDHypervisor() (name string, root bool) {
+	if _, _, ecx, _ := cpuid(1, 0); ecx&(1<<31) == 0 {
+		return "", false
+	}
+
+	maxLeaf, ebx, ecx, edx := cpuid(0x40000000, 0)
+	sig := make([]byte, 12)
+	binary.LittleEndian.PutUint32(sig[0:], ebx)
+	binary.LittleEndian.PutUint32(sig[4:], ecx)
+	binary.LittleEndian.PutUint32(sig[8:], edx)
+
+	name = hypervisorName(string(sig))
+	if name == "Hyper-V" && maxLeaf >= 0x40000003 {
+		// The CreatePartitions privilege is only granted to the root
+		// partition.
+		_, privileges, _, _ := cpuid(0x40000003, 0)
+		root = privileges&1 != 0
+	}
+	return name, root
+}
+
+func hypervisorName(sig string) string {
+	if name, found := hypervisorVendors[sig]; found {
+		return name
+	}
+	if name, found := hypervisorVendors[strings.TrimRight(sig, "\x00")]; found {
+		return name
+	}
+	if strings.Trim(sig, "\x00") == "" {
+		// The hypervisor bit is set but there is no vendor signature.
+		return "unknown"
+	}
+	return strings.TrimSpace(strings.Trim(sig, "\x00"))
+}
+
+// DMIVirtualization returns the hypervisor and the cloud provider that are
+// indicated by the SMBIOS strings of the host. assetTag is the chassis
+// asset tag, if known. Empty strings are returned if the strings match no
+// known virtual hardware.
+func DMIVirtualization(hw *types.HardwareInfo, assetTag string) (hypervisor, cloud string) {
+	if hw == nil {
+		return "", ""
+	}
+
+	vendor := hw.Manufacturer
+	product := hw.ProductName
+	contains := func(substr string) bool {
+		return strings.Contains(vendor, substr) || strings.Contains(product, substr) ||
+			strings.Contains(hw.BIOSVendor, substr) || strings.Contains(hw.BIOSVersion, substr)
+	}
+
+	switch {
+	case contains("Amazon EC2"):
+		hypervisor, cloud = "KVM", "aws"
+	case strings.Contains(hw.BIOSVersion, "amazon"):
+		hypervisor, cloud = "Xen", "aws"
+	case product == "Google Compute Engine":
+		hypervisor, cloud = "KVM", "gcp"
+	case vendor == "Microsoft Corporation" && product == "Virtual Machine":
+		hypervisor = "Hyper-V"
+		if assetTag == azureAssetTag {
+			cloud = "azure"
+		}
+	case vendor == "DigitalOcean":
+		hypervisor, cloud = "KVM", "digitalocean"
+	case vendor == "Alibaba Cloud":
+		hypervisor, cloud = "KVM", "alibaba"
+	case strings.HasPrefix(product, "OpenStack"):
+		hypervisor, cloud = "KVM", "openstack"
+	case contains("VMware"):
+		hypervisor = "VMware"
+	case contains("VirtualBox") || vendor == "innotek GmbH":
+		hypervisor = "VirtualBox"
+	case contains("Parallels"):
+		hypervisor = "Parallels"
+	case vendor == "Xen" || contains("HVM domU"):
+		hypervisor = "Xen"
+	case product == "KVM":
+		hypervisor = "KVM"
+	case vendor == "QEMU" || contains("QEMU"):
+		hypervisor = "QEMU"
+	case vendor == "bhyve" || hw.BIOSVendor == "BHYVE":
+		hypervisor = "bhyve"
+	}
+	return hypervisor, cloud
+}
+
+// Virtualization combines the CPUID and DMI results. The CPUID vendor takes
+// precedence because it identifies the hypervisor itself, while the DMI
+// strings describe the emulated hardware (e.g. QEMU hardware under KVM).
+func Virtualization(hw *types.HardwareInfo, assetTag string) *types.VirtualizationInfo {
+	info := &types.VirtualizationInfo{}
+
+	name, root := CPUIDHypervisor()
+	dmiName, cloud := DMIVirtualization(hw, assetTag)
+	info.Cloud = cloud
+	switch {
+	case root:
+		info.Hypervisor, info.Role = name, types.VirtualizationRoleHost
+	case name != "" && name != "unknown":
+		info.Hypervisor, info.Role = name, types.VirtualizationRoleGuest
+	case dmiName != "":
+		info.Hypervisor, info.Role = dmiName, types.VirtualizationRoleGuest
+	case name != "":
+		info.Hypervisor, info.Role = name, types.VirtualizationRoleGuest
+	}
+	return info
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestHypervisorName(t *testing.T) {
+	assert.Equal(t, "KVM", hypervisorName("KVMKVMKVM\x00\x00\x00"))
+	assert.Equal(t, "Hyper-V", hypervisorName("Microsoft Hv"))
+	assert.Equal(t, "QNX", hypervisorName("QNXQVMBSQG\x00\x00"))
+	assert.Equal(t, "unknown", hypervisorName("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"))
+	assert.Equal(t, "NewHV", hypervisorName("NewHV\x00\x00\x00\x00\x00\x00\x00"))
+}
+
+func TestDMIVirtualization(t *testing.T) {
+	for _, tc := range []struct {
+		hw         types.HardwareInfo
+		assetTag   string
+		hypervisor string
+		cloud      string
+	}{
+		{types.HardwareInfo{Manufacturer: "Dell Inc.", ProductName: "PowerEdge R740"}, "", "", ""},
+		{types.HardwareInfo{Manufacturer: "Amazon EC2", ProductName: "m5.large"}, "", "KVM", "aws"},
+		{types.HardwareInfo{Manufacturer: "Xen", ProductName: "HVM domU", BIOSVersion: "4.2.amazon"}, "", "Xen", "aws"},
+		{types.HardwareInfo{Manufacturer: "Google", ProductName: "Google Compute Engine"}, "", "KVM", "gcp"},
+		{types.HardwareInfo{Manufacturer: "Microsoft Corporation", ProductName: "Virtual Machine"}, azureAssetTag, "Hyper-V", "azure"},
+		{types.HardwareInfo{Manufacturer: "Microsoft Corporation", ProductName: "Virtual Machine"}, "", "Hyper-V", ""},
+		{types.HardwareInfo{Manufacturer: "VMware, Inc.", ProductName: "VMware Virtual Platform"}, "", "VMware", ""},
+		{types.HardwareInfo{Manufacturer: "innotek GmbH", ProductName: "VirtualBox"}, "", "VirtualBox", ""},
+		{types.HardwareInfo{Manufacturer: "QEMU", ProductName: "Standard PC (Q35 + ICH9, 2009)"}, "", "QEMU", ""},
+	} {
+		hw := tc.hw
+		hypervisor, cloud := DMIVirtualization(&hw, tc.assetTag)
+		assert.Equal(t, tc.hypervisor, hypervisor, "%+v", tc.hw)
+		assert.Equal(t, tc.cloud, cloud, "%+v", tc.hw)
+	}
+
+	hypervisor, cloud := DMIVirtualization(nil, "")
+	assert.Empty(t, hypervisor)
+	assert.Empty(t, cloud)
+}
@@ -0,0 +1,272 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build solaris,cgo
+
+package solaris
+
+/*
+#include <unistd.h>
+#include <sys/utsname.h>
+*/
+import "C"
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joeshaw/multierror"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func (s solarisSystem) Host() (types.Host, error) {
+	return newHost()
+}
+
+type host struct {
+	info types.HostInfo
+}
+
+func (h *host) Info() types.HostInfo {
+	return h.info
+}
+
+// Uptime returns the time since boot.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock.Uptime()
+}
+
+// LoadAverage returns the load averages from the unix:0:system_misc kstat.
+// The values are fixed-point numbers scaled by FSCALE (256).
+func (h *host) LoadAverage() (*types.LoadAverageInfo, error) {
+	kc, err := openKstat()
+	if err != nil {
+		return nil, err
+	}
+	defer kc.Close()
+
+	ks, err := kc.lookup("unix", 0, "system_misc")
+	if err != nil {
+		return nil, err
+	}
+
+	var ldavg [3]uint32
+	for i, name := range []string{"avenrun_1min", "avenrun_5min", "avenrun_15min"} {
+		v, err := ks.uint64(name)
+		if err != nil {
+			return nil, err
+		}
+		ldavg[i] = uint32(v)
+	}
+	return shared.FixedPointLoadAverage(ldavg, 256), nil
+}
+
+func (h *host) CPUTime() (types.CPUTimes, error) {
+	perCPU, err := h.CPUTimePerCPU()
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+
+	var total types.CPUTimes
+	for _, cpu := range perCPU {
+		total.User += cpu.User
+		total.System += cpu.System
+		total.Idle += cpu.Idle
+	}
+	return total, nil
+}
+
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	kc, err := openKstat()
+	if err != nil {
+		return nil, err
+	}
+	defer kc.Close()
+
+	stats, err := kc.all("cpu", "sys")
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]types.CPUTimes, 0, len(stats))
+	for _, ks := range stats {
+		var t types.CPUTimes
+		for _, v := range []struct {
+			name string
+			d    *time.Duration
+		}{
+			{"cpu_nsec_user", &t.User},
+			{"cpu_nsec_kernel", &t.System},
+			{"cpu_nsec_idle", &t.Idle},
+		} {
+			ns, err := ks.uint64(v.name)
+			if err != nil {
+				return nil, err
+			}
+			*v.d = time.Duration(ns)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+func (h *host) Memory() (*types.HostMemoryInfo, error) {
+	kc, err := openKstat()
+	if err != nil {
+		return nil, err
+	}
+	defer kc.Close()
+
+	ks, err := kc.lookup("unix", 0, "system_pages")
+	if err != nil {
+		return nil, err
+	}
+
+	physmem, err := ks.uint64("physmem")
+	if err != nil {
+		return nil, err
+	}
+	freemem, err := ks.uint64("freemem")
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	mem := &types.HostMemoryInfo{
+		Total: physmem * pageSize,
+		Free:  freemem * pageSize,
+	}
+	mem.Used = mem.Total - mem.Free
+
+	// Memory used by the ZFS ARC can be reclaimed but it is not reported
+	// as available.
+	mem.Available = mem.Free
+	return mem, nil
+}
+
+func newHost() (*host, error) {
+	h := &host{}
+	r := &reader{}
+	r.bootTime(h)
+	r.hostname(h)
+	r.network(h)
+	r.uname(h)
+	r.os(h)
+	r.time(h)
+	r.uniqueID(h)
+	return h, r.Err()
+}
+
+type reader struct {
+	errs []error
+}
+
+func (r *reader) addErr(err error) bool {
+	if err != nil {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
+			r.errs = append(r.errs, err)
+		}
+		return true
+	}
+	return false
+}
+
+func (r *reader) Err() error {
+	if len(r.errs) > 0 {
+		return &multierror.MultiError{Errors: r.errs}
+	}
+	return nil
+}
+
+func (r *reader) bootTime(h *host) {
+	v, err := bootClock.BootTime()
+	if r.addErr(err) {
+		return
+	}
+	h.info.BootTime = v
+}
+
+var bootClock = shared.NewBootTimeClock(kstatBootTime)
+
+func kstatBootTime() (time.Time, error) {
+	kc, err := openKstat()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer kc.Close()
+
+	ks, err := kc.lookup("unix", 0, "system_misc")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	sec, err := ks.uint64("boot_time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(sec), 0), nil
+}
+
+func (r *reader) hostname(h *host) {
+	v, err := os.Hostname()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Hostname = v
+}
+
+func (r *reader) network(h *host) {
+	ips, macs, err := shared.Network()
+	if r.addErr(err) {
+		return
+	}
+	h.info.IPs = ips
+	h.info.MACs = macs
+}
+
+// uname reads the architecture and the kernel version. The release is always
+// 5.11 so the version (e.g. omnios-r151046-0e9b6d6b07) is reported instead.
+func (r *reader) uname(h *host) {
+	var uts C.struct_utsname
+	if rtn, err := C.uname(&uts); rtn == -1 {
+		r.addErr(errors.Wrap(err, "uname failed"))
+		return
+	}
+	h.info.Architecture = C.GoString(&uts.machine[0])
+	h.info.KernelVersion = C.GoString(&uts.version[0])
+}
+
+func (r *reader) os(h *host) {
+	v, err := OperatingSystem()
+	if r.addErr(err) {
+		return
+	}
+	h.info.OS = v
+}
+
+func (r *reader) time(h *host) {
+	h.info.Timezone, h.info.TimezoneOffsetSec = time.Now().Zone()
+}
+
+func (r *reader) uniqueID(h *host) {
+	h.info.UniqueID = strconv.FormatUint(uint64(uint32(C.gethostid())), 16)
+	h.info.UniqueIDSource = types.MachineIDSourceHostID
+}
@@ -0,0 +1,139 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build solaris,cgo
+
+package solaris
+
+/*
+#cgo LDFLAGS: -lkstat
+#include <stdint.h>
+#include <stdlib.h>
+#include <kstat.h>
+
+static int
+kstat_named_uint64(kstat_t *ksp, char *name, uint64_t *value)
+{
+	kstat_named_t *kn = kstat_data_lookup(ksp, name);
+	if (kn == NULL) {
+		return -1;
+	}
+
+	switch (kn->data_type) {
+	case KSTAT_DATA_INT32:
+		*value = kn->value.i32;
+		break;
+	case KSTAT_DATA_UINT32:
+		*value = kn->value.ui32;
+		break;
+	case KSTAT_DATA_INT64:
+		*value = kn->value.i64;
+		break;
+	case KSTAT_DATA_UINT64:
+		*value = kn->value.ui64;
+		break;
+	default:
+		return -1;
+	}
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"sort"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+type kstatCtl struct {
+	kc *C.kstat_ctl_t
+}
+
+func openKstat() (*kstatCtl, error) {
+	kc, err := C.kstat_open()
+	if kc == nil {
+		return nil, errors.Wrap(err, "kstat_open failed")
+	}
+	return &kstatCtl{kc: kc}, nil
+}
+
+func (k *kstatCtl) Close() error {
+	C.kstat_close(k.kc)
+	return nil
+}
+
+// kstat is a named kstat whose data has been read.
+type kstat struct {
+	ksp *C.kstat_t
+}
+
+func (k *kstatCtl) read(ksp *C.kstat_t) (*kstat, error) {
+	if ksp.ks_type != C.KSTAT_TYPE_NAMED {
+		return nil, errors.Errorf("kstat %v is not a named kstat", C.GoString(&ksp.ks_name[0]))
+	}
+	if id, err := C.kstat_read(k.kc, ksp, nil); id == -1 {
+		return nil, errors.Wrapf(err, "kstat_read of %v failed", C.GoString(&ksp.ks_name[0]))
+	}
+	return &kstat{ksp: ksp}, nil
+}
+
+// lookup returns the named kstat module:instance:name.
+func (k *kstatCtl) lookup(module string, instance int, name string) (*kstat, error) {
+	cModule, cName := C.CString(module), C.CString(name)
+	defer C.free(unsafe.Pointer(cModule))
+	defer C.free(unsafe.Pointer(cName))
+
+	ksp, err := C.kstat_lookup(k.kc, cModule, C.int(instance), cName)
+	if ksp == nil {
+		return nil, errors.Wrapf(err, "kstat %v:%d:%v not found", module, instance, name)
+	}
+	return k.read(ksp)
+}
+
+// all returns the named kstats module:*:name ordered by instance.
+func (k *kstatCtl) all(module, name string) ([]*kstat, error) {
+	var stats []*kstat
+	for ksp := k.kc.kc_chain; ksp != nil; ksp = ksp.ks_next {
+		if C.GoString(&ksp.ks_module[0]) != module || C.GoString(&ksp.ks_name[0]) != name {
+			continue
+		}
+
+		ks, err := k.read(ksp)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, ks)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ksp.ks_instance < stats[j].ksp.ks_instance
+	})
+	return stats, nil
+}
+
+func (ks *kstat) uint64(name string) (uint64, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var v C.uint64_t
+	if C.kstat_named_uint64(ks.ksp, cName, &v) != 0 {
+		return 0, errors.Errorf("kstat value %v not found", name)
+	}
+	return uint64(v), nil
+}
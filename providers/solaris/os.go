@@ -0,0 +1,95 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solaris
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const etcRelease = "/etc/release"
+
+// versionRegexp matches release versions like 11.4, r151046, or the build
+// date of SmartOS (20230323T000719Z).
+var versionRegexp = regexp.MustCompile(`\b(?:r|v)?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+func OperatingSystem() (*types.OSInfo, error) {
+	data, err := ioutil.ReadFile(etcRelease)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read release file")
+	}
+
+	return getOSInfo(data)
+}
+
+// getOSInfo parses the first line of /etc/release.
+func getOSInfo(data []byte) (*types.OSInfo, error) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	var line string
+	for s.Scan() {
+		if line = strings.TrimSpace(s.Text()); line != "" {
+			break
+		}
+	}
+	if line == "" {
+		return nil, errors.New("release file is empty")
+	}
+
+	os := &types.OSInfo{
+		Family:   "solaris",
+		Platform: "solaris",
+		Name:     "Solaris",
+	}
+	for _, name := range []string{"OmniOS", "SmartOS", "OpenIndiana", "Oracle Solaris"} {
+		if strings.Contains(line, name) {
+			os.Name = name
+			os.Platform = strings.ToLower(strings.Fields(name)[0])
+			break
+		}
+	}
+
+	// Skip over the distribution name so that names with digits don't
+	// match the version.
+	rest := line
+	if i := strings.Index(line, os.Name); i >= 0 {
+		rest = line[i+len(os.Name):]
+	}
+	if m := versionRegexp.FindStringSubmatchIndex(rest); m != nil {
+		os.Version = strings.Fields(rest[m[0]:])[0]
+		os.Major, _ = strconv.Atoi(rest[m[2]:m[3]])
+		if m[4] >= 0 {
+			os.Minor, _ = strconv.Atoi(rest[m[4]:m[5]])
+		}
+		if m[6] >= 0 {
+			os.Patch, _ = strconv.Atoi(rest[m[6]:m[7]])
+		}
+	}
+	if os.Platform == "oracle" {
+		os.Platform = "solaris"
+	}
+
+	return os, nil
+}
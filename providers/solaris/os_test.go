@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solaris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestGetOSInfo(t *testing.T) {
+	tests := map[string]types.OSInfo{
+		"  OmniOS v11 r151046\n  Copyright (c) 2012-2017 OmniTI Computer Consulting, Inc.\n": {
+			Family:   "solaris",
+			Platform: "omnios",
+			Name:     "OmniOS",
+			Version:  "v11",
+			Major:    11,
+		},
+		"                       SmartOS 20230323T000719Z x86_64\n": {
+			Family:   "solaris",
+			Platform: "smartos",
+			Name:     "SmartOS",
+			Version:  "20230323T000719Z",
+			Major:    20230323,
+		},
+		"                             Oracle Solaris 11.4 X86\n": {
+			Family:   "solaris",
+			Platform: "solaris",
+			Name:     "Oracle Solaris",
+			Version:  "11.4",
+			Major:    11,
+			Minor:    4,
+		},
+	}
+
+	for release, expected := range tests {
+		os, err := getOSInfo([]byte(release))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, *os)
+	}
+
+	_, err := getOSInfo(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,306 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build solaris
+
+package solaris
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const procfs = "/proc"
+
+func init() {
+	registry.Register(solarisSystem{})
+}
+
+// solarisSystem implements the ProcessProvider. It also implements the
+// HostProvider when built with cgo.
+type solarisSystem struct {
+	envFilter types.EnvFilter // Applied to the environment of the processes.
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s solarisSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+func (s solarisSystem) Processes() ([]types.Process, error) {
+	names, err := ioutil.ReadDir(procfs)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]types.Process, 0, len(names))
+	for _, name := range names {
+		pid, err := strconv.Atoi(name.Name())
+		if err != nil {
+			continue
+		}
+		processes = append(processes, &process{pid: pid, envFilter: s.envFilter})
+	}
+	return processes, nil
+}
+
+func (s solarisSystem) Process(pid int) (types.Process, error) {
+	p := &process{pid: pid, envFilter: s.envFilter}
+	if _, err := p.psinfo(); err != nil {
+		if os.IsNotExist(err) {
+			return nil, &types.ProcessNotFoundError{PID: pid}
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s solarisSystem) Self() (types.Process, error) {
+	return &process{pid: os.Getpid(), envFilter: s.envFilter}, nil
+}
+
+type process struct {
+	pid       int
+	envFilter types.EnvFilter
+	info      *types.ProcessInfo
+}
+
+func (p *process) PID() int {
+	return p.pid
+}
+
+func (p *process) path(pa ...string) string {
+	return filepath.Join(append([]string{procfs, strconv.Itoa(p.pid)}, pa...)...)
+}
+
+func (p *process) psinfo() (*psinfo, error) {
+	data, err := ioutil.ReadFile(p.path("psinfo"))
+	if err != nil {
+		return nil, err
+	}
+	return parsePSInfo(data)
+}
+
+func (p *process) Info() (types.ProcessInfo, error) {
+	if p.info != nil {
+		return *p.info, nil
+	}
+
+	ps, err := p.psinfo()
+	if err != nil {
+		return types.ProcessInfo{}, err
+	}
+
+	// The links are only readable by the owner of the process.
+	exe, _ := os.Readlink(p.path("path", "a.out"))
+	cwd, _ := os.Readlink(p.path("path", "cwd"))
+
+	// The address space of the process holds the full argument list. Fall
+	// back to the truncated pr_psargs when it cannot be read.
+	args, err := p.readStringArray(ps, ps.Argv, ps.Argc)
+	if err != nil {
+		args = strings.Fields(ps.PSArgs)
+	}
+
+	p.info = &types.ProcessInfo{
+		Name:      ps.Name,
+		PID:       p.pid,
+		PPID:      ps.PPID,
+		CWD:       cwd,
+		Exe:       exe,
+		Args:      args,
+		StartTime: ps.Start,
+		State:     lwpState(ps.Sname),
+	}
+
+	return *p.info, nil
+}
+
+func (p *process) User() (types.UserInfo, error) {
+	ps, err := p.psinfo()
+	if err != nil {
+		return types.UserInfo{}, err
+	}
+
+	user := types.UserInfo{
+		UID:  strconv.Itoa(int(ps.UID)),
+		EUID: strconv.Itoa(int(ps.EUID)),
+		GID:  strconv.Itoa(int(ps.GID)),
+		EGID: strconv.Itoa(int(ps.EGID)),
+	}
+
+	// The saved IDs are only readable by the owner of the process.
+	if data, err := ioutil.ReadFile(p.path("cred")); err == nil {
+		if suid, sgid, err := parsePRCred(data); err == nil {
+			user.SUID = strconv.Itoa(int(suid))
+			user.SGID = strconv.Itoa(int(sgid))
+		}
+	}
+
+	return user, nil
+}
+
+func (p *process) Memory() (types.MemoryInfo, error) {
+	ps, err := p.psinfo()
+	if err != nil {
+		return types.MemoryInfo{}, err
+	}
+
+	return types.MemoryInfo{
+		Resident: ps.RSSize,
+		Virtual:  ps.Size,
+	}, nil
+}
+
+func (p *process) CPUTime() (types.CPUTimes, error) {
+	data, err := ioutil.ReadFile(p.path("usage"))
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+
+	user, system, err := parsePRUsage(data)
+	if err != nil {
+		return types.CPUTimes{}, err
+	}
+
+	return types.CPUTimes{
+		User:   user,
+		System: system,
+	}, nil
+}
+
+func (p *process) Environment() (map[string]string, error) {
+	ps, err := p.psinfo()
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := p.readStringArray(ps, ps.Envp, -1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process environment")
+	}
+
+	env := map[string]string{}
+	for _, kv := range vars {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			continue
+		}
+
+		env[key] = parts[1]
+	}
+
+	return shared.FilterEnv(p.envFilter, env), nil
+}
+
+func (p *process) Children() ([]types.Process, error) {
+	procs, err := solarisSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Children(procs, p)
+}
+
+// Ancestors returns the parents of the process up to the root of the tree.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := solarisSystem{envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Ancestors(procs, p)
+}
+
+// readStringArray reads a NULL terminated array of string pointers starting
+// at addr from the address space of the process. When count is not negative
+// at most count strings are read.
+func (p *process) readStringArray(ps *psinfo, addr uint64, count int) ([]string, error) {
+	const maxStrings = 4096
+
+	ptrSize := uint64(4)
+	if ps.DataModel == prModelLP64 {
+		ptrSize = 8
+	}
+
+	as, err := os.Open(p.path("as"))
+	if err != nil {
+		return nil, err
+	}
+	defer as.Close()
+
+	var strs []string
+	ptr := make([]byte, ptrSize)
+	for i := 0; i != count && i < maxStrings; i++ {
+		if _, err := as.ReadAt(ptr, int64(addr+uint64(i)*ptrSize)); err != nil {
+			return nil, err
+		}
+
+		var strAddr uint64
+		if ptrSize == 8 {
+			strAddr = binary.LittleEndian.Uint64(ptr)
+		} else {
+			strAddr = uint64(binary.LittleEndian.Uint32(ptr))
+		}
+		if strAddr == 0 {
+			break
+		}
+
+		s, err := readString(as, int64(strAddr))
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// readString reads a NUL terminated string from the address space.
+func readString(as *os.File, addr int64) (string, error) {
+	const maxLen = 1 << 20
+
+	var s []byte
+	buf := make([]byte, 256)
+	for len(s) < maxLen {
+		n, err := as.ReadAt(buf, addr+int64(len(s)))
+		if n == 0 && err != nil {
+			return "", err
+		}
+		for i, c := range buf[:n] {
+			if c == 0 {
+				return string(append(s, buf[:i]...)), nil
+			}
+		}
+		s = append(s, buf[:n]...)
+	}
+	return string(s), nil
+}
@@ -0,0 +1,176 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solaris
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Offsets of the fields of psinfo_t from sys/procfs.h for the 64-bit data
+// model. Only pr_sname is read from the embedded lwpsinfo_t of the
+// representative LWP.
+const (
+	psinfoMinSize = 288
+
+	psinfoPID    = 8
+	psinfoPPID   = 12
+	psinfoUID    = 24
+	psinfoEUID   = 28
+	psinfoGID    = 32
+	psinfoEGID   = 36
+	psinfoVSize  = 48
+	psinfoRSSize = 56
+	psinfoStart  = 88
+	psinfoFname  = 136
+	psinfoPSArgs = 152
+	psinfoArgc   = 236
+	psinfoArgv   = 240
+	psinfoEnvp   = 248
+	psinfoDModel = 256
+	psinfoSname  = 288 + 26 // pr_lwp.pr_sname
+
+	prfnsz  = 16 // PRFNSZ
+	prargsz = 80 // PRARGSZ
+
+	prModelLP64 = 2 // PR_MODEL_LP64
+)
+
+// Offsets of the CPU times in prusage_t.
+const (
+	prusageMinSize = 104
+
+	prusageUTime = 72
+	prusageSTime = 88
+)
+
+// Offsets of the saved IDs in prcred_t.
+const (
+	prcredMinSize = 24
+
+	prcredSUID = 8
+	prcredSGID = 20
+)
+
+// psinfo contains the fields of psinfo_t that are used by the provider.
+type psinfo struct {
+	PID       int
+	PPID      int
+	UID       uint32
+	EUID      uint32
+	GID       uint32
+	EGID      uint32
+	Size      uint64 // Virtual size in bytes.
+	RSSize    uint64 // Resident set size in bytes.
+	Start     time.Time
+	Name      string
+	PSArgs    string // First 80 characters of the arguments.
+	Argc      int
+	Argv      uint64 // Address of the argument vector.
+	Envp      uint64 // Address of the environment vector.
+	DataModel byte
+	Sname     byte // State of the representative LWP (e.g. R, S, Z).
+}
+
+func parsePSInfo(b []byte) (*psinfo, error) {
+	if len(b) < psinfoMinSize {
+		return nil, errors.Errorf("psinfo is too small (%d bytes)", len(b))
+	}
+
+	le := binary.LittleEndian
+	ps := &psinfo{
+		PID:       int(int32(le.Uint32(b[psinfoPID:]))),
+		PPID:      int(int32(le.Uint32(b[psinfoPPID:]))),
+		UID:       le.Uint32(b[psinfoUID:]),
+		EUID:      le.Uint32(b[psinfoEUID:]),
+		GID:       le.Uint32(b[psinfoGID:]),
+		EGID:      le.Uint32(b[psinfoEGID:]),
+		Size:      le.Uint64(b[psinfoVSize:]) * 1024,
+		RSSize:    le.Uint64(b[psinfoRSSize:]) * 1024,
+		Start:     timestruc(b[psinfoStart:]),
+		Name:      nullTerminated(b[psinfoFname : psinfoFname+prfnsz]),
+		PSArgs:    nullTerminated(b[psinfoPSArgs : psinfoPSArgs+prargsz]),
+		Argc:      int(int32(le.Uint32(b[psinfoArgc:]))),
+		Argv:      le.Uint64(b[psinfoArgv:]),
+		Envp:      le.Uint64(b[psinfoEnvp:]),
+		DataModel: b[psinfoDModel],
+	}
+	if len(b) > psinfoSname {
+		ps.Sname = b[psinfoSname]
+	}
+	return ps, nil
+}
+
+// lwpState maps pr_sname to one of the ProcessState constants.
+func lwpState(sname byte) string {
+	switch sname {
+	case 'O', 'R':
+		return types.ProcessStateRunning
+	case 'S':
+		return types.ProcessStateSleeping
+	case 'T':
+		return types.ProcessStateStopped
+	case 'Z':
+		return types.ProcessStateZombie
+	case 'W':
+		return types.ProcessStateWaiting
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+// parsePRUsage returns the user and system CPU time from prusage_t.
+func parsePRUsage(b []byte) (user, system time.Duration, err error) {
+	if len(b) < prusageMinSize {
+		return 0, 0, errors.Errorf("prusage is too small (%d bytes)", len(b))
+	}
+	return timestrucDuration(b[prusageUTime:]), timestrucDuration(b[prusageSTime:]), nil
+}
+
+// parsePRCred returns the saved user and group IDs from prcred_t.
+func parsePRCred(b []byte) (suid, sgid uint32, err error) {
+	if len(b) < prcredMinSize {
+		return 0, 0, errors.Errorf("prcred is too small (%d bytes)", len(b))
+	}
+	return binary.LittleEndian.Uint32(b[prcredSUID:]), binary.LittleEndian.Uint32(b[prcredSGID:]), nil
+}
+
+func timestruc(b []byte) time.Time {
+	sec := int64(binary.LittleEndian.Uint64(b))
+	nsec := int64(binary.LittleEndian.Uint64(b[8:]))
+	return time.Unix(sec, nsec)
+}
+
+func timestrucDuration(b []byte) time.Duration {
+	sec := int64(binary.LittleEndian.Uint64(b))
+	nsec := int64(binary.LittleEndian.Uint64(b[8:]))
+	return time.Duration(sec)*time.Second + time.Duration(nsec)
+}
+
+func nullTerminated(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solaris
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParsePSInfo(t *testing.T) {
+	b := make([]byte, 416)
+	le := binary.LittleEndian
+	le.PutUint32(b[psinfoPID:], 1234)
+	le.PutUint32(b[psinfoPPID:], 1)
+	le.PutUint32(b[psinfoUID:], 100)
+	le.PutUint32(b[psinfoEUID:], 0)
+	le.PutUint32(b[psinfoGID:], 10)
+	le.PutUint32(b[psinfoEGID:], 0)
+	le.PutUint64(b[psinfoVSize:], 4096)
+	le.PutUint64(b[psinfoRSSize:], 1024)
+	le.PutUint64(b[psinfoStart:], 1500000000)
+	le.PutUint64(b[psinfoStart+8:], 500)
+	copy(b[psinfoFname:], "nginx")
+	copy(b[psinfoPSArgs:], "nginx -g daemon off;")
+	le.PutUint32(b[psinfoArgc:], 3)
+	le.PutUint64(b[psinfoArgv:], 0x8047e30)
+	le.PutUint64(b[psinfoEnvp:], 0x8047e50)
+	b[psinfoDModel] = prModelLP64
+	b[psinfoSname] = 'S'
+
+	info, err := parsePSInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &psinfo{
+		PID:       1234,
+		PPID:      1,
+		UID:       100,
+		EUID:      0,
+		GID:       10,
+		EGID:      0,
+		Size:      4096 * 1024,
+		RSSize:    1024 * 1024,
+		Start:     time.Unix(1500000000, 500),
+		Name:      "nginx",
+		PSArgs:    "nginx -g daemon off;",
+		Argc:      3,
+		Argv:      0x8047e30,
+		Envp:      0x8047e50,
+		DataModel: prModelLP64,
+		Sname:     'S',
+	}, info)
+	assert.Equal(t, types.ProcessStateSleeping, lwpState(info.Sname))
+
+	_, err = parsePSInfo(b[:100])
+	assert.Error(t, err)
+}
+
+func TestParsePRUsage(t *testing.T) {
+	b := make([]byte, prusageMinSize)
+	le := binary.LittleEndian
+	le.PutUint64(b[prusageUTime:], 2)
+	le.PutUint64(b[prusageUTime+8:], 500000000)
+	le.PutUint64(b[prusageSTime+8:], 1000)
+
+	user, system, err := parsePRUsage(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2500*time.Millisecond, user)
+	assert.Equal(t, time.Microsecond, system)
+}
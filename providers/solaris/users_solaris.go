@@ -0,0 +1,45 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build solaris
+
+package solaris
+
+import (
+	"io/ioutil"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Users returns the accounts in /etc/passwd.
+func (s solarisSystem) Users() ([]types.UserAccount, error) {
+	content, err := ioutil.ReadFile("/etc/passwd")
+	if err != nil {
+		return nil, err
+	}
+	return shared.ParsePasswd(content), nil
+}
+
+// Groups returns the groups in /etc/group.
+func (s solarisSystem) Groups() ([]types.GroupAccount, error) {
+	content, err := ioutil.ReadFile("/etc/group")
+	if err != nil {
+		return nil, err
+	}
+	return shared.ParseGroup(content), nil
+}
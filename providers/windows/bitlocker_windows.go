@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	coinitApartmentThreaded = 0x2 // COINIT_APARTMENTTHREADED
+	gpsDefault              = 0   // GPS_DEFAULT
+	vtI4                    = 3   // VT_I4
+
+	// bitLockerProtectionProperty is the shell property that Explorer uses
+	// to show the BitLocker state of a drive. It can be read without
+	// administrator rights, unlike Win32_EncryptableVolume.
+	bitLockerProtectionProperty = "System.Volume.BitLockerProtection"
+)
+
+// iidIPropertyStore is the interface ID of IPropertyStore.
+var iidIPropertyStore = syscall.GUID{
+	Data1: 0x886d8eeb,
+	Data2: 0x8cf2,
+	Data3: 0x4446,
+	Data4: [8]byte{0x8d, 0x02, 0xcd, 0xba, 0x1d, 0xbd, 0xcf, 0x99},
+}
+
+// propertyKey is Go's counterpart of the PROPERTYKEY struct.
+type propertyKey struct {
+	FmtID syscall.GUID
+	PID   uint32
+}
+
+// propVariant is Go's counterpart of the PROPVARIANT struct. Only integer
+// values are read from it.
+type propVariant struct {
+	VT       uint16
+	reserved [3]uint16
+	Val      [2]uint64
+}
+
+type iPropertyStore struct {
+	vtbl *iPropertyStoreVtbl
+}
+
+type iPropertyStoreVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	GetCount       uintptr
+	GetAt          uintptr
+	GetValue       uintptr
+	SetValue       uintptr
+	Commit         uintptr
+}
+
+// readBitLockerStatus sets the BitLocker state of each file system from the
+// shell property store of its drive.
+func readBitLockerStatus(filesystems []types.FileSystemInfo) {
+	// COM is initialized per thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if hr := _CoInitializeEx(0, coinitApartmentThreaded); hr >= 0 {
+		defer _CoUninitialize()
+	}
+
+	name, err := syscall.UTF16PtrFromString(bitLockerProtectionProperty)
+	if err != nil {
+		return
+	}
+	var key propertyKey
+	if hr := _PSGetPropertyKeyFromName(name, &key); hr < 0 {
+		return
+	}
+
+	for i := range filesystems {
+		fs := &filesystems[i]
+		protection, ok := shellPropertyInt32(fs.MountPoint, &key)
+		if !ok {
+			continue
+		}
+		if fs.Encrypted = bitLockerEncrypted(protection); fs.Encrypted != nil && *fs.Encrypted {
+			fs.Encryption = "bitlocker"
+		}
+	}
+}
+
+// shellPropertyInt32 reads an integer property of the shell item at path.
+func shellPropertyInt32(path string, key *propertyKey) (int32, bool) {
+	pathW, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, false
+	}
+
+	var store *iPropertyStore
+	if hr := _SHGetPropertyStoreFromParsingName(pathW, 0, gpsDefault, &iidIPropertyStore, &store); hr < 0 || store == nil {
+		return 0, false
+	}
+	defer syscall.Syscall(store.vtbl.Release, 1, uintptr(unsafe.Pointer(store)), 0, 0)
+
+	var v propVariant
+	hr, _, _ := syscall.Syscall(store.vtbl.GetValue, 3, uintptr(unsafe.Pointer(store)),
+		uintptr(unsafe.Pointer(key)), uintptr(unsafe.Pointer(&v)))
+	if int32(hr) < 0 || v.VT != vtI4 {
+		return 0, false
+	}
+	return int32(uint32(v.Val[0])), true
+}
+
+// bitLockerEncrypted converts a System.Volume.BitLockerProtection value. The
+// volume is considered encrypted while it is being encrypted or decrypted,
+// and when protection is suspended or awaiting activation because the data
+// is still encrypted on disk.
+func bitLockerEncrypted(protection int32) *bool {
+	var encrypted bool
+	switch protection {
+	case 1, // On
+		3, // Encrypting
+		4, // Decrypting
+		5, // Suspended
+		6, // On, locked
+		8: // Waiting for activation
+		encrypted = true
+	case 2: // Off
+		encrypted = false
+	default:
+		return nil
+	}
+	return &encrypted
+}
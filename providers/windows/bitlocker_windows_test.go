@@ -0,0 +1,33 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitLockerEncrypted(t *testing.T) {
+	for protection, expected := range map[int32]bool{1: true, 2: false, 5: true, 6: true} {
+		if v := bitLockerEncrypted(protection); assert.NotNil(t, v, "protection=%d", protection) {
+			assert.Equal(t, expected, *v, "protection=%d", protection)
+		}
+	}
+	assert.Nil(t, bitLockerEncrypted(0))
+}
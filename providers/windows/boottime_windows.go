@@ -18,22 +18,55 @@
 package windows
 
 import (
+	"strconv"
+	"sync"
 	"time"
 
 	windows "github.com/elastic/go-windows"
 	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
 )
 
-func BootTime() (time.Time, error) {
+// bootClock derives the boot time from GetTickCount64. The tick count
+// includes time spent in sleep and hibernation.
+var bootClock = shared.NewUptimeClock(func() (time.Duration, error) {
 	msSinceBoot, err := windows.GetTickCount64()
 	if err != nil {
-		return time.Time{}, errors.Wrap(err, "failed to get boot time")
+		return 0, errors.Wrap(err, "failed to get uptime")
 	}
+	return time.Duration(msSinceBoot) * time.Millisecond, nil
+})
 
-	// According to GetTickCount64 the resolution is limited to between 10 to 16
-	// milliseconds so truncate the time as to not mislead anyone about the
-	// resolution.
-	bootTime := time.Now().Add(-1 * time.Duration(msSinceBoot) * time.Millisecond)
-	bootTime = bootTime.Truncate(10 * time.Millisecond)
+func BootTime() (time.Time, error) {
+	bootTime, err := bootClock.BootTime()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get boot time")
+	}
 	return bootTime, nil
 }
+
+var bootIDOnce struct {
+	sync.Once
+	id string
+}
+
+// bootID returns the BootId counter that Windows increments on each boot. It
+// doesn't change while the current process is running so it is only read
+// once. An empty string is returned if it cannot be read.
+func bootID() string {
+	bootIDOnce.Do(func() {
+		const path = `SYSTEM\CurrentControlSet\Control\Session Manager\Memory Management\PrefetchParameters`
+		k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+		if err != nil {
+			return
+		}
+		defer k.Close()
+
+		if v, _, err := k.GetIntegerValue("BootId"); err == nil {
+			bootIDOnce.id = strconv.FormatUint(v, 10)
+		}
+	})
+	return bootIDOnce.id
+}
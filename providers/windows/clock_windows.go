@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	w32timeParametersKey = `SYSTEM\CurrentControlSet\Services\W32Time\Parameters`
+	timeZonesKey         = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\Time Zones`
+)
+
+// ClockInfo returns the time provider configured for the Windows Time service
+// (w32time) and the version of the time zone data. The clock is considered
+// synchronized when the service is running with a provider other than
+// NoSync. The offset is not reported because w32time only exposes it through
+// w32tm.exe.
+func (h *host) ClockInfo() (*types.ClockStatus, error) {
+	status := &types.ClockStatus{}
+
+	if k, err := registry.OpenKey(registry.LOCAL_MACHINE, w32timeParametersKey, registry.QUERY_VALUE); err == nil {
+		status.Source, _, _ = k.GetStringValue("Type")
+		k.Close()
+	}
+
+	if status.Source != "" && status.Source != "NoSync" {
+		status.Synchronized, _ = serviceRunning("W32Time")
+	}
+
+	if v, err := registryDWORD(timeZonesKey, "TzVersion"); err == nil {
+		status.TZDataVersion = tzVersion(v)
+	}
+	return status, nil
+}
+
+// tzVersion formats the TzVersion registry value. The high word is the year
+// of the IANA release and the low word is the update for that year.
+func tzVersion(v uint64) string {
+	return fmt.Sprintf("%d.%d", v>>16, v&0xffff)
+}
@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	bcdObjectsKey = `BCD00000000\Objects`
+
+	// GUID of the Windows Boot Manager object ({bootmgr}).
+	bcdBootMgrGUID = "{9dea862c-5cdd-4e70-acc1-f32b344d4795}"
+
+	// BcdBootMgrObject_DefaultObject element.
+	bcdDefaultObject = 0x23000003
+)
+
+type bcdElementFormat int
+
+const (
+	bcdString bcdElementFormat = iota
+	bcdBoolean
+	bcdInteger
+)
+
+// bcdElements are the boot entry settings that are reported. Names and values
+// match the output of bcdedit.
+var bcdElements = []struct {
+	element uint32
+	name    string
+	format  bcdElementFormat
+	values  []string // Names of integer values.
+}{
+	{0x12000004, "description", bcdString, nil},
+	{0x12000002, "path", bcdString, nil},
+	{0x22000002, "systemroot", bcdString, nil},
+	{0x16000049, "testsigning", bcdBoolean, nil},
+	{0x16000048, "nointegritychecks", bcdBoolean, nil},
+	{0x16000010, "bootdebug", bcdBoolean, nil},
+	{0x260000a0, "debug", bcdBoolean, nil},
+	{0x25000020, "nx", bcdInteger, []string{"OptIn", "OptOut", "AlwaysOff", "AlwaysOn"}},
+	{0x250000f0, "hypervisorlaunchtype", bcdInteger, []string{"Off", "Auto"}},
+}
+
+// KernelCmdline returns the settings of the default boot entry from the BCD
+// store which is mounted in the registry at HKLM\BCD00000000. Reading it
+// requires administrator rights. Elements that are not set are omitted.
+func (h *host) KernelCmdline() (*types.KernelCmdlineInfo, error) {
+	entry, err := bcdStringElement(bcdBootMgrGUID, bcdDefaultObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the default boot entry")
+	}
+
+	info := &types.KernelCmdlineInfo{Parameters: []types.KernelCmdlineParam{}}
+	for _, e := range bcdElements {
+		var value string
+		switch e.format {
+		case bcdString:
+			if value, err = bcdStringElement(entry, e.element); err != nil {
+				continue
+			}
+		default:
+			data, err := bcdBinaryElement(entry, e.element)
+			if err != nil || len(data) == 0 {
+				continue
+			}
+			value = bcdValue(data, e.format, e.values)
+		}
+		info.Parameters = append(info.Parameters, types.KernelCmdlineParam{Name: e.name, Value: value})
+	}
+	return info, nil
+}
+
+func bcdElementKey(object string, element uint32) (registry.Key, error) {
+	path := fmt.Sprintf(`%v\%v\Elements\%08x`, bcdObjectsKey, object, element)
+	return registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+}
+
+func bcdStringElement(object string, element uint32) (string, error) {
+	k, err := bcdElementKey(object, element)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+
+	v, _, err := k.GetStringValue("Element")
+	return v, err
+}
+
+func bcdBinaryElement(object string, element uint32) ([]byte, error) {
+	k, err := bcdElementKey(object, element)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+
+	v, _, err := k.GetBinaryValue("Element")
+	return v, err
+}
+
+// bcdValue formats a boolean or integer element. Booleans are stored as a
+// single byte and integers as a little-endian uint64.
+func bcdValue(data []byte, format bcdElementFormat, names []string) string {
+	if format == bcdBoolean {
+		if data[0] != 0 {
+			return "Yes"
+		}
+		return "No"
+	}
+
+	buf := make([]byte, 8)
+	copy(buf, data)
+	v := binary.LittleEndian.Uint64(buf)
+	if v < uint64(len(names)) {
+		return names[v]
+	}
+	return fmt.Sprintf("%d", v)
+}
@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// jobObjectSiloBasicInformation is the JOBOBJECTINFOCLASS that returns a
+// siloObjectBasicInformation.
+const jobObjectSiloBasicInformation = 36
+
+// ContainerType registry values.
+const (
+	containerTypeProcess = 1
+	containerTypeHyperV  = 2
+)
+
+// siloObjectBasicInformation is the SILOOBJECT_BASIC_INFORMATION structure.
+type siloObjectBasicInformation struct {
+	SiloID            uint32
+	SiloParentID      uint32
+	NumberOfProcesses uint32
+	IsInServerSilo    uint8
+	_                 [3]byte
+}
+
+// IsContainerized returns true if this process is running in a Windows
+// container. Windows sets the ContainerType value in the SYSTEM hive of
+// containers and runs their processes in a server silo.
+func IsContainerized() (bool, error) {
+	if _, found, err := containerType(); err != nil || found {
+		return found, err
+	}
+	return inServerSilo(), nil
+}
+
+// ContainerInfo returns the isolation mode of the Windows container that this
+// process is running in. The container ID is not visible from inside a
+// Windows container so it is left empty.
+func ContainerInfo() (*types.ContainerInfo, error) {
+	typ, _, err := containerType()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.ContainerInfo{}
+	switch typ {
+	case containerTypeProcess:
+		info.Isolation = types.ContainerIsolationProcess
+	case containerTypeHyperV:
+		info.Isolation = types.ContainerIsolationHyperV
+	}
+	return info, nil
+}
+
+func containerType() (value uint64, found bool, err error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control`, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, false, errors.Wrap(err, `failed to open HKLM\SYSTEM\CurrentControlSet\Control`)
+	}
+	defer k.Close()
+
+	value, _, err = k.GetIntegerValue("ContainerType")
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return 0, false, nil
+		}
+		return 0, false, errors.Wrap(err, "failed to read ContainerType")
+	}
+	return value, true, nil
+}
+
+// inServerSilo returns true if the current process is in a server silo.
+// Silos were added in Windows 10 1607 and Server 2016 so the query fails on
+// older versions.
+func inServerSilo() bool {
+	var info siloObjectBasicInformation
+	if err := _QueryInformationJobObject(0, jobObjectSiloBasicInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		return false
+	}
+	return info.IsInServerSilo != 0
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"runtime"
+
+	windows "github.com/elastic/go-windows"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// sizeofProcessorPowerInformation is the size of PROCESSOR_POWER_INFORMATION.
+const sizeofProcessorPowerInformation = 24
+
+// CPUFrequency returns the current and maximum frequency of each logical CPU
+// as reported by CallNtPowerInformation(ProcessorInformation).
+func (h *host) CPUFrequency() ([]types.CPUFrequencyInfo, error) {
+	buf := make([]byte, runtime.NumCPU()*sizeofProcessorPowerInformation)
+	status := _CallNtPowerInformation(processorInformation, nil, 0, &buf[0], uint32(len(buf)))
+	if status != 0 {
+		return nil, errors.Wrap(windows.NTStatus(status), "CallNtPowerInformation failed")
+	}
+
+	return parseProcessorPowerInformation(buf)
+}
+
+// parseProcessorPowerInformation parses an array of
+// PROCESSOR_POWER_INFORMATION structures.
+func parseProcessorPowerInformation(buf []byte) ([]types.CPUFrequencyInfo, error) {
+	if len(buf)%sizeofProcessorPowerInformation != 0 {
+		return nil, errors.Errorf("invalid PROCESSOR_POWER_INFORMATION buffer length %d", len(buf))
+	}
+
+	const mhz = 1000 * 1000
+	freqs := make([]types.CPUFrequencyInfo, 0, len(buf)/sizeofProcessorPowerInformation)
+	for ; len(buf) > 0; buf = buf[sizeofProcessorPowerInformation:] {
+		freqs = append(freqs, types.CPUFrequencyInfo{
+			CPU:     int(binary.LittleEndian.Uint32(buf[0:])),
+			Max:     uint64(binary.LittleEndian.Uint32(buf[4:])) * mhz,
+			Current: uint64(binary.LittleEndian.Uint32(buf[8:])) * mhz,
+		})
+	}
+	return freqs, nil
+}
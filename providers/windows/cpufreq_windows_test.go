@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseProcessorPowerInformation(t *testing.T) {
+	buf := make([]byte, 2*sizeofProcessorPowerInformation)
+	for i := 0; i < 2; i++ {
+		b := buf[i*sizeofProcessorPowerInformation:]
+		binary.LittleEndian.PutUint32(b[0:], uint32(i))
+		binary.LittleEndian.PutUint32(b[4:], 3000)
+		binary.LittleEndian.PutUint32(b[8:], uint32(2400+100*i))
+	}
+
+	freqs, err := parseProcessorPowerInformation(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []types.CPUFrequencyInfo{
+		{CPU: 0, Current: 2400000000, Max: 3000000000},
+		{CPU: 1, Current: 2500000000, Max: 3000000000},
+	}, freqs)
+
+	_, err = parseProcessorPowerInformation(buf[:10])
+	assert.Error(t, err)
+}
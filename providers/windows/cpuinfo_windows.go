@@ -0,0 +1,210 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// LOGICAL_PROCESSOR_RELATIONSHIP values.
+const (
+	relationProcessorCore    = 0
+	relationCache            = 2
+	relationProcessorPackage = 3
+	relationAll              = 0xffff
+)
+
+// processorFeatures maps the PF_* values of IsProcessorFeaturePresent to the
+// flag names used on Linux. Versions of Windows that predate a feature value
+// (e.g. AVX2 before Windows 10 21H2) report it as not present.
+var processorFeatures = []struct {
+	feature uint32
+	name    string
+}{
+	{3, "mmx"},
+	{6, "sse"},
+	{8, "tsc"},
+	{9, "pae"},
+	{10, "sse2"},
+	{12, "nx"},
+	{13, "sse3"},
+	{14, "cx16"},
+	{19, "neon"},
+	{22, "fsgsbase"},
+	{28, "rdrand"},
+	{30, "crypto"},
+	{31, "crc32"},
+	{32, "rdtscp"},
+	{33, "rdpid"},
+	{34, "atomics"},
+	{36, "ssse3"},
+	{37, "sse4_1"},
+	{38, "sse4_2"},
+	{39, "avx"},
+	{40, "avx2"},
+	{41, "avx512f"},
+}
+
+// CPUInfo returns the processor model and topology. The result is cached in
+// shared.StaticCache.
+func (h *host) CPUInfo() (*types.ProcessorInfo, error) {
+	v, err := h.cache.Get("cpuinfo", func() (interface{}, error) {
+		return readCPUInfo()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.CopyProcessorInfo(v.(*types.ProcessorInfo)), nil
+}
+
+func readCPUInfo() (*types.ProcessorInfo, error) {
+	info := &types.ProcessorInfo{}
+
+	buf, err := getLogicalProcessorInformation()
+	if err != nil {
+		return nil, err
+	}
+	if err = parseLogicalProcessorInformation(buf, info); err != nil {
+		return nil, err
+	}
+
+	const key = registry.LOCAL_MACHINE
+	const path = `HARDWARE\DESCRIPTION\System\CentralProcessor\0`
+
+	k, err := registry.OpenKey(key, path, registry.READ)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to open HKLM\%v`, path)
+	}
+	defer k.Close()
+
+	info.ModelName, _, _ = k.GetStringValue("ProcessorNameString")
+	info.Vendor, _, _ = k.GetStringValue("VendorIdentifier")
+	if mhz, _, err := k.GetIntegerValue("~MHz"); err == nil {
+		info.BaseFrequency = mhz * 1000000
+	}
+
+	for _, f := range processorFeatures {
+		if _IsProcessorFeaturePresent(f.feature) {
+			info.Flags = append(info.Flags, f.name)
+		}
+	}
+
+	return info, nil
+}
+
+func getLogicalProcessorInformation() ([]byte, error) {
+	var size uint32
+	err := _GetLogicalProcessorInformationEx(relationAll, nil, &size)
+	if err != syscall.ERROR_INSUFFICIENT_BUFFER {
+		return nil, errors.Wrap(err, "GetLogicalProcessorInformationEx failed")
+	}
+
+	buf := make([]byte, size)
+	if err = _GetLogicalProcessorInformationEx(relationAll, &buf[0], &size); err != nil {
+		return nil, errors.Wrap(err, "GetLogicalProcessorInformationEx failed")
+	}
+	return buf[:size], nil
+}
+
+// parseLogicalProcessorInformation counts the packages, cores, and logical
+// processors and collects the caches from an array of variable sized
+// SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX structures.
+func parseLogicalProcessorInformation(buf []byte, info *types.ProcessorInfo) error {
+	// Size of GROUP_AFFINITY (KAFFINITY mask, group, and 3 reserved words).
+	groupAffinitySize := int(unsafe.Sizeof(uintptr(0))) + 8
+
+	type cacheKey struct {
+		level int
+		typ   string
+	}
+	caches := map[cacheKey]struct{}{}
+
+	le := binary.LittleEndian
+	for len(buf) >= 8 {
+		relationship, size := le.Uint32(buf), int(le.Uint32(buf[4:]))
+		if size < 8 || size > len(buf) {
+			return errors.Errorf("invalid SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX size %d", size)
+		}
+		record := buf[:size]
+		buf = buf[size:]
+
+		switch relationship {
+		case relationProcessorPackage:
+			info.Sockets++
+		case relationProcessorCore:
+			info.PhysicalCores++
+
+			// PROCESSOR_RELATIONSHIP.GroupCount and GroupMask.
+			if len(record) < 32 {
+				continue
+			}
+			groups := int(le.Uint16(record[30:]))
+			for i := 0; i < groups; i++ {
+				offset := 32 + i*groupAffinitySize
+				if offset+8 > len(record) {
+					break
+				}
+				var mask uint64
+				if groupAffinitySize == 16 {
+					mask = le.Uint64(record[offset:])
+				} else {
+					mask = uint64(le.Uint32(record[offset:]))
+				}
+				for ; mask != 0; mask &= mask - 1 {
+					info.LogicalCores++
+				}
+			}
+		case relationCache:
+			// CACHE_RELATIONSHIP Level, Associativity, LineSize, CacheSize,
+			// and Type.
+			if len(record) < 20 {
+				continue
+			}
+			c := types.CPUCacheInfo{
+				Level: int(record[8]),
+				Size:  uint64(le.Uint32(record[12:])),
+			}
+			switch le.Uint32(record[16:]) {
+			case 0:
+				c.Type = types.CPUCacheUnified
+			case 1:
+				c.Type = types.CPUCacheInstruction
+			case 2:
+				c.Type = types.CPUCacheData
+			default:
+				continue // Trace cache.
+			}
+
+			key := cacheKey{c.Level, c.Type}
+			if _, found := caches[key]; found {
+				continue
+			}
+			caches[key] = struct{}{}
+			info.Caches = append(info.Caches, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseLogicalProcessorInformation(t *testing.T) {
+	le := binary.LittleEndian
+	record := func(relationship uint32, size int) []byte {
+		b := make([]byte, size)
+		le.PutUint32(b, relationship)
+		le.PutUint32(b[4:], uint32(size))
+		return b
+	}
+
+	var buf []byte
+
+	pkg := record(relationProcessorPackage, 48)
+	buf = append(buf, pkg...)
+
+	// Two cores with two hardware threads each.
+	for _, mask := range []uint32{0x3, 0xc} {
+		core := record(relationProcessorCore, 32+int(unsafe.Sizeof(uintptr(0)))+8)
+		le.PutUint16(core[30:], 1)
+		le.PutUint32(core[32:], mask)
+		buf = append(buf, core...)
+	}
+
+	for _, c := range []struct {
+		level byte
+		size  uint32
+		typ   uint32
+	}{
+		{1, 32 << 10, 2},
+		{1, 32 << 10, 1},
+		{1, 32 << 10, 2}, // L1d of the second core.
+		{2, 256 << 10, 0},
+	} {
+		cache := record(relationCache, 48)
+		cache[8] = c.level
+		le.PutUint32(cache[12:], c.size)
+		le.PutUint32(cache[16:], c.typ)
+		buf = append(buf, cache...)
+	}
+
+	info := &types.ProcessorInfo{}
+	if err := parseLogicalProcessorInformation(buf, info); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, info.Sockets)
+	assert.Equal(t, 2, info.PhysicalCores)
+	assert.Equal(t, 4, info.LogicalCores)
+	assert.Equal(t, []types.CPUCacheInfo{
+		{Level: 1, Type: types.CPUCacheData, Size: 32 << 10},
+		{Level: 1, Type: types.CPUCacheInstruction, Size: 32 << 10},
+		{Level: 2, Type: types.CPUCacheUnified, Size: 256 << 10},
+	}, info.Caches)
+
+	truncated := record(relationCache, 48)[:16]
+	assert.Error(t, parseLogicalProcessorInformation(truncated, info))
+}
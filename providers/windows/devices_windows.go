@@ -0,0 +1,193 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"regexp"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// spDevinfoData is the SP_DEVINFO_DATA structure.
+type spDevinfoData struct {
+	Size      uint32
+	ClassGUID syscall.GUID
+	DevInst   uint32
+	Reserved  uintptr
+}
+
+// SetupAPI and Configuration Manager constants.
+const (
+	digcfPresent    = 0x2
+	digcfAllClasses = 0x4
+
+	spdrpDeviceDesc   = 0x0
+	spdrpCompatibleID = 0x2
+	spdrpService      = 0x4
+	spdrpMfg          = 0xB
+
+	errorNoMoreItems = syscall.Errno(259)
+
+	crSuccess = 0
+)
+
+var (
+	pciInstanceIDRegexp = regexp.MustCompile(`(?i)^PCI\\VEN_([0-9A-F]{4})&DEV_([0-9A-F]{4})`)
+	usbInstanceIDRegexp = regexp.MustCompile(`(?i)^USB\\VID_([0-9A-F]{4})&PID_([0-9A-F]{4})`)
+	pciClassRegexp      = regexp.MustCompile(`(?i)\\CC_([0-9A-F]{6})$`)
+	usbClassRegexp      = regexp.MustCompile(`(?i)\\Class_([0-9A-F]{2})`)
+)
+
+// Devices lists the present PCI and USB devices using SetupAPI. The IDs are
+// the device instance IDs (e.g. USB\VID_046D&PID_C52B\5&2D5F4F0&0&2).
+func (h *host) Devices() ([]types.DeviceInfo, error) {
+	var devices []types.DeviceInfo
+	for _, bus := range []string{types.DeviceBusPCI, types.DeviceBusUSB} {
+		busDevices, err := setupDiDevices(bus)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, busDevices...)
+	}
+
+	ids := make(map[string]struct{}, len(devices))
+	for _, d := range devices {
+		ids[d.ID] = struct{}{}
+	}
+	for i := range devices {
+		if _, found := ids[devices[i].Parent]; !found {
+			devices[i].Parent = ""
+		}
+	}
+	return devices, nil
+}
+
+func setupDiDevices(bus string) ([]types.DeviceInfo, error) {
+	enumerator, err := syscall.UTF16PtrFromString(strings.ToUpper(bus))
+	if err != nil {
+		return nil, err
+	}
+	set, err := _SetupDiGetClassDevs(nil, enumerator, 0, digcfPresent|digcfAllClasses)
+	if err != nil {
+		return nil, errors.Wrapf(err, "SetupDiGetClassDevs failed for %v", bus)
+	}
+	defer _SetupDiDestroyDeviceInfoList(set)
+
+	var devices []types.DeviceInfo
+	for i := uint32(0); ; i++ {
+		data := spDevinfoData{Size: uint32(unsafe.Sizeof(spDevinfoData{}))}
+		if err := _SetupDiEnumDeviceInfo(set, i, &data); err != nil {
+			if err == errorNoMoreItems {
+				break
+			}
+			return nil, errors.Wrap(err, "SetupDiEnumDeviceInfo failed")
+		}
+
+		var buf [256]uint16
+		if err := _SetupDiGetDeviceInstanceId(set, &data, &buf[0], uint32(len(buf)), nil); err != nil {
+			continue
+		}
+		id := syscall.UTF16ToString(buf[:])
+
+		// Skip the interfaces of composite USB devices.
+		if bus == types.DeviceBusUSB && strings.Contains(strings.ToUpper(id), "&MI_") {
+			continue
+		}
+
+		dev := types.DeviceInfo{
+			Bus:     bus,
+			ID:      id,
+			Parent:  deviceParent(data.DevInst),
+			Vendor:  deviceRegistryProperty(set, &data, spdrpMfg),
+			Product: deviceRegistryProperty(set, &data, spdrpDeviceDesc),
+			Driver:  deviceRegistryProperty(set, &data, spdrpService),
+		}
+		parseDeviceInstanceID(&dev, deviceRegistryProperty(set, &data, spdrpCompatibleID))
+		devices = append(devices, dev)
+	}
+	return devices, nil
+}
+
+// parseDeviceInstanceID sets the vendor and product IDs from the instance ID
+// and the class from the compatible IDs. A USB instance ID ends with the
+// serial number when the device has one.
+func parseDeviceInstanceID(dev *types.DeviceInfo, compatibleIDs string) {
+	idRegexp, classRegexp := pciInstanceIDRegexp, pciClassRegexp
+	if dev.Bus == types.DeviceBusUSB {
+		idRegexp, classRegexp = usbInstanceIDRegexp, usbClassRegexp
+	}
+
+	if m := idRegexp.FindStringSubmatch(dev.ID); m != nil {
+		dev.VendorID, dev.ProductID = strings.ToLower(m[1]), strings.ToLower(m[2])
+	}
+	for _, id := range strings.Split(compatibleIDs, "\n") {
+		if m := classRegexp.FindStringSubmatch(id); m != nil {
+			dev.Class = strings.ToLower(m[1])
+			break
+		}
+	}
+
+	if dev.Bus == types.DeviceBusUSB && dev.VendorID != "" {
+		parts := strings.Split(dev.ID, `\`)
+		if serial := parts[len(parts)-1]; len(parts) == 3 && !strings.Contains(serial, "&") {
+			dev.Serial = serial
+		}
+	}
+}
+
+// deviceRegistryProperty returns a string property of a device. The strings
+// of REG_MULTI_SZ properties are separated by newlines.
+func deviceRegistryProperty(set syscall.Handle, data *spDevinfoData, property uint32) string {
+	var buf [1024]uint16
+	var dataType uint32
+	if err := _SetupDiGetDeviceRegistryProperty(set, data, property, &dataType,
+		(*byte)(unsafe.Pointer(&buf[0])), uint32(len(buf)*2), nil); err != nil {
+		return ""
+	}
+
+	var values []string
+	for start, i := 0, 0; i < len(buf); i++ {
+		if buf[i] != 0 {
+			continue
+		}
+		if i == start {
+			break
+		}
+		values = append(values, syscall.UTF16ToString(buf[start:i]))
+		start = i + 1
+	}
+	return strings.Join(values, "\n")
+}
+
+// deviceParent returns the instance ID of the parent of a device.
+func deviceParent(devInst uint32) string {
+	var parent uint32
+	if _CM_Get_Parent(&parent, devInst, 0) != crSuccess {
+		return ""
+	}
+	var buf [256]uint16
+	if _CM_Get_Device_ID(parent, &buf[0], uint32(len(buf)), 0) != crSuccess {
+		return ""
+	}
+	return syscall.UTF16ToString(buf[:])
+}
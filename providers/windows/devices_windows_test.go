@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseDeviceInstanceID(t *testing.T) {
+	dev := types.DeviceInfo{Bus: types.DeviceBusPCI, ID: `PCI\VEN_8086&DEV_9D2F&SUBSYS_224517AA&REV_21\3&11583659&0&A0`}
+	parseDeviceInstanceID(&dev, "PCI\\VEN_8086&DEV_9D2F&REV_21\nPCI\\VEN_8086&DEV_9D2F\nPCI\\VEN_8086&CC_0C0330\nPCI\\VEN_8086&CC_0C03\nPCI\\VEN_8086\nPCI\\CC_0C0330\nPCI\\CC_0C03")
+	assert.Equal(t, "8086", dev.VendorID)
+	assert.Equal(t, "9d2f", dev.ProductID)
+	assert.Equal(t, "0c0330", dev.Class)
+	assert.Empty(t, dev.Serial)
+
+	dev = types.DeviceInfo{Bus: types.DeviceBusUSB, ID: `USB\VID_0781&PID_5581\4C530001131018117135`}
+	parseDeviceInstanceID(&dev, "USB\\Class_08&SubClass_06&Prot_50\nUSB\\Class_08&SubClass_06\nUSB\\Class_08")
+	assert.Equal(t, "0781", dev.VendorID)
+	assert.Equal(t, "5581", dev.ProductID)
+	assert.Equal(t, "08", dev.Class)
+	assert.Equal(t, "4C530001131018117135", dev.Serial)
+
+	// Devices without a serial number have a generated instance ID.
+	dev = types.DeviceInfo{Bus: types.DeviceBusUSB, ID: `USB\VID_046D&PID_C52B\5&2D5F4F0&0&2`}
+	parseDeviceInstanceID(&dev, "")
+	assert.Empty(t, dev.Serial)
+}
@@ -0,0 +1,193 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	ioctlStorageQueryProperty = 0x2d1400 // IOCTL_STORAGE_QUERY_PROPERTY
+	smartSendDriveCommand     = 0x7c084  // SMART_SEND_DRIVE_COMMAND
+	smartRcvDriveData         = 0x7c088  // SMART_RCV_DRIVE_DATA
+
+	storageDeviceProperty                 = 0  // StorageDeviceProperty
+	storageDeviceProtocolSpecificProperty = 50 // StorageDeviceProtocolSpecificProperty
+	propertyStandardQuery                 = 0  // PropertyStandardQuery
+	protocolTypeNvme                      = 3  // ProtocolTypeNvme
+	nvmeDataTypeLogPage                   = 2  // NVMeDataTypeLogPage
+
+	busTypeAta  = 3  // BusTypeAta
+	busTypeSata = 11 // BusTypeSata
+	busTypeNvme = 17 // BusTypeNvme
+
+	// Sizes of the packed SENDCMDINPARAMS and SENDCMDOUTPARAMS structs
+	// without their data buffers, and of the STORAGE_PROTOCOL_SPECIFIC_DATA
+	// struct.
+	sendCmdInParamsSize          = 32
+	sendCmdOutParamsSize         = 16
+	storageProtocolSpecificSize  = 40
+	storageDeviceDescriptorSize  = 40
+	storageDeviceDescriptorLimit = 1024
+
+	maxPhysicalDrives = 32
+)
+
+// DiskHealth reads the SMART data of the ATA and NVMe physical drives.
+// Drives are opened with read and write access which requires administrator
+// rights, otherwise only their identity is reported.
+func (h *host) DiskHealth() ([]types.DiskHealthInfo, error) {
+	var disks []types.DiskHealthInfo
+	for i := 0; i < maxPhysicalDrives; i++ {
+		name := fmt.Sprintf("PhysicalDrive%d", i)
+		pathW, err := syscall.UTF16PtrFromString(`\\.\` + name)
+		if err != nil {
+			return nil, err
+		}
+
+		handle, err := syscall.CreateFile(pathW, syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+			syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+		if err == syscall.ERROR_ACCESS_DENIED {
+			handle, err = syscall.CreateFile(pathW, 0,
+				syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+		}
+		if err != nil {
+			continue
+		}
+
+		if info, ok := physicalDriveHealth(handle, name); ok {
+			disks = append(disks, info)
+		}
+		syscall.CloseHandle(handle)
+	}
+	return disks, nil
+}
+
+func physicalDriveHealth(handle syscall.Handle, name string) (types.DiskHealthInfo, bool) {
+	query := make([]byte, 12)
+	binary.LittleEndian.PutUint32(query, storageDeviceProperty)
+	binary.LittleEndian.PutUint32(query[4:], propertyStandardQuery)
+	desc := make([]byte, storageDeviceDescriptorLimit)
+	n, err := deviceIoControl(handle, ioctlStorageQueryProperty, query, desc)
+	if err != nil {
+		return types.DiskHealthInfo{}, false
+	}
+
+	info, busType := parseStorageDeviceDescriptor(desc[:n])
+	info.Name = name
+	switch busType {
+	case busTypeAta, busTypeSata:
+		info.Protocol = types.DiskProtocolATA
+		readATASMART(handle, &info)
+	case busTypeNvme:
+		info.Protocol = types.DiskProtocolNVMe
+		if data, err := nvmeSMARTLog(handle); err == nil {
+			shared.ParseNVMeSMARTLog(data, &info)
+		}
+	default:
+		return types.DiskHealthInfo{}, false
+	}
+	return info, true
+}
+
+// parseStorageDeviceDescriptor returns the product and serial number from a
+// STORAGE_DEVICE_DESCRIPTOR and its bus type.
+func parseStorageDeviceDescriptor(desc []byte) (types.DiskHealthInfo, uint32) {
+	var info types.DiskHealthInfo
+	if len(desc) < storageDeviceDescriptorSize {
+		return info, 0
+	}
+
+	str := func(offset uint32) string {
+		if offset == 0 || int(offset) >= len(desc) {
+			return ""
+		}
+		s := desc[offset:]
+		if i := strings.IndexByte(string(s), 0); i >= 0 {
+			s = s[:i]
+		}
+		return strings.TrimSpace(string(s))
+	}
+	info.Model = str(binary.LittleEndian.Uint32(desc[16:]))
+	info.Serial = str(binary.LittleEndian.Uint32(desc[24:]))
+	return info, binary.LittleEndian.Uint32(desc[28:])
+}
+
+// nvmeSMARTLog reads the SMART / Health Information log page through the
+// protocol specific storage property.
+func nvmeSMARTLog(handle syscall.Handle) ([]byte, error) {
+	// STORAGE_PROPERTY_QUERY followed by STORAGE_PROTOCOL_SPECIFIC_DATA and
+	// the buffer for the log page.
+	buf := make([]byte, 8+storageProtocolSpecificSize+shared.SMARTDataSize)
+	binary.LittleEndian.PutUint32(buf, storageDeviceProtocolSpecificProperty)
+	binary.LittleEndian.PutUint32(buf[4:], propertyStandardQuery)
+	binary.LittleEndian.PutUint32(buf[8:], protocolTypeNvme)
+	binary.LittleEndian.PutUint32(buf[12:], nvmeDataTypeLogPage)
+	binary.LittleEndian.PutUint32(buf[16:], shared.NVMeSMARTLogPage)
+	binary.LittleEndian.PutUint32(buf[24:], storageProtocolSpecificSize)
+	binary.LittleEndian.PutUint32(buf[28:], shared.SMARTDataSize)
+
+	// The result is a STORAGE_PROTOCOL_DATA_DESCRIPTOR whose protocol data
+	// offset is relative to its ProtocolSpecificData member.
+	out := make([]byte, len(buf))
+	if _, err := deviceIoControl(handle, ioctlStorageQueryProperty, buf, out); err != nil {
+		return nil, err
+	}
+	offset := 8 + int(binary.LittleEndian.Uint32(out[24:]))
+	if offset+shared.SMARTDataSize > len(out) {
+		return nil, syscall.EINVAL
+	}
+	return out[offset : offset+shared.SMARTDataSize], nil
+}
+
+// readATASMART reads the SMART status and attributes of an ATA drive.
+func readATASMART(handle syscall.Handle, info *types.DiskHealthInfo) {
+	out := make([]byte, sendCmdOutParamsSize+8)
+	if _, err := deviceIoControl(handle, smartSendDriveCommand, sendCmdInParams(shared.ATASMARTReturnStatus), out); err == nil {
+		// The IDEREGS are returned in the buffer.
+		regs := out[sendCmdOutParamsSize:]
+		info.Status = shared.ATASMARTStatus(regs[3], regs[4])
+	}
+
+	out = make([]byte, sendCmdOutParamsSize+shared.SMARTDataSize)
+	if _, err := deviceIoControl(handle, smartRcvDriveData, sendCmdInParams(shared.ATASMARTReadData), out); err == nil {
+		shared.ParseATASMARTData(out[sendCmdOutParamsSize:], info)
+	}
+}
+
+// sendCmdInParams returns a SENDCMDINPARAMS struct for a SMART command.
+func sendCmdInParams(feature byte) []byte {
+	in := make([]byte, sendCmdInParamsSize+1)
+	binary.LittleEndian.PutUint32(in, shared.SMARTDataSize)
+	// IDEREGS: features, sector count, sector number, LBA mid, LBA high,
+	// drive/head, and command.
+	copy(in[4:], []byte{feature, 1, 1, shared.ATASMARTLBAMid, shared.ATASMARTLBAHigh, 0xa0, shared.ATASMARTCommand})
+	return in
+}
+
+func deviceIoControl(handle syscall.Handle, code uint32, in, out []byte) (int, error) {
+	var returned uint32
+	err := syscall.DeviceIoControl(handle, code, &in[0], uint32(len(in)), &out[0], uint32(len(out)), &returned, nil)
+	return int(returned), err
+}
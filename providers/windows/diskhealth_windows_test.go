@@ -0,0 +1,39 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStorageDeviceDescriptor(t *testing.T) {
+	desc := make([]byte, storageDeviceDescriptorSize)
+	binary.LittleEndian.PutUint32(desc[16:], uint32(len(desc)))
+	desc = append(desc, "Samsung SSD 970 EVO Plus 500GB\x00"...)
+	binary.LittleEndian.PutUint32(desc[24:], uint32(len(desc)))
+	desc = append(desc, "0025_3852_91B2_3C4D.\x00"...)
+	binary.LittleEndian.PutUint32(desc[28:], busTypeNvme)
+
+	info, busType := parseStorageDeviceDescriptor(desc)
+	assert.EqualValues(t, busTypeNvme, busType)
+	assert.Equal(t, "Samsung SSD 970 EVO Plus 500GB", info.Model)
+	assert.Equal(t, "0025_3852_91B2_3C4D.", info.Serial)
+}
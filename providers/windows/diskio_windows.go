@@ -0,0 +1,117 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// ioctlDiskPerformance (IOCTL_DISK_PERFORMANCE) returns the performance
+// counters of a disk.
+const ioctlDiskPerformance = 0x70020
+
+// diskPerformance is Go's counterpart of the DISK_PERFORMANCE struct.
+type diskPerformance struct {
+	BytesRead           int64
+	BytesWritten        int64
+	ReadTime            int64
+	WriteTime           int64
+	IdleTime            int64
+	ReadCount           uint32
+	WriteCount          uint32
+	QueueDepth          uint32
+	SplitCount          uint32
+	QueryTime           int64
+	StorageDeviceNumber uint32
+	StorageManagerName  [8]uint16
+}
+
+// DiskIOCounters returns the I/O statistics of the fixed logical drives.
+// Drives that cannot be queried are skipped.
+func (h *host) DiskIOCounters() ([]types.DiskIOInfo, error) {
+	mask, err := devMapper.GetLogicalDrives()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetLogicalDrives failed")
+	}
+
+	var disks []types.DiskIOInfo
+	for bit := uint32(0); bit < uint32('Z'-'A'+1); bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		name := string(byte('A'+bit)) + ":"
+
+		rootW, err := syscall.UTF16PtrFromString(name + "\\")
+		if err != nil {
+			return nil, err
+		}
+		if syswin.GetDriveType(rootW) != syswin.DRIVE_FIXED {
+			continue
+		}
+
+		perf, err := getDiskPerformance(`\\.\` + name)
+		if err != nil {
+			continue
+		}
+
+		// Times are in 100-nanosecond intervals.
+		disks = append(disks, types.DiskIOInfo{
+			Name:          name,
+			ReadCount:     uint64(perf.ReadCount),
+			WriteCount:    uint64(perf.WriteCount),
+			ReadBytes:     uint64(perf.BytesRead),
+			WriteBytes:    uint64(perf.BytesWritten),
+			ReadTime:      time.Duration(perf.ReadTime) * 100,
+			WriteTime:     time.Duration(perf.WriteTime) * 100,
+			IOsInProgress: uint64(perf.QueueDepth),
+		})
+	}
+	return disks, nil
+}
+
+func getDiskPerformance(path string) (*diskPerformance, error) {
+	pathW, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// No access rights are needed to query the device.
+	handle, err := syscall.CreateFile(pathW, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil,
+		syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CreateFile failed for %v", path)
+	}
+	defer syscall.CloseHandle(handle)
+
+	var perf diskPerformance
+	var returned uint32
+	err = syscall.DeviceIoControl(handle, ioctlDiskPerformance, nil, 0,
+		(*byte)(unsafe.Pointer(&perf)), uint32(unsafe.Sizeof(perf)), &returned, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "DeviceIoControl failed for %v", path)
+	}
+	return &perf, nil
+}
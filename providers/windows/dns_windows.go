@@ -0,0 +1,172 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// GetAdaptersAddresses flags.
+	gaaFlagSkipUnicast   = 0x1
+	gaaFlagSkipAnycast   = 0x2
+	gaaFlagSkipMulticast = 0x4
+
+	ifOperStatusUp = 1
+
+	tcpipParametersKey = `SYSTEM\CurrentControlSet\Services\Tcpip\Parameters`
+	dnsClientPolicyKey = `SOFTWARE\Policies\Microsoft\Windows NT\DNSClient`
+)
+
+// DNSConfig returns the nameservers and DNS suffix of each connected adapter
+// from GetAdaptersAddresses. The global nameservers are the ones of all
+// adapters in the order of the adapter list, which Windows sorts by metric.
+func (h *host) DNSConfig() (*types.DNSConfigInfo, error) {
+	adapters, err := adapterAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.DNSConfigInfo{}
+	var suffixes []string
+	for aa := adapters; aa != nil; aa = aa.Next {
+		if aa.OperStatus != ifOperStatusUp {
+			continue
+		}
+
+		r := types.DNSResolverInfo{Interface: utf16PtrToString(aa.FriendlyName)}
+		for ds := aa.FirstDnsServerAddress; ds != nil; ds = ds.Next {
+			ip := socketAddressIP(&ds.Address)
+			if ip == nil || isDeprecatedSiteLocalDNS(ip) {
+				continue
+			}
+			r.Nameservers = append(r.Nameservers, ip.String())
+		}
+		if suffix := utf16PtrToString(aa.DnsSuffix); suffix != "" {
+			r.SearchDomains = []string{suffix}
+			suffixes = appendUnique(suffixes, suffix)
+		}
+		if len(r.Nameservers) == 0 && len(r.SearchDomains) == 0 {
+			continue
+		}
+
+		for _, ns := range r.Nameservers {
+			info.Nameservers = appendUnique(info.Nameservers, ns)
+		}
+		info.Resolvers = append(info.Resolvers, r)
+	}
+
+	info.SearchDomains = searchList(suffixes)
+	return info, nil
+}
+
+func adapterAddresses() (*syswin.IpAdapterAddresses, error) {
+	const flags = gaaFlagSkipUnicast | gaaFlagSkipAnycast | gaaFlagSkipMulticast
+
+	size := uint32(15 * 1024)
+	for {
+		buf := make([]byte, size)
+		aa := (*syswin.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := syswin.GetAdaptersAddresses(syscall.AF_UNSPEC, flags, 0, aa, &size)
+		if err == nil {
+			return aa, nil
+		}
+		if err != syscall.ERROR_BUFFER_OVERFLOW || size <= uint32(len(buf)) {
+			return nil, errors.Wrap(err, "GetAdaptersAddresses failed")
+		}
+	}
+}
+
+// searchList returns the DNS suffix search list. An explicitly configured
+// list (by policy or in the TCP/IP parameters) replaces the primary domain
+// and the suffixes of the adapters.
+func searchList(adapterSuffixes []string) []string {
+	for _, path := range []string{dnsClientPolicyKey, tcpipParametersKey} {
+		if list := registryString(path, "SearchList"); list != "" {
+			return strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+		}
+	}
+
+	var list []string
+	if domain := registryString(tcpipParametersKey, "Domain"); domain != "" {
+		list = append(list, domain)
+	}
+	for _, suffix := range adapterSuffixes {
+		list = appendUnique(list, suffix)
+	}
+	return list
+}
+
+func registryString(path, name string) string {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer k.Close()
+
+	v, _, err := k.GetStringValue(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(v)
+}
+
+func socketAddressIP(sa *syswin.SocketAddress) net.IP {
+	if sa.Sockaddr == nil {
+		return nil
+	}
+	switch sa.Sockaddr.Addr.Family {
+	case syscall.AF_INET:
+		addr := (*syscall.RawSockaddrInet4)(unsafe.Pointer(sa.Sockaddr)).Addr
+		return net.IP(addr[:])
+	case syscall.AF_INET6:
+		addr := (*syscall.RawSockaddrInet6)(unsafe.Pointer(sa.Sockaddr)).Addr
+		return net.IP(addr[:])
+	default:
+		return nil
+	}
+}
+
+// isDeprecatedSiteLocalDNS reports whether ip is one of the fec0:0:0:ffff::1-3
+// placeholders that Windows lists for adapters without IPv6 nameservers.
+func isDeprecatedSiteLocalDNS(ip net.IP) bool {
+	if ip.To4() != nil {
+		return false
+	}
+	prefix := []byte{0xfe, 0xc0, 0, 0, 0, 0, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0}
+	return bytes.Equal(ip[:15], prefix) && ip[15] >= 1 && ip[15] <= 3
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
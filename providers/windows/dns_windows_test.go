@@ -0,0 +1,33 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDeprecatedSiteLocalDNS(t *testing.T) {
+	assert.True(t, isDeprecatedSiteLocalDNS(net.ParseIP("fec0:0:0:ffff::1")))
+	assert.True(t, isDeprecatedSiteLocalDNS(net.ParseIP("fec0:0:0:ffff::3")))
+	assert.False(t, isDeprecatedSiteLocalDNS(net.ParseIP("fec0:0:0:ffff::4")))
+	assert.False(t, isDeprecatedSiteLocalDNS(net.ParseIP("2001:4860:4860::8888")))
+	assert.False(t, isDeprecatedSiteLocalDNS(net.ParseIP("192.168.1.1")))
+}
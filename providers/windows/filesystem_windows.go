@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// FileSystems returns the file systems of the logical drives. Drives that
+// cannot be queried (e.g. removable drives without media) are returned
+// without usage data. The BitLocker state is reported for each drive.
+func (s windowsSystem) FileSystems() ([]types.FileSystemInfo, error) {
+	mask, err := devMapper.GetLogicalDrives()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetLogicalDrives failed")
+	}
+
+	var filesystems []types.FileSystemInfo
+	for bit := uint32(0); bit < uint32('Z'-'A'+1); bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		driveLetter := byte('A' + bit)
+		root := string(driveLetter) + ":\\"
+		rootW, err := syscall.UTF16PtrFromString(root)
+		if err != nil {
+			return nil, err
+		}
+
+		switch syswin.GetDriveType(rootW) {
+		case syswin.DRIVE_UNKNOWN, syswin.DRIVE_NO_ROOT_DIR:
+			continue
+		}
+
+		fs := types.FileSystemInfo{MountPoint: root}
+		fs.Device, _ = devMapper.getDevice(driveLetter)
+
+		fsName := make([]uint16, syscall.MAX_PATH+1)
+		if err := syswin.GetVolumeInformation(rootW, nil, 0, nil, nil, nil, &fsName[0], uint32(len(fsName))); err == nil {
+			fs.Type = syscall.UTF16ToString(fsName)
+		}
+
+		if err := _GetDiskFreeSpaceEx(rootW, &fs.Available, &fs.Total, &fs.Free); err == nil {
+			fs.Used = fs.Total - fs.Free
+		}
+
+		filesystems = append(filesystems, fs)
+	}
+
+	readBitLockerStatus(filesystems)
+	return filesystems, nil
+}
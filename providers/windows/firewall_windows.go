@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import "github.com/elastic/go-sysinfo/types"
+
+const (
+	firewallProduct        = "Windows Defender Firewall"
+	firewallService        = "MpsSvc"
+	firewallPolicyKey      = `SYSTEM\CurrentControlSet\Services\SharedAccess\Parameters\FirewallPolicy`
+	firewallGroupPolicyKey = `SOFTWARE\Policies\Microsoft\WindowsFirewall`
+)
+
+// Firewall profiles with their registry keys. The private profile is named
+// StandardProfile in the local policy.
+var firewallProfiles = []struct {
+	name        string
+	localKey    string
+	groupPolicy string
+}{
+	{"domain", "DomainProfile", "DomainProfile"},
+	{"private", "StandardProfile", "PrivateProfile"},
+	{"public", "PublicProfile", "PublicProfile"},
+}
+
+// FirewallInfo reports the state of the domain, private, and public profiles
+// of the Windows Firewall from the registry. Settings applied by group policy
+// take precedence over the local policy. A profile only filters traffic while
+// the firewall service is running.
+func (h *host) FirewallInfo() (*types.FirewallInfo, error) {
+	running, err := serviceRunning(firewallService)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.FirewallInfo{Product: firewallProduct}
+	for _, p := range firewallProfiles {
+		value := func(name string, def uint64) uint64 {
+			if v, err := registryDWORD(firewallGroupPolicyKey+`\`+p.groupPolicy, name); err == nil {
+				return v
+			}
+			if v, err := registryDWORD(firewallPolicyKey+`\`+p.localKey, name); err == nil {
+				return v
+			}
+			return def
+		}
+
+		profile := types.FirewallProfile{
+			Name:            p.name,
+			Enabled:         running && value("EnableFirewall", 1) != 0,
+			DefaultInbound:  firewallAction(value("DefaultInboundAction", 1)),
+			DefaultOutbound: firewallAction(value("DefaultOutboundAction", 0)),
+		}
+		info.Enabled = info.Enabled || profile.Enabled
+		info.Profiles = append(info.Profiles, profile)
+	}
+	return info, nil
+}
+
+// firewallAction converts a DefaultInboundAction or DefaultOutboundAction
+// registry value. Unlike NET_FW_ACTION of the COM API, 1 means block.
+func firewallAction(v uint64) string {
+	if v == 0 {
+		return types.FirewallPolicyAllow
+	}
+	return types.FirewallPolicyBlock
+}
@@ -0,0 +1,95 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// displayClassKey is the device class key of display adapters. Each adapter
+// has a numbered subkey (e.g. 0000) that describes its driver.
+const displayClassKey = `SYSTEM\CurrentControlSet\Control\Class\{4d36e968-e325-11ce-bfc1-08002be10318}`
+
+var (
+	adapterKeyRegexp  = regexp.MustCompile(`^[0-9]{4}$`)
+	pciDeviceIDRegexp = regexp.MustCompile(`(?i)^pci\\ven_([0-9a-f]{4})&dev_([0-9a-f]{4})`)
+)
+
+// GPUs lists the display adapters from the registry.
+func (h *host) GPUs() ([]types.GPUInfo, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, displayClassKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to open HKLM\%v`, displayClassKey)
+	}
+	defer k.Close()
+
+	names, err := k.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to list HKLM\%v`, displayClassKey)
+	}
+
+	var gpus []types.GPUInfo
+	for _, name := range names {
+		if !adapterKeyRegexp.MatchString(name) {
+			continue
+		}
+		if gpu, err := readDisplayAdapter(k, name); err == nil {
+			gpus = append(gpus, *gpu)
+		}
+	}
+	return gpus, nil
+}
+
+func readDisplayAdapter(classKey registry.Key, name string) (*types.GPUInfo, error) {
+	k, err := registry.OpenKey(classKey, name, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+
+	gpu := &types.GPUInfo{}
+	if gpu.Model, _, err = k.GetStringValue("DriverDesc"); err != nil {
+		return nil, err
+	}
+	gpu.Vendor, _, _ = k.GetStringValue("ProviderName")
+	gpu.DriverVersion, _, _ = k.GetStringValue("DriverVersion")
+
+	if id, _, err := k.GetStringValue("MatchingDeviceId"); err == nil {
+		if m := pciDeviceIDRegexp.FindStringSubmatch(id); m != nil {
+			gpu.PCIID = strings.ToLower(m[1] + ":" + m[2])
+		}
+	}
+
+	// The memory size is a QWORD since Windows 10 and a DWORD (sometimes
+	// stored as binary) before.
+	if size, _, err := k.GetIntegerValue("HardwareInformation.qwMemorySize"); err == nil {
+		gpu.VRAM = size
+	} else if size, _, err := k.GetIntegerValue("HardwareInformation.MemorySize"); err == nil {
+		gpu.VRAM = size
+	} else if data, _, err := k.GetBinaryValue("HardwareInformation.MemorySize"); err == nil && len(data) >= 4 {
+		gpu.VRAM = uint64(binary.LittleEndian.Uint32(data))
+	}
+	return gpu, nil
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+	windows "github.com/elastic/go-windows"
+)
+
+// OpenHandles returns the list of open handles of the process. Handles are
+// enumerated from the system handle table and then duplicated into this
+// process so that their type and name can be queried.
+func (p *process) OpenHandles() ([]types.OpenHandleInfo, error) {
+	buf, err := NtQuerySystemInformation(systemExtendedHandleInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQuerySystemInformation failed")
+	}
+
+	var entry systemHandleTableEntryInfoEx
+	headerSize := 2 * unsafe.Sizeof(uintptr(0))
+	entrySize := unsafe.Sizeof(entry)
+	if uintptr(len(buf)) < headerSize {
+		return nil, errors.New("SYSTEM_HANDLE_INFORMATION_EX: short buffer")
+	}
+
+	count := *(*uintptr)(unsafe.Pointer(&buf[0]))
+	if headerSize+count*entrySize > uintptr(len(buf)) {
+		return nil, errors.Errorf("SYSTEM_HANDLE_INFORMATION_EX: short buffer for %d handles", count)
+	}
+
+	source, err := syscall.OpenProcess(processDupHandle, false, uint32(p.pid))
+	if err != nil {
+		return nil, errors.Wrap(err, "OpenProcess with PROCESS_DUP_HANDLE failed")
+	}
+	defer syscall.CloseHandle(source)
+
+	self, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	var handles []types.OpenHandleInfo
+	for i := uintptr(0); i < count; i++ {
+		entry = *(*systemHandleTableEntryInfoEx)(unsafe.Pointer(&buf[headerSize+i*entrySize]))
+		if int(entry.UniqueProcessID) != p.pid {
+			continue
+		}
+
+		handle := types.OpenHandleInfo{
+			FD:   uint64(entry.HandleValue),
+			Type: types.HandleTypeUnknown,
+		}
+
+		var dup syscall.Handle
+		err := syscall.DuplicateHandle(source, syscall.Handle(entry.HandleValue), self, &dup, 0, false, syscall.DUPLICATE_SAME_ACCESS)
+		if err == nil {
+			handle.Type, handle.Path = describeHandle(dup)
+			syscall.CloseHandle(dup)
+		}
+
+		handles = append(handles, handle)
+	}
+
+	return handles, nil
+}
+
+// describeHandle returns the type and name of the object referenced by the
+// given handle.
+func describeHandle(h syscall.Handle) (typ, name string) {
+	typ = types.HandleTypeUnknown
+	if info, err := NtQueryObject(h, objectTypeInformation); err == nil {
+		if s, err := unicodeStringAt(info); err == nil && s != "" {
+			typ = strings.ToLower(s)
+		}
+	}
+
+	if typ != types.HandleTypeFile {
+		if info, err := NtQueryObject(h, objectNameInformation); err == nil {
+			name, _ = unicodeStringAt(info)
+		}
+		return typ, name
+	}
+
+	// Querying the name of a synchronous pipe with NtQueryObject can block
+	// indefinitely so only resolve names for disk files.
+	fileType, err := syscall.GetFileType(h)
+	if err != nil {
+		return typ, ""
+	}
+
+	switch fileType {
+	case syscall.FILE_TYPE_DISK:
+		if path, err := GetFinalPathNameByHandle(h); err == nil {
+			name = strings.TrimPrefix(path, `\\?\`)
+		}
+	case syscall.FILE_TYPE_PIPE:
+		typ = types.HandleTypePipe
+	case syscall.FILE_TYPE_CHAR:
+		typ = types.HandleTypeDevice
+	}
+
+	return typ, name
+}
+
+// GetGuiResources flags.
+const (
+	grGDIObjects      = 0
+	grUSERObjects     = 1
+	grGDIObjectsPeak  = 2
+	grUSERObjectsPeak = 4
+)
+
+// WindowsHandles returns the number of open handles and the GDI and USER
+// object counts of the process.
+func (p *process) WindowsHandles() (*types.WindowsHandleInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	count, err := windows.GetProcessHandleCount(handle)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetProcessHandleCount failed")
+	}
+
+	// GetGuiResources returns 0 both on failure and for processes without
+	// GUI objects so its errors are not reported.
+	return &types.WindowsHandleInfo{
+		Handles:         count,
+		GDIObjects:      _GetGuiResources(handle, grGDIObjects),
+		GDIObjectsPeak:  _GetGuiResources(handle, grGDIObjectsPeak),
+		USERObjects:     _GetGuiResources(handle, grUSERObjects),
+		USERObjectsPeak: _GetGuiResources(handle, grUSERObjectsPeak),
+	}, nil
+}
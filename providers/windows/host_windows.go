@@ -20,6 +20,7 @@ package windows
 import (
 	"os"
 	"time"
+	"unsafe"
 
 	windows "github.com/elastic/go-windows"
 	"github.com/joeshaw/multierror"
@@ -34,20 +35,50 @@ func init() {
 	registry.Register(windowsSystem{})
 }
 
-type windowsSystem struct{}
+type windowsSystem struct {
+	fields    types.ProcessField // Fields collected by the processes. Zero selects all fields.
+	envFilter types.EnvFilter    // Applied to the environment of the processes.
+	cache     shared.CachePolicy // TTL of the StaticCache lookups of the host.
+}
+
+// WithFields returns a provider whose processes only read the selected
+// information.
+func (s windowsSystem) WithFields(fields types.ProcessField) interface{} {
+	s.fields = fields
+	return s
+}
+
+// WithEnvFilter returns a provider whose processes apply filter to their
+// environment.
+func (s windowsSystem) WithEnvFilter(filter types.EnvFilter) interface{} {
+	s.envFilter = filter
+	return s
+}
+
+// WithCacheTTL returns a provider that caches the host data for ttl.
+func (s windowsSystem) WithCacheTTL(ttl time.Duration) interface{} {
+	s.cache = shared.CacheTTL(ttl)
+	return s
+}
 
 func (s windowsSystem) Host() (types.Host, error) {
-	return newHost()
+	return newHost(s.cache)
 }
 
 type host struct {
-	info types.HostInfo
+	info  types.HostInfo
+	cache shared.CachePolicy
 }
 
 func (h *host) Info() types.HostInfo {
 	return h.info
 }
 
+// Uptime returns the time since boot.
+func (h *host) Uptime() (time.Duration, error) {
+	return bootClock.Uptime()
+}
+
 func (h *host) CPUTime() (types.CPUTimes, error) {
 	idle, kernel, user, err := windows.GetSystemTimes()
 	if err != nil {
@@ -61,13 +92,81 @@ func (h *host) CPUTime() (types.CPUTimes, error) {
 	}, nil
 }
 
+func (h *host) CPUTimePerCPU() ([]types.CPUTimes, error) {
+	buf, err := NtQuerySystemInformation(systemProcessorPerformanceInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQuerySystemInformation failed")
+	}
+
+	const size = unsafe.Sizeof(systemProcessorPerformanceInfo{})
+	times := make([]types.CPUTimes, 0, uintptr(len(buf))/size)
+	for off := uintptr(0); off+size <= uintptr(len(buf)); off += size {
+		info := (*systemProcessorPerformanceInfo)(unsafe.Pointer(&buf[off]))
+
+		// Times are in 100-nanosecond intervals. Kernel time includes
+		// idle time so it is subtracted out.
+		times = append(times, types.CPUTimes{
+			System: time.Duration(info.KernelTime-info.IdleTime) * 100,
+			User:   time.Duration(info.UserTime) * 100,
+			Idle:   time.Duration(info.IdleTime) * 100,
+		})
+	}
+	return times, nil
+}
+
+// VMStat returns paging and context switch counters from
+// SystemPerformanceInformation and the interrupt count from
+// SystemProcessorPerformanceInformation. The kernel keeps these as 32-bit
+// counters so they wrap. Dirty pages are written to the page file so they are
+// reported as swapped out.
+func (h *host) VMStat() (*types.VMStatInfo, error) {
+	buf, err := NtQuerySystemInformation(systemPerformanceInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQuerySystemInformation failed")
+	}
+	if uintptr(len(buf)) < unsafe.Sizeof(systemPerformanceInfo{}) {
+		return nil, errors.Errorf("system performance information is too short (%d bytes)", len(buf))
+	}
+	perf := (*systemPerformanceInfo)(unsafe.Pointer(&buf[0]))
+
+	pageSize := uint64(os.Getpagesize())
+	info := &types.VMStatInfo{
+		PageIn:          uint64(perf.PageReadCount) * pageSize,
+		PageOut:         uint64(perf.DirtyPagesWriteCount+perf.MappedPagesWriteCount) * pageSize,
+		SwapOut:         uint64(perf.DirtyPagesWriteCount) * pageSize,
+		PageFaults:      uint64(perf.PageFaultCount),
+		MajorPageFaults: uint64(perf.PageReadIoCount),
+		ContextSwitches: uint64(perf.ContextSwitches),
+	}
+
+	if buf, err = NtQuerySystemInformation(systemProcessorPerformanceInformation); err == nil {
+		const size = unsafe.Sizeof(systemProcessorPerformanceInfo{})
+		for off := uintptr(0); off+size <= uintptr(len(buf)); off += size {
+			cpu := (*systemProcessorPerformanceInfo)(unsafe.Pointer(&buf[off]))
+			info.Interrupts += uint64(cpu.InterruptCount)
+		}
+	}
+	return info, nil
+}
+
+// HugePages returns the large page size from GetLargePageMinimum. Windows
+// allocates large pages on demand so there is no pool to report. No pools
+// are returned when the processor does not support large pages.
+func (h *host) HugePages() (*types.HugePagesInfo, error) {
+	info := &types.HugePagesInfo{}
+	if size := _GetLargePageMinimum(); size > 0 {
+		info.Pools = []types.HugePagePoolInfo{{Size: uint64(size)}}
+	}
+	return info, nil
+}
+
 func (h *host) Memory() (*types.HostMemoryInfo, error) {
 	mem, err := windows.GlobalMemoryStatusEx()
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.HostMemoryInfo{
+	info := &types.HostMemoryInfo{
 		Total:        mem.TotalPhys,
 		Used:         mem.TotalPhys - mem.AvailPhys,
 		Free:         mem.AvailPhys,
@@ -75,14 +174,67 @@ func (h *host) Memory() (*types.HostMemoryInfo, error) {
 		VirtualTotal: mem.TotalPageFile,
 		VirtualUsed:  mem.TotalPageFile - mem.AvailPageFile,
 		VirtualFree:  mem.AvailPageFile,
+	}
+
+	var perf performanceInformation
+	perf.cb = uint32(unsafe.Sizeof(perf))
+	if err := _GetPerformanceInfo(&perf, perf.cb); err == nil {
+		pageSize := uint64(perf.pageSize)
+		info.Cached = uint64(perf.systemCache) * pageSize
+		info.Slab = uint64(perf.kernelTotal) * pageSize
+		info.Metrics = map[string]uint64{
+			"commit_total_bytes":    uint64(perf.commitTotal) * pageSize,
+			"commit_limit_bytes":    uint64(perf.commitLimit) * pageSize,
+			"commit_peak_bytes":     uint64(perf.commitPeak) * pageSize,
+			"kernel_paged_bytes":    uint64(perf.kernelPaged) * pageSize,
+			"kernel_nonpaged_bytes": uint64(perf.kernelNonpaged) * pageSize,
+		}
+	}
+
+	return info, nil
+}
+
+// HandleCounts returns the number of handles, processes, and threads on the
+// host.
+func (h *host) HandleCounts() (*types.HandleCountsInfo, error) {
+	var perf performanceInformation
+	perf.cb = uint32(unsafe.Sizeof(perf))
+	if err := _GetPerformanceInfo(&perf, perf.cb); err != nil {
+		return nil, errors.Wrap(err, "GetPerformanceInfo failed")
+	}
+
+	return &types.HandleCountsInfo{
+		Handles:   uint64(perf.handleCount),
+		Processes: uint64(perf.processCount),
+		Threads:   uint64(perf.threadCount),
 	}, nil
 }
 
-func newHost() (*host, error) {
-	h := &host{}
+// performanceInformation is the PERFORMANCE_INFORMATION structure. Values
+// other than the counts are measured in pages.
+type performanceInformation struct {
+	cb                uint32
+	commitTotal       uintptr
+	commitLimit       uintptr
+	commitPeak        uintptr
+	physicalTotal     uintptr
+	physicalAvailable uintptr
+	systemCache       uintptr
+	kernelTotal       uintptr
+	kernelPaged       uintptr
+	kernelNonpaged    uintptr
+	pageSize          uintptr
+	handleCount       uint32
+	processCount      uint32
+	threadCount       uint32
+}
+
+func newHost(cache shared.CachePolicy) (*host, error) {
+	h := &host{cache: cache}
 	r := &reader{}
 	r.architecture(h)
 	r.bootTime(h)
+	r.containerized(h)
 	r.hostname(h)
 	r.network(h)
 	r.kernelVersion(h)
@@ -98,7 +250,7 @@ type reader struct {
 
 func (r *reader) addErr(err error) bool {
 	if err != nil {
-		if errors.Cause(err) != types.ErrNotImplemented {
+		if !types.IsNotImplemented(err) && !types.IsNotSupported(err) {
 			r.errs = append(r.errs, err)
 		}
 		return true
@@ -129,6 +281,23 @@ func (r *reader) bootTime(h *host) {
 	h.info.BootTime = v
 }
 
+func (r *reader) containerized(h *host) {
+	v, err := IsContainerized()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Containerized = &v
+
+	if !v {
+		return
+	}
+	info, err := ContainerInfo()
+	if r.addErr(err) {
+		return
+	}
+	h.info.Container = info
+}
+
 func (r *reader) hostname(h *host) {
 	v, err := os.Hostname()
 	if r.addErr(err) {
@@ -155,11 +324,13 @@ func (r *reader) kernelVersion(h *host) {
 }
 
 func (r *reader) os(h *host) {
-	v, err := OperatingSystem()
+	v, err := h.cache.Get("os", func() (interface{}, error) {
+		return OperatingSystem()
+	})
 	if r.addErr(err) {
 		return
 	}
-	h.info.OS = v
+	h.info.OS = shared.CopyOSInfo(v.(*types.OSInfo))
 }
 
 func (r *reader) time(h *host) {
@@ -167,9 +338,10 @@ func (r *reader) time(h *host) {
 }
 
 func (r *reader) uniqueID(h *host) {
-	v, err := MachineID()
+	v, err := machineID(h.cache)
 	if r.addErr(err) {
 		return
 	}
 	h.info.UniqueID = v
+	h.info.UniqueIDSource = types.MachineIDSourceMachineGUID
 }
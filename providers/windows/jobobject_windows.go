@@ -0,0 +1,203 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// JOBOBJECTINFOCLASS values.
+const (
+	jobObjectBasicAccountingInformation = 1
+	jobObjectExtendedLimitInformation   = 9
+	jobObjectCPURateControlInformation  = 15
+)
+
+// JOBOBJECT_BASIC_LIMIT_INFORMATION LimitFlags.
+const (
+	jobObjectLimitProcessTime   = 0x2
+	jobObjectLimitJobTime       = 0x4
+	jobObjectLimitActiveProcess = 0x8
+	jobObjectLimitAffinity      = 0x10
+	jobObjectLimitProcessMemory = 0x100
+	jobObjectLimitJobMemory     = 0x200
+)
+
+// JOBOBJECT_CPU_RATE_CONTROL_INFORMATION ControlFlags.
+const (
+	jobObjectCPURateControlEnable      = 0x1
+	jobObjectCPURateControlWeightBased = 0x2
+	jobObjectCPURateControlHardCap     = 0x4
+)
+
+// jobObjectBasicAccounting is the
+// JOBOBJECT_BASIC_ACCOUNTING_INFORMATION structure. Times are in 100ns units.
+type jobObjectBasicAccounting struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// jobObjectBasicLimit is the JOBOBJECT_BASIC_LIMIT_INFORMATION structure.
+type jobObjectBasicLimit struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// jobObjectBasicLimitPad is the padding that the C compiler adds after
+// JOBOBJECT_BASIC_LIMIT_INFORMATION to 8 byte align the following
+// IO_COUNTERS. Go only aligns 64-bit values to 4 bytes on 386.
+const jobObjectBasicLimitPad = (8 - unsafe.Sizeof(jobObjectBasicLimit{})%8) % 8
+
+// jobObjectExtendedLimit is the JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+// structure.
+type jobObjectExtendedLimit struct {
+	BasicLimitInformation jobObjectBasicLimit
+	_                     [jobObjectBasicLimitPad]byte
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// jobObjectCPURateControl is the JOBOBJECT_CPU_RATE_CONTROL_INFORMATION
+// structure. Rate holds the CpuRate or Weight member of the union.
+type jobObjectCPURateControl struct {
+	ControlFlags uint32
+	Rate         uint32
+}
+
+// JobObject reports whether the process is assigned to a job object. The
+// limits and usage of the job are reported for the current process only.
+func (p *process) JobObject() (*types.JobObjectInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	var inJob int32
+	if err = _IsProcessInJob(handle, 0, &inJob); err != nil {
+		return nil, errors.Wrap(err, "IsProcessInJob failed")
+	}
+
+	info := &types.JobObjectInfo{InJob: inJob != 0}
+	if !info.InJob || p.pid != selfPID {
+		return info, nil
+	}
+
+	// A nil job handle refers to the job of the calling process.
+	var limits jobObjectExtendedLimit
+	if err = queryJobObject(jobObjectExtendedLimitInformation, unsafe.Pointer(&limits), unsafe.Sizeof(limits)); err != nil {
+		return info, err
+	}
+	var accounting jobObjectBasicAccounting
+	if err = queryJobObject(jobObjectBasicAccountingInformation, unsafe.Pointer(&accounting), unsafe.Sizeof(accounting)); err != nil {
+		return info, err
+	}
+	// CPU rate control requires Windows 8 or Server 2012.
+	var cpu jobObjectCPURateControl
+	queryJobObject(jobObjectCPURateControlInformation, unsafe.Pointer(&cpu), unsafe.Sizeof(cpu))
+
+	info.Limits = jobObjectLimits(&limits, &cpu)
+	info.Usage = jobObjectUsage(&limits, &accounting)
+	return info, nil
+}
+
+func queryJobObject(infoClass uint32, info unsafe.Pointer, size uintptr) error {
+	if err := _QueryInformationJobObject(0, infoClass, uintptr(info), uint32(size), nil); err != nil {
+		return errors.Wrapf(err, "QueryInformationJobObject failed (class %d)", infoClass)
+	}
+	return nil
+}
+
+// jobObjectLimits converts the limits that are enabled by the flags.
+func jobObjectLimits(limits *jobObjectExtendedLimit, cpu *jobObjectCPURateControl) *types.JobObjectLimits {
+	out := &types.JobObjectLimits{}
+
+	flags := limits.BasicLimitInformation.LimitFlags
+	if flags&jobObjectLimitProcessTime != 0 {
+		d := time.Duration(limits.BasicLimitInformation.PerProcessUserTimeLimit) * 100
+		out.ProcessCPUTime = &d
+	}
+	if flags&jobObjectLimitJobTime != 0 {
+		d := time.Duration(limits.BasicLimitInformation.PerJobUserTimeLimit) * 100
+		out.JobCPUTime = &d
+	}
+	if flags&jobObjectLimitActiveProcess != 0 {
+		n := limits.BasicLimitInformation.ActiveProcessLimit
+		out.ActiveProcess = &n
+	}
+	if flags&jobObjectLimitAffinity != 0 {
+		mask := uint64(limits.BasicLimitInformation.Affinity)
+		out.ProcessorMask = &mask
+	}
+	if flags&jobObjectLimitProcessMemory != 0 {
+		n := uint64(limits.ProcessMemoryLimit)
+		out.ProcessMemory = &n
+	}
+	if flags&jobObjectLimitJobMemory != 0 {
+		n := uint64(limits.JobMemoryLimit)
+		out.JobMemory = &n
+	}
+
+	if cpu.ControlFlags&jobObjectCPURateControlEnable != 0 {
+		switch {
+		case cpu.ControlFlags&jobObjectCPURateControlWeightBased != 0:
+			weight := cpu.Rate
+			out.CPUWeight = &weight
+		case cpu.ControlFlags&jobObjectCPURateControlHardCap != 0:
+			// CpuRate is the percentage times 100 of all processors.
+			rate := float64(cpu.Rate) / 100
+			out.CPURate = &rate
+		}
+	}
+
+	return out
+}
+
+func jobObjectUsage(limits *jobObjectExtendedLimit, accounting *jobObjectBasicAccounting) *types.JobObjectUsage {
+	return &types.JobObjectUsage{
+		UserTime:          time.Duration(accounting.TotalUserTime) * 100,
+		SystemTime:        time.Duration(accounting.TotalKernelTime) * 100,
+		PageFaults:        uint64(accounting.TotalPageFaultCount),
+		Processes:         uint64(accounting.TotalProcesses),
+		ActiveProcesses:   uint64(accounting.ActiveProcesses),
+		PeakProcessMemory: uint64(limits.PeakProcessMemoryUsed),
+		PeakJobMemory:     uint64(limits.PeakJobMemoryUsed),
+	}
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobObjectExtendedLimitSize(t *testing.T) {
+	// Size of JOBOBJECT_EXTENDED_LIMIT_INFORMATION as computed by the C
+	// compiler.
+	expected := uintptr(144)
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		expected = 112
+	}
+	assert.Equal(t, expected, unsafe.Sizeof(jobObjectExtendedLimit{}))
+}
+
+func TestSiloObjectBasicInformationSize(t *testing.T) {
+	assert.EqualValues(t, 16, unsafe.Sizeof(siloObjectBasicInformation{}))
+}
+
+func TestJobObjectLimits(t *testing.T) {
+	limits := &jobObjectExtendedLimit{
+		BasicLimitInformation: jobObjectBasicLimit{
+			LimitFlags:         jobObjectLimitJobMemory | jobObjectLimitActiveProcess,
+			ActiveProcessLimit: 16,
+			Affinity:           0xf,
+		},
+		ProcessMemoryLimit: 1 << 20,
+		JobMemoryLimit:     512 << 20,
+	}
+	cpu := &jobObjectCPURateControl{
+		ControlFlags: jobObjectCPURateControlEnable | jobObjectCPURateControlHardCap,
+		Rate:         2550,
+	}
+
+	out := jobObjectLimits(limits, cpu)
+	if assert.NotNil(t, out.JobMemory) {
+		assert.EqualValues(t, 512<<20, *out.JobMemory)
+	}
+	if assert.NotNil(t, out.ActiveProcess) {
+		assert.EqualValues(t, 16, *out.ActiveProcess)
+	}
+	if assert.NotNil(t, out.CPURate) {
+		assert.Equal(t, 25.5, *out.CPURate)
+	}
+	// Values without their limit flag are ignored.
+	assert.Nil(t, out.ProcessMemory)
+	assert.Nil(t, out.ProcessorMask)
+	assert.Nil(t, out.CPUWeight)
+	assert.Nil(t, out.JobCPUTime)
+
+	usage := jobObjectUsage(limits, &jobObjectBasicAccounting{
+		TotalUserTime:   15000000,
+		ActiveProcesses: 3,
+	})
+	assert.Equal(t, 1500*time.Millisecond, usage.UserTime)
+	assert.EqualValues(t, 3, usage.ActiveProcesses)
+}
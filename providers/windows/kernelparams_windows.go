@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+)
+
+// kernelParamsRoot is the key that parameter names are relative to.
+const kernelParamsRoot = `SYSTEM\CurrentControlSet`
+
+// kernelParams are the registry values that are returned by KernelParams.
+// They control kernel, network stack, and credential protection features
+// that compliance checks commonly look at.
+var kernelParams = []string{
+	`Control\Session Manager\Memory Management\FeatureSettingsOverride`,
+	`Control\Session Manager\Memory Management\FeatureSettingsOverrideMask`,
+	`Control\Session Manager\Memory Management\ClearPageFileAtShutdown`,
+	`Control\Session Manager\Memory Management\MoveImages`,
+	`Control\Session Manager\kernel\DisableExceptionChainValidation`,
+	`Control\Session Manager\SafeDllSearchMode`,
+	`Control\Lsa\RunAsPPL`,
+	`Control\Lsa\LmCompatibilityLevel`,
+	`Control\Lsa\RestrictAnonymous`,
+	`Control\SecurityProviders\WDigest\UseLogonCredential`,
+	`Control\Terminal Server\fDenyTSConnections`,
+	`Services\Tcpip\Parameters\IPEnableRouter`,
+	`Services\Tcpip\Parameters\DisableIPSourceRouting`,
+	`Services\Tcpip\Parameters\EnableICMPRedirect`,
+	`Services\LanmanServer\Parameters\SMB1`,
+}
+
+// KernelParam reads a value from HKLM\SYSTEM\CurrentControlSet. The name is
+// the path of the value relative to that key.
+func (h *host) KernelParam(name string) (string, error) {
+	return readKernelParam(name)
+}
+
+// KernelParams returns the selected registry values that are set.
+func (h *host) KernelParams() (map[string]string, error) {
+	params := map[string]string{}
+	for _, name := range kernelParams {
+		value, err := readKernelParam(name)
+		if err != nil {
+			if errors.Cause(err) == registry.ErrNotExist {
+				continue
+			}
+			return nil, err
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+func readKernelParam(name string) (string, error) {
+	idx := strings.LastIndex(name, `\`)
+	if idx < 0 {
+		return "", errors.Errorf("invalid kernel parameter %v", name)
+	}
+	path := kernelParamsRoot + `\` + name[:idx]
+	valueName := name[idx+1:]
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return "", errors.Wrapf(err, `failed to open HKLM\%v`, path)
+	}
+	defer k.Close()
+
+	_, valType, err := k.GetValue(valueName, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, valueName)
+	}
+
+	switch valType {
+	case registry.DWORD, registry.QWORD:
+		v, _, err := k.GetIntegerValue(valueName)
+		if err != nil {
+			return "", errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, valueName)
+		}
+		return strconv.FormatUint(v, 10), nil
+	case registry.SZ, registry.EXPAND_SZ:
+		v, _, err := k.GetStringValue(valueName)
+		if err != nil {
+			return "", errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, valueName)
+		}
+		return v, nil
+	case registry.MULTI_SZ:
+		v, _, err := k.GetStringsValue(valueName)
+		if err != nil {
+			return "", errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, valueName)
+		}
+		return strings.Join(v, "\n"), nil
+	default:
+		v, _, err := k.GetBinaryValue(valueName)
+		if err != nil {
+			return "", errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, valueName)
+		}
+		return hex.EncodeToString(v), nil
+	}
+}
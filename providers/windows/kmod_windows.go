@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// LoadedModules returns the device drivers loaded by the kernel as reported by
+// EnumDeviceDrivers. Reading the load addresses requires administrator
+// privileges.
+func (h *host) LoadedModules() ([]types.KernelModuleInfo, error) {
+	var bases []uintptr
+	var needed uint32
+	for size := uint32(256); ; size = needed / uint32(unsafe.Sizeof(uintptr(0))) {
+		bases = make([]uintptr, size)
+		if err := _EnumDeviceDrivers(&bases[0], size*uint32(unsafe.Sizeof(bases[0])), &needed); err != nil {
+			return nil, errors.Wrap(err, "EnumDeviceDrivers failed")
+		}
+		if needed <= size*uint32(unsafe.Sizeof(bases[0])) {
+			bases = bases[:needed/uint32(unsafe.Sizeof(bases[0]))]
+			break
+		}
+	}
+
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	modules := make([]types.KernelModuleInfo, 0, len(bases))
+	for _, base := range bases {
+		module := types.KernelModuleInfo{Address: uint64(base)}
+		if n, err := _GetDeviceDriverBaseName(base, &buf[0], uint32(len(buf))); err == nil {
+			module.Name = syscall.UTF16ToString(buf[:n])
+		}
+		if n, err := _GetDeviceDriverFileName(base, &buf[0], uint32(len(buf))); err == nil {
+			module.Path = driverPath(syscall.UTF16ToString(buf[:n]))
+		}
+		if module.Name == "" && module.Path == "" {
+			continue
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// driverPath converts the NT paths returned by GetDeviceDriverFileName (e.g.
+// \SystemRoot\system32\ntoskrnl.exe or \??\C:\drivers\x.sys) to Win32 paths.
+func driverPath(path string) string {
+	switch {
+	case strings.HasPrefix(strings.ToLower(path), `\systemroot\`):
+		return os.Getenv("SystemRoot") + path[len(`\SystemRoot`):]
+	case strings.HasPrefix(path, `\??\`):
+		return path[len(`\??\`):]
+	default:
+		return path
+	}
+}
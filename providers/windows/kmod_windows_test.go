@@ -0,0 +1,31 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriverPath(t *testing.T) {
+	assert.Equal(t, os.Getenv("SystemRoot")+`\system32\ntoskrnl.exe`, driverPath(`\SystemRoot\system32\ntoskrnl.exe`))
+	assert.Equal(t, `C:\drivers\x.sys`, driverPath(`\??\C:\drivers\x.sys`))
+	assert.Equal(t, `C:\Windows\System32\drivers\acpi.sys`, driverPath(`C:\Windows\System32\drivers\acpi.sys`))
+}
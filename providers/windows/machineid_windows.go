@@ -18,12 +18,29 @@
 package windows
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
 )
 
+// MachineID returns the MachineGuid. The result is cached in
+// shared.StaticCache.
 func MachineID() (string, error) {
-	return getMachineGUID()
+	return machineID(shared.CachePolicy{})
+}
+
+func machineID(cache shared.CachePolicy) (string, error) {
+	v, err := cache.Get("machine-guid", func() (interface{}, error) {
+		return getMachineGUID()
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
 }
 
 func getMachineGUID() (string, error) {
@@ -44,3 +61,17 @@ func getMachineGUID() (string, error) {
 
 	return guid, nil
 }
+
+// MachineIDs returns the MachineGuid, which is generated during setup, and
+// the SMBIOS system UUID. Images that are not generalized with sysprep keep
+// the MachineGuid when they are cloned.
+func (h *host) MachineIDs() ([]types.MachineIDInfo, error) {
+	var ids []types.MachineIDInfo
+	if guid, err := machineID(h.cache); err == nil {
+		ids = append(ids, types.MachineIDInfo{ID: guid, Source: types.MachineIDSourceMachineGUID})
+	}
+	if hw, err := h.Hardware(); err == nil && shared.ValidSystemUUID(hw.UUID) {
+		ids = append(ids, types.MachineIDInfo{ID: strings.ToLower(hw.UUID), Source: types.MachineIDSourceDMI})
+	}
+	return ids, nil
+}
@@ -0,0 +1,134 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// MEMORY_BASIC_INFORMATION State and Type values.
+const (
+	memFree    = 0x10000
+	memPrivate = 0x20000
+	memMapped  = 0x40000
+	memImage   = 0x1000000
+)
+
+// Memory protection constants.
+const (
+	pageReadOnly         = 0x02
+	pageReadWrite        = 0x04
+	pageWriteCopy        = 0x08
+	pageExecute          = 0x10
+	pageExecuteRead      = 0x20
+	pageExecuteReadWrite = 0x40
+	pageExecuteWriteCopy = 0x80
+	pageGuard            = 0x100
+	pageNoCache          = 0x200
+	pageWriteCombine     = 0x400
+)
+
+// MemoryMaps returns the reserved and committed regions of the process
+// address space as reported by VirtualQueryEx. Windows does not report
+// per-region resident sizes without a working set query so Resident is zero.
+func (p *process) MemoryMaps() ([]types.MemoryMapInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	var maps []types.MemoryMapInfo
+	var mbi memoryBasicInformation
+	for addr := uintptr(0); ; {
+		if _, err = _VirtualQueryEx(handle, addr, &mbi, unsafe.Sizeof(mbi)); err != nil {
+			// ERROR_INVALID_PARAMETER marks the end of the address space.
+			break
+		}
+		next := mbi.BaseAddress + mbi.RegionSize
+		if next <= addr {
+			break
+		}
+		addr = next
+
+		if mbi.State == memFree {
+			continue
+		}
+
+		info := types.MemoryMapInfo{
+			Start:       uint64(mbi.BaseAddress),
+			End:         uint64(next),
+			Permissions: protectionString(mbi.Protect, mbi.Type),
+			Anonymous:   mbi.Type == memPrivate,
+		}
+		if mbi.Type == memImage || mbi.Type == memMapped {
+			info.Path = mappedFileName(handle, mbi.BaseAddress)
+		}
+		maps = append(maps, info)
+	}
+
+	if len(maps) == 0 && err != nil {
+		return nil, err
+	}
+	return maps, nil
+}
+
+// mappedFileName returns the drive path of the file mapped at address.
+func mappedFileName(handle syscall.Handle, address uintptr) string {
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	n, err := _GetMappedFileName(handle, address, &buf[0], uint32(len(buf)))
+	if err != nil {
+		return ""
+	}
+
+	path := syscall.UTF16ToString(buf[:n])
+	if drivePath, err := devMapper.DevicePathToDrivePath(path); err == nil {
+		return drivePath
+	}
+	return path
+}
+
+// protectionString formats a page protection value in the rwxp form used by
+// Linux. Copy-on-write and private pages are marked p and the others s.
+func protectionString(protect, memType uint32) string {
+	perms := []byte("---s")
+	// The modifiers do not affect access.
+	switch protect &^ (pageGuard | pageNoCache | pageWriteCombine) {
+	case pageReadOnly:
+		perms[0] = 'r'
+	case pageReadWrite:
+		perms[0], perms[1] = 'r', 'w'
+	case pageWriteCopy:
+		perms[0], perms[1], perms[3] = 'r', 'w', 'p'
+	case pageExecute:
+		perms[2] = 'x'
+	case pageExecuteRead:
+		perms[0], perms[2] = 'r', 'x'
+	case pageExecuteReadWrite:
+		perms[0], perms[1], perms[2] = 'r', 'w', 'x'
+	case pageExecuteWriteCopy:
+		perms[0], perms[1], perms[2], perms[3] = 'r', 'w', 'x', 'p'
+	}
+	if memType == memPrivate {
+		perms[3] = 'p'
+	}
+	return string(perms)
+}
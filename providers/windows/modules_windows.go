@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// listModulesAll (LIST_MODULES_ALL) includes both 32-bit and 64-bit modules.
+const listModulesAll = 0x03
+
+// Modules returns the executable and DLLs loaded by the process. It requires
+// PROCESS_QUERY_INFORMATION and PROCESS_VM_READ access to the process.
+func (p *process) Modules() ([]types.ModuleInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	const handleSize = uint32(unsafe.Sizeof(syscall.Handle(0)))
+	modules := make([]syscall.Handle, 256)
+	for {
+		var needed uint32
+		if err = _EnumProcessModulesEx(handle, &modules[0], uint32(len(modules))*handleSize, &needed, listModulesAll); err != nil {
+			return nil, errors.Wrap(err, "EnumProcessModulesEx failed")
+		}
+		n := int(needed / handleSize)
+		if n <= len(modules) {
+			modules = modules[:n]
+			break
+		}
+		// Modules can be loaded between calls so add some slack.
+		modules = make([]syscall.Handle, n+32)
+	}
+
+	infos := make([]types.ModuleInfo, 0, len(modules))
+	for _, module := range modules {
+		var mi moduleInfo
+		if err = _GetModuleInformation(handle, module, &mi, uint32(unsafe.Sizeof(mi))); err != nil {
+			// The module was unloaded.
+			continue
+		}
+
+		buf := make([]uint16, syscall.MAX_LONG_PATH)
+		n, err := _GetModuleFileNameEx(handle, module, &buf[0], uint32(len(buf)))
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, types.ModuleInfo{
+			Path:        syscall.UTF16ToString(buf[:n]),
+			BaseAddress: uint64(mi.BaseOfDll),
+			Size:        uint64(mi.SizeOfImage),
+		})
+	}
+	return infos, nil
+}
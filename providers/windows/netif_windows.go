@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// mibIfRow2 is Go's counterpart of the MIB_IF_ROW2 struct.
+type mibIfRow2 struct {
+	InterfaceLuid               uint64
+	InterfaceIndex              uint32
+	InterfaceGUID               [16]byte
+	Alias                       [257]uint16
+	Description                 [257]uint16
+	PhysicalAddressLength       uint32
+	PhysicalAddress             [32]byte
+	PermanentPhysicalAddress    [32]byte
+	Mtu                         uint32
+	Type                        uint32
+	TunnelType                  uint32
+	MediaType                   uint32
+	PhysicalMediumType          uint32
+	AccessType                  uint32
+	DirectionType               uint32
+	InterfaceAndOperStatusFlags uint8
+	OperStatus                  uint32
+	AdminStatus                 uint32
+	MediaConnectState           uint32
+	NetworkGUID                 [16]byte
+	ConnectionType              uint32
+	_                           uint32 // Explicit padding so 386 matches the C layout.
+	TransmitLinkSpeed           uint64
+	ReceiveLinkSpeed            uint64
+	InOctets                    uint64
+	InUcastPkts                 uint64
+	InNUcastPkts                uint64
+	InDiscards                  uint64
+	InErrors                    uint64
+	InUnknownProtos             uint64
+	InUcastOctets               uint64
+	InMulticastOctets           uint64
+	InBroadcastOctets           uint64
+	OutOctets                   uint64
+	OutUcastPkts                uint64
+	OutNUcastPkts               uint64
+	OutDiscards                 uint64
+	OutErrors                   uint64
+	OutUcastOctets              uint64
+	OutMulticastOctets          uint64
+	OutBroadcastOctets          uint64
+	OutQLen                     uint64
+}
+
+// NetworkInterfaces returns the network interfaces of the host with the
+// counters from GetIfEntry2.
+func (h *host) NetworkInterfaces() ([]types.NetworkInterfaceInfo, error) {
+	ifcs, err := shared.NetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ifc := range ifcs {
+		row := mibIfRow2{InterfaceIndex: uint32(ifc.Index)}
+		if err := _GetIfEntry2(&row); err != nil {
+			continue
+		}
+
+		ifcs[i].Counters = &types.NetworkInterfaceCounters{
+			RxBytes:   row.InOctets,
+			RxPackets: row.InUcastPkts + row.InNUcastPkts,
+			RxErrors:  row.InErrors,
+			RxDropped: row.InDiscards,
+			TxBytes:   row.OutOctets,
+			TxPackets: row.OutUcastPkts + row.OutNUcastPkts,
+			TxErrors:  row.OutErrors,
+			TxDropped: row.OutDiscards,
+		}
+	}
+	return ifcs, nil
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func (s windowsSystem) Network() (types.Network, error) {
+	return &network{}, nil
+}
+
+type network struct{}
+
+// Connections returns the sockets of the given kind on the host. Unix domain
+// sockets are not reported.
+func (n *network) Connections(kind string) ([]types.NetworkConnection, error) {
+	match, err := shared.ConnectionKindFilter(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := connections()
+	if err != nil {
+		return nil, err
+	}
+
+	var conns []types.NetworkConnection
+	for _, c := range all {
+		if match(c.NetworkConnection) {
+			c.NetworkConnection.PID = c.pid
+			conns = append(conns, c.NetworkConnection)
+		}
+	}
+	return conns, nil
+}
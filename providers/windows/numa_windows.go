@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// groupAffinity is the GROUP_AFFINITY structure.
+type groupAffinity struct {
+	Mask     uintptr
+	Group    uint16
+	Reserved [3]uint16
+}
+
+// cpus returns the logical CPU numbers in the affinity mask. Processor groups
+// are numbered as if each group holds 64 processors.
+func (a groupAffinity) cpus() []int {
+	var cpus []int
+	for mask := uint64(a.Mask); mask != 0; mask &= mask - 1 {
+		cpus = append(cpus, int(a.Group)*64+bits.TrailingZeros64(mask))
+	}
+	return cpus
+}
+
+// NUMA returns the NUMA nodes of the host. Windows only reports the available
+// memory of each node.
+func (h *host) NUMA() (*types.NUMAInfo, error) {
+	var highest uint32
+	if err := _GetNumaHighestNodeNumber(&highest); err != nil {
+		return nil, errors.Wrap(err, "GetNumaHighestNodeNumber failed")
+	}
+
+	info := &types.NUMAInfo{}
+	for n := uint32(0); n <= highest; n++ {
+		var affinity groupAffinity
+		if err := _GetNumaNodeProcessorMaskEx(uint16(n), &affinity); err != nil {
+			return nil, errors.Wrapf(err, "GetNumaNodeProcessorMaskEx failed for node %d", n)
+		}
+		if affinity.Mask == 0 {
+			// Node numbers can have gaps.
+			continue
+		}
+
+		node := types.NUMANodeInfo{ID: int(n), CPUs: affinity.cpus()}
+		if err := _GetNumaAvailableMemoryNodeEx(uint16(n), &node.MemoryFree); err != nil {
+			return nil, errors.Wrapf(err, "GetNumaAvailableMemoryNodeEx failed for node %d", n)
+		}
+		info.Nodes = append(info.Nodes, node)
+	}
+	return info, nil
+}
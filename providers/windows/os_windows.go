@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sys/windows/registry"
@@ -68,7 +69,7 @@ func OperatingSystem() (*types.OSInfo, error) {
 			case 0:
 				osInfo.Major, _ = strconv.Atoi(p)
 			case 1:
-				osInfo.Major, _ = strconv.Atoi(p)
+				osInfo.Minor, _ = strconv.Atoi(p)
 			}
 		}
 	}
@@ -82,10 +83,29 @@ func OperatingSystem() (*types.OSInfo, error) {
 	// Update Build Revision (optional)
 	name = "UBR"
 	updateBuildRevision, _, err := k.GetIntegerValue(name)
-	if err != nil && err != registry.ErrNotExist {
-		return nil, errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, name)
-	} else {
+	switch {
+	case err == nil:
+		osInfo.Revision = int(updateBuildRevision)
 		osInfo.Build = fmt.Sprintf("%v.%d", osInfo.Build, updateBuildRevision)
+	case err != registry.ErrNotExist:
+		return nil, errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, name)
+	}
+
+	// The remaining values are optional and their availability depends on
+	// the Windows version.
+
+	// DisplayVersion (e.g. 21H2) replaced ReleaseId (e.g. 2009) in 20H2.
+	osInfo.DisplayVersion, _, _ = k.GetStringValue("DisplayVersion")
+	if osInfo.DisplayVersion == "" {
+		osInfo.DisplayVersion, _, _ = k.GetStringValue("ReleaseId")
+	}
+	osInfo.Edition, _, _ = k.GetStringValue("EditionID")
+	osInfo.InstallationType, _, _ = k.GetStringValue("InstallationType")
+
+	// InstallDate is a DWORD containing seconds since the Unix epoch.
+	if installDate, _, err := k.GetIntegerValue("InstallDate"); err == nil && installDate > 0 {
+		t := time.Unix(int64(installDate), 0).UTC()
+		osInfo.InstallDate = &t
 	}
 
 	return osInfo, nil
@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const uninstallKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`
+
+// Packages lists the programs registered in the Uninstall key of the 64-bit
+// and 32-bit registry views. These are the entries shown by Programs and
+// Features. Windows Installer (MSI) products also register an entry there and
+// are reported with type msi. Updates of other products (entries with a
+// ParentKeyName) are omitted.
+func (h *host) Packages() ([]types.PackageInfo, error) {
+	var pkgs []types.PackageInfo
+
+	// 32-bit Windows has a single view so entries are listed once.
+	seen := map[string]struct{}{}
+	for _, view := range []uint32{registry.WOW64_64KEY, registry.WOW64_32KEY} {
+		k, err := registry.OpenKey(registry.LOCAL_MACHINE, uninstallKey, registry.ENUMERATE_SUB_KEYS|view)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to open HKLM\%v`, uninstallKey)
+		}
+
+		names, err := k.ReadSubKeyNames(-1)
+		if err != nil {
+			k.Close()
+			return nil, errors.Wrapf(err, `failed to list HKLM\%v`, uninstallKey)
+		}
+		for _, name := range names {
+			if _, found := seen[name]; found {
+				continue
+			}
+			seen[name] = struct{}{}
+			if pkg, err := readUninstallEntry(k, name, view); err == nil && pkg != nil {
+				pkgs = append(pkgs, *pkg)
+			}
+		}
+		k.Close()
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+	return pkgs, nil
+}
+
+// readUninstallEntry returns nil for entries without a display name.
+func readUninstallEntry(parent registry.Key, name string, view uint32) (*types.PackageInfo, error) {
+	k, err := registry.OpenKey(parent, name, registry.QUERY_VALUE|view)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+
+	displayName, _, err := k.GetStringValue("DisplayName")
+	if err != nil || displayName == "" {
+		return nil, err
+	}
+	if parentKey, _, _ := k.GetStringValue("ParentKeyName"); parentKey != "" {
+		return nil, nil
+	}
+
+	pkg := &types.PackageInfo{Name: displayName, Type: types.PackageTypeWindows}
+	pkg.Version, _, _ = k.GetStringValue("DisplayVersion")
+	pkg.Vendor, _, _ = k.GetStringValue("Publisher")
+	pkg.Path, _, _ = k.GetStringValue("InstallLocation")
+	pkg.Summary, _, _ = k.GetStringValue("Comments")
+	if date, _, err := k.GetStringValue("InstallDate"); err == nil {
+		pkg.InstallTime = parseInstallDate(date)
+	}
+	if kB, _, err := k.GetIntegerValue("EstimatedSize"); err == nil {
+		pkg.Size = kB * 1024
+	}
+	if msi, _, err := k.GetIntegerValue("WindowsInstaller"); err == nil && msi == 1 {
+		pkg.Type = types.PackageTypeMSI
+	}
+	if view == registry.WOW64_32KEY {
+		pkg.Arch = "x86"
+	}
+	return pkg, nil
+}
+
+// parseInstallDate parses the YYYYMMDD format of the InstallDate value.
+func parseInstallDate(date string) time.Time {
+	t, err := time.Parse("20060102", date)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,37 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInstallDate(t *testing.T) {
+	assert.Equal(t, time.Date(2019, 3, 14, 0, 0, 0, 0, time.UTC), parseInstallDate("20190314"))
+	assert.True(t, parseInstallDate("14/03/2019").IsZero())
+}
+
+func TestHotfixID(t *testing.T) {
+	assert.Equal(t, "KB4560959", hotfixID("Package_for_KB4560959~31bf3856ad364e35~amd64~~10.0.1.2"))
+	assert.Equal(t, "KB4562830", hotfixID("Package_1_for_KB4562830~31bf3856ad364e35~amd64~~10.0.1.2"))
+	assert.Equal(t, "", hotfixID("Package_for_RollupFix~31bf3856ad364e35~amd64~~19041.388.1.8"))
+	assert.Equal(t, "", hotfixID("Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.19041.1"))
+}
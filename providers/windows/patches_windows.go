@@ -0,0 +1,114 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	cbsPackagesKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\Packages`
+
+	// CurrentState of a package that is installed.
+	cbsStateInstalled = 0x70
+)
+
+var hotfixIDRegexp = regexp.MustCompile(`KB[0-9]+`)
+
+// OSPatches lists the installed hotfixes from the Component Based Servicing
+// packages in the registry. This is the store that backs
+// Win32_QuickFixEngineering. A hotfix consists of multiple packages so the
+// earliest install time is reported.
+func (h *host) OSPatches() ([]types.OSPatchInfo, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, cbsPackagesKey, registry.ENUMERATE_SUB_KEYS|registry.WOW64_64KEY)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to open HKLM\%v`, cbsPackagesKey)
+	}
+	defer k.Close()
+
+	names, err := k.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to list HKLM\%v`, cbsPackagesKey)
+	}
+
+	hotfixes := map[string]*types.OSPatchInfo{}
+	for _, name := range names {
+		id := hotfixID(name)
+		if id == "" {
+			continue
+		}
+
+		installed, installTime := readCBSPackage(k, name)
+		if !installed {
+			continue
+		}
+
+		if hf, found := hotfixes[id]; found {
+			if !installTime.IsZero() && (hf.InstallTime.IsZero() || installTime.Before(hf.InstallTime)) {
+				hf.InstallTime = installTime
+			}
+			continue
+		}
+		hotfixes[id] = &types.OSPatchInfo{ID: id, InstallTime: installTime}
+	}
+
+	patches := make([]types.OSPatchInfo, 0, len(hotfixes))
+	for _, hf := range hotfixes {
+		patches = append(patches, *hf)
+	}
+	sort.Slice(patches, func(i, j int) bool { return patches[i].ID < patches[j].ID })
+	return patches, nil
+}
+
+// hotfixID returns the KB number from a package name such as
+// Package_for_KB4560959~31bf3856ad364e35~amd64~~10.0.1.2.
+func hotfixID(name string) string {
+	if !strings.HasPrefix(name, "Package_") {
+		return ""
+	}
+	return hotfixIDRegexp.FindString(name)
+}
+
+func readCBSPackage(parent registry.Key, name string) (installed bool, installTime time.Time) {
+	k, err := registry.OpenKey(parent, name, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return false, time.Time{}
+	}
+	defer k.Close()
+
+	if state, _, err := k.GetIntegerValue("CurrentState"); err != nil || state != cbsStateInstalled {
+		return false, time.Time{}
+	}
+
+	high, _, errHigh := k.GetIntegerValue("InstallTimeHigh")
+	low, _, errLow := k.GetIntegerValue("InstallTimeLow")
+	if errHigh == nil && errLow == nil {
+		ft := syscall.Filetime{HighDateTime: uint32(high), LowDateTime: uint32(low)}
+		installTime = time.Unix(0, ft.Nanoseconds()).UTC()
+	}
+	return true, installTime
+}
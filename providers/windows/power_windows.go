@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// systemPowerStatus is the SYSTEM_POWER_STATUS structure.
+type systemPowerStatus struct {
+	ACLineStatus        uint8
+	BatteryFlag         uint8
+	BatteryLifePercent  uint8
+	SystemStatusFlag    uint8
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// SYSTEM_POWER_STATUS values.
+const (
+	acLineOnline = 1
+	powerUnknown = 255
+
+	batteryFlagCharging  = 8
+	batteryFlagNoBattery = 128
+)
+
+// PowerSources returns the AC adapter and the combined state of the system
+// batteries from GetSystemPowerStatus. Battery health is not reported.
+func (h *host) PowerSources() ([]types.PowerSourceInfo, error) {
+	var status systemPowerStatus
+	if err := _GetSystemPowerStatus(&status); err != nil {
+		return nil, errors.Wrap(err, "GetSystemPowerStatus failed")
+	}
+	return powerSources(status), nil
+}
+
+func powerSources(status systemPowerStatus) []types.PowerSourceInfo {
+	var sources []types.PowerSourceInfo
+
+	if status.ACLineStatus != powerUnknown {
+		online := status.ACLineStatus == acLineOnline
+		sources = append(sources, types.PowerSourceInfo{
+			Name:   "AC",
+			Type:   types.PowerSourceMains,
+			Online: &online,
+		})
+	}
+
+	if status.BatteryFlag == powerUnknown || status.BatteryFlag&batteryFlagNoBattery != 0 {
+		return sources
+	}
+
+	battery := types.PowerSourceInfo{
+		Name: "Battery",
+		Type: types.PowerSourceBattery,
+	}
+	if status.BatteryLifePercent != powerUnknown {
+		battery.Percent = float64(status.BatteryLifePercent)
+	}
+	switch {
+	case status.BatteryFlag&batteryFlagCharging != 0:
+		battery.State = types.BatteryCharging
+	case status.ACLineStatus != acLineOnline:
+		battery.State = types.BatteryDischarging
+	case status.BatteryLifePercent == 100:
+		battery.State = types.BatteryFull
+	default:
+		battery.State = types.BatteryNotCharging
+	}
+	return append(sources, battery)
+}
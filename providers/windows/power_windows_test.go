@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestPowerSources(t *testing.T) {
+	online, offline := true, false
+
+	// Desktop without a battery.
+	assert.Equal(t, []types.PowerSourceInfo{
+		{Name: "AC", Type: types.PowerSourceMains, Online: &online},
+	}, powerSources(systemPowerStatus{ACLineStatus: 1, BatteryFlag: 128, BatteryLifePercent: 255}))
+
+	// Laptop on battery.
+	assert.Equal(t, []types.PowerSourceInfo{
+		{Name: "AC", Type: types.PowerSourceMains, Online: &offline},
+		{Name: "Battery", Type: types.PowerSourceBattery, Percent: 42, State: types.BatteryDischarging},
+	}, powerSources(systemPowerStatus{ACLineStatus: 0, BatteryFlag: 0, BatteryLifePercent: 42}))
+
+	// Laptop charging.
+	sources := powerSources(systemPowerStatus{ACLineStatus: 1, BatteryFlag: 8 | 1, BatteryLifePercent: 80})
+	if assert.Len(t, sources, 2) {
+		assert.Equal(t, types.BatteryCharging, sources[1].State)
+	}
+}
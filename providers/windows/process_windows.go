@@ -18,11 +18,13 @@
 package windows
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf16"
 	"unsafe"
 
 	"github.com/pkg/errors"
@@ -30,6 +32,7 @@ import (
 
 	windows "github.com/elastic/go-windows"
 
+	"github.com/elastic/go-sysinfo/providers/shared"
 	"github.com/elastic/go-sysinfo/types"
 )
 
@@ -39,20 +42,25 @@ var (
 )
 
 func (s windowsSystem) Processes() (procs []types.Process, err error) {
-	pids, err := windows.EnumProcesses()
+	return s.ProcessesContext(context.Background())
+}
+
+// ProcessesContext lists the processes and stops opening them when the
+// context is done.
+func (s windowsSystem) ProcessesContext(ctx context.Context) (procs []types.Process, err error) {
+	pids, err := enumProcesses()
 	if err != nil {
-		return nil, errors.Wrap(err, "EnumProcesses")
+		return nil, err
 	}
+	// The states of all processes are taken from a single snapshot.
+	states := processStates()
 	procs = make([]types.Process, 0, len(pids))
-	var proc types.Process
+	var proc *process
 	for _, pid := range pids {
-		if pid == 0 || pid == 4 {
-			// The Idle and System processes (PIDs 0 and 4) can never be
-			// opened by user-level code (see documentation for OpenProcess).
-			continue
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
-		if proc, err = s.Process(int(pid)); err == nil {
+		if proc, err = s.openProcess(int(pid), states); err == nil {
 			procs = append(procs, proc)
 		}
 	}
@@ -62,28 +70,87 @@ func (s windowsSystem) Processes() (procs []types.Process, err error) {
 	return procs, nil
 }
 
+// ForEachProcess opens the processes one at a time and passes them to fn so
+// that only the list of PIDs is held in memory. Processes that cannot be
+// opened are skipped. Iteration stops at the first error returned by fn.
+func (s windowsSystem) ForEachProcess(fn func(types.Process) error) error {
+	pids, err := enumProcesses()
+	if err != nil {
+		return err
+	}
+	states := processStates()
+	for _, pid := range pids {
+		proc, err := s.openProcess(int(pid), states)
+		if err != nil {
+			continue
+		}
+		if err = fn(proc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enumProcesses returns the PIDs of the processes that can be opened.
+func enumProcesses() ([]uint32, error) {
+	pids, err := windows.EnumProcesses()
+	if err != nil {
+		return nil, errors.Wrap(err, "EnumProcesses")
+	}
+
+	filtered := pids[:0]
+	for _, pid := range pids {
+		if pid == 0 || pid == 4 {
+			// The Idle and System processes (PIDs 0 and 4) can never be
+			// opened by user-level code (see documentation for OpenProcess).
+			continue
+		}
+		filtered = append(filtered, pid)
+	}
+	return filtered, nil
+}
+
 func (s windowsSystem) Process(pid int) (types.Process, error) {
-	return newProcess(pid)
+	return s.openProcess(pid, nil)
+}
+
+// ProcessWithFields opens the process and only reads the process parameters
+// from its memory when the working directory or arguments are selected.
+func (s windowsSystem) ProcessWithFields(pid int, fields types.ProcessField) (types.Process, error) {
+	s.fields = fields
+	return s.openProcess(pid, nil)
 }
 
 func (s windowsSystem) Self() (types.Process, error) {
-	return newProcess(selfPID)
+	return s.openProcess(selfPID, nil)
 }
 
 type process struct {
-	pid  int
-	info types.ProcessInfo
+	pid       int
+	fields    types.ProcessField // Zero selects all fields.
+	envFilter types.EnvFilter
+	info      types.ProcessInfo
+	hasState  bool  // info.State was taken from a snapshot or looked up.
+	partial   error // *types.PartialInfoError listing the fields that could not be read.
 }
 
 func (p *process) PID() int {
 	return p.pid
 }
 
-func newProcess(pid int) (*process, error) {
-	p := &process{pid: pid}
+// openProcess opens the process and reads its info. Its state is looked up
+// in states or, if states is nil, when Info is first called so that opening
+// a single process doesn't take a snapshot of all processes.
+func (s windowsSystem) openProcess(pid int, states map[int]string) (*process, error) {
+	p := &process{pid: pid, fields: s.fields, envFilter: s.envFilter}
 	if err := p.init(); err != nil {
 		return nil, err
 	}
+
+	if states != nil {
+		p.info.State = states[pid]
+		p.hasState = true
+	}
 	return p, nil
 }
 
@@ -94,11 +161,20 @@ func (p *process) init() error {
 	}
 	defer syscall.CloseHandle(handle)
 
+	var partial types.PartialInfoError
+
 	var path string
 	if imgf, err := windows.GetProcessImageFileName(handle); err == nil {
-		path, err = devMapper.DevicePathToDrivePath(imgf)
-		if err != nil {
-			path = imgf
+		path = imgf
+		if p.fields.Includes(types.FieldExe) {
+			if drivePath, err := devMapper.DevicePathToDrivePath(imgf); err == nil {
+				path = drivePath
+			}
+		}
+	} else {
+		partial.Add("name", err)
+		if p.fields.Includes(types.FieldExe) {
+			partial.Add("exe", err)
 		}
 	}
 
@@ -111,31 +187,49 @@ func (p *process) init() error {
 	// memory. This can fail due to missing access rights or when we are running
 	// as a 32bit process in a 64bit system (WOW64).
 	// Don't make this a fatal error: If it fails, `args` and `cwd` fields will
-	// be missing.
+	// be missing and reported in a PartialInfoError.
 	var args []string
 	var cwd string
 	var ppid int
+	var argsErr, cwdErr error
 	pbi, err := getProcessBasicInformation(handle)
 	if err == nil {
 		ppid = int(pbi.InheritedFromUniqueProcessID)
+	} else {
+		partial.Add("ppid", err)
+	}
+	argsErr, cwdErr = err, err
+	if err == nil && (p.fields.Includes(types.FieldArgs) || p.fields.Includes(types.FieldCWD)) {
 		userProcParams, err := getUserProcessParams(handle, pbi)
+		argsErr, cwdErr = err, err
 		if err == nil {
-			if argsW, err := readProcessUnicodeString(handle, &userProcParams.CommandLine); err == nil {
-				args, err = splitCommandline(argsW)
-				if err != nil {
-					args = nil
-				}
+			var argsW, cwdW []byte
+			if argsW, argsErr = readProcessUnicodeString(handle, &userProcParams.CommandLine); argsErr == nil {
+				args, argsErr = splitCommandline(argsW)
 			}
-			if cwdW, err := readProcessUnicodeString(handle, &userProcParams.CurrentDirectoryPath); err == nil {
-				cwd, _, err = windows.UTF16BytesToString(cwdW)
-				if err != nil {
-					cwd = ""
-				}
+			if cwdW, cwdErr = readProcessUnicodeString(handle, &userProcParams.CurrentDirectoryPath); cwdErr == nil {
+				cwd, _, cwdErr = windows.UTF16BytesToString(cwdW)
 				// Remove trailing separator
 				cwd = strings.TrimRight(cwd, "\\")
 			}
 		}
 	}
+	if argsErr != nil && p.fields.Includes(types.FieldArgs) {
+		// The PEB of protected processes can't be read, but on Windows 8.1
+		// and newer the command line can still be queried.
+		var argsW []byte
+		if argsW, argsErr = getProcessCommandLine(handle); argsErr == nil {
+			args, argsErr = splitCommandline(argsW)
+		}
+	}
+	if argsErr != nil && p.fields.Includes(types.FieldArgs) {
+		args = nil
+		partial.Add("args", argsErr)
+	}
+	if cwdErr != nil && p.fields.Includes(types.FieldCWD) {
+		cwd = ""
+		partial.Add("cwd", cwdErr)
+	}
 
 	p.info = types.ProcessInfo{
 		Name:      filepath.Base(path),
@@ -146,6 +240,19 @@ func (p *process) init() error {
 		CWD:       cwd,
 		StartTime: time.Unix(0, creationTime.Nanoseconds()),
 	}
+	if path == "" {
+		p.info.Name = ""
+	}
+	if !p.fields.Includes(types.FieldExe) {
+		p.info.Exe = ""
+	}
+	if !p.fields.Includes(types.FieldArgs) {
+		p.info.Args = nil
+	}
+	if !p.fields.Includes(types.FieldCWD) {
+		p.info.CWD = ""
+	}
+	p.partial = partial.ErrOrNil()
 	return nil
 }
 
@@ -157,14 +264,31 @@ func getProcessBasicInformation(handle syscall.Handle) (pbi windows.ProcessBasic
 	return pbi, err
 }
 
-func getUserProcessParams(handle syscall.Handle, pbi windows.ProcessBasicInformationStruct) (params windows.RtlUserProcessParameters, err error) {
+// userProcessParams contains the fields of the RTL_USER_PROCESS_PARAMETERS
+// struct that are read from the target process memory.
+type userProcessParams struct {
+	windows.RtlUserProcessParameters
+
+	// Environment is the address of the environment block. It immediately
+	// follows the CommandLine field.
+	Environment uintptr
+
+	// EnvironmentSize is the size in bytes of the environment block. It is
+	// only available in Vista and newer.
+	EnvironmentSize uintptr
+}
+
+func getUserProcessParams(handle syscall.Handle, pbi windows.ProcessBasicInformationStruct) (params userProcessParams, err error) {
 	const is32bitProc = unsafe.Sizeof(uintptr(0)) == 4
 
-	// Offset of params field within PEB structure.
-	// This structure is different in 32 and 64 bit.
+	// Offset of params field within PEB structure and offset of the
+	// EnvironmentSize field within RTL_USER_PROCESS_PARAMETERS structure.
+	// These structures are different in 32 and 64 bit.
 	paramsOffset := 0x20
+	envSizeOffset := 0x3F0
 	if is32bitProc {
 		paramsOffset = 0x10
+		envSizeOffset = 0x290
 	}
 
 	// Read the PEB from the target process memory
@@ -182,19 +306,40 @@ func getUserProcessParams(handle syscall.Handle, pbi windows.ProcessBasicInforma
 	paramsAddr := *(*uintptr)(unsafe.Pointer(&peb[paramsOffset]))
 
 	// Read the RTL_USER_PROCESS_PARAMETERS from the target process memory
-	paramsBuf := make([]byte, windows.SizeOfRtlUserProcessParameters)
+	paramsSize := envSizeOffset + int(unsafe.Sizeof(uintptr(0)))
+	paramsBuf := make([]byte, paramsSize)
 	nRead, err = windows.ReadProcessMemory(handle, paramsAddr, paramsBuf)
 	if err != nil {
 		return params, err
 	}
-	if nRead != uintptr(windows.SizeOfRtlUserProcessParameters) {
-		return params, errors.Errorf("RTL_USER_PROCESS_PARAMETERS: short read (%d/%d)", nRead, windows.SizeOfRtlUserProcessParameters)
+	if nRead != uintptr(paramsSize) {
+		return params, errors.Errorf("RTL_USER_PROCESS_PARAMETERS: short read (%d/%d)", nRead, paramsSize)
 	}
 
-	params = *(*windows.RtlUserProcessParameters)(unsafe.Pointer(&paramsBuf[0]))
+	params.RtlUserProcessParameters = *(*windows.RtlUserProcessParameters)(unsafe.Pointer(&paramsBuf[0]))
+	params.Environment = *(*uintptr)(unsafe.Pointer(&paramsBuf[unsafe.Offsetof(params.Environment)]))
+	params.EnvironmentSize = *(*uintptr)(unsafe.Pointer(&paramsBuf[envSizeOffset]))
 	return params, nil
 }
 
+// getProcessCommandLine returns the UTF-16 command line of the process using
+// ProcessCommandLineInformation. Unlike reading the PEB this only requires
+// PROCESS_QUERY_LIMITED_INFORMATION, but it is only available on Windows 8.1
+// and newer.
+func getProcessCommandLine(handle syscall.Handle) ([]byte, error) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := windows.NtQueryInformationProcess(handle, processCommandLineInformation, unsafe.Pointer(&buf[0]), uint32(len(buf)))
+		if err == nil {
+			return unicodeStringBytesAt(buf)
+		}
+		if status, ok := err.(windows.NTStatus); !ok || (status != statusInfoLengthMismatch && status != statusBufferTooSmall) || int(n) <= len(buf) {
+			return nil, err
+		}
+		buf = make([]byte, n)
+	}
+}
+
 // read an UTF-16 string from another process memory. Result is an []byte
 // with the UTF-16 data.
 func readProcessUnicodeString(handle syscall.Handle, s *windows.UnicodeString) ([]byte, error) {
@@ -209,6 +354,65 @@ func readProcessUnicodeString(handle syscall.Handle, s *windows.UnicodeString) (
 	return buf, nil
 }
 
+// maxEnvironmentSize limits the size of the environment block that is read
+// from another process.
+const maxEnvironmentSize = 16 * 1024 * 1024
+
+// read the environment block from another process memory. Result is an
+// []byte with the UTF-16 data.
+func readProcessEnvironment(handle syscall.Handle, params *userProcessParams) ([]byte, error) {
+	if params.Environment == 0 || params.EnvironmentSize == 0 {
+		return nil, errors.New("environment block is not available")
+	}
+	if params.EnvironmentSize > maxEnvironmentSize {
+		return nil, errors.Errorf("environment block is too large (%d bytes)", params.EnvironmentSize)
+	}
+
+	buf := make([]byte, params.EnvironmentSize)
+	nRead, err := windows.ReadProcessMemory(handle, params.Environment, buf)
+	if err != nil {
+		return nil, err
+	}
+	if nRead != params.EnvironmentSize {
+		return nil, errors.Errorf("environment: short read: (%d/%d)", nRead, params.EnvironmentSize)
+	}
+	return buf, nil
+}
+
+// parseEnvironmentBlock parses an UTF-16 environment block. The block is a
+// sequence of null-terminated key=value strings followed by an additional
+// null character. The hidden entries that track the current directory of
+// each drive (e.g. =C:=C:\Windows) are ignored.
+func parseEnvironmentBlock(block []byte) map[string]string {
+	chars := make([]uint16, len(block)/2)
+	for i := range chars {
+		chars[i] = uint16(block[2*i]) | uint16(block[2*i+1])<<8
+	}
+
+	env := map[string]string{}
+	for len(chars) > 0 {
+		end := 0
+		for end < len(chars) && chars[end] != 0 {
+			end++
+		}
+		if end == 0 {
+			// An empty string terminates the block.
+			break
+		}
+
+		kv := string(utf16.Decode(chars[:end]))
+		if idx := strings.IndexByte(kv, '='); idx > 0 {
+			env[kv[:idx]] = kv[idx+1:]
+		}
+
+		if end == len(chars) {
+			break
+		}
+		chars = chars[end+1:]
+	}
+	return env
+}
+
 // Use Windows' CommandLineToArgv API to split an UTF-16 command line string
 // into a list of parameters.
 func splitCommandline(utf16 []byte) ([]string, error) {
@@ -227,6 +431,8 @@ func splitCommandline(utf16 []byte) ([]string, error) {
 	return args, nil
 }
 
+const errorInvalidParameter = syscall.Errno(87) // ERROR_INVALID_PARAMETER
+
 func (p *process) open() (handle syscall.Handle, err error) {
 	if p.pid == selfPID {
 		return syscall.GetCurrentProcess()
@@ -245,11 +451,55 @@ func (p *process) open() (handle syscall.Handle, err error) {
 			break
 		}
 	}
+	if err == errorInvalidParameter {
+		// OpenProcess fails with ERROR_INVALID_PARAMETER for unused PIDs.
+		return handle, &types.ProcessNotFoundError{PID: p.pid}
+	}
 	return handle, err
 }
 
+// Info returns the process information that was read when the process was
+// opened. Fields that could not be read (e.g. the arguments of a protected
+// process) are listed in the returned *types.PartialInfoError.
 func (p *process) Info() (types.ProcessInfo, error) {
-	return p.info, nil
+	if !p.hasState {
+		p.info.State = p.state()
+		p.hasState = true
+	}
+	return p.info, p.partial
+}
+
+// Identity returns the PID, creation time, and boot ID of the process.
+func (p *process) Identity() (types.ProcessIdentity, error) {
+	return types.ProcessIdentity{PID: p.pid, StartTime: p.info.StartTime, BootID: bootID()}, nil
+}
+
+// Environment returns the environment variables of the process by reading
+// its PEB. This requires PROCESS_VM_READ access and fails for protected
+// processes.
+func (p *process) Environment() (map[string]string, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, errors.Wrap(err, "OpenProcess failed")
+	}
+	defer syscall.CloseHandle(handle)
+
+	pbi, err := getProcessBasicInformation(handle)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQueryInformationProcess failed")
+	}
+
+	params, err := getUserProcessParams(handle, pbi)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process parameters")
+	}
+
+	block, err := readProcessEnvironment(handle, &params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read process environment")
+	}
+
+	return shared.FilterEnv(p.envFilter, parseEnvironmentBlock(block)), nil
 }
 
 func (p *process) User() (types.UserInfo, error) {
@@ -306,6 +556,32 @@ func (p *process) Memory() (types.MemoryInfo, error) {
 	return types.MemoryInfo{
 		Resident: uint64(counters.WorkingSetSize),
 		Virtual:  uint64(counters.PrivateUsage),
+		Swap:     uint64(counters.PagefileUsage),
+	}, nil
+}
+
+// IOCounters returns the I/O statistics reported by GetProcessIoCounters.
+func (p *process) IOCounters() (*types.IOCountersInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	var counters ioCounters
+	if err = _GetProcessIoCounters(handle, &counters); err != nil {
+		return nil, errors.Wrap(err, "GetProcessIoCounters failed")
+	}
+
+	return &types.IOCountersInfo{
+		ReadBytes:  counters.ReadTransferCount,
+		WriteBytes: counters.WriteTransferCount,
+		ReadCount:  counters.ReadOperationCount,
+		WriteCount: counters.WriteOperationCount,
+		Metrics: map[string]uint64{
+			"other_count": counters.OtherOperationCount,
+			"other_bytes": counters.OtherTransferCount,
+		},
 	}, nil
 }
 
@@ -327,6 +603,24 @@ func (p *process) CPUTime() (types.CPUTimes, error) {
 	}, nil
 }
 
+// Children returns the direct children of the process.
+func (p *process) Children() ([]types.Process, error) {
+	procs, err := windowsSystem{fields: p.fields, envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Children(procs, p)
+}
+
+// Ancestors returns the parents of the process up to the root of the tree.
+func (p *process) Ancestors() ([]types.Process, error) {
+	procs, err := windowsSystem{fields: p.fields, envFilter: p.envFilter}.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.Ancestors(procs, p)
+}
+
 // OpenHandles returns the number of open handles of the process.
 func (p *process) OpenHandleCount() (int, error) {
 	handle, err := p.open()
@@ -338,3 +632,13 @@ func (p *process) OpenHandleCount() (int, error) {
 	count, err := windows.GetProcessHandleCount(handle)
 	return int(count), err
 }
+
+// Hashes hashes the executable of the process. Windows prevents running
+// images from being modified so the path refers to the running binary.
+func (p *process) Hashes(algorithms ...types.HashType) (map[types.HashType]string, error) {
+	info, err := p.Info()
+	if err != nil && (info.Exe == "" || !types.IsPartialInfo(err)) {
+		return nil, err
+	}
+	return shared.HashFile(info.Exe, algorithms...)
+}
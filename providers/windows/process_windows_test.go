@@ -18,8 +18,93 @@
 package windows
 
 import (
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
 	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/types"
 )
 
 var _ registry.HostProvider = windowsSystem{}
 var _ registry.ProcessProvider = windowsSystem{}
+var _ registry.NetworkProvider = windowsSystem{}
+var _ registry.FileSystemProvider = windowsSystem{}
+var _ registry.UserProvider = windowsSystem{}
+var _ registry.SignatureProvider = windowsSystem{}
+var _ registry.ProcessContextProvider = windowsSystem{}
+var _ registry.ProcessIterator = windowsSystem{}
+var _ registry.ProcessFieldsProvider = windowsSystem{}
+var _ registry.ProcessFieldsSelector = windowsSystem{}
+var _ registry.CacheTTLProvider = windowsSystem{}
+var _ registry.EnvFilterProvider = windowsSystem{}
+var _ types.DiskIOCounters = (*host)(nil)
+var _ types.NetworkInterfaces = (*host)(nil)
+var _ types.Hardware = (*host)(nil)
+var _ types.Virtualization = (*host)(nil)
+var _ types.KernelParameters = (*host)(nil)
+var _ types.Security = (*host)(nil)
+var _ types.MachineIdentifier = (*host)(nil)
+var _ types.Sensors = (*host)(nil)
+var _ types.PowerSources = (*host)(nil)
+var _ types.GPUs = (*host)(nil)
+var _ types.Devices = (*host)(nil)
+var _ types.CPUInfo = (*host)(nil)
+var _ types.CPUFrequency = (*host)(nil)
+var _ types.Uptime = (*host)(nil)
+var _ types.SessionEnumerator = (*host)(nil)
+var _ types.LoadedModules = (*host)(nil)
+var _ types.WindowsServices = (*host)(nil)
+var _ types.HandleCounts = (*host)(nil)
+var _ types.VMStat = (*host)(nil)
+var _ types.NUMA = (*host)(nil)
+var _ types.HugePages = (*host)(nil)
+var _ types.ClockInfo = (*host)(nil)
+var _ types.KernelCmdline = (*host)(nil)
+var _ types.CPUVulnerabilities = (*host)(nil)
+var _ types.RebootRequired = (*host)(nil)
+var _ types.PackageEnumerator = (*host)(nil)
+var _ types.OSPatches = (*host)(nil)
+var _ types.Routes = (*host)(nil)
+var _ types.Neighbors = (*host)(nil)
+var _ types.DNSConfig = (*host)(nil)
+var _ types.ProxyConfig = (*host)(nil)
+var _ types.Firewall = (*host)(nil)
+var _ types.TPM = (*host)(nil)
+var _ types.DiskHealth = (*host)(nil)
+var _ types.IOCounters = (*process)(nil)
+var _ types.ResourceUsage = (*process)(nil)
+var _ types.ThreadEnumerator = (*process)(nil)
+var _ types.AncestorEnumerator = (*process)(nil)
+var _ types.ModuleEnumerator = (*process)(nil)
+var _ types.MemoryMapEnumerator = (*process)(nil)
+var _ types.CodeSignature = (*process)(nil)
+var _ types.ProcessIdentifier = (*process)(nil)
+var _ types.WindowsHandles = (*process)(nil)
+var _ types.JobObject = (*process)(nil)
+var _ types.Scheduling = (*process)(nil)
+
+func TestParseEnvironmentBlock(t *testing.T) {
+	var block []byte
+	for _, c := range utf16.Encode([]rune("=C:=C:\\Users\x00PATH=C:\\Windows\x00EMPTY=\x00A=B=C\x00\x00")) {
+		block = append(block, byte(c), byte(c>>8))
+	}
+
+	assert.Equal(t, map[string]string{
+		"PATH":  `C:\Windows`,
+		"EMPTY": "",
+		"A":     "B=C",
+	}, parseEnvironmentBlock(block))
+}
+
+func TestMibIfRow2Size(t *testing.T) {
+	// sizeof(MIB_IF_ROW2) is the same for 32 and 64 bit.
+	assert.EqualValues(t, 1352, unsafe.Sizeof(mibIfRow2{}))
+}
+
+func TestSystemPerformanceInfo(t *testing.T) {
+	assert.EqualValues(t, 312, unsafe.Sizeof(systemPerformanceInfo{}))
+	assert.EqualValues(t, 296, unsafe.Offsetof(systemPerformanceInfo{}.ContextSwitches))
+}
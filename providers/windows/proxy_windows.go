@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Sources of proxy settings on Windows.
+const (
+	proxySourceWinHTTP = "winhttp" // Machine-wide settings used by services (netsh winhttp).
+	proxySourceWinINET = "wininet" // Settings of the current user used by browsers (Internet Options).
+)
+
+const winHTTPAccessTypeNamedProxy = 3 // WINHTTP_ACCESS_TYPE_NAMED_PROXY
+
+// winHTTPProxyInfo is Go's counterpart of the WINHTTP_PROXY_INFO struct.
+type winHTTPProxyInfo struct {
+	AccessType  uint32
+	Proxy       *uint16
+	ProxyBypass *uint16
+}
+
+// winHTTPCurrentUserIEProxyConfig is Go's counterpart of the
+// WINHTTP_CURRENT_USER_IE_PROXY_CONFIG struct.
+type winHTTPCurrentUserIEProxyConfig struct {
+	AutoDetect    int32
+	AutoConfigURL *uint16
+	Proxy         *uint16
+	ProxyBypass   *uint16
+}
+
+// ProxyConfig returns the WinHTTP proxy of the machine and the WinINET proxy
+// settings of the user running the process. Sources without a proxy are
+// omitted.
+func (h *host) ProxyConfig() ([]types.ProxySettings, error) {
+	var settings []types.ProxySettings
+
+	var info winHTTPProxyInfo
+	if err := _WinHttpGetDefaultProxyConfiguration(&info); err != nil {
+		return nil, errors.Wrap(err, "WinHttpGetDefaultProxyConfiguration failed")
+	}
+	proxy, bypass := utf16PtrToString(info.Proxy), utf16PtrToString(info.ProxyBypass)
+	freeGlobalStrings(info.Proxy, info.ProxyBypass)
+	if info.AccessType == winHTTPAccessTypeNamedProxy && proxy != "" {
+		settings = append(settings, parseWinProxy(proxySourceWinHTTP, proxy, bypass))
+	}
+
+	var ie winHTTPCurrentUserIEProxyConfig
+	if err := _WinHttpGetIEProxyConfigForCurrentUser(&ie); err != nil {
+		return nil, errors.Wrap(err, "WinHttpGetIEProxyConfigForCurrentUser failed")
+	}
+	s := parseWinProxy(proxySourceWinINET, utf16PtrToString(ie.Proxy), utf16PtrToString(ie.ProxyBypass))
+	s.AutoConfigURL = utf16PtrToString(ie.AutoConfigURL)
+	s.AutoDetect = ie.AutoDetect != 0
+	freeGlobalStrings(ie.AutoConfigURL, ie.Proxy, ie.ProxyBypass)
+	if s.HTTP != "" || s.HTTPS != "" || s.FTP != "" || s.SOCKS != "" || s.AutoConfigURL != "" || s.AutoDetect {
+		settings = append(settings, s)
+	}
+
+	return settings, nil
+}
+
+// freeGlobalStrings frees the strings that WinHTTP allocates with
+// GlobalAlloc.
+func freeGlobalStrings(strs ...*uint16) {
+	for _, s := range strs {
+		if s != nil {
+			_GlobalFree(s)
+		}
+	}
+}
+
+// parseWinProxy parses a proxy list of the form "host:port" (used for all
+// protocols) or "http=host:port;https=host:port;socks=host:port" and a bypass
+// list separated by semicolons or whitespace.
+func parseWinProxy(source, proxy, bypass string) types.ProxySettings {
+	s := types.ProxySettings{Source: source}
+
+	for _, entry := range strings.FieldsFunc(proxy, func(r rune) bool { return r == ';' || r == ' ' }) {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) == 1 {
+			s.HTTP, s.HTTPS, s.FTP = entry, entry, entry
+			continue
+		}
+		switch strings.ToLower(parts[0]) {
+		case "http":
+			s.HTTP = parts[1]
+		case "https":
+			s.HTTPS = parts[1]
+		case "ftp":
+			s.FTP = parts[1]
+		case "socks":
+			s.SOCKS = parts[1]
+		}
+	}
+
+	s.Bypass = strings.FieldsFunc(bypass, func(r rune) bool { return r == ';' || r == ' ' || r == '\t' })
+	return s
+}
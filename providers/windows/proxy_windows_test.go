@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestParseWinProxy(t *testing.T) {
+	assert.Equal(t, types.ProxySettings{
+		Source: "winhttp",
+		HTTP:   "proxy.example.com:8080",
+		HTTPS:  "proxy.example.com:8080",
+		FTP:    "proxy.example.com:8080",
+		Bypass: []string{"<local>", "*.example.com"},
+	}, parseWinProxy("winhttp", "proxy.example.com:8080", "<local>;*.example.com"))
+
+	assert.Equal(t, types.ProxySettings{
+		Source: "wininet",
+		HTTP:   "web.example.com:80",
+		HTTPS:  "secure.example.com:443",
+		SOCKS:  "socks.example.com:1080",
+	}, parseWinProxy("wininet", "http=web.example.com:80;https=secure.example.com:443;socks=socks.example.com:1080", ""))
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Registry locations that indicate a pending reboot. These are the same
+// checks that are made by Windows Update tooling.
+var rebootPendingKeys = []struct {
+	path   string
+	value  string // Empty if the existence of the key is checked.
+	reason string
+}{
+	{`SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending`, "", "component based servicing reboot pending"},
+	{`SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired`, "", "windows update reboot required"},
+	{`SYSTEM\CurrentControlSet\Control\Session Manager`, "PendingFileRenameOperations", "pending file rename operations"},
+}
+
+// RebootRequired checks the registry for pending servicing operations.
+func (h *host) RebootRequired() (*types.RebootRequiredInfo, error) {
+	info := &types.RebootRequiredInfo{}
+	for _, check := range rebootPendingKeys {
+		k, err := registry.OpenKey(registry.LOCAL_MACHINE, check.path, registry.QUERY_VALUE|registry.WOW64_64KEY)
+		if err != nil {
+			continue
+		}
+
+		found := true
+		if check.value != "" {
+			_, _, err = k.GetValue(check.value, nil)
+			found = err == nil
+		}
+		k.Close()
+
+		if found {
+			info.Reasons = append(info.Reasons, check.reason)
+		}
+	}
+
+	info.Required = len(info.Reasons) > 0
+	return info, nil
+}
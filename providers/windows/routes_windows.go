@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"net"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Offset of the Table array within MIB_IPFORWARD_TABLE2 and MIB_IPNET_TABLE2.
+// The rows contain a 64-bit LUID so they are 8-byte aligned on 32-bit Windows
+// too.
+const mibTable2RowsOffset = 8
+
+// rawSockaddrInet is Go's counterpart of the SOCKADDR_INET union.
+type rawSockaddrInet struct {
+	Family uint16
+	Data   [26]byte
+}
+
+// ip returns the address of a sockaddr_in or sockaddr_in6.
+func (sa *rawSockaddrInet) ip() (string, net.IP) {
+	switch sa.Family {
+	case syscall.AF_INET:
+		return types.FamilyIPv4, append(net.IP(nil), sa.Data[2:6]...)
+	case syscall.AF_INET6:
+		return types.FamilyIPv6, append(net.IP(nil), sa.Data[6:22]...)
+	default:
+		return "", nil
+	}
+}
+
+// mibIPForwardRow2 is Go's counterpart of the MIB_IPFORWARD_ROW2 struct.
+type mibIPForwardRow2 struct {
+	InterfaceLuid        uint64
+	InterfaceIndex       uint32
+	DestinationPrefix    rawSockaddrInet
+	PrefixLength         uint8
+	_                    [3]byte // Padding of IP_ADDRESS_PREFIX.
+	NextHop              rawSockaddrInet
+	SitePrefixLength     uint8
+	ValidLifetime        uint32
+	PreferredLifetime    uint32
+	Metric               uint32
+	Protocol             uint32
+	Loopback             uint8
+	AutoconfigureAddress uint8
+	Publish              uint8
+	Immortal             uint8
+	Age                  uint32
+	Origin               uint32
+}
+
+// mibIPNetRow2 is Go's counterpart of the MIB_IPNET_ROW2 struct.
+type mibIPNetRow2 struct {
+	Address               rawSockaddrInet
+	InterfaceIndex        uint32
+	InterfaceLuid         uint64
+	PhysicalAddress       [32]byte
+	PhysicalAddressLength uint32
+	State                 uint32
+	Flags                 uint8
+	ReachabilityTime      uint32
+}
+
+// NL_NEIGHBOR_STATE values.
+var neighborStates = map[uint32]string{
+	0: types.NeighborStateUnreachable,
+	1: types.NeighborStateIncomplete,
+	2: types.NeighborStateProbe,
+	3: types.NeighborStateDelay,
+	4: types.NeighborStateStale,
+	5: types.NeighborStateReachable,
+	6: types.NeighborStatePermanent,
+}
+
+// Routes returns the IPv4 and IPv6 routes from GetIpForwardTable2. The
+// metric is the route metric without the metric of the interface that
+// Windows adds when selecting a route.
+func (h *host) Routes() ([]types.RouteInfo, error) {
+	var table *byte
+	if err := _GetIpForwardTable2(syscall.AF_UNSPEC, &table); err != nil {
+		return nil, errors.Wrap(err, "GetIpForwardTable2 failed")
+	}
+	defer _FreeMibTable(table)
+
+	names := shared.InterfaceNames()
+	n := *(*uint32)(unsafe.Pointer(table))
+	routes := make([]types.RouteInfo, 0, n)
+	for i := uintptr(0); i < uintptr(n); i++ {
+		row := (*mibIPForwardRow2)(unsafe.Pointer(uintptr(unsafe.Pointer(table)) + mibTable2RowsOffset + i*unsafe.Sizeof(mibIPForwardRow2{})))
+
+		family, dst := row.DestinationPrefix.ip()
+		if dst == nil {
+			continue
+		}
+		route := types.RouteInfo{
+			Family:       family,
+			Destination:  dst,
+			PrefixLength: int(row.PrefixLength),
+			Interface:    names[int(row.InterfaceIndex)],
+			Metric:       int(row.Metric),
+		}
+		if _, gw := row.NextHop.ip(); gw != nil && !gw.IsUnspecified() {
+			route.Gateway = gw
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// Neighbors returns the ARP and NDP entries from GetIpNetTable2.
+func (h *host) Neighbors() ([]types.NeighborInfo, error) {
+	var table *byte
+	if err := _GetIpNetTable2(syscall.AF_UNSPEC, &table); err != nil {
+		return nil, errors.Wrap(err, "GetIpNetTable2 failed")
+	}
+	defer _FreeMibTable(table)
+
+	names := shared.InterfaceNames()
+	n := *(*uint32)(unsafe.Pointer(table))
+	neighbors := make([]types.NeighborInfo, 0, n)
+	for i := uintptr(0); i < uintptr(n); i++ {
+		row := (*mibIPNetRow2)(unsafe.Pointer(uintptr(unsafe.Pointer(table)) + mibTable2RowsOffset + i*unsafe.Sizeof(mibIPNetRow2{})))
+
+		family, ip := row.Address.ip()
+		if ip == nil {
+			continue
+		}
+		neighbor := types.NeighborInfo{
+			Family:    family,
+			IP:        ip,
+			Interface: names[int(row.InterfaceIndex)],
+			State:     neighborStates[row.State],
+		}
+		if l := row.PhysicalAddressLength; l > 0 && l <= uint32(len(row.PhysicalAddress)) {
+			neighbor.MAC = net.HardwareAddr(row.PhysicalAddress[:l]).String()
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
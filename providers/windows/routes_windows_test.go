@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestMibTable2RowSizes(t *testing.T) {
+	// The sizes are the same for 32 and 64 bit.
+	assert.EqualValues(t, 28, unsafe.Sizeof(rawSockaddrInet{}))
+	assert.EqualValues(t, 104, unsafe.Sizeof(mibIPForwardRow2{}))
+	assert.EqualValues(t, 44, unsafe.Offsetof(mibIPForwardRow2{}.NextHop))
+	assert.EqualValues(t, 84, unsafe.Offsetof(mibIPForwardRow2{}.Metric))
+	assert.EqualValues(t, 88, unsafe.Sizeof(mibIPNetRow2{}))
+	assert.EqualValues(t, 76, unsafe.Offsetof(mibIPNetRow2{}.State))
+}
+
+func TestRawSockaddrInetIP(t *testing.T) {
+	sa := rawSockaddrInet{Family: syscall.AF_INET}
+	copy(sa.Data[2:], []byte{192, 168, 1, 1})
+	family, ip := sa.ip()
+	assert.Equal(t, types.FamilyIPv4, family)
+	assert.Equal(t, net.IP{192, 168, 1, 1}, ip)
+
+	sa = rawSockaddrInet{Family: syscall.AF_INET6}
+	copy(sa.Data[6:], net.ParseIP("fe80::1"))
+	family, ip = sa.ip()
+	assert.Equal(t, types.FamilyIPv6, family)
+	assert.Equal(t, "fe80::1", ip.String())
+
+	_, ip = (&rawSockaddrInet{}).ip()
+	assert.Nil(t, ip)
+}
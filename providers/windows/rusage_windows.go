@@ -0,0 +1,171 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// unicodeString is the UNICODE_STRING structure.
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        uintptr
+}
+
+// systemProcessInfo is the SYSTEM_PROCESS_INFORMATION structure. It is
+// followed in memory by NumberOfThreads systemThreadInfo entries.
+type systemProcessInfo struct {
+	NextEntryOffset              uint32
+	NumberOfThreads              uint32
+	WorkingSetPrivateSize        int64
+	HardFaultCount               uint32
+	NumberOfThreadsHighWatermark uint32
+	CycleTime                    uint64
+	CreateTime                   int64
+	UserTime                     int64
+	KernelTime                   int64
+	ImageName                    unicodeString
+	BasePriority                 int32
+	UniqueProcessID              uintptr
+	InheritedFromUniqueProcessID uintptr
+	HandleCount                  uint32
+	SessionID                    uint32
+	UniqueProcessKey             uintptr
+	PeakVirtualSize              uintptr
+	VirtualSize                  uintptr
+	PageFaultCount               uint32
+	PeakWorkingSetSize           uintptr
+	WorkingSetSize               uintptr
+	QuotaPeakPagedPoolUsage      uintptr
+	QuotaPagedPoolUsage          uintptr
+	QuotaPeakNonPagedPoolUsage   uintptr
+	QuotaNonPagedPoolUsage       uintptr
+	PagefileUsage                uintptr
+	PeakPagefileUsage            uintptr
+	PrivatePageCount             uintptr
+	ReadOperationCount           int64
+	WriteOperationCount          int64
+	OtherOperationCount          int64
+	ReadTransferCount            int64
+	WriteTransferCount           int64
+	OtherTransferCount           int64
+}
+
+// systemThreadInfo is the SYSTEM_THREAD_INFORMATION structure.
+type systemThreadInfo struct {
+	KernelTime      int64
+	UserTime        int64
+	CreateTime      int64
+	WaitTime        uint32
+	StartAddress    uintptr
+	ClientID        [2]uintptr
+	Priority        int32
+	BasePriority    int32
+	ContextSwitches uint32
+	ThreadState     uint32
+	WaitReason      uint32
+}
+
+// sizeofSystemThreadInfo is the size of SYSTEM_THREAD_INFORMATION including
+// the trailing padding that the C compiler adds for its 8 byte alignment.
+const sizeofSystemThreadInfo = (unsafe.Sizeof(systemThreadInfo{}) + 7) &^ 7
+
+// ResourceUsage returns the page fault and context switch counts of the
+// process. Windows does not distinguish voluntary context switches.
+func (p *process) ResourceUsage() (*types.ResourceUsageInfo, error) {
+	buf, err := NtQuerySystemInformation(systemProcessInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQuerySystemInformation failed")
+	}
+
+	return parseSystemProcessInformation(buf, p.pid)
+}
+
+// parseSystemProcessInformation finds the entry for pid in a
+// SystemProcessInformation buffer and sums the context switches of its
+// threads.
+func parseSystemProcessInformation(buf []byte, pid int) (*types.ResourceUsageInfo, error) {
+	info, threads, err := findSystemProcessInfo(buf, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &types.ResourceUsageInfo{
+		MajorPageFaults: uint64(info.HardFaultCount),
+	}
+	if info.PageFaultCount > info.HardFaultCount {
+		usage.MinorPageFaults = uint64(info.PageFaultCount - info.HardFaultCount)
+	}
+	for _, thread := range threads {
+		usage.ContextSwitches += uint64(thread.ContextSwitches)
+	}
+	return usage, nil
+}
+
+// findSystemProcessInfo returns the entry for pid and its threads from a
+// SystemProcessInformation buffer. The returned values point into buf.
+func findSystemProcessInfo(buf []byte, pid int) (*systemProcessInfo, []*systemThreadInfo, error) {
+	var info *systemProcessInfo
+	var threads []*systemThreadInfo
+	err := walkSystemProcessInformation(buf, func(p *systemProcessInfo, t []*systemThreadInfo) bool {
+		if int(p.UniqueProcessID) != pid {
+			return true
+		}
+		info, threads = p, t
+		return false
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if info == nil {
+		return nil, nil, errors.Errorf("process %d not found in SYSTEM_PROCESS_INFORMATION", pid)
+	}
+	return info, threads, nil
+}
+
+// walkSystemProcessInformation calls fn for each entry of a
+// SystemProcessInformation buffer until fn returns false.
+func walkSystemProcessInformation(buf []byte, fn func(*systemProcessInfo, []*systemThreadInfo) bool) error {
+	const size = unsafe.Sizeof(systemProcessInfo{})
+	for off := uintptr(0); off+size <= uintptr(len(buf)); {
+		info := (*systemProcessInfo)(unsafe.Pointer(&buf[off]))
+		start := off + size
+		if start+uintptr(info.NumberOfThreads)*sizeofSystemThreadInfo > uintptr(len(buf)) {
+			return errors.New("SYSTEM_PROCESS_INFORMATION thread array exceeds buffer")
+		}
+
+		threads := make([]*systemThreadInfo, 0, info.NumberOfThreads)
+		for i := uintptr(0); i < uintptr(info.NumberOfThreads); i++ {
+			threads = append(threads, (*systemThreadInfo)(unsafe.Pointer(&buf[start+i*sizeofSystemThreadInfo])))
+		}
+		if !fn(info, threads) {
+			return nil
+		}
+
+		if info.NextEntryOffset == 0 {
+			break
+		}
+		off += uintptr(info.NextEntryOffset)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSystemProcessInformation(t *testing.T) {
+	const procSize = unsafe.Sizeof(systemProcessInfo{})
+
+	entry := func(pid uintptr, threads ...uint32) []byte {
+		b := make([]byte, procSize+uintptr(len(threads))*sizeofSystemThreadInfo)
+		info := (*systemProcessInfo)(unsafe.Pointer(&b[0]))
+		info.NextEntryOffset = uint32(len(b))
+		info.NumberOfThreads = uint32(len(threads))
+		info.UniqueProcessID = pid
+		info.PageFaultCount = 100
+		info.HardFaultCount = 10
+		for i, csw := range threads {
+			thread := (*systemThreadInfo)(unsafe.Pointer(&b[procSize+uintptr(i)*sizeofSystemThreadInfo]))
+			thread.ContextSwitches = csw
+		}
+		return b
+	}
+
+	last := entry(8, 1, 2, 3)
+	(*systemProcessInfo)(unsafe.Pointer(&last[0])).NextEntryOffset = 0
+	buf := append(entry(4, 50), last...)
+
+	usage, err := parseSystemProcessInformation(buf, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, 90, usage.MinorPageFaults)
+	assert.EqualValues(t, 10, usage.MajorPageFaults)
+	assert.EqualValues(t, 6, usage.ContextSwitches)
+
+	_, err = parseSystemProcessInformation(buf, 12)
+	assert.Error(t, err)
+}
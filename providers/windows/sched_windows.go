@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// priorityClasses maps the priority classes returned by GetPriorityClass to
+// their name and base priority.
+var priorityClasses = map[uint32]struct {
+	name     string
+	priority int
+}{
+	0x40:   {"idle", 4},          // IDLE_PRIORITY_CLASS
+	0x4000: {"below_normal", 6},  // BELOW_NORMAL_PRIORITY_CLASS
+	0x20:   {"normal", 8},        // NORMAL_PRIORITY_CLASS
+	0x8000: {"above_normal", 10}, // ABOVE_NORMAL_PRIORITY_CLASS
+	0x80:   {"high", 13},         // HIGH_PRIORITY_CLASS
+	0x100:  {"realtime", 24},     // REALTIME_PRIORITY_CLASS
+}
+
+// Scheduling returns the priority class and base priority of the process.
+func (p *process) Scheduling() (*types.SchedulingInfo, error) {
+	handle, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	class, err := _GetPriorityClass(handle)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetPriorityClass failed")
+	}
+	return priorityClassInfo(class), nil
+}
+
+func priorityClassInfo(class uint32) *types.SchedulingInfo {
+	pc, found := priorityClasses[class]
+	if !found {
+		return &types.SchedulingInfo{Policy: fmt.Sprintf("0x%x", class)}
+	}
+	return &types.SchedulingInfo{Policy: pc.name, Priority: pc.priority}
+}
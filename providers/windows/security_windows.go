@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// SecurityInfo reports the state of Secure Boot, BitLocker, and Microsoft
+// Defender. The values are read from the registry and the service manager
+// because WMI is not available from Go without COM.
+func (h *host) SecurityInfo() (*types.SecurityInfo, error) {
+	info := &types.SecurityInfo{}
+
+	if v, err := registryDWORD(`SYSTEM\CurrentControlSet\Control\SecureBoot\State`, "UEFISecureBootEnabled"); err == nil {
+		b := v == 1
+		info.SecureBoot = &b
+	}
+
+	// BootStatus is set to 1 when the OS volume is protected by BitLocker.
+	if v, err := registryDWORD(`SYSTEM\CurrentControlSet\Control\BitLockerStatus`, "BootStatus"); err == nil {
+		b := v == 1
+		info.BitLocker = &b
+	}
+
+	if running, err := serviceRunning("WinDefend"); err == nil {
+		info.Defender = &types.DefenderInfo{Running: running}
+		if v, err := registryDWORD(`SOFTWARE\Microsoft\Windows Defender\Real-Time Protection`, "DisableRealtimeMonitoring"); err == nil {
+			b := v == 0
+			info.Defender.RealTimeProtection = &b
+		}
+	}
+
+	return info, nil
+}
+
+func registryDWORD(path, name string) (uint64, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return 0, errors.Wrapf(err, `failed to open HKLM\%v`, path)
+	}
+	defer k.Close()
+
+	v, _, err := k.GetIntegerValue(name)
+	if err != nil {
+		return 0, errors.Wrapf(err, `failed to get value of HKLM\%v\%v`, path, name)
+	}
+	return v, nil
+}
+
+// serviceRunning returns true if the named service is running.
+func serviceRunning(name string) (bool, error) {
+	scm, err := syswin.OpenSCManager(nil, nil, syswin.SC_MANAGER_CONNECT)
+	if err != nil {
+		return false, errors.Wrap(err, "OpenSCManager failed")
+	}
+	defer syswin.CloseServiceHandle(scm)
+
+	svc, err := syswin.OpenService(scm, syswin.StringToUTF16Ptr(name), syswin.SERVICE_QUERY_STATUS)
+	if err != nil {
+		return false, errors.Wrapf(err, "OpenService %v failed", name)
+	}
+	defer syswin.CloseServiceHandle(svc)
+
+	var status syswin.SERVICE_STATUS
+	if err = syswin.QueryServiceStatus(svc, &status); err != nil {
+		return false, errors.Wrapf(err, "QueryServiceStatus %v failed", name)
+	}
+	return status.CurrentState == syswin.SERVICE_RUNNING, nil
+}
@@ -0,0 +1,134 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// Thermal zone counters of the ACPI thermal zones. These expose the same
+// data as the WMI class MSAcpi_ThermalZoneTemperature without requiring
+// COM. The high precision counter (Windows 10+) is in tenths of Kelvin.
+const (
+	thermalZoneHighPrecisionCounter = `\Thermal Zone Information(*)\High Precision Temperature`
+	thermalZoneCounter              = `\Thermal Zone Information(*)\Temperature`
+)
+
+const (
+	pdhFmtDouble        = 0x00000200
+	pdhMoreData         = 0x800007D2
+	pdhCStatusNoObject  = 0xC0000BB8
+	pdhCStatusNoCounter = 0xC0000BB9
+)
+
+// pdhFmtCounterValueItemDouble is PDH_FMT_COUNTERVALUE_ITEM_DOUBLE. The
+// value is 8-byte aligned on all architectures.
+type pdhFmtCounterValueItemDouble struct {
+	Name    *uint16
+	_       [8 - unsafe.Sizeof(uintptr(0))]byte
+	CStatus uint32
+	_       uint32
+	Value   float64
+}
+
+// Sensors returns the temperatures of the ACPI thermal zones. Hosts without
+// thermal zones (e.g. most virtual machines) have no sensors.
+func (h *host) Sensors() ([]types.SensorInfo, error) {
+	values, err := pdhCounterArray(thermalZoneHighPrecisionCounter)
+	divisor := 10.0
+	if err != nil || len(values) == 0 {
+		values, err = pdhCounterArray(thermalZoneCounter)
+		divisor = 1
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := make([]types.SensorInfo, 0, len(values))
+	for _, v := range values {
+		sensors = append(sensors, types.SensorInfo{
+			Type:   types.SensorTemperature,
+			Device: "acpi",
+			Label:  v.name,
+			Value:  v.value/divisor - 273.15,
+		})
+	}
+	return sensors, nil
+}
+
+type pdhValue struct {
+	name  string
+	value float64
+}
+
+// pdhCounterArray collects a single sample of a wildcard counter path and
+// returns the value of each instance. It returns no values when the counter
+// does not exist.
+func pdhCounterArray(path string) ([]pdhValue, error) {
+	var query syscall.Handle
+	if status := _PdhOpenQuery(nil, 0, &query); status != 0 {
+		return nil, errors.Errorf("PdhOpenQuery failed with status 0x%X", status)
+	}
+	defer _PdhCloseQuery(query)
+
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	var counter syscall.Handle
+	switch status := _PdhAddEnglishCounter(query, pathPtr, 0, &counter); status {
+	case 0:
+	case pdhCStatusNoObject, pdhCStatusNoCounter:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("PdhAddEnglishCounter for %v failed with status 0x%X", path, status)
+	}
+	if status := _PdhCollectQueryData(query); status != 0 {
+		return nil, errors.Errorf("PdhCollectQueryData failed with status 0x%X", status)
+	}
+
+	var size, count uint32
+	switch status := _PdhGetFormattedCounterArray(counter, pdhFmtDouble, &size, &count, nil); status {
+	case pdhMoreData:
+	case 0:
+		// No instances.
+		return nil, nil
+	default:
+		return nil, errors.Errorf("PdhGetFormattedCounterArray failed with status 0x%X", status)
+	}
+	buf := make([]byte, size)
+	if status := _PdhGetFormattedCounterArray(counter, pdhFmtDouble, &size, &count, &buf[0]); status != 0 {
+		return nil, errors.Errorf("PdhGetFormattedCounterArray failed with status 0x%X", status)
+	}
+
+	itemSize := unsafe.Sizeof(pdhFmtCounterValueItemDouble{})
+	values := make([]pdhValue, 0, count)
+	for i := uintptr(0); i < uintptr(count) && (i+1)*itemSize <= uintptr(len(buf)); i++ {
+		item := (*pdhFmtCounterValueItemDouble)(unsafe.Pointer(&buf[i*itemSize]))
+		values = append(values, pdhValue{
+			name:  utf16PtrToString(item.Name),
+			value: item.Value,
+		})
+	}
+	return values, nil
+}
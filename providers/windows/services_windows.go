@@ -0,0 +1,143 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// WindowsServices returns the Win32 services that are registered with the
+// service control manager. The configuration of services that cannot be
+// opened by the current user is omitted.
+func (h *host) WindowsServices() ([]types.WindowsServiceInfo, error) {
+	scm, err := syswin.OpenSCManager(nil, nil, syswin.SC_MANAGER_ENUMERATE_SERVICE)
+	if err != nil {
+		return nil, errors.Wrap(err, "OpenSCManager failed")
+	}
+	defer syswin.CloseServiceHandle(scm)
+
+	var services []types.WindowsServiceInfo
+	var buf []byte
+	var needed, count, resume uint32
+	for {
+		var p *byte
+		if len(buf) > 0 {
+			p = &buf[0]
+		}
+		err := syswin.EnumServicesStatusEx(scm, syswin.SC_ENUM_PROCESS_INFO, syswin.SERVICE_WIN32,
+			syswin.SERVICE_STATE_ALL, p, uint32(len(buf)), &needed, &count, &resume, nil)
+		if err != nil && err != syscall.ERROR_MORE_DATA {
+			return nil, errors.Wrap(err, "EnumServicesStatusEx failed")
+		}
+
+		for i := uintptr(0); i < uintptr(count); i++ {
+			entry := (*syswin.ENUM_SERVICE_STATUS_PROCESS)(unsafe.Pointer(uintptr(unsafe.Pointer(p)) + i*unsafe.Sizeof(syswin.ENUM_SERVICE_STATUS_PROCESS{})))
+			service := types.WindowsServiceInfo{
+				Name:        utf16PtrToString(entry.ServiceName),
+				DisplayName: utf16PtrToString(entry.DisplayName),
+				State:       serviceState(entry.ServiceStatusProcess.CurrentState),
+				PID:         int(entry.ServiceStatusProcess.ProcessId),
+			}
+			serviceConfig(scm, &service)
+			services = append(services, service)
+		}
+
+		if err == nil {
+			return services, nil
+		}
+		if needed > uint32(len(buf)) {
+			buf = make([]byte, needed)
+		}
+	}
+}
+
+// serviceConfig fills in the start type, binary path, and account of the
+// service from QueryServiceConfig.
+func serviceConfig(scm syswin.Handle, service *types.WindowsServiceInfo) {
+	name, err := syscall.UTF16PtrFromString(service.Name)
+	if err != nil {
+		return
+	}
+	handle, err := syswin.OpenService(scm, name, syswin.SERVICE_QUERY_CONFIG)
+	if err != nil {
+		return
+	}
+	defer syswin.CloseServiceHandle(handle)
+
+	var needed uint32
+	buf := make([]byte, 1024)
+	for {
+		err = syswin.QueryServiceConfig(handle, (*syswin.QUERY_SERVICE_CONFIG)(unsafe.Pointer(&buf[0])), uint32(len(buf)), &needed)
+		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
+			break
+		}
+		buf = make([]byte, needed)
+	}
+	if err != nil {
+		return
+	}
+
+	config := (*syswin.QUERY_SERVICE_CONFIG)(unsafe.Pointer(&buf[0]))
+	service.StartType = serviceStartType(config.StartType)
+	service.BinaryPath = utf16PtrToString(config.BinaryPathName)
+	service.Account = utf16PtrToString(config.ServiceStartName)
+}
+
+func serviceState(state uint32) string {
+	switch state {
+	case syswin.SERVICE_STOPPED:
+		return "stopped"
+	case syswin.SERVICE_START_PENDING:
+		return "start_pending"
+	case syswin.SERVICE_STOP_PENDING:
+		return "stop_pending"
+	case syswin.SERVICE_RUNNING:
+		return "running"
+	case syswin.SERVICE_CONTINUE_PENDING:
+		return "continue_pending"
+	case syswin.SERVICE_PAUSE_PENDING:
+		return "pause_pending"
+	case syswin.SERVICE_PAUSED:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+func serviceStartType(startType uint32) string {
+	switch startType {
+	case syswin.SERVICE_BOOT_START:
+		return "boot"
+	case syswin.SERVICE_SYSTEM_START:
+		return "system"
+	case syswin.SERVICE_AUTO_START:
+		return "auto"
+	case syswin.SERVICE_DEMAND_START:
+		return "manual"
+	case syswin.SERVICE_DISABLED:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
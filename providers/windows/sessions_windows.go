@@ -0,0 +1,121 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// wtsCurrentServerHandle (WTS_CURRENT_SERVER_HANDLE) refers to the local
+	// terminal server.
+	wtsCurrentServerHandle = 0
+
+	// WTS_INFO_CLASS values.
+	wtsClientName  = 10
+	wtsSessionInfo = 24
+)
+
+// wtsSessionInfoW is the WTS_SESSION_INFOW structure.
+type wtsSessionInfoW struct {
+	SessionID      uint32
+	WinStationName *uint16
+	State          int32
+}
+
+// wtsInfoW is the WTSINFOW structure.
+type wtsInfoW struct {
+	State                   int32
+	SessionID               uint32
+	IncomingBytes           uint32
+	OutgoingBytes           uint32
+	IncomingFrames          uint32
+	OutgoingFrames          uint32
+	IncomingCompressedBytes uint32
+	OutgoingCompressedBytes uint32
+	WinStationName          [32]uint16
+	Domain                  [17]uint16
+	UserName                [21]uint16
+	_                       [4]byte // Aligns the LARGE_INTEGER fields on 386.
+	ConnectTime             int64
+	DisconnectTime          int64
+	LastInputTime           int64
+	LogonTime               int64
+	CurrentTime             int64
+}
+
+// Sessions returns the Remote Desktop Services sessions that have a user
+// logged in. This includes the local console session.
+func (h *host) Sessions() ([]types.SessionInfo, error) {
+	var list *wtsSessionInfoW
+	var count uint32
+	if err := _WTSEnumerateSessions(wtsCurrentServerHandle, 0, 1, &list, &count); err != nil {
+		return nil, errors.Wrap(err, "WTSEnumerateSessions failed")
+	}
+	defer _WTSFreeMemory(uintptr(unsafe.Pointer(list)))
+
+	var sessions []types.SessionInfo
+	for i := uintptr(0); i < uintptr(count); i++ {
+		entry := (*wtsSessionInfoW)(unsafe.Pointer(uintptr(unsafe.Pointer(list)) + i*unsafe.Sizeof(*list)))
+
+		var info *wtsInfoW
+		if err := wtsQuerySessionInformation(entry.SessionID, wtsSessionInfo, unsafe.Pointer(&info)); err != nil {
+			// The session was closed after it was listed.
+			continue
+		}
+		session := types.SessionInfo{
+			ID:       entry.SessionID,
+			User:     syscall.UTF16ToString(info.UserName[:]),
+			Terminal: utf16PtrToString(entry.WinStationName),
+		}
+		if domain := syscall.UTF16ToString(info.Domain[:]); domain != "" && session.User != "" {
+			session.User = domain + `\` + session.User
+		}
+		if info.LogonTime > 0 {
+			ft := syscall.Filetime{LowDateTime: uint32(info.LogonTime), HighDateTime: uint32(info.LogonTime >> 32)}
+			session.LoginTime = time.Unix(0, ft.Nanoseconds())
+		}
+		_WTSFreeMemory(uintptr(unsafe.Pointer(info)))
+
+		// Services and listener sessions do not have a user.
+		if session.User == "" {
+			continue
+		}
+
+		var client *uint16
+		if err := wtsQuerySessionInformation(entry.SessionID, wtsClientName, unsafe.Pointer(&client)); err == nil {
+			session.RemoteHost = utf16PtrToString(client)
+			_WTSFreeMemory(uintptr(unsafe.Pointer(client)))
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// wtsQuerySessionInformation stores a pointer to the requested information in
+// buf. The caller must release it with WTSFreeMemory.
+func wtsQuerySessionInformation(sessionID uint32, infoClass uint32, buf unsafe.Pointer) error {
+	var size uint32
+	return _WTSQuerySessionInformation(wtsCurrentServerHandle, sessionID, infoClass, (**uint16)(buf), &size)
+}
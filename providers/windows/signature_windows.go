@@ -0,0 +1,272 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// WINTRUST_DATA values.
+	wtdUINone                = 2
+	wtdRevokeNone            = 0
+	wtdChoiceFile            = 1
+	wtdChoiceCatalog         = 2
+	wtdStateActionVerify     = 1
+	wtdStateActionClose      = 2
+	wtdCacheOnlyURLRetrieval = 0x1000
+
+	// WinVerifyTrust results that indicate that the file is not signed.
+	trustENoSignature        = 0x800B0100
+	trustESubjectFormUnknown = 0x800B0003
+	trustEProviderUnknown    = 0x800B0001
+)
+
+// wintrustActionGenericVerifyV2 (WINTRUST_ACTION_GENERIC_VERIFY_V2) verifies
+// Authenticode signatures.
+var wintrustActionGenericVerifyV2 = syscall.GUID{
+	Data1: 0x00AAC56B,
+	Data2: 0xCD44,
+	Data3: 0x11D0,
+	Data4: [8]byte{0x8C, 0xC2, 0x00, 0xC0, 0x4F, 0xC2, 0x95, 0xEE},
+}
+
+// wintrustFileInfo is the WINTRUST_FILE_INFO structure.
+type wintrustFileInfo struct {
+	Size         uint32
+	FilePath     *uint16
+	File         syscall.Handle
+	KnownSubject *syscall.GUID
+}
+
+// wintrustCatalogInfo is the WINTRUST_CATALOG_INFO structure.
+type wintrustCatalogInfo struct {
+	Size               uint32
+	CatalogVersion     uint32
+	CatalogFilePath    *uint16
+	MemberTag          *uint16
+	MemberFilePath     *uint16
+	MemberFile         syscall.Handle
+	CalculatedFileHash *byte
+	CalculatedHashSize uint32
+	CatalogContext     uintptr
+	CatAdmin           syscall.Handle
+}
+
+// wintrustData is the WINTRUST_DATA structure.
+type wintrustData struct {
+	Size               uint32
+	PolicyCallbackData uintptr
+	SIPClientData      uintptr
+	UIChoice           uint32
+	RevocationChecks   uint32
+	UnionChoice        uint32
+	Info               unsafe.Pointer // WINTRUST_FILE_INFO or WINTRUST_CATALOG_INFO.
+	StateAction        uint32
+	StateData          syscall.Handle
+	URLReference       *uint16
+	ProvFlags          uint32
+	UIContext          uint32
+	SignatureSettings  uintptr
+}
+
+// catalogInfo is the CATALOG_INFO structure.
+type catalogInfo struct {
+	Size        uint32
+	CatalogFile [syscall.MAX_PATH]uint16
+}
+
+// cryptProviderCert is the beginning of the CRYPT_PROVIDER_CERT structure.
+type cryptProviderCert struct {
+	Size uint32
+	Cert *certContext
+}
+
+// certContext is the CERT_CONTEXT structure.
+type certContext struct {
+	EncodingType uint32
+	EncodedCert  *byte
+	Length       uint32
+	CertInfo     uintptr
+	Store        syscall.Handle
+}
+
+// FileSignature verifies the Authenticode signature of the file. Files that
+// do not have an embedded signature are looked up in the system catalogs.
+// Revocation is not checked to avoid network requests.
+func (s windowsSystem) FileSignature(path string) (*types.SignatureInfo, error) {
+	return authenticodeSignature(path)
+}
+
+// SignatureInfo verifies the Authenticode signature of the executable of the
+// process.
+func (p *process) SignatureInfo() (*types.SignatureInfo, error) {
+	info, err := p.Info()
+	if err != nil && (info.Exe == "" || !types.IsPartialInfo(err)) {
+		return nil, err
+	}
+	return authenticodeSignature(info.Exe)
+}
+
+func authenticodeSignature(path string) (*types.SignatureInfo, error) {
+	pathW, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo := wintrustFileInfo{FilePath: pathW}
+	fileInfo.Size = uint32(unsafe.Sizeof(fileInfo))
+	info, signed := verifyTrust(wtdChoiceFile, unsafe.Pointer(&fileInfo))
+	if signed {
+		return info, nil
+	}
+
+	info, err = verifyCatalog(path, pathW)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check the catalog signature of %v", path)
+	}
+	return info, nil
+}
+
+// verifyCatalog verifies the signature of the catalog that contains the hash
+// of the file.
+func verifyCatalog(path string, pathW *uint16) (*types.SignatureInfo, error) {
+	unsigned := &types.SignatureInfo{Status: types.SignatureStatusUnsigned}
+
+	var catAdmin syscall.Handle
+	if err := _CryptCATAdminAcquireContext(&catAdmin, nil, 0); err != nil {
+		return nil, err
+	}
+	defer _CryptCATAdminReleaseContext(catAdmin, 0)
+
+	file, err := syscall.Open(path, syscall.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(file)
+
+	hash := make([]byte, 64)
+	size := uint32(len(hash))
+	if err := _CryptCATAdminCalcHashFromFileHandle(file, &size, &hash[0], 0); err != nil {
+		// The file is not a PE image or another supported format.
+		return unsigned, nil
+	}
+	hash = hash[:size]
+
+	catInfo, err := _CryptCATAdminEnumCatalogFromHash(catAdmin, &hash[0], size, 0, nil)
+	if err != nil {
+		return unsigned, nil
+	}
+	defer _CryptCATAdminReleaseCatalogContext(catAdmin, catInfo, 0)
+
+	var catalog catalogInfo
+	catalog.Size = uint32(unsafe.Sizeof(catalog))
+	if err := _CryptCATCatalogInfoFromContext(catInfo, &catalog, 0); err != nil {
+		return nil, err
+	}
+
+	tag, err := syscall.UTF16PtrFromString(strings.ToUpper(hex.EncodeToString(hash)))
+	if err != nil {
+		return nil, err
+	}
+	member := wintrustCatalogInfo{
+		CatalogFilePath:    &catalog.CatalogFile[0],
+		MemberTag:          tag,
+		MemberFilePath:     pathW,
+		MemberFile:         file,
+		CalculatedFileHash: &hash[0],
+		CalculatedHashSize: size,
+		CatAdmin:           catAdmin,
+	}
+	member.Size = uint32(unsafe.Sizeof(member))
+	info, _ := verifyTrust(wtdChoiceCatalog, unsafe.Pointer(&member))
+	return info, nil
+}
+
+// verifyTrust calls WinVerifyTrust and extracts the signing certificate. It
+// returns false if the subject is not signed.
+func verifyTrust(choice uint32, subject unsafe.Pointer) (*types.SignatureInfo, bool) {
+	data := wintrustData{
+		UIChoice:         wtdUINone,
+		RevocationChecks: wtdRevokeNone,
+		UnionChoice:      choice,
+		Info:             subject,
+		StateAction:      wtdStateActionVerify,
+		ProvFlags:        wtdCacheOnlyURLRetrieval,
+	}
+	data.Size = uint32(unsafe.Sizeof(data))
+
+	rtn := uint32(_WinVerifyTrust(0, &wintrustActionGenericVerifyV2, &data))
+	defer func() {
+		data.StateAction = wtdStateActionClose
+		_WinVerifyTrust(0, &wintrustActionGenericVerifyV2, &data)
+	}()
+
+	switch rtn {
+	case 0:
+		info := &types.SignatureInfo{Status: types.SignatureStatusTrusted}
+		signingCertificate(data.StateData, info)
+		return info, true
+	case trustENoSignature, trustESubjectFormUnknown, trustEProviderUnknown:
+		return &types.SignatureInfo{Status: types.SignatureStatusUnsigned}, false
+	default:
+		info := &types.SignatureInfo{Status: types.SignatureStatusUntrusted}
+		signingCertificate(data.StateData, info)
+		return info, true
+	}
+}
+
+// signingCertificate fills in the details of the leaf certificate of the
+// primary signer.
+func signingCertificate(state syscall.Handle, info *types.SignatureInfo) {
+	provData := _WTHelperProvDataFromStateData(state)
+	if provData == 0 {
+		return
+	}
+	signer := _WTHelperGetProvSignerFromChain(provData, 0, false, 0)
+	if signer == 0 {
+		return
+	}
+	provCert := _WTHelperGetProvCertFromChain(signer, 0)
+	if provCert == nil || provCert.Cert == nil {
+		return
+	}
+
+	raw := make([]byte, provCert.Cert.Length)
+	copy(raw, (*[1 << 20]byte)(unsafe.Pointer(provCert.Cert.EncodedCert))[:len(raw):len(raw)])
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return
+	}
+
+	thumbprint := sha1.Sum(raw)
+	info.Signer = cert.Subject.CommonName
+	info.Issuer = cert.Issuer.CommonName
+	info.Thumbprint = hex.EncodeToString(thumbprint[:])
+	info.NotBefore = &cert.NotBefore
+	info.NotAfter = &cert.NotAfter
+}
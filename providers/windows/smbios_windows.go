@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// firmwareTableProviderRSMB is the 'RSMB' firmware table provider signature
+// that returns the raw SMBIOS table.
+const firmwareTableProviderRSMB = 'R'<<24 | 'S'<<16 | 'M'<<8 | 'B'
+
+// rawSMBIOSDataHeaderSize is the size of the RawSMBIOSData header (calling
+// method, major version, minor version, DMI revision, and table length) that
+// precedes the SMBIOS table.
+const rawSMBIOSDataHeaderSize = 8
+
+// Hardware returns the values of the SMBIOS table. The result is cached in
+// shared.StaticCache.
+func (h *host) Hardware() (*types.HardwareInfo, error) {
+	v, err := h.cache.Get("hardware", func() (interface{}, error) {
+		return readHardware()
+	})
+	if err != nil {
+		return nil, err
+	}
+	hw := *v.(*types.HardwareInfo)
+	return &hw, nil
+}
+
+func readHardware() (*types.HardwareInfo, error) {
+	size, err := _GetSystemFirmwareTable(firmwareTableProviderRSMB, 0, nil, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSystemFirmwareTable failed")
+	}
+
+	buf := make([]byte, size)
+	n, err := _GetSystemFirmwareTable(firmwareTableProviderRSMB, 0, &buf[0], size)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSystemFirmwareTable failed")
+	}
+	if n > size || n < rawSMBIOSDataHeaderSize {
+		return nil, errors.Errorf("unexpected SMBIOS data size %d", n)
+	}
+	buf = buf[:n]
+
+	major, minor := int(buf[1]), int(buf[2])
+	table := buf[rawSMBIOSDataHeaderSize:]
+	if length := binary.LittleEndian.Uint32(buf[4:]); int(length) < len(table) {
+		table = table[:length]
+	}
+	return shared.ParseSMBIOS(table, major, minor)
+}
@@ -0,0 +1,237 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// tcpStates maps MIB_TCP_STATE values to their names.
+var tcpStates = map[uint32]string{
+	1:  types.TCPStateClose,
+	2:  types.TCPStateListen,
+	3:  types.TCPStateSynSent,
+	4:  types.TCPStateSynRecv,
+	5:  types.TCPStateEstablished,
+	6:  types.TCPStateFinWait1,
+	7:  types.TCPStateFinWait2,
+	8:  types.TCPStateCloseWait,
+	9:  types.TCPStateClosing,
+	10: types.TCPStateLastAck,
+	11: types.TCPStateTimeWait,
+	12: types.TCPStateDeleteTCB,
+}
+
+// mibTCPRowOwnerPID is Go's counterpart of MIB_TCPROW_OWNER_PID.
+type mibTCPRowOwnerPID struct {
+	State      uint32
+	LocalAddr  [4]byte
+	LocalPort  uint32
+	RemoteAddr [4]byte
+	RemotePort uint32
+	OwningPID  uint32
+}
+
+// mibTCP6RowOwnerPID is Go's counterpart of MIB_TCP6ROW_OWNER_PID.
+type mibTCP6RowOwnerPID struct {
+	LocalAddr     [16]byte
+	LocalScopeID  uint32
+	LocalPort     uint32
+	RemoteAddr    [16]byte
+	RemoteScopeID uint32
+	RemotePort    uint32
+	State         uint32
+	OwningPID     uint32
+}
+
+// mibUDPRowOwnerPID is Go's counterpart of MIB_UDPROW_OWNER_PID.
+type mibUDPRowOwnerPID struct {
+	LocalAddr [4]byte
+	LocalPort uint32
+	OwningPID uint32
+}
+
+// mibUDP6RowOwnerPID is Go's counterpart of MIB_UDP6ROW_OWNER_PID.
+type mibUDP6RowOwnerPID struct {
+	LocalAddr    [16]byte
+	LocalScopeID uint32
+	LocalPort    uint32
+	OwningPID    uint32
+}
+
+// ownedConnection is a NetworkConnection with the PID of the owning process.
+type ownedConnection struct {
+	types.NetworkConnection
+	pid int
+}
+
+// Connections returns the TCP and UDP sockets owned by the process.
+func (p *process) Connections() ([]types.NetworkConnection, error) {
+	all, err := connections()
+	if err != nil {
+		return nil, err
+	}
+
+	var conns []types.NetworkConnection
+	for _, c := range all {
+		if c.pid == p.pid {
+			c.NetworkConnection.PID = c.pid
+			conns = append(conns, c.NetworkConnection)
+		}
+	}
+	return conns, nil
+}
+
+// connections returns all TCP and UDP sockets on the host.
+func connections() ([]ownedConnection, error) {
+	var conns []ownedConnection
+
+	buf, err := GetExtendedTcpTable(syscall.AF_INET, tcpTableOwnerPIDAll)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetExtendedTcpTable failed for AF_INET")
+	}
+	err = forEachRow(buf, unsafe.Sizeof(mibTCPRowOwnerPID{}), func(ptr unsafe.Pointer) {
+		row := (*mibTCPRowOwnerPID)(ptr)
+		conns = append(conns, ownedConnection{
+			NetworkConnection: types.NetworkConnection{
+				Family:     types.FamilyIPv4,
+				Type:       types.ProtocolTCP,
+				LocalIP:    copyIP(row.LocalAddr[:]),
+				LocalPort:  port(row.LocalPort),
+				RemoteIP:   remoteIP(row.RemoteAddr[:]),
+				RemotePort: port(row.RemotePort),
+				State:      tcpStates[row.State],
+			},
+			pid: int(row.OwningPID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err = GetExtendedTcpTable(syscall.AF_INET6, tcpTableOwnerPIDAll)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetExtendedTcpTable failed for AF_INET6")
+	}
+	err = forEachRow(buf, unsafe.Sizeof(mibTCP6RowOwnerPID{}), func(ptr unsafe.Pointer) {
+		row := (*mibTCP6RowOwnerPID)(ptr)
+		conns = append(conns, ownedConnection{
+			NetworkConnection: types.NetworkConnection{
+				Family:     types.FamilyIPv6,
+				Type:       types.ProtocolTCP,
+				LocalIP:    copyIP(row.LocalAddr[:]),
+				LocalPort:  port(row.LocalPort),
+				RemoteIP:   remoteIP(row.RemoteAddr[:]),
+				RemotePort: port(row.RemotePort),
+				State:      tcpStates[row.State],
+			},
+			pid: int(row.OwningPID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err = GetExtendedUdpTable(syscall.AF_INET, udpTableOwnerPID)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetExtendedUdpTable failed for AF_INET")
+	}
+	err = forEachRow(buf, unsafe.Sizeof(mibUDPRowOwnerPID{}), func(ptr unsafe.Pointer) {
+		row := (*mibUDPRowOwnerPID)(ptr)
+		conns = append(conns, ownedConnection{
+			NetworkConnection: types.NetworkConnection{
+				Family:    types.FamilyIPv4,
+				Type:      types.ProtocolUDP,
+				LocalIP:   copyIP(row.LocalAddr[:]),
+				LocalPort: port(row.LocalPort),
+			},
+			pid: int(row.OwningPID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err = GetExtendedUdpTable(syscall.AF_INET6, udpTableOwnerPID)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetExtendedUdpTable failed for AF_INET6")
+	}
+	err = forEachRow(buf, unsafe.Sizeof(mibUDP6RowOwnerPID{}), func(ptr unsafe.Pointer) {
+		row := (*mibUDP6RowOwnerPID)(ptr)
+		conns = append(conns, ownedConnection{
+			NetworkConnection: types.NetworkConnection{
+				Family:    types.FamilyIPv6,
+				Type:      types.ProtocolUDP,
+				LocalIP:   copyIP(row.LocalAddr[:]),
+				LocalPort: port(row.LocalPort),
+			},
+			pid: int(row.OwningPID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conns, nil
+}
+
+// forEachRow invokes fn with a pointer to each row of a MIB_*TABLE_OWNER_PID
+// table. The table begins with a DWORD row count followed by the rows.
+func forEachRow(buf []byte, rowSize uintptr, fn func(unsafe.Pointer)) error {
+	if len(buf) < 4 {
+		return errors.New("table: short buffer")
+	}
+
+	// The rows are aligned to 4 bytes which is also the size of the count.
+	count := uintptr(binary.LittleEndian.Uint32(buf))
+	if 4+count*rowSize > uintptr(len(buf)) {
+		return errors.Errorf("table: short buffer for %d rows", count)
+	}
+
+	for i := uintptr(0); i < count; i++ {
+		fn(unsafe.Pointer(&buf[4+i*rowSize]))
+	}
+	return nil
+}
+
+// port converts a port number stored in network byte order in the lower 16
+// bits of a DWORD.
+func port(p uint32) int {
+	return int(p&0xFF)<<8 | int(p>>8&0xFF)
+}
+
+func copyIP(b []byte) net.IP {
+	ip := make(net.IP, len(b))
+	copy(ip, b)
+	return ip
+}
+
+func remoteIP(b []byte) net.IP {
+	ip := copyIP(b)
+	if ip.IsUnspecified() {
+		return nil
+	}
+	return ip
+}
@@ -0,0 +1,340 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	windows "github.com/elastic/go-windows"
+)
+
+//go:generate go run $GOROOT/src/syscall/mksyscall_windows.go -systemdll=false -output zsyscall_windows.go syscall_windows.go
+
+// Syscalls
+// Warning: NtQuerySystemInformation and NtQueryObject are unsupported APIs
+//          that can change in future versions of Windows.
+//sys   _NtQuerySystemInformation(infoClass uint32, info uintptr, infoLen uint32, returnLen *uint32) (ntStatus uint32) = ntdll.NtQuerySystemInformation
+//sys   _NtQueryObject(handle syscall.Handle, infoClass uint32, info uintptr, infoLen uint32, returnLen *uint32) (ntStatus uint32) = ntdll.NtQueryObject
+//sys   _GetFinalPathNameByHandle(handle syscall.Handle, filePath *uint16, filePathLen uint32, flags uint32) (n uint32, err error) = kernel32.GetFinalPathNameByHandleW
+//sys   _GetDiskFreeSpaceEx(directoryName *uint16, freeBytesAvailable *uint64, totalNumberOfBytes *uint64, totalNumberOfFreeBytes *uint64) (err error) = kernel32.GetDiskFreeSpaceExW
+//sys   _GetSystemFirmwareTable(provider uint32, tableID uint32, buf *byte, size uint32) (n uint32, err error) = kernel32.GetSystemFirmwareTable
+//sys   _GetLogicalProcessorInformationEx(relationship uint32, buffer *byte, returnedLength *uint32) (err error) = kernel32.GetLogicalProcessorInformationEx
+//sys   _CallNtPowerInformation(level int32, inputBuffer *byte, inputBufferLen uint32, outputBuffer *byte, outputBufferLen uint32) (ntStatus uint32) = powrprof.CallNtPowerInformation
+//sys   _GetPerformanceInfo(info *performanceInformation, cb uint32) (err error) = psapi.GetPerformanceInfo
+//sys   _GetProcessIoCounters(process syscall.Handle, counters *ioCounters) (err error) = kernel32.GetProcessIoCounters
+//sys   _OpenThread(desiredAccess uint32, inheritHandle bool, threadID uint32) (handle syscall.Handle, err error) = kernel32.OpenThread
+//sys   _EnumProcessModulesEx(process syscall.Handle, modules *syscall.Handle, cb uint32, needed *uint32, filterFlag uint32) (err error) = psapi.EnumProcessModulesEx
+//sys   _GetModuleFileNameEx(process syscall.Handle, module syscall.Handle, filename *uint16, size uint32) (n uint32, err error) = psapi.GetModuleFileNameExW
+//sys   _GetModuleInformation(process syscall.Handle, module syscall.Handle, info *moduleInfo, cb uint32) (err error) = psapi.GetModuleInformation
+//sys   _VirtualQueryEx(process syscall.Handle, address uintptr, buffer *memoryBasicInformation, length uintptr) (n uintptr, err error) = kernel32.VirtualQueryEx
+//sys   _GetMappedFileName(process syscall.Handle, address uintptr, filename *uint16, size uint32) (n uint32, err error) = psapi.GetMappedFileNameW
+//sys   _NetUserEnum(serverName *uint16, level uint32, filter uint32, buf **byte, prefMaxLen uint32, entriesRead *uint32, totalEntries *uint32, resumeHandle *uint32) (neterr error) = netapi32.NetUserEnum
+//sys   _NetLocalGroupEnum(serverName *uint16, level uint32, buf **byte, prefMaxLen uint32, entriesRead *uint32, totalEntries *uint32, resumeHandle *uint32) (neterr error) = netapi32.NetLocalGroupEnum
+//sys   _NetLocalGroupGetMembers(serverName *uint16, groupName *uint16, level uint32, buf **byte, prefMaxLen uint32, entriesRead *uint32, totalEntries *uint32, resumeHandle *uint32) (neterr error) = netapi32.NetLocalGroupGetMembers
+//sys   _WTSEnumerateSessions(server syscall.Handle, reserved uint32, version uint32, sessions **wtsSessionInfoW, count *uint32) (err error) = wtsapi32.WTSEnumerateSessionsW
+//sys   _WTSQuerySessionInformation(server syscall.Handle, sessionID uint32, infoClass uint32, buf **uint16, bytesReturned *uint32) (err error) = wtsapi32.WTSQuerySessionInformationW
+//sys   _WTSFreeMemory(memory uintptr) = wtsapi32.WTSFreeMemory
+//sys   _EnumDeviceDrivers(imageBase *uintptr, cb uint32, needed *uint32) (err error) = psapi.EnumDeviceDrivers
+//sys   _GetDeviceDriverBaseName(imageBase uintptr, baseName *uint16, size uint32) (n uint32, err error) = psapi.GetDeviceDriverBaseNameW
+//sys   _GetDeviceDriverFileName(imageBase uintptr, filename *uint16, size uint32) (n uint32, err error) = psapi.GetDeviceDriverFileNameW
+//sys   _WinVerifyTrust(hwnd syscall.Handle, action *syscall.GUID, data *wintrustData) (rtn int32) = wintrust.WinVerifyTrust
+//sys   _WTHelperProvDataFromStateData(stateData syscall.Handle) (provData uintptr) = wintrust.WTHelperProvDataFromStateData
+//sys   _WTHelperGetProvSignerFromChain(provData uintptr, signerIdx uint32, counterSigner bool, counterSignerIdx uint32) (signer uintptr) = wintrust.WTHelperGetProvSignerFromChain
+//sys   _WTHelperGetProvCertFromChain(signer uintptr, certIdx uint32) (cert *cryptProviderCert) = wintrust.WTHelperGetProvCertFromChain
+//sys   _CryptCATAdminAcquireContext(catAdmin *syscall.Handle, subsystem *syscall.GUID, flags uint32) (err error) = wintrust.CryptCATAdminAcquireContext
+//sys   _CryptCATAdminReleaseContext(catAdmin syscall.Handle, flags uint32) (err error) = wintrust.CryptCATAdminReleaseContext
+//sys   _CryptCATAdminCalcHashFromFileHandle(file syscall.Handle, hashSize *uint32, hash *byte, flags uint32) (err error) = wintrust.CryptCATAdminCalcHashFromFileHandle
+//sys   _CryptCATAdminEnumCatalogFromHash(catAdmin syscall.Handle, hash *byte, hashSize uint32, flags uint32, prevCatInfo *syscall.Handle) (catInfo syscall.Handle, err error) = wintrust.CryptCATAdminEnumCatalogFromHash
+//sys   _CryptCATAdminReleaseCatalogContext(catAdmin syscall.Handle, catInfo syscall.Handle, flags uint32) (err error) = wintrust.CryptCATAdminReleaseCatalogContext
+//sys   _CryptCATCatalogInfoFromContext(catInfo syscall.Handle, info *catalogInfo, flags uint32) (err error) = wintrust.CryptCATCatalogInfoFromContext
+//sys   _IsProcessorFeaturePresent(feature uint32) (present bool) = kernel32.IsProcessorFeaturePresent
+//sys   _GetIfEntry2(row *mibIfRow2) (errcode error) = iphlpapi.GetIfEntry2
+//sys   _GetExtendedTcpTable(table *byte, size *uint32, order bool, af uint32, tableClass uint32, reserved uint32) (errcode error) = iphlpapi.GetExtendedTcpTable
+//sys   _GetExtendedUdpTable(table *byte, size *uint32, order bool, af uint32, tableClass uint32, reserved uint32) (errcode error) = iphlpapi.GetExtendedUdpTable
+//sys   _PdhOpenQuery(dataSource *uint16, userData uintptr, query *syscall.Handle) (status uint32) = pdh.PdhOpenQueryW
+//sys   _PdhAddEnglishCounter(query syscall.Handle, counterPath *uint16, userData uintptr, counter *syscall.Handle) (status uint32) = pdh.PdhAddEnglishCounterW
+//sys   _PdhCollectQueryData(query syscall.Handle) (status uint32) = pdh.PdhCollectQueryData
+//sys   _PdhGetFormattedCounterArray(counter syscall.Handle, format uint32, bufferSize *uint32, itemCount *uint32, items *byte) (status uint32) = pdh.PdhGetFormattedCounterArrayW
+//sys   _PdhCloseQuery(query syscall.Handle) (status uint32) = pdh.PdhCloseQuery
+//sys   _GetSystemPowerStatus(status *systemPowerStatus) (err error) = kernel32.GetSystemPowerStatus
+//sys   _SetupDiGetClassDevs(classGUID *syscall.GUID, enumerator *uint16, hwndParent uintptr, flags uint32) (handle syscall.Handle, err error) [failretval==syscall.InvalidHandle] = setupapi.SetupDiGetClassDevsW
+//sys   _SetupDiEnumDeviceInfo(devInfoSet syscall.Handle, index uint32, data *spDevinfoData) (err error) = setupapi.SetupDiEnumDeviceInfo
+//sys   _SetupDiGetDeviceInstanceId(devInfoSet syscall.Handle, data *spDevinfoData, id *uint16, idSize uint32, requiredSize *uint32) (err error) = setupapi.SetupDiGetDeviceInstanceIdW
+//sys   _SetupDiGetDeviceRegistryProperty(devInfoSet syscall.Handle, data *spDevinfoData, property uint32, regDataType *uint32, buf *byte, bufSize uint32, requiredSize *uint32) (err error) = setupapi.SetupDiGetDeviceRegistryPropertyW
+//sys   _SetupDiDestroyDeviceInfoList(devInfoSet syscall.Handle) (err error) = setupapi.SetupDiDestroyDeviceInfoList
+//sys   _CM_Get_Parent(parent *uint32, devInst uint32, flags uint32) (cr uint32) = cfgmgr32.CM_Get_Parent
+//sys   _CM_Get_Device_ID(devInst uint32, buf *uint16, bufLen uint32, flags uint32) (cr uint32) = cfgmgr32.CM_Get_Device_IDW
+//sys   _GetGuiResources(process syscall.Handle, flags uint32) (n uint32) = user32.GetGuiResources
+//sys   _IsProcessInJob(process syscall.Handle, job syscall.Handle, result *int32) (err error) = kernel32.IsProcessInJob
+//sys   _QueryInformationJobObject(job syscall.Handle, infoClass uint32, info uintptr, infoLen uint32, returnLen *uint32) (err error) = kernel32.QueryInformationJobObject
+//sys   _GetPriorityClass(process syscall.Handle) (class uint32, err error) = kernel32.GetPriorityClass
+//sys   _GetNumaHighestNodeNumber(highest *uint32) (err error) = kernel32.GetNumaHighestNodeNumber
+//sys   _GetNumaAvailableMemoryNodeEx(node uint16, available *uint64) (err error) = kernel32.GetNumaAvailableMemoryNodeEx
+//sys   _GetNumaNodeProcessorMaskEx(node uint16, affinity *groupAffinity) (err error) = kernel32.GetNumaNodeProcessorMaskEx
+//sys   _GetLargePageMinimum() (size uintptr) = kernel32.GetLargePageMinimum
+//sys   _GetIpForwardTable2(family uint16, table **byte) (errcode error) = iphlpapi.GetIpForwardTable2
+//sys   _GetIpNetTable2(family uint16, table **byte) (errcode error) = iphlpapi.GetIpNetTable2
+//sys   _FreeMibTable(memory *byte) = iphlpapi.FreeMibTable
+//sys   _WinHttpGetDefaultProxyConfiguration(info *winHTTPProxyInfo) (err error) = winhttp.WinHttpGetDefaultProxyConfiguration
+//sys   _WinHttpGetIEProxyConfigForCurrentUser(config *winHTTPCurrentUserIEProxyConfig) (err error) = winhttp.WinHttpGetIEProxyConfigForCurrentUser
+//sys   _GlobalFree(mem *uint16) = kernel32.GlobalFree
+//sys   _Tbsi_GetDeviceInfo(size uint32, info *tpmDeviceInfo) (errcode error) = tbs.Tbsi_GetDeviceInfo
+//sys   _Tbsi_Context_Create(params *tbsContextParams2, context *uintptr) (errcode error) = tbs.Tbsi_Context_Create
+//sys   _Tbsip_Submit_Command(context uintptr, locality uint32, priority uint32, command *byte, commandLen uint32, result *byte, resultLen *uint32) (errcode error) = tbs.Tbsip_Submit_Command
+//sys   _Tbsip_Context_Close(context uintptr) (errcode error) = tbs.Tbsip_Context_Close
+//sys   _CoInitializeEx(reserved uintptr, coInit uint32) (hr int32) = ole32.CoInitializeEx
+//sys   _CoUninitialize() = ole32.CoUninitialize
+//sys   _PSGetPropertyKeyFromName(name *uint16, key *propertyKey) (hr int32) = propsys.PSGetPropertyKeyFromName
+//sys   _SHGetPropertyStoreFromParsingName(path *uint16, bindCtx uintptr, flags uint32, iid *syscall.GUID, store **iPropertyStore) (hr int32) = shell32.SHGetPropertyStoreFromParsingName
+
+const (
+	// statusInfoLengthMismatch (STATUS_INFO_LENGTH_MISMATCH) is returned when
+	// the buffer passed to an Nt* query function is too small.
+	statusInfoLengthMismatch = 0xC0000004
+
+	// statusBufferTooSmall (STATUS_BUFFER_TOO_SMALL) is returned by some
+	// information classes instead of statusInfoLengthMismatch.
+	statusBufferTooSmall = 0xC0000023
+
+	// SYSTEM_INFORMATION_CLASS values.
+	systemPerformanceInformation          = 2
+	systemProcessInformation              = 5
+	systemProcessorPerformanceInformation = 8
+	systemExtendedHandleInformation       = 64
+	systemKernelVaShadowInformation       = 196
+	systemSpeculationControlInformation   = 201
+
+	// POWER_INFORMATION_LEVEL values.
+	processorInformation = 11
+
+	// PROCESSINFOCLASS values not defined by go-windows.
+	processCommandLineInformation = 60
+
+	// OBJECT_INFORMATION_CLASS values.
+	objectNameInformation = 1
+	objectTypeInformation = 2
+
+	// TCP_TABLE_CLASS and UDP_TABLE_CLASS values.
+	tcpTableOwnerPIDAll = 5
+	udpTableOwnerPID    = 1
+
+	// processDupHandle (PROCESS_DUP_HANDLE) is required to duplicate a handle
+	// owned by another process.
+	processDupHandle = 0x0040
+)
+
+// systemHandleTableEntryInfoEx is Go's counterpart of the
+// SYSTEM_HANDLE_TABLE_ENTRY_INFO_EX struct.
+type systemHandleTableEntryInfoEx struct {
+	Object                uintptr
+	UniqueProcessID       uintptr
+	HandleValue           uintptr
+	GrantedAccess         uint32
+	CreatorBackTraceIndex uint16
+	ObjectTypeIndex       uint16
+	HandleAttributes      uint32
+	Reserved              uint32
+}
+
+// ioCounters is the IO_COUNTERS structure.
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// moduleInfo is the MODULEINFO structure.
+type moduleInfo struct {
+	BaseOfDll   uintptr
+	SizeOfImage uint32
+	EntryPoint  uintptr
+}
+
+// memoryBasicInformation is the MEMORY_BASIC_INFORMATION structure.
+type memoryBasicInformation struct {
+	BaseAddress       uintptr
+	AllocationBase    uintptr
+	AllocationProtect uint32
+	RegionSize        uintptr
+	State             uint32
+	Protect           uint32
+	Type              uint32
+}
+
+// systemProcessorPerformanceInfo is Go's counterpart of the
+// SYSTEM_PROCESSOR_PERFORMANCE_INFORMATION struct.
+type systemProcessorPerformanceInfo struct {
+	IdleTime       int64
+	KernelTime     int64
+	UserTime       int64
+	DpcTime        int64
+	InterruptTime  int64
+	InterruptCount uint32
+	_              uint32
+}
+
+// systemPerformanceInfo is the leading part of the undocumented
+// SYSTEM_PERFORMANCE_INFORMATION struct up to SystemCalls. It is the source of
+// the Memory and System performance counters.
+type systemPerformanceInfo struct {
+	IdleProcessTime       int64
+	IoReadTransferCount   int64
+	IoWriteTransferCount  int64
+	IoOtherTransferCount  int64
+	_                     [7]uint32 // IoReadOperationCount - PeakCommitment
+	PageFaultCount        uint32
+	_                     [4]uint32 // CopyOnWriteCount - DemandZeroCount
+	PageReadCount         uint32
+	PageReadIoCount       uint32
+	_                     [2]uint32 // CacheReadCount, CacheIoCount
+	DirtyPagesWriteCount  uint32
+	_                     uint32 // DirtyWriteIoCount
+	MappedPagesWriteCount uint32
+	_                     [47]uint32 // MappedWriteIoCount - CcDataPages
+	ContextSwitches       uint32
+	_                     [2]uint32 // FirstLevelTbFills, SecondLevelTbFills
+	SystemCalls           uint32
+}
+
+// NtQuerySystemInformation is a wrapper for ntdll.NtQuerySystemInformation.
+// It grows the buffer until it is large enough to hold the requested data.
+// Returns an error of type windows.NTStatus.
+func NtQuerySystemInformation(infoClass uint32) ([]byte, error) {
+	buf := make([]byte, 64*1024)
+	for {
+		var returnedLen uint32
+		status := _NtQuerySystemInformation(infoClass, uintptr(unsafe.Pointer(&buf[0])), uint32(len(buf)), &returnedLen)
+		switch status {
+		case 0:
+			return buf[:returnedLen], nil
+		case statusInfoLengthMismatch:
+			// The set of handles can grow between calls so add some slack.
+			size := 2 * len(buf)
+			if int(returnedLen) > size {
+				size = int(returnedLen) + 64*1024
+			}
+			buf = make([]byte, size)
+		default:
+			return nil, windows.NTStatus(status)
+		}
+	}
+}
+
+// NtQueryObject is a wrapper for ntdll.NtQueryObject. It returns the raw
+// information buffer. Returns an error of type windows.NTStatus.
+func NtQueryObject(handle syscall.Handle, infoClass uint32) ([]byte, error) {
+	buf := make([]byte, 1024)
+	for {
+		var returnedLen uint32
+		status := _NtQueryObject(handle, infoClass, uintptr(unsafe.Pointer(&buf[0])), uint32(len(buf)), &returnedLen)
+		switch status {
+		case 0:
+			return buf, nil
+		case statusInfoLengthMismatch:
+			if int(returnedLen) <= len(buf) {
+				return nil, windows.NTStatus(status)
+			}
+			buf = make([]byte, returnedLen)
+		default:
+			return nil, windows.NTStatus(status)
+		}
+	}
+}
+
+// GetFinalPathNameByHandle returns the final path for the given file handle.
+func GetFinalPathNameByHandle(handle syscall.Handle) (string, error) {
+	buf := make([]uint16, syscall.MAX_PATH)
+	for {
+		n, err := _GetFinalPathNameByHandle(handle, &buf[0], uint32(len(buf)), 0)
+		if err != nil {
+			return "", err
+		}
+		if int(n) < len(buf) {
+			return syscall.UTF16ToString(buf[:n]), nil
+		}
+		// n is the required size including the null terminator.
+		buf = make([]uint16, n)
+	}
+}
+
+// GetExtendedTcpTable is a wrapper for iphlpapi.GetExtendedTcpTable. It
+// returns the raw table for the given address family and table class.
+func GetExtendedTcpTable(af, tableClass uint32) ([]byte, error) {
+	return getExtendedTable(_GetExtendedTcpTable, af, tableClass)
+}
+
+// GetExtendedUdpTable is a wrapper for iphlpapi.GetExtendedUdpTable. It
+// returns the raw table for the given address family and table class.
+func GetExtendedUdpTable(af, tableClass uint32) ([]byte, error) {
+	return getExtendedTable(_GetExtendedUdpTable, af, tableClass)
+}
+
+type extendedTableFunc func(table *byte, size *uint32, order bool, af uint32, tableClass uint32, reserved uint32) error
+
+func getExtendedTable(fn extendedTableFunc, af, tableClass uint32) ([]byte, error) {
+	var size uint32
+	buf := make([]byte, 4)
+	for {
+		size = uint32(len(buf))
+		err := fn(&buf[0], &size, false, af, tableClass, 0)
+		if err == nil {
+			return buf[:size], nil
+		}
+		if err != syscall.ERROR_INSUFFICIENT_BUFFER || size <= uint32(len(buf)) {
+			return nil, err
+		}
+		// Add some slack because the table can grow between calls.
+		buf = make([]byte, size+size/4)
+	}
+}
+
+// unicodeStringAt decodes the UNICODE_STRING located at the start of buf.
+// The string data must also be contained within buf (as is the case for data
+// returned by NtQueryObject).
+func unicodeStringAt(buf []byte) (string, error) {
+	data, err := unicodeStringBytesAt(buf)
+	if err != nil || len(data) == 0 {
+		return "", err
+	}
+
+	s, _, err := windows.UTF16BytesToString(data)
+	return s, err
+}
+
+// unicodeStringBytesAt returns the raw UTF-16 data of the UNICODE_STRING
+// located at the start of buf.
+func unicodeStringBytesAt(buf []byte) ([]byte, error) {
+	var us windows.UnicodeString
+	if len(buf) < int(unsafe.Sizeof(us)) {
+		return nil, syscall.EINVAL
+	}
+	us = *(*windows.UnicodeString)(unsafe.Pointer(&buf[0]))
+	if us.Size == 0 {
+		return nil, nil
+	}
+
+	offset := us.Buffer - uintptr(unsafe.Pointer(&buf[0]))
+	if offset >= uintptr(len(buf)) || offset+uintptr(us.Size) > uintptr(len(buf)) {
+		return nil, syscall.EINVAL
+	}
+	return buf[offset : offset+uintptr(us.Size)], nil
+}
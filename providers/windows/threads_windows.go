@@ -0,0 +1,180 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	syswin "golang.org/x/sys/windows"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// GetThreadDescription was added in Windows 10 version 1607 so it is
+// resolved at runtime rather than through a //sys declaration.
+var procGetThreadDescription = modkernel32.NewProc("GetThreadDescription")
+
+const threadQueryLimitedInformation = 0x0800
+
+// KTHREAD_STATE and KWAIT_REASON values.
+const (
+	threadStateInitialized   = 0
+	threadStateReady         = 1
+	threadStateRunning       = 2
+	threadStateStandby       = 3
+	threadStateTerminated    = 4
+	threadStateWaiting       = 5
+	threadStateTransition    = 6
+	threadStateDeferredReady = 7
+
+	waitReasonSuspended = 5
+)
+
+// Threads returns the threads of the process from SystemProcessInformation.
+func (p *process) Threads() ([]types.ThreadInfo, error) {
+	buf, err := NtQuerySystemInformation(systemProcessInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "NtQuerySystemInformation failed")
+	}
+
+	_, threads, err := findSystemProcessInfo(buf, p.pid)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]types.ThreadInfo, 0, len(threads))
+	for _, thread := range threads {
+		tid := int(thread.ClientID[1])
+		infos = append(infos, types.ThreadInfo{
+			TID:   tid,
+			Name:  threadDescription(tid),
+			State: threadState(thread.ThreadState, thread.WaitReason),
+			CPUTime: types.CPUTimes{
+				User:   time.Duration(thread.UserTime) * 100,
+				System: time.Duration(thread.KernelTime) * 100,
+			},
+		})
+	}
+	return infos, nil
+}
+
+// threadDescription returns the name of a thread as set by
+// SetThreadDescription. It returns an empty string when the name cannot be
+// read.
+func threadDescription(tid int) string {
+	if procGetThreadDescription.Find() != nil {
+		return ""
+	}
+
+	handle, err := _OpenThread(threadQueryLimitedInformation, false, uint32(tid))
+	if err != nil {
+		return ""
+	}
+	defer syscall.CloseHandle(handle)
+
+	var desc *uint16
+	hr, _, _ := procGetThreadDescription.Call(uintptr(handle), uintptr(unsafe.Pointer(&desc)))
+	if int32(hr) < 0 || desc == nil {
+		return ""
+	}
+	defer syswin.LocalFree(syswin.Handle(unsafe.Pointer(desc)))
+
+	// The description is NUL terminated and limited to 32767 characters.
+	return syscall.UTF16ToString((*[1 << 15]uint16)(unsafe.Pointer(desc))[:])
+}
+
+// threadState maps a KTHREAD_STATE to one of the ProcessState constants.
+func threadState(state, waitReason uint32) string {
+	switch state {
+	case threadStateReady, threadStateRunning, threadStateStandby, threadStateDeferredReady:
+		return types.ProcessStateRunning
+	case threadStateWaiting:
+		if waitReason == waitReasonSuspended {
+			return types.ProcessStateStopped
+		}
+		return types.ProcessStateSleeping
+	case threadStateTransition:
+		return types.ProcessStateWaiting
+	case threadStateTerminated:
+		return types.ProcessStateZombie
+	default:
+		return types.ProcessStateUnknown
+	}
+}
+
+// processStates returns the state of each process in a
+// SystemProcessInformation snapshot. It returns nil if the snapshot cannot be
+// taken.
+func processStates() map[int]string {
+	buf, err := NtQuerySystemInformation(systemProcessInformation)
+	if err != nil {
+		return nil
+	}
+
+	states := map[int]string{}
+	walkSystemProcessInformation(buf, func(info *systemProcessInfo, threads []*systemThreadInfo) bool {
+		states[int(info.UniqueProcessID)] = processState(threads)
+		return true
+	})
+	return states
+}
+
+// state returns the state of the process in a new SystemProcessInformation
+// snapshot. It returns an empty string if the state cannot be read.
+func (p *process) state() string {
+	buf, err := NtQuerySystemInformation(systemProcessInformation)
+	if err != nil {
+		return ""
+	}
+
+	_, threads, err := findSystemProcessInfo(buf, p.pid)
+	if err != nil {
+		return ""
+	}
+	return processState(threads)
+}
+
+// processState synthesizes the state of a process from the states of its
+// threads. A process is running if any thread is runnable, stopped if all
+// of its threads are suspended, and a zombie if it exited and only its
+// process object remains.
+func processState(threads []*systemThreadInfo) string {
+	if len(threads) == 0 {
+		return types.ProcessStateZombie
+	}
+
+	counts := map[string]int{}
+	for _, thread := range threads {
+		counts[threadState(thread.ThreadState, thread.WaitReason)]++
+	}
+	for _, state := range []string{types.ProcessStateRunning, types.ProcessStateWaiting, types.ProcessStateSleeping} {
+		if counts[state] > 0 {
+			return state
+		}
+	}
+	switch len(threads) {
+	case counts[types.ProcessStateStopped]:
+		return types.ProcessStateStopped
+	case counts[types.ProcessStateZombie]:
+		return types.ProcessStateZombie
+	}
+	return types.ProcessStateUnknown
+}
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestThreadState(t *testing.T) {
+	assert.Equal(t, types.ProcessStateRunning, threadState(threadStateRunning, 0))
+	assert.Equal(t, types.ProcessStateRunning, threadState(threadStateReady, 0))
+	assert.Equal(t, types.ProcessStateSleeping, threadState(threadStateWaiting, 6))
+	assert.Equal(t, types.ProcessStateStopped, threadState(threadStateWaiting, waitReasonSuspended))
+	assert.Equal(t, types.ProcessStateUnknown, threadState(threadStateInitialized, 0))
+}
+
+func TestProcessState(t *testing.T) {
+	thread := func(state, waitReason uint32) *systemThreadInfo {
+		return &systemThreadInfo{ThreadState: state, WaitReason: waitReason}
+	}
+
+	assert.Equal(t, types.ProcessStateZombie, processState(nil))
+	assert.Equal(t, types.ProcessStateRunning, processState([]*systemThreadInfo{
+		thread(threadStateWaiting, 6), thread(threadStateRunning, 0),
+	}))
+	assert.Equal(t, types.ProcessStateSleeping, processState([]*systemThreadInfo{
+		thread(threadStateWaiting, 6), thread(threadStateWaiting, waitReasonSuspended),
+	}))
+	assert.Equal(t, types.ProcessStateStopped, processState([]*systemThreadInfo{
+		thread(threadStateWaiting, waitReasonSuspended), thread(threadStateWaiting, waitReasonSuspended),
+	}))
+}
+
+func TestProcessStateLazy(t *testing.T) {
+	self, err := windowsSystem{}.Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := self.(*process)
+	assert.False(t, p.hasState, "opening a process must not take a snapshot")
+
+	info, err := p.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.ProcessStateRunning, info.State)
+}
@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/providers/shared"
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// TPM Base Services constants from tbs.h.
+const (
+	tbsContextVersionTwo   = 2
+	tbsIncludeTPM20        = 1 << 2
+	tbsCommandLocalityZero = 0
+	tbsCommandPriorityNorm = 200
+
+	tpmVersion12 = 1 // TPM_VERSION_12
+	tpmVersion20 = 2 // TPM_VERSION_20
+
+	tbsErrTPMNotFound = syscall.Errno(0x8028400F) // TBS_E_TPM_NOT_FOUND
+)
+
+// tpmDeviceInfo is Go's counterpart of the TPM_DEVICE_INFO struct.
+type tpmDeviceInfo struct {
+	StructVersion uint32
+	TPMVersion    uint32
+	TPMInterface  uint32
+	TPMImpRev     uint32
+}
+
+// tbsContextParams2 is Go's counterpart of the TBS_CONTEXT_PARAMS2 struct.
+type tbsContextParams2 struct {
+	Version uint32
+	Flags   uint32
+}
+
+// TPMInfo reports the TPM of the host using TPM Base Services. The
+// manufacturer and state are only read from a TPM 2.0.
+func (h *host) TPMInfo() (*types.TPMInfo, error) {
+	var dev tpmDeviceInfo
+	if err := _Tbsi_GetDeviceInfo(uint32(unsafe.Sizeof(dev)), &dev); err != nil {
+		if err == tbsErrTPMNotFound {
+			return &types.TPMInfo{}, nil
+		}
+		return nil, errors.Wrap(err, "Tbsi_GetDeviceInfo failed")
+	}
+
+	info := &types.TPMInfo{Present: true}
+	switch dev.TPMVersion {
+	case tpmVersion12:
+		info.Version = types.TPMVersion12
+		return info, nil
+	case tpmVersion20:
+		info.Version = types.TPMVersion20
+	default:
+		return info, nil
+	}
+
+	params := tbsContextParams2{Version: tbsContextVersionTwo, Flags: tbsIncludeTPM20}
+	var ctx uintptr
+	if err := _Tbsi_Context_Create(&params, &ctx); err != nil {
+		return nil, errors.Wrap(err, "Tbsi_Context_Create failed")
+	}
+	defer _Tbsip_Context_Close(ctx)
+
+	submit := func(cmd []byte) ([]byte, error) {
+		resp := make([]byte, 4096)
+		n := uint32(len(resp))
+		if err := _Tbsip_Submit_Command(ctx, tbsCommandLocalityZero, tbsCommandPriorityNorm,
+			&cmd[0], uint32(len(cmd)), &resp[0], &n); err != nil {
+			return nil, errors.Wrap(err, "Tbsip_Submit_Command failed")
+		}
+		return resp[:n], nil
+	}
+	if err := shared.TPM2Query(submit, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
@@ -0,0 +1,182 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+const (
+	// filterNormalAccount (FILTER_NORMAL_ACCOUNT) restricts NetUserEnum to
+	// ordinary user accounts.
+	filterNormalAccount = 0x0002
+
+	// maxPreferredLength (MAX_PREFERRED_LENGTH) lets the Net* functions
+	// allocate as much memory as required for the result.
+	maxPreferredLength = 0xFFFFFFFF
+
+	// errorMoreData (ERROR_MORE_DATA) indicates that more entries are
+	// available using the resume handle.
+	errorMoreData syscall.Errno = 234
+)
+
+// userInfo2 is the USER_INFO_2 structure.
+type userInfo2 struct {
+	Name         *uint16
+	Password     *uint16
+	PasswordAge  uint32
+	Priv         uint32
+	HomeDir      *uint16
+	Comment      *uint16
+	Flags        uint32
+	ScriptPath   *uint16
+	AuthFlags    uint32
+	FullName     *uint16
+	UsrComment   *uint16
+	Parms        *uint16
+	Workstations *uint16
+	LastLogon    uint32
+	LastLogoff   uint32
+	AcctExpires  uint32
+	MaxStorage   uint32
+	UnitsPerWeek uint32
+	LogonHours   *byte
+	BadPwCount   uint32
+	NumLogons    uint32
+	LogonServer  *uint16
+	CountryCode  uint32
+	CodePage     uint32
+}
+
+// localGroupInfo1 is the LOCALGROUP_INFO_1 structure.
+type localGroupInfo1 struct {
+	Name    *uint16
+	Comment *uint16
+}
+
+// localGroupMembersInfo3 is the LOCALGROUP_MEMBERS_INFO_3 structure.
+type localGroupMembersInfo3 struct {
+	DomainAndName *uint16
+}
+
+// Users returns the local user accounts reported by NetUserEnum. The UID of
+// each account is its SID.
+func (s windowsSystem) Users() ([]types.UserAccount, error) {
+	var users []types.UserAccount
+	err := netEnum(func(buf **byte, read, total, resume *uint32) error {
+		return _NetUserEnum(nil, 2, filterNormalAccount, buf, maxPreferredLength, read, total, resume)
+	}, unsafe.Sizeof(userInfo2{}), func(entry unsafe.Pointer) {
+		info := (*userInfo2)(entry)
+		user := types.UserAccount{
+			Name:     utf16PtrToString(info.Name),
+			FullName: utf16PtrToString(info.FullName),
+			Home:     utf16PtrToString(info.HomeDir),
+		}
+		if sid, _, _, err := syscall.LookupSID("", user.Name); err == nil {
+			user.UID, _ = sid.String()
+		}
+		if info.LastLogon > 0 {
+			t := time.Unix(int64(info.LastLogon), 0)
+			user.LastLogin = &t
+		}
+		users = append(users, user)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "NetUserEnum failed")
+	}
+	return users, nil
+}
+
+// Groups returns the local groups reported by NetLocalGroupEnum. The GID of
+// each group is its SID and members are given as DOMAIN\name.
+func (s windowsSystem) Groups() ([]types.GroupAccount, error) {
+	var groups []types.GroupAccount
+	err := netEnum(func(buf **byte, read, total, resume *uint32) error {
+		return _NetLocalGroupEnum(nil, 1, buf, maxPreferredLength, read, total, resume)
+	}, unsafe.Sizeof(localGroupInfo1{}), func(entry unsafe.Pointer) {
+		info := (*localGroupInfo1)(entry)
+		groups = append(groups, types.GroupAccount{
+			Name: utf16PtrToString(info.Name),
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "NetLocalGroupEnum failed")
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		if sid, _, _, err := syscall.LookupSID("", group.Name); err == nil {
+			group.GID, _ = sid.String()
+		}
+
+		name, err := syscall.UTF16PtrFromString(group.Name)
+		if err != nil {
+			continue
+		}
+		err = netEnum(func(buf **byte, read, total, resume *uint32) error {
+			return _NetLocalGroupGetMembers(nil, name, 3, buf, maxPreferredLength, read, total, resume)
+		}, unsafe.Sizeof(localGroupMembersInfo3{}), func(entry unsafe.Pointer) {
+			info := (*localGroupMembersInfo3)(entry)
+			group.Members = append(group.Members, utf16PtrToString(info.DomainAndName))
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "NetLocalGroupGetMembers failed for group %v", group.Name)
+		}
+	}
+	return groups, nil
+}
+
+// netEnum drives one of the Net*Enum functions until all entries have been
+// returned. It invokes fn for each entry of size entrySize in the buffers
+// allocated by the API and frees them afterwards.
+func netEnum(enum func(buf **byte, read, total, resume *uint32) error, entrySize uintptr, fn func(entry unsafe.Pointer)) error {
+	var resume uint32
+	for {
+		var buf *byte
+		var read, total uint32
+		err := enum(&buf, &read, &total, &resume)
+		if err != nil && err != errorMoreData {
+			return err
+		}
+
+		if buf != nil {
+			for i := uintptr(0); i < uintptr(read); i++ {
+				fn(unsafe.Pointer(uintptr(unsafe.Pointer(buf)) + i*entrySize))
+			}
+			syscall.NetApiBufferFree(buf)
+		}
+
+		if err == nil {
+			return nil
+		}
+	}
+}
+
+// utf16PtrToString converts a NUL terminated UTF-16 string to a Go string.
+func utf16PtrToString(p *uint16) string {
+	if p == nil {
+		return ""
+	}
+	return syscall.UTF16ToString((*[1 << 15]uint16)(unsafe.Pointer(p))[:])
+}
@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"unsafe"
+
+	windows "github.com/elastic/go-windows"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+// SYSTEM_SPECULATION_CONTROL_INFORMATION flags.
+const (
+	specBpbEnabled            = 1 << 0
+	specBpbDisabledNoHardware = 1 << 2
+	specSSBDisabledSystemWide = 1 << 10
+	specSSBDisabledKernel     = 1 << 11
+	specSSBDisableRequired    = 1 << 12
+)
+
+// SYSTEM_KERNEL_VA_SHADOW_INFORMATION flags.
+const (
+	kvaShadowEnabled  = 1 << 0
+	kvaShadowRequired = 1 << 4
+)
+
+// CPUVulnerabilities returns the state of the Meltdown, Spectre variant 2, and
+// Speculative Store Bypass mitigations using the same information classes as
+// the SpeculationControl PowerShell module. Versions of Windows that predate
+// the mitigations do not support the information classes and return an
+// error.
+func (h *host) CPUVulnerabilities() (map[string]types.CPUVulnerabilityInfo, error) {
+	spec, err := querySystemInformationFlags(systemSpeculationControlInformation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query speculation control information")
+	}
+
+	vulns := speculationControlVulnerabilities(spec)
+	if kva, err := querySystemInformationFlags(systemKernelVaShadowInformation); err == nil {
+		vulns["meltdown"] = kvaShadowVulnerability(kva)
+	}
+	return vulns, nil
+}
+
+// querySystemInformationFlags returns the 32-bit flags of a fixed size
+// information class.
+func querySystemInformationFlags(infoClass uint32) (uint32, error) {
+	var flags, returnedLen uint32
+	status := _NtQuerySystemInformation(infoClass, uintptr(unsafe.Pointer(&flags)), uint32(unsafe.Sizeof(flags)), &returnedLen)
+	if status != 0 {
+		return 0, windows.NTStatus(status)
+	}
+	return flags, nil
+}
+
+func speculationControlVulnerabilities(flags uint32) map[string]types.CPUVulnerabilityInfo {
+	vulns := map[string]types.CPUVulnerabilityInfo{}
+
+	switch {
+	case flags&specBpbEnabled != 0:
+		vulns["spectre_v2"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityMitigated, Details: "Mitigation: Branch target injection mitigation enabled"}
+	case flags&specBpbDisabledNoHardware != 0:
+		vulns["spectre_v2"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityVulnerable, Details: "Vulnerable: No hardware support"}
+	default:
+		vulns["spectre_v2"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityVulnerable, Details: "Vulnerable: Disabled by system policy"}
+	}
+
+	switch {
+	case flags&specSSBDisableRequired == 0:
+		vulns["spec_store_bypass"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityNotAffected, Details: "Not affected"}
+	case flags&specSSBDisabledSystemWide != 0:
+		vulns["spec_store_bypass"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityMitigated, Details: "Mitigation: Speculative Store Bypass disabled system-wide"}
+	case flags&specSSBDisabledKernel != 0:
+		vulns["spec_store_bypass"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityMitigated, Details: "Mitigation: Speculative Store Bypass disabled in the kernel"}
+	default:
+		vulns["spec_store_bypass"] = types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityVulnerable, Details: "Vulnerable"}
+	}
+
+	return vulns
+}
+
+func kvaShadowVulnerability(flags uint32) types.CPUVulnerabilityInfo {
+	switch {
+	case flags&kvaShadowRequired == 0:
+		return types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityNotAffected, Details: "Not affected"}
+	case flags&kvaShadowEnabled != 0:
+		return types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityMitigated, Details: "Mitigation: Kernel VA shadow"}
+	default:
+		return types.CPUVulnerabilityInfo{Status: types.CPUVulnerabilityVulnerable, Details: "Vulnerable"}
+	}
+}
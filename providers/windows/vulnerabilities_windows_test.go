@@ -0,0 +1,40 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-sysinfo/types"
+)
+
+func TestSpeculationControlVulnerabilities(t *testing.T) {
+	vulns := speculationControlVulnerabilities(specBpbEnabled | specSSBDisableRequired)
+	assert.Equal(t, types.CPUVulnerabilityMitigated, vulns["spectre_v2"].Status)
+	assert.Equal(t, types.CPUVulnerabilityVulnerable, vulns["spec_store_bypass"].Status)
+
+	vulns = speculationControlVulnerabilities(specBpbDisabledNoHardware)
+	assert.Equal(t, types.CPUVulnerabilityVulnerable, vulns["spectre_v2"].Status)
+	assert.Equal(t, types.CPUVulnerabilityNotAffected, vulns["spec_store_bypass"].Status)
+
+	assert.Equal(t, types.CPUVulnerabilityNotAffected, kvaShadowVulnerability(0).Status)
+	assert.Equal(t, types.CPUVulnerabilityMitigated, kvaShadowVulnerability(kvaShadowRequired|kvaShadowEnabled).Status)
+	assert.Equal(t, types.CPUVulnerabilityVulnerable, kvaShadowVulnerability(kvaShadowRequired).Status)
+}
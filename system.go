@@ -18,14 +18,24 @@
 package sysinfo
 
 import (
+	"context"
+	"regexp"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/elastic/go-sysinfo/internal/registry"
+	"github.com/elastic/go-sysinfo/providers/shared"
 	"github.com/elastic/go-sysinfo/types"
 
 	// Register host and process providers.
+	_ "github.com/elastic/go-sysinfo/providers/aix"
 	_ "github.com/elastic/go-sysinfo/providers/darwin"
+	_ "github.com/elastic/go-sysinfo/providers/freebsd"
 	_ "github.com/elastic/go-sysinfo/providers/linux"
+	_ "github.com/elastic/go-sysinfo/providers/netbsd"
+	_ "github.com/elastic/go-sysinfo/providers/openbsd"
+	_ "github.com/elastic/go-sysinfo/providers/solaris"
 	_ "github.com/elastic/go-sysinfo/providers/windows"
 )
 
@@ -42,31 +52,119 @@ func Go() types.GoInfo {
 // Host returns information about host on which this process is running. If
 // host information collection is not implemented for this platform then
 // types.ErrNotImplemented is returned.
-func Host() (types.Host, error) {
-	provider := registry.GetHostProvider()
+func Host(opts ...Option) (types.Host, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Host
 	if provider == nil {
 		return nil, types.ErrNotImplemented
 	}
 	return provider.Host()
 }
 
+// SetCacheTTL enables caching of host data that rarely changes (the OS
+// version, machine IDs, CPU model and topology, and SMBIOS values) for the
+// given duration so that calling Host().Info() on every collection cycle
+// doesn't re-read it. A TTL of zero (the default) disables caching. Use
+// WithCacheTTL to override it for one call.
+func SetCacheTTL(ttl time.Duration) {
+	shared.StaticCache.SetTTL(ttl)
+}
+
+// Refresh drops the cached host data so that it is read again by the next
+// call to Host (e.g. to detect an OS upgrade).
+func Refresh() {
+	shared.StaticCache.Invalidate()
+}
+
+// EnvAllowlist returns a filter that only keeps the named variables. Names
+// are compared case-insensitively because they are case-insensitive on
+// Windows.
+func EnvAllowlist(names ...string) types.EnvFilter {
+	return func(name, value string) (string, bool) {
+		return value, containsFold(names, name)
+	}
+}
+
+// EnvDenylist returns a filter that drops the named variables. Names are
+// compared case-insensitively.
+func EnvDenylist(names ...string) types.EnvFilter {
+	return func(name, value string) (string, bool) {
+		return value, !containsFold(names, name)
+	}
+}
+
+// EnvRedactRegexp returns a filter that replaces the values of the variables
+// whose names match re with replacement.
+func EnvRedactRegexp(re *regexp.Regexp, replacement string) types.EnvFilter {
+	return func(name, value string) (string, bool) {
+		if re.MatchString(name) {
+			return replacement, true
+		}
+		return value, true
+	}
+}
+
+// ChainEnvFilters returns a filter that applies the filters in order. A
+// variable is dropped if any of them drops it.
+func ChainEnvFilters(filters ...types.EnvFilter) types.EnvFilter {
+	return func(name, value string) (string, bool) {
+		for _, filter := range filters {
+			var keep bool
+			if value, keep = filter(name, value); !keep {
+				return "", false
+			}
+		}
+		return value, true
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // Process returns a types.Process object representing the process associated
 // with the given PID. The types.Process object can be used to query information
 // about the process.  If process information collection is not implemented for
 // this platform then types.ErrNotImplemented is returned.
-func Process(pid int) (types.Process, error) {
-	provider := registry.GetProcessProvider()
+func Process(pid int, opts ...ProcessOption) (types.Process, error) {
+	options, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
 	if provider == nil {
 		return nil, types.ErrNotImplemented
 	}
+
+	return openProcess(provider, pid, options)
+}
+
+// openProcess opens a process with the providers that were returned by
+// newOptions.
+func openProcess(provider registry.ProcessProvider, pid int, options options) (types.Process, error) {
+	if p, ok := provider.(registry.ProcessFieldsProvider); ok && options.fields != types.FieldAll {
+		return p.ProcessWithFields(pid, options.fields)
+	}
 	return provider.Process(pid)
 }
 
 // Processes return a list of all processes. If process information collection
 // is not implemented for this platform then types.ErrNotImplemented is
 // returned.
-func Processes() ([]types.Process, error) {
-	provider := registry.GetProcessProvider()
+func Processes(opts ...Option) ([]types.Process, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
 	if provider == nil {
 		return nil, types.ErrNotImplemented
 	}
@@ -76,10 +174,412 @@ func Processes() ([]types.Process, error) {
 // Self return a types.Process object representing this process. If process
 // information collection is not implemented for this platform then
 // types.ErrNotImplemented is returned.
-func Self() (types.Process, error) {
-	provider := registry.GetProcessProvider()
+func Self(opts ...Option) (types.Process, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
 	if provider == nil {
 		return nil, types.ErrNotImplemented
 	}
 	return provider.Self()
 }
+
+// SampleProcessCPU reads the CPU times of a process together with the
+// identity of the process so that two samples can be compared with
+// types.CPUPercent.
+func SampleProcessCPU(p types.Process) (types.CPUSample, error) {
+	id, err := ProcessIdentity(p)
+	if err != nil {
+		return types.CPUSample{}, err
+	}
+	times, err := p.CPUTime()
+	if err != nil {
+		return types.CPUSample{}, err
+	}
+	return types.CPUSample{Times: times, Timestamp: time.Now(), Process: id}, nil
+}
+
+// ProcessIdentity returns the identity of a process. Compare identities with
+// types.SameProcess to detect that a PID was reused by a new process. The
+// boot ID is only set by providers that implement types.ProcessIdentifier.
+func ProcessIdentity(p types.Process) (types.ProcessIdentity, error) {
+	if v, ok := p.(types.ProcessIdentifier); ok {
+		return v.Identity()
+	}
+
+	info, err := p.Info()
+	if err != nil && !types.IsPartialInfo(err) {
+		return types.ProcessIdentity{}, err
+	}
+	return types.ProcessIdentity{PID: p.PID(), StartTime: info.StartTime}, nil
+}
+
+// MachineID returns a stable ID of the host and the source it was read from.
+// By default the preferred source of the platform is used (see
+// types.MachineIdentifier). If host information collection is not
+// implemented for this platform then types.ErrNotImplemented is
+// returned, if the host has no machine ID then a *types.NotSupportedError is
+// returned.
+func MachineID(opts ...MachineIDOption) (*types.MachineIDInfo, error) {
+	options, _, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := Host(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []types.MachineIDInfo
+	if v, ok := host.(types.MachineIdentifier); ok {
+		if ids, err = v.MachineIDs(); err != nil {
+			return nil, err
+		}
+	} else if info := host.Info(); info.UniqueID != "" {
+		ids = []types.MachineIDInfo{{ID: info.UniqueID, Source: info.UniqueIDSource}}
+	}
+	if len(ids) == 0 {
+		return nil, &types.NotSupportedError{Feature: "MachineID", Reason: "no machine ID source is available"}
+	}
+
+	if options.combined {
+		id := shared.CombineMachineIDs(ids)
+		return &id, nil
+	}
+	return &ids[0], nil
+}
+
+// HostContext is like Host but it returns ctx.Err() if the context is done
+// before the host information has been collected. The collection itself is
+// not interrupted; it finishes in the background and its result is dropped.
+func HostContext(ctx context.Context, opts ...Option) (types.Host, error) {
+	var host types.Host
+	err := shared.WithContext(ctx, func() (err error) {
+		host, err = Host(opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return host, nil
+}
+
+// ProcessContext is like Process but it returns ctx.Err() if the context is
+// done before the process has been opened. Opening the process is not
+// interrupted; it finishes in the background and its result is dropped.
+func ProcessContext(ctx context.Context, pid int, opts ...ProcessOption) (types.Process, error) {
+	var proc types.Process
+	err := shared.WithContext(ctx, func() (err error) {
+		proc, err = Process(pid, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proc, nil
+}
+
+// ProcessesContext is like Processes but it returns ctx.Err() if the context
+// is done before all processes have been listed. Providers that support it
+// (Linux and Windows) stop enumerating processes when the context is done.
+// The others keep enumerating in the background and the result is dropped.
+func ProcessesContext(ctx context.Context, opts ...Option) ([]types.Process, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+
+	if p, ok := provider.(registry.ProcessContextProvider); ok {
+		return p.ProcessesContext(ctx)
+	}
+
+	var procs []types.Process
+	err = shared.WithContext(ctx, func() (err error) {
+		procs, err = provider.Processes()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return procs, nil
+}
+
+// ProcessInfos returns the ProcessInfo of all processes. The context is
+// checked between processes so that collection can be bound by a deadline,
+// but the Info call of a single process is not interrupted.
+// Processes whose information cannot be read (e.g. because they exited) are
+// omitted, but partial information is included. If process information
+// collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func ProcessInfos(ctx context.Context, opts ...Option) ([]types.ProcessInfo, error) {
+	procs, err := ProcessesContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]types.ProcessInfo, 0, len(procs))
+	for _, proc := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		info, err := proc.Info()
+		if err != nil && !types.IsPartialInfo(err) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// TakeProcessSnapshot enumerates all processes and records their identity,
+// info, and user. Use types.ProcessSnapshot.Diff to compare it with a
+// previous snapshot. Processes whose information or identity (see
+// ProcessIdentity) cannot be read are omitted like in ProcessInfos. If
+// process information collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func TakeProcessSnapshot(ctx context.Context, opts ...Option) (*types.ProcessSnapshot, error) {
+	procs, err := ProcessesContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &types.ProcessSnapshot{
+		Time:      time.Now(),
+		Processes: make(map[int]types.ProcessSnapshotEntry, len(procs)),
+	}
+	for _, proc := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		info, err := proc.Info()
+		if err != nil && !types.IsPartialInfo(err) {
+			continue
+		}
+
+		id, err := ProcessIdentity(proc)
+		if err != nil {
+			continue
+		}
+
+		entry := types.ProcessSnapshotEntry{Identity: id, Info: info}
+		if user, err := proc.User(); err == nil {
+			entry.User = &user
+		}
+		snapshot.Processes[info.PID] = entry
+	}
+	return snapshot, nil
+}
+
+// ForEachProcess calls fn for each process. Providers that support it yield
+// the processes one at a time which keeps memory usage low on hosts with many
+// processes. Iteration stops when fn returns an error and that error is
+// returned. If process information collection is not implemented for this
+// platform then types.ErrNotImplemented is returned.
+func ForEachProcess(fn func(types.Process) error, opts ...Option) error {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return err
+	}
+	provider := providers.Process
+	if provider == nil {
+		return types.ErrNotImplemented
+	}
+
+	if it, ok := provider.(registry.ProcessIterator); ok {
+		return it.ForEachProcess(fn)
+	}
+
+	procs, err := provider.Processes()
+	if err != nil {
+		return err
+	}
+	for _, proc := range procs {
+		if err := fn(proc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessTree returns the process with the given PID and all of its
+// descendants. The tree is built from a single snapshot of all processes so
+// that parent-child relationships are consistent. If process information
+// collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func ProcessTree(pid int, opts ...Option) (*types.ProcessTreeNode, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+
+	procs, err := provider.Processes()
+	if err != nil {
+		return nil, err
+	}
+	return shared.ProcessTree(procs, pid)
+}
+
+// WatchProcesses returns a channel that receives an event each time a process
+// starts or exits. The source of the events depends on the platform:
+//
+//   - Linux: the kernel's proc connector (requires CAP_NET_ADMIN).
+//   - macOS: kqueue EVFILT_PROC filters. The kernel does not report the PID
+//     of forked children, so they are found by rescanning the process list
+//     and children that exit before the rescan are missed.
+//   - Windows and the other platforms: polling the process list.
+//
+// Providers that cannot receive notifications from the operating system
+// poll the process list instead, in which case short-lived processes may be
+// missed. The channel is closed when the context is done. If process
+// information collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+//
+// TODO: add a Windows backend based on WMI process traces or ETW.
+func WatchProcesses(ctx context.Context, opts ...Option) (<-chan types.ProcessEvent, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Process
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+
+	if w, ok := provider.(registry.ProcessWatcher); ok {
+		return w.WatchProcesses(ctx)
+	}
+	return shared.PollProcesses(ctx, provider.Processes, shared.ProcessPollInterval)
+}
+
+// WatchProcessExits returns a channel that receives the lifetime resource
+// usage of each process that exits, including short-lived processes that
+// polling would miss. The channel is closed when the context is done. If
+// process exit accounting is not supported on this platform then
+// types.ErrNotImplemented is returned.
+func WatchProcessExits(ctx context.Context, opts ...Option) (<-chan types.ProcessExitEvent, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if w, ok := providers.Process.(registry.ProcessExitWatcher); ok {
+		return w.WatchProcessExits(ctx)
+	}
+	return nil, types.ErrNotImplemented
+}
+
+// Network returns a types.Network object that can be used to query the
+// network stack of the host (e.g. to list all sockets). If network
+// information collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func Network(opts ...Option) (types.Network, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Network
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+	return provider.Network()
+}
+
+// ListeningPorts returns the TCP and UDP sockets that accept connections or
+// datagrams along with information about the owning processes. Processes
+// that cannot be read by the current user are omitted from the result. If
+// network or process information collection is not implemented for this
+// platform then types.ErrNotImplemented is returned.
+func ListeningPorts(opts ...Option) ([]types.ListeningPort, error) {
+	options, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	networkProvider := providers.Network
+	processProvider := providers.Process
+	if networkProvider == nil || processProvider == nil {
+		return nil, types.ErrNotImplemented
+	}
+
+	network, err := networkProvider.Network()
+	if err != nil {
+		return nil, err
+	}
+	conns, err := network.Connections(types.ConnectionKindInet)
+	if err != nil {
+		return nil, err
+	}
+	return shared.ListeningPorts(conns, func(pid int) (types.Process, error) {
+		return openProcess(processProvider, pid, options)
+	}), nil
+}
+
+// FileSystems returns the mounted file systems and their usage. If file
+// system information collection is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func FileSystems(opts ...Option) ([]types.FileSystemInfo, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.FileSystem
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+	return provider.FileSystems()
+}
+
+// Users returns the local user accounts. If user account enumeration is not
+// implemented for this platform then types.ErrNotImplemented is
+// returned.
+func Users(opts ...Option) ([]types.UserAccount, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.User
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+	return provider.Users()
+}
+
+// Groups returns the local groups. If group enumeration is not implemented
+// for this platform then types.ErrNotImplemented is returned.
+func Groups(opts ...Option) ([]types.GroupAccount, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.User
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+	return provider.Groups()
+}
+
+// FileSignature returns the code signing information of an executable file.
+// If signature verification is not implemented for this platform then
+// types.ErrNotImplemented is returned.
+func FileSignature(path string, opts ...Option) (*types.SignatureInfo, error) {
+	_, providers, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers.Signature
+	if provider == nil {
+		return nil, types.ErrNotImplemented
+	}
+	return provider.FileSignature(path)
+}
@@ -18,9 +18,14 @@
 package sysinfo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	osUser "os/user"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -30,6 +35,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/elastic/go-sysinfo/providers/shared"
 	"github.com/elastic/go-sysinfo/types"
 )
 
@@ -38,29 +44,104 @@ type ProcessFeatures struct {
 	Environment          bool
 	OpenHandleEnumerator bool
 	OpenHandleCounter    bool
+	ConnectionEnumerator bool
+	ChildEnumerator      bool
+	CgroupStats          bool
 	Seccomp              bool
 	Capabilities         bool
+	IOCounters           bool
+	ResourceUsage        bool
+	ThreadEnumerator     bool
+	ModuleEnumerator     bool
+	MemoryMapEnumerator  bool
+	SystemdUnit          bool
+	Namespaces           bool
+	CodeSignature        bool
+	ExecutableHasher     bool
 }
 
 var expectedProcessFeatures = map[string]*ProcessFeatures{
+	"aix": &ProcessFeatures{
+		ProcessInfo:          true,
+		Environment:          true,
+		OpenHandleEnumerator: true,
+		OpenHandleCounter:    true,
+		ChildEnumerator:      true,
+	},
 	"darwin": &ProcessFeatures{
 		ProcessInfo:          true,
 		Environment:          true,
-		OpenHandleEnumerator: false,
+		OpenHandleEnumerator: true,
 		OpenHandleCounter:    false,
+		ConnectionEnumerator: true,
+		ChildEnumerator:      true,
+		IOCounters:           true,
+		ResourceUsage:        true,
+		ThreadEnumerator:     true,
+		ModuleEnumerator:     true,
+		MemoryMapEnumerator:  true,
+		CodeSignature:        true,
+		ExecutableHasher:     true,
+	},
+	"freebsd": &ProcessFeatures{
+		ProcessInfo:     true,
+		Environment:     true,
+		ChildEnumerator: true,
+	},
+	"illumos": &ProcessFeatures{
+		ProcessInfo:     true,
+		Environment:     true,
+		ChildEnumerator: true,
 	},
 	"linux": &ProcessFeatures{
 		ProcessInfo:          true,
 		Environment:          true,
 		OpenHandleEnumerator: true,
 		OpenHandleCounter:    true,
+		ConnectionEnumerator: true,
+		ChildEnumerator:      true,
+		CgroupStats:          true,
 		Seccomp:              true,
 		Capabilities:         true,
+		IOCounters:           true,
+		ResourceUsage:        true,
+		ThreadEnumerator:     true,
+		ModuleEnumerator:     true,
+		MemoryMapEnumerator:  true,
+		SystemdUnit:          true,
+		Namespaces:           true,
+		CodeSignature:        true,
+		ExecutableHasher:     true,
+	},
+	"netbsd": &ProcessFeatures{
+		ProcessInfo:     true,
+		Environment:     true,
+		ChildEnumerator: true,
+	},
+	"openbsd": &ProcessFeatures{
+		ProcessInfo:     true,
+		Environment:     true,
+		ChildEnumerator: true,
+	},
+	"solaris": &ProcessFeatures{
+		ProcessInfo:     true,
+		Environment:     true,
+		ChildEnumerator: true,
 	},
 	"windows": &ProcessFeatures{
 		ProcessInfo:          true,
-		OpenHandleEnumerator: false,
+		Environment:          true,
+		OpenHandleEnumerator: true,
 		OpenHandleCounter:    true,
+		ConnectionEnumerator: true,
+		ChildEnumerator:      true,
+		IOCounters:           true,
+		ResourceUsage:        true,
+		ThreadEnumerator:     true,
+		ModuleEnumerator:     true,
+		MemoryMapEnumerator:  true,
+		CodeSignature:        true,
+		ExecutableHasher:     true,
 	},
 }
 
@@ -80,8 +161,20 @@ func TestProcessFeaturesMatrix(t *testing.T) {
 	_, features.Environment = process.(types.Environment)
 	_, features.OpenHandleEnumerator = process.(types.OpenHandleEnumerator)
 	_, features.OpenHandleCounter = process.(types.OpenHandleCounter)
+	_, features.ConnectionEnumerator = process.(types.ConnectionEnumerator)
+	_, features.ChildEnumerator = process.(types.ChildProcessEnumerator)
+	_, features.CgroupStats = process.(types.CgroupStats)
 	_, features.Seccomp = process.(types.Seccomp)
 	_, features.Capabilities = process.(types.Capabilities)
+	_, features.IOCounters = process.(types.IOCounters)
+	_, features.ResourceUsage = process.(types.ResourceUsage)
+	_, features.ThreadEnumerator = process.(types.ThreadEnumerator)
+	_, features.ModuleEnumerator = process.(types.ModuleEnumerator)
+	_, features.MemoryMapEnumerator = process.(types.MemoryMapEnumerator)
+	_, features.SystemdUnit = process.(types.SystemdUnit)
+	_, features.Namespaces = process.(types.Namespaces)
+	_, features.CodeSignature = process.(types.CodeSignature)
+	_, features.ExecutableHasher = process.(types.ExecutableHasher)
 
 	assert.Equal(t, expectedProcessFeatures[GOOS], &features)
 	logAsJSON(t, map[string]interface{}{
@@ -115,6 +208,8 @@ func TestSelf(t *testing.T) {
 	assert.EqualValues(t, os.Getpid(), info.PID)
 	assert.EqualValues(t, os.Getppid(), info.PPID)
 	assert.Equal(t, os.Args, info.Args)
+	// The test is running so it can't be a zombie or stopped.
+	assert.Contains(t, []string{types.ProcessStateRunning, types.ProcessStateSleeping, ""}, info.State)
 
 	wd, err := os.Getwd()
 	if err != nil {
@@ -158,6 +253,10 @@ func TestSelf(t *testing.T) {
 			if len(parts) != 2 {
 				t.Fatal("failed to parse os.Environ()")
 			}
+			if parts[0] == "" {
+				// Hidden variables like =C: on Windows are not reported.
+				continue
+			}
 			expectedEnv[parts[0]] = parts[1]
 		}
 		actualEnv, err := v.Environment()
@@ -198,6 +297,52 @@ func TestSelf(t *testing.T) {
 		}
 	}
 
+	if v, ok := process.(types.ConnectionEnumerator); ok {
+		conns, err := v.Connections()
+		if assert.NoError(t, err) {
+			output["process.connections"] = conns
+		}
+	}
+
+	if v, ok := process.(types.CgroupStats); ok {
+		cgroupInfo, err := v.CgroupStats()
+		if assert.NoError(t, err) {
+			output["process.cgroup"] = cgroupInfo
+		}
+	}
+
+	if v, ok := process.(types.SystemdUnit); ok {
+		unit, err := v.UnitName()
+		if assert.NoError(t, err) {
+			output["process.systemd_unit"] = unit
+		}
+	}
+
+	if v, ok := process.(types.Namespaces); ok {
+		namespaces, err := v.Namespaces()
+		if assert.NoError(t, err) {
+			output["process.namespaces"] = namespaces
+		}
+	}
+
+	if v, ok := process.(types.CodeSignature); ok {
+		sig, err := v.SignatureInfo()
+		if assert.NoError(t, err) {
+			output["process.signature"] = sig
+		}
+	}
+
+	if v, ok := process.(types.ExecutableHasher); ok {
+		hashes, err := v.Hashes()
+		if assert.NoError(t, err) {
+			expected, err := shared.HashFile(exe, types.SHA256)
+			if assert.NoError(t, err) {
+				assert.Equal(t, expected[types.SHA256], hashes[types.SHA256])
+			}
+			output["process.hashes"] = hashes
+		}
+	}
+
 	if v, ok := process.(types.OpenHandleCounter); ok {
 		count, err := v.OpenHandleCount()
 		if assert.NoError(t, err) {
@@ -221,6 +366,52 @@ func TestSelf(t *testing.T) {
 		}
 	}
 
+	if v, ok := process.(types.RLimits); ok {
+		limits, err := v.RLimits()
+		if assert.NoError(t, err) {
+			assert.NotZero(t, limits[types.RLimitNoFile].Soft)
+			output["process.rlimits"] = limits
+		}
+	}
+
+	if v, ok := process.(types.IOCounters); ok {
+		ioInfo, err := v.IOCounters()
+		if assert.NoError(t, err) {
+			output["process.io"] = ioInfo
+		}
+	}
+
+	if v, ok := process.(types.ResourceUsage); ok {
+		usage, err := v.ResourceUsage()
+		if assert.NoError(t, err) {
+			output["process.resource_usage"] = usage
+		}
+	}
+
+	if v, ok := process.(types.ThreadEnumerator); ok {
+		threads, err := v.Threads()
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, threads)
+			output["process.threads"] = threads
+		}
+	}
+
+	if v, ok := process.(types.ModuleEnumerator); ok {
+		modules, err := v.Modules()
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, modules)
+			output["process.modules"] = modules
+		}
+	}
+
+	if v, ok := process.(types.MemoryMapEnumerator); ok {
+		maps, err := v.MemoryMaps()
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, maps)
+			t.Log("memory map regions:", len(maps))
+		}
+	}
+
 	logAsJSON(t, output)
 }
 
@@ -245,11 +436,149 @@ func TestHost(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	logAsJSON(t, map[string]interface{}{
-		"host.info":   info,
-		"host.memory": memory,
-		"host.cpu":    cpu,
-	})
+	cpus, err := host.CPUTimePerCPU()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, cpus)
+
+	output := map[string]interface{}{
+		"host.info":    info,
+		"host.memory":  memory,
+		"host.cpu":     cpu,
+		"host.per_cpu": cpus,
+	}
+
+	if v, ok := host.(types.NetworkInterfaces); ok {
+		ifcs, err := v.NetworkInterfaces()
+		if assert.NoError(t, err) {
+			output["host.network_interfaces"] = ifcs
+		}
+	}
+
+	if v, ok := host.(types.DiskIOCounters); ok {
+		disks, err := v.DiskIOCounters()
+		if assert.NoError(t, err) {
+			output["host.disk_io"] = disks
+		}
+	}
+
+	if v, ok := host.(types.Hardware); ok {
+		hw, err := v.Hardware()
+		if err != types.ErrNotImplemented && !types.IsNotSupported(err) && assert.NoError(t, err) {
+			output["host.hardware"] = hw
+		}
+	}
+
+	if v, ok := host.(types.Virtualization); ok {
+		virt, err := v.VirtualizationInfo()
+		if assert.NoError(t, err) {
+			output["host.virtualization"] = virt
+		}
+	}
+
+	if v, ok := host.(types.KernelParameters); ok {
+		params, err := v.KernelParams()
+		if assert.NoError(t, err) {
+			t.Log("found", len(params), "kernel parameters")
+		}
+	}
+
+	if v, ok := host.(types.Security); ok {
+		security, err := v.SecurityInfo()
+		if assert.NoError(t, err) {
+			output["host.security"] = security
+		}
+	}
+
+	if v, ok := host.(types.Sensors); ok {
+		sensors, err := v.Sensors()
+		if assert.NoError(t, err) {
+			output["host.sensors"] = sensors
+		}
+	}
+
+	if v, ok := host.(types.PowerSources); ok {
+		sources, err := v.PowerSources()
+		if assert.NoError(t, err) {
+			output["host.power_sources"] = sources
+		}
+	}
+
+	if v, ok := host.(types.GPUs); ok {
+		gpus, err := v.GPUs()
+		if assert.NoError(t, err) {
+			output["host.gpus"] = gpus
+		}
+	}
+
+	if v, ok := host.(types.Devices); ok {
+		devices, err := v.Devices()
+		if assert.NoError(t, err) {
+			t.Log("found", len(devices), "devices")
+		}
+	}
+
+	if v, ok := host.(types.CPUInfo); ok {
+		cpuInfo, err := v.CPUInfo()
+		if assert.NoError(t, err) {
+			output["host.cpu_info"] = cpuInfo
+		}
+	}
+
+	if v, ok := host.(types.LoadAverage); ok {
+		load, err := v.LoadAverage()
+		if assert.NoError(t, err) {
+			output["host.load"] = load
+		}
+	}
+
+	if v, ok := host.(types.Uptime); ok {
+		uptime, err := v.Uptime()
+		if assert.NoError(t, err) {
+			assert.NotZero(t, uptime)
+			output["host.uptime"] = uptime
+		}
+	}
+
+	if v, ok := host.(types.SessionEnumerator); ok {
+		sessions, err := v.Sessions()
+		if assert.NoError(t, err) {
+			output["host.sessions"] = sessions
+		}
+	}
+
+	if v, ok := host.(types.LoadedModules); ok {
+		modules, err := v.LoadedModules()
+		if assert.NoError(t, err) {
+			output["host.loaded_modules"] = modules
+		}
+	}
+
+	if v, ok := host.(types.WindowsServices); ok {
+		services, err := v.WindowsServices()
+		if assert.NoError(t, err) {
+			output["host.windows_services"] = services
+		}
+	}
+
+	if v, ok := host.(types.ServiceUnits); ok {
+		// The host might not be running systemd.
+		if units, err := v.ServiceUnits(); err == nil {
+			output["host.service_units"] = units
+		} else {
+			t.Log("service units:", err)
+		}
+	}
+
+	if v, ok := host.(types.CPUFrequency); ok {
+		freqs, err := v.CPUFrequency()
+		if assert.NoError(t, err) {
+			output["host.cpu_frequency"] = freqs
+		}
+	}
+
+	logAsJSON(t, output)
 }
 
 func logAsJSON(t testing.TB, v interface{}) {
@@ -261,6 +590,239 @@ func logAsJSON(t testing.TB, v interface{}) {
 	t.Log(string(j))
 }
 
+type mockHost struct {
+	types.Host
+}
+
+func (mockHost) Info() types.HostInfo { return types.HostInfo{Hostname: "mock"} }
+
+type mockProvider struct{}
+
+func (mockProvider) Host() (types.Host, error) { return mockHost{}, nil }
+
+func TestRegisterProvider(t *testing.T) {
+	if err := RegisterProvider("mock", mockProvider{}); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterProvider("mock")
+
+	assert.Error(t, RegisterProvider("mock", mockProvider{}), "duplicate name")
+	assert.Error(t, RegisterProvider("other", struct{}{}), "not a provider")
+
+	host, err := Host(WithProvider("mock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "mock", host.Info().Hostname)
+
+	// The mock doesn't provide processes.
+	_, err = Self(WithProvider("mock"))
+	assert.Equal(t, types.ErrNotImplemented, err)
+
+	_, err = Host(WithProvider("missing"))
+	assert.Error(t, err)
+
+	// All functions honor the options.
+	err = ForEachProcess(func(types.Process) error { return nil }, WithProvider("mock"))
+	assert.Equal(t, types.ErrNotImplemented, err)
+	_, err = ProcessTree(os.Getpid(), WithProvider("mock"))
+	assert.Equal(t, types.ErrNotImplemented, err)
+	_, err = ListeningPorts(WithProvider("mock"))
+	assert.Equal(t, types.ErrNotImplemented, err)
+	_, err = MachineID(WithProvider("missing"))
+	assert.Error(t, err)
+}
+
+func TestWithHostFS(t *testing.T) {
+	host, err := Host(WithHostFS("/"))
+	if err == types.ErrNotImplemented {
+		t.Skip("host FS not supported on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, host.Info().Hostname)
+
+	procs, err := Processes(WithHostFS("/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, procs)
+}
+
+func TestHostCache(t *testing.T) {
+	SetCacheTTL(time.Minute)
+	defer SetCacheTTL(0)
+
+	h1, err := Host()
+	if err == types.ErrNotImplemented {
+		t.Skip("host provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cached values are copied so that callers can't modify each other's.
+	os1, os2 := h1.Info().OS, h2.Info().OS
+	if assert.NotNil(t, os1) && assert.NotNil(t, os2) {
+		assert.Equal(t, *os1, *os2)
+		assert.False(t, os1 == os2)
+	}
+
+	Refresh()
+	h3, err := Host()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, h1.Info().OS, h3.Info().OS)
+}
+
+func TestHostCacheTTLOption(t *testing.T) {
+	defer Refresh()
+
+	h1, err := Host(WithCacheTTL(time.Minute))
+	if err == types.ErrNotImplemented {
+		t.Skip("host provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := Host(WithCacheTTL(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, h1.Info().OS, h2.Info().OS)
+
+	h3, err := Host(WithCacheTTL(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, h1.Info().OS, h3.Info().OS)
+}
+
+func TestEnvFilter(t *testing.T) {
+	self, err := Self(WithEnvFilter(ChainEnvFilters(
+		EnvDenylist("path"),
+		EnvRedactRegexp(regexp.MustCompile(`^HOME$`), "[redacted]"),
+	)))
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	environ, ok := self.(types.Environment)
+	if !ok {
+		t.Skip("environment not implemented on", runtime.GOOS)
+	}
+
+	env, err := environ.Environment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range env {
+		assert.False(t, strings.EqualFold(name, "PATH"), name)
+	}
+	if _, found := env["HOME"]; found {
+		assert.Equal(t, "[redacted]", env["HOME"])
+	}
+
+	self, err = Process(os.Getpid(), WithEnvFilter(EnvAllowlist("path")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = self.(types.Environment).Environment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, env, 1)
+	for name := range env {
+		assert.True(t, strings.EqualFold(name, "PATH"), name)
+	}
+
+	// The filter is stored on the process, not set globally.
+	self, err = Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = self.(types.Environment).Environment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, len(env) > 1)
+}
+
+func TestProcessIdentity(t *testing.T) {
+	self, err := Self()
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := ProcessIdentity(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, os.Getpid(), id.PID)
+	assert.False(t, id.StartTime.IsZero())
+
+	again, err := Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := ProcessIdentity(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, types.SameProcess(id, id2), "%+v != %+v", id, id2)
+	t.Logf("%+v", id)
+}
+
+func TestSampleProcessCPU(t *testing.T) {
+	self, err := Self()
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	prev, err := SampleProcessCPU(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for end := time.Now().Add(50 * time.Millisecond); time.Now().Before(end); {
+	}
+	cur, err := SampleProcessCPU(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pct, ok := types.CPUPercent(prev, cur)
+	assert.True(t, ok)
+	assert.True(t, pct.Total >= 0)
+	t.Logf("%+v", pct)
+}
+
+func TestMachineID(t *testing.T) {
+	id, err := MachineID()
+	if err == types.ErrNotImplemented {
+		t.Skip("machine ID not available on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, id.ID)
+	assert.NotEmpty(t, id.Source)
+
+	combined, err := MachineID(WithCombinedMachineID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.MachineIDSourceCombined, combined.Source)
+	assert.NotEqual(t, id.ID, combined.ID)
+	t.Log(id, combined)
+}
+
 func TestProcesses(t *testing.T) {
 	start := time.Now()
 	procs, err := Processes()
@@ -276,9 +838,413 @@ func TestProcesses(t *testing.T) {
 			if os.IsPermission(err) {
 				continue
 			}
-			t.Fatal(err)
+			if !types.IsPartialInfo(err) {
+				t.Fatal(err)
+			}
+			t.Logf("pid=%v %v", info.PID, err)
 		}
 		t.Logf("pid=%v name='%s' exe='%s' args=%+v ppid=%d cwd='%s' start_time=%v", info.PID, info.Name, info.Exe, info.Args, info.PPID, info.CWD, info.StartTime)
 	}
 
 }
+
+func TestProcessInfos(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	infos, err := ProcessInfos(ctx)
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, infos)
+
+	cancel()
+	_, err = ProcessesContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+	_, err = ProcessInfos(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestTakeProcessSnapshot(t *testing.T) {
+	ctx := context.Background()
+	prev, err := TakeProcessSnapshot(ctx)
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Contains(t, prev.Processes, os.Getpid()) {
+		assert.Equal(t, os.Getpid(), prev.Processes[os.Getpid()].Identity.PID)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessTreeHelper$")
+	cmd.Env = append(os.Environ(), "GO_SYSINFO_HELPER_PROCESS=1")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer stdin.Close()
+
+	cur, err := TakeProcessSnapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := cur.Diff(prev)
+	var started bool
+	for _, p := range diff.Started {
+		started = started || p.Identity.PID == cmd.Process.Pid
+	}
+	assert.True(t, started, "child process was not reported as started")
+	for _, c := range diff.Changed {
+		assert.NotEqual(t, os.Getpid(), c.Current.Identity.PID)
+	}
+}
+
+func TestProcessWithFields(t *testing.T) {
+	proc, err := Process(os.Getpid(), WithFields(types.FieldCPU|types.FieldMemory))
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := proc.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, os.Getpid(), info.PID)
+	assert.EqualValues(t, os.Getppid(), info.PPID)
+	assert.NotEmpty(t, info.Name)
+	if runtime.GOOS == "linux" || runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		assert.Empty(t, info.CWD)
+		assert.Empty(t, info.Exe)
+		assert.Empty(t, info.Args)
+	}
+
+	if _, err = proc.CPUTime(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = proc.Memory(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment is read even though neither FieldExe nor FieldArgs
+	// is selected.
+	if e, ok := proc.(types.Environment); ok {
+		env, err := e.Environment()
+		if err != types.ErrNotImplemented && assert.NoError(t, err) {
+			assert.NotEmpty(t, env)
+		}
+	}
+}
+
+func TestProcessWithFieldNone(t *testing.T) {
+	proc, err := Process(os.Getpid(), WithFields(types.FieldNone))
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := proc.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualValues(t, os.Getpid(), info.PID)
+	assert.NotEmpty(t, info.Name)
+	assert.False(t, info.StartTime.IsZero())
+	if runtime.GOOS == "linux" || runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		assert.Empty(t, info.CWD)
+		assert.Empty(t, info.Exe)
+		assert.Empty(t, info.Args)
+	}
+}
+
+func TestProcessesWithFields(t *testing.T) {
+	procs, err := Processes(WithFields(types.FieldCPU))
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	var self types.Process
+	for _, p := range procs {
+		if p.PID() == os.Getpid() {
+			self = p
+		}
+	}
+	if !assert.NotNil(t, self, "self was not enumerated") {
+		return
+	}
+	info, err := self.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, info.Name)
+	if runtime.GOOS == "linux" || runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		assert.Empty(t, info.Args)
+	}
+}
+
+func TestProcessesWithProcessBackend(t *testing.T) {
+	procs, err := Processes(WithProcessBackend(types.ProcessBackendEBPF))
+	if err == types.ErrNotImplemented {
+		t.Skip("process backends not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, p := range procs {
+		if p.PID() == os.Getpid() {
+			found = true
+		}
+	}
+	assert.True(t, found, "self was not enumerated")
+}
+
+func TestForEachProcess(t *testing.T) {
+	var found bool
+	err := ForEachProcess(func(p types.Process) error {
+		if p.PID() == os.Getpid() {
+			found = true
+		}
+		return nil
+	})
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, found, "self was not enumerated")
+
+	stop := errors.New("stop")
+	var count int
+	err = ForEachProcess(func(p types.Process) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestNetwork(t *testing.T) {
+	network, err := Network()
+	if err == types.ErrNotImplemented {
+		t.Skip("network provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	conns, err := network.Connections(types.ConnectionKindAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log("found", len(conns), "connections")
+
+	_, err = network.Connections("bogus")
+	assert.Error(t, err)
+
+	logAsJSON(t, map[string]interface{}{
+		"network.connections": conns,
+	})
+}
+
+func TestListeningPorts(t *testing.T) {
+	ports, err := ListeningPorts()
+	if err == types.ErrNotImplemented {
+		t.Skip("network provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	logAsJSON(t, map[string]interface{}{
+		"listening_ports": ports,
+	})
+}
+
+func TestFileSystems(t *testing.T) {
+	filesystems, err := FileSystems()
+	if err == types.ErrNotImplemented {
+		t.Skip("file system provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, filesystems)
+
+	logAsJSON(t, map[string]interface{}{
+		"filesystems": filesystems,
+	})
+}
+
+func TestUsers(t *testing.T) {
+	users, err := Users()
+	if err == types.ErrNotImplemented {
+		t.Skip("user provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, users)
+
+	groups, err := Groups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, groups)
+
+	logAsJSON(t, map[string]interface{}{
+		"users":  users,
+		"groups": groups,
+	})
+}
+
+// TestProcessTreeHelper is executed as a child process by TestProcessTree. It
+// blocks until stdin is closed.
+func TestProcessTreeHelper(t *testing.T) {
+	if os.Getenv("GO_SYSINFO_HELPER_PROCESS") != "1" {
+		return
+	}
+	ioutil.ReadAll(os.Stdin)
+	os.Exit(0)
+}
+
+func TestProcessTree(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessTreeHelper$")
+	cmd.Env = append(os.Environ(), "GO_SYSINFO_HELPER_PROCESS=1")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer stdin.Close()
+
+	tree, err := ProcessTree(os.Getpid())
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, os.Getpid(), tree.Info.PID)
+
+	var found bool
+	for _, child := range tree.Children {
+		if child.Info.PID == cmd.Process.Pid {
+			found = true
+		}
+	}
+	assert.True(t, found, "child process %d not found in tree", cmd.Process.Pid)
+
+	self, err := Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := self.(types.ChildProcessEnumerator); ok {
+		children, err := v.Children()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var pids []int
+		for _, child := range children {
+			pids = append(pids, child.PID())
+		}
+		assert.Contains(t, pids, cmd.Process.Pid)
+	}
+
+	child, err := Process(cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := child.(types.AncestorEnumerator); ok {
+		ancestors, err := v.Ancestors()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if assert.NotEmpty(t, ancestors) {
+			assert.Equal(t, os.Getpid(), ancestors[0].PID())
+		}
+	}
+
+	logAsJSON(t, map[string]interface{}{
+		"process.tree": tree,
+	})
+}
+
+func TestWatchProcesses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events, err := WatchProcesses(ctx)
+	if err == types.ErrNotImplemented {
+		t.Skip("process provider not implemented on", runtime.GOOS)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessTreeHelper$")
+	cmd.Env = append(os.Environ(), "GO_SYSINFO_HELPER_PROCESS=1")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var started bool
+	for e := range events {
+		if e.PID != cmd.Process.Pid {
+			continue
+		}
+		if e.Type == types.ProcessEventStart && !started {
+			// Let the child exit once its start was observed.
+			started = true
+			stdin.Close()
+			cmd.Wait()
+		}
+		if e.Type == types.ProcessEventExit {
+			break
+		}
+	}
+	assert.True(t, started, "start event not received")
+	assert.NoError(t, ctx.Err(), "exit event not received")
+}
+
+func TestWatchProcessExits(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events, err := WatchProcessExits(ctx)
+	if err == types.ErrNotImplemented {
+		t.Skip("process exit accounting not implemented on", runtime.GOOS)
+	} else if err != nil {
+		// Registering a taskstats listener requires CAP_NET_ADMIN.
+		t.Skip("process exit accounting unavailable:", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessTreeHelper$")
+	cmd.Env = append(os.Environ(), "GO_SYSINFO_HELPER_PROCESS=1")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for e := range events {
+		if e.PID == cmd.Process.Pid {
+			assert.Equal(t, os.Getpid(), e.PPID)
+			assert.Zero(t, e.ExitCode)
+			assert.NotZero(t, e.Totals.MaxRSS)
+			return
+		}
+	}
+	t.Fatal("exit event not received")
+}
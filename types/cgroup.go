@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// CgroupStats returns resource usage statistics for the control group that a
+// process belongs to.
+type CgroupStats interface {
+	CgroupStats() (*CgroupInfo, error)
+}
+
+// CgroupInfo contains the control group statistics of a process.
+type CgroupInfo struct {
+	// V2 contains the statistics from the cgroup v2 unified hierarchy. It is
+	// nil when the unified hierarchy is not mounted or the process is not
+	// part of it.
+	V2 *CgroupsV2 `json:"v2,omitempty"`
+}
+
+// CgroupsV2 contains statistics read from a cgroup in the cgroup v2 unified
+// hierarchy. Statistics of controllers that are not enabled for the cgroup
+// are left empty.
+type CgroupsV2 struct {
+	Path   string               `json:"path"` // Path of the cgroup relative to the hierarchy root.
+	CPU    CgroupV2CPUStats     `json:"cpu"`
+	Memory CgroupV2MemoryStats  `json:"memory"`
+	IO     []CgroupV2IODevStats `json:"io,omitempty"`
+	Pids   CgroupV2PidsStats    `json:"pids"`
+}
+
+// CgroupV2CPUStats contains data from cpu.stat.
+type CgroupV2CPUStats struct {
+	Usage         time.Duration `json:"usage"`          // usage_usec
+	User          time.Duration `json:"user"`           // user_usec
+	System        time.Duration `json:"system"`         // system_usec
+	Periods       uint64        `json:"periods"`        // nr_periods
+	Throttled     uint64        `json:"throttled"`      // nr_throttled
+	ThrottledTime time.Duration `json:"throttled_time"` // throttled_usec
+}
+
+// CgroupV2MemoryStats contains data from memory.current and memory.max.
+type CgroupV2MemoryStats struct {
+	Current uint64  `json:"current_bytes"`       // Memory currently in use.
+	Max     *uint64 `json:"max_bytes,omitempty"` // Memory limit (nil when unlimited).
+}
+
+// CgroupV2IODevStats contains the data from io.stat for one device.
+type CgroupV2IODevStats struct {
+	Device       string `json:"device"`        // Device number (major:minor).
+	ReadBytes    uint64 `json:"read_bytes"`    // rbytes
+	WriteBytes   uint64 `json:"write_bytes"`   // wbytes
+	ReadOps      uint64 `json:"read_ops"`      // rios
+	WriteOps     uint64 `json:"write_ops"`     // wios
+	DiscardBytes uint64 `json:"discard_bytes"` // dbytes
+	DiscardOps   uint64 `json:"discard_ops"`   // dios
+}
+
+// CgroupV2PidsStats contains data from pids.current and pids.max.
+type CgroupV2PidsStats struct {
+	Current uint64  `json:"current"`       // Number of processes in the cgroup.
+	Max     *uint64 `json:"max,omitempty"` // Process limit (nil when unlimited).
+}
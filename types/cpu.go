@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// CPUInfo returns information about the model and topology of the processors
+// of the host.
+type CPUInfo interface {
+	CPUInfo() (*ProcessorInfo, error)
+}
+
+// ProcessorInfo describes the processors of the host. All sockets are assumed
+// to contain the same model.
+type ProcessorInfo struct {
+	Vendor        string         `json:"vendor,omitempty"`            // Vendor (e.g. GenuineIntel, AuthenticAMD, ARM).
+	ModelName     string         `json:"model_name,omitempty"`        // Model name (e.g. Intel(R) Xeon(R) CPU E5-2670 0 @ 2.60GHz).
+	Sockets       int            `json:"sockets"`                     // Number of physical packages.
+	PhysicalCores int            `json:"physical_cores"`              // Number of cores in all packages.
+	LogicalCores  int            `json:"logical_cores"`               // Number of hardware threads in all packages.
+	BaseFrequency uint64         `json:"base_frequency_hz,omitempty"` // Nominal frequency.
+	MaxFrequency  uint64         `json:"max_frequency_hz,omitempty"`  // Maximum (turbo) frequency.
+	Caches        []CPUCacheInfo `json:"caches,omitempty"`            // Caches of a single core.
+	Flags         []string       `json:"flags,omitempty"`             // Lower case feature flags (e.g. sse4_2, avx2, neon).
+}
+
+// NUMA returns the non-uniform memory access topology of the host.
+type NUMA interface {
+	NUMA() (*NUMAInfo, error)
+}
+
+// NUMAInfo describes the NUMA nodes of the host. Hosts without NUMA support
+// report a single node.
+type NUMAInfo struct {
+	Nodes []NUMANodeInfo `json:"nodes"`
+}
+
+// NUMANodeInfo describes a NUMA node. Memory values are in bytes and are zero
+// when the platform does not report them.
+type NUMANodeInfo struct {
+	ID          int    `json:"id"`                           // Node number.
+	CPUs        []int  `json:"cpus,omitempty"`               // Logical CPUs that belong to the node.
+	MemoryTotal uint64 `json:"memory_total_bytes,omitempty"` // Total memory of the node.
+	MemoryFree  uint64 `json:"memory_free_bytes"`            // Free memory of the node.
+	MemoryUsed  uint64 `json:"memory_used_bytes,omitempty"`  // MemoryTotal - MemoryFree
+}
+
+// CPUVulnerabilities reports the state of mitigations for CPU hardware
+// vulnerabilities. The map is keyed by the Linux vulnerability name (e.g.
+// meltdown, spectre_v2, spec_store_bypass).
+type CPUVulnerabilities interface {
+	CPUVulnerabilities() (map[string]CPUVulnerabilityInfo, error)
+}
+
+// CPUVulnerabilityInfo describes the state of a single vulnerability.
+type CPUVulnerabilityInfo struct {
+	Status  string `json:"status"`            // One of the CPUVulnerability* constants.
+	Details string `json:"details,omitempty"` // Description from the kernel (e.g. "Mitigation: PTI").
+}
+
+// CPU vulnerability states.
+const (
+	CPUVulnerabilityNotAffected = "not_affected"
+	CPUVulnerabilityVulnerable  = "vulnerable"
+	CPUVulnerabilityMitigated   = "mitigated"
+	CPUVulnerabilityUnknown     = "unknown"
+)
+
+// CPUCacheInfo describes a cache of a processor. The size is of a single
+// instance of the cache (shared caches are only counted once).
+type CPUCacheInfo struct {
+	Level int    `json:"level"`      // Cache level (1, 2, 3).
+	Type  string `json:"type"`       // Cache type (data, instruction, unified).
+	Size  uint64 `json:"size_bytes"` // Size in bytes.
+}
+
+// Types of CPU caches.
+const (
+	CPUCacheData        = "data"
+	CPUCacheInstruction = "instruction"
+	CPUCacheUnified     = "unified"
+)
+
+// CPUFrequency returns the current frequency of each logical CPU.
+type CPUFrequency interface {
+	CPUFrequency() ([]CPUFrequencyInfo, error)
+}
+
+// CPUFrequencyInfo contains the frequency of a logical CPU. The limits,
+// governor, and driver are only reported on Linux when the kernel has a
+// cpufreq driver.
+type CPUFrequencyInfo struct {
+	CPU      int    `json:"cpu"`                // Logical CPU number.
+	Current  uint64 `json:"current_hz"`         // Current frequency.
+	Min      uint64 `json:"min_hz,omitempty"`   // Minimum frequency allowed by the policy.
+	Max      uint64 `json:"max_hz,omitempty"`   // Maximum frequency allowed by the policy.
+	Governor string `json:"governor,omitempty"` // Scaling governor (e.g. performance, powersave, schedutil).
+	Driver   string `json:"driver,omitempty"`   // Scaling driver (e.g. intel_pstate, acpi-cpufreq).
+}
@@ -0,0 +1,137 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// CPUPercentages is the CPU utilization between two samples of CPUTimes. A
+// value of 100 means one CPU was fully used by that kind of work, so the
+// values of a multi-threaded process or of the host can exceed 100 unless
+// they are normalized.
+type CPUPercentages struct {
+	User    float64 `json:"user"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle,omitempty"`
+	IOWait  float64 `json:"iowait,omitempty"`
+	IRQ     float64 `json:"irq,omitempty"`
+	Nice    float64 `json:"nice,omitempty"`
+	SoftIRQ float64 `json:"soft_irq,omitempty"`
+	Steal   float64 `json:"steal,omitempty"`
+	Total   float64 `json:"total"` // Time not spent idle or waiting for I/O.
+}
+
+// Normalize divides the percentages by the number of CPUs so that they are
+// between 0 and 100.
+func (p CPUPercentages) Normalize(numCPU int) CPUPercentages {
+	if numCPU <= 1 {
+		return p
+	}
+	n := float64(numCPU)
+	return CPUPercentages{
+		User:    p.User / n,
+		System:  p.System / n,
+		Idle:    p.Idle / n,
+		IOWait:  p.IOWait / n,
+		IRQ:     p.IRQ / n,
+		Nice:    p.Nice / n,
+		SoftIRQ: p.SoftIRQ / n,
+		Steal:   p.Steal / n,
+		Total:   p.Total / n,
+	}
+}
+
+// Delta returns the CPU time consumed since prev. Counters that went
+// backwards (e.g. a reset after CPU hotplug or the iowait counter on Linux,
+// which is known to decrease) yield zero instead of a negative value.
+func (cpu CPUTimes) Delta(prev CPUTimes) CPUTimes {
+	delta := func(cur, prev time.Duration) time.Duration {
+		if cur < prev {
+			return 0
+		}
+		return cur - prev
+	}
+	return CPUTimes{
+		User:    delta(cpu.User, prev.User),
+		System:  delta(cpu.System, prev.System),
+		Idle:    delta(cpu.Idle, prev.Idle),
+		IOWait:  delta(cpu.IOWait, prev.IOWait),
+		IRQ:     delta(cpu.IRQ, prev.IRQ),
+		Nice:    delta(cpu.Nice, prev.Nice),
+		SoftIRQ: delta(cpu.SoftIRQ, prev.SoftIRQ),
+		Steal:   delta(cpu.Steal, prev.Steal),
+	}
+}
+
+// DeltaPercent returns the CPU utilization since prev, which was sampled
+// interval ago. The result is relative to one CPU (see
+// CPUPercentages.Normalize).
+//
+// When interval is zero or less the utilization is computed relative to the
+// total CPU time that elapsed between the samples instead. This is the
+// preferred way to compute the utilization of the host because it does not
+// depend on the accuracy of the sampling interval, and the result is already
+// normalized.
+func (cpu CPUTimes) DeltaPercent(prev CPUTimes, interval time.Duration) CPUPercentages {
+	d := cpu.Delta(prev)
+	if interval <= 0 {
+		interval = d.Total()
+		if interval <= 0 {
+			return CPUPercentages{}
+		}
+	}
+
+	pct := func(v time.Duration) float64 {
+		return 100 * float64(v) / float64(interval)
+	}
+	return CPUPercentages{
+		User:    pct(d.User),
+		System:  pct(d.System),
+		Idle:    pct(d.Idle),
+		IOWait:  pct(d.IOWait),
+		IRQ:     pct(d.IRQ),
+		Nice:    pct(d.Nice),
+		SoftIRQ: pct(d.SoftIRQ),
+		Steal:   pct(d.Steal),
+		Total:   pct(d.Total() - d.Idle - d.IOWait),
+	}
+}
+
+// CPUSample is a reading of the CPU times of a process or the host.
+type CPUSample struct {
+	Times     CPUTimes        `json:"cpu"`
+	Timestamp time.Time       `json:"timestamp"` // When the sample was taken.
+	Process   ProcessIdentity `json:"process"`   // Identity of the process (zero for the host).
+}
+
+// CPUPercent returns the CPU utilization between two samples of the same
+// process relative to one CPU. The wall clock time between the samples is
+// used as the interval because process CPU times are accumulated in clock
+// ticks on some platforms (e.g. 10ms with USER_HZ=100 on Linux) and don't
+// contain the idle time. ok is false if the samples can't be compared
+// because the PID was reused by a new process or the samples are not in
+// order.
+func CPUPercent(prev, cur CPUSample) (pct CPUPercentages, ok bool) {
+	if !SameProcess(prev.Process, cur.Process) {
+		return CPUPercentages{}, false
+	}
+	interval := cur.Timestamp.Sub(prev.Timestamp)
+	if interval <= 0 {
+		return CPUPercentages{}, false
+	}
+	return cur.Times.DeltaPercent(prev.Times, interval), true
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPUTimesDeltaPercent(t *testing.T) {
+	prev := CPUTimes{User: 10 * time.Second, System: 5 * time.Second, Idle: 80 * time.Second, IOWait: 2 * time.Second}
+	cur := CPUTimes{User: 13 * time.Second, System: 6 * time.Second, Idle: 86 * time.Second, IOWait: 2 * time.Second}
+
+	// Relative to the elapsed CPU time (host utilization).
+	pct := cur.DeltaPercent(prev, 0)
+	assert.InDelta(t, 30, pct.User, 1e-9)
+	assert.InDelta(t, 10, pct.System, 1e-9)
+	assert.InDelta(t, 60, pct.Idle, 1e-9)
+	assert.InDelta(t, 40, pct.Total, 1e-9)
+
+	// Relative to one CPU over a 5 second interval on a two CPU host.
+	pct = cur.DeltaPercent(prev, 5*time.Second)
+	assert.InDelta(t, 80, pct.Total, 1e-9)
+	assert.InDelta(t, 40, pct.Normalize(2).Total, 1e-9)
+
+	// No time elapsed.
+	assert.Equal(t, CPUPercentages{}, cur.DeltaPercent(cur, 0))
+}
+
+func TestCPUTimesDeltaWrap(t *testing.T) {
+	// Counters that go backwards must not produce negative utilization.
+	prev := CPUTimes{User: 10 * time.Second, IOWait: 5 * time.Second}
+	cur := CPUTimes{User: 12 * time.Second, IOWait: 4 * time.Second}
+
+	assert.Equal(t, CPUTimes{User: 2 * time.Second}, cur.Delta(prev))
+
+	pct := cur.DeltaPercent(prev, time.Second)
+	assert.InDelta(t, 200, pct.User, 1e-9)
+	assert.Zero(t, pct.IOWait)
+}
+
+func TestCPUPercent(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := CPUSample{
+		Times:     CPUTimes{User: time.Second, System: time.Second},
+		Timestamp: start.Add(time.Minute),
+		Process:   ProcessIdentity{PID: 42, StartTime: start},
+	}
+	cur := prev
+	cur.Times = CPUTimes{User: 1500 * time.Millisecond, System: 1250 * time.Millisecond}
+	cur.Timestamp = prev.Timestamp.Add(time.Second)
+
+	pct, ok := CPUPercent(prev, cur)
+	assert.True(t, ok)
+	assert.InDelta(t, 50, pct.User, 1e-9)
+	assert.InDelta(t, 25, pct.System, 1e-9)
+	assert.InDelta(t, 75, pct.Total, 1e-9)
+
+	// The PID was reused by a process that started after the first sample.
+	reused := cur
+	reused.Process.StartTime = prev.Timestamp.Add(500 * time.Millisecond)
+	reused.Times = CPUTimes{User: 10 * time.Millisecond}
+	_, ok = CPUPercent(prev, reused)
+	assert.False(t, ok)
+
+	// The host rebooted and a new process got the same PID and start time.
+	rebooted := cur
+	rebooted.Process.BootID = "8c1f9b0e-3a4d-4f61-b2c5-7d9e0a1b2c3d"
+	_, ok = CPUPercent(prev, rebooted)
+	assert.False(t, ok)
+
+	// Samples out of order.
+	_, ok = CPUPercent(cur, prev)
+	assert.False(t, ok)
+}
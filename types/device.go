@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// Devices is implemented by hosts that can enumerate the devices attached
+// to their PCI and USB buses.
+type Devices interface {
+	Devices() ([]DeviceInfo, error)
+}
+
+// Device buses.
+const (
+	DeviceBusPCI = "pci"
+	DeviceBusUSB = "usb"
+)
+
+// DeviceInfo describes a PCI or USB device. The devices form a tree through
+// their Parent IDs (e.g. a USB device is the child of a hub, and a root hub
+// of a PCI host controller). Names are empty when the OS does not report
+// them and they are not in the ID database.
+type DeviceInfo struct {
+	Bus    string `json:"bus"`              // Bus type (pci or usb).
+	ID     string `json:"id"`               // Address on the bus (e.g. 0000:00:14.0, 1-1.2) or the OS instance ID.
+	Parent string `json:"parent,omitempty"` // ID of the parent device.
+
+	VendorID  string `json:"vendor_id,omitempty"`  // Vendor ID as four hex digits (e.g. 8086).
+	ProductID string `json:"product_id,omitempty"` // Device or product ID as four hex digits.
+	Vendor    string `json:"vendor,omitempty"`     // Vendor name.
+	Product   string `json:"product,omitempty"`    // Device or product name.
+	Class     string `json:"class,omitempty"`      // PCI class code (e.g. 0c0330) or USB device class (e.g. 09).
+	Driver    string `json:"driver,omitempty"`     // Driver bound to the device.
+	Serial    string `json:"serial,omitempty"`     // Serial number (USB only).
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// DiskHealth is implemented by hosts that can read the SMART (Self-
+// Monitoring, Analysis and Reporting Technology) data of their disks.
+type DiskHealth interface {
+	DiskHealth() ([]DiskHealthInfo, error)
+}
+
+// Disk protocols.
+const (
+	DiskProtocolATA  = "ata"
+	DiskProtocolNVMe = "nvme"
+)
+
+// Overall SMART health states.
+const (
+	DiskHealthPassed  = "passed"  // The drive reports no threshold exceeded or critical warning.
+	DiskHealthFailing = "failing" // The drive predicts a failure.
+)
+
+// DiskHealthInfo is a summary of the SMART data of a disk. Reading SMART
+// data usually requires administrator rights. Without them only the
+// identity of the disk is reported and the remaining fields are empty.
+type DiskHealthInfo struct {
+	Name     string `json:"name"`             // Device name (e.g. sda, nvme0, PhysicalDrive0, disk0).
+	Model    string `json:"model,omitempty"`  // Model or product name.
+	Serial   string `json:"serial,omitempty"` // Serial number.
+	Protocol string `json:"protocol"`         // ata or nvme.
+	Status   string `json:"status,omitempty"` // Overall health (passed or failing), empty if unknown.
+
+	Temperature        *float64 `json:"temperature,omitempty"`         // Current temperature in degrees Celsius.
+	PowerOnHours       *uint64  `json:"power_on_hours,omitempty"`      // Hours the drive has been powered on.
+	ReallocatedSectors *uint64  `json:"reallocated_sectors,omitempty"` // ATA only, number of remapped sectors.
+	PercentageUsed     *uint64  `json:"percentage_used,omitempty"`     // NVMe only, estimate of the endurance used (may exceed 100).
+	AvailableSpare     *uint64  `json:"available_spare,omitempty"`     // NVMe only, remaining spare capacity in percent.
+	CriticalWarning    uint8    `json:"critical_warning,omitempty"`    // NVMe only, critical warning flags of the SMART log.
+}
@@ -0,0 +1,35 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package types contains the data types and interfaces reported by the
+// providers.
+//
+// The JSON encoding of the types is a stable schema:
+//
+//   - Field names are the snake_case names of the json struct tags. They are
+//     not renamed once released.
+//   - Durations (time.Duration) are integer nanoseconds.
+//   - Times are RFC 3339 strings with nanosecond precision. Times that are
+//     zero because they are unknown (e.g. the install time of a package)
+//     are omitted.
+//   - Optional fields are omitted when they are empty. Pointers to bool and
+//     numbers are used where false or 0 is a valid value that must be
+//     reported.
+//
+// Use Flatten to get the fields as a flat map with dotted keys as used by
+// ECS-style documents.
+package types
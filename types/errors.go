@@ -17,6 +17,155 @@
 
 package types
 
-import "github.com/pkg/errors"
+import (
+	"os"
+	"strconv"
+	"strings"
 
-var ErrNotImplemented = errors.New("unimplemented")
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotImplemented is returned for features that are not implemented
+	// for the OS. The functions of the sysinfo package return it as is so
+	// that err == ErrNotImplemented works.
+	ErrNotImplemented = errors.New("unimplemented")
+
+	// ErrNotSupported is the cause of errors returned for features that are
+	// implemented for the OS but are unavailable on the host, e.g. because
+	// the kernel is too old or lacks a config option. See NotSupportedError.
+	ErrNotSupported = errors.New("not supported")
+
+	// ErrProcessNotFound is the cause of errors returned when a process does
+	// not exist (anymore). See ProcessNotFoundError.
+	ErrProcessNotFound = errors.New("process not found")
+)
+
+// NotSupportedError is returned when a feature is not available on the host.
+type NotSupportedError struct {
+	Feature string // Name of the feature.
+	Reason  string // Why the feature is unavailable.
+}
+
+func (e *NotSupportedError) Error() string {
+	if e.Reason == "" {
+		return e.Feature + " is not supported"
+	}
+	return e.Feature + " is not supported: " + e.Reason
+}
+
+func (e *NotSupportedError) Cause() error  { return ErrNotSupported }
+func (e *NotSupportedError) Unwrap() error { return ErrNotSupported }
+
+// ProcessNotFoundError is returned when the process with the given PID does
+// not exist.
+type ProcessNotFoundError struct {
+	PID int
+}
+
+func (e *ProcessNotFoundError) Error() string {
+	return "process " + strconv.Itoa(e.PID) + " not found"
+}
+
+func (e *ProcessNotFoundError) Cause() error  { return ErrProcessNotFound }
+func (e *ProcessNotFoundError) Unwrap() error { return ErrProcessNotFound }
+
+// IsNotImplemented returns true if err or any error it wraps is
+// ErrNotImplemented.
+func IsNotImplemented(err error) bool {
+	return anyCause(err, func(err error) bool { return err == ErrNotImplemented })
+}
+
+// IsNotSupported returns true if err or any error it wraps is
+// ErrNotSupported.
+func IsNotSupported(err error) bool {
+	return anyCause(err, func(err error) bool { return err == ErrNotSupported })
+}
+
+// IsProcessNotFound returns true if err or any error it wraps is
+// ErrProcessNotFound.
+func IsProcessNotFound(err error) bool {
+	return anyCause(err, func(err error) bool { return err == ErrProcessNotFound })
+}
+
+// IsPermission returns true if err or any error it wraps reports missing
+// privileges (see os.IsPermission).
+func IsPermission(err error) bool {
+	return anyCause(err, os.IsPermission)
+}
+
+// anyCause returns true if match returns true for err or any of the errors
+// in its chain. It follows both Cause (github.com/pkg/errors) and Unwrap
+// (Go 1.13 errors) so that it works regardless of how the errors were
+// wrapped. The github.com/pkg/errors wrappers implement both, so on Go 1.13
+// and newer errors.Is and errors.As see the same chain.
+func anyCause(err error, match func(error) bool) bool {
+	for err != nil {
+		if match(err) {
+			return true
+		}
+		switch e := err.(type) {
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// PartialInfoError is returned together with a partially populated result
+// when some of the information could not be read, usually because of
+// missing privileges (e.g. the process belongs to another user, is a
+// protected process on Windows, or is covered by SIP on macOS). The result
+// is still valid; the missing fields are left empty.
+type PartialInfoError struct {
+	Missing []MissingField
+}
+
+// MissingField describes a field that could not be read.
+type MissingField struct {
+	Name string // JSON name of the field (e.g. cwd).
+	Err  error  // Reason why the field could not be read.
+}
+
+// Add records that the named field could not be read.
+func (e *PartialInfoError) Add(name string, err error) {
+	e.Missing = append(e.Missing, MissingField{Name: name, Err: err})
+}
+
+// Fields returns the names of the missing fields.
+func (e *PartialInfoError) Fields() []string {
+	names := make([]string, 0, len(e.Missing))
+	for _, m := range e.Missing {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// ErrOrNil returns e if any field is missing and nil otherwise.
+func (e *PartialInfoError) ErrOrNil() error {
+	if e == nil || len(e.Missing) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *PartialInfoError) Error() string {
+	msgs := make([]string, 0, len(e.Missing))
+	for _, m := range e.Missing {
+		msgs = append(msgs, m.Name+": "+m.Err.Error())
+	}
+	return "partial info (" + strings.Join(msgs, "; ") + ")"
+}
+
+// IsPartialInfo returns true if err is a PartialInfoError, meaning that the
+// result it was returned with is usable.
+func IsPartialInfo(err error) bool {
+	return anyCause(err, func(err error) bool {
+		_, ok := err.(*PartialInfoError)
+		return ok
+	})
+}
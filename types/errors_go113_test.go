@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build go1.13
+
+package types
+
+import (
+	stderrors "errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsIs(t *testing.T) {
+	err := errors.Wrap(errors.Wrap(&ProcessNotFoundError{PID: 42}, "inner"), "outer")
+	assert.True(t, stderrors.Is(err, ErrProcessNotFound))
+	var notFound *ProcessNotFoundError
+	assert.True(t, stderrors.As(err, &notFound))
+
+	assert.True(t, stderrors.Is(errors.Wrap(&NotSupportedError{Feature: "TPM"}, "failed"), ErrNotSupported))
+	assert.True(t, stderrors.Is(errors.Wrap(ErrNotImplemented, "failed"), ErrNotImplemented))
+
+	perm := errors.Wrap(&os.PathError{Op: "open", Path: "/proc/1/environ", Err: syscall.EACCES}, "failed")
+	assert.True(t, stderrors.Is(perm, os.ErrPermission))
+}
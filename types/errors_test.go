@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type unwrapper struct{ err error }
+
+func (e unwrapper) Error() string { return "wrapped: " + e.err.Error() }
+func (e unwrapper) Unwrap() error { return e.err }
+
+func TestIsNotImplemented(t *testing.T) {
+	assert.True(t, IsNotImplemented(ErrNotImplemented))
+	assert.True(t, IsNotImplemented(errors.Wrap(ErrNotImplemented, "failed")))
+	assert.True(t, IsNotImplemented(unwrapper{ErrNotImplemented}))
+	assert.False(t, IsNotImplemented(ErrNotSupported))
+	assert.False(t, IsNotImplemented(nil))
+}
+
+func TestNotSupportedError(t *testing.T) {
+	err := error(&NotSupportedError{Feature: "pressure stall information", Reason: "/proc/pressure does not exist"})
+	assert.EqualError(t, err, "pressure stall information is not supported: /proc/pressure does not exist")
+	assert.EqualError(t, &NotSupportedError{Feature: "TPM"}, "TPM is not supported")
+	assert.True(t, IsNotSupported(errors.Wrap(err, "failed")))
+	assert.False(t, IsNotImplemented(err))
+}
+
+func TestProcessNotFoundError(t *testing.T) {
+	err := error(&ProcessNotFoundError{PID: 42})
+	assert.EqualError(t, err, "process 42 not found")
+	assert.True(t, IsProcessNotFound(errors.Wrap(err, "failed")))
+	assert.True(t, IsProcessNotFound(errors.Wrapf(errors.Wrap(err, "failed"), "pid %d", 42)))
+	assert.True(t, IsProcessNotFound(unwrapper{errors.Wrap(err, "failed")}))
+	assert.False(t, IsProcessNotFound(errors.New("process 42 not found")))
+}
+
+func TestIsPermission(t *testing.T) {
+	err := &os.PathError{Op: "open", Path: "/proc/1/environ", Err: syscall.EACCES}
+	assert.True(t, IsPermission(err))
+	assert.True(t, IsPermission(errors.Wrap(err, "failed to read environment")))
+	assert.True(t, IsPermission(errors.Wrap(errors.Wrap(syscall.EPERM, "inner"), "outer")))
+	assert.True(t, IsPermission(unwrapper{syscall.EPERM}))
+	assert.False(t, IsPermission(&os.PathError{Op: "open", Path: "/proc/1/environ", Err: syscall.ENOENT}))
+}
+
+func TestIsPartialInfo(t *testing.T) {
+	var partial PartialInfoError
+	partial.Add("cwd", syscall.EACCES)
+	assert.True(t, IsPartialInfo(partial.ErrOrNil()))
+	assert.True(t, IsPartialInfo(errors.Wrap(&partial, "failed")))
+	assert.False(t, IsPartialInfo(syscall.EACCES))
+}
@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// FileSystemInfo describes a mounted file system and its usage. The usage
+// fields are zero if the file system could not be queried (e.g. a removable
+// drive without media).
+type FileSystemInfo struct {
+	Device     string `json:"device"`         // Device or source of the mount (e.g. /dev/sda1).
+	MountPoint string `json:"mount_point"`    // Path where the file system is mounted.
+	Type       string `json:"type,omitempty"` // File system type (e.g. ext4, apfs, NTFS).
+
+	Total     uint64 `json:"total_bytes"`     // Total size.
+	Used      uint64 `json:"used_bytes"`      // Total - Free
+	Free      uint64 `json:"free_bytes"`      // Free space including space reserved for privileged users.
+	Available uint64 `json:"available_bytes"` // Free space available to unprivileged users.
+
+	Inodes     uint64 `json:"inodes,omitempty"`      // Total number of inodes.
+	InodesUsed uint64 `json:"inodes_used,omitempty"` // Inodes - InodesFree
+	InodesFree uint64 `json:"inodes_free,omitempty"` // Number of free inodes.
+
+	// Encrypted is nil if it is unknown whether the volume is encrypted
+	// (e.g. network and virtual file systems).
+	Encrypted  *bool  `json:"encrypted,omitempty"`
+	Encryption string `json:"encryption,omitempty"` // Encryption in use (e.g. luks2, bitlocker, filevault).
+}
@@ -0,0 +1,37 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// GPUs is implemented by hosts that can list their graphics and compute
+// accelerator devices.
+type GPUs interface {
+	GPUs() ([]GPUInfo, error)
+}
+
+// GPUInfo describes a graphics or accelerator device. Fields are empty when
+// the OS does not report them (e.g. VRAM of NVIDIA GPUs on Linux requires
+// the vendor tools).
+type GPUInfo struct {
+	Vendor        string `json:"vendor,omitempty"`         // Vendor name (e.g. NVIDIA Corporation).
+	Model         string `json:"model,omitempty"`          // Device name (e.g. TU104GL [Tesla T4]).
+	PCIID         string `json:"pci_id,omitempty"`         // PCI vendor and device ID (e.g. 10de:1eb8).
+	BusID         string `json:"bus_id,omitempty"`         // PCI bus address (e.g. 0000:00:1e.0).
+	Driver        string `json:"driver,omitempty"`         // Kernel driver (e.g. nvidia, amdgpu, i915).
+	DriverVersion string `json:"driver_version,omitempty"` // Version of the driver.
+	VRAM          uint64 `json:"vram_bytes,omitempty"`     // Dedicated video memory.
+}
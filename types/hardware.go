@@ -0,0 +1,39 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// Hardware returns the hardware inventory of the host as reported by the
+// system firmware (SMBIOS/DMI).
+type Hardware interface {
+	Hardware() (*HardwareInfo, error)
+}
+
+// HardwareInfo identifies the hardware of the host. Fields are empty when
+// the firmware does not provide them or when they require privileges (e.g.
+// the serial number on Linux is only readable by root).
+type HardwareInfo struct {
+	Manufacturer   string `json:"manufacturer,omitempty"`    // System manufacturer (e.g. Dell Inc.).
+	ProductName    string `json:"product_name,omitempty"`    // System product name (e.g. PowerEdge R740).
+	ProductVersion string `json:"product_version,omitempty"` // System product version.
+	SerialNumber   string `json:"serial_number,omitempty"`   // System serial number.
+	UUID           string `json:"uuid,omitempty"`            // System UUID.
+	BIOSVendor     string `json:"bios_vendor,omitempty"`     // BIOS vendor.
+	BIOSVersion    string `json:"bios_version,omitempty"`    // BIOS version.
+	BIOSDate       string `json:"bios_date,omitempty"`       // BIOS release date (e.g. 03/17/2017).
+	ChassisType    string `json:"chassis_type,omitempty"`    // Chassis type (e.g. Desktop, Notebook, Rack Mount Chassis).
+}
@@ -23,26 +23,68 @@ type Host interface {
 	CPUTimer
 	Info() HostInfo
 	Memory() (*HostMemoryInfo, error)
+
+	// CPUTimePerCPU returns a CPUTimes structure for each
+	// logical CPU. The index of each entry is the CPU number.
+	CPUTimePerCPU() ([]CPUTimes, error)
 }
 
 type HostInfo struct {
-	Architecture      string    `json:"architecture"`            // Hardware architecture (e.g. x86_64, arm, ppc, mips).
-	BootTime          time.Time `json:"boot_time"`               // Host boot time.
-	Containerized     *bool     `json:"containerized,omitempty"` // Is the process containerized.
-	Hostname          string    `json:"name"`                    // Hostname
-	IPs               []string  `json:"ip,omitempty"`            // List of all IPs.
-	KernelVersion     string    `json:"kernel_version"`          // Kernel version.
-	MACs              []string  `json:"mac"`                     // List of MAC addresses.
-	OS                *OSInfo   `json:"os"`                      // OS information.
-	Timezone          string    `json:"timezone"`                // System timezone.
-	TimezoneOffsetSec int       `json:"timezone_offset_sec"`     // Timezone offset (seconds from UTC).
-	UniqueID          string    `json:"id,omitempty"`            // Unique ID of the host (optional).
+	Architecture      string         `json:"architecture"`            // Hardware architecture (e.g. x86_64, arm, ppc, mips).
+	BootTime          time.Time      `json:"boot_time"`               // Host boot time.
+	Containerized     *bool          `json:"containerized,omitempty"` // Is the process containerized.
+	Container         *ContainerInfo `json:"container,omitempty"`     // Container metadata (only when containerized).
+	Hostname          string         `json:"name"`                    // Hostname
+	IPs               []string       `json:"ip,omitempty"`            // List of all IPs.
+	KernelVersion     string         `json:"kernel_version"`          // Kernel version.
+	MACs              []string       `json:"mac"`                     // List of MAC addresses.
+	OS                *OSInfo        `json:"os"`                      // OS information.
+	Partition         *PartitionInfo `json:"partition,omitempty"`     // Logical partition metadata (AIX only).
+	Timezone          string         `json:"timezone"`                // System timezone.
+	TimezoneOffsetSec int            `json:"timezone_offset_sec"`     // Timezone offset (seconds from UTC).
+	UniqueID          string         `json:"id,omitempty"`            // Unique ID of the host (optional).
+	UniqueIDSource    string         `json:"id_source,omitempty"`     // Source of UniqueID (e.g. machine-id).
 }
 
 func (host HostInfo) Uptime() time.Duration {
 	return time.Since(host.BootTime)
 }
 
+// ContainerInfo contains information about the container in which the
+// process is running. Fields are empty when they cannot be determined.
+type ContainerInfo struct {
+	Runtime    string `json:"runtime,omitempty"`     // Container runtime (e.g. docker, containerd, cri-o, podman, lxc).
+	ID         string `json:"id,omitempty"`          // Container ID (or name for lxc).
+	CgroupPath string `json:"cgroup_path,omitempty"` // Path of the container's cgroup.
+	Isolation  string `json:"isolation,omitempty"`   // Isolation mode of a Windows container (process or hyperv).
+}
+
+// Container runtimes reported in ContainerInfo.
+const (
+	ContainerRuntimeDocker     = "docker"
+	ContainerRuntimeContainerd = "containerd"
+	ContainerRuntimeCRIO       = "cri-o"
+	ContainerRuntimePodman     = "podman"
+	ContainerRuntimeLXC        = "lxc"
+	ContainerRuntimeWPAR       = "wpar" // AIX workload partition. The ID is the WPAR name.
+)
+
+// PartitionInfo describes the logical partition (LPAR) of an AIX host. A
+// workload partition (WPAR) inside of it is reported as a container.
+type PartitionInfo struct {
+	Name             string  `json:"name,omitempty"`              // Partition name.
+	ID               int     `json:"id"`                          // Partition number.
+	Shared           bool    `json:"shared"`                      // Uses the shared processor pool.
+	EntitledCapacity float64 `json:"entitled_capacity,omitempty"` // Entitled processor capacity in processing units.
+	SMTThreads       int     `json:"smt_threads,omitempty"`       // Hardware threads per core (1 when SMT is off).
+}
+
+// Windows container isolation modes reported in ContainerInfo.
+const (
+	ContainerIsolationProcess = "process" // Shares the kernel of the host.
+	ContainerIsolationHyperV  = "hyperv"  // Runs in a lightweight utility VM.
+)
+
 type OSInfo struct {
 	Family   string `json:"family"`             // OS Family (e.g. redhat, debian, freebsd, windows).
 	Platform string `json:"platform"`           // OS platform (e.g. centos, ubuntu, windows).
@@ -53,10 +95,64 @@ type OSInfo struct {
 	Patch    int    `json:"patch"`              // Patch release version.
 	Build    string `json:"build,omitempty"`    // Build (e.g. 16G1114).
 	Codename string `json:"codename,omitempty"` // OS codename (e.g. jessie).
+
+	// VersionExtra is a supplemental version suffix (e.g. "(c)" for a macOS
+	// Rapid Security Response).
+	VersionExtra string `json:"version_extra,omitempty"`
+
+	// Fields from the optional os-release(5) keys.
+	Variant      string `json:"variant,omitempty"`       // OS variant (e.g. Silverblue, Server Edition).
+	ImageID      string `json:"image_id,omitempty"`      // ID of the image the OS was deployed from.
+	ImageVersion string `json:"image_version,omitempty"` // Version of the image the OS was deployed from.
+
+	// Immutable is true for image based distributions with a read-only root
+	// filesystem (e.g. Fedora Silverblue, Flatcar, Bottlerocket, Talos).
+	Immutable bool `json:"immutable,omitempty"`
+
+	// Windows specific fields.
+	Revision         int        `json:"revision,omitempty"`          // Update Build Revision (UBR).
+	DisplayVersion   string     `json:"display_version,omitempty"`   // Feature update version (e.g. 23H2).
+	Edition          string     `json:"edition,omitempty"`           // Edition ID (e.g. Professional, ServerStandard).
+	InstallationType string     `json:"installation_type,omitempty"` // Installation type (e.g. Client, Server Core).
+	InstallDate      *time.Time `json:"install_date,omitempty"`      // Time when the OS was installed.
 }
 
+// Sources of machine IDs. IDs that are generated by the OS (e.g. machine-id
+// and machine-guid) are copied when a disk image is cloned, while firmware
+// IDs (e.g. dmi-uuid) change with each virtual machine.
+const (
+	MachineIDSourceSystemd      = "machine-id"      // /etc/machine-id (Linux).
+	MachineIDSourceDBus         = "dbus-machine-id" // /var/lib/dbus/machine-id (Linux).
+	MachineIDSourceDMI          = "dmi-uuid"        // SMBIOS system UUID.
+	MachineIDSourceMachineGUID  = "machine-guid"    // HKLM\SOFTWARE\Microsoft\Cryptography\MachineGuid (Windows).
+	MachineIDSourcePlatformUUID = "platform-uuid"   // IOPlatformUUID (macOS).
+	MachineIDSourceHostUUID     = "hostuuid"        // kern.hostuuid (FreeBSD).
+	MachineIDSourceHostID       = "hostid"          // gethostid(3) (Solaris).
+	MachineIDSourceCombined     = "combined"        // Hash of all available sources.
+)
+
+// MachineIdentifier lists all of the IDs of the host that can be read, in
+// the order of preference. The first ID is used as HostInfo.UniqueID.
+type MachineIdentifier interface {
+	MachineIDs() ([]MachineIDInfo, error)
+}
+
+// MachineIDInfo is an ID of the host and the source it was read from.
+type MachineIDInfo struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+// Uptime returns the time elapsed since the host booted. Unlike
+// HostInfo.Uptime it is read from a monotonic clock so consecutive samples are
+// not affected by wall clock adjustments.
+type Uptime interface {
+	Uptime() (time.Duration, error)
+}
+
+// LoadAverage returns the 1, 5, and 15 minute system load averages.
 type LoadAverage interface {
-	LoadAverage() LoadAverageInfo
+	LoadAverage() (*LoadAverageInfo, error)
 }
 
 type LoadAverageInfo struct {
@@ -65,6 +161,263 @@ type LoadAverageInfo struct {
 	Fifteen float64 `json:"fifteen_min"`
 }
 
+// PressureStats reports resource pressure. On Linux it contains the Pressure
+// Stall Information (PSI) from /proc/pressure (kernel 4.20+). On macOS only
+// the memory pressure level is reported.
+type PressureStats interface {
+	PressureStats() (*PressureInfo, error)
+}
+
+// PressureInfo contains the pressure of each resource. A resource is nil
+// when the platform does not report it.
+type PressureInfo struct {
+	CPU    *ResourcePressure `json:"cpu,omitempty"`
+	Memory *ResourcePressure `json:"memory,omitempty"`
+	IO     *ResourcePressure `json:"io,omitempty"`
+
+	// MemoryLevel is the memory pressure level (normal, warning, or critical)
+	// reported by macOS.
+	MemoryLevel string `json:"memory_level,omitempty"`
+}
+
+// ResourcePressure contains the share of time in which tasks were stalled
+// waiting for a resource.
+type ResourcePressure struct {
+	Some PressureStall  `json:"some"`           // At least one task was stalled.
+	Full *PressureStall `json:"full,omitempty"` // All non-idle tasks were stalled at the same time.
+}
+
+// PressureStall contains stall percentages averaged over 10, 60, and 300
+// second windows and the cumulative stall time.
+type PressureStall struct {
+	Avg10  float64       `json:"avg10"`
+	Avg60  float64       `json:"avg60"`
+	Avg300 float64       `json:"avg300"`
+	Total  time.Duration `json:"total"`
+}
+
+// Memory pressure levels.
+const (
+	MemoryPressureNormal   = "normal"
+	MemoryPressureWarning  = "warning"
+	MemoryPressureCritical = "critical"
+)
+
+// VMStat returns cumulative virtual memory and scheduler activity counters
+// since boot.
+type VMStat interface {
+	VMStat() (*VMStatInfo, error)
+}
+
+// VMStatInfo contains counters since boot. A counter is zero when the
+// platform does not report it.
+type VMStatInfo struct {
+	PageIn          uint64 `json:"page_in_bytes"`     // Bytes paged in from disk.
+	PageOut         uint64 `json:"page_out_bytes"`    // Bytes paged out to disk.
+	SwapIn          uint64 `json:"swap_in_bytes"`     // Bytes swapped in.
+	SwapOut         uint64 `json:"swap_out_bytes"`    // Bytes swapped out.
+	PageFaults      uint64 `json:"page_faults"`       // Number of page faults.
+	MajorPageFaults uint64 `json:"major_page_faults"` // Number of page faults that required disk I/O.
+	ContextSwitches uint64 `json:"context_switches"`  // Number of context switches.
+	Interrupts      uint64 `json:"interrupts"`        // Number of interrupts serviced.
+	Forks           uint64 `json:"forks,omitempty"`   // Number of processes created.
+}
+
+// HugePages reports the huge page (large page on Windows) configuration of
+// the host.
+type HugePages interface {
+	HugePages() (*HugePagesInfo, error)
+}
+
+// HugePagesInfo describes the huge page pools and transparent huge page mode.
+type HugePagesInfo struct {
+	Pools []HugePagePoolInfo `json:"pools,omitempty"` // One pool per supported page size.
+
+	// Transparent huge page settings on Linux (always, madvise, or never).
+	TransparentEnabled string `json:"transparent_enabled,omitempty"`
+	TransparentDefrag  string `json:"transparent_defrag,omitempty"`
+}
+
+// HugePagePoolInfo describes a pool of huge pages of a single size. On
+// Windows only the large page size is reported.
+type HugePagePoolInfo struct {
+	Size     uint64 `json:"size_bytes"`         // Page size in bytes.
+	Total    uint64 `json:"total"`              // Number of pages in the pool.
+	Free     uint64 `json:"free"`               // Number of pages not allocated.
+	Reserved uint64 `json:"reserved,omitempty"` // Pages committed to a mapping but not yet faulted in.
+	Surplus  uint64 `json:"surplus,omitempty"`  // Pages allocated above Total by overcommit.
+}
+
+// ClockInfo reports the time synchronization status of the host clock.
+type ClockInfo interface {
+	ClockInfo() (*ClockStatus, error)
+}
+
+// ClockStatus describes the host clock. Offset and error estimates are zero
+// when the platform does not report them (Windows).
+type ClockStatus struct {
+	Source         string        `json:"source,omitempty"`          // Clock source on Linux (e.g. tsc), time provider on Windows (e.g. NTP, NT5DS), or NTP server on macOS.
+	Synchronized   bool          `json:"synchronized"`              // The clock is disciplined by a time synchronization service.
+	Offset         time.Duration `json:"offset,omitempty"`          // Estimated offset from the reference clock.
+	Frequency      float64       `json:"frequency_ppm,omitempty"`   // Frequency correction (drift) in parts per million.
+	MaxError       time.Duration `json:"max_error,omitempty"`       // Maximum error.
+	EstimatedError time.Duration `json:"estimated_error,omitempty"` // Estimated error.
+	TZDataVersion  string        `json:"tzdata_version,omitempty"`  // Version of the time zone database (e.g. 2024a).
+}
+
+// KernelCmdline returns the parameters the kernel was booted with. These are
+// the contents of /proc/cmdline on Linux, the boot-args on macOS, and the
+// settings of the default boot entry in the BCD store on Windows.
+type KernelCmdline interface {
+	KernelCmdline() (*KernelCmdlineInfo, error)
+}
+
+// KernelCmdlineInfo contains the boot parameters in the order they were given.
+type KernelCmdlineInfo struct {
+	Raw        string               `json:"raw,omitempty"` // Unparsed command line. Empty on Windows.
+	Parameters []KernelCmdlineParam `json:"parameters"`
+}
+
+// KernelCmdlineParam is a single boot parameter. Value is empty for flags
+// such as nokaslr.
+type KernelCmdlineParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// Get returns the value of the last parameter with the given name. The
+// boolean is false when the parameter is not present.
+func (c *KernelCmdlineInfo) Get(name string) (string, bool) {
+	for i := len(c.Parameters) - 1; i >= 0; i-- {
+		if c.Parameters[i].Name == name {
+			return c.Parameters[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Entropy reports the state of the kernel random number generator. It is
+// implemented by Host on Linux.
+type Entropy interface {
+	Entropy() (*EntropyInfo, error)
+}
+
+// EntropyInfo describes the kernel entropy pool. Since Linux 5.18 the pool
+// always reports 256 bits once it is initialized.
+type EntropyInfo struct {
+	Available int   `json:"available_bits"`  // Bits of entropy in the input pool.
+	PoolSize  int   `json:"pool_size_bits"`  // Size of the input pool in bits.
+	Ready     *bool `json:"ready,omitempty"` // getrandom(2) will not block. Nil when it could not be determined.
+}
+
+// RebootRequired reports whether the host has pending changes (e.g. installed
+// updates) that take effect after a reboot.
+type RebootRequired interface {
+	RebootRequired() (*RebootRequiredInfo, error)
+}
+
+// RebootRequiredInfo contains the result of the pending reboot checks.
+type RebootRequiredInfo struct {
+	Required bool     `json:"required"`
+	Reasons  []string `json:"reasons,omitempty"`  // Description of each check that found a pending reboot.
+	Packages []string `json:"packages,omitempty"` // Packages that requested the reboot when known.
+}
+
+// SessionEnumerator lists the users that are currently logged in to the host.
+type SessionEnumerator interface {
+	Sessions() ([]SessionInfo, error)
+}
+
+// SessionInfo describes a login session.
+type SessionInfo struct {
+	User       string    `json:"user"`                  // Name of the logged in user.
+	Terminal   string    `json:"terminal,omitempty"`    // Terminal (e.g. pts/0) or window station name on Windows (e.g. RDP-Tcp#0).
+	RemoteHost string    `json:"remote_host,omitempty"` // Host name or address of the client for remote sessions.
+	LoginTime  time.Time `json:"login_time"`            // Time when the session started.
+	PID        int       `json:"pid,omitempty"`         // PID of the login process (not reported on Windows).
+	ID         uint32    `json:"id,omitempty"`          // Session ID (only reported on Windows).
+}
+
+// LoadedModules lists the kernel modules (Linux), drivers (Windows), or
+// kernel extensions (macOS) that are loaded.
+type LoadedModules interface {
+	LoadedModules() ([]KernelModuleInfo, error)
+}
+
+// KernelModuleInfo describes a loaded kernel module.
+type KernelModuleInfo struct {
+	Name     string   `json:"name"`               // Module name (bundle identifier on macOS).
+	Path     string   `json:"path,omitempty"`     // Path of the module file when known.
+	Version  string   `json:"version,omitempty"`  // Version declared by the module.
+	Address  uint64   `json:"address,omitempty"`  // Load address (zero when hidden from unprivileged users).
+	Size     uint64   `json:"size,omitempty"`     // Size of the module in memory.
+	RefCount int      `json:"ref_count"`          // Number of references held on the module.
+	UsedBy   []string `json:"used_by,omitempty"`  // Modules that depend on this module (Linux only).
+	State    string   `json:"state,omitempty"`    // Load state (e.g. Live, Loading, Unloading on Linux).
+	Taint    string   `json:"taint,omitempty"`    // Linux taint flags (e.g. O for out-of-tree, E for unsigned).
+	Unsigned bool     `json:"unsigned,omitempty"` // True if the kernel reported that the module signature is missing or invalid.
+}
+
+// ServiceUnits lists the units that are loaded by the systemd service manager.
+type ServiceUnits interface {
+	ServiceUnits() ([]ServiceUnitInfo, error)
+}
+
+// ServiceUnitInfo describes a systemd unit.
+type ServiceUnitInfo struct {
+	Name        string `json:"name"`                  // Unit name (e.g. ssh.service).
+	Description string `json:"description,omitempty"` // Human readable description.
+	LoadState   string `json:"load_state"`            // Whether the unit file was loaded (e.g. loaded, not-found).
+	ActiveState string `json:"active_state"`          // High level state (e.g. active, inactive, failed).
+	SubState    string `json:"sub_state"`             // Unit type specific state (e.g. running, exited).
+}
+
+// KernelParameters reads kernel tunables. Names use the sysctl notation on
+// Linux, macOS, and FreeBSD (e.g. net.ipv4.ip_forward) and registry paths
+// relative to HKLM\SYSTEM\CurrentControlSet on Windows (e.g.
+// Services\Tcpip\Parameters\IPEnableRouter). Numeric values are formatted
+// in decimal and multiple values are separated by whitespace.
+type KernelParameters interface {
+	// KernelParam returns the value of the named parameter.
+	KernelParam(name string) (string, error)
+
+	// KernelParams returns the values of all readable parameters. On Windows
+	// only a selection of security relevant values is returned.
+	KernelParams() (map[string]string, error)
+}
+
+// HandleCounts reports the number of handles, processes, and threads that
+// exist on the host. It is implemented by Host on Windows.
+type HandleCounts interface {
+	HandleCounts() (*HandleCountsInfo, error)
+}
+
+// HandleCountsInfo contains host wide handle totals.
+type HandleCountsInfo struct {
+	Handles   uint64 `json:"handles"`   // Number of open kernel object handles.
+	Processes uint64 `json:"processes"` // Number of processes.
+	Threads   uint64 `json:"threads"`   // Number of threads.
+}
+
+// DiskIOCounters returns I/O statistics for each block device.
+type DiskIOCounters interface {
+	DiskIOCounters() ([]DiskIOInfo, error)
+}
+
+// DiskIOInfo contains the cumulative I/O statistics of a block device. Times
+// are the total time spent by all requests so they can exceed wall time.
+type DiskIOInfo struct {
+	Name          string        `json:"name"`              // Device name (e.g. sda on Linux, C: on Windows).
+	ReadCount     uint64        `json:"read_count"`        // Number of completed reads.
+	WriteCount    uint64        `json:"write_count"`       // Number of completed writes.
+	ReadBytes     uint64        `json:"read_bytes"`        // Number of bytes read.
+	WriteBytes    uint64        `json:"write_bytes"`       // Number of bytes written.
+	ReadTime      time.Duration `json:"read_time"`         // Time spent reading.
+	WriteTime     time.Duration `json:"write_time"`        // Time spent writing.
+	IOTime        time.Duration `json:"io_time,omitempty"` // Time the device was busy doing I/O.
+	IOsInProgress uint64        `json:"ios_in_progress"`   // Number of requests in flight (queue depth).
+}
+
 // HostMemoryInfo (all values are specified in bytes).
 type HostMemoryInfo struct {
 	Total        uint64            `json:"total_bytes"`         // Total physical memory.
@@ -75,4 +428,18 @@ type HostMemoryInfo struct {
 	VirtualUsed  uint64            `json:"virtual_used_bytes"`  // VirtualTotal - VirtualFree
 	VirtualFree  uint64            `json:"virtual_free_bytes"`  // Virtual memory that is not used.
 	Metrics      map[string]uint64 `json:"raw,omitempty"`       // Other memory related metrics.
+
+	// Normalized breakdown of memory usage. A field is zero when the
+	// platform does not report it. The raw values remain in Metrics.
+	Cached     uint64 `json:"cached_bytes,omitempty"`      // File cache (page cache on Linux, file-backed pages on macOS, system cache on Windows).
+	Buffers    uint64 `json:"buffers_bytes,omitempty"`     // Block device and filesystem metadata buffers.
+	Slab       uint64 `json:"slab_bytes,omitempty"`        // Kernel data structure caches (slab on Linux, pool on Windows).
+	PageTables uint64 `json:"page_tables_bytes,omitempty"` // Memory used by page tables.
+	Dirty      uint64 `json:"dirty_bytes,omitempty"`       // Memory waiting to be written back to disk.
+	SwapIn     uint64 `json:"swap_in_bytes,omitempty"`     // Cumulative bytes swapped in since boot.
+	SwapOut    uint64 `json:"swap_out_bytes,omitempty"`    // Cumulative bytes swapped out since boot.
+
+	HugePagesTotal uint64 `json:"hugepages_total,omitempty"`     // Number of huge pages in the pool.
+	HugePagesFree  uint64 `json:"hugepages_free,omitempty"`      // Number of huge pages that are not allocated.
+	HugePageSize   uint64 `json:"hugepage_size_bytes,omitempty"` // Size of a huge page.
 }
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// JobObject reports the Windows job object that a process is assigned to.
+// Job objects are the Windows counterpart of Linux control groups and are
+// used to constrain Windows containers. It is implemented by Process on
+// Windows.
+type JobObject interface {
+	JobObject() (*JobObjectInfo, error)
+}
+
+// JobObjectInfo describes the job object membership of a process.
+type JobObjectInfo struct {
+	InJob bool `json:"in_job"` // True if the process is assigned to a job.
+
+	// Limits and Usage are only available for the current process because
+	// Windows provides no way to open the job of another process. They are
+	// nil otherwise and when the process is not in a job.
+	Limits *JobObjectLimits `json:"limits,omitempty"`
+	Usage  *JobObjectUsage  `json:"usage,omitempty"`
+}
+
+// JobObjectLimits contains the resource limits of a job object. Limits that
+// are not set are nil.
+type JobObjectLimits struct {
+	CPURate        *float64       `json:"cpu_rate_pct,omitempty"`         // Hard cap on the CPU usage in percent of all CPUs.
+	CPUWeight      *uint32        `json:"cpu_weight,omitempty"`           // Relative CPU weight (1-9) when the job is weight based.
+	ProcessMemory  *uint64        `json:"process_memory_bytes,omitempty"` // Committed memory limit of each process.
+	JobMemory      *uint64        `json:"job_memory_bytes,omitempty"`     // Committed memory limit of all processes in the job.
+	ActiveProcess  *uint32        `json:"active_processes,omitempty"`     // Maximum number of simultaneously active processes.
+	ProcessorMask  *uint64        `json:"processor_mask,omitempty"`       // Processor affinity mask of the processes.
+	ProcessCPUTime *time.Duration `json:"process_cpu_time,omitempty"`     // User-mode CPU time limit of each process.
+	JobCPUTime     *time.Duration `json:"job_cpu_time,omitempty"`         // User-mode CPU time limit of the job.
+}
+
+// JobObjectUsage contains the accounting data of a job object.
+type JobObjectUsage struct {
+	UserTime          time.Duration `json:"user_time"`                 // Total user time of all processes.
+	SystemTime        time.Duration `json:"system_time"`               // Total kernel time of all processes.
+	PageFaults        uint64        `json:"page_faults"`               // Total page faults of all processes.
+	Processes         uint64        `json:"processes"`                 // Number of processes ever in the job.
+	ActiveProcesses   uint64        `json:"active_processes"`          // Number of processes currently in the job.
+	PeakProcessMemory uint64        `json:"peak_process_memory_bytes"` // Peak committed memory of any process.
+	PeakJobMemory     uint64        `json:"peak_job_memory_bytes"`     // Peak committed memory of the job.
+}
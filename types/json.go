@@ -0,0 +1,118 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timeOrNil returns nil for the zero time so that it is omitted from JSON.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// MarshalJSON omits the boot time if it is unknown.
+func (host HostInfo) MarshalJSON() ([]byte, error) {
+	type hostInfo HostInfo
+	return json.Marshal(struct {
+		hostInfo
+		BootTime *time.Time `json:"boot_time,omitempty"`
+	}{hostInfo(host), timeOrNil(host.BootTime)})
+}
+
+// MarshalJSON omits the start time if it is unknown.
+func (p ProcessInfo) MarshalJSON() ([]byte, error) {
+	type processInfo ProcessInfo
+	return json.Marshal(struct {
+		processInfo
+		StartTime *time.Time `json:"start_time,omitempty"`
+	}{processInfo(p), timeOrNil(p.StartTime)})
+}
+
+// MarshalJSON omits the login time if it is unknown.
+func (s SessionInfo) MarshalJSON() ([]byte, error) {
+	type sessionInfo SessionInfo
+	return json.Marshal(struct {
+		sessionInfo
+		LoginTime *time.Time `json:"login_time,omitempty"`
+	}{sessionInfo(s), timeOrNil(s.LoginTime)})
+}
+
+// MarshalJSON omits the install time if it is unknown.
+func (p PackageInfo) MarshalJSON() ([]byte, error) {
+	type packageInfo PackageInfo
+	return json.Marshal(struct {
+		packageInfo
+		InstallTime *time.Time `json:"install_time,omitempty"`
+	}{packageInfo(p), timeOrNil(p.InstallTime)})
+}
+
+// MarshalJSON omits the install time if it is unknown.
+func (p OSPatchInfo) MarshalJSON() ([]byte, error) {
+	type osPatchInfo OSPatchInfo
+	return json.Marshal(struct {
+		osPatchInfo
+		InstallTime *time.Time `json:"install_time,omitempty"`
+	}{osPatchInfo(p), timeOrNil(p.InstallTime)})
+}
+
+// Flatten encodes v as JSON and returns its fields as a flat map. The keys
+// are the dotted paths of the fields (e.g. os.family) prefixed by prefix and
+// a dot if prefix isn't empty, so Flatten("host", info) returns ECS-style
+// keys like host.os.family. Arrays are not flattened. Null values and empty
+// objects are omitted and numbers are returned as json.Number so that large
+// counters keep their precision. v must encode to a JSON object.
+func Flatten(prefix string, v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err = dec.Decode(&obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to flatten %T", v)
+	}
+
+	flat := map[string]interface{}{}
+	flattenObject(flat, prefix, obj)
+	return flat, nil
+}
+
+func flattenObject(flat map[string]interface{}, prefix string, obj map[string]interface{}) {
+	for k, v := range obj {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			flattenObject(flat, k, v)
+		default:
+			flat[k] = v
+		}
+	}
+}
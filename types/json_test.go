@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSONOmitsUnknownTimes(t *testing.T) {
+	data, err := json.Marshal(PackageInfo{Name: "bash", Version: "4.4", Type: PackageTypeDpkg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"name":"bash","version":"4.4","type":"dpkg"}`, string(data))
+
+	start := time.Date(2018, 3, 1, 12, 30, 0, 500, time.UTC)
+	data, err = json.Marshal(ProcessInfo{Name: "init", PID: 1, StartTime: start, State: ProcessStateSleeping})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"name":"init","pid":1,"ppid":0,"cwd":"","exe":"","args":null,
+		"start_time":"2018-03-01T12:30:00.0000005Z","state":"sleeping"}`, string(data))
+
+	// Pointers are encoded the same way.
+	data, err = json.Marshal(&SessionInfo{User: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"user":"root"}`, string(data))
+}
+
+func TestMarshalJSONDurations(t *testing.T) {
+	data, err := json.Marshal(CPUTimes{User: 1500 * time.Millisecond, System: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"user":1500000000,"system":1000000000}`, string(data))
+}
+
+func TestFlatten(t *testing.T) {
+	info := HostInfo{
+		Architecture: "x86_64",
+		Hostname:     "web01",
+		MACs:         []string{"00:11:22:33:44:55"},
+		OS:           &OSInfo{Family: "debian", Name: "Ubuntu"},
+	}
+
+	flat, err := Flatten("host", info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "x86_64", flat["host.architecture"])
+	assert.Equal(t, "web01", flat["host.name"])
+	assert.Equal(t, "debian", flat["host.os.family"])
+	assert.Equal(t, []interface{}{"00:11:22:33:44:55"}, flat["host.mac"])
+	assert.Equal(t, json.Number("0"), flat["host.timezone_offset_sec"])
+	assert.NotContains(t, flat, "host.os")
+	assert.NotContains(t, flat, "host.boot_time")
+
+	flat, err = Flatten("", HostMemoryInfo{Total: 1<<64 - 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, json.Number("18446744073709551615"), flat["total_bytes"])
+
+	_, err = Flatten("", []string{"a"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "net"
+
+// Network provides information about the network stack of the host.
+type Network interface {
+	// Connections returns the sockets of the given kind (see the
+	// ConnectionKind constants) on the host.
+	Connections(kind string) ([]NetworkConnection, error)
+}
+
+// ConnectionEnumerator lists the network connections of a process.
+type ConnectionEnumerator interface {
+	Connections() ([]NetworkConnection, error)
+}
+
+// NetworkConnection describes a TCP, UDP, or Unix domain socket.
+type NetworkConnection struct {
+	Family     string `json:"family"`                // Address family (ipv4, ipv6, or unix).
+	Type       string `json:"type"`                  // Transport protocol (tcp, udp, or unix).
+	LocalIP    net.IP `json:"local_ip,omitempty"`    // Local address.
+	LocalPort  int    `json:"local_port,omitempty"`  // Local port.
+	RemoteIP   net.IP `json:"remote_ip,omitempty"`   // Remote address (empty when not connected).
+	RemotePort int    `json:"remote_port,omitempty"` // Remote port (zero when not connected).
+	Path       string `json:"path,omitempty"`        // Path of a Unix domain socket.
+	State      string `json:"state,omitempty"`       // Connection state (e.g. ESTABLISHED).
+	PID        int    `json:"pid,omitempty"`         // PID of the owning process (zero if unknown).
+	UID        string `json:"uid,omitempty"`         // Owner of the socket (Linux only).
+	Inode      uint64 `json:"inode,omitempty"`       // Socket inode (Linux only).
+	NetNS      uint64 `json:"netns,omitempty"`       // Inode of the network namespace (Linux only).
+}
+
+// ListeningPort is a TCP socket in the listen state or an unconnected UDP
+// socket together with the process that owns it.
+type ListeningPort struct {
+	NetworkConnection
+	Process *ProcessInfo `json:"process,omitempty"` // Owning process (nil if it is unknown or has exited).
+}
+
+// Address families reported in NetworkConnection.
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+	FamilyUnix = "unix"
+)
+
+// Transport protocols reported in NetworkConnection.
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+	ProtocolUnix = "unix"
+)
+
+// Kinds of connections that can be requested from Network.Connections.
+const (
+	ConnectionKindAll  = "all"  // All sockets.
+	ConnectionKindInet = "inet" // TCP and UDP sockets over IPv4 and IPv6.
+	ConnectionKindTCP  = "tcp"  // TCP sockets over IPv4 and IPv6.
+	ConnectionKindTCP4 = "tcp4" // TCP sockets over IPv4.
+	ConnectionKindTCP6 = "tcp6" // TCP sockets over IPv6.
+	ConnectionKindUDP  = "udp"  // UDP sockets over IPv4 and IPv6.
+	ConnectionKindUDP4 = "udp4" // UDP sockets over IPv4.
+	ConnectionKindUDP6 = "udp6" // UDP sockets over IPv6.
+	ConnectionKindUnix = "unix" // Unix domain sockets.
+)
+
+// TCP connection states reported in NetworkConnection.
+const (
+	TCPStateEstablished = "ESTABLISHED"
+	TCPStateSynSent     = "SYN_SENT"
+	TCPStateSynRecv     = "SYN_RECV"
+	TCPStateFinWait1    = "FIN_WAIT1"
+	TCPStateFinWait2    = "FIN_WAIT2"
+	TCPStateTimeWait    = "TIME_WAIT"
+	TCPStateClose       = "CLOSE"
+	TCPStateCloseWait   = "CLOSE_WAIT"
+	TCPStateLastAck     = "LAST_ACK"
+	TCPStateListen      = "LISTEN"
+	TCPStateClosing     = "CLOSING"
+	TCPStateDeleteTCB   = "DELETE_TCB"
+)
+
+// Unix domain socket states reported in NetworkConnection.
+const (
+	UnixStateListen    = "LISTEN"
+	UnixStateConnected = "CONNECTED"
+)
+
+// NetworkInterfaces returns the configuration and statistics of the network
+// interfaces.
+type NetworkInterfaces interface {
+	NetworkInterfaces() ([]NetworkInterfaceInfo, error)
+}
+
+// NetworkInterfaceInfo describes a network interface.
+type NetworkInterfaceInfo struct {
+	Index     int      `json:"index"`
+	Name      string   `json:"name"`
+	MTU       int      `json:"mtu"`
+	MAC       string   `json:"mac,omitempty"`
+	Flags     []string `json:"flags,omitempty"`     // Interface flags (e.g. up, broadcast, loopback).
+	Addresses []string `json:"addresses,omitempty"` // Assigned addresses in CIDR notation.
+
+	// Counters is nil when the statistics of the interface are not available.
+	Counters *NetworkInterfaceCounters `json:"counters,omitempty"`
+}
+
+// NetworkInterfaceCounters contains the cumulative traffic statistics of a
+// network interface.
+type NetworkInterfaceCounters struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// Routes returns the routing table of the host.
+type Routes interface {
+	Routes() ([]RouteInfo, error)
+}
+
+// RouteInfo describes an entry of the routing table.
+type RouteInfo struct {
+	Family       string `json:"family"`              // Address family (ipv4 or ipv6).
+	Destination  net.IP `json:"destination"`         // Destination network address.
+	PrefixLength int    `json:"prefix_length"`       // Length of the destination network prefix (zero for the default route).
+	Gateway      net.IP `json:"gateway,omitempty"`   // Next hop (empty for directly connected networks).
+	Source       net.IP `json:"source,omitempty"`    // Preferred source address (Linux only).
+	Interface    string `json:"interface,omitempty"` // Name of the outgoing interface.
+	Metric       int    `json:"metric"`              // Route metric (lower values are preferred).
+	Table        int    `json:"table,omitempty"`     // Routing table ID (Linux only).
+}
+
+// Neighbors returns the neighbor table of the host. It contains the ARP
+// entries for IPv4 and the NDP entries for IPv6.
+type Neighbors interface {
+	Neighbors() ([]NeighborInfo, error)
+}
+
+// NeighborInfo describes an entry of the ARP or NDP neighbor table.
+type NeighborInfo struct {
+	Family    string `json:"family"`              // Address family (ipv4 or ipv6).
+	IP        net.IP `json:"ip"`                  // Address of the neighbor.
+	MAC       string `json:"mac,omitempty"`       // Link layer address (empty when unresolved).
+	Interface string `json:"interface,omitempty"` // Name of the interface the neighbor is reachable through.
+	State     string `json:"state,omitempty"`     // Reachability state (see the NeighborState constants).
+}
+
+// Neighbor reachability states reported in NeighborInfo.
+const (
+	NeighborStateIncomplete  = "incomplete"
+	NeighborStateReachable   = "reachable"
+	NeighborStateStale       = "stale"
+	NeighborStateDelay       = "delay"
+	NeighborStateProbe       = "probe"
+	NeighborStateFailed      = "failed"
+	NeighborStatePermanent   = "permanent"
+	NeighborStateUnreachable = "unreachable"
+)
+
+// DNSConfig returns the DNS resolver configuration of the host.
+type DNSConfig interface {
+	DNSConfig() (*DNSConfigInfo, error)
+}
+
+// DNSConfigInfo describes the DNS resolver configuration of the host.
+type DNSConfigInfo struct {
+	Nameservers   []string          `json:"nameservers,omitempty"`    // Nameservers used for queries that no scoped resolver handles.
+	SearchDomains []string          `json:"search_domains,omitempty"` // Domains appended to names that are not fully qualified.
+	Options       []string          `json:"options,omitempty"`        // Resolver options from resolv.conf (e.g. ndots:2).
+	Manager       string            `json:"manager,omitempty"`        // Local service that manages name resolution (e.g. systemd-resolved).
+	Resolvers     []DNSResolverInfo `json:"resolvers,omitempty"`      // Per-interface and scoped resolvers.
+}
+
+// DNSResolverInfo describes the DNS configuration of an interface or a
+// resolver that is scoped to specific domains.
+type DNSResolverInfo struct {
+	Interface     string   `json:"interface,omitempty"`      // Name of the interface the resolver is bound to.
+	Nameservers   []string `json:"nameservers,omitempty"`    // Nameservers of the resolver.
+	SearchDomains []string `json:"search_domains,omitempty"` // Search domains of the resolver.
+	Domains       []string `json:"domains,omitempty"`        // Domains routed to this resolver (empty when it handles all queries).
+}
+
+// ProxyConfig returns the proxy settings configured on the host. Each source
+// of settings (e.g. environment variables and desktop settings on Linux or
+// WinHTTP and WinINET on Windows) is reported separately because programs
+// use different sources.
+type ProxyConfig interface {
+	ProxyConfig() ([]ProxySettings, error)
+}
+
+// ProxySettings describes the proxy settings from one source.
+type ProxySettings struct {
+	Source        string   `json:"source"`                    // Where the settings are configured (e.g. environment, winhttp).
+	HTTP          string   `json:"http,omitempty"`            // Proxy for HTTP requests.
+	HTTPS         string   `json:"https,omitempty"`           // Proxy for HTTPS requests.
+	FTP           string   `json:"ftp,omitempty"`             // Proxy for FTP requests.
+	SOCKS         string   `json:"socks,omitempty"`           // SOCKS proxy.
+	Bypass        []string `json:"bypass,omitempty"`          // Hosts, domains, and networks that are accessed directly.
+	AutoConfigURL string   `json:"auto_config_url,omitempty"` // URL of the proxy auto-config (PAC) script.
+	AutoDetect    bool     `json:"auto_detect,omitempty"`     // Whether the proxy is discovered with WPAD.
+}
@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// PackageEnumerator lists the software that is installed on the host.
+type PackageEnumerator interface {
+	Packages() ([]PackageInfo, error)
+}
+
+// PackageInfo describes an installed package. Fields that a package database
+// does not record are empty.
+type PackageInfo struct {
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`                // Version (with the epoch as "epoch:version" for rpm).
+	Release     string    `json:"release,omitempty"`      // Package release (rpm) or build (Windows).
+	Arch        string    `json:"architecture,omitempty"` // Architecture (e.g. amd64, x86_64, noarch).
+	Summary     string    `json:"summary,omitempty"`      // One line description.
+	Vendor      string    `json:"vendor,omitempty"`       // Vendor, maintainer, or publisher.
+	Size        uint64    `json:"size_bytes,omitempty"`   // Installed size.
+	InstallTime time.Time `json:"install_time"`           // Zero if unknown.
+	Path        string    `json:"path,omitempty"`         // Install location (e.g. the .app bundle).
+	Type        string    `json:"type"`                   // One of the PackageType* constants.
+}
+
+// Package database types.
+const (
+	PackageTypeDpkg    = "dpkg"
+	PackageTypeRPM     = "rpm"
+	PackageTypeAPK     = "apk"
+	PackageTypeWindows = "windows" // Entry of the Uninstall registry key.
+	PackageTypeMSI     = "msi"     // Windows Installer product.
+	PackageTypeApp     = "app"     // macOS application bundle.
+	PackageTypePkg     = "pkg"     // macOS installer package receipt.
+)
+
+// OSPatches lists the updates that are installed on the operating system.
+// These are the hotfixes on Windows, the install history on macOS, and the
+// installed kernels on Linux.
+type OSPatches interface {
+	OSPatches() ([]OSPatchInfo, error)
+}
+
+// OSPatchInfo describes an installed update.
+type OSPatchInfo struct {
+	ID          string    `json:"id"`                    // Hotfix (e.g. KB5001330), update name, or kernel release.
+	Version     string    `json:"version,omitempty"`     // Version of the update when it differs from the ID.
+	Description string    `json:"description,omitempty"` // Additional details (e.g. the installing process).
+	InstallTime time.Time `json:"install_time"`          // Zero if unknown.
+	Current     bool      `json:"current,omitempty"`     // The running kernel on Linux.
+}
@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// PowerSources is implemented by hosts that can report their batteries and
+// AC adapters.
+type PowerSources interface {
+	PowerSources() ([]PowerSourceInfo, error)
+}
+
+// Power source types.
+const (
+	PowerSourceBattery = "battery"
+	PowerSourceMains   = "mains" // AC adapter.
+	PowerSourceUPS     = "ups"
+)
+
+// Battery states.
+const (
+	BatteryCharging    = "charging"
+	BatteryDischarging = "discharging"
+	BatteryFull        = "full"
+	BatteryNotCharging = "not_charging" // Connected to AC but not charging.
+)
+
+// PowerSourceInfo describes a battery or an external power supply.
+type PowerSourceInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Online reports whether an AC adapter or UPS is supplying power. It is
+	// nil for batteries.
+	Online *bool `json:"online,omitempty"`
+
+	// Battery fields. They are empty when unknown.
+	Percent float64 `json:"percent,omitempty"`     // Remaining charge (0-100).
+	State   string  `json:"state,omitempty"`       // Charging state (e.g. charging, discharging).
+	Health  string  `json:"health,omitempty"`      // Health as reported by the OS (e.g. Good).
+	Cycles  int     `json:"cycle_count,omitempty"` // Number of charge cycles.
+}
@@ -35,6 +35,64 @@ type ProcessInfo struct {
 	Exe       string    `json:"exe"`
 	Args      []string  `json:"args"`
 	StartTime time.Time `json:"start_time"`
+
+	// State is the scheduling state (one of the ProcessState constants) at
+	// the time the info was read. It is empty when it cannot be determined.
+	State string `json:"state,omitempty"`
+
+	// Launchd is the launchd job that manages the process or one of its
+	// ancestors. Only reported on macOS and only read when FieldLaunchd is
+	// selected.
+	Launchd *LaunchdJobInfo `json:"launchd,omitempty"`
+}
+
+// LaunchdJobInfo identifies a launchd job.
+type LaunchdJobInfo struct {
+	Label  string `json:"label"`  // Job label (e.g. com.apple.Finder).
+	Domain string `json:"domain"` // Domain of the job (system or user).
+	PID    int    `json:"pid"`    // PID of the process started by launchd for the job.
+}
+
+// ProcessField is a bit mask that selects the process information that a
+// caller intends to read. Providers use it to skip expensive lookups. The
+// Name, PID, PPID, and StartTime of ProcessInfo are always populated.
+type ProcessField uint32
+
+const (
+	FieldCWD     ProcessField = 1 << iota // ProcessInfo.CWD
+	FieldExe                              // ProcessInfo.Exe
+	FieldArgs                             // ProcessInfo.Args
+	FieldCPU                              // CPUTime
+	FieldMemory                           // Memory
+	FieldLaunchd                          // ProcessInfo.Launchd
+
+	// FieldAll selects all information. It is the default.
+	FieldAll ProcessField = 1<<iota - 1
+
+	// FieldNone selects none of the fields above, so only the Name, PID,
+	// PPID, and StartTime are read. Unlike an empty mask it does not select
+	// all fields.
+	FieldNone ProcessField = 1 << 31
+)
+
+// Process enumeration backends of the Linux provider.
+const (
+	// ProcessBackendProcFS lists the processes by reading the /proc
+	// directory. It is the default.
+	ProcessBackendProcFS = "procfs"
+
+	// ProcessBackendEBPF lists the processes with a BPF task iterator, which
+	// is faster than reading /proc on hosts with very many processes. It
+	// requires Linux 5.8 or newer with BTF, CAP_BPF (or CAP_SYS_ADMIN), and
+	// the initial PID namespace. The information of each process is still
+	// read from /proc.
+	ProcessBackendEBPF = "ebpf"
+)
+
+// Includes returns true if all of the given fields are selected. An empty
+// mask selects all fields.
+func (f ProcessField) Includes(fields ProcessField) bool {
+	return f == 0 || f&fields == fields
 }
 
 // UserInfo contains information about the UID and GID
@@ -73,16 +131,65 @@ type Environment interface {
 	Environment() (map[string]string, error)
 }
 
+// EnvFilter is applied to each environment variable of a process before it
+// is returned by Environment. It returns the value to report (e.g. a redacted
+// value) and false if the variable must be dropped.
+type EnvFilter func(name, value string) (string, bool)
+
 // OpenHandleEnumerator lists the open file handles.
 type OpenHandleEnumerator interface {
-	OpenHandles() ([]string, error)
+	OpenHandles() ([]OpenHandleInfo, error)
 }
 
+// OpenHandleInfo describes a file, socket, pipe, or other kernel object that
+// is held open by a process.
+type OpenHandleInfo struct {
+	// FD is the file descriptor number. On Windows this is the handle value.
+	FD uint64 `json:"fd"`
+
+	// Type of the object referenced by the handle (e.g. file, socket, pipe).
+	Type string `json:"type"`
+
+	// Path is the target of the handle. For files this is the file path. For
+	// other objects it is a platform specific description (e.g. socket:[1234]
+	// on Linux). It is empty if the target could not be determined.
+	Path string `json:"path,omitempty"`
+}
+
+// Types of open handles that are common across platforms. Providers may
+// report other platform specific types (e.g. kqueue on Darwin).
+const (
+	HandleTypeFile    = "file"
+	HandleTypeDir     = "dir"
+	HandleTypeSocket  = "socket"
+	HandleTypePipe    = "pipe"
+	HandleTypeDevice  = "device"
+	HandleTypeUnknown = "unknown"
+)
+
 // OpenHandleCount returns the number the open file handles.
 type OpenHandleCounter interface {
 	OpenHandleCount() (int, error)
 }
 
+// WindowsHandles reports the kernel, GDI, and USER object counts of a
+// Windows process. Steadily growing counts indicate a handle leak. It is
+// implemented by Process on Windows.
+type WindowsHandles interface {
+	WindowsHandles() (*WindowsHandleInfo, error)
+}
+
+// WindowsHandleInfo contains the handle and GUI object counts of a Windows
+// process. The GUI object counts are zero for processes that are not
+// attached to a desktop (e.g. services).
+type WindowsHandleInfo struct {
+	Handles         uint32 `json:"handles"`           // Number of open kernel object handles.
+	GDIObjects      uint32 `json:"gdi_objects"`       // Number of GDI objects in use.
+	GDIObjectsPeak  uint32 `json:"gdi_objects_peak"`  // Peak number of GDI objects.
+	USERObjects     uint32 `json:"user_objects"`      // Number of USER objects in use.
+	USERObjectsPeak uint32 `json:"user_objects_peak"` // Peak number of USER objects.
+}
+
 type CPUTimer interface {
 	// CPUTime returns a CPUTimes structure for
 	// the host or some process.
@@ -112,7 +219,70 @@ func (cpu CPUTimes) Total() time.Duration {
 type MemoryInfo struct {
 	Resident uint64            `json:"resident_bytes"`
 	Virtual  uint64            `json:"virtual_bytes"`
-	Metrics  map[string]uint64 `json:"raw,omitempty"` // Other memory related metrics.
+	Swap     uint64            `json:"swap_bytes,omitempty"` // Memory swapped out (VmSwap on Linux, pagefile usage on Windows, compressed memory on macOS).
+	Metrics  map[string]uint64 `json:"raw,omitempty"`        // Other memory related metrics.
+}
+
+// MemoryDetails reports the proportional and unique memory usage of a
+// process. RSS counts shared pages in full for every process that maps them
+// which over-counts the memory of multi-process services. Reading the details
+// is more expensive than Memory so it is a separate call.
+type MemoryDetails interface {
+	MemoryDetails() (*MemoryDetailsInfo, error)
+}
+
+// MemoryDetailsInfo contains the memory usage of a process broken down by
+// sharing. Values are in bytes.
+type MemoryDetailsInfo struct {
+	Resident     uint64 `json:"resident_bytes"`      // Resident set size (RSS).
+	Proportional uint64 `json:"proportional_bytes"`  // Proportional set size (PSS). Shared pages are divided by the number of processes mapping them.
+	Unique       uint64 `json:"unique_bytes"`        // Unique set size (USS). Memory that would be freed if the process exited.
+	SharedClean  uint64 `json:"shared_clean_bytes"`  // Unmodified pages that are shared with other processes.
+	SharedDirty  uint64 `json:"shared_dirty_bytes"`  // Modified pages that are shared with other processes.
+	PrivateClean uint64 `json:"private_clean_bytes"` // Unmodified pages that are private to the process.
+	PrivateDirty uint64 `json:"private_dirty_bytes"` // Modified pages that are private to the process.
+	Swap         uint64 `json:"swap_bytes"`          // Memory that is swapped out.
+	SwapPss      uint64 `json:"swap_pss_bytes"`      // Proportional share of swapped out memory.
+}
+
+// IOCounters returns the cumulative I/O statistics of a process.
+type IOCounters interface {
+	IOCounters() (*IOCountersInfo, error)
+}
+
+// IOCountersInfo contains the cumulative I/O statistics of a process.
+type IOCountersInfo struct {
+	// ReadBytes and WriteBytes are the number of bytes transferred to or
+	// from storage on Linux and macOS. On Windows they include all I/O
+	// (files, devices, and pipes) issued by the process.
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+
+	// ReadCount and WriteCount are the number of read and write system calls
+	// (I/O operations on Windows). They are not reported on macOS.
+	ReadCount  uint64 `json:"read_count,omitempty"`
+	WriteCount uint64 `json:"write_count,omitempty"`
+
+	Metrics map[string]uint64 `json:"raw,omitempty"` // Other I/O related metrics.
+}
+
+// ResourceUsage returns the page fault and context switch counts of a
+// process.
+type ResourceUsage interface {
+	ResourceUsage() (*ResourceUsageInfo, error)
+}
+
+// ResourceUsageInfo contains cumulative page fault and context switch counts
+// for a process.
+type ResourceUsageInfo struct {
+	MinorPageFaults uint64 `json:"minor_page_faults"` // Faults serviced without I/O.
+	MajorPageFaults uint64 `json:"major_page_faults"` // Faults that required I/O.
+
+	// ContextSwitches is the total number of context switches. Linux also
+	// reports the voluntary (blocking) and involuntary (preempted) counts.
+	ContextSwitches            uint64 `json:"context_switches"`
+	VoluntaryContextSwitches   uint64 `json:"voluntary_context_switches,omitempty"`
+	InvoluntaryContextSwitches uint64 `json:"involuntary_context_switches,omitempty"`
 }
 
 type SeccompInfo struct {
@@ -125,7 +295,12 @@ type CapabilityInfo struct {
 	Permitted   []string `json:"permitted"`
 	Effective   []string `json:"effective"`
 	Bounding    []string `json:"bounding"`
-	Ambient     []string `json:"ambient"`
+	Ambient     []string `json:"ambient"` // Added in kernel 4.3.
+
+	// NoNewPrivs and SeccompMode limit what the capabilities can be used
+	// for so they are reported together with them.
+	NoNewPrivs  *bool  `json:"no_new_privs,omitempty"` // Added in kernel 4.10.
+	SeccompMode string `json:"seccomp_mode,omitempty"` // disabled, strict, or filter.
 }
 
 type Capabilities interface {
@@ -135,3 +310,337 @@ type Capabilities interface {
 type Seccomp interface {
 	Seccomp() (*SeccompInfo, error)
 }
+
+// Scheduling reports the CPU and I/O scheduling parameters of a process.
+type Scheduling interface {
+	Scheduling() (*SchedulingInfo, error)
+}
+
+// SchedulingInfo contains the scheduling parameters of a process. Fields that
+// don't apply to the OS are left empty.
+type SchedulingInfo struct {
+	// Policy is the scheduling policy. On Linux it is one of other, fifo,
+	// rr, batch, idle, or deadline. On macOS it is one of timeshare, rr, or
+	// fifo. On Windows it is the priority class (idle, below_normal, normal,
+	// above_normal, high, or realtime).
+	Policy string `json:"policy"`
+
+	Priority         int  `json:"priority"`                    // Native priority value (higher is more favorable on macOS and Windows, lower on Linux).
+	Nice             *int `json:"nice,omitempty"`              // Nice value (-20 to 19). Not reported on Windows.
+	RealtimePriority int  `json:"realtime_priority,omitempty"` // Priority for the fifo and rr policies on Linux.
+
+	// IOClass and IOPriority are the I/O scheduling class (none, realtime,
+	// best-effort, or idle) and the priority level within the class (0 is
+	// the highest). Only reported on Linux.
+	IOClass    string `json:"io_class,omitempty"`
+	IOPriority *int   `json:"io_priority,omitempty"`
+}
+
+// OOMScore reports how likely the Linux OOM killer is to select a process.
+type OOMScore interface {
+	OOMScore() (*OOMScoreInfo, error)
+}
+
+// OOMScoreInfo contains the values of /proc/[pid]/oom_score and
+// /proc/[pid]/oom_score_adj.
+type OOMScoreInfo struct {
+	Score    int `json:"score"`     // Current badness score (0 to 2000). The highest is killed first.
+	ScoreAdj int `json:"score_adj"` // Adjustment added to the score (-1000 to 1000). -1000 disables OOM killing.
+}
+
+// Namespaces lists the Linux namespaces of a process.
+type Namespaces interface {
+	Namespaces() ([]NamespaceInfo, error)
+}
+
+// NamespaceInfo identifies a namespace. Two processes are in the same
+// namespace if the type and the inode are equal.
+type NamespaceInfo struct {
+	Type  string `json:"type"`  // Name of the entry in /proc/[pid]/ns (e.g. net or pid_for_children).
+	Inode uint64 `json:"inode"` // Inode of the namespace.
+}
+
+// SameNamespace returns true if both lists contain the namespace of the
+// given type (e.g. net) and it has the same inode.
+func SameNamespace(a, b []NamespaceInfo, typ string) bool {
+	inode := func(list []NamespaceInfo) uint64 {
+		for _, ns := range list {
+			if ns.Type == typ {
+				return ns.Inode
+			}
+		}
+		return 0
+	}
+	x := inode(a)
+	return x != 0 && x == inode(b)
+}
+
+// SharedNamespaces returns the types of the namespaces that are the same in
+// both lists.
+func SharedNamespaces(a, b []NamespaceInfo) []string {
+	var shared []string
+	for _, ns := range a {
+		if SameNamespace(a, b, ns.Type) {
+			shared = append(shared, ns.Type)
+		}
+	}
+	return shared
+}
+
+// ProcessIdentity identifies a process across samples. PIDs are reused after
+// a process exits, so the start time of the process and the boot ID of the
+// host are needed to tell two processes with the same PID apart.
+type ProcessIdentity struct {
+	PID       int       `json:"pid"`
+	StartTime time.Time `json:"start_time"`
+	BootID    string    `json:"boot_id,omitempty"` // ID of the boot session (empty if the OS has none).
+}
+
+// SameProcess returns true if both identities refer to the same process.
+func SameProcess(a, b ProcessIdentity) bool {
+	return a.PID == b.PID && a.StartTime.Equal(b.StartTime) && a.BootID == b.BootID
+}
+
+// ProcessIdentifier is implemented by processes that can report their
+// identity without reading all of the ProcessInfo.
+type ProcessIdentifier interface {
+	Identity() (ProcessIdentity, error)
+}
+
+// Taskstats reports the delay and I/O accounting of a process as maintained
+// by the Linux kernel (see Documentation/accounting/taskstats.rst).
+type Taskstats interface {
+	Taskstats() (*TaskstatsInfo, error)
+}
+
+// TaskstatsInfo contains the accounting data of a process. The counters are
+// the sum over the live threads of the process.
+type TaskstatsInfo struct {
+	UserTime     time.Duration `json:"user_time"`    // CPU time spent in user mode.
+	SystemTime   time.Duration `json:"system_time"`  // CPU time spent in kernel mode.
+	CPUDelay     time.Duration `json:"cpu_delay"`    // Time spent waiting for a CPU while runnable.
+	BlockIODelay time.Duration `json:"blkio_delay"`  // Time spent waiting for synchronous block I/O.
+	SwapinDelay  time.Duration `json:"swapin_delay"` // Time spent waiting for pages to be swapped in.
+	MinorFaults  uint64        `json:"minor_faults"` // Page faults that did not require loading from disk.
+	MajorFaults  uint64        `json:"major_faults"` // Page faults that required loading from disk.
+	MaxRSS       uint64        `json:"max_rss"`      // High-water mark of the resident set size in bytes.
+	MaxVM        uint64        `json:"max_vm"`       // High-water mark of the virtual memory size in bytes.
+
+	ReadChars           uint64 `json:"read_chars"`            // Bytes passed to read syscalls.
+	WriteChars          uint64 `json:"write_chars"`           // Bytes passed to write syscalls.
+	ReadSyscalls        uint64 `json:"read_syscalls"`         // Number of read syscalls.
+	WriteSyscalls       uint64 `json:"write_syscalls"`        // Number of write syscalls.
+	ReadBytes           uint64 `json:"read_bytes"`            // Bytes read from storage.
+	WriteBytes          uint64 `json:"write_bytes"`           // Bytes written to storage.
+	CancelledWriteBytes uint64 `json:"cancelled_write_bytes"` // Bytes whose writeback was cancelled (e.g. truncated files).
+
+	VoluntaryContextSwitches   uint64 `json:"voluntary_context_switches"`
+	InvoluntaryContextSwitches uint64 `json:"involuntary_context_switches"`
+}
+
+// RLimits is implemented by processes that can report their resource
+// limits (see getrlimit(2)). The limits are keyed by resource name (e.g.
+// RLimitNoFile). On Linux the limits of any process can be read, on macOS
+// only those of the current process.
+type RLimits interface {
+	RLimits() (map[string]RLimit, error)
+}
+
+// RLimit is the soft and hard limit of a resource. Unlimited resources are
+// reported as RLimitInfinity.
+type RLimit struct {
+	Soft uint64 `json:"soft"` // Limit enforced by the kernel.
+	Hard uint64 `json:"hard"` // Ceiling for the soft limit.
+}
+
+// RLimitInfinity is the value of a limit that is not enforced.
+const RLimitInfinity = ^uint64(0)
+
+// Resource names used as keys of RLimits. They match the names used by
+// prlimit(1). Not every OS supports all of them.
+const (
+	RLimitAS         = "as"         // Address space (virtual memory) in bytes.
+	RLimitCore       = "core"       // Size of core dumps in bytes.
+	RLimitCPU        = "cpu"        // CPU time in seconds.
+	RLimitData       = "data"       // Data segment size in bytes.
+	RLimitFSize      = "fsize"      // Size of created files in bytes.
+	RLimitLocks      = "locks"      // Number of file locks.
+	RLimitMemLock    = "memlock"    // Locked memory in bytes.
+	RLimitMsgQueue   = "msgqueue"   // Bytes in POSIX message queues.
+	RLimitNice       = "nice"       // Ceiling of the nice value (20 - limit).
+	RLimitNoFile     = "nofile"     // Number of open files.
+	RLimitNProc      = "nproc"      // Number of processes of the user.
+	RLimitRSS        = "rss"        // Resident set size in bytes.
+	RLimitRTPrio     = "rtprio"     // Real-time priority.
+	RLimitRTTime     = "rttime"     // CPU time of real-time tasks in microseconds.
+	RLimitSigPending = "sigpending" // Number of queued signals of the user.
+	RLimitStack      = "stack"      // Stack size in bytes.
+)
+
+// OpenFileLimitRaiser is implemented by the current process on Linux and
+// macOS. RaiseOpenFileLimit raises the soft RLIMIT_NOFILE limit to the hard
+// limit, or to the largest value accepted by the OS if that is lower, and
+// returns the new limit. Long-running agents that open many files or
+// sockets typically call it once at startup.
+type OpenFileLimitRaiser interface {
+	RaiseOpenFileLimit() (RLimit, error)
+}
+
+// ChildProcessEnumerator lists the direct children of a process.
+type ChildProcessEnumerator interface {
+	Children() ([]Process, error)
+}
+
+// AncestorEnumerator lists the parents of a process, ordered from the direct
+// parent up to the root of the process tree (PID 1 or System). The chain is
+// resolved from a single snapshot of the process table.
+type AncestorEnumerator interface {
+	Ancestors() ([]Process, error)
+}
+
+// ThreadEnumerator lists the threads of a process.
+type ThreadEnumerator interface {
+	Threads() ([]ThreadInfo, error)
+}
+
+// ThreadInfo describes a single thread of a process.
+type ThreadInfo struct {
+	TID     int      `json:"tid"`            // Thread ID.
+	Name    string   `json:"name,omitempty"` // Thread name (empty when unnamed or unsupported).
+	State   string   `json:"state"`          // Scheduling state (one of the ProcessState constants).
+	CPUTime CPUTimes `json:"cpu"`            // CPU time consumed by the thread.
+}
+
+// Process and thread scheduling states. Providers map their native states
+// onto this set.
+const (
+	ProcessStateRunning  = "running"  // Running or runnable.
+	ProcessStateSleeping = "sleeping" // Interruptible sleep or waiting for an event.
+	ProcessStateWaiting  = "waiting"  // Uninterruptible wait (usually disk I/O).
+	ProcessStateStopped  = "stopped"  // Stopped by a signal or a debugger.
+	ProcessStateZombie   = "zombie"   // Exited but not yet reaped by its parent.
+	ProcessStateIdle     = "idle"     // Idle kernel thread.
+	ProcessStateUnknown  = "unknown"
+)
+
+// ModuleEnumerator lists the executable images (the main executable and
+// shared libraries) mapped into a process.
+type ModuleEnumerator interface {
+	Modules() ([]ModuleInfo, error)
+}
+
+// ModuleInfo describes an executable image loaded by a process.
+type ModuleInfo struct {
+	Path        string `json:"path"`         // Path of the image file.
+	BaseAddress uint64 `json:"base_address"` // Lowest address at which the image is mapped.
+	Size        uint64 `json:"size"`         // Size of the address range spanned by the image.
+}
+
+// MemoryMapEnumerator lists the memory regions mapped into a process.
+type MemoryMapEnumerator interface {
+	MemoryMaps() ([]MemoryMapInfo, error)
+}
+
+// MemoryMapInfo describes a contiguous region of a process's address space.
+type MemoryMapInfo struct {
+	Start       uint64 `json:"start"`          // Start address of the region.
+	End         uint64 `json:"end"`            // End address (exclusive) of the region.
+	Permissions string `json:"permissions"`    // Permissions in the form rwxp (p is private, s is shared). Dashes mark missing permissions.
+	Path        string `json:"path,omitempty"` // Backing file or a pseudo-path like [heap].
+	Offset      uint64 `json:"offset"`         // Offset into the backing file.
+	Anonymous   bool   `json:"anonymous"`      // True when the region is not backed by a file.
+
+	// Resident is the number of bytes of the region in physical memory. It is
+	// zero when the platform does not report it.
+	Resident uint64 `json:"resident_bytes,omitempty"`
+
+	// Proportional is the proportional set size (PSS) of the region where
+	// shared pages are divided by the number of processes mapping them.
+	// Only reported on Linux.
+	Proportional uint64 `json:"proportional_bytes,omitempty"`
+
+	// Swap is the number of bytes of the region that are swapped out.
+	// Only reported on Linux.
+	Swap uint64 `json:"swap_bytes,omitempty"`
+}
+
+// WindowsServices lists the services that are registered with the Windows
+// service control manager. It is implemented by the Host on Windows.
+type WindowsServices interface {
+	WindowsServices() ([]WindowsServiceInfo, error)
+}
+
+// WindowsServiceInfo describes a Windows service.
+type WindowsServiceInfo struct {
+	Name        string `json:"name"`                   // Service (key) name.
+	DisplayName string `json:"display_name,omitempty"` // Name shown in the services console.
+	State       string `json:"state"`                  // Current state (e.g. running, stopped).
+	StartType   string `json:"start_type"`             // How the service is started (auto, manual, disabled, boot, or system).
+	BinaryPath  string `json:"binary_path,omitempty"`  // Command line used to start the service.
+	Account     string `json:"account,omitempty"`      // Account the service runs as (e.g. LocalSystem).
+	PID         int    `json:"pid,omitempty"`          // PID of the service process when it is running.
+}
+
+// SystemdUnit resolves the systemd unit and slice that a process belongs to
+// from its cgroup path. Both are empty when the process is not managed by
+// systemd.
+type SystemdUnit interface {
+	UnitName() (string, error)  // Name of the unit (e.g. ssh.service or session-2.scope).
+	SliceName() (string, error) // Name of the slice containing the unit (e.g. system.slice).
+}
+
+// ExecutableHasher hashes the executable file of a process. When no
+// algorithms are given all of the supported HashTypes are computed.
+type ExecutableHasher interface {
+	Hashes(algorithms ...HashType) (map[HashType]string, error)
+}
+
+// HashType identifies a hash algorithm. Hashes are reported as lowercase hex
+// strings.
+type HashType string
+
+// Supported hash algorithms.
+const (
+	MD5    HashType = "md5"
+	SHA1   HashType = "sha1"
+	SHA256 HashType = "sha256"
+)
+
+// ProcessTreeNode is a process and its descendants.
+type ProcessTreeNode struct {
+	Process  Process            `json:"-"`
+	Info     ProcessInfo        `json:"info"`
+	Children []*ProcessTreeNode `json:"children,omitempty"`
+}
+
+// ProcessEvent is a process start or exit notification.
+type ProcessEvent struct {
+	Type string    `json:"type"`           // Type of event (start, exec, or exit).
+	PID  int       `json:"pid"`            // PID of the process.
+	PPID int       `json:"ppid,omitempty"` // Parent PID (only for start events when known).
+	Time time.Time `json:"time"`           // Time when the event was observed.
+}
+
+// Types of process events. Exec events are only reported by providers that
+// receive notifications from the kernel.
+const (
+	ProcessEventStart = "start"
+	ProcessEventExec  = "exec"
+	ProcessEventExit  = "exit"
+)
+
+// ProcessExitEvent reports the resources that a process used over its
+// lifetime. Totals are summed over all threads of the process.
+type ProcessExitEvent struct {
+	PID       int           `json:"pid"`
+	PPID      int           `json:"ppid"`
+	Name      string        `json:"name"` // Command name (truncated by the kernel).
+	UID       int           `json:"uid"`
+	GID       int           `json:"gid"`
+	ExitCode  int           `json:"exit_code"`        // Exit status passed to exit(2).
+	Signal    int           `json:"signal,omitempty"` // Signal that terminated the process.
+	StartTime time.Time     `json:"start_time"`
+	Elapsed   time.Duration `json:"elapsed"` // Wall clock time from start to exit.
+	Time      time.Time     `json:"time"`    // Time when the event was observed.
+	Totals    TaskstatsInfo `json:"totals"`
+}
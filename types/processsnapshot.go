@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// ProcessSnapshot records the processes that were seen by one enumeration.
+// Compare two snapshots with Diff to find the processes that started,
+// exited, or changed in between.
+type ProcessSnapshot struct {
+	Time      time.Time                    `json:"time"`
+	Processes map[int]ProcessSnapshotEntry `json:"processes"` // Keyed by PID.
+}
+
+// ProcessSnapshotEntry is a process in a ProcessSnapshot.
+type ProcessSnapshotEntry struct {
+	Identity ProcessIdentity `json:"identity"`
+	Info     ProcessInfo     `json:"info"`
+	User     *UserInfo       `json:"user,omitempty"` // Nil if the user could not be read.
+}
+
+// ProcessDiff lists the differences between two snapshots. The processes
+// are sorted by PID.
+type ProcessDiff struct {
+	Started []ProcessSnapshotEntry `json:"started,omitempty"`
+	Exited  []ProcessSnapshotEntry `json:"exited,omitempty"`
+	Changed []ProcessChange        `json:"changed,omitempty"`
+}
+
+// ProcessChange describes a process that exists in both snapshots but whose
+// information changed.
+type ProcessChange struct {
+	Previous ProcessSnapshotEntry `json:"previous"`
+	Current  ProcessSnapshotEntry `json:"current"`
+	Fields   []string             `json:"fields"` // Names of the changed fields (see the ProcessChange constants).
+}
+
+// Fields that are compared by ProcessSnapshot.Diff.
+const (
+	ProcessChangeExe  = "exe"  // The executable was replaced (e.g. by exec).
+	ProcessChangeName = "name" // The process name changed.
+	ProcessChangeArgs = "args" // The arguments changed (e.g. by exec or setproctitle).
+	ProcessChangePPID = "ppid" // The process was reparented.
+	ProcessChangeUID  = "uid"  // The real user ID changed.
+	ProcessChangeEUID = "euid" // The effective user ID changed (e.g. by setuid).
+)
+
+// Diff returns the processes that were started, exited, or changed since
+// prev. A process is identified by its ProcessIdentity so that a PID that
+// was reused by a new process is reported as the old process exiting and the
+// new one starting.
+func (s *ProcessSnapshot) Diff(prev *ProcessSnapshot) ProcessDiff {
+	var diff ProcessDiff
+	var prevProcs map[int]ProcessSnapshotEntry
+	if prev != nil {
+		prevProcs = prev.Processes
+	}
+
+	for pid, cur := range s.Processes {
+		old, found := prevProcs[pid]
+		switch {
+		case !found:
+			diff.Started = append(diff.Started, cur)
+		case !SameProcess(old.Identity, cur.Identity):
+			diff.Exited = append(diff.Exited, old)
+			diff.Started = append(diff.Started, cur)
+		default:
+			if fields := changedFields(old, cur); len(fields) > 0 {
+				diff.Changed = append(diff.Changed, ProcessChange{Previous: old, Current: cur, Fields: fields})
+			}
+		}
+	}
+	for pid, old := range prevProcs {
+		if _, found := s.Processes[pid]; !found {
+			diff.Exited = append(diff.Exited, old)
+		}
+	}
+
+	sortEntries(diff.Started)
+	sortEntries(diff.Exited)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Current.Identity.PID < diff.Changed[j].Current.Identity.PID
+	})
+	return diff
+}
+
+// changedFields returns the fields that differ between the entries. Values
+// that could not be read in one of the snapshots (e.g. the arguments of a
+// process that became a zombie) are not compared.
+func changedFields(old, cur ProcessSnapshotEntry) []string {
+	var fields []string
+	if old.Info.Exe != "" && cur.Info.Exe != "" && old.Info.Exe != cur.Info.Exe {
+		fields = append(fields, ProcessChangeExe)
+	}
+	if old.Info.Name != "" && cur.Info.Name != "" && old.Info.Name != cur.Info.Name {
+		fields = append(fields, ProcessChangeName)
+	}
+	if len(old.Info.Args) > 0 && len(cur.Info.Args) > 0 && !equalStrings(old.Info.Args, cur.Info.Args) {
+		fields = append(fields, ProcessChangeArgs)
+	}
+	if old.Info.PPID != cur.Info.PPID {
+		fields = append(fields, ProcessChangePPID)
+	}
+	if old.User != nil && cur.User != nil {
+		if old.User.UID != cur.User.UID {
+			fields = append(fields, ProcessChangeUID)
+		}
+		if old.User.EUID != cur.User.EUID {
+			fields = append(fields, ProcessChangeEUID)
+		}
+	}
+	return fields
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortEntries(entries []ProcessSnapshotEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Identity.PID < entries[j].Identity.PID
+	})
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshotEntry(pid int, start time.Time, exe, uid string) ProcessSnapshotEntry {
+	return ProcessSnapshotEntry{
+		Identity: ProcessIdentity{PID: pid, StartTime: start},
+		Info:     ProcessInfo{PID: pid, PPID: 1, Name: exe, Exe: "/usr/bin/" + exe, Args: []string{exe}, StartTime: start},
+		User:     &UserInfo{UID: uid, EUID: uid},
+	}
+}
+
+func TestProcessSnapshotDiff(t *testing.T) {
+	boot := time.Unix(1500000000, 0)
+
+	prev := &ProcessSnapshot{Processes: map[int]ProcessSnapshotEntry{
+		1:   snapshotEntry(1, boot, "init", "0"),
+		100: snapshotEntry(100, boot.Add(time.Minute), "sshd", "0"),
+		200: snapshotEntry(200, boot.Add(2*time.Minute), "bash", "1000"),
+		300: snapshotEntry(300, boot.Add(3*time.Minute), "sleep", "1000"),
+		400: snapshotEntry(400, boot.Add(4*time.Minute), "cron", "0"),
+	}}
+
+	// sshd drops privileges, bash execs vim, sleep exits and its PID is
+	// reused, cron exits, and nginx starts.
+	sshd := snapshotEntry(100, boot.Add(time.Minute), "sshd", "0")
+	sshd.User.EUID = "1000"
+	vim := snapshotEntry(200, boot.Add(2*time.Minute), "vim", "1000")
+	reused := snapshotEntry(300, boot.Add(time.Hour), "python", "1000")
+	nginx := snapshotEntry(500, boot.Add(time.Hour), "nginx", "33")
+	zombie := snapshotEntry(1, boot, "init", "0")
+	zombie.Info.Args = nil
+
+	cur := &ProcessSnapshot{Processes: map[int]ProcessSnapshotEntry{
+		1:   zombie,
+		100: sshd,
+		200: vim,
+		300: reused,
+		500: nginx,
+	}}
+
+	diff := cur.Diff(prev)
+	assert.Equal(t, []ProcessSnapshotEntry{reused, nginx}, diff.Started)
+	assert.Equal(t, []ProcessSnapshotEntry{prev.Processes[300], prev.Processes[400]}, diff.Exited)
+	if assert.Len(t, diff.Changed, 2) {
+		assert.Equal(t, 100, diff.Changed[0].Current.Identity.PID)
+		assert.Equal(t, []string{ProcessChangeEUID}, diff.Changed[0].Fields)
+		assert.Equal(t, prev.Processes[200], diff.Changed[1].Previous)
+		assert.Equal(t, []string{ProcessChangeExe, ProcessChangeName, ProcessChangeArgs}, diff.Changed[1].Fields)
+	}
+
+	assert.Empty(t, cur.Diff(cur).Started)
+	assert.Len(t, cur.Diff(nil).Started, len(cur.Processes))
+}
@@ -0,0 +1,116 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// Security reports the state of the security features of the host.
+type Security interface {
+	SecurityInfo() (*SecurityInfo, error)
+}
+
+// SecurityInfo describes the security posture of the host. Fields that are
+// not applicable to the OS or that could not be determined are nil.
+type SecurityInfo struct {
+	SELinux          *SELinuxInfo  `json:"selinux,omitempty"`           // Linux
+	AppArmor         *AppArmorInfo `json:"apparmor,omitempty"`          // Linux
+	SeccompAvailable *bool         `json:"seccomp_available,omitempty"` // Linux
+	SecureBoot       *bool         `json:"secure_boot,omitempty"`       // Linux and Windows on UEFI systems
+	SIP              *bool         `json:"sip,omitempty"`               // macOS System Integrity Protection
+	Gatekeeper       *bool         `json:"gatekeeper,omitempty"`        // macOS
+	FileVault        *bool         `json:"filevault,omitempty"`         // macOS, true if the data volume is encrypted
+	BitLocker        *bool         `json:"bitlocker,omitempty"`         // Windows, true if the OS volume is protected
+	Defender         *DefenderInfo `json:"defender,omitempty"`          // Windows
+}
+
+// SELinux modes.
+const (
+	SELinuxEnforcing  = "enforcing"
+	SELinuxPermissive = "permissive"
+	SELinuxDisabled   = "disabled"
+)
+
+// SELinuxInfo describes the state of SELinux.
+type SELinuxInfo struct {
+	Mode          string `json:"mode"`                     // enforcing, permissive, or disabled.
+	Policy        string `json:"policy,omitempty"`         // Policy type from /etc/selinux/config (e.g. targeted).
+	PolicyVersion int    `json:"policy_version,omitempty"` // Version of the loaded policy.
+}
+
+// AppArmorInfo describes the state of AppArmor. The profile counts require
+// root privileges and are zero otherwise.
+type AppArmorInfo struct {
+	Enabled          bool `json:"enabled"`
+	EnforceProfiles  int  `json:"enforce_profiles,omitempty"`
+	ComplainProfiles int  `json:"complain_profiles,omitempty"`
+}
+
+// DefenderInfo describes the state of Microsoft Defender Antivirus.
+type DefenderInfo struct {
+	Running            bool  `json:"running"`                        // The WinDefend service is running.
+	RealTimeProtection *bool `json:"real_time_protection,omitempty"` // nil if not configured by policy.
+}
+
+// Firewall reports the state of the host firewall.
+type Firewall interface {
+	FirewallInfo() (*FirewallInfo, error)
+}
+
+// FirewallInfo describes the host firewall.
+type FirewallInfo struct {
+	Enabled  bool              `json:"enabled"`            // True if any profile filters traffic.
+	Product  string            `json:"product,omitempty"`  // Firewall implementation (e.g. nftables, Windows Firewall).
+	Profiles []FirewallProfile `json:"profiles,omitempty"` // Profiles (Windows), tables (Linux), or components (macOS).
+}
+
+// FirewallProfile describes a firewall profile and its default policies.
+type FirewallProfile struct {
+	Name            string `json:"name"`
+	Enabled         bool   `json:"enabled"`
+	DefaultInbound  string `json:"default_inbound,omitempty"`  // Policy for unmatched inbound traffic (allow or block).
+	DefaultOutbound string `json:"default_outbound,omitempty"` // Policy for unmatched outbound traffic (allow or block).
+	Rules           int    `json:"rules,omitempty"`            // Number of rules (Linux only).
+}
+
+// Firewall default policies reported in FirewallProfile.
+const (
+	FirewallPolicyAllow = "allow"
+	FirewallPolicyBlock = "block"
+)
+
+// TPM reports the Trusted Platform Module of the host.
+type TPM interface {
+	TPMInfo() (*TPMInfo, error)
+}
+
+// TPMInfo describes the Trusted Platform Module of the host. Enabled and
+// Owned are nil when they cannot be determined (e.g. without access to the
+// TPM device).
+type TPMInfo struct {
+	Present         bool   `json:"present"`
+	Version         string `json:"version,omitempty"`          // Specification version (1.2 or 2.0).
+	Manufacturer    string `json:"manufacturer,omitempty"`     // TCG vendor ID (e.g. IFX, INTC, NTC).
+	FirmwareVersion string `json:"firmware_version,omitempty"` // Firmware version reported by the TPM.
+	Enabled         *bool  `json:"enabled,omitempty"`          // TPM 1.2 enabled flag or TPM 2.0 storage hierarchy enabled.
+	Owned           *bool  `json:"owned,omitempty"`            // An owner (storage hierarchy) authorization is set.
+	SecureEnclave   *bool  `json:"secure_enclave,omitempty"`   // macOS only, the Mac has a Secure Enclave.
+}
+
+// TPM specification versions reported in TPMInfo.
+const (
+	TPMVersion12 = "1.2"
+	TPMVersion20 = "2.0"
+)
@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// Sensors is implemented by hosts that can read hardware monitoring sensors
+// (e.g. CPU temperatures and fan speeds).
+type Sensors interface {
+	Sensors() ([]SensorInfo, error)
+}
+
+// SensorType is the kind of quantity measured by a sensor.
+type SensorType string
+
+// Sensor types and the unit of their values.
+const (
+	SensorTemperature SensorType = "temperature" // Degrees Celsius.
+	SensorFan         SensorType = "fan"         // Revolutions per minute.
+	SensorPower       SensorType = "power"       // Watts.
+	SensorVoltage     SensorType = "voltage"     // Volts.
+	SensorCurrent     SensorType = "current"     // Amperes.
+)
+
+// SensorInfo is a reading of a single sensor. High and Critical are zero
+// when the hardware does not report thresholds.
+type SensorInfo struct {
+	Type     SensorType `json:"type"`
+	Device   string     `json:"device"`          // Chip or zone that owns the sensor (e.g. coretemp, acpitz).
+	Label    string     `json:"label,omitempty"` // Sensor label (e.g. Core 0).
+	Value    float64    `json:"value"`
+	High     float64    `json:"high,omitempty"`     // Upper threshold.
+	Critical float64    `json:"critical,omitempty"` // Critical threshold.
+}
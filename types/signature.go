@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// CodeSignature returns the code signing information of the executable of a
+// process.
+type CodeSignature interface {
+	SignatureInfo() (*SignatureInfo, error)
+}
+
+// SignatureInfo describes the signature of an executable file.
+type SignatureInfo struct {
+	// Status is the result of the verification (one of the SignatureStatus
+	// constants).
+	Status string `json:"status"`
+
+	// Signer is the subject common name of the signing certificate
+	// (Authenticode on Windows, the leaf authority on macOS).
+	Signer     string     `json:"signer,omitempty"`
+	Issuer     string     `json:"issuer,omitempty"`     // Issuer common name of the signing certificate.
+	Thumbprint string     `json:"thumbprint,omitempty"` // Hex encoded SHA-1 hash of the signing certificate.
+	NotBefore  *time.Time `json:"not_before,omitempty"` // Start of the validity period of the signing certificate.
+	NotAfter   *time.Time `json:"not_after,omitempty"`  // End of the validity period of the signing certificate.
+
+	// macOS only.
+	TeamID       string   `json:"team_id,omitempty"`      // Team identifier of the developer.
+	SigningID    string   `json:"signing_id,omitempty"`   // Code signing identifier (usually the bundle ID).
+	Notarized    bool     `json:"notarized,omitempty"`    // True if the code satisfies the notarization requirement.
+	Entitlements []string `json:"entitlements,omitempty"` // Names of the entitlements claimed by the code.
+
+	// IMA is the type of the security.ima extended attribute of the file
+	// (digest, hmac, or signature). Linux only.
+	IMA string `json:"ima,omitempty"`
+}
+
+// Signature verification results reported in SignatureInfo.
+const (
+	SignatureStatusTrusted    = "trusted"    // The signature is valid and chains to a trusted root.
+	SignatureStatusUntrusted  = "untrusted"  // The file is signed but verification failed.
+	SignatureStatusUnsigned   = "unsigned"   // The file is not signed.
+	SignatureStatusUnverified = "unverified" // The signature was not verified (e.g. Linux IMA).
+)
@@ -0,0 +1,38 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+import "time"
+
+// UserAccount describes a local user account.
+type UserAccount struct {
+	Name      string     `json:"name"`                 // Login name.
+	UID       string     `json:"uid"`                  // User ID (the SID on Windows).
+	GID       string     `json:"gid,omitempty"`        // Primary group ID (empty on Windows).
+	FullName  string     `json:"full_name,omitempty"`  // Full name (the GECOS field on Unix).
+	Home      string     `json:"home,omitempty"`       // Home directory.
+	Shell     string     `json:"shell,omitempty"`      // Login shell (empty on Windows).
+	LastLogin *time.Time `json:"last_login,omitempty"` // Time of the last login, if known.
+}
+
+// GroupAccount describes a local group.
+type GroupAccount struct {
+	Name    string   `json:"name"`              // Group name.
+	GID     string   `json:"gid"`               // Group ID (the SID on Windows).
+	Members []string `json:"members,omitempty"` // Names of the members that are listed explicitly.
+}
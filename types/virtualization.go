@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package types
+
+// Virtualization is implemented by hosts that can detect whether they are
+// running under a hypervisor.
+type Virtualization interface {
+	VirtualizationInfo() (*VirtualizationInfo, error)
+}
+
+// Virtualization roles.
+const (
+	VirtualizationRoleGuest = "guest" // The OS runs inside a virtual machine.
+	VirtualizationRoleHost  = "host"  // The OS runs or can run virtual machines.
+)
+
+// VirtualizationInfo describes the hypervisor of the host.
+type VirtualizationInfo struct {
+	// Hypervisor is the name of the hypervisor vendor (e.g. KVM, VMware,
+	// Hyper-V, Xen, QEMU, Parallels, VirtualBox). It is empty on bare metal.
+	Hypervisor string `json:"hypervisor,omitempty"`
+
+	// Role is guest when running inside a virtual machine and host when the
+	// OS is a hypervisor host (e.g. Xen dom0, the Hyper-V root partition, or
+	// a Linux host with /dev/kvm). It is empty on bare metal.
+	Role string `json:"role,omitempty"`
+
+	// Cloud is a hint of the cloud provider derived from the firmware
+	// strings (e.g. aws, gcp, azure). It is empty when unknown.
+	Cloud string `json:"cloud,omitempty"`
+}